@@ -0,0 +1,184 @@
+package emailkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/optimode/emailkit/internal/greylist"
+	"github.com/optimode/emailkit/internal/parse"
+)
+
+// DomainState is what a Validator remembers about a domain, as returned by
+// DomainState. It is meant for support tooling: when a customer fixes
+// their MX, it shows what a re-verification would still reuse.
+type DomainState struct {
+	Domain string         `json:"domain"`
+	MX     *DNSStateEntry `json:"mx,omitempty"`  // cached MX answer; nil if none
+	TXT    []string       `json:"txt,omitempty"` // cached TXT names at or under Domain
+	// Greylisted holds the first greylisting time by "mxhost|address" of
+	// the domain's recipients, the local part hashed in privacy mode; only
+	// a NewMemoryGreylistStore is listed.
+	Greylisted map[string]time.Time `json:"greylisted,omitempty"`
+	// IdleConns counts pooled SMTP connections by MX host.
+	IdleConns map[string]int `json:"idleConns,omitempty"`
+	// Tracked reports whether WithDisposableCandidates has observations
+	// for the domain.
+	Tracked bool `json:"tracked,omitempty"`
+}
+
+// Invalidated counts what Invalidate dropped.
+type Invalidated struct {
+	Domain   string `json:"domain"`
+	DNS      int    `json:"dns"`      // MX and TXT cache entries
+	Greylist int    `json:"greylist"` // greylisting timestamps
+	Conns    int    `json:"conns"`    // idle pooled SMTP connections
+	Tracked  bool   `json:"tracked"`  // disposable candidate observations
+	// Forgotten counts the Forgetter levels and subscribers that held
+	// state for the domain.
+	Forgotten int `json:"forgotten"`
+}
+
+// Forgetter is implemented by levels and subscribers that keep their own
+// per-domain state, such as x/reputation's Learner, so Invalidate drops it
+// too.
+type Forgetter interface {
+	// Forget drops what is kept about domain (ASCII/Punycode form) and
+	// reports whether there was anything.
+	Forget(domain string) bool
+}
+
+// DomainState returns what the validator has cached about domain. It
+// never queries DNS or connects.
+func (v *Validator) DomainState(domain string) DomainState {
+	domain = adminDomain(domain)
+	st := DomainState{Domain: domain}
+
+	if v.dnsCache != nil {
+		if e, ok := v.dnsCache.Lookup(domain); ok {
+			de := toStateEntry(e)
+			st.MX = &de
+		}
+		st.TXT = v.dnsCache.TXTNames(domain)
+	}
+	if ms, ok := v.greylist.(*greylist.MemoryStore); ok {
+		for k, t := range ms.Entries() {
+			if greylistedFor(k, domain) {
+				if st.Greylisted == nil {
+					st.Greylisted = make(map[string]time.Time)
+				}
+				st.Greylisted[k] = t
+			}
+		}
+	}
+	if v.smtpPool != nil {
+		for _, host := range mxHostsOf(domain, st.MX) {
+			if n := v.smtpPool.IdleConns(host); n > 0 {
+				if st.IdleConns == nil {
+					st.IdleConns = make(map[string]int)
+				}
+				st.IdleConns[host] = n
+			}
+		}
+	}
+	if v.candidates != nil {
+		v.candidates.mu.Lock()
+		_, st.Tracked = v.candidates.domains[domain]
+		v.candidates.mu.Unlock()
+	}
+	return st
+}
+
+// Invalidate forgets what the validator has cached about domain, so the
+// next validation resolves and probes afresh: its MX and TXT cache
+// entries (and the MXStore entry, if the store is an MXStoreDeleter), the
+// greylisting timestamps of its recipients in a NewMemoryGreylistStore,
+// idle pooled connections to its MX hosts, its disposable candidate
+// observations and what registered levels and subscribers implementing
+// Forgetter keep about it. With a Manager the pool is shared, so the
+// connections are closed for every tenant.
+//
+// Lookups in flight are not interrupted. The error reports a failing
+// MXStore; the local state is dropped regardless.
+func (v *Validator) Invalidate(ctx context.Context, domain string) (Invalidated, error) {
+	domain = adminDomain(domain)
+	inv := Invalidated{Domain: domain}
+
+	var cached *DNSStateEntry
+	if v.dnsCache != nil {
+		if e, ok := v.dnsCache.Lookup(domain); ok {
+			de := toStateEntry(e)
+			cached = &de
+		}
+	}
+	if v.smtpPool != nil {
+		for _, host := range mxHostsOf(domain, cached) {
+			inv.Conns += v.smtpPool.CloseHost(host)
+		}
+	}
+	if v.dnsCache != nil {
+		inv.DNS = v.dnsCache.Invalidate(domain)
+	}
+	var err error
+	if d, ok := v.mxStore.(MXStoreDeleter); ok {
+		if derr := d.DeleteMX(ctx, domain); derr != nil {
+			err = fmt.Errorf("emailkit: invalidate %s: MX store: %w", domain, derr)
+		}
+	}
+	if ms, ok := v.greylist.(*greylist.MemoryStore); ok {
+		for k := range ms.Entries() {
+			if greylistedFor(k, domain) {
+				_ = ms.Clear(ctx, k)
+				inv.Greylist++
+			}
+		}
+	}
+	if v.candidates != nil {
+		v.candidates.mu.Lock()
+		_, inv.Tracked = v.candidates.domains[domain]
+		delete(v.candidates.domains, domain)
+		v.candidates.mu.Unlock()
+	}
+	for _, c := range v.checkers {
+		if f, ok := c.(Forgetter); ok && f.Forget(domain) {
+			inv.Forgotten++
+		}
+	}
+	for _, s := range v.subscribers {
+		if f, ok := s.(Forgetter); ok && f.Forget(domain) {
+			inv.Forgotten++
+		}
+	}
+
+	return inv, err
+}
+
+// adminDomain normalizes a domain the way validation does, so cache keys
+// match; input it can't parse is only lowercased.
+func adminDomain(domain string) string {
+	if d := parse.NewDomain(domain); d.Valid {
+		return d.Domain
+	}
+	return strings.ToLower(strings.TrimSpace(domain))
+}
+
+// mxHostsOf returns the hosts the SMTP pool knows domain's MX by: the
+// cached MX hosts, or the domain itself (implicit MX) without an answer.
+func mxHostsOf(domain string, cached *DNSStateEntry) []string {
+	if cached == nil || len(cached.MX) == 0 {
+		return []string{domain}
+	}
+	hosts := make([]string, 0, len(cached.MX))
+	for _, mx := range cached.MX {
+		hosts = append(hosts, strings.TrimSuffix(mx.Host, "."))
+	}
+	return hosts
+}
+
+// greylistedFor reports whether a greylist key ("mxhost|address") is for
+// a recipient at domain.
+func greylistedFor(key, domain string) bool {
+	_, rcpt, ok := strings.Cut(key, "|")
+	return ok && strings.HasSuffix(strings.ToLower(rcpt), "@"+domain)
+}
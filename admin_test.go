@@ -0,0 +1,82 @@
+package emailkit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+// deletingMXStore is a memoryMXStore that is also an MXStoreDeleter.
+type deletingMXStore struct {
+	memoryMXStore
+	err error
+}
+
+func (s *deletingMXStore) DeleteMX(_ context.Context, domain string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, domain)
+	return s.err
+}
+
+func TestValidator_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	n := fakeNetwork()
+	store := &deletingMXStore{memoryMXStore: memoryMXStore{entries: map[string]emailkit.DNSStateEntry{}}}
+	v := emailkit.NewOffline(n).WithDNS().WithMXStore(store).WithDisposableCandidates(emailkit.DisposableCandidateOptions{}).
+		WithSMTP(emailkit.SMTPOptions{
+			HeloDomain: "verifier.test",
+			MailFrom:   "verify@verifier.test",
+			Greylist:   emailkit.NewMemoryGreylistStore(),
+		})
+	defer func() { _ = v.Close() }()
+
+	_, err := v.Validate(ctx, "dave@grey.example")
+	require.NoError(t, err)
+	_, err = v.Validate(ctx, "alice@example.com")
+	require.NoError(t, err)
+
+	st := v.DomainState("Grey.Example.")
+	assert.Equal(t, "grey.example", st.Domain)
+	require.NotNil(t, st.MX)
+	assert.Equal(t, "mx.grey.example.", st.MX.MX[0].Host)
+	assert.Equal(t, map[string]int{"mx.grey.example": 1}, st.IdleConns)
+	assert.Contains(t, st.Greylisted, "mx.grey.example|dave@grey.example")
+	assert.True(t, st.Tracked)
+
+	inv, err := v.Invalidate(ctx, "grey.example")
+	require.NoError(t, err)
+	assert.Equal(t, emailkit.Invalidated{Domain: "grey.example", DNS: 1, Greylist: 1, Conns: 1, Tracked: true}, inv)
+	assert.Equal(t, emailkit.DomainState{Domain: "grey.example"}, v.DomainState("grey.example"))
+	_, ok, _ := store.LoadMX(ctx, "grey.example")
+	assert.False(t, ok)
+
+	// Other domains are untouched
+	assert.NotNil(t, v.DomainState("example.com").MX)
+	assert.Len(t, v.DomainState("example.com").IdleConns, 1)
+}
+
+func TestValidator_InvalidateMXStoreError(t *testing.T) {
+	store := &deletingMXStore{memoryMXStore: memoryMXStore{entries: map[string]emailkit.DNSStateEntry{}}, err: errors.New("backend down")}
+	v := emailkit.NewOffline(fakeNetwork()).WithDNS().WithMXStore(store)
+	_, err := v.Validate(context.Background(), "alice@example.com")
+	require.NoError(t, err)
+
+	inv, err := v.Invalidate(context.Background(), "example.com")
+	require.ErrorContains(t, err, "backend down")
+	assert.Equal(t, 1, inv.DNS, "local state is dropped regardless")
+	assert.Nil(t, v.DomainState("example.com").MX)
+}
+
+func TestValidator_DomainStateWithoutLevels(t *testing.T) {
+	v := emailkit.New()
+	assert.Equal(t, emailkit.DomainState{Domain: "example.com"}, v.DomainState("example.com"))
+	inv, err := v.Invalidate(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, emailkit.Invalidated{Domain: "example.com"}, inv)
+}
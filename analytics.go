@@ -0,0 +1,75 @@
+package emailkit
+
+import (
+	"time"
+
+	"github.com/optimode/emailkit/internal/analytics"
+)
+
+// ProbeAnalytics aggregates the outcomes of the SMTP level's MX host
+// attempts over a rolling window (SMTPOptions.AnalyticsWindow), per
+// mailbox provider and per MX host: one source for dashboards, and for
+// tuning the ProbeLimiter, the schedule budgets and the warm-up ramp.
+type ProbeAnalytics struct {
+	Window time.Duration `json:"window"`
+	// Providers are keyed by provider ("google", "microsoft", ...; see
+	// InspectDomain) or, for unrecognised providers, by the registrable
+	// domain of the primary MX host, like ProbeSchedule budgets.
+	Providers []ProbeStats `json:"providers"`
+	// MXHosts are keyed by MX host name.
+	MXHosts []ProbeStats `json:"mxHosts"`
+}
+
+// ProbeStats are the aggregated outcomes of the MX host attempts of one
+// provider or host. Each host tried by a probe counts once; a probe
+// answered by its second MX host counts for both.
+type ProbeStats struct {
+	Key       string `json:"key"`
+	Attempts  int64  `json:"attempts"`
+	Accepted  int64  `json:"accepted"`  // 2xx
+	Temporary int64  `json:"temporary"` // 4xx, e.g. greylisting and throttling
+	Permanent int64  `json:"permanent"` // 5xx
+	Errors    int64  `json:"errors"`    // no reply: connection or protocol failures
+	// The rates are the shares of Attempts, 0..1.
+	AcceptRate    float64       `json:"acceptRate"`
+	TemporaryRate float64       `json:"temporaryRate"`
+	PermanentRate float64       `json:"permanentRate"`
+	ErrorRate     float64       `json:"errorRate"`
+	AvgLatency    time.Duration `json:"avgLatency"`
+}
+
+// Analytics returns the SMTP level's probe outcomes over the last
+// SMTPOptions.AnalyticsWindow; the zero ProbeAnalytics without an SMTP
+// level. Outcomes are kept in memory, per Validator.
+func (v *Validator) Analytics() ProbeAnalytics {
+	if v.analytics == nil {
+		return ProbeAnalytics{}
+	}
+	providers, hosts := v.analytics.Snapshot()
+	return ProbeAnalytics{
+		Window:    v.analytics.Window(),
+		Providers: probeStats(providers),
+		MXHosts:   probeStats(hosts),
+	}
+}
+
+func probeStats(stats []analytics.Stats) []ProbeStats {
+	out := make([]ProbeStats, len(stats))
+	for i, s := range stats {
+		n := float64(s.Probes)
+		out[i] = ProbeStats{
+			Key:           s.Key,
+			Attempts:      s.Probes,
+			Accepted:      s.Accepted,
+			Temporary:     s.Temporary,
+			Permanent:     s.Permanent,
+			Errors:        s.Errors,
+			AcceptRate:    float64(s.Accepted) / n,
+			TemporaryRate: float64(s.Temporary) / n,
+			PermanentRate: float64(s.Permanent) / n,
+			ErrorRate:     float64(s.Errors) / n,
+			AvgLatency:    s.Latency / time.Duration(s.Probes),
+		}
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+package emailkit
+
+import (
+	"maps"
+	"math"
+	"time"
+)
+
+// Signal names a piece of evidence that affects deliverability.
+type Signal = string
+
+// Signals used by Calibration.
+const (
+	// SignalValid is present on every valid result.
+	SignalValid Signal = "valid"
+	// SignalNoSMTP: the mailbox was not probed (no SMTP level configured).
+	SignalNoSMTP Signal = "no_smtp"
+	// SignalSMTPAccepted: RCPT TO was accepted with 250/251.
+	SignalSMTPAccepted Signal = "smtp_accepted"
+	// SignalSMTPConnect: an MX host accepted an SMTP session, but the
+	// mailbox was not probed (WithSMTPConnect).
+	SignalSMTPConnect Signal = "smtp_connect"
+	// SignalSMTP252: the server answered 252 (cannot verify, will attempt delivery).
+	SignalSMTP252 Signal = "smtp_252"
+	// SignalCatchAll: the domain accepts every recipient.
+	SignalCatchAll Signal = "catch_all"
+	// SignalGreylisted: the mailbox could not be probed because of greylisting.
+	SignalGreylisted Signal = "greylisted"
+	// SignalSMTPUnknown: the SMTP probe was inconclusive (temporary failure).
+	SignalSMTPUnknown Signal = "smtp_unknown"
+	// SignalYoungDomain: the domain was registered less than YoungDomainAge ago.
+	SignalYoungDomain Signal = "young_domain"
+	// SignalDNSUnknown: the MX lookup failed because of the resolver
+	// (timeout, SERVFAIL), not because the domain doesn't exist.
+	SignalDNSUnknown Signal = "dns_unknown"
+	// SignalParked: the domain looks parked (see NSOptions.DetectParking).
+	SignalParked Signal = "parked"
+	// SignalMailboxFull: the mailbox exists but is over its quota.
+	SignalMailboxFull Signal = "mailbox_full"
+)
+
+// YoungDomainAge is the registration age below which SignalYoungDomain applies.
+const YoungDomainAge = 30 * 24 * time.Hour
+
+// Calibration maps signals to empirical deliverability probabilities
+// (0..1). The probability of a result is that of its weakest signal.
+type Calibration map[Signal]float64
+
+var defaultCalibration = Calibration{
+	SignalValid:        0.99,
+	SignalNoSMTP:       0.85,
+	SignalSMTPAccepted: 0.97,
+	SignalSMTPConnect:  0.88,
+	SignalSMTP252:      0.75,
+	SignalCatchAll:     0.60,
+	SignalGreylisted:   0.70,
+	SignalSMTPUnknown:  0.65,
+	SignalYoungDomain:  0.50,
+	SignalParked:       0.10,
+	SignalDNSUnknown:   0.60,
+	SignalMailboxFull:  0.30,
+}
+
+// DefaultCalibration returns a copy of the built-in table, derived from
+// typical bounce rates. Adjust it with your own bounce data and pass it to
+// WithCalibration.
+func DefaultCalibration() Calibration {
+	return maps.Clone(defaultCalibration)
+}
+
+// WithCalibration enables Result.DeliverabilityProbability. Signals missing
+// from table fall back to DefaultCalibration; with no table the defaults
+// are used as is.
+func (v *Validator) WithCalibration(table ...Calibration) *Validator {
+	c := DefaultCalibration()
+	if len(table) > 0 {
+		maps.Copy(c, table[0])
+	}
+	v.calibration = c
+	return v
+}
+
+// inconclusiveCodes mark failed checks that don't prove the address invalid.
+var inconclusiveCodes = map[CheckCode]Signal{
+	CodeGreylisted:         SignalGreylisted,
+	CodeTemporary:          SignalSMTPUnknown,
+	CodeRateLimited:        SignalSMTPUnknown,
+	CodeServiceUnavailable: SignalSMTPUnknown,
+	CodeDeferred:           SignalNoSMTP,
+	CodeDNSTimeout:         SignalDNSUnknown,
+	CodeDNSError:           SignalDNSUnknown,
+	CodeMailboxFull:        SignalMailboxFull,
+}
+
+// signals collects the deliverability signals of r. ok is false if r
+// failed a check definitively (probability 0).
+func signals(r Result) (out []Signal, ok bool) {
+	out = []Signal{SignalValid}
+	smtpSeen := false
+	for _, c := range r.Checks {
+		if !c.Passed {
+			s, inconclusive := inconclusiveCodes[c.Code]
+			if !inconclusive {
+				return nil, false
+			}
+			out = append(out, s)
+			smtpSeen = smtpSeen || c.Level == LevelSMTP
+			continue
+		}
+		if c.Code == CodeSkipped {
+			continue // the SMTP level did not probe the mailbox
+		}
+		switch {
+		case c.Code == CodeCatchAll:
+			out = append(out, SignalCatchAll)
+		case c.Code == CodeParked:
+			out = append(out, SignalParked)
+		case c.Level == LevelSMTP && c.Meta["probe"] == "connect":
+			out = append(out, SignalSMTPConnect)
+		case c.Level == LevelSMTP && c.SMTPCode == 252:
+			out = append(out, SignalSMTP252)
+		case c.Level == LevelSMTP:
+			out = append(out, SignalSMTPAccepted)
+		}
+		if c.Level == LevelSMTP {
+			smtpSeen = true
+		}
+		if reg, err := time.Parse(time.RFC3339, c.Meta["registered"]); err == nil && time.Since(reg) < YoungDomainAge {
+			out = append(out, SignalYoungDomain)
+		}
+	}
+	if !smtpSeen {
+		out = append(out, SignalNoSMTP)
+	}
+	return out, true
+}
+
+// deliverability returns the probability of the weakest signal of r.
+func (c Calibration) deliverability(r Result) float64 {
+	if r.Truncated {
+		return 0
+	}
+	sigs, ok := signals(r)
+	if !ok {
+		return 0
+	}
+	p := math.Inf(1)
+	for _, s := range sigs {
+		if v, known := c[s]; known {
+			p = math.Min(p, v)
+		}
+	}
+	if math.IsInf(p, 1) {
+		return 0
+	}
+	return p
+}
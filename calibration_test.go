@@ -0,0 +1,88 @@
+package emailkit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+// fixedChecker returns the same CheckResult for every address.
+type fixedChecker struct{ res emailkit.CheckResult }
+
+func (c fixedChecker) Check(_ context.Context, _ emailkit.Email) emailkit.CheckResult {
+	return c.res
+}
+
+func TestWithCalibration(t *testing.T) {
+	young := time.Now().Add(-5 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	old := time.Now().Add(-5 * 365 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name   string
+		checks []emailkit.CheckResult
+		want   float64
+	}{
+		{"no SMTP", nil, 0.85},
+		{"SMTP accepted", []emailkit.CheckResult{{Level: emailkit.LevelSMTP, Passed: true, SMTPCode: 250}}, 0.97},
+		{"SMTP connect only", []emailkit.CheckResult{{Level: emailkit.LevelSMTP, Passed: true, SMTPCode: 250, Meta: map[string]string{"probe": "connect"}}}, 0.88},
+		{"SMTP 252", []emailkit.CheckResult{{Level: emailkit.LevelSMTP, Passed: true, SMTPCode: 252}}, 0.75},
+		{"catch-all", []emailkit.CheckResult{{Level: emailkit.LevelSMTP, Passed: true, SMTPCode: 250, Code: emailkit.CodeCatchAll}}, 0.60},
+		{"greylisted", []emailkit.CheckResult{{Level: emailkit.LevelSMTP, Passed: false, Code: emailkit.CodeGreylisted}}, 0.70},
+		{"rejected", []emailkit.CheckResult{{Level: emailkit.LevelSMTP, Passed: false, Code: emailkit.CodeMailboxUnknown}}, 0},
+		{"mailbox full", []emailkit.CheckResult{{Level: emailkit.LevelSMTP, Passed: false, SMTPCode: 552, Code: emailkit.CodeMailboxFull}}, 0.30},
+		{"DNS timeout", []emailkit.CheckResult{{Level: emailkit.LevelDNS, Passed: false, Code: emailkit.CodeDNSTimeout}}, 0.60},
+		{"NXDOMAIN", []emailkit.CheckResult{{Level: emailkit.LevelDNS, Passed: false, Code: emailkit.CodeBadDomain}}, 0},
+		{"parked domain", []emailkit.CheckResult{{Level: emailkit.LevelNS, Passed: true, Code: emailkit.CodeParked}}, 0.10},
+		{"skipped by policy", []emailkit.CheckResult{{Level: emailkit.LevelSMTP, Passed: true, Code: emailkit.CodeSkipped}}, 0.85},
+		{"young domain", []emailkit.CheckResult{
+			{Level: emailkit.LevelRegistration, Passed: true, Meta: map[string]string{"registered": young}},
+			{Level: emailkit.LevelSMTP, Passed: true, SMTPCode: 250},
+		}, 0.50},
+		{"old domain", []emailkit.CheckResult{
+			{Level: emailkit.LevelRegistration, Passed: true, Meta: map[string]string{"registered": old}},
+			{Level: emailkit.LevelSMTP, Passed: true, SMTPCode: 250},
+		}, 0.97},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := emailkit.New().WithCalibration()
+			for _, c := range tt.checks {
+				v.With(fixedChecker{res: c})
+			}
+			res, err := v.ValidateAll(context.Background(), "user@example.com")
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, res.DeliverabilityProbability, 1e-9)
+		})
+	}
+}
+
+func TestWithCalibration_Override(t *testing.T) {
+	v := emailkit.New().
+		With(fixedChecker{res: emailkit.CheckResult{Level: emailkit.LevelSMTP, Passed: true, SMTPCode: 252}}).
+		WithCalibration(emailkit.Calibration{emailkit.SignalSMTP252: 0.4})
+
+	res, err := v.Validate(context.Background(), "user@example.com")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.4, res.DeliverabilityProbability, 1e-9)
+
+	// Overrides don't leak into the defaults
+	assert.InDelta(t, 0.75, emailkit.DefaultCalibration()[emailkit.SignalSMTP252], 1e-9)
+}
+
+func TestWithCalibration_Disabled(t *testing.T) {
+	res, err := emailkit.New().Validate(context.Background(), "user@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, res.DeliverabilityProbability)
+}
+
+func TestWithCalibration_Invalid(t *testing.T) {
+	res, err := emailkit.New().WithCalibration().Validate(context.Background(), "invalid")
+	require.NoError(t, err)
+	assert.Zero(t, res.DeliverabilityProbability)
+}
@@ -0,0 +1,92 @@
+package emailkit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Canaries are addresses with a known outcome, validated along with a
+// ValidateMany batch to verify the pipeline itself: if the probe IP is
+// blocked or DNS is broken, a known-good address comes back invalid and
+// the whole batch is suspect. Canary results are not part of the returned
+// slice.
+type Canaries struct {
+	// Good must validate as valid, e.g. mailboxes you own at major providers.
+	Good []string
+	// Bad must validate as invalid, e.g. a random local part at a domain
+	// known to reject unknown recipients.
+	Bad []string
+}
+
+func (c Canaries) len() int { return len(c.Good) + len(c.Bad) }
+
+// CanaryFailure is a canary the pipeline misclassified.
+type CanaryFailure struct {
+	Email     string
+	WantValid bool
+	Result    Result
+	// Hint names the likely systemic cause, e.g. "SMTP connectivity".
+	Hint string
+}
+
+// CanaryError is returned by ValidateMany when canaries were misclassified.
+// It wraps ErrCanaryFailed.
+type CanaryError struct {
+	Failures []CanaryFailure
+}
+
+func (e *CanaryError) Error() string {
+	parts := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		want := "invalid"
+		if f.WantValid {
+			want = "valid"
+		}
+		parts[i] = fmt.Sprintf("%s (want %s: %s)", f.Email, want, f.Hint)
+	}
+	return fmt.Sprintf("%v: %s", ErrCanaryFailed, strings.Join(parts, "; "))
+}
+
+func (e *CanaryError) Unwrap() error { return ErrCanaryFailed }
+
+// verify compares the canary results (Good first, then Bad) with the
+// expected outcomes. Truncated runs are not judged.
+func (c Canaries) verify(results []Result) error {
+	var failures []CanaryFailure
+	for i, email := range append(append([]string(nil), c.Good...), c.Bad...) {
+		r := results[i]
+		wantValid := i < len(c.Good)
+		if r.Truncated || r.Valid == wantValid {
+			continue
+		}
+		failures = append(failures, CanaryFailure{Email: email, WantValid: wantValid, Result: r, Hint: canaryHint(r, wantValid)})
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return &CanaryError{Failures: failures}
+}
+
+// canaryHint guesses the systemic cause of a misclassified canary.
+func canaryHint(r Result, wantValid bool) string {
+	if !wantValid {
+		if c, ok := r.CheckFor(LevelSMTP); ok && c.Code == CodeSkipped {
+			return "known-bad address accepted: " + c.Details
+		}
+		return "known-bad address accepted: catch-all, skipped or policy-relaxed checks"
+	}
+	failed := r.FailedChecks()
+	if len(failed) == 0 {
+		return "unknown"
+	}
+	c := failed[0]
+	switch {
+	case c.Level == LevelDNS:
+		return "DNS resolution: " + c.Details
+	case c.Level == LevelSMTP && c.Code == CodeBlocked:
+		return "probe IP blocked"
+	case c.Level == LevelSMTP && c.SMTPCode == 0:
+		return "SMTP connectivity (outbound port 25 or probe IP blocked): " + c.Details
+	}
+	return fmt.Sprintf("%s level: %s", c.Level, c.Details)
+}
@@ -0,0 +1,68 @@
+package emailkit_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestValidateMany_Canaries(t *testing.T) {
+	v := emailkit.New().WithResolver(&stubResolver{
+		mx: map[string][]*net.MX{
+			"example.com": {{Host: "mx.example.com.", Pref: 10}},
+			"canary.test": {{Host: "mx.canary.test.", Pref: 10}},
+		},
+	}).WithDNS()
+
+	results, err := v.ValidateMany(context.Background(),
+		[]string{"a@example.com", "b@nomx.example"},
+		emailkit.ConcurrencyOptions{Canaries: emailkit.Canaries{
+			Good: []string{"ok@canary.test"},
+			Bad:  []string{"gone@nomx.canary.test"},
+		}})
+	require.NoError(t, err)
+	require.Len(t, results, 2, "canary results are not returned")
+	assert.Equal(t, "a@example.com", results[0].Email)
+	assert.True(t, results[0].Valid)
+	assert.False(t, results[1].Valid)
+}
+
+func TestValidateMany_CanaryFailure(t *testing.T) {
+	// DNS is broken: every lookup fails
+	v := emailkit.New().WithResolver(&stubResolver{}).WithDNS()
+
+	results, err := v.ValidateMany(context.Background(),
+		[]string{"a@example.com"},
+		emailkit.ConcurrencyOptions{Prefilter: true, Canaries: emailkit.Canaries{
+			Good: []string{"ok@canary.test"},
+		}})
+	require.Len(t, results, 1)
+	assert.ErrorIs(t, err, emailkit.ErrCanaryFailed)
+
+	var cerr *emailkit.CanaryError
+	require.True(t, errors.As(err, &cerr))
+	require.Len(t, cerr.Failures, 1)
+	f := cerr.Failures[0]
+	assert.Equal(t, "ok@canary.test", f.Email)
+	assert.True(t, f.WantValid)
+	assert.Contains(t, f.Hint, "DNS resolution: MX lookup failed")
+}
+
+func TestValidateMany_BadCanaryAccepted(t *testing.T) {
+	v := emailkit.New() // syntax only: nothing can reject the bad canary
+
+	_, err := v.ValidateMany(context.Background(), []string{"a@example.com"},
+		emailkit.ConcurrencyOptions{Canaries: emailkit.Canaries{Bad: []string{"nobody@example.com"}}})
+
+	var cerr *emailkit.CanaryError
+	require.True(t, errors.As(err, &cerr))
+	assert.False(t, cerr.Failures[0].WantValid)
+	assert.EqualError(t, err, "emailkit: canary addresses misclassified: nobody@example.com "+
+		"(want invalid: known-bad address accepted: catch-all, skipped or policy-relaxed checks)")
+}
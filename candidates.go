@@ -0,0 +1,192 @@
+package emailkit
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Disposable candidate signals; see DisposableCandidate.
+const (
+	// CandidateYoungDomain: the registration level dated the domain less
+	// than YoungDomainAge ago.
+	CandidateYoungDomain = "young_domain"
+	// CandidateRandomMX: an MX host's first label looks machine-generated.
+	CandidateRandomMX = "random_mx"
+	// CandidateAcceptsAll: the mail server accepted every probed
+	// recipient, or was fingerprinted as catch-all.
+	CandidateAcceptsAll = "accepts_all"
+	// CandidateBounces: the mail server rejected at least half of the
+	// probed recipients as unknown, like a service expiring its inboxes.
+	CandidateBounces = "bounces"
+)
+
+// DisposableCandidateOptions configures disposable candidate reporting;
+// see WithDisposableCandidates.
+type DisposableCandidateOptions struct {
+	// MinValidations is the number of validations of a domain before it
+	// is judged; the rates of the SMTP signals count only SMTP probes.
+	// Default: 5
+	MinValidations int
+	// MinSignals is the number of distinct signals that make a domain a
+	// candidate. Default: 2
+	MinSignals int
+	// MaxDomains bounds the domains tracked at once; beyond it, domains
+	// are forgotten in no particular order. Default: 10000
+	MaxDomains int
+}
+
+// DisposableCandidate is emitted, once per domain, when validations of a
+// domain that is not on the disposable list showed disposable-like
+// behavior: at least MinSignals of CandidateYoungDomain,
+// CandidateRandomMX, CandidateAcceptsAll and CandidateBounces. Review
+// candidates and feed confirmed ones to your blocklist (see WithBlocklist)
+// or upstream to the disposable list.
+type DisposableCandidate struct {
+	Time        time.Time
+	Domain      string   // ASCII/Punycode form
+	Signals     []string // in the order listed above
+	Validations int      // validations of the domain so far
+	Probes      int      // of which the SMTP level probed a mailbox
+}
+
+func (DisposableCandidate) event() {}
+
+// WithDisposableCandidates tracks how the domains of validated addresses
+// behave and emits a DisposableCandidate event (see WithSubscriber) for a
+// domain that looks disposable although no list knows it yet. The
+// signals come from the registration and SMTP levels, so add those.
+// Domains that failed the domain level, or are allowlisted, are skipped.
+func (v *Validator) WithDisposableCandidates(opts ...DisposableCandidateOptions) *Validator {
+	var o DisposableCandidateOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	switch {
+	case o.MinValidations < 0:
+		v.setErr(negative("DisposableCandidateOptions.MinValidations", o.MinValidations))
+		return v
+	case o.MinSignals < 0:
+		v.setErr(negative("DisposableCandidateOptions.MinSignals", o.MinSignals))
+		return v
+	case o.MaxDomains < 0:
+		v.setErr(negative("DisposableCandidateOptions.MaxDomains", o.MaxDomains))
+		return v
+	}
+	if o.MinValidations == 0 {
+		o.MinValidations = 5
+	}
+	if o.MinSignals == 0 {
+		o.MinSignals = 2
+	}
+	if o.MaxDomains == 0 {
+		o.MaxDomains = 10000
+	}
+	v.candidates = &candidateTracker{opts: o, domains: make(map[string]*domainBehavior)}
+	return v
+}
+
+// candidateTracker accumulates the behavior of domains.
+type candidateTracker struct {
+	opts    DisposableCandidateOptions
+	mu      sync.Mutex
+	domains map[string]*domainBehavior
+}
+
+type domainBehavior struct {
+	validations int
+	probes      int
+	accepted    int // accepted or fingerprinted catch-all
+	unknown     int // rejected as an unknown mailbox
+	young       bool
+	randomMX    bool
+	reported    bool
+}
+
+// observe records a finished result for domain and returns the candidate
+// it makes, if it just became one.
+func (t *candidateTracker) observe(domain string, r Result) (DisposableCandidate, bool) {
+	if domain == "" || r.Truncated {
+		return DisposableCandidate{}, false
+	}
+	for _, c := range r.Checks {
+		if (c.Level == LevelDomain && !c.Passed) || c.Code == CodeAllowlisted {
+			return DisposableCandidate{}, false
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b := t.domains[domain]
+	if b == nil {
+		if len(t.domains) >= t.opts.MaxDomains {
+			for d := range t.domains {
+				delete(t.domains, d)
+				break
+			}
+		}
+		b = &domainBehavior{}
+		t.domains[domain] = b
+	}
+	if b.reported {
+		return DisposableCandidate{}, false
+	}
+	b.validations++
+	for _, c := range r.Checks {
+		switch c.Level {
+		case LevelRegistration:
+			if reg, err := time.Parse(time.RFC3339, c.Meta["registered"]); err == nil && time.Since(reg) < YoungDomainAge {
+				b.young = true
+			}
+		case LevelSMTP:
+			hosts := []string{c.MXHost}
+			for _, a := range c.Attempts {
+				hosts = append(hosts, a.Host)
+			}
+			for _, h := range hosts {
+				if label, _, _ := strings.Cut(h, "."); randomLooking(strings.ToLower(label)) {
+					b.randomMX = true
+				}
+			}
+			switch {
+			case c.Passed && c.Code == "" && c.Meta["probe"] != "connect", c.Code == CodeCatchAll:
+				b.probes++
+				b.accepted++
+			case c.Code == CodeMailboxUnknown:
+				b.probes++
+				b.unknown++
+			case c.SMTPCode > 0:
+				b.probes++
+			}
+		}
+	}
+
+	if b.validations < t.opts.MinValidations {
+		return DisposableCandidate{}, false
+	}
+	var signals []string
+	if b.young {
+		signals = append(signals, CandidateYoungDomain)
+	}
+	if b.randomMX {
+		signals = append(signals, CandidateRandomMX)
+	}
+	if b.probes > 0 && b.accepted == b.probes {
+		signals = append(signals, CandidateAcceptsAll)
+	}
+	if b.probes > 0 && 2*b.unknown >= b.probes {
+		signals = append(signals, CandidateBounces)
+	}
+	if len(signals) < t.opts.MinSignals {
+		return DisposableCandidate{}, false
+	}
+	b.reported = true
+	return DisposableCandidate{
+		Time:        time.Now(),
+		Domain:      domain,
+		Signals:     slices.Clip(signals),
+		Validations: b.validations,
+		Probes:      b.probes,
+	}, true
+}
@@ -0,0 +1,84 @@
+package emailkit_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestWithDisposableCandidates(t *testing.T) {
+	n := emailkit.NewFakeNetwork().
+		AddDomain("burner.example", emailkit.FakeDomain{
+			CatchAll:   true,
+			MX:         []string{"x7fk2q9z.mailhost.example"},
+			Registered: time.Now().Add(-48 * time.Hour),
+		}).
+		AddDomain("example.com", emailkit.FakeDomain{Mailboxes: []string{"alice"}})
+
+	var mu sync.Mutex
+	var got []emailkit.DisposableCandidate
+	v := emailkit.NewOffline(n).WithDomain().WithRegistration().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "verifier.test",
+		MailFrom:   "verify@verifier.test",
+	}).WithDisposableCandidates(emailkit.DisposableCandidateOptions{MinValidations: 3}).
+		WithSubscriber(emailkit.SubscriberFunc(func(e emailkit.Event) {
+			if c, ok := e.(emailkit.DisposableCandidate); ok {
+				mu.Lock()
+				got = append(got, c)
+				mu.Unlock()
+			}
+		}))
+	defer func() { _ = v.Close() }()
+	ctx := context.Background()
+
+	for i := range 5 {
+		for _, domain := range []string{"burner.example", "example.com", "mailinator.com"} {
+			_, err := v.Validate(ctx, fmt.Sprintf("user%d@%s", i, domain))
+			require.NoError(t, err)
+		}
+	}
+
+	require.Len(t, got, 1, "reported once, known disposable domains are skipped")
+	c := got[0]
+	assert.Equal(t, "burner.example", c.Domain)
+	assert.Equal(t, []string{emailkit.CandidateYoungDomain, emailkit.CandidateRandomMX, emailkit.CandidateAcceptsAll}, c.Signals)
+	assert.Equal(t, 3, c.Validations)
+	assert.Equal(t, 3, c.Probes)
+}
+
+func TestWithDisposableCandidates_Bounces(t *testing.T) {
+	n := emailkit.NewFakeNetwork().AddDomain("expired.example", emailkit.FakeDomain{
+		Registered: time.Now().Add(-72 * time.Hour),
+	})
+	var got []emailkit.DisposableCandidate
+	v := emailkit.NewOffline(n).WithRegistration().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "verifier.test",
+		MailFrom:   "verify@verifier.test",
+	}).WithDisposableCandidates().WithSubscriber(emailkit.SubscriberFunc(func(e emailkit.Event) {
+		if c, ok := e.(emailkit.DisposableCandidate); ok {
+			got = append(got, c)
+		}
+	}))
+	defer func() { _ = v.Close() }()
+
+	for i := range 5 {
+		_, err := v.Validate(context.Background(), fmt.Sprintf("user%d@expired.example", i))
+		require.NoError(t, err)
+	}
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{emailkit.CandidateYoungDomain, emailkit.CandidateBounces}, got[0].Signals)
+}
+
+func TestWithDisposableCandidates_Options(t *testing.T) {
+	_, err := emailkit.New().WithDisposableCandidates(emailkit.DisposableCandidateOptions{MinSignals: -1}).Validate(context.Background(), "a@example.com")
+	var oe *emailkit.OptionsError
+	require.ErrorAs(t, err, &oe)
+	assert.Equal(t, "DisposableCandidateOptions.MinSignals", oe.Field)
+}
@@ -0,0 +1,80 @@
+package check
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/optimode/emailkit/internal/provider"
+	"github.com/optimode/emailkit/internal/smtppool"
+)
+
+// blockedPortThreshold is how many distinct MX hosts of well-known
+// providers must fail to connect, with no successful dial in between,
+// before outbound SMTP is considered blocked. These providers accept
+// connections around the clock, so failures on several of them point at
+// the local network (e.g. a cloud provider blocking port 25).
+const blockedPortThreshold = 2
+
+// blockedPortRecheck is how often one probe is let through while blocked,
+// to notice when the block is lifted.
+const blockedPortRecheck = 10 * time.Minute
+
+// portDetector tracks dial outcomes to detect a blocked outbound SMTP
+// port. It is safe for concurrent use.
+type portDetector struct {
+	mu        sync.Mutex
+	failed    map[string]bool // well-known MX hosts that failed since the last successful dial
+	blockedAt time.Time
+	lastTry   time.Time
+	now       func() time.Time
+}
+
+func newPortDetector() *portDetector {
+	return &portDetector{failed: make(map[string]bool), now: time.Now}
+}
+
+// observe records a dial to host. DNS and proxy failures say nothing
+// about the port and are ignored.
+func (d *portDetector) observe(host string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err == nil {
+		clear(d.failed)
+		d.blockedAt = time.Time{}
+		return
+	}
+	var dnsErr *net.DNSError
+	var proxyErr *smtppool.ProxyError
+	if errors.As(err, &dnsErr) || errors.As(err, &proxyErr) || provider.Detect([]string{host}) == "" {
+		return
+	}
+	d.failed[host] = true
+	if len(d.failed) >= blockedPortThreshold && d.blockedAt.IsZero() {
+		d.blockedAt = d.now()
+		d.lastTry = d.blockedAt
+	}
+}
+
+// blocked reports whether outbound SMTP is currently considered blocked.
+// Every blockedPortRecheck it returns false once, letting a probe through.
+func (d *portDetector) blocked() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.blockedAt.IsZero() {
+		return false
+	}
+	if now := d.now(); now.Sub(d.lastTry) >= blockedPortRecheck {
+		d.lastTry = now
+		return false
+	}
+	return true
+}
+
+// isBlocked reports the state without consuming a recheck.
+func (d *portDetector) isBlocked() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return !d.blockedAt.IsZero()
+}
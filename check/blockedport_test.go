@@ -0,0 +1,115 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/internal/smtppool"
+	"github.com/optimode/emailkit/types"
+)
+
+// timeoutDial fails every connection like a firewalled port 25.
+func timeoutDial(dials *atomic.Int64) func(string, string, time.Duration) (net.Conn, error) {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		dials.Add(1)
+		return nil, &net.OpError{Op: "dial", Net: network, Err: os.ErrDeadlineExceeded}
+	}
+}
+
+func TestSMTPChecker_DetectBlockedPort(t *testing.T) {
+	var dials atomic.Int64
+	mxRecords := []*net.MX{
+		{Host: "gmail-smtp-in.l.google.com.", Pref: 5},
+		{Host: "alt1.gmail-smtp-in.l.google.com.", Pref: 10},
+	}
+	cfg := check.SMTPConfig{
+		HeloDomain:        "test.com",
+		MailFrom:          "verify@test.com",
+		MaxMXHosts:        2,
+		DetectBlockedPort: true,
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, timeoutDial(&dials))
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("a@gmail.com"))
+	assert.False(t, result.Passed)
+	assert.Contains(t, result.Details, "SMTP probe failed on all MX hosts")
+	assert.True(t, c.PortBlocked())
+	assert.True(t, c.LastProbe().IsZero())
+
+	result = c.Check(context.Background(), parse.NewEmail("b@gmail.com"))
+	assert.True(t, result.Passed)
+	assert.Equal(t, types.CodeSkipped, result.Code)
+	assert.Equal(t, "skipped: outbound port 25 blocked", result.Details)
+	assert.Equal(t, int64(2), dials.Load(), "no dial while blocked")
+}
+
+func TestSMTPChecker_DetectBlockedPortIgnoresUnknownHosts(t *testing.T) {
+	var dials atomic.Int64
+	mxRecords := []*net.MX{
+		{Host: "mx1.example.com.", Pref: 10},
+		{Host: "mx2.example.com.", Pref: 20},
+	}
+	cfg := check.SMTPConfig{
+		HeloDomain:        "test.com",
+		MailFrom:          "verify@test.com",
+		MaxMXHosts:        2,
+		DetectBlockedPort: true,
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, timeoutDial(&dials))
+	defer cleanup()
+
+	for _, addr := range []string{"a@example.com", "b@example.com"} {
+		assert.False(t, c.Check(context.Background(), parse.NewEmail(addr)).Passed)
+	}
+	// A small domain's MX hosts being down says nothing about the network
+	assert.False(t, c.PortBlocked())
+	assert.Equal(t, int64(4), dials.Load())
+}
+
+func TestSMTPChecker_DetectBlockedPortIgnoresProxyAndCancelled(t *testing.T) {
+	mxRecords := []*net.MX{
+		{Host: "gmail-smtp-in.l.google.com.", Pref: 5},
+		{Host: "alt1.gmail-smtp-in.l.google.com.", Pref: 10},
+	}
+	cfg := check.SMTPConfig{
+		HeloDomain:        "test.com",
+		MailFrom:          "verify@test.com",
+		MaxMXHosts:        2,
+		DetectBlockedPort: true,
+	}
+
+	// A proxy that is down says nothing about port 25
+	proxyDown := func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return nil, &smtppool.ProxyError{Proxy: "127.0.0.1:1080", Err: os.ErrDeadlineExceeded}
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, proxyDown)
+	defer cleanup()
+	for _, addr := range []string{"a@gmail.com", "b@gmail.com"} {
+		assert.False(t, c.Check(context.Background(), parse.NewEmail(addr)).Passed)
+	}
+	assert.False(t, c.PortBlocked())
+
+	// Neither does a dial cut short by the caller
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelled := func(network, address string, timeout time.Duration) (net.Conn, error) {
+		if strings.HasPrefix(address, "alt1.") {
+			cancel()
+		}
+		return nil, &net.OpError{Op: "dial", Net: network, Err: os.ErrDeadlineExceeded}
+	}
+	c, cleanup = newTestSMTPCheckerWithConfig(cfg, mxRecords, cancelled)
+	defer cleanup()
+	assert.False(t, c.Check(ctx, parse.NewEmail("a@gmail.com")).Passed)
+	assert.False(t, c.PortBlocked())
+}
@@ -0,0 +1,47 @@
+package check
+
+import "github.com/optimode/emailkit/types"
+
+// Cost classes what running a level takes, from cheapest to dearest.
+type Cost int
+
+const (
+	CostOffline Cost = iota // no network: embedded lists and parsing
+	CostDNS                 // DNS lookups, mostly cached
+	CostNetwork             // other network requests, e.g. RDAP or HTTPS
+	CostSMTP                // SMTP sessions with the domain's mail hosts
+)
+
+// String returns the name of the cost class, e.g. "dns".
+func (c Cost) String() string {
+	switch c {
+	case CostOffline:
+		return "offline"
+	case CostDNS:
+		return "dns"
+	case CostNetwork:
+		return "network"
+	case CostSMTP:
+		return "smtp"
+	}
+	return "unknown"
+}
+
+// Capabilities declares what a level is, costs and needs, so that a
+// pipeline can order its levels and drop duplicates without relying on
+// the order they were added in.
+type Capabilities struct {
+	// Level is the CheckLevel the level reports.
+	Level types.CheckLevel
+	// Cost is what running the level takes.
+	Cost Cost
+	// ProvidesMX is set for a level that resolves the domain's MX records
+	// and records them in the Findings of the validation.
+	ProvidesMX bool
+	// NeedsMX is set for a level that uses the domain's MX records; it
+	// runs after the level that provides them.
+	NeedsMX bool
+}
+
+// Network reports whether the level makes network requests.
+func (c Capabilities) Network() bool { return c.Cost > CostOffline }
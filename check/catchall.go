@@ -0,0 +1,70 @@
+package check
+
+import (
+	"regexp"
+
+	"github.com/optimode/emailkit/internal/provider"
+)
+
+// CatchAllFingerprint describes a mailbox provider or mail gateway whose
+// RCPT TO acceptance doesn't prove the mailbox exists: it accepts every
+// recipient and bounces unknown ones asynchronously, after DATA. A 250
+// from a matching server is reported as CodeCatchAll.
+type CatchAllFingerprint struct {
+	// Name identifies the fingerprint in CheckResult.Meta["catch_all"].
+	Name string `json:"name"`
+	// Provider matches the mailbox provider detected from the MX hosts
+	// ("yahoo", "proofpoint", ...; see InspectDomain). Empty matches any.
+	Provider string `json:"provider,omitempty"`
+	// Reply, if set, must match the text of the 250 reply.
+	Reply *regexp.Regexp `json:"reply,omitempty"`
+	// Reason explains the behavior; it is added to the details.
+	Reason string `json:"reason"`
+}
+
+// DefaultCatchAllFingerprints are the built-in fingerprints.
+var DefaultCatchAllFingerprints = []CatchAllFingerprint{
+	{
+		Name:     "yahoo",
+		Provider: provider.Yahoo,
+		Reason:   "Yahoo accepts every recipient at RCPT time and bounces unknown mailboxes later",
+	},
+	{
+		Name:     "proofpoint",
+		Provider: provider.Proofpoint,
+		Reason:   "Proofpoint gateways accept recipients on behalf of the mail server and may bounce later",
+	},
+	{
+		Name:     "mimecast",
+		Provider: provider.Mimecast,
+		Reason:   "Mimecast gateways accept recipients on behalf of the mail server and may bounce later",
+	},
+	{
+		Name:     "barracuda",
+		Provider: provider.Barracuda,
+		Reason:   "Barracuda gateways accept recipients on behalf of the mail server and may bounce later",
+	},
+	{
+		Name:   "deferred-verification",
+		Reply:  regexp.MustCompile(`(?i)(verif\w*|check\w*) (later|after|on delivery)|not (been )?verified|unverified`),
+		Reason: "the server defers recipient verification",
+	},
+}
+
+// catchAll returns the first fingerprint matching a 250 reply msg from a
+// server of provider prov.
+func (c *SMTPChecker) catchAll(prov, msg string) (CatchAllFingerprint, bool) {
+	for _, fp := range c.cfg.CatchAllFingerprints {
+		if fp.Provider != "" && fp.Provider != prov {
+			continue
+		}
+		if fp.Reply != nil && !fp.Reply.MatchString(msg) {
+			continue
+		}
+		if fp.Provider == "" && fp.Reply == nil {
+			continue // matches everything: a misconfiguration
+		}
+		return fp, true
+	}
+	return CatchAllFingerprint{}, false
+}
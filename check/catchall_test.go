@@ -0,0 +1,89 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// replyingDial connects to a server answering RCPT TO with rcptReply.
+func replyingDial(rcptReply string) func(string, string, time.Duration) (net.Conn, error) {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "RSET": "250 OK",
+			"MAIL FROM": "250 OK", "RCPT TO": rcptReply,
+		}
+		go testSMTPServer(server, "220 mx ESMTP", responses)
+		return client, nil
+	}
+}
+
+func TestSMTPChecker_CatchAllFingerprints(t *testing.T) {
+	cfg := check.SMTPConfig{
+		HeloDomain:           "test.com",
+		MailFrom:             "verify@test.com",
+		CatchAllFingerprints: check.DefaultCatchAllFingerprints,
+	}
+
+	tests := []struct {
+		name   string
+		mx     string
+		reply  string
+		want   string // fingerprint name, "" for a plain accept
+		reason string
+	}{
+		{"provider", "mta5.am0.yahoodns.net.", "250 recipient <x@yahoo.com> ok", "yahoo", "Yahoo accepts every recipient"},
+		{"gateway", "mx0a-001.pphosted.com.", "250 2.1.5 Ok", "proofpoint", "Proofpoint gateways"},
+		{"reply text", "mx.example.com.", "250 2.1.5 Recipient accepted, will verify later", "deferred-verification", "defers recipient verification"},
+		{"no match", "aspmx.l.google.com.", "250 2.1.5 OK", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, cleanup := newTestSMTPCheckerWithConfig(cfg, []*net.MX{{Host: tt.mx, Pref: 10}}, replyingDial(tt.reply))
+			defer cleanup()
+
+			res := c.Check(context.Background(), parse.NewEmail("someone@example.com"))
+			assert.True(t, res.Passed)
+			assert.Equal(t, 250, res.SMTPCode)
+			if tt.want == "" {
+				assert.Empty(t, res.Code)
+				assert.Nil(t, res.Meta)
+				return
+			}
+			assert.Equal(t, types.CodeCatchAll, res.Code)
+			assert.Equal(t, tt.want, res.Meta["catch_all"])
+			assert.Equal(t, "unknown", res.Meta["deliverability"])
+			assert.Contains(t, res.Details, tt.reason)
+		})
+	}
+}
+
+func TestSMTPChecker_CatchAllCustomFingerprint(t *testing.T) {
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		CatchAllFingerprints: []check.CatchAllFingerprint{
+			{Name: "acme-relay", Reply: regexp.MustCompile(`queued for relay`), Reason: "the ACME relay accepts everything"},
+			{Name: "match-all"}, // neither provider nor reply: ignored
+		},
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, []*net.MX{{Host: "mx.example.com.", Pref: 10}}, replyingDial("250 queued for relay"))
+	defer cleanup()
+	res := c.Check(context.Background(), parse.NewEmail("someone@example.com"))
+	assert.Equal(t, types.CodeCatchAll, res.Code)
+	assert.Equal(t, "acme-relay", res.Meta["catch_all"])
+
+	c, cleanup = newTestSMTPCheckerWithConfig(cfg, []*net.MX{{Host: "mx.example.com.", Pref: 10}}, replyingDial("250 OK"))
+	defer cleanup()
+	res = c.Check(context.Background(), parse.NewEmail("someone@example.com"))
+	assert.Empty(t, res.Code)
+}
@@ -0,0 +1,154 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/optimode/emailkit/internal/compliance"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// ComplianceEntry is an entry of a compliance policy.
+type ComplianceEntry struct {
+	// Domain is the domain or TLD, in lowercase ASCII form; it matches
+	// itself and its subdomains.
+	Domain string          `json:"domain"`
+	Code   types.CheckCode `json:"code"` // CodeSanctioned or CodeRestricted
+	Note   string          `json:"note,omitempty"`
+}
+
+// CompliancePolicy returns the entries of the embedded policy pack, for
+// audits: TLDs of jurisdictions under comprehensive sanctions
+// (CodeSanctioned) and government and military domains (CodeRestricted).
+func CompliancePolicy() []ComplianceEntry {
+	builtin := compliance.Builtin()
+	out := make([]ComplianceEntry, 0, len(builtin))
+	for _, e := range builtin {
+		out = append(out, fromPack(e))
+	}
+	return out
+}
+
+// fromPack converts an entry of the policy pack.
+func fromPack(e compliance.Entry) ComplianceEntry {
+	code := types.CodeRestricted
+	if e.Kind == compliance.Sanctioned {
+		code = types.CodeSanctioned
+	}
+	return ComplianceEntry{Domain: e.Domain, Code: code, Note: e.Note}
+}
+
+// ComplianceConfig is the compliance checker configuration.
+type ComplianceConfig struct {
+	// Sanctioned and Restricted add domains or TLDs to the embedded policy.
+	Sanctioned []string
+	Restricted []string
+	// Exempt lifts entries: a domain or TLD listed here, and its
+	// subdomains, match no less specific entry, e.g. "gov" or
+	// "agency.gov".
+	Exempt []string
+	// FailRestricted fails restricted domains instead of flagging them.
+	FailRestricted bool
+}
+
+// ComplianceChecker matches domains against a compliance policy: the
+// current pack (embedded, or the one given to LoadCompliancePolicy) plus
+// the configured entries, which win over the pack's for the same domain.
+// A sanctioned domain fails
+// with CodeSanctioned; a restricted one passes with CodeRestricted, or
+// fails with FailRestricted. The most specific matching entry or
+// exemption decides.
+type ComplianceChecker struct {
+	cfg     ComplianceConfig
+	entries map[string]ComplianceEntry // configured entries
+	exempt  map[string]bool
+}
+
+// NewComplianceChecker creates a compliance checker.
+func NewComplianceChecker(cfg ComplianceConfig) *ComplianceChecker {
+	c := &ComplianceChecker{cfg: cfg, entries: make(map[string]ComplianceEntry), exempt: make(map[string]bool)}
+	for _, d := range cfg.Sanctioned {
+		d = policyDomain(d)
+		c.entries[d] = ComplianceEntry{Domain: d, Code: types.CodeSanctioned}
+	}
+	for _, d := range cfg.Restricted {
+		d = policyDomain(d)
+		c.entries[d] = ComplianceEntry{Domain: d, Code: types.CodeRestricted}
+	}
+	for _, d := range cfg.Exempt {
+		c.exempt[policyDomain(d)] = true
+	}
+	return c
+}
+
+// policyDomain normalizes a policy entry to lowercase ASCII form.
+func policyDomain(d string) string {
+	d = strings.Trim(strings.TrimSpace(d), ".")
+	if parsed := parse.NewDomain(d); parsed.Valid {
+		return parsed.Domain
+	}
+	return strings.ToLower(d)
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input).
+func (c *ComplianceChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the compliance level, which matches an embedded
+// policy without network lookups.
+func (c *ComplianceChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelCompliance, Cost: CostOffline}
+}
+
+func (c *ComplianceChecker) Check(_ context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelCompliance
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	entry, ok := c.match(strings.ToLower(email.Domain))
+	if !ok {
+		return types.CheckResult{Level: level, Passed: true, Details: "no compliance restriction"}
+	}
+
+	what := "domain"
+	if !strings.Contains(entry.Domain, ".") {
+		what = "TLD"
+	}
+	details := fmt.Sprintf("%s %s %s", entry.Code, what, entry.Domain)
+	if entry.Note != "" {
+		details += " (" + entry.Note + ")"
+	}
+	return types.CheckResult{
+		Level:   level,
+		Passed:  entry.Code == types.CodeRestricted && !c.cfg.FailRestricted,
+		Details: details,
+		Code:    entry.Code,
+		Meta:    map[string]string{"entry": entry.Domain},
+	}
+}
+
+// match returns the most specific entry for domain, unless an exemption
+// is at least as specific.
+func (c *ComplianceChecker) match(domain string) (ComplianceEntry, bool) {
+	for suffix := domain; ; {
+		if c.exempt[suffix] {
+			return ComplianceEntry{}, false
+		}
+		if e, ok := c.entries[suffix]; ok {
+			return e, true
+		}
+		if e, ok := compliance.Lookup(suffix); ok {
+			return fromPack(e), true
+		}
+		_, rest, found := strings.Cut(suffix, ".")
+		if !found {
+			return ComplianceEntry{}, false
+		}
+		suffix = rest
+	}
+}
@@ -0,0 +1,78 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestComplianceChecker(t *testing.T) {
+	requireDatasets(t)
+	c := check.NewComplianceChecker(check.ComplianceConfig{
+		Sanctioned: []string{"Blocked.Example"},
+		Restricted: []string{".agency.test"},
+		Exempt:     []string{"gov.uk", "open.mil"},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		email  string
+		passed bool
+		code   string
+		entry  string
+	}{
+		{"user@example.com", true, "", ""},
+		{"user@mail.example.ir", false, types.CodeSanctioned, "ir"},
+		{"user@example.ایران", false, types.CodeSanctioned, "xn--mgba3a4f16a"},
+		{"user@army.mil", true, types.CodeRestricted, "mil"},
+		{"user@open.mil", true, "", ""},       // exempt
+		{"user@dept.open.mil", true, "", ""},  // exempt with its subdomains
+		{"user@cabinet.gov.uk", true, "", ""}, // exempt
+		{"user@sub.blocked.example", false, types.CodeSanctioned, "blocked.example"},
+		{"user@x.agency.test", true, types.CodeRestricted, "agency.test"},
+		{"user@ir.example.com", true, "", ""}, // only suffixes match
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			r := c.Check(ctx, parse.NewEmail(tt.email))
+			assert.Equal(t, types.LevelCompliance, r.Level)
+			assert.Equal(t, tt.passed, r.Passed, r.Details)
+			assert.Equal(t, tt.code, r.Code)
+			assert.Equal(t, tt.entry, r.Meta["entry"])
+		})
+	}
+
+	r := c.CheckDomain(ctx, parse.NewDomain("tehran.ir"))
+	assert.False(t, r.Passed)
+	assert.Equal(t, "sanctioned TLD ir (Iran)", r.Details)
+
+	r = c.Check(ctx, parse.NewEmail("not an email"))
+	assert.False(t, r.Passed)
+	assert.Empty(t, r.Code)
+}
+
+func TestComplianceChecker_FailRestricted(t *testing.T) {
+	requireDatasets(t)
+	c := check.NewComplianceChecker(check.ComplianceConfig{FailRestricted: true})
+	r := c.Check(context.Background(), parse.NewEmail("user@agency.gov"))
+	assert.False(t, r.Passed)
+	assert.Equal(t, types.CodeRestricted, r.Code)
+	assert.Equal(t, "restricted TLD gov (US government)", r.Details)
+}
+
+func TestCompliancePolicy(t *testing.T) {
+	requireDatasets(t)
+	policy := check.CompliancePolicy()
+	codes := make(map[string]string)
+	for _, e := range policy {
+		assert.NotEmpty(t, e.Note, e.Domain)
+		codes[e.Domain] = e.Code
+	}
+	assert.Equal(t, types.CodeSanctioned, codes["kp"])
+	assert.Equal(t, types.CodeRestricted, codes["gov.uk"])
+}
@@ -0,0 +1,37 @@
+package check_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit/internal/compliance"
+	"github.com/optimode/emailkit/internal/disposable"
+	"github.com/optimode/emailkit/internal/freemail"
+)
+
+// requireDatasets loads the datasets left out by the emailkit_no* build
+// tags from their source files, so tests see the same data either way.
+func requireDatasets(t *testing.T) {
+	t.Helper()
+	for _, ds := range []struct {
+		embedded bool
+		path     string
+		load     func(io.Reader) (int, error)
+	}{
+		{disposable.Embedded, "../internal/disposable/list.txt", disposable.Load},
+		{freemail.Embedded, "../internal/freemail/list.txt", freemail.Load},
+		{compliance.Embedded, "../internal/compliance/policy.txt", compliance.Load},
+	} {
+		if ds.embedded {
+			continue
+		}
+		f, err := os.Open(ds.path)
+		require.NoError(t, err)
+		_, err = ds.load(f)
+		_ = f.Close()
+		require.NoError(t, err)
+	}
+}
@@ -2,12 +2,17 @@ package check
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/optimode/emailkit/internal/dnscache"
 	"github.com/optimode/emailkit/internal/parse"
 	"github.com/optimode/emailkit/types"
 )
@@ -16,46 +21,125 @@ import (
 type DNSConfig struct {
 	Timeout     time.Duration
 	FallbackToA bool
+	// DetectWildcard when true rejects an A record fallback if a random
+	// subdomain of the domain resolves to the same address: the record
+	// comes from wildcard DNS, not from a host set up to receive mail.
+	DetectWildcard bool
+	// LookupHost resolves the A record fallback. Default:
+	// net.DefaultResolver.LookupHost
+	LookupHost func(ctx context.Context, host string) ([]string, error)
+	// Diagnostics when true adds dig-style details of the MX lookup to
+	// CheckResult.Meta: "dns.status", "dns.rtt", "dns.source",
+	// "dns.answers" and "dns.resolver".
+	Diagnostics bool
+	// ResolverName names the resolver in "dns.resolver". Default: "system"
+	ResolverName func() string
+	// Retries is how many times an MX lookup that timed out or failed
+	// with SERVFAIL is repeated, bypassing the cache. Default: 0
+	Retries int
 }
 
 // DNSChecker verifies the existence of MX records.
 type DNSChecker struct {
 	cfg    DNSConfig
-	lookup func(domain string) ([]*net.MX, error) // injectable for testability
+	lookup func(domain string) ([]*net.MX, dnscache.Info, error) // injectable for testability
+	forget func(domain string)                                   // drops a cached answer before a retry; may be nil
 }
 
 func NewDNSChecker(cfg DNSConfig) *DNSChecker {
-	return &DNSChecker{
-		cfg: cfg,
-		lookup: func(domain string) ([]*net.MX, error) {
-			ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
-			defer cancel()
-			r := &net.Resolver{}
-			return r.LookupMX(ctx, domain)
-		},
-	}
+	return NewDNSCheckerWithLookup(cfg, func(domain string) ([]*net.MX, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+		defer cancel()
+		r := &net.Resolver{}
+		return r.LookupMX(ctx, domain)
+	})
 }
 
 // NewDNSCheckerWithLookup is a test-oriented constructor that overrides the MX lookup function.
 func NewDNSCheckerWithLookup(cfg DNSConfig, fn func(string) ([]*net.MX, error)) *DNSChecker {
-	c := NewDNSChecker(cfg)
-	c.lookup = fn
+	c := newDNSChecker(cfg)
+	c.lookup = func(domain string) ([]*net.MX, dnscache.Info, error) {
+		start := time.Now()
+		records, err := fn(domain)
+		return records, dnscache.Info{Source: dnscache.SourceResolver, RTT: time.Since(start)}, err
+	}
 	return c
 }
 
-func (c *DNSChecker) Check(ctx context.Context, email parse.Email) types.CheckResult {
-	level := types.LevelDNS
+// NewDNSCheckerWithCache creates a DNS checker that looks up MX records
+// through cache, sharing them with the SMTP level.
+func NewDNSCheckerWithCache(cfg DNSConfig, cache *dnscache.Cache) *DNSChecker {
+	c := newDNSChecker(cfg)
+	c.lookup = cache.LookupMXInfo
+	c.forget = cache.Forget
+	return c
+}
+
+func newDNSChecker(cfg DNSConfig) *DNSChecker {
+	if cfg.LookupHost == nil {
+		cfg.LookupHost = net.DefaultResolver.LookupHost
+	}
+	return &DNSChecker{cfg: cfg}
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input);
+// the DNS level only looks at the domain.
+func (c *DNSChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
 
+// Capabilities declares the DNS level, which resolves the MX records the
+// levels after it reuse.
+func (c *DNSChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelDNS, Cost: CostDNS, ProvidesMX: true}
+}
+
+func (c *DNSChecker) Check(ctx context.Context, email parse.Email) types.CheckResult {
 	if !email.Valid {
-		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+		return types.CheckResult{Level: types.LevelDNS, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	mxRecords, info, err := c.lookup(email.Domain)
+	for i := 0; i < c.cfg.Retries && transientDNSError(err) && ctx.Err() == nil; i++ {
+		if c.forget != nil {
+			c.forget(email.Domain)
+		}
+		mxRecords, info, err = c.lookup(email.Domain)
 	}
+	result := c.check(ctx, email, mxRecords, err)
+	f := ValidationFrom(ctx).Findings()
+	switch {
+	case result.Passed && err == nil:
+		f.SetMX(mxRecords)
+	case result.Code == types.CodeBadDomain && nullMX(mxRecords):
+		f.SetNoMail("null MX")
+	case result.Code == types.CodeBadDomain:
+		f.SetNoMail("NXDOMAIN")
+	}
+	if c.cfg.Diagnostics {
+		result.Meta = c.diagnostics(mxRecords, info, err)
+	}
+	return result
+}
+
+// check grades an MX lookup, falling back to A records if configured.
+func (c *DNSChecker) check(ctx context.Context, email parse.Email, mxRecords []*net.MX, err error) types.CheckResult {
+	level := types.LevelDNS
 
-	mxRecords, err := c.lookup(email.Domain)
 	if err != nil {
 		// If FallbackToA is enabled, try A record
 		if c.cfg.FallbackToA {
-			addrs, aErr := net.LookupHost(email.Domain)
+			ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+			defer cancel()
+			addrs, aErr := c.cfg.LookupHost(ctx, email.Domain)
 			if aErr == nil && len(addrs) > 0 {
+				if c.cfg.DetectWildcard && c.wildcard(ctx, email.Domain, addrs) {
+					return types.CheckResult{
+						Level:   level,
+						Passed:  false,
+						Details: "no MX record, and the A record comes from wildcard DNS",
+					}
+				}
 				return types.CheckResult{
 					Level:   level,
 					Passed:  true,
@@ -68,12 +152,21 @@ func (c *DNSChecker) Check(ctx context.Context, email parse.Email) types.CheckRe
 			Level:   level,
 			Passed:  false,
 			Details: fmt.Sprintf("MX lookup failed: %v", err),
+			Code:    dnsErrorCode(err),
 		}
 	}
 
 	if len(mxRecords) == 0 {
 		return types.CheckResult{Level: level, Passed: false, Details: "no MX records found"}
 	}
+	if nullMX(mxRecords) {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  false,
+			Details: "domain accepts no mail (null MX)",
+			Code:    types.CodeBadDomain,
+		}
+	}
 
 	sort.Slice(mxRecords, func(i, j int) bool {
 		return mxRecords[i].Pref < mxRecords[j].Pref
@@ -87,3 +180,75 @@ func (c *DNSChecker) Check(ctx context.Context, email parse.Email) types.CheckRe
 		MXHost:  primaryMX,
 	}
 }
+
+// diagnostics describes an MX lookup for CheckResult.Meta.
+func (c *DNSChecker) diagnostics(mxRecords []*net.MX, info dnscache.Info, err error) map[string]string {
+	resolver := "system"
+	if c.cfg.ResolverName != nil {
+		resolver = c.cfg.ResolverName()
+	}
+	return map[string]string{
+		"dns.status":   dnsStatus(err),
+		"dns.rtt":      info.RTT.Round(time.Microsecond).String(),
+		"dns.source":   string(info.Source),
+		"dns.answers":  strconv.Itoa(len(mxRecords)),
+		"dns.resolver": resolver,
+	}
+}
+
+// dnsStatus names the outcome of a lookup the way dig does: NOERROR,
+// NXDOMAIN (also for a name without records of the type), SERVFAIL, or
+// TIMEOUT; ERROR for anything else, e.g. a refused connection.
+func dnsStatus(err error) string {
+	var dnsErr *net.DNSError
+	switch {
+	case err == nil:
+		return "NOERROR"
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return "NXDOMAIN"
+	case errors.As(err, &dnsErr) && dnsErr.IsTimeout, errors.Is(err, context.DeadlineExceeded):
+		return "TIMEOUT"
+	case errors.As(err, &dnsErr) && dnsErr.IsTemporary:
+		return "SERVFAIL"
+	}
+	return "ERROR"
+}
+
+// dnsErrorCode classifies a failed lookup: CodeBadDomain for NXDOMAIN,
+// CodeDNSTimeout or CodeDNSError for resolver trouble, which says
+// nothing about the domain.
+func dnsErrorCode(err error) types.CheckCode {
+	switch dnsStatus(err) {
+	case "NOERROR":
+		return ""
+	case "NXDOMAIN":
+		return types.CodeBadDomain
+	case "TIMEOUT":
+		return types.CodeDNSTimeout
+	}
+	return types.CodeDNSError
+}
+
+// transientDNSError reports whether a lookup failed because of the
+// resolver rather than the domain, so repeating it may succeed.
+func transientDNSError(err error) bool {
+	code := dnsErrorCode(err)
+	return code == types.CodeDNSTimeout || code == types.CodeDNSError
+}
+
+// wildcard reports whether a random subdomain of domain resolves to one of
+// addrs, i.e. the domain's addresses are likely synthesized by a wildcard
+// record. Lookup errors count as no wildcard.
+func (c *DNSChecker) wildcard(ctx context.Context, domain string, addrs []string) bool {
+	probe := fmt.Sprintf("ek-%016x.%s", rand.Uint64(), domain)
+	wild, err := c.cfg.LookupHost(ctx, probe)
+	if err != nil {
+		return false
+	}
+	for _, w := range wild {
+		if slices.Contains(addrs, w) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,14 +2,19 @@ package check_test
 
 import (
 	"context"
+	"errors"
 	"net"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/dnscache"
 	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
 )
 
 func TestDNSChecker_WithMockLookup(t *testing.T) {
@@ -84,3 +89,151 @@ func TestDNSChecker_InvalidEmail(t *testing.T) {
 	assert.False(t, result.Passed)
 	assert.Contains(t, result.Details, "skipped")
 }
+
+func TestDNSChecker_FallbackWildcard(t *testing.T) {
+	noMX := func(domain string) ([]*net.MX, error) {
+		return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+	}
+	// wildcard.example has *.wildcard.example; plain.example only an apex A record.
+	lookupHost := func(_ context.Context, host string) ([]string, error) {
+		switch {
+		case host == "plain.example":
+			return []string{"192.0.2.1"}, nil
+		case strings.HasSuffix(host, ".wildcard.example"):
+			return []string{"192.0.2.2"}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	tests := []struct {
+		name        string
+		email       string
+		detect      bool
+		wantOK      bool
+		wantDetails string
+	}{
+		{"explicit A record", "user@plain.example", true, true, "A record found (fallback)"},
+		{"wildcard A record", "user@mail.wildcard.example", true, false, "wildcard DNS"},
+		{"detection off", "user@mail.wildcard.example", false, true, "A record found (fallback)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := check.NewDNSCheckerWithLookup(check.DNSConfig{
+				Timeout:        2 * time.Second,
+				FallbackToA:    true,
+				DetectWildcard: tt.detect,
+				LookupHost:     lookupHost,
+			}, noMX)
+			result := c.Check(context.Background(), parse.NewEmail(tt.email))
+			assert.Equal(t, tt.wantOK, result.Passed)
+			assert.Contains(t, result.Details, tt.wantDetails)
+		})
+	}
+}
+
+func TestDNSChecker_Diagnostics(t *testing.T) {
+	tests := []struct {
+		name       string
+		records    []*net.MX
+		err        error
+		wantStatus string
+	}{
+		{"answer", []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil, "NOERROR"},
+		{"nxdomain", nil, &net.DNSError{Err: "no such host", IsNotFound: true}, "NXDOMAIN"},
+		{"servfail", nil, &net.DNSError{Err: "server misbehaving", IsTemporary: true}, "SERVFAIL"},
+		{"timeout", nil, &net.DNSError{Err: "i/o timeout", IsTimeout: true, IsTemporary: true}, "TIMEOUT"},
+		{"other", nil, errors.New("connection refused"), "ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := check.NewDNSCheckerWithLookup(check.DNSConfig{
+				Timeout:      2 * time.Second,
+				Diagnostics:  true,
+				ResolverName: func() string { return "192.0.2.53:53" },
+			}, func(string) ([]*net.MX, error) { return tt.records, tt.err })
+			result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+			assert.Equal(t, tt.wantStatus, result.Meta["dns.status"])
+			assert.Equal(t, "resolver", result.Meta["dns.source"])
+			assert.Equal(t, "192.0.2.53:53", result.Meta["dns.resolver"])
+			assert.Equal(t, strconv.Itoa(len(tt.records)), result.Meta["dns.answers"])
+			assert.NotEmpty(t, result.Meta["dns.rtt"])
+		})
+	}
+
+	c := check.NewDNSCheckerWithLookup(check.DNSConfig{Timeout: 2 * time.Second}, func(string) ([]*net.MX, error) {
+		return []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil
+	})
+	assert.Nil(t, c.Check(context.Background(), parse.NewEmail("test@example.com")).Meta, "off by default")
+}
+
+func TestDNSChecker_ErrorCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode types.CheckCode
+	}{
+		{"nxdomain", &net.DNSError{Err: "no such host", IsNotFound: true}, types.CodeBadDomain},
+		{"timeout", &net.DNSError{Err: "i/o timeout", IsTimeout: true, IsTemporary: true}, types.CodeDNSTimeout},
+		{"servfail", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, types.CodeDNSError},
+		{"unreachable resolver", errors.New("connection refused"), types.CodeDNSError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := check.NewDNSCheckerWithLookup(check.DNSConfig{Timeout: 2 * time.Second}, func(string) ([]*net.MX, error) {
+				return nil, tt.err
+			})
+			result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+			assert.False(t, result.Passed)
+			assert.Equal(t, tt.wantCode, result.Code)
+		})
+	}
+}
+
+func TestDNSChecker_Retries(t *testing.T) {
+	servfail := &net.DNSError{Err: "server misbehaving", IsTemporary: true}
+	var calls int
+	lookup := func(string) ([]*net.MX, error) {
+		calls++
+		if calls < 3 {
+			return nil, servfail
+		}
+		return []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil
+	}
+
+	c := check.NewDNSCheckerWithLookup(check.DNSConfig{Timeout: 2 * time.Second, Retries: 2}, lookup)
+	result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+	assert.True(t, result.Passed)
+	assert.Equal(t, 3, calls)
+
+	// NXDOMAIN is an answer, not retried
+	calls = 0
+	c = check.NewDNSCheckerWithLookup(check.DNSConfig{Timeout: 2 * time.Second, Retries: 2}, func(string) ([]*net.MX, error) {
+		calls++
+		return nil, &net.DNSError{Err: "no such host", IsNotFound: true}
+	})
+	result = c.Check(context.Background(), parse.NewEmail("test@example.com"))
+	assert.False(t, result.Passed)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDNSChecker_RetriesBypassCache(t *testing.T) {
+	r := &flakyMXResolver{failures: 1}
+	cache := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	c := check.NewDNSCheckerWithCache(check.DNSConfig{Timeout: 2 * time.Second, Retries: 1}, cache)
+	result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+	assert.True(t, result.Passed, "the cached SERVFAIL is dropped before retrying")
+	assert.Equal(t, 2, r.calls)
+}
+
+// flakyMXResolver fails the first failures lookups with SERVFAIL.
+type flakyMXResolver struct {
+	failures, calls int
+}
+
+func (r *flakyMXResolver) LookupMX(context.Context, string) ([]*net.MX, error) {
+	r.calls++
+	if r.calls <= r.failures {
+		return nil, &net.DNSError{Err: "server misbehaving", IsTemporary: true}
+	}
+	return []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil
+}
@@ -0,0 +1,148 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// DNSBLConfig is the DNSBL checker configuration.
+type DNSBLConfig struct {
+	// Zones are the DNSBL zones queried, e.g. "zen.spamhaus.org".
+	Zones   []string
+	Timeout time.Duration
+	// MaxHosts is the number of MX hosts (by preference) checked. Default: 3
+	MaxHosts int
+}
+
+// DNSBLChecker checks the addresses of a domain's mail hosts — its MX
+// hosts, or the domain itself if it has no MX records — against DNS
+// blocklists. A listed address passes with CodeDNSBLListed and the
+// listings in Meta "listed"; Meta "hosts" holds the hosts checked. Lookup
+// trouble never fails the check, and answers outside 127.0.0.0/8 or in
+// 127.255.255.0/24 (Spamhaus error codes, e.g. for queries through public
+// resolvers) do not count as listings.
+type DNSBLChecker struct {
+	cfg      DNSBLConfig
+	resolver Resolver
+}
+
+// NewDNSBLChecker creates a DNSBL checker using the given resolver.
+func NewDNSBLChecker(cfg DNSBLConfig, r Resolver) *DNSBLChecker {
+	if cfg.MaxHosts <= 0 {
+		cfg.MaxHosts = 3
+	}
+	zones := make([]string, 0, len(cfg.Zones))
+	for _, z := range cfg.Zones {
+		if z = strings.Trim(strings.ToLower(strings.TrimSpace(z)), "."); z != "" {
+			zones = append(zones, z)
+		}
+	}
+	cfg.Zones = zones
+	return &DNSBLChecker{cfg: cfg, resolver: r}
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input).
+func (c *DNSBLChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the DNSBL level, which looks up the addresses
+// of the MX hosts.
+func (c *DNSBLChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelDNSBL, Cost: CostDNS, NeedsMX: true}
+}
+
+func (c *DNSBLChecker) Check(ctx context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelDNSBL
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	hosts, err := mailHosts(ctx, c.resolver, email.Domain, c.cfg.MaxHosts)
+	if err != nil {
+		return types.CheckResult{Level: level, Passed: true, Details: fmt.Sprintf("listing unknown: %v", err)}
+	}
+
+	var listed []string
+	checked := 0
+	for _, host := range hosts {
+		addrs, err := c.resolver.LookupHost(ctx, host)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				continue
+			}
+			for _, zone := range c.cfg.Zones {
+				answer, ok, err := c.lookup(ctx, ip, zone)
+				if err != nil {
+					continue
+				}
+				checked++
+				if ok {
+					listed = append(listed, fmt.Sprintf("%s %s in %s (%s)", host, addr, zone, answer))
+				}
+			}
+		}
+	}
+
+	meta := map[string]string{"hosts": strings.Join(hosts, ",")}
+	if len(listed) > 0 {
+		meta["listed"] = strings.Join(listed, "; ")
+		return types.CheckResult{
+			Level:   level,
+			Passed:  true,
+			Details: "mail hosts listed: " + strings.Join(listed, "; "),
+			Code:    types.CodeDNSBLListed,
+			Meta:    meta,
+		}
+	}
+	if checked == 0 {
+		detail := "no answer from the blocklists"
+		if ctx.Err() != nil {
+			detail = ctx.Err().Error()
+		}
+		return types.CheckResult{Level: level, Passed: true, Details: "listing unknown: " + detail, Meta: meta}
+	}
+	return types.CheckResult{
+		Level:   level,
+		Passed:  true,
+		Details: fmt.Sprintf("mail hosts not listed on %s", strings.Join(c.cfg.Zones, ", ")),
+		Meta:    meta,
+	}
+}
+
+// lookup queries zone for ip. ok is true for a listing; answer is the
+// listing's return code, e.g. "127.0.0.2".
+func (c *DNSBLChecker) lookup(ctx context.Context, ip net.IP, zone string) (answer string, ok bool, err error) {
+	addrs, err := c.resolver.LookupHost(ctx, reverseName(ip)+"."+zone)
+	if isNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	for _, a := range addrs {
+		code := net.ParseIP(a).To4()
+		switch {
+		case code == nil || code[0] != 127:
+		case code[1] == 255 && code[2] == 255:
+			return "", false, fmt.Errorf("%s refused the query (%s)", zone, a)
+		default:
+			return a, true, nil
+		}
+	}
+	return "", false, fmt.Errorf("%s answered outside 127.0.0.0/8", zone)
+}
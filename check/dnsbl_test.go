@@ -0,0 +1,60 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func dnsblChecker(hosts map[string][]string, zones ...string) *check.DNSBLChecker {
+	r := geoResolver()
+	for name, addrs := range hosts {
+		r.hosts[name] = addrs
+	}
+	return check.NewDNSBLChecker(check.DNSBLConfig{Zones: zones, Timeout: time.Second}, r)
+}
+
+func TestDNSBLChecker(t *testing.T) {
+	c := dnsblChecker(map[string][]string{
+		"1.2.0.192.bl.example": {"127.0.0.2"},
+		"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.bl.example": {"127.0.0.4"},
+		"1.100.51.198.other.example": {"127.0.0.3"},
+	}, "bl.example", " Other.Example. ")
+
+	r := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.Equal(t, types.LevelDNSBL, r.Level)
+	assert.True(t, r.Passed)
+	assert.Equal(t, types.CodeDNSBLListed, r.Code)
+	assert.Equal(t, "mx1.example.net,mx2.example.net", r.Meta["hosts"])
+	assert.Equal(t, "mx1.example.net 192.0.2.1 in bl.example (127.0.0.2); "+
+		"mx1.example.net 2001:db8::1 in bl.example (127.0.0.4); "+
+		"mx2.example.net 198.51.100.1 in other.example (127.0.0.3)", r.Meta["listed"])
+
+	// No MX: the domain's own address
+	r = c.Check(context.Background(), parse.NewEmail("user@nomx.example"))
+	assert.True(t, r.Passed)
+	assert.Empty(t, r.Code)
+	assert.Equal(t, "mail hosts not listed on bl.example, other.example", r.Details)
+}
+
+func TestDNSBLChecker_Unknown(t *testing.T) {
+	// Spamhaus answers 127.255.255.x for refused queries
+	c := dnsblChecker(map[string][]string{"9.113.0.203.bl.example": {"127.255.255.254"}}, "bl.example")
+	r := c.Check(context.Background(), parse.NewEmail("user@nomx.example"))
+	assert.True(t, r.Passed)
+	assert.Empty(t, r.Code)
+	assert.Equal(t, "listing unknown: no answer from the blocklists", r.Details)
+
+	r = c.Check(context.Background(), parse.NewEmail("user@null.example"))
+	assert.True(t, r.Passed)
+	assert.Equal(t, "listing unknown: domain accepts no mail (null MX)", r.Details)
+
+	r = c.Check(context.Background(), parse.NewEmail("not-an-email"))
+	assert.False(t, r.Passed)
+}
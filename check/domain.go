@@ -3,6 +3,7 @@ package check
 import (
 	"context"
 	"strings"
+	"sync"
 
 	"github.com/optimode/emailkit/internal/disposable"
 	"github.com/optimode/emailkit/internal/levenshtein"
@@ -12,15 +13,20 @@ import (
 
 // DomainConfig is the domain checker configuration.
 type DomainConfig struct {
-	CheckDisposable bool
-	CheckTypos      bool
-	TypoThreshold   int
+	CheckDisposable    bool
+	CheckTypos         bool
+	TypoThreshold      int
+	CheckProviderRules bool
+	// TypoCorpus are additional domains typos are matched against, e.g.
+	// customer domains. Indexed in a BK-tree, so large corpora stay cheap.
+	TypoCorpus []string
 }
 
-// DomainChecker detects disposable domains and typos.
+// DomainChecker detects disposable domains and typos, and applies
+// provider-specific local-part rules for known providers.
 type DomainChecker struct {
-	cfg            DomainConfig
-	knownProviders []string // known major email providers for typo detection
+	cfg       DomainConfig
+	typoIndex *levenshtein.BKTree // known providers, then TypoCorpus
 }
 
 // defaultKnownProviders is the list of known major email providers.
@@ -43,11 +49,38 @@ var defaultKnownProviders = []string{
 	"freemail.hu", "citromail.hu", "t-online.hu", "invitel.hu",
 }
 
+// defaultTypoIndex indexes defaultKnownProviders, shared by checkers
+// without a TypoCorpus.
+var defaultTypoIndex = sync.OnceValue(func() *levenshtein.BKTree {
+	return levenshtein.NewBKTree(defaultKnownProviders)
+})
+
 func NewDomainChecker(cfg DomainConfig) *DomainChecker {
-	return &DomainChecker{
-		cfg:            cfg,
-		knownProviders: defaultKnownProviders,
+	c := &DomainChecker{cfg: cfg}
+	if len(cfg.TypoCorpus) == 0 {
+		c.typoIndex = defaultTypoIndex()
+		return c
+	}
+	// Providers go in first so they win distance ties.
+	c.typoIndex = levenshtein.NewBKTree(defaultKnownProviders)
+	for _, raw := range cfg.TypoCorpus {
+		// Match on the same lowercase Unicode form as Check.
+		if d := parse.NewDomain(raw); d.Valid {
+			c.typoIndex.Add(strings.ToLower(d.DomainUnicode))
+		}
 	}
+	return c
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input).
+// Provider local-part rules are skipped as there is no local part.
+func (c *DomainChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the domain level, which needs no network.
+func (c *DomainChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelDomain, Cost: CostOffline}
 }
 
 func (c *DomainChecker) Check(_ context.Context, email parse.Email) types.CheckResult {
@@ -66,13 +99,21 @@ func (c *DomainChecker) Check(_ context.Context, email parse.Email) types.CheckR
 	if c.cfg.CheckDisposable {
 		if disposable.IsDisposable(asciiDomain) {
 			return types.CheckResult{
-				Level:   level,
-				Passed:  false,
-				Details: "disposable email domain detected",
+				Level:    level,
+				Passed:   false,
+				Details:  "disposable email domain detected",
+				Category: types.CategoryReputation,
 			}
 		}
 	}
 
+	// Provider rules (e.g. gmail.com requires 6-30 letters, digits or dots)
+	if c.cfg.CheckProviderRules && email.Local != "" {
+		if err := checkProviderRules(asciiDomain, email.Local); err != "" {
+			return types.CheckResult{Level: level, Passed: false, Details: err, Category: types.CategorySyntax}
+		}
+	}
+
 	// Typo detection (warning only, does not fail)
 	if c.cfg.CheckTypos {
 		suggestion := c.findTypoSuggestion(unicodeDomain)
@@ -82,6 +123,7 @@ func (c *DomainChecker) Check(_ context.Context, email parse.Email) types.CheckR
 				Passed:     true, // typo suspicion does not fail
 				Details:    "possible typo in domain",
 				Suggestion: suggestion,
+				Category:   types.CategorySyntax,
 			}
 		}
 	}
@@ -89,23 +131,14 @@ func (c *DomainChecker) Check(_ context.Context, email parse.Email) types.CheckR
 	return types.CheckResult{Level: level, Passed: true, Details: "domain ok"}
 }
 
-// findTypoSuggestion finds the closest known provider.
-// If the distance is <= TypoThreshold and the domain is not an exact match,
-// it returns the suggested domain. Otherwise returns an empty string.
+// findTypoSuggestion finds the closest known provider or TypoCorpus domain.
+// If the distance is <= TypoThreshold and the domain is not an exact match
+// of either, it returns the suggested domain. Otherwise returns an empty
+// string. On equal distance a known provider wins.
 func (c *DomainChecker) findTypoSuggestion(domain string) string {
-	bestDist := c.cfg.TypoThreshold + 1
-	bestMatch := ""
-
-	for _, provider := range c.knownProviders {
-		if domain == provider {
-			return "" // exact match, no typo
-		}
-		dist := levenshtein.Distance(domain, provider)
-		if dist <= c.cfg.TypoThreshold && dist < bestDist {
-			bestDist = dist
-			bestMatch = provider
-		}
+	match, dist, ok := c.typoIndex.Closest(domain, c.cfg.TypoThreshold)
+	if !ok || dist == 0 {
+		return "" // nothing close, or an exact match: no typo
 	}
-
-	return bestMatch
+	return match
 }
@@ -0,0 +1,103 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestDomainChecker(t *testing.T) {
+	requireDatasets(t)
+	c := check.NewDomainChecker(check.DomainConfig{
+		CheckDisposable: true,
+		CheckTypos:      true,
+		TypoThreshold:   2,
+	})
+	ctx := context.Background()
+
+	result := c.Check(ctx, parse.NewEmail("user@mailinator.com"))
+	assert.Equal(t, types.LevelDomain, result.Level)
+	assert.False(t, result.Passed)
+
+	result = c.Check(ctx, parse.NewEmail("user@gmial.com"))
+	assert.True(t, result.Passed)
+	assert.Equal(t, "gmail.com", result.Suggestion)
+
+	result = c.Check(ctx, parse.NewEmail("user@gmail.com"))
+	assert.True(t, result.Passed)
+	assert.Empty(t, result.Suggestion)
+}
+
+func TestDomainChecker_ProviderRules(t *testing.T) {
+	c := check.NewDomainChecker(check.DomainConfig{CheckProviderRules: true})
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		email  string
+		wantOK bool
+	}{
+		{"gmail ok", "john.doe@gmail.com", true},
+		{"gmail ok with tag", "john.doe+news@gmail.com", true},
+		{"gmail ok uppercase", "John.Doe@gmail.com", true},
+		{"gmail too short", "joe@gmail.com", false},
+		{"gmail dots don't count", "j.o.e.x@gmail.com", false},
+		{"gmail too long", "abcdefghijklmnopqrstuvwxyzabcde@gmail.com", false},
+		{"gmail underscore", "john_doe@gmail.com", false},
+		{"gmail hyphen", "john-doe@googlemail.com", false},
+		{"gmail consecutive dots in base", "john..doe@gmail.com", false},
+		{"outlook ok", "john_doe-1@outlook.com", true},
+		{"outlook starts with digit", "1john@hotmail.com", false},
+		{"outlook trailing dot base", "john.+tag@live.com", false},
+		{"yahoo ok", "john.doe_1@yahoo.com", true},
+		{"yahoo disposable hyphen", "john-shopping@yahoo.com", true},
+		{"yahoo two dots", "j.o.hn@yahoo.com", false},
+		{"yahoo too short", "joe@yahoo.com", false},
+		{"yahoo trailing underscore", "johndoe_@ymail.com", false},
+		{"unknown provider untouched", "x@example.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(ctx, parse.NewEmail(tt.email))
+			assert.Equal(t, tt.wantOK, result.Passed, "Details: %s", result.Details)
+			if !tt.wantOK {
+				assert.Contains(t, result.Details, "local part not valid at")
+			}
+		})
+	}
+}
+
+func TestDomainChecker_CheckDomain_SkipsProviderRules(t *testing.T) {
+	requireDatasets(t)
+	c := check.NewDomainChecker(check.DomainConfig{CheckDisposable: true, CheckProviderRules: true})
+	ctx := context.Background()
+
+	assert.True(t, c.CheckDomain(ctx, parse.NewDomain("gmail.com")).Passed)
+	assert.False(t, c.CheckDomain(ctx, parse.NewDomain("mailinator.com")).Passed)
+}
+
+func TestDomainChecker_TypoCorpus(t *testing.T) {
+	c := check.NewDomainChecker(check.DomainConfig{
+		CheckTypos:    true,
+		TypoThreshold: 2,
+		TypoCorpus:    []string{"Acme.io", "globex.com", "not a domain"},
+	})
+	ctx := context.Background()
+
+	result := c.Check(ctx, parse.NewEmail("alice@acmme.io"))
+	assert.True(t, result.Passed)
+	assert.Equal(t, "acme.io", result.Suggestion)
+
+	result = c.Check(ctx, parse.NewEmail("alice@acme.io"))
+	assert.Empty(t, result.Suggestion, "corpus domains are not typos")
+
+	// Known providers still apply, and win ties.
+	result = c.Check(ctx, parse.NewEmail("alice@gmial.com"))
+	assert.Equal(t, "gmail.com", result.Suggestion)
+}
@@ -0,0 +1,95 @@
+package check
+
+import (
+	"net"
+	"sync"
+
+	"github.com/optimode/emailkit/internal/provider"
+)
+
+// Findings carries what the levels of one validation learned about the
+// domain to the levels after them, so that they neither repeat lookups
+// nor probe a domain an earlier level found dead. It is part of the
+// ValidationContext: levels reach it with ValidationFrom(ctx).Findings(),
+// and levels run outside a validation get nil and behave as before. It is
+// safe for concurrent use, and all its methods are no-ops on a nil
+// *Findings.
+type Findings struct {
+	mu       sync.Mutex
+	mx       []*net.MX
+	mxSet    bool
+	provider string
+	noMail   string
+}
+
+// SetMX records the domain's MX records and the mailbox provider they
+// point to.
+func (f *Findings) SetMX(records []*net.MX) {
+	if f == nil {
+		return
+	}
+	key := provider.Detect(mxHosts(records))
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mx, f.mxSet, f.provider = copyMX(records), true, key
+}
+
+// MX returns the recorded MX records; ok is false if none were recorded.
+func (f *Findings) MX() (records []*net.MX, ok bool) {
+	if f == nil {
+		return nil, false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return copyMX(f.mx), f.mxSet
+}
+
+// Provider returns the mailbox provider key of the recorded MX records,
+// e.g. "google", or "" if none was recognized or none were recorded.
+func (f *Findings) Provider() string {
+	if f == nil {
+		return ""
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.provider
+}
+
+// SetNoMail records that the domain definitively accepts no mail, e.g.
+// "NXDOMAIN" or "null MX".
+func (f *Findings) SetNoMail(reason string) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.noMail = reason
+}
+
+// NoMail returns why the domain accepts no mail, or "".
+func (f *Findings) NoMail() string {
+	if f == nil {
+		return ""
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.noMail
+}
+
+func copyMX(records []*net.MX) []*net.MX {
+	if records == nil {
+		return nil
+	}
+	out := make([]*net.MX, len(records))
+	for i, r := range records {
+		cp := *r
+		out[i] = &cp
+	}
+	return out
+}
+
+// nullMX reports whether records are a null MX (RFC 7505): a single
+// record for the root, saying the domain accepts no mail.
+func nullMX(records []*net.MX) bool {
+	return len(records) == 1 && (records[0].Host == "." || records[0].Host == "")
+}
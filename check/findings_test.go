@@ -0,0 +1,81 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestFindings_DNSRecordsMX(t *testing.T) {
+	c := check.NewDNSCheckerWithLookup(check.DNSConfig{Timeout: 2 * time.Second}, func(string) ([]*net.MX, error) {
+		return []*net.MX{{Host: "alt1.aspmx.l.google.com.", Pref: 20}, {Host: "aspmx.l.google.com.", Pref: 10}}, nil
+	})
+	ctx, vc := check.WithValidation(context.Background(), parse.NewEmail("user@example.com"))
+	f := vc.Findings()
+
+	assert.True(t, c.Check(ctx, parse.NewEmail("user@example.com")).Passed)
+	mx, ok := f.MX()
+	assert.True(t, ok)
+	assert.Equal(t, "aspmx.l.google.com.", mx[0].Host)
+	assert.Equal(t, "google", f.Provider())
+	assert.Empty(t, f.NoMail())
+}
+
+func TestFindings_DNSRecordsNoMail(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []*net.MX
+		err     error
+		want    string
+	}{
+		{"nxdomain", nil, &net.DNSError{Err: "no such host", IsNotFound: true}, "NXDOMAIN"},
+		{"null MX", []*net.MX{{Host: ".", Pref: 0}}, nil, "null MX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := check.NewDNSCheckerWithLookup(check.DNSConfig{Timeout: 2 * time.Second}, func(string) ([]*net.MX, error) {
+				return tt.records, tt.err
+			})
+			ctx, vc := check.WithValidation(context.Background(), parse.NewEmail("user@example.com"))
+			f := vc.Findings()
+
+			res := c.Check(ctx, parse.NewEmail("user@example.com"))
+			assert.False(t, res.Passed)
+			assert.Equal(t, types.CodeBadDomain, res.Code)
+			assert.Equal(t, tt.want, f.NoMail())
+		})
+	}
+}
+
+func TestFindings_SMTPSkipsDeadDomain(t *testing.T) {
+	dialed := false
+	checker, cleanup := newTestSMTPChecker([]*net.MX{{Host: "mx.example.com", Pref: 10}}, func(string, string, time.Duration) (net.Conn, error) {
+		dialed = true
+		return nil, &net.OpError{Op: "dial"}
+	})
+	defer cleanup()
+	ctx, vc := check.WithValidation(context.Background(), parse.NewEmail("user@example.com"))
+	f := vc.Findings()
+	f.SetNoMail("NXDOMAIN")
+
+	res := checker.Check(ctx, parse.NewEmail("user@example.com"))
+	assert.False(t, res.Passed)
+	assert.Equal(t, types.CodeBadDomain, res.Code)
+	assert.Contains(t, res.Details, "NXDOMAIN")
+	assert.False(t, dialed)
+}
+
+func TestFindings_NilIsNoOp(t *testing.T) {
+	f := check.ValidationFrom(context.Background()).Findings()
+	f.SetMX([]*net.MX{{Host: "mx.example.com"}})
+	_, ok := f.MX()
+	assert.False(t, ok)
+	assert.Empty(t, f.Provider())
+}
@@ -0,0 +1,65 @@
+package check
+
+import (
+	"context"
+	"strings"
+
+	"github.com/optimode/emailkit/internal/freemail"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// FreeProviderConfig is the free-provider checker configuration.
+type FreeProviderConfig struct {
+	// Domains add free providers to the embedded list.
+	Domains []string
+	// Fail fails free-provider addresses instead of flagging them.
+	Fail bool
+}
+
+// FreeProviderChecker flags addresses at free consumer mailbox providers
+// (gmail.com, outlook.com, ...): the embedded list plus the configured
+// domains. A match passes with CodeFreeProvider, or fails with Fail.
+type FreeProviderChecker struct {
+	cfg   FreeProviderConfig
+	extra map[string]bool
+}
+
+// NewFreeProviderChecker creates a free-provider checker.
+func NewFreeProviderChecker(cfg FreeProviderConfig) *FreeProviderChecker {
+	c := &FreeProviderChecker{cfg: cfg, extra: make(map[string]bool, len(cfg.Domains))}
+	for _, d := range cfg.Domains {
+		c.extra[policyDomain(d)] = true
+	}
+	return c
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input).
+func (c *FreeProviderChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the free-provider level, which only consults
+// embedded and configured lists.
+func (c *FreeProviderChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelFreeProvider, Cost: CostOffline}
+}
+
+func (c *FreeProviderChecker) Check(_ context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelFreeProvider
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	domain := strings.ToLower(email.Domain)
+	if !freemail.IsFree(domain) && !c.extra[domain] {
+		return types.CheckResult{Level: level, Passed: true, Details: "not a free provider"}
+	}
+	return types.CheckResult{
+		Level:   level,
+		Passed:  !c.cfg.Fail,
+		Details: "free email provider " + domain,
+		Code:    types.CodeFreeProvider,
+	}
+}
@@ -0,0 +1,49 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestFreeProviderChecker(t *testing.T) {
+	requireDatasets(t)
+	c := check.NewFreeProviderChecker(check.FreeProviderConfig{Domains: []string{"Free.Example"}})
+	ctx := context.Background()
+
+	tests := []struct {
+		email string
+		free  bool
+	}{
+		{"jane@gmail.com", true},
+		{"jane@GMAIL.com", true},
+		{"jane@outlook.com", true},
+		{"jane@free.example", true}, // configured
+		{"jane@example.com", false},
+		{"jane@mail.gmail.com", false}, // only exact domains match
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			r := c.Check(ctx, parse.NewEmail(tt.email))
+			assert.Equal(t, types.LevelFreeProvider, r.Level)
+			assert.True(t, r.Passed)
+			if tt.free {
+				assert.Equal(t, types.CodeFreeProvider, r.Code)
+			} else {
+				assert.Empty(t, r.Code)
+			}
+		})
+	}
+
+	r := c.CheckDomain(ctx, parse.NewDomain("yahoo.com"))
+	assert.Equal(t, types.CodeFreeProvider, r.Code)
+
+	r = check.NewFreeProviderChecker(check.FreeProviderConfig{Fail: true}).Check(ctx, parse.NewEmail("jane@gmail.com"))
+	assert.False(t, r.Passed)
+	assert.Equal(t, types.CodeFreeProvider, r.Code)
+}
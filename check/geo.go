@@ -0,0 +1,245 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// GeoInfo locates an IP address.
+type GeoInfo struct {
+	Country string `json:"country,omitempty"` // ISO 3166-1 alpha-2, e.g. "DE"; "" if unknown
+	ASN     string `json:"asn,omitempty"`     // origin AS number, e.g. "15169"
+	ASName  string `json:"asName,omitempty"`  // e.g. "GOOGLE, US"
+}
+
+// GeoProvider maps IP addresses to their country and AS, e.g. a reader
+// for a GeoIP database.
+type GeoProvider interface {
+	LookupIP(ctx context.Context, ip string) (GeoInfo, error)
+}
+
+// CymruGeo is a GeoProvider backed by Team Cymru's IP-to-ASN DNS service.
+// Its country is that of the address block's registration, which is
+// usually, but not always, where the host is.
+type CymruGeo struct {
+	Resolver Resolver
+}
+
+// LookupIP implements GeoProvider.
+func (g CymruGeo) LookupIP(ctx context.Context, ip string) (GeoInfo, error) {
+	query := cymruOriginQuery(ip)
+	if query == "" {
+		return GeoInfo{}, fmt.Errorf("invalid IP address %q", ip)
+	}
+	// "15169 | 8.8.8.0/24 | US | arin | 2000-03-30"
+	txt, err := g.Resolver.LookupTXT(ctx, query)
+	if err != nil {
+		return GeoInfo{}, err
+	}
+	if len(txt) == 0 {
+		return GeoInfo{}, errors.New("no origin record")
+	}
+	fields := strings.Split(txt[0], "|")
+	// Multi-origin prefixes list several ASNs; keep the first
+	origins := strings.Fields(fields[0])
+	if len(origins) == 0 {
+		return GeoInfo{}, errors.New("no origin AS")
+	}
+	info := GeoInfo{ASN: origins[0]}
+	if len(fields) > 2 {
+		info.Country = strings.ToUpper(strings.TrimSpace(fields[2]))
+	}
+
+	// "15169 | US | arin | 2000-03-30 | GOOGLE, US"
+	txt, err = g.Resolver.LookupTXT(ctx, "AS"+info.ASN+".asn.cymru.com")
+	if err == nil && len(txt) > 0 {
+		fields := strings.Split(txt[0], "|")
+		info.ASName = strings.TrimSpace(fields[len(fields)-1])
+	}
+	return info, nil
+}
+
+// cymruOriginQuery builds the reverse-order origin query name for an IP.
+func cymruOriginQuery(addr string) string {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return ""
+	}
+	if ip.To4() != nil {
+		return reverseName(ip) + ".origin.asn.cymru.com"
+	}
+	return reverseName(ip) + ".origin6.asn.cymru.com"
+}
+
+// reverseName returns the labels of ip in reverse order, as queried under
+// reverse-lookup zones such as DNSBLs: "192.0.2.1" → "1.2.0.192", and
+// nibbles for IPv6 addresses.
+func reverseName(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d", ip4[3], ip4[2], ip4[1], ip4[0])
+	}
+	const hexDigits = "0123456789abcdef"
+	var b strings.Builder
+	for i := len(ip) - 1; i >= 0; i-- {
+		if i < len(ip)-1 {
+			b.WriteByte('.')
+		}
+		b.WriteByte(hexDigits[ip[i]&0x0f])
+		b.WriteByte('.')
+		b.WriteByte(hexDigits[ip[i]>>4])
+	}
+	return b.String()
+}
+
+// GeoConfig is the geo checker configuration.
+type GeoConfig struct {
+	Timeout time.Duration
+	// Provider locates the addresses. Default: CymruGeo on the checker's
+	// resolver
+	Provider GeoProvider
+	// MaxHosts is the number of MX hosts (by preference) located. Default: 3
+	MaxHosts int
+	// BlockCountries fails domains with a mail host in one of these
+	// countries (ISO 3166-1 alpha-2 codes, any case) with CodeGeoBlocked.
+	BlockCountries []string
+}
+
+// GeoChecker locates the mail hosts of a domain — its MX hosts, or the
+// domain itself if it has no MX records — and reports their countries and
+// ASNs in Meta. Lookup trouble never fails the check: the result passes
+// with an "unknown" detail instead.
+type GeoChecker struct {
+	cfg      GeoConfig
+	resolver Resolver
+	blocked  map[string]bool
+}
+
+// NewGeoChecker creates a geo checker using the given resolver.
+func NewGeoChecker(cfg GeoConfig, r Resolver) *GeoChecker {
+	if cfg.Provider == nil {
+		cfg.Provider = CymruGeo{Resolver: r}
+	}
+	if cfg.MaxHosts <= 0 {
+		cfg.MaxHosts = 3
+	}
+	blocked := make(map[string]bool, len(cfg.BlockCountries))
+	for _, c := range cfg.BlockCountries {
+		blocked[strings.ToUpper(strings.TrimSpace(c))] = true
+	}
+	return &GeoChecker{cfg: cfg, resolver: r, blocked: blocked}
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input).
+func (c *GeoChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the geo level, which locates the MX hosts.
+func (c *GeoChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelGeo, Cost: CostDNS, NeedsMX: true}
+}
+
+func (c *GeoChecker) Check(ctx context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelGeo
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	hosts, err := mailHosts(ctx, c.resolver, email.Domain, c.cfg.MaxHosts)
+	if err != nil {
+		return types.CheckResult{Level: level, Passed: true, Details: fmt.Sprintf("location unknown: %v", err)}
+	}
+
+	var countries, asns []string
+	var blockedAt []string
+	for _, host := range hosts {
+		addrs, err := c.resolver.LookupHost(ctx, host)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			info, err := c.cfg.Provider.LookupIP(ctx, addr)
+			if err != nil {
+				continue
+			}
+			if info.Country != "" && !slices.Contains(countries, info.Country) {
+				countries = append(countries, info.Country)
+			}
+			if info.ASN != "" && !slices.Contains(asns, info.ASN) {
+				asns = append(asns, info.ASN)
+			}
+			if c.blocked[info.Country] {
+				blockedAt = append(blockedAt, fmt.Sprintf("%s %s in %s", host, addr, info.Country))
+			}
+		}
+	}
+	if ctx.Err() != nil && len(countries) == 0 && len(asns) == 0 {
+		return types.CheckResult{Level: level, Passed: true, Details: fmt.Sprintf("location unknown: %v", ctx.Err())}
+	}
+
+	sort.Strings(countries)
+	meta := map[string]string{"hosts": strings.Join(hosts, ",")}
+	if len(countries) > 0 {
+		meta["countries"] = strings.Join(countries, ",")
+	}
+	if len(asns) > 0 {
+		meta["asns"] = strings.Join(asns, ",")
+	}
+	if len(blockedAt) > 0 {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  false,
+			Details: "mail hosts in a blocked country: " + strings.Join(blockedAt, "; "),
+			Code:    types.CodeGeoBlocked,
+			Meta:    meta,
+		}
+	}
+	if len(countries) == 0 {
+		return types.CheckResult{Level: level, Passed: true, Details: "location unknown: no country for the mail hosts", Meta: meta}
+	}
+	return types.CheckResult{
+		Level:   level,
+		Passed:  true,
+		Details: "mail hosts in " + strings.Join(countries, ", "),
+		Meta:    meta,
+	}
+}
+
+// mailHosts returns up to maxHosts MX hosts of domain by preference, or
+// the domain itself if it has no MX records. It uses the MX records the
+// DNS level recorded in the Findings of ctx, if any.
+func mailHosts(ctx context.Context, r Resolver, domain string, maxHosts int) ([]string, error) {
+	mxRecords, ok := ValidationFrom(ctx).Findings().MX()
+	if !ok {
+		var err error
+		mxRecords, err = r.LookupMX(ctx, domain)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+	}
+	if len(mxRecords) == 0 {
+		return []string{domain}, nil
+	}
+	sort.SliceStable(mxRecords, func(i, j int) bool { return mxRecords[i].Pref < mxRecords[j].Pref })
+	hosts := slices.DeleteFunc(mxHosts(mxRecords), func(h string) bool { return h == "" }) // null MX
+	if len(hosts) == 0 {
+		return nil, errors.New("domain accepts no mail (null MX)")
+	}
+	if len(hosts) > maxHosts {
+		hosts = hosts[:maxHosts]
+	}
+	return hosts, nil
+}
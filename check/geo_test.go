@@ -0,0 +1,118 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// geoTable is a GeoProvider from a static map.
+type geoTable map[string]check.GeoInfo
+
+func (g geoTable) LookupIP(_ context.Context, ip string) (check.GeoInfo, error) {
+	if info, ok := g[ip]; ok {
+		return info, nil
+	}
+	return check.GeoInfo{}, errors.New("not in table")
+}
+
+func geoResolver() *fakeResolver {
+	return &fakeResolver{
+		mx: map[string][]*net.MX{
+			"example.com":  {{Host: "mx2.example.net.", Pref: 20}, {Host: "mx1.example.net.", Pref: 10}},
+			"null.example": {{Host: ".", Pref: 0}},
+		},
+		hosts: map[string][]string{
+			"mx1.example.net": {"192.0.2.1", "2001:db8::1"},
+			"mx2.example.net": {"198.51.100.1"},
+			"nomx.example":    {"203.0.113.9"},
+		},
+	}
+}
+
+var geoIPs = geoTable{
+	"192.0.2.1":    {Country: "DE", ASN: "64500"},
+	"2001:db8::1":  {Country: "DE", ASN: "64500"},
+	"198.51.100.1": {Country: "RU", ASN: "64501"},
+	"203.0.113.9":  {Country: "US", ASN: "64502"},
+}
+
+func TestGeoChecker(t *testing.T) {
+	c := check.NewGeoChecker(check.GeoConfig{Timeout: time.Second, Provider: geoIPs}, geoResolver())
+
+	r := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.Equal(t, types.LevelGeo, r.Level)
+	assert.True(t, r.Passed)
+	assert.Equal(t, "mail hosts in DE, RU", r.Details)
+	assert.Equal(t, map[string]string{
+		"hosts":     "mx1.example.net,mx2.example.net",
+		"countries": "DE,RU",
+		"asns":      "64500,64501",
+	}, r.Meta)
+
+	// Without MX records the domain itself is located
+	r = c.Check(context.Background(), parse.NewEmail("user@nomx.example"))
+	assert.True(t, r.Passed)
+	assert.Equal(t, "US", r.Meta["countries"])
+}
+
+func TestGeoChecker_BlockCountries(t *testing.T) {
+	c := check.NewGeoChecker(check.GeoConfig{Timeout: time.Second, Provider: geoIPs, BlockCountries: []string{"ru", "KP"}}, geoResolver())
+
+	r := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.False(t, r.Passed)
+	assert.Equal(t, types.CodeGeoBlocked, r.Code)
+	assert.Equal(t, "mail hosts in a blocked country: mx2.example.net 198.51.100.1 in RU", r.Details)
+	assert.Equal(t, "DE,RU", r.Meta["countries"])
+
+	// MaxHosts leaves the backup MX out
+	c = check.NewGeoChecker(check.GeoConfig{Timeout: time.Second, Provider: geoIPs, BlockCountries: []string{"RU"}, MaxHosts: 1}, geoResolver())
+	r = c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.True(t, r.Passed)
+	assert.Equal(t, "DE", r.Meta["countries"])
+}
+
+func TestGeoChecker_Unknown(t *testing.T) {
+	c := check.NewGeoChecker(check.GeoConfig{Timeout: time.Second, Provider: geoIPs}, geoResolver())
+
+	r := c.Check(context.Background(), parse.NewEmail("user@null.example"))
+	assert.True(t, r.Passed)
+	assert.Equal(t, "location unknown: domain accepts no mail (null MX)", r.Details)
+
+	r = c.Check(context.Background(), parse.NewEmail("user@gone.example"))
+	assert.True(t, r.Passed)
+	assert.Equal(t, "location unknown: no country for the mail hosts", r.Details)
+
+	c = check.NewGeoChecker(check.GeoConfig{Timeout: time.Second, Provider: geoIPs},
+		&fakeResolver{err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}})
+	r = c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.True(t, r.Passed)
+	assert.Contains(t, r.Details, "location unknown: ")
+
+	r = c.Check(context.Background(), parse.NewEmail("invalid"))
+	assert.False(t, r.Passed)
+}
+
+func TestCymruGeo(t *testing.T) {
+	r := &fakeResolver{txt: map[string][]string{
+		"1.2.0.192.origin.asn.cymru.com": {"64500 64511 | 192.0.2.0/24 | de | ripencc | 2001-01-01"},
+		"AS64500.asn.cymru.com":          {"64500 | DE | ripencc | 2001-01-01 | EXAMPLE-AS, DE"},
+	}}
+	info, err := check.CymruGeo{Resolver: r}.LookupIP(context.Background(), "192.0.2.1")
+	require.NoError(t, err)
+	assert.Equal(t, check.GeoInfo{Country: "DE", ASN: "64500", ASName: "EXAMPLE-AS, DE"}, info)
+
+	_, err = check.CymruGeo{Resolver: r}.LookupIP(context.Background(), "198.51.100.1")
+	assert.Error(t, err)
+	_, err = check.CymruGeo{Resolver: r}.LookupIP(context.Background(), "not-an-ip")
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// DefaultGreylistWindow is how long a greylisting MX host is left alone
+// before the recipient is probed again. Typical greylisters accept
+// retries after 1 to 5 minutes.
+const DefaultGreylistWindow = 5 * time.Minute
+
+// GreylistStore persists when an MX host first greylisted a recipient, so
+// that retries in later batches, or after a process restart, can wait out
+// the greylisting window instead of sleeping in-call. Implementations must
+// be safe for concurrent use.
+type GreylistStore interface {
+	// FirstGreylisted returns when key was first greylisted; ok is false
+	// if it never was (or the entry expired).
+	FirstGreylisted(ctx context.Context, key string) (at time.Time, ok bool, err error)
+	// MarkGreylisted records at unless an earlier timestamp is stored.
+	MarkGreylisted(ctx context.Context, key string, at time.Time) error
+	// Clear forgets key once the recipient got a definitive answer.
+	Clear(ctx context.Context, key string) error
+}
+
+// greylistKey identifies the greylisting triplet. The client IP and the
+// sender are fixed per checker, so MX host and recipient are enough:
+// "mxhost|rcpt", with the local part of rcpt hashed by HashLocal.
+func (c *SMTPChecker) greylistKey(mxHost string, email parse.Email) string {
+	rcpt := email.Raw
+	if c.cfg.HashLocal != nil {
+		rcpt = c.cfg.HashLocal(email.Local) + "@" + email.Domain
+	}
+	return strings.ToLower(mxHost) + "|" + rcpt
+}
+
+// greylistPending reports whether key is inside its greylisting window,
+// returning the first attempt time. Store errors count as "not pending".
+func (c *SMTPChecker) greylistPending(ctx context.Context, key string) (time.Time, bool) {
+	if c.cfg.Greylist == nil {
+		return time.Time{}, false
+	}
+	first, ok, err := c.cfg.Greylist.FirstGreylisted(ctx, key)
+	if err != nil || !ok {
+		return time.Time{}, false
+	}
+	return first, time.Now().Before(first.Add(c.cfg.GreylistWindow))
+}
+
+// markGreylisted stores the first attempt and returns its time.
+func (c *SMTPChecker) markGreylisted(ctx context.Context, key string) time.Time {
+	now := time.Now()
+	if c.cfg.Greylist == nil {
+		return now
+	}
+	_ = c.cfg.Greylist.MarkGreylisted(ctx, key, now)
+	if first, ok, err := c.cfg.Greylist.FirstGreylisted(ctx, key); err == nil && ok {
+		return first
+	}
+	return now
+}
+
+func (c *SMTPChecker) clearGreylist(ctx context.Context, key string) {
+	if c.cfg.Greylist != nil {
+		_ = c.cfg.Greylist.Clear(ctx, key)
+	}
+}
+
+// greylistedResult describes a greylisted recipient. With a store, Meta
+// carries the first attempt and the earliest useful retry.
+func (c *SMTPChecker) greylistedResult(mxHost string, code int, first time.Time) types.CheckResult {
+	res := types.CheckResult{
+		Level:    types.LevelSMTP,
+		Passed:   false,
+		Details:  "greylisted by " + mxHost,
+		MXHost:   mxHost,
+		SMTPCode: code,
+		Code:     types.CodeGreylisted,
+	}
+	if c.cfg.Greylist != nil {
+		retry := first.Add(c.cfg.GreylistWindow)
+		res.Details = fmt.Sprintf("greylisted by %s, retry after %s", mxHost, retry.UTC().Format(time.RFC3339))
+		res.Meta = map[string]string{
+			"greylisted_since": first.UTC().Format(time.RFC3339),
+			"retry_after":      retry.UTC().Format(time.RFC3339),
+		}
+	}
+	return res
+}
@@ -0,0 +1,110 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/greylist"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// greylistingDialer answers RCPT TO with a greylisting reply until accept
+// is set, counting the RCPT commands it receives.
+func greylistingDialer(accept *atomic.Bool, rcpts *atomic.Int32) func(string, string, time.Duration) (net.Conn, error) {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		rcpt := "450 4.2.0 <test@example.com>: Recipient address rejected: Greylisted, see https://postgrey.schweikert.ch/"
+		if accept.Load() {
+			rcpt = "250 OK"
+		}
+		responses := map[string]string{
+			"EHLO": "250 OK", "MAIL FROM": "250 OK", "RSET": "250 OK",
+			"RCPT TO": rcpt,
+		}
+		go testSMTPServer(&countingConn{Conn: server, rcpts: rcpts}, "220 smtp.example.com ESMTP", responses)
+		return client, nil
+	}
+}
+
+// countingConn counts RCPT TO commands read by the fake server.
+type countingConn struct {
+	net.Conn
+	rcpts *atomic.Int32
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n >= 7 && string(b[:7]) == "RCPT TO" {
+		c.rcpts.Add(1)
+	}
+	return n, err
+}
+
+func TestSMTPChecker_Greylisted(t *testing.T) {
+	var accept atomic.Bool
+	var rcpts atomic.Int32
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	c, cleanup := newTestSMTPChecker(mxRecords, greylistingDialer(&accept, &rcpts))
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, types.CodeGreylisted, result.Code)
+	assert.Equal(t, 450, result.SMTPCode)
+	assert.Equal(t, "greylisted by mx.example.com", result.Details)
+	assert.Nil(t, result.Meta)
+}
+
+func TestSMTPChecker_GreylistStore(t *testing.T) {
+	var accept atomic.Bool
+	var rcpts atomic.Int32
+	store := greylist.NewMemoryStore(0)
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+		Greylist:   store,
+	}
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, greylistingDialer(&accept, &rcpts))
+	defer cleanup()
+	ctx := context.Background()
+	email := parse.NewEmail("test@example.com")
+
+	// First attempt: greylisted, timestamp stored
+	result := c.Check(ctx, email)
+	assert.Equal(t, types.CodeGreylisted, result.Code)
+	assert.Contains(t, result.Details, "retry after")
+	assert.NotEmpty(t, result.Meta["greylisted_since"])
+	assert.NotEmpty(t, result.Meta["retry_after"])
+	assert.Equal(t, int32(1), rcpts.Load())
+
+	// Inside the window the host is not probed again
+	result = c.Check(ctx, email)
+	assert.Equal(t, types.CodeGreylisted, result.Code)
+	assert.Equal(t, int32(1), rcpts.Load())
+
+	// Window elapsed (as if this were a later batch): probe, accept, clear
+	assert.NoError(t, store.Clear(ctx, "mx.example.com|test@example.com"))
+	assert.NoError(t, store.MarkGreylisted(ctx, "mx.example.com|test@example.com", time.Now().Add(-10*time.Minute)))
+	accept.Store(true)
+	cleanup() // drop the pooled greylisting connection
+
+	c, cleanup = newTestSMTPCheckerWithConfig(cfg, mxRecords, greylistingDialer(&accept, &rcpts))
+	defer cleanup()
+	result = c.Check(ctx, email)
+	assert.True(t, result.Passed)
+	assert.Equal(t, int32(2), rcpts.Load())
+
+	_, ok, err := store.FirstGreylisted(ctx, "mx.example.com|test@example.com")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,174 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/optimode/emailkit/internal/spf"
+)
+
+// SMTPIdentity is a HELO name and MAIL FROM address pair the SMTP level
+// presents to MX hosts.
+type SMTPIdentity struct {
+	HeloDomain string `json:"heloDomain"`
+	MailFrom   string `json:"mailFrom"`
+}
+
+// IdentityHealth is the verification outcome of one SMTPIdentity.
+type IdentityHealth struct {
+	Identity SMTPIdentity `json:"identity"`
+	Healthy  bool         `json:"healthy"`
+	ProbeIP  string       `json:"probeIP,omitempty"` // "" if it could not be determined
+	SPF      string       `json:"spf,omitempty"`     // SPF result of the MAIL FROM domain for ProbeIP
+	Problems []string     `json:"problems,omitempty"`
+	Checked  time.Time    `json:"checked"`
+}
+
+// IdentityMonitorConfig configures an IdentityMonitor.
+type IdentityMonitorConfig struct {
+	Identities []SMTPIdentity
+	// ProbeIP is the address MX hosts see probes come from. If nil, the
+	// address of the outbound interface is used; behind NAT set it explicitly.
+	ProbeIP  net.IP
+	Resolver Resolver      // default: net.DefaultResolver
+	Interval time.Duration // how long a verification is trusted (default: 1h)
+	Timeout  time.Duration // per verification round (default: 10s)
+	// Now is injectable for testing. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// IdentityMonitor verifies that probing identities are fit for use: the
+// HELO name resolves to the probe IP, the MAIL FROM domain resolves, and
+// its SPF record authorizes the probe IP. Receivers penalize identities
+// failing these checks, which drags down the probe acceptance rate.
+type IdentityMonitor struct {
+	cfg IdentityMonitorConfig
+
+	mu      sync.Mutex
+	health  []IdentityHealth
+	checked time.Time
+}
+
+// NewIdentityMonitor creates an IdentityMonitor. Nothing is verified
+// until Verify or Healthy is called.
+func NewIdentityMonitor(cfg IdentityMonitorConfig) *IdentityMonitor {
+	if cfg.Resolver == nil {
+		cfg.Resolver = net.DefaultResolver
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &IdentityMonitor{cfg: cfg}
+}
+
+// Verify checks every identity now and returns the outcome in
+// configuration order.
+func (m *IdentityMonitor) Verify(ctx context.Context) []IdentityHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verify(ctx)
+	return slices.Clone(m.health)
+}
+
+// Healthy returns the identities that passed verification, verifying
+// first if the last round is older than Interval. next is when the
+// current verdict expires.
+func (m *IdentityMonitor) Healthy(ctx context.Context) (ids []SMTPIdentity, next time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.health == nil || m.cfg.Now().Sub(m.checked) >= m.cfg.Interval {
+		m.verify(ctx)
+	}
+	for _, h := range m.health {
+		if h.Healthy {
+			ids = append(ids, h.Identity)
+		}
+	}
+	return ids, m.checked.Add(m.cfg.Interval)
+}
+
+// verify runs one verification round. m.mu must be held.
+func (m *IdentityMonitor) verify(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+
+	ip := m.cfg.ProbeIP
+	if ip == nil {
+		ip = outboundIP()
+	}
+	now := m.cfg.Now()
+	health := make([]IdentityHealth, len(m.cfg.Identities))
+	for i, id := range m.cfg.Identities {
+		health[i] = m.verifyIdentity(ctx, id, ip)
+		health[i].Checked = now
+	}
+	m.health, m.checked = health, now
+}
+
+func (m *IdentityMonitor) verifyIdentity(ctx context.Context, id SMTPIdentity, ip net.IP) IdentityHealth {
+	h := IdentityHealth{Identity: id}
+	if ip != nil {
+		h.ProbeIP = ip.String()
+	}
+	r := m.cfg.Resolver
+
+	// The HELO name should resolve, and to the address probes come from
+	addrs, err := r.LookupHost(ctx, id.HeloDomain)
+	switch {
+	case err != nil || len(addrs) == 0:
+		h.Problems = append(h.Problems, fmt.Sprintf("HELO name %s does not resolve", id.HeloDomain))
+	case ip != nil && !slices.ContainsFunc(addrs, func(a string) bool { return ip.Equal(net.ParseIP(a)) }):
+		h.Problems = append(h.Problems, fmt.Sprintf("HELO name %s does not resolve to probe IP %s", id.HeloDomain, ip))
+	}
+
+	// Receivers verify that the MAIL FROM domain can take bounces
+	at := strings.LastIndex(id.MailFrom, "@")
+	domain := strings.ToLower(id.MailFrom[at+1:])
+	mxs, mxErr := r.LookupMX(ctx, domain)
+	if mxErr != nil || len(mxs) == 0 {
+		if hosts, err := r.LookupHost(ctx, domain); err != nil || len(hosts) == 0 {
+			h.Problems = append(h.Problems, fmt.Sprintf("MAIL FROM domain %s does not resolve", domain))
+		}
+	}
+
+	// SPF of the MAIL FROM domain must authorize the probe IP
+	if ip != nil {
+		res, err := spf.Check(ctx, r, ip, domain, id.MailFrom)
+		h.SPF = string(res)
+		if res != spf.Pass {
+			problem := fmt.Sprintf("SPF %s for %s on %s", res, ip, domain)
+			if err != nil {
+				problem += ": " + err.Error()
+			}
+			h.Problems = append(h.Problems, problem)
+		}
+	}
+
+	h.Healthy = len(h.Problems) == 0
+	return h
+}
+
+// outboundIP returns the local address of the default route, or nil.
+// A UDP "connection" sends no packets; it only selects the interface.
+func outboundIP() net.IP {
+	conn, err := net.Dial("udp", "198.41.0.4:53")
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = conn.Close() }()
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+		return addr.IP
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// identityResolver publishes probe.test.com (healthy for 192.0.2.10) and
+// bad.test.com (SPF does not cover the probe IP, HELO resolves elsewhere).
+func identityResolver() *fakeResolver {
+	return &fakeResolver{
+		mx: map[string][]*net.MX{
+			"probe.test.com": {{Host: "mx.probe.test.com.", Pref: 10}},
+		},
+		hosts: map[string][]string{
+			"helo.probe.test.com": {"192.0.2.10"},
+			"helo.bad.test.com":   {"198.51.100.1"},
+			"bad.test.com":        {"198.51.100.1"},
+		},
+		txt: map[string][]string{
+			"probe.test.com": {"v=spf1 ip4:192.0.2.0/24 -all"},
+			"bad.test.com":   {"v=spf1 ip4:198.51.100.0/24 -all"},
+		},
+	}
+}
+
+var (
+	goodIdentity = check.SMTPIdentity{HeloDomain: "helo.probe.test.com", MailFrom: "verify@probe.test.com"}
+	badIdentity  = check.SMTPIdentity{HeloDomain: "helo.bad.test.com", MailFrom: "verify@bad.test.com"}
+)
+
+func TestIdentityMonitor_Verify(t *testing.T) {
+	m := check.NewIdentityMonitor(check.IdentityMonitorConfig{
+		Identities: []check.SMTPIdentity{goodIdentity, badIdentity},
+		ProbeIP:    net.ParseIP("192.0.2.10"),
+		Resolver:   identityResolver(),
+	})
+
+	health := m.Verify(context.Background())
+	require.Len(t, health, 2)
+
+	assert.True(t, health[0].Healthy)
+	assert.Equal(t, "pass", health[0].SPF)
+	assert.Equal(t, "192.0.2.10", health[0].ProbeIP)
+	assert.Empty(t, health[0].Problems)
+
+	assert.False(t, health[1].Healthy)
+	assert.Equal(t, "fail", health[1].SPF)
+	assert.Equal(t, []string{
+		"HELO name helo.bad.test.com does not resolve to probe IP 192.0.2.10",
+		"SPF fail for 192.0.2.10 on bad.test.com",
+	}, health[1].Problems)
+}
+
+func TestIdentityMonitor_Unresolvable(t *testing.T) {
+	m := check.NewIdentityMonitor(check.IdentityMonitorConfig{
+		Identities: []check.SMTPIdentity{{HeloDomain: "nowhere.test", MailFrom: "verify@nowhere.test"}},
+		ProbeIP:    net.ParseIP("192.0.2.10"),
+		Resolver:   identityResolver(),
+	})
+
+	health := m.Verify(context.Background())
+	require.Len(t, health, 1)
+	assert.False(t, health[0].Healthy)
+	assert.Equal(t, "none", health[0].SPF)
+	assert.Contains(t, health[0].Problems, "HELO name nowhere.test does not resolve")
+	assert.Contains(t, health[0].Problems, "MAIL FROM domain nowhere.test does not resolve")
+}
+
+func TestIdentityMonitor_HealthyRechecksAfterInterval(t *testing.T) {
+	r := identityResolver()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	m := check.NewIdentityMonitor(check.IdentityMonitorConfig{
+		Identities: []check.SMTPIdentity{badIdentity},
+		ProbeIP:    net.ParseIP("192.0.2.10"),
+		Resolver:   r,
+		Interval:   time.Hour,
+		Now:        func() time.Time { return now },
+	})
+
+	ids, next := m.Healthy(context.Background())
+	assert.Empty(t, ids)
+	assert.Equal(t, now.Add(time.Hour), next)
+
+	// The operator fixes DNS; the verdict is kept until the interval passes
+	r.hosts["helo.bad.test.com"] = []string{"192.0.2.10"}
+	r.txt["bad.test.com"] = []string{"v=spf1 ip4:192.0.2.10 -all"}
+	ids, _ = m.Healthy(context.Background())
+	assert.Empty(t, ids)
+
+	now = now.Add(time.Hour)
+	ids, _ = m.Healthy(context.Background())
+	assert.Equal(t, []check.SMTPIdentity{badIdentity}, ids)
+}
+
+// recordingDial returns a dialer whose fake servers accept everything and
+// record the EHLO and MAIL FROM commands they receive.
+func recordingDial(mu *sync.Mutex, commands *[]string) func(string, string, time.Duration) (net.Conn, error) {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer func() { _ = server.Close() }()
+			_, _ = fmt.Fprintf(server, "220 mx.example.com ESMTP\r\n")
+			buf := make([]byte, 4096)
+			for {
+				n, err := server.Read(buf)
+				if err != nil {
+					return
+				}
+				cmd := strings.TrimSpace(string(buf[:n]))
+				if strings.HasPrefix(cmd, "EHLO") || strings.HasPrefix(cmd, "MAIL FROM") {
+					mu.Lock()
+					*commands = append(*commands, cmd)
+					mu.Unlock()
+				}
+				if cmd == "QUIT" {
+					return
+				}
+				_, _ = fmt.Fprintf(server, "250 OK\r\n")
+			}
+		}()
+		return client, nil
+	}
+}
+
+func TestSMTPChecker_IdentityRotation(t *testing.T) {
+	var mu sync.Mutex
+	var commands []string
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+		Identities: []check.SMTPIdentity{goodIdentity},
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, recordingDial(&mu, &commands))
+	defer cleanup()
+
+	for _, addr := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		result := c.Check(context.Background(), parse.NewEmail(addr))
+		assert.True(t, result.Passed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"EHLO test.com",
+		"MAIL FROM:<verify@test.com>",
+		"EHLO helo.probe.test.com",
+		"MAIL FROM:<verify@probe.test.com>",
+		"MAIL FROM:<verify@test.com>", // reused connection: no second EHLO
+	}, commands)
+}
+
+func TestSMTPChecker_IdentityMonitorSkipsUnhealthy(t *testing.T) {
+	var mu sync.Mutex
+	var commands []string
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	cfg := check.SMTPConfig{
+		HeloDomain: badIdentity.HeloDomain,
+		MailFrom:   badIdentity.MailFrom,
+		MaxMXHosts: 1,
+		Identities: []check.SMTPIdentity{goodIdentity},
+		IdentityMonitor: check.NewIdentityMonitor(check.IdentityMonitorConfig{
+			Identities: []check.SMTPIdentity{badIdentity, goodIdentity},
+			ProbeIP:    net.ParseIP("192.0.2.10"),
+			Resolver:   identityResolver(),
+		}),
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, recordingDial(&mu, &commands))
+	defer cleanup()
+
+	for _, addr := range []string{"a@example.com", "b@example.com"} {
+		result := c.Check(context.Background(), parse.NewEmail(addr))
+		assert.True(t, result.Passed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.NotContains(t, commands, "MAIL FROM:<verify@bad.test.com>")
+	assert.Contains(t, commands, "MAIL FROM:<verify@probe.test.com>")
+}
+
+func TestSMTPChecker_NoHealthyIdentity(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	cfg := check.SMTPConfig{
+		HeloDomain: badIdentity.HeloDomain,
+		MailFrom:   badIdentity.MailFrom,
+		MaxMXHosts: 1,
+		IdentityMonitor: check.NewIdentityMonitor(check.IdentityMonitorConfig{
+			Identities: []check.SMTPIdentity{badIdentity},
+			ProbeIP:    net.ParseIP("192.0.2.10"),
+			Resolver:   identityResolver(),
+		}),
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return nil, fmt.Errorf("should not be called")
+	})
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("a@example.com"))
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, types.CodeDeferred, result.Code)
+	assert.Equal(t, "SMTP probe deferred: no healthy sender identity", result.Details)
+	assert.NotEmpty(t, result.Meta["resume_at"])
+}
+
+func TestSMTPChecker_IdentityPerWorker(t *testing.T) {
+	var mu sync.Mutex
+	var commands []string
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	cfg := check.SMTPConfig{
+		HeloDomain:        "test.com",
+		MailFrom:          "verify@test.com",
+		MaxMXHosts:        1,
+		Identities:        []check.SMTPIdentity{goodIdentity},
+		IdentityPerWorker: true,
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, recordingDial(&mu, &commands))
+	defer cleanup()
+
+	ctx := check.WithWorker(context.Background(), 3) // 3 mod 2 identities
+	for _, addr := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		result := c.Check(ctx, parse.NewEmail(addr))
+		assert.True(t, result.Passed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"EHLO helo.probe.test.com",
+		"MAIL FROM:<verify@probe.test.com>",
+		"MAIL FROM:<verify@probe.test.com>",
+		"MAIL FROM:<verify@probe.test.com>",
+	}, commands)
+}
+
+func TestSMTPChecker_IdentityStats(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	cfg := check.SMTPConfig{
+		HeloDomain:        "test.com",
+		MailFrom:          "verify@test.com",
+		MaxMXHosts:        1,
+		Identities:        []check.SMTPIdentity{goodIdentity},
+		IdentityPerWorker: true,
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer func() { _ = server.Close() }()
+			_, _ = fmt.Fprintf(server, "220 mx.example.com ESMTP\r\n")
+			buf := make([]byte, 4096)
+			for {
+				n, err := server.Read(buf)
+				if err != nil {
+					return
+				}
+				cmd := strings.TrimSpace(string(buf[:n]))
+				switch {
+				case cmd == "QUIT":
+					return
+				case strings.HasPrefix(cmd, "RCPT TO:<busy@"):
+					_, _ = fmt.Fprintf(server, "421 4.7.0 Try again later, closing connection\r\n")
+				case strings.HasPrefix(cmd, "RCPT TO:<gone@"):
+					_, _ = fmt.Fprintf(server, "550 5.1.1 User unknown\r\n")
+				default:
+					_, _ = fmt.Fprintf(server, "250 OK\r\n")
+				}
+			}
+		}()
+		return client, nil
+	})
+	defer cleanup()
+
+	primary, second := check.WithWorker(context.Background(), 0), check.WithWorker(context.Background(), 1)
+	c.Check(primary, parse.NewEmail("jane@example.com"))
+	c.Check(primary, parse.NewEmail("gone@example.com"))
+	c.Check(second, parse.NewEmail("busy@example.com"))
+
+	stats := c.IdentityStats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "verify@test.com", stats[0].Identity.MailFrom)
+	assert.Equal(t, int64(2), stats[0].Probes)
+	assert.Equal(t, int64(1), stats[0].Accepted)
+	assert.Equal(t, int64(1), stats[0].Rejected)
+	assert.Zero(t, stats[0].Throttled)
+
+	assert.Equal(t, goodIdentity, stats[1].Identity)
+	assert.Equal(t, int64(1), stats[1].Probes)
+	assert.Equal(t, int64(1), stats[1].Failed)
+	assert.Equal(t, int64(1), stats[1].Throttled)
+	assert.False(t, stats[1].LastThrottled.IsZero())
+}
@@ -0,0 +1,147 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// NSConfig is the NS checker configuration.
+type NSConfig struct {
+	Timeout time.Duration
+	// ProbeNameservers when true requires at least one delegated
+	// nameserver to answer a direct query.
+	ProbeNameservers bool
+	// DetectParking when true marks a passing result CodeParked if the
+	// domain looks parked: parking-service nameservers or MX hosts, or
+	// addresses in a parking service's range.
+	DetectParking bool
+	// Probe is injectable for testing. Defaults to a UDP query sent
+	// directly to the nameserver.
+	Probe func(ctx context.Context, nameserver, domain string) error
+}
+
+// NSChecker verifies that the registrable domain is delegated (has NS records)
+// and optionally that at least one of its nameservers responds.
+// Its failure details distinguish an unregistered domain from a registered
+// domain whose nameservers are broken; a registered domain without MX records
+// passes this level and fails the DNS level instead.
+type NSChecker struct {
+	cfg      NSConfig
+	resolver Resolver
+}
+
+// NewNSChecker creates an NS checker using the given resolver.
+func NewNSChecker(cfg NSConfig, r Resolver) *NSChecker {
+	if cfg.Probe == nil {
+		cfg.Probe = probeNameserver
+	}
+	return &NSChecker{cfg: cfg, resolver: r}
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input).
+func (c *NSChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the NS level, which makes DNS lookups and probes
+// the nameservers.
+func (c *NSChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelNS, Cost: CostDNS}
+}
+
+func (c *NSChecker) Check(ctx context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelNS
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	zone := registrableDomain(email.Domain)
+	nsRecords, err := c.resolver.LookupNS(ctx, zone)
+	if err != nil {
+		if isNotFound(err) {
+			return types.CheckResult{
+				Level:   level,
+				Passed:  false,
+				Details: fmt.Sprintf("domain not registered: %s has no NS delegation", zone),
+			}
+		}
+		return types.CheckResult{
+			Level:   level,
+			Passed:  false,
+			Details: fmt.Sprintf("NS lookup failed: %v", err),
+		}
+	}
+	if len(nsRecords) == 0 {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  false,
+			Details: fmt.Sprintf("domain not registered: %s has no NS delegation", zone),
+		}
+	}
+
+	if !c.cfg.ProbeNameservers {
+		return c.checkParking(ctx, email.Domain, nsRecords, types.CheckResult{
+			Level:   level,
+			Passed:  true,
+			Details: fmt.Sprintf("%d nameserver(s) delegated", len(nsRecords)),
+		})
+	}
+
+	for _, ns := range nsRecords {
+		host := strings.TrimSuffix(ns.Host, ".")
+		if err := c.cfg.Probe(ctx, host, zone); err == nil {
+			return c.checkParking(ctx, email.Domain, nsRecords, types.CheckResult{
+				Level:   level,
+				Passed:  true,
+				Details: fmt.Sprintf("%d nameserver(s) delegated, %s responding", len(nsRecords), host),
+			})
+		}
+	}
+
+	return types.CheckResult{
+		Level:   level,
+		Passed:  false,
+		Details: fmt.Sprintf("domain registered but none of its %d nameserver(s) responded", len(nsRecords)),
+	}
+}
+
+// checkParking marks a passing result CodeParked if DetectParking is set
+// and the domain looks parked. Parked domains resolve and are delegated,
+// but don't receive mail.
+func (c *NSChecker) checkParking(ctx context.Context, domain string, nsRecords []*net.NS, result types.CheckResult) types.CheckResult {
+	if !c.cfg.DetectParking {
+		return result
+	}
+	if reason := parkedReason(ctx, c.resolver, domain, nsRecords); reason != "" {
+		result.Code = types.CodeParked
+		result.Details = "domain appears parked: " + reason
+	}
+	return result
+}
+
+// probeNameserver sends an NS query for the domain directly to the nameserver.
+// Any DNS answer counts as a response, including NXDOMAIN.
+func probeNameserver(ctx context.Context, nameserver, domain string) error {
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, net.JoinHostPort(nameserver, "53"))
+		},
+	}
+	_, err := r.LookupNS(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
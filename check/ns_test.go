@@ -0,0 +1,68 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestNSChecker(t *testing.T) {
+	r := &fakeResolver{ns: map[string][]*net.NS{
+		"example.com":   {{Host: "ns1.example.com."}, {Host: "ns2.example.com."}},
+		"example.co.uk": {{Host: "ns1.example.co.uk."}},
+	}}
+
+	tests := []struct {
+		name        string
+		email       string
+		probe       bool
+		probeErr    error
+		wantOK      bool
+		wantDetails string
+	}{
+		{"delegated", "user@example.com", false, nil, true, "2 nameserver(s) delegated"},
+		{"subdomain uses registrable domain", "user@mail.example.co.uk", false, nil, true, "1 nameserver(s) delegated"},
+		{"unregistered", "user@unregistered.com", false, nil, false, "domain not registered"},
+		{"nameserver responds", "user@example.com", true, nil, true, "ns1.example.com responding"},
+		{"no nameserver responds", "user@example.com", true, errors.New("timeout"), false, "none of its 2 nameserver(s) responded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := check.NewNSChecker(check.NSConfig{
+				Timeout:          2 * time.Second,
+				ProbeNameservers: tt.probe,
+				Probe: func(_ context.Context, _, _ string) error {
+					return tt.probeErr
+				},
+			}, r)
+			result := c.Check(context.Background(), parse.NewEmail(tt.email))
+			assert.Equal(t, types.LevelNS, result.Level)
+			assert.Equal(t, tt.wantOK, result.Passed)
+			assert.Contains(t, result.Details, tt.wantDetails)
+		})
+	}
+}
+
+func TestNSChecker_LookupError(t *testing.T) {
+	r := &fakeResolver{err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}}
+	c := check.NewNSChecker(check.NSConfig{Timeout: 2 * time.Second}, r)
+	result := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.False(t, result.Passed)
+	assert.Contains(t, result.Details, "NS lookup failed")
+}
+
+func TestNSChecker_InvalidEmail(t *testing.T) {
+	c := check.NewNSChecker(check.NSConfig{Timeout: 2 * time.Second}, &fakeResolver{})
+	result := c.Check(context.Background(), parse.NewEmail("invalid"))
+	assert.False(t, result.Passed)
+	assert.Contains(t, result.Details, "skipped")
+}
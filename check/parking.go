@@ -0,0 +1,98 @@
+package check
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+// parkingHostSuffixes are nameserver and MX host suffixes of domain
+// parking services. A domain delegated to or receiving mail at one of
+// these is for sale or monetized, not used for mail.
+var parkingHostSuffixes = []string{
+	"sedoparking.com",
+	"parkingcrew.net",
+	"bodis.com",
+	"above.com",
+	"parklogic.com",
+	"dan.com",
+	"afternic.com",
+	"hugedomains.com",
+	"uniregistrymarket.link",
+	"domainmarket.com",
+	"parked.com",
+}
+
+// parkingNetworks are address ranges that parking services point parked
+// domains (usually with a wildcard) at.
+var parkingNetworks = mustParseCIDRs(
+	"91.195.240.0/23",  // Sedo
+	"64.190.62.0/23",   // Sedo
+	"185.53.176.0/22",  // ParkingCrew
+	"199.59.240.0/22",  // Bodis
+	"103.224.182.0/24", // Above
+	"103.224.212.0/24", // Above
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
+
+// parkingHost reports whether host belongs to a parking service.
+func parkingHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, s := range parkingHostSuffixes {
+		if host == s || strings.HasSuffix(host, "."+s) {
+			return true
+		}
+	}
+	return false
+}
+
+// parkingIP reports whether addr is in a parking service's range.
+func parkingIP(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range parkingNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parkedReason returns why domain looks parked, or "" if it does not.
+// nsRecords are the registrable domain's nameservers. The MX and address
+// lookups are best effort: errors just mean no evidence.
+func parkedReason(ctx context.Context, r Resolver, domain string, nsRecords []*net.NS) string {
+	for _, ns := range nsRecords {
+		if parkingHost(ns.Host) {
+			return "nameserver " + strings.TrimSuffix(ns.Host, ".") + " belongs to a parking service"
+		}
+	}
+	if mxs, err := r.LookupMX(ctx, domain); err == nil {
+		for _, mx := range mxs {
+			if parkingHost(mx.Host) {
+				return "MX " + strings.TrimSuffix(mx.Host, ".") + " belongs to a parking service"
+			}
+		}
+	}
+	if addrs, err := r.LookupHost(ctx, domain); err == nil {
+		for _, a := range addrs {
+			if parkingIP(a) {
+				return "address " + a + " belongs to a parking service"
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestNSChecker_DetectParking(t *testing.T) {
+	r := &fakeResolver{
+		ns: map[string][]*net.NS{
+			"example.com":   {{Host: "ns1.example.com."}},
+			"parked-ns.com": {{Host: "ns1.sedoparking.com."}, {Host: "ns2.sedoparking.com."}},
+			"parked-mx.com": {{Host: "ns1.parked-mx.com."}},
+			"parked-a.com":  {{Host: "ns1.parked-a.com."}},
+		},
+		mx: map[string][]*net.MX{
+			"example.com":   {{Host: "mx.example.com.", Pref: 10}},
+			"parked-mx.com": {{Host: "mx76.m1bp.com.", Pref: 10}, {Host: "park-mx.above.com.", Pref: 20}},
+		},
+		hosts: map[string][]string{
+			"example.com":       {"93.184.216.34"},
+			"mail.parked-a.com": {"185.53.178.7"}, // wildcard to a ParkingCrew address
+		},
+	}
+
+	tests := []struct {
+		name        string
+		email       string
+		detect      bool
+		wantCode    types.CheckCode
+		wantDetails string
+	}{
+		{"not parked", "user@example.com", true, "", "1 nameserver(s) delegated"},
+		{"parking nameservers", "user@parked-ns.com", true, types.CodeParked, "nameserver ns1.sedoparking.com belongs to a parking service"},
+		{"parking MX", "user@parked-mx.com", true, types.CodeParked, "MX park-mx.above.com belongs to a parking service"},
+		{"parking address", "user@mail.parked-a.com", true, types.CodeParked, "address 185.53.178.7 belongs to a parking service"},
+		{"detection off", "user@parked-ns.com", false, "", "2 nameserver(s) delegated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := check.NewNSChecker(check.NSConfig{Timeout: 2 * time.Second, DetectParking: tt.detect}, r)
+			result := c.Check(context.Background(), parse.NewEmail(tt.email))
+			assert.True(t, result.Passed, "parked domains pass, flagged by code")
+			assert.Equal(t, tt.wantCode, result.Code)
+			assert.Contains(t, result.Details, tt.wantDetails)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package check
+
+import (
+	"fmt"
+	"strings"
+)
+
+// localPartRule validates a local part against a provider's account naming
+// rules. The local part is lowercased and stripped of any subaddress tag.
+// Returns error text, or "" if ok.
+type localPartRule func(local string) string
+
+// providerRules maps known provider domains to their local-part rules.
+// Addresses failing these rules are RFC-valid but cannot exist at the provider.
+var providerRules = map[string]localPartRule{
+	"gmail.com":      gmailRule,
+	"googlemail.com": gmailRule,
+
+	"outlook.com":   outlookRule,
+	"hotmail.com":   outlookRule,
+	"hotmail.co.uk": outlookRule,
+	"live.com":      outlookRule,
+	"msn.com":       outlookRule,
+
+	"yahoo.com":      yahooRule,
+	"yahoo.co.uk":    yahooRule,
+	"yahoo.fr":       yahooRule,
+	"yahoo.de":       yahooRule,
+	"ymail.com":      yahooRule,
+	"rocketmail.com": yahooRule,
+}
+
+// checkProviderRules applies the provider's local-part rules, if the domain
+// is a known provider. Returns error text, or "" if ok or unknown provider.
+func checkProviderRules(domain, local string) string {
+	rule, ok := providerRules[domain]
+	if !ok {
+		return ""
+	}
+	local = strings.ToLower(local)
+	// Subaddress tags (user+tag) are delivered to the base account
+	if i := strings.IndexByte(local, '+'); i >= 0 {
+		local = local[:i]
+	}
+	if err := rule(local); err != "" {
+		return fmt.Sprintf("local part not valid at %s: %s", domain, err)
+	}
+	return ""
+}
+
+// gmailRule: 6–30 characters; letters, digits and dots only;
+// no leading, trailing or consecutive dots. Dots don't count towards the minimum.
+func gmailRule(local string) string {
+	if err := onlyChars(local, "."); err != "" {
+		return err
+	}
+	if err := dotPlacement(local); err != "" {
+		return err
+	}
+	if len(strings.ReplaceAll(local, ".", "")) < 6 || len(local) > 30 {
+		return "must be 6-30 characters"
+	}
+	return ""
+}
+
+// outlookRule: up to 64 characters; letters, digits, dots, underscores and
+// hyphens; must start with a letter; no trailing or consecutive dots.
+func outlookRule(local string) string {
+	if err := onlyChars(local, "._-"); err != "" {
+		return err
+	}
+	if !startsWithLetter(local) {
+		return "must start with a letter"
+	}
+	if err := dotPlacement(local); err != "" {
+		return err
+	}
+	if len(local) > 64 {
+		return "must be at most 64 characters"
+	}
+	return ""
+}
+
+// yahooRule: 4–32 characters; letters, digits, underscores, at most one dot,
+// and hyphens (disposable "base-keyword" addresses); must start with a letter
+// and cannot end with a dot or underscore.
+func yahooRule(local string) string {
+	if err := onlyChars(local, "._-"); err != "" {
+		return err
+	}
+	if !startsWithLetter(local) {
+		return "must start with a letter"
+	}
+	if strings.Count(local, ".") > 1 {
+		return "must contain at most one dot"
+	}
+	if strings.HasSuffix(local, ".") || strings.HasSuffix(local, "_") {
+		return "cannot end with a dot or underscore"
+	}
+	if len(local) < 4 || len(local) > 32 {
+		return "must be 4-32 characters"
+	}
+	return ""
+}
+
+// onlyChars checks that s holds only ASCII letters, digits and the extra characters.
+func onlyChars(s, extra string) string {
+	for _, ch := range s {
+		if (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || strings.ContainsRune(extra, ch) {
+			continue
+		}
+		return "contains disallowed character: " + string(ch)
+	}
+	return ""
+}
+
+func startsWithLetter(s string) bool {
+	return s != "" && s[0] >= 'a' && s[0] <= 'z'
+}
+
+func dotPlacement(s string) string {
+	if strings.HasPrefix(s, ".") || strings.HasSuffix(s, ".") {
+		return "cannot start or end with a dot"
+	}
+	if strings.Contains(s, "..") {
+		return "cannot contain consecutive dots"
+	}
+	return ""
+}
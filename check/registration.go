@@ -0,0 +1,267 @@
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// DefaultRDAPBootstrapURL is the IANA RDAP bootstrap registry for domains (RFC 9224).
+const DefaultRDAPBootstrapURL = "https://data.iana.org/rdap/dns.json"
+
+// RegistrationConfig is the registration checker configuration.
+type RegistrationConfig struct {
+	Timeout      time.Duration
+	BootstrapURL string        // default: DefaultRDAPBootstrapURL
+	BootstrapTTL time.Duration // how long the bootstrap file is cached (default: 24h)
+	// BootstrapRetry is how long a failed bootstrap fetch is not retried
+	// (default: 1m)
+	BootstrapRetry time.Duration
+	// HTTPClient is injectable for testing. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// RegistrationChecker confirms via RDAP that the registrable domain exists
+// at its registry. Registry or bootstrap trouble never fails the check:
+// the result passes with an "unknown" detail instead.
+type RegistrationChecker struct {
+	cfg RegistrationConfig
+
+	mu        sync.Mutex
+	services  map[string][]string // TLD → RDAP base URLs
+	fetchedAt time.Time
+	retryAt   time.Time     // no fetch before, after a failed one
+	fetchErr  error         // error of the last failed fetch
+	fetching  chan struct{} // closed when the fetch in flight is done
+}
+
+// NewRegistrationChecker creates an RDAP-based registration checker.
+func NewRegistrationChecker(cfg RegistrationConfig) *RegistrationChecker {
+	if cfg.BootstrapURL == "" {
+		cfg.BootstrapURL = DefaultRDAPBootstrapURL
+	}
+	if cfg.BootstrapTTL <= 0 {
+		cfg.BootstrapTTL = 24 * time.Hour
+	}
+	if cfg.BootstrapRetry <= 0 {
+		cfg.BootstrapRetry = time.Minute
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	return &RegistrationChecker{cfg: cfg}
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input).
+func (c *RegistrationChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the registration level, which queries RDAP
+// over HTTPS.
+func (c *RegistrationChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelRegistration, Cost: CostNetwork}
+}
+
+func (c *RegistrationChecker) Check(ctx context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelRegistration
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	zone := registrableDomain(email.Domain)
+	tld := zone[strings.LastIndex(zone, ".")+1:]
+
+	bases, err := c.rdapServers(ctx, tld)
+	if err != nil {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  true,
+			Details: fmt.Sprintf("registration status unknown: %v", err),
+		}
+	}
+	if len(bases) == 0 {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  true,
+			Details: fmt.Sprintf("registration status unknown: no RDAP service for .%s", tld),
+		}
+	}
+
+	var lastErr error
+	for _, base := range bases {
+		status, registered, err := c.queryDomain(ctx, base, zone)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		switch status {
+		case http.StatusOK:
+			cr := types.CheckResult{
+				Level:   level,
+				Passed:  true,
+				Details: fmt.Sprintf("%s is registered", zone),
+			}
+			if registered != "" {
+				cr.Meta = map[string]string{"registered": registered}
+			}
+			return cr
+		case http.StatusNotFound:
+			return types.CheckResult{
+				Level:   level,
+				Passed:  false,
+				Details: fmt.Sprintf("domain not registered: %s not found at registry", zone),
+			}
+		default:
+			lastErr = fmt.Errorf("RDAP server answered %d", status)
+		}
+	}
+
+	return types.CheckResult{
+		Level:   level,
+		Passed:  true,
+		Details: fmt.Sprintf("registration status unknown: %v", lastErr),
+	}
+}
+
+// rdapServers returns the RDAP base URLs responsible for the TLD,
+// fetching (or refreshing) the bootstrap registry when needed. One fetch
+// runs at a time, outside the lock: callers without a copy wait for it,
+// the others keep using the stale copy meanwhile. After a failed fetch
+// there is none for BootstrapRetry.
+func (c *RegistrationChecker) rdapServers(ctx context.Context, tld string) ([]string, error) {
+	c.mu.Lock()
+	for {
+		now := time.Now()
+		if c.services != nil && (now.Sub(c.fetchedAt) <= c.cfg.BootstrapTTL || now.Before(c.retryAt)) {
+			break
+		}
+		if c.services == nil && now.Before(c.retryAt) {
+			err := c.fetchErr
+			c.mu.Unlock()
+			return nil, err
+		}
+		if c.fetching == nil {
+			c.fetching = make(chan struct{})
+			go c.refresh(ctx)
+		}
+		if c.services != nil {
+			break // keep serving the stale copy rather than waiting
+		}
+		done := c.fetching
+		c.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		c.mu.Lock()
+	}
+	bases := c.services[tld]
+	c.mu.Unlock()
+	return bases, nil
+}
+
+// refresh fetches the bootstrap registry for rdapServers. It is not
+// cancelled with the check that started it, which may not be the last
+// one waiting.
+func (c *RegistrationChecker) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.cfg.Timeout)
+	defer cancel()
+	services, err := c.fetchBootstrap(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.retryAt, c.fetchErr = time.Now().Add(c.cfg.BootstrapRetry), err
+	} else {
+		c.services, c.fetchedAt, c.retryAt, c.fetchErr = services, time.Now(), time.Time{}, nil
+	}
+	close(c.fetching)
+	c.fetching = nil
+}
+
+// fetchBootstrap downloads and indexes the RDAP bootstrap file.
+func (c *RegistrationChecker) fetchBootstrap(ctx context.Context) (map[string][]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.BootstrapURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("RDAP bootstrap: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RDAP bootstrap: status %d", resp.StatusCode)
+	}
+
+	// {"services": [[["com", "net"], ["https://rdap.example/"]], ...]}
+	var doc struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("RDAP bootstrap: %w", err)
+	}
+
+	services := make(map[string][]string)
+	for _, svc := range doc.Services {
+		if len(svc) < 2 {
+			continue
+		}
+		for _, tld := range svc[0] {
+			services[strings.ToLower(tld)] = svc[1]
+		}
+	}
+	return services, nil
+}
+
+// queryDomain performs the RDAP domain query. It returns the HTTP status
+// and, for registered domains, the registration date if the registry reports one.
+func (c *RegistrationChecker) queryDomain(ctx context.Context, base, domain string) (int, string, error) {
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"domain/"+domain, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("RDAP query: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, "", nil
+	}
+
+	var doc struct {
+		Events []struct {
+			Action string `json:"eventAction"`
+			Date   string `json:"eventDate"`
+		} `json:"events"`
+	}
+	// The registration date is optional; a body we can't decode still means "registered"
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&doc)
+	for _, e := range doc.Events {
+		if e.Action == "registration" {
+			return resp.StatusCode, e.Date, nil
+		}
+	}
+	return resp.StatusCode, "", nil
+}
@@ -0,0 +1,162 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// roundTripFunc serves HTTP requests in-process (no real network).
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func httpResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+const testBootstrap = `{"services": [[["com", "net"], ["https://rdap.test/com/"]]]}`
+
+func newTestRegistrationChecker(bootstrapCalls *atomic.Int64) *check.RegistrationChecker {
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.String() {
+		case check.DefaultRDAPBootstrapURL:
+			if bootstrapCalls != nil {
+				bootstrapCalls.Add(1)
+			}
+			return httpResponse(http.StatusOK, testBootstrap), nil
+		case "https://rdap.test/com/domain/example.com":
+			return httpResponse(http.StatusOK, `{"events": [{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"}]}`), nil
+		case "https://rdap.test/com/domain/broken.com":
+			return nil, errors.New("connection reset")
+		default:
+			return httpResponse(http.StatusNotFound, `{"errorCode": 404}`), nil
+		}
+	})}
+	return check.NewRegistrationChecker(check.RegistrationConfig{
+		Timeout:    2 * time.Second,
+		HTTPClient: client,
+	})
+}
+
+func TestRegistrationChecker(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		wantOK      bool
+		wantDetails string
+	}{
+		{"registered", "user@example.com", true, "example.com is registered"},
+		{"registered via subdomain", "user@mail.example.com", true, "example.com is registered"},
+		{"not registered", "user@exmaple-unregistered.com", false, "domain not registered"},
+		{"no RDAP service for TLD", "user@example.zz", true, "no RDAP service for .zz"},
+		{"registry unreachable", "user@broken.com", true, "registration status unknown"},
+		{"invalid email", "invalid", false, "skipped"},
+	}
+
+	c := newTestRegistrationChecker(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.Check(context.Background(), parse.NewEmail(tt.email))
+			assert.Equal(t, types.LevelRegistration, result.Level)
+			assert.Equal(t, tt.wantOK, result.Passed)
+			assert.Contains(t, result.Details, tt.wantDetails)
+		})
+	}
+}
+
+func TestRegistrationChecker_RegistrationDate(t *testing.T) {
+	c := newTestRegistrationChecker(nil)
+	result := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.Equal(t, "1995-08-14T04:00:00Z", result.Meta["registered"])
+}
+
+func TestRegistrationChecker_CachesBootstrap(t *testing.T) {
+	var calls atomic.Int64
+	c := newTestRegistrationChecker(&calls)
+	ctx := context.Background()
+	_ = c.Check(ctx, parse.NewEmail("a@example.com"))
+	_ = c.Check(ctx, parse.NewEmail("b@example.net"))
+	assert.Equal(t, int64(1), calls.Load())
+}
+
+func TestRegistrationChecker_BootstrapUnavailable(t *testing.T) {
+	client := &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return httpResponse(http.StatusServiceUnavailable, ""), nil
+	})}
+	c := check.NewRegistrationChecker(check.RegistrationConfig{Timeout: 2 * time.Second, HTTPClient: client})
+	result := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.True(t, result.Passed)
+	assert.Contains(t, result.Details, "registration status unknown")
+}
+
+func TestRegistrationChecker_BootstrapBackoff(t *testing.T) {
+	var calls atomic.Int64
+	client := &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return nil, errors.New("connection refused")
+	})}
+	c := check.NewRegistrationChecker(check.RegistrationConfig{Timeout: 2 * time.Second, HTTPClient: client})
+	for range 3 {
+		result := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+		assert.True(t, result.Passed)
+		assert.Contains(t, result.Details, "connection refused")
+	}
+	assert.Equal(t, int64(1), calls.Load(), "a failed fetch is not retried right away")
+}
+
+func TestRegistrationChecker_BootstrapFetchedOnce(t *testing.T) {
+	var calls atomic.Int64
+	release := make(chan struct{})
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.String() == check.DefaultRDAPBootstrapURL {
+			calls.Add(1)
+			<-release
+			return httpResponse(http.StatusOK, testBootstrap), nil
+		}
+		return httpResponse(http.StatusOK, `{}`), nil
+	})}
+	c := check.NewRegistrationChecker(check.RegistrationConfig{Timeout: 2 * time.Second, HTTPClient: client})
+
+	var wg sync.WaitGroup
+	results := make([]types.CheckResult, 5)
+	for i := range results {
+		wg.Go(func() { results[i] = c.Check(context.Background(), parse.NewEmail("user@example.com")) })
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), calls.Load(), "concurrent checks share one fetch")
+	for _, r := range results {
+		assert.Equal(t, "example.com is registered", r.Details)
+	}
+
+	// A waiting check gives up with its own context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c = check.NewRegistrationChecker(check.RegistrationConfig{Timeout: 2 * time.Second, HTTPClient: &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			<-r.Context().Done()
+			return nil, r.Context().Err()
+		}),
+	}})
+	result := c.Check(ctx, parse.NewEmail("user@example.com"))
+	assert.Contains(t, result.Details, "registration status unknown")
+}
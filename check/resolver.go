@@ -0,0 +1,36 @@
+package check
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Resolver is the DNS resolver used by the network levels.
+// *net.Resolver satisfies it; tests and offline setups can inject fakes.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// registrableDomain returns the registrable domain (eTLD+1) of the given
+// ASCII domain, e.g. "mail.example.co.uk" → "example.co.uk".
+// Falls back to the domain itself if it has no known public suffix.
+func registrableDomain(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if d, err := publicsuffix.EffectiveTLDPlusOne(domain); err == nil {
+		return d
+	}
+	return domain
+}
+
+// isNotFound reports whether err is an authoritative "no such host" answer.
+func isNotFound(err error) bool {
+	dnsErr, ok := err.(*net.DNSError)
+	return ok && dnsErr.IsNotFound
+}
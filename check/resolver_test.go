@@ -0,0 +1,71 @@
+package check_test
+
+import (
+	"context"
+	"net"
+)
+
+// fakeResolver implements check.Resolver from static maps.
+// Missing names answer with a "no such host" DNS error.
+type fakeResolver struct {
+	mx    map[string][]*net.MX
+	hosts map[string][]string
+	ns    map[string][]*net.NS
+	txt   map[string][]string
+	addr  map[string][]string
+	err   error // if set, returned by every lookup
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if v, ok := r.mx[name]; ok {
+		return v, nil
+	}
+	return nil, notFound(name)
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if v, ok := r.hosts[host]; ok {
+		return v, nil
+	}
+	return nil, notFound(host)
+}
+
+func (r *fakeResolver) LookupNS(_ context.Context, name string) ([]*net.NS, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if v, ok := r.ns[name]; ok {
+		return v, nil
+	}
+	return nil, notFound(name)
+}
+
+func (r *fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if v, ok := r.txt[name]; ok {
+		return v, nil
+	}
+	return nil, notFound(name)
+}
+
+func (r *fakeResolver) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if v, ok := r.addr[addr]; ok {
+		return v, nil
+	}
+	return nil, notFound(addr)
+}
@@ -0,0 +1,97 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// defaultRoles are local parts of mailboxes read by a team or a system
+// rather than a person (RFC 2142 and common practice).
+var defaultRoles = []string{
+	"abuse", "admin", "billing", "contact", "help", "hello", "hostmaster",
+	"info", "mailer-daemon", "marketing", "no-reply", "noreply", "office",
+	"postmaster", "root", "sales", "security", "support", "team", "webmaster",
+}
+
+// DefaultRoles returns a copy of the built-in role prefixes.
+func DefaultRoles() []string {
+	return slices.Clone(defaultRoles)
+}
+
+// RoleConfig is the role checker configuration.
+type RoleConfig struct {
+	// Roles are the role prefixes; nil means DefaultRoles.
+	Roles []string
+	// Fail fails role accounts instead of flagging them.
+	Fail bool
+}
+
+// RoleChecker flags role accounts: addresses whose local part, without a
+// plus tag, is a role prefix, alone or followed by a separator ('.', '-'
+// or '_'), e.g. info@, sales-eu@ or support.de@. A role account passes
+// with CodeRole, or fails with Fail. Meta "role" holds the prefix.
+type RoleChecker struct {
+	cfg   RoleConfig
+	roles map[string]bool
+}
+
+// NewRoleChecker creates a role checker.
+func NewRoleChecker(cfg RoleConfig) *RoleChecker {
+	roles := cfg.Roles
+	if roles == nil {
+		roles = defaultRoles
+	}
+	c := &RoleChecker{cfg: cfg, roles: make(map[string]bool, len(roles))}
+	for _, role := range roles {
+		c.roles[strings.ToLower(strings.TrimSpace(role))] = true
+	}
+	return c
+}
+
+// Role returns the role prefix local matches, if any.
+func (c *RoleChecker) Role(local string) (string, bool) {
+	local, _, _ = strings.Cut(strings.ToLower(local), "+")
+	if c.roles[local] {
+		return local, true
+	}
+	if i := strings.IndexAny(local, ".-_"); i > 0 && c.roles[local[:i]] {
+		return local[:i], true
+	}
+	return "", false
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input).
+// It always passes as there is no local part.
+func (c *RoleChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the role level, which only looks at the local part.
+func (c *RoleChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelRole, Cost: CostOffline}
+}
+
+func (c *RoleChecker) Check(_ context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelRole
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	role, ok := c.Role(email.Local)
+	if !ok {
+		return types.CheckResult{Level: level, Passed: true, Details: "not a role account"}
+	}
+	return types.CheckResult{
+		Level:   level,
+		Passed:  !c.cfg.Fail,
+		Details: fmt.Sprintf("%s@ is a role account", role),
+		Code:    types.CodeRole,
+		Meta:    map[string]string{"role": role},
+	}
+}
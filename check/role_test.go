@@ -0,0 +1,62 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestRoleChecker(t *testing.T) {
+	c := check.NewRoleChecker(check.RoleConfig{})
+	ctx := context.Background()
+
+	tests := []struct {
+		email string
+		role  string
+	}{
+		{"jane@example.com", ""},
+		{"info@example.com", "info"},
+		{"Admin@example.com", "admin"},
+		{"support+tickets@example.com", "support"},
+		{"sales-eu@example.com", "sales"},
+		{"support.de@example.com", "support"},
+		{"no-reply@example.com", "no-reply"},
+		{"salesforce@example.com", ""}, // a prefix needs a separator
+		{"jane.info@example.com", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			r := c.Check(ctx, parse.NewEmail(tt.email))
+			assert.Equal(t, types.LevelRole, r.Level)
+			assert.True(t, r.Passed, "role accounts only warn by default")
+			assert.Equal(t, tt.role, r.Meta["role"])
+			if tt.role != "" {
+				assert.Equal(t, types.CodeRole, r.Code)
+			} else {
+				assert.Empty(t, r.Code)
+			}
+		})
+	}
+
+	r := c.CheckDomain(ctx, parse.NewDomain("example.com"))
+	assert.True(t, r.Passed)
+	assert.Empty(t, r.Code)
+}
+
+func TestRoleChecker_Config(t *testing.T) {
+	c := check.NewRoleChecker(check.RoleConfig{Roles: []string{"Careers"}, Fail: true})
+	ctx := context.Background()
+
+	r := c.Check(ctx, parse.NewEmail("careers@example.com"))
+	assert.False(t, r.Passed)
+	assert.Equal(t, types.CodeRole, r.Code)
+
+	r = c.Check(ctx, parse.NewEmail("info@example.com"))
+	assert.True(t, r.Passed, "Roles replaces the defaults")
+	assert.Empty(t, r.Code)
+}
@@ -0,0 +1,278 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/internal/spf"
+	"github.com/optimode/emailkit/types"
+)
+
+// SenderConfig is the sender checker configuration.
+type SenderConfig struct {
+	Timeout time.Duration
+	// SendingIPs are the addresses mail will be sent from. If set, the
+	// SPF record of the sender domain is evaluated for each of them;
+	// otherwise only its presence and policy are checked.
+	SendingIPs []net.IP
+	// RequireDMARC fails domains without a DMARC record.
+	RequireDMARC bool
+	// RestrictedRoles are local parts that may not send, compared
+	// case-insensitively and without a plus tag. nil means
+	// defaultRestrictedSenderRoles.
+	RestrictedRoles []string
+}
+
+// defaultRestrictedSenderRoles are the mailboxes reserved for mail
+// infrastructure (RFC 2142), which should not send on a customer's behalf.
+var defaultRestrictedSenderRoles = []string{"postmaster", "abuse", "hostmaster", "mailer-daemon", "root"}
+
+// SenderChecker validates an address as a sender (MAIL FROM) rather than
+// a recipient: its local part must not be a restricted role, its domain
+// must receive mail so bounces reach it, and its SPF and DMARC records
+// are checked. Meta holds "spf" (the SPF result for the sending IPs, or
+// "present"/"none" without them), "spf.all" (the qualifier of the
+// record's "all" mechanism) and "dmarc" (the DMARC policy, or "none" if
+// there is no record).
+type SenderChecker struct {
+	cfg      SenderConfig
+	resolver Resolver
+	roles    map[string]bool
+}
+
+// NewSenderChecker creates a sender checker using the given resolver.
+func NewSenderChecker(cfg SenderConfig, r Resolver) *SenderChecker {
+	roles := cfg.RestrictedRoles
+	if roles == nil {
+		roles = defaultRestrictedSenderRoles
+	}
+	c := &SenderChecker{cfg: cfg, resolver: r, roles: make(map[string]bool, len(roles))}
+	for _, role := range roles {
+		c.roles[strings.ToLower(role)] = true
+	}
+	return c
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input).
+// The role restriction is skipped as there is no local part.
+func (c *SenderChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the sender level, which makes its own MX, TXT
+// and DMARC lookups.
+func (c *SenderChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelSender, Cost: CostDNS}
+}
+
+func (c *SenderChecker) Check(ctx context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelSender
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	local, _, _ := strings.Cut(strings.ToLower(email.Local), "+")
+	if c.roles[local] {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  false,
+			Details: fmt.Sprintf("%s@ is reserved for mail infrastructure and may not send", local),
+			Code:    types.CodeSenderRole,
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	domain := strings.ToLower(email.Domain)
+	if result, ok := c.checkBounces(ctx, domain); !ok {
+		return result
+	}
+
+	meta := make(map[string]string)
+	var warnings []string
+
+	// SPF: the record must not rule out the sending IPs, or all mail
+	record, err := spfRecord(ctx, c.resolver, domain)
+	switch {
+	case err != nil:
+		meta["spf"] = string(spf.TempError)
+		warnings = append(warnings, fmt.Sprintf("SPF lookup failed: %v", err))
+	case record == "":
+		meta["spf"] = string(spf.None)
+		warnings = append(warnings, "no SPF record")
+	default:
+		meta["spf"] = "present"
+		meta["spf.all"] = spfAll(record)
+		if strings.EqualFold(strings.Join(strings.Fields(record), " "), "v=spf1 -all") {
+			return types.CheckResult{
+				Level:   level,
+				Passed:  false,
+				Details: "SPF record says the domain sends no mail",
+				Code:    types.CodeSenderSPFFail,
+				Meta:    meta,
+			}
+		}
+	}
+	if len(c.cfg.SendingIPs) > 0 && record != "" {
+		sender := email.Local + "@" + domain
+		if email.Local == "" {
+			sender = "postmaster@" + domain // RFC 7208 §2.4
+		}
+		var results []string
+		for _, ip := range c.cfg.SendingIPs {
+			res, _ := spf.Check(ctx, c.resolver, ip, domain, sender)
+			if !slices.Contains(results, string(res)) {
+				results = append(results, string(res))
+			}
+			switch res {
+			case spf.Pass:
+			case spf.Fail:
+				meta["spf"] = string(res)
+				return types.CheckResult{
+					Level:   level,
+					Passed:  false,
+					Details: fmt.Sprintf("SPF of %s rejects sending IP %s", domain, ip),
+					Code:    types.CodeSenderSPFFail,
+					Meta:    meta,
+				}
+			default:
+				warnings = append(warnings, fmt.Sprintf("SPF %s for sending IP %s", res, ip))
+			}
+		}
+		meta["spf"] = strings.Join(results, ",")
+	}
+
+	// DMARC, at the domain or else at its organizational domain
+	policy, err := c.dmarcPolicy(ctx, domain)
+	code := ""
+	switch {
+	case err != nil:
+		meta["dmarc"] = string(spf.TempError)
+		warnings = append(warnings, fmt.Sprintf("DMARC lookup failed: %v", err))
+	case policy == "":
+		meta["dmarc"] = "none"
+		if c.cfg.RequireDMARC {
+			return types.CheckResult{
+				Level:   level,
+				Passed:  false,
+				Details: "no DMARC record",
+				Code:    types.CodeSenderNoDMARC,
+				Meta:    meta,
+			}
+		}
+		code = types.CodeSenderNoDMARC
+		warnings = append(warnings, "no DMARC record")
+	default:
+		meta["dmarc"] = policy
+	}
+
+	details := "sender ok"
+	if len(warnings) > 0 {
+		details = "sender ok with warnings: " + strings.Join(warnings, "; ")
+	}
+	return types.CheckResult{Level: level, Passed: true, Details: details, Code: code, Meta: meta}
+}
+
+// checkBounces reports whether domain can receive bounces: it has MX
+// records other than a null MX, or resolves for the implicit MX. If not,
+// it returns the failed result.
+func (c *SenderChecker) checkBounces(ctx context.Context, domain string) (types.CheckResult, bool) {
+	level := types.LevelSender
+	mxRecords, err := c.resolver.LookupMX(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  false,
+			Details: fmt.Sprintf("MX lookup failed: %v", err),
+			Code:    dnsErrorCode(err),
+		}, false
+	}
+	if len(mxRecords) == 1 && strings.TrimSuffix(mxRecords[0].Host, ".") == "" {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  false,
+			Details: "domain accepts no mail (null MX), so bounces are lost",
+			Code:    types.CodeSenderNoBounce,
+		}, false
+	}
+	if len(mxRecords) == 0 {
+		if addrs, err := c.resolver.LookupHost(ctx, domain); err != nil || len(addrs) == 0 {
+			return types.CheckResult{
+				Level:   level,
+				Passed:  false,
+				Details: "domain does not receive mail, so bounces are lost",
+				Code:    types.CodeSenderNoBounce,
+			}, false
+		}
+	}
+	return types.CheckResult{}, true
+}
+
+// dmarcPolicy returns the p= policy of the DMARC record that applies to
+// domain (RFC 7489 §6.6.3), or "" if there is none.
+func (c *SenderChecker) dmarcPolicy(ctx context.Context, domain string) (string, error) {
+	names := []string{domain}
+	if org := registrableDomain(domain); org != domain {
+		names = append(names, org)
+	}
+	for _, name := range names {
+		txt, err := c.resolver.LookupTXT(ctx, "_dmarc."+name)
+		if err != nil && !isNotFound(err) {
+			return "", err
+		}
+		for _, record := range txt {
+			tags := strings.Split(record, ";")
+			if !strings.EqualFold(strings.TrimSpace(tags[0]), "v=DMARC1") {
+				continue
+			}
+			for _, tag := range tags[1:] {
+				if v, ok := strings.CutPrefix(strings.TrimSpace(tag), "p="); ok {
+					return strings.ToLower(strings.TrimSpace(v)), nil
+				}
+			}
+			return "none", nil // p is required; treat its absence as monitoring
+		}
+	}
+	return "", nil
+}
+
+// spfRecord returns the SPF record of domain, or "" if it has none.
+func spfRecord(ctx context.Context, r Resolver, domain string) (string, error) {
+	txt, err := r.LookupTXT(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return "", err
+	}
+	return findSPF(txt), nil
+}
+
+// findSPF returns the SPF record among TXT records, or "" if there is none.
+func findSPF(txt []string) string {
+	for _, record := range txt {
+		lower := strings.ToLower(record)
+		if lower == "v=spf1" || strings.HasPrefix(lower, "v=spf1 ") {
+			return record
+		}
+	}
+	return ""
+}
+
+// spfAll returns the "all" mechanism of an SPF record with its qualifier,
+// e.g. "-all" or "~all", or "" if the record has none.
+func spfAll(record string) string {
+	for _, term := range strings.Fields(strings.ToLower(record)) {
+		switch term {
+		case "all", "+all":
+			return "+all"
+		case "-all", "~all", "?all":
+			return term
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func senderResolver() *fakeResolver {
+	return &fakeResolver{
+		mx: map[string][]*net.MX{
+			"good.example":   {{Host: "mx.good.example.", Pref: 10}},
+			"nospf.example":  {{Host: "mx.nospf.example.", Pref: 10}},
+			"nomail.example": {{Host: "mx.nomail.example.", Pref: 10}},
+			"null.example":   {{Host: ".", Pref: 0}},
+			"mail.org.co.uk": {{Host: "mx.org.co.uk.", Pref: 10}},
+		},
+		hosts: map[string][]string{
+			"amx.example": {"192.0.2.80"}, // no MX: implicit MX
+		},
+		txt: map[string][]string{
+			"good.example":         {"google-site-verification=abc", "v=spf1 ip4:192.0.2.0/24 ~all"},
+			"_dmarc.good.example":  {"v=DMARC1; p=reject; rua=mailto:d@good.example"},
+			"nomail.example":       {"v=spf1 -all"},
+			"amx.example":          {"v=spf1 a -all"},
+			"_dmarc.amx.example":   {"v=DMARC1; p=quarantine"},
+			"mail.org.co.uk":       {"v=spf1 mx ~all"},
+			"_dmarc.org.co.uk":     {"v=DMARC1; p=none"},
+			"_dmarc.nospf.example": {"v=DMARC1;p=Reject"},
+		},
+	}
+}
+
+func TestSenderChecker(t *testing.T) {
+	c := check.NewSenderChecker(check.SenderConfig{Timeout: time.Second}, senderResolver())
+	ctx := context.Background()
+
+	tests := []struct {
+		email  string
+		passed bool
+		code   string
+		spf    string
+		spfAll string
+		dmarc  string
+	}{
+		{"news@good.example", true, "", "present", "~all", "reject"},
+		{"news@amx.example", true, "", "present", "-all", "quarantine"},
+		{"news@mail.org.co.uk", true, "", "present", "~all", "none"}, // organizational DMARC record
+		{"news@nospf.example", true, "", "none", "", "reject"},
+		{"Postmaster+x@good.example", false, types.CodeSenderRole, "", "", ""},
+		{"news@null.example", false, types.CodeSenderNoBounce, "", "", ""},
+		{"news@missing.example", false, types.CodeSenderNoBounce, "", "", ""},
+		{"news@nomail.example", false, types.CodeSenderSPFFail, "present", "-all", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			r := c.Check(ctx, parse.NewEmail(tt.email))
+			assert.Equal(t, types.LevelSender, r.Level)
+			assert.Equal(t, tt.passed, r.Passed, r.Details)
+			assert.Equal(t, tt.code, r.Code)
+			assert.Equal(t, tt.spf, r.Meta["spf"])
+			assert.Equal(t, tt.spfAll, r.Meta["spf.all"])
+			assert.Equal(t, tt.dmarc, r.Meta["dmarc"])
+		})
+	}
+
+	r := c.Check(ctx, parse.NewEmail("news@nospf.example"))
+	assert.Equal(t, "sender ok with warnings: no SPF record", r.Details)
+}
+
+func TestSenderChecker_DMARC(t *testing.T) {
+	res := senderResolver()
+	res.txt["nodmarc.example"] = []string{"v=spf1 mx -all"}
+	res.mx["nodmarc.example"] = []*net.MX{{Host: "mx.nodmarc.example.", Pref: 10}}
+	ctx := context.Background()
+
+	r := check.NewSenderChecker(check.SenderConfig{Timeout: time.Second}, res).Check(ctx, parse.NewEmail("a@nodmarc.example"))
+	assert.True(t, r.Passed)
+	assert.Equal(t, types.CodeSenderNoDMARC, r.Code)
+	assert.Equal(t, "none", r.Meta["dmarc"])
+
+	r = check.NewSenderChecker(check.SenderConfig{Timeout: time.Second, RequireDMARC: true}, res).Check(ctx, parse.NewEmail("a@nodmarc.example"))
+	assert.False(t, r.Passed)
+	assert.Equal(t, types.CodeSenderNoDMARC, r.Code)
+}
+
+func TestSenderChecker_SendingIPs(t *testing.T) {
+	ctx := context.Background()
+	cfg := func(ips ...string) check.SenderConfig {
+		c := check.SenderConfig{Timeout: time.Second}
+		for _, ip := range ips {
+			c.SendingIPs = append(c.SendingIPs, net.ParseIP(ip))
+		}
+		return c
+	}
+
+	r := check.NewSenderChecker(cfg("192.0.2.10"), senderResolver()).Check(ctx, parse.NewEmail("news@good.example"))
+	assert.True(t, r.Passed)
+	assert.Equal(t, "pass", r.Meta["spf"])
+	assert.Equal(t, "sender ok", r.Details)
+
+	// ~all: not authorized, but not rejected
+	r = check.NewSenderChecker(cfg("192.0.2.10", "198.51.100.1"), senderResolver()).Check(ctx, parse.NewEmail("news@good.example"))
+	assert.True(t, r.Passed)
+	assert.Equal(t, "pass,softfail", r.Meta["spf"])
+	assert.Contains(t, r.Details, "SPF softfail for sending IP 198.51.100.1")
+
+	r = check.NewSenderChecker(cfg("198.51.100.1"), senderResolver()).Check(ctx, parse.NewEmail("news@amx.example"))
+	assert.False(t, r.Passed)
+	assert.Equal(t, types.CodeSenderSPFFail, r.Code)
+	assert.Equal(t, "fail", r.Meta["spf"])
+}
+
+func TestSenderChecker_Roles(t *testing.T) {
+	ctx := context.Background()
+	c := check.NewSenderChecker(check.SenderConfig{Timeout: time.Second, RestrictedRoles: []string{"NoReply"}}, senderResolver())
+	assert.Equal(t, types.CodeSenderRole, c.Check(ctx, parse.NewEmail("noreply@good.example")).Code)
+	assert.True(t, c.Check(ctx, parse.NewEmail("postmaster@good.example")).Passed, "the list replaces the default")
+
+	c = check.NewSenderChecker(check.SenderConfig{Timeout: time.Second, RestrictedRoles: []string{}}, senderResolver())
+	assert.True(t, c.Check(ctx, parse.NewEmail("abuse@good.example")).Passed)
+	assert.True(t, c.CheckDomain(ctx, parse.NewDomain("good.example")).Passed)
+}
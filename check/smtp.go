@@ -2,92 +2,386 @@ package check
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/optimode/emailkit/internal/analytics"
+	"github.com/optimode/emailkit/internal/bounce"
 	"github.com/optimode/emailkit/internal/dnscache"
 	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/internal/provider"
+	"github.com/optimode/emailkit/internal/schedule"
 	"github.com/optimode/emailkit/internal/smtppool"
+	"github.com/optimode/emailkit/internal/warmup"
 	"github.com/optimode/emailkit/types"
 )
 
 // SMTPConfig is the SMTP checker configuration.
 type SMTPConfig struct {
-	HeloDomain     string
-	MailFrom       string
-	MaxMXHosts     int
+	HeloDomain string
+	MailFrom   string
+	MaxMXHosts int
+	// Sanitize, if set, rewrites Details before the result is returned,
+	// e.g. to redact addresses and IPs echoed back by the MX server.
+	Sanitize func(string) string
+	// Greylist, if set, remembers greylisted recipients per MX host; hosts
+	// still inside GreylistWindow are not probed again.
+	Greylist       GreylistStore
+	GreylistWindow time.Duration
+	// HashLocal, if set, replaces the local part of the recipient in
+	// Greylist keys, so a store in privacy mode holds no addresses while
+	// keys stay grouped by domain.
+	HashLocal func(local string) string
+	// Schedule, if set, restricts probing to its windows and daily budgets
+	// (keyed by provider, or the primary MX domain for unknown providers).
+	Schedule *schedule.Schedule
+	// WarmUp, if set, ramps up the daily RCPT probes per provider of a
+	// new identity and pauses a provider that throttles; same keys as
+	// Schedule.
+	WarmUp *warmup.Ramp
+	// Analytics, if set, aggregates the reply of every MX host attempt
+	// per provider (same keys as Schedule) and per host.
+	Analytics *analytics.Recorder
+	// Identities are additional HELO/MAIL FROM pairs; probes rotate
+	// round-robin over HeloDomain/MailFrom and these.
+	Identities []SMTPIdentity
+	// IdentityMonitor, if set, limits the rotation to identities that
+	// passed its verification.
+	IdentityMonitor *IdentityMonitor
+	// IdentityPerWorker pins probes of a worker (see WithWorker) to one
+	// identity, worker n using identity n modulo their number, instead of
+	// rotating per probe, so a provider throttling one identity slows down
+	// only the workers using it. Probes without a worker still rotate.
+	IdentityPerWorker bool
+	// Policy, if set, overrides MaxMXHosts, timeouts and the probing
+	// strategy per recipient domain and provider (see SMTPPolicy).
+	Policy func(domain, provider string) SMTPPolicy
+	// Limiter, if set, paces probes per provider (or primary MX domain),
+	// e.g. across a fleet of processes sharing one backend.
+	Limiter ProbeLimiter
+	// Quota, if set, caps probes across all keys, e.g. per tenant.
+	Quota ProbeQuota
+	// DetectBlockedPort skips probing once connections to several
+	// well-known provider MX hosts failed with no successful connection in
+	// between, as when the hosting provider blocks outbound port 25. The
+	// level then passes with CodeSkipped; every 10 minutes one probe is let
+	// through to notice when the block is lifted.
+	DetectBlockedPort bool
+	// Hooks are notified of dials and throttled probes.
+	Hooks SMTPHooks
+	// ConnectOnly never sends MAIL FROM or RCPT TO: the level passes once an
+	// MX host completes the banner and EHLO exchange, with Meta "probe"
+	// ("connect"). Policies can still skip a domain (StrategySkip).
+	ConnectOnly bool
+	// CatchAllFingerprints mark providers and replies whose 250 doesn't
+	// prove the mailbox exists (see DefaultCatchAllFingerprints). A match
+	// passes with CodeCatchAll and Meta "catch_all" (the fingerprint name)
+	// and "deliverability" ("unknown").
+	CatchAllFingerprints []CatchAllFingerprint
+}
+
+// ProbeQuota caps the number of SMTP probes. Reserve takes one probe; if
+// none is left it returns false and when the quota resets.
+type ProbeQuota interface {
+	Reserve() (ok bool, resume time.Time)
+}
+
+// SMTPHooks observe the SMTP checker, e.g. to feed metrics. Nil funcs are
+// skipped. They are called synchronously and must not block.
+type SMTPHooks struct {
+	// OnDial is called when a probe opened a new connection to host.
+	OnDial func(email, host string, elapsed time.Duration, err error)
+	// OnThrottle is called when the schedule or the quota deferred a probe
+	// (reason "schedule" or "quota", deferred true) or the limiter held it
+	// back for at least a millisecond or refused it (reason "limiter").
+	OnThrottle func(email, key, reason string, waited time.Duration, deferred bool)
+}
+
+// ProbeLimiter paces SMTP probes. Wait blocks until a probe for key may
+// start, or returns an error (ctx.Err() when ctx is done). key is the
+// mailbox provider, e.g. "google", or the registrable domain of the
+// primary MX host. Implementations must be safe for concurrent use.
+type ProbeLimiter interface {
+	Wait(ctx context.Context, key string) error
 }
 
 // SMTPChecker performs SMTP RCPT TO probes to verify email existence.
 // It uses a shared DNS cache for MX lookups and an SMTP connection pool
 // for efficient connection reuse via the RSET command.
 type SMTPChecker struct {
-	cfg      SMTPConfig
-	dnsCache *dnscache.Cache
-	pool     *smtppool.Pool
+	cfg        SMTPConfig
+	dnsCache   *dnscache.Cache
+	pool       *smtppool.Pool
+	identities []SMTPIdentity
+	next       atomic.Uint64 // rotation counter
+	statsMu    sync.Mutex
+	stats      []IdentityStats // per identity, in identities order
+	port       *portDetector   // nil unless DetectBlockedPort
+	lastProbe  atomic.Int64    // unix nanos of the last SMTP answer
+	paused     atomic.Bool     // see SetPaused
 }
 
 // NewSMTPChecker creates an SMTP checker with a shared DNS cache and connection pool.
 func NewSMTPChecker(cfg SMTPConfig, cache *dnscache.Cache, pool *smtppool.Pool) *SMTPChecker {
-	return &SMTPChecker{
-		cfg:      cfg,
-		dnsCache: cache,
-		pool:     pool,
+	if cfg.GreylistWindow <= 0 {
+		cfg.GreylistWindow = DefaultGreylistWindow
+	}
+	identities := append([]SMTPIdentity{{HeloDomain: cfg.HeloDomain, MailFrom: cfg.MailFrom}}, cfg.Identities...)
+	c := &SMTPChecker{
+		cfg:        cfg,
+		dnsCache:   cache,
+		pool:       pool,
+		identities: identities,
+		stats:      make([]IdentityStats, len(identities)),
 	}
+	for i, id := range identities {
+		c.stats[i].Identity = id
+	}
+	if cfg.DetectBlockedPort {
+		c.port = newPortDetector()
+	}
+	return c
+}
+
+// PortBlocked reports whether outbound SMTP was detected as blocked (see
+// SMTPConfig.DetectBlockedPort).
+func (c *SMTPChecker) PortBlocked() bool {
+	return c.port != nil && c.port.isBlocked()
+}
+
+// SetPaused pauses or resumes SMTP traffic. While paused, the level
+// passes with CodeSkipped without looking up or contacting MX hosts.
+func (c *SMTPChecker) SetPaused(paused bool) {
+	c.paused.Store(paused)
+}
+
+// Paused reports whether SMTP traffic is paused (see SetPaused).
+func (c *SMTPChecker) Paused() bool {
+	return c.paused.Load()
+}
+
+// LastProbe returns when an MX host last answered a probe or connection
+// check; zero if none has yet.
+func (c *SMTPChecker) LastProbe() time.Time {
+	if ns := c.lastProbe.Load(); ns != 0 {
+		return time.Unix(0, ns)
+	}
+	return time.Time{}
+}
+
+// portBlockedResult is returned instead of probing while the port is blocked.
+var portBlockedResult = types.CheckResult{
+	Level:   types.LevelSMTP,
+	Passed:  true,
+	Details: "skipped: outbound port 25 blocked",
+	Code:    types.CodeSkipped,
+}
+
+// pausedResult is returned instead of probing while paused.
+var pausedResult = types.CheckResult{
+	Level:   types.LevelSMTP,
+	Passed:  true,
+	Details: "skipped: SMTP probing paused",
+	Code:    types.CodeSkipped,
+}
+
+// Capabilities declares the SMTP level, the dearest: it opens sessions
+// with the MX hosts.
+func (c *SMTPChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelSMTP, Cost: CostSMTP, NeedsMX: true}
 }
 
 func (c *SMTPChecker) Check(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.sanitize(c.check(ctx, email))
+}
+
+// sanitize applies Sanitize to the details and to what each MX host said.
+func (c *SMTPChecker) sanitize(result types.CheckResult) types.CheckResult {
+	if c.cfg.Sanitize == nil {
+		return result
+	}
+	result.Details = c.cfg.Sanitize(result.Details)
+	for i, a := range result.Attempts {
+		result.Attempts[i].Message = c.cfg.Sanitize(a.Message)
+		result.Attempts[i].Error = c.cfg.Sanitize(a.Error)
+	}
+	return result
+}
+
+func (c *SMTPChecker) check(ctx context.Context, email parse.Email) types.CheckResult {
 	level := types.LevelSMTP
 
 	if !email.Valid {
 		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
 	}
+	if c.paused.Load() {
+		return pausedResult
+	}
 
-	// Use cached MX lookup (shared with DNS checker)
-	mxRecords, err := c.dnsCache.LookupMX(email.Domain)
-	if err != nil || len(mxRecords) == 0 {
-		detail := "no MX records found"
-		if err != nil {
-			detail = fmt.Sprintf("MX lookup failed: %v", err)
-		}
+	mxRecords, failed := c.mailExchangers(ctx, email.Domain)
+	if failed != nil {
+		return *failed
+	}
+
+	policy := c.policy(email.Domain, mxRecords)
+	if policy.Strategy == StrategySkip {
 		return types.CheckResult{
 			Level:   level,
+			Passed:  true,
+			Details: "SMTP probe skipped by domain policy",
+			Code:    types.CodeSkipped,
+		}
+	}
+	if c.port != nil && c.port.blocked() {
+		return portBlockedResult
+	}
+	if c.cfg.ConnectOnly || policy.Strategy == StrategyConnect {
+		if res, deferred := c.reserveProbe(ctx, email.Raw, mxRecords); deferred {
+			return res
+		}
+		res := c.connectHosts(ctx, email.Raw, mxRecords, policy)
+		c.recordAnalytics(mxRecords, res)
+		switch {
+		case !res.Passed:
+		case c.cfg.ConnectOnly:
+			res.Details += " (mailbox not verified)"
+			res.Meta = map[string]string{"probe": "connect"}
+		default:
+			res.Details += " (RCPT probe skipped by domain policy)"
+			res.Code = types.CodeSkipped
+		}
+		return res
+	}
+
+	idx, res, deferred := c.identity(ctx)
+	if deferred {
+		return res
+	}
+	if res, deferred := c.reserveProbe(ctx, email.Raw, mxRecords); deferred {
+		return res
+	}
+	if res, deferred := c.reserveWarmUp(email.Raw, mxRecords); deferred {
+		return res
+	}
+	res = c.rcptHosts(ctx, email, mxRecords, policy, c.identities[idx])
+	c.recordIdentity(idx, res)
+	c.recordAnalytics(mxRecords, res)
+	if c.cfg.WarmUp != nil {
+		c.cfg.WarmUp.Record(probeKey(mxRecords), res.Passed, throttled(res))
+	}
+	return res
+}
+
+// mailExchangers returns the MX records of domain by preference: those
+// the DNS level recorded in the Findings of ctx, or else from the cache.
+// If there are none to probe — an earlier level found the domain dead, or
+// it has no MX records or a null MX — it returns the failed result instead.
+func (c *SMTPChecker) mailExchangers(ctx context.Context, domain string) ([]*net.MX, *types.CheckResult) {
+	f := ValidationFrom(ctx).Findings()
+	if reason := f.NoMail(); reason != "" {
+		return nil, &types.CheckResult{
+			Level:   types.LevelSMTP,
 			Passed:  false,
-			Details: detail,
+			Details: fmt.Sprintf("skipped: domain accepts no mail (%s)", reason),
+			Code:    types.CodeBadDomain,
 		}
 	}
 
-	sort.Slice(mxRecords, func(i, j int) bool {
+	mxRecords, ok := f.MX()
+	var err error
+	if !ok {
+		// Use cached MX lookup (shared with DNS checker)
+		mxRecords, err = c.dnsCache.LookupMX(domain)
+	}
+	switch {
+	case err != nil:
+		return nil, &types.CheckResult{Level: types.LevelSMTP, Passed: false, Details: fmt.Sprintf("MX lookup failed: %v", err), Code: dnsErrorCode(err)}
+	case len(mxRecords) == 0:
+		return nil, &types.CheckResult{Level: types.LevelSMTP, Passed: false, Details: "no MX records found"}
+	case nullMX(mxRecords):
+		return nil, &types.CheckResult{Level: types.LevelSMTP, Passed: false, Details: "domain accepts no mail (null MX)", Code: types.CodeBadDomain}
+	}
+
+	sort.SliceStable(mxRecords, func(i, j int) bool {
 		return mxRecords[i].Pref < mxRecords[j].Pref
 	})
+	return mxRecords, nil
+}
 
-	maxHosts := c.cfg.MaxMXHosts
-	if maxHosts <= 0 || maxHosts > len(mxRecords) {
-		maxHosts = len(mxRecords)
-	}
+// rcptHosts probes the MX hosts in preference order with RCPT TO, as id,
+// until one gives a definitive answer. The fallback is sequential by
+// nature, so the hosts are not probed concurrently.
+func (c *SMTPChecker) rcptHosts(ctx context.Context, email parse.Email, mxRecords []*net.MX, policy SMTPPolicy, id SMTPIdentity) types.CheckResult {
+	level := types.LevelSMTP
+	maxHosts := policy.maxHosts(len(mxRecords))
 
-	var lastErr error
+	var attempts []types.ProbeAttempt
+	var greylisted *types.CheckResult
 	for i := 0; i < maxHosts; i++ {
-		// Check context cancellation before each attempt
-		select {
-		case <-ctx.Done():
-			return types.CheckResult{
-				Level:   level,
-				Passed:  false,
-				Details: "context cancelled",
-			}
-		default:
+		if ctx.Err() != nil {
+			return types.CheckResult{Level: level, Passed: false, Details: "context cancelled", Attempts: attempts}
 		}
 
 		mxHost := strings.TrimSuffix(mxRecords[i].Host, ".")
 
-		code, msg, err := c.pool.CheckRCPT(mxHost, email.Raw)
-		if err != nil {
-			lastErr = err
+		// Still inside the greylisting window: probing now can't succeed
+		key := c.greylistKey(mxHost, email)
+		if first, pending := c.greylistPending(ctx, key); pending {
+			res := c.greylistedResult(mxHost, 0, first)
+			greylisted = &res
+			continue
+		}
+
+		r, ok := c.attempt(ctx, maxHosts-i, func(deadline time.Time) hostReply {
+			code, msg, err := c.pool.CheckRCPTWith(mxHost, email.Raw, smtppool.ProbeOptions{
+				Identity:       smtppool.Identity{HeloDomain: id.HeloDomain, MailFrom: id.MailFrom},
+				ConnectTimeout: policy.ConnectTimeout,
+				CommandTimeout: policy.CommandTimeout,
+				OnDial:         c.onDial(ctx, email.Raw, mxHost),
+				Deadline:       deadline,
+				Context:        ctx,
+			})
+			return hostReply{code: code, msg: msg, err: err}
+		})
+		attempts = append(attempts, r.attempt(mxHost))
+		if !ok {
+			return types.CheckResult{Level: level, Passed: false, Details: "context cancelled", Attempts: attempts}
+		}
+		if r.err != nil {
+			continue
+		}
+		c.lastProbe.Store(time.Now().UnixNano())
+		code, msg := r.code, r.msg
+
+		if code >= 400 && code < 500 && bounce.Explain(code, msg).Category == types.CodeGreylisted {
+			res := c.greylistedResult(mxHost, code, c.markGreylisted(ctx, key))
+			greylisted = &res
 			continue
 		}
+		if code < 400 || code >= 500 {
+			c.clearGreylist(ctx, key)
+		}
 
+		if code >= 400 && bounce.Explain(code, msg).Category == types.CodeMailboxFull {
+			// The mailbox exists; another MX host won't make room in it
+			return types.CheckResult{
+				Level:    level,
+				Passed:   false,
+				Details:  fmt.Sprintf("mailbox full: %s", msg),
+				MXHost:   mxHost,
+				SMTPCode: code,
+				Code:     types.CodeMailboxFull,
+				Meta:     map[string]string{"deliverability": "temporarily_undeliverable"},
+				Attempts: attempts,
+			}
+		}
 		if code >= 500 {
 			return types.CheckResult{
 				Level:    level,
@@ -95,25 +389,450 @@ func (c *SMTPChecker) Check(ctx context.Context, email parse.Email) types.CheckR
 				Details:  fmt.Sprintf("RCPT rejected: %s", msg),
 				MXHost:   mxHost,
 				SMTPCode: code,
+				Code:     bounce.Explain(code, msg).Category,
+				Attempts: attempts,
 			}
 		}
 		if code >= 400 {
-			lastErr = fmt.Errorf("temporary failure %d: %s", code, msg)
-			continue
+			continue // temporary failure: try the next host
 		}
 
-		return types.CheckResult{
+		res := types.CheckResult{
 			Level:    level,
 			Passed:   true,
 			Details:  "RCPT TO accepted",
 			MXHost:   mxHost,
 			SMTPCode: code,
+			Attempts: attempts,
+		}
+		if fp, ok := c.catchAll(provider.Detect(mxHosts(mxRecords)), msg); ok {
+			res.Details += " (" + fp.Reason + "; deliverability unknown)"
+			res.Code = types.CodeCatchAll
+			res.Meta = map[string]string{"catch_all": fp.Name, "deliverability": "unknown"}
+		}
+		return res
+	}
+
+	if greylisted != nil {
+		greylisted.Attempts = attempts
+		return *greylisted
+	}
+	return types.CheckResult{
+		Level:    level,
+		Passed:   false,
+		Details:  "SMTP probe failed on all MX hosts: " + describeAttempts(attempts),
+		Attempts: attempts,
+	}
+}
+
+// hostReply is what one MX host answered, or why it didn't, and how long
+// it took.
+type hostReply struct {
+	code    int
+	msg     string
+	err     error
+	latency time.Duration
+}
+
+func (r hostReply) attempt(host string) types.ProbeAttempt {
+	a := types.ProbeAttempt{Host: host, Code: r.code, Message: r.msg, Latency: r.latency}
+	if r.err != nil {
+		a.Error = r.err.Error()
+	}
+	return a
+}
+
+// attempt runs probe against one MX host. If ctx has a deadline, the
+// host gets an equal share of the time left across the hostsLeft hosts
+// still to try (this one included), so a host that hangs can't use up
+// the budget of the ones after it. probe runs in its own goroutine, bound
+// to that deadline: when ctx is done first, attempt returns at once with
+// ok false and a "context cancelled" reply, and the abandoned probe ends
+// at its deadline or its timeouts.
+func (c *SMTPChecker) attempt(ctx context.Context, hostsLeft int, probe func(deadline time.Time) hostReply) (r hostReply, ok bool) {
+	var deadline time.Time
+	if d, has := ctx.Deadline(); has {
+		deadline = time.Now().Add(time.Until(d) / time.Duration(max(hostsLeft, 1)))
+	}
+	start := time.Now()
+	done := make(chan hostReply, 1) // buffered: an abandoned probe must not block
+	go func() { done <- probe(deadline) }()
+	select {
+	case r := <-done:
+		r.latency = time.Since(start)
+		return r, true
+	case <-ctx.Done():
+		return hostReply{err: errors.New("context cancelled"), latency: time.Since(start)}, false
+	}
+}
+
+// describeAttempts summarizes what every host said, e.g.
+// "mx1.example.com: connect: i/o timeout; mx2.example.com: 451 try later".
+// Hosts skipped inside their greylisting window aren't listed; with no
+// attempt at all it returns "no host probed".
+func describeAttempts(attempts []types.ProbeAttempt) string {
+	if len(attempts) == 0 {
+		return "no host probed"
+	}
+	parts := make([]string, len(attempts))
+	for i, a := range attempts {
+		if a.Error != "" {
+			parts[i] = a.Host + ": " + a.Error
+		} else {
+			parts[i] = a.Host + ": " + a.Message
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// identity picks the index of the next identity: the worker's own with
+// IdentityPerWorker, else the next in the rotation. It returns a deferred
+// result if the IdentityMonitor found no healthy identity.
+func (c *SMTPChecker) identity(ctx context.Context) (int, types.CheckResult, bool) {
+	ids := make([]int, len(c.identities))
+	for i := range ids {
+		ids[i] = i
+	}
+	if c.cfg.IdentityMonitor != nil {
+		healthy, next := c.cfg.IdentityMonitor.Healthy(ctx)
+		if len(healthy) == 0 {
+			return 0, types.CheckResult{
+				Level:   types.LevelSMTP,
+				Passed:  false,
+				Details: "SMTP probe deferred: no healthy sender identity",
+				Code:    types.CodeDeferred,
+				Meta:    map[string]string{"resume_at": next.UTC().Format(time.RFC3339)},
+			}, true
+		}
+		ids = ids[:0]
+		for _, h := range healthy {
+			if i := slices.Index(c.identities, h); i >= 0 {
+				ids = append(ids, i)
+			}
+		}
+	}
+	var n uint64
+	if w, ok := workerFrom(ctx); ok && c.cfg.IdentityPerWorker {
+		n = uint64(w)
+	} else {
+		n = c.next.Add(1) - 1
+	}
+	return ids[n%uint64(len(ids))], types.CheckResult{}, false
+}
+
+type workerKey struct{}
+
+// WithWorker tags ctx with the index of the worker goroutine validating
+// under it, for SMTPConfig.IdentityPerWorker. Negative indexes are
+// ignored.
+func WithWorker(ctx context.Context, worker int) context.Context {
+	if worker < 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, workerKey{}, worker)
+}
+
+func workerFrom(ctx context.Context) (int, bool) {
+	w, ok := ctx.Value(workerKey{}).(int)
+	return w, ok
+}
+
+// IdentityStats counts the RCPT probes made with one SMTPIdentity, to spot
+// an identity that providers throttle.
+type IdentityStats struct {
+	Identity SMTPIdentity `json:"identity"`
+	Probes   int64        `json:"probes"`
+	Accepted int64        `json:"accepted"`
+	// Rejected counts permanent answers about the mailbox (5xx other
+	// than blocks).
+	Rejected int64 `json:"rejected"`
+	// Throttled counts probes an MX host answered with 421 or a rate
+	// limiting or blocking reply, on any host tried.
+	Throttled int64 `json:"throttled"`
+	// Failed counts probes with no definitive answer: connection failures,
+	// temporary errors and greylisting.
+	Failed        int64     `json:"failed"`
+	LastThrottled time.Time `json:"lastThrottled,omitzero"`
+}
+
+// IdentityStats returns the probe counters of every identity, the primary
+// HeloDomain/MailFrom first.
+func (c *SMTPChecker) IdentityStats() []IdentityStats {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	return slices.Clone(c.stats)
+}
+
+// throttled reports whether an MX host answered a probe with 421 or a
+// rate limiting or blocking reply.
+func throttled(res types.CheckResult) bool {
+	for _, a := range res.Attempts {
+		if a.Code == 0 {
+			continue
+		}
+		if cat := bounce.Explain(a.Code, a.Message).Category; a.Code == 421 || cat == types.CodeRateLimited || cat == types.CodeBlocked {
+			return true
 		}
 	}
+	return false
+}
+
+// recordIdentity counts the outcome of a probe made with identity idx.
+func (c *SMTPChecker) recordIdentity(idx int, res types.CheckResult) {
+	throttled := throttled(res)
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	s := &c.stats[idx]
+	s.Probes++
+	switch {
+	case res.Passed:
+		s.Accepted++
+	case res.SMTPCode >= 500 && res.Code != types.CodeBlocked:
+		s.Rejected++
+	default:
+		s.Failed++
+	}
+	if throttled {
+		s.Throttled++
+		s.LastThrottled = time.Now()
+	}
+}
 
+// recordAnalytics feeds the attempts of a probe to SMTPConfig.Analytics.
+func (c *SMTPChecker) recordAnalytics(mxRecords []*net.MX, res types.CheckResult) {
+	if c.cfg.Analytics == nil || len(res.Attempts) == 0 {
+		return
+	}
+	key := probeKey(mxRecords)
+	for _, a := range res.Attempts {
+		c.cfg.Analytics.Record(key, a.Host, a.Code, a.Latency)
+	}
+}
+
+// onDial feeds dial outcomes to the blocked port detector and
+// Hooks.OnDial. Dials cut short by ctx say nothing about the port and are
+// not observed.
+func (c *SMTPChecker) onDial(ctx context.Context, email, host string) func(time.Duration, error) {
+	if c.port == nil && c.cfg.Hooks.OnDial == nil {
+		return nil
+	}
+	return func(elapsed time.Duration, err error) {
+		if c.port != nil && ctx.Err() == nil {
+			c.port.observe(host, err)
+		}
+		if c.cfg.Hooks.OnDial != nil {
+			c.cfg.Hooks.OnDial(email, host, elapsed, err)
+		}
+	}
+}
+
+// reserveProbe applies the probe schedule, the quota and the limiter. It
+// returns a deferred result if the probe may not run now. mxRecords must
+// be sorted by preference.
+func (c *SMTPChecker) reserveProbe(ctx context.Context, email string, mxRecords []*net.MX) (types.CheckResult, bool) {
+	if c.cfg.Schedule == nil && c.cfg.Limiter == nil && c.cfg.Quota == nil {
+		return types.CheckResult{}, false
+	}
+	key := probeKey(mxRecords)
+
+	if c.cfg.Schedule != nil {
+		ok, reason, resume := c.cfg.Schedule.Reserve(key)
+		if !ok {
+			if c.cfg.Hooks.OnThrottle != nil {
+				c.cfg.Hooks.OnThrottle(email, key, "schedule", 0, true)
+			}
+			details := "SMTP probe deferred: " + reason
+			if reason == schedule.ReasonBudget {
+				details += " for " + key
+			}
+			res := types.CheckResult{
+				Level:   types.LevelSMTP,
+				Passed:  false,
+				Details: details,
+				Code:    types.CodeDeferred,
+			}
+			if !resume.IsZero() {
+				res.Meta = map[string]string{"resume_at": resume.UTC().Format(time.RFC3339)}
+			}
+			return res, true
+		}
+	}
+
+	if c.cfg.Quota != nil {
+		if ok, resume := c.cfg.Quota.Reserve(); !ok {
+			if c.cfg.Hooks.OnThrottle != nil {
+				c.cfg.Hooks.OnThrottle(email, key, "quota", 0, true)
+			}
+			return types.CheckResult{
+				Level:   types.LevelSMTP,
+				Passed:  false,
+				Details: "SMTP probe deferred: daily probe quota exhausted",
+				Code:    types.CodeDeferred,
+				Meta:    map[string]string{"resume_at": resume.UTC().Format(time.RFC3339)},
+			}, true
+		}
+	}
+
+	if c.cfg.Limiter != nil {
+		start := time.Now()
+		err := c.cfg.Limiter.Wait(ctx, key)
+		if waited := time.Since(start); c.cfg.Hooks.OnThrottle != nil && (err != nil || waited >= time.Millisecond) {
+			c.cfg.Hooks.OnThrottle(email, key, "limiter", waited, err != nil)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return types.CheckResult{Level: types.LevelSMTP, Passed: false, Details: "context cancelled"}, true
+			}
+			return types.CheckResult{
+				Level:   types.LevelSMTP,
+				Passed:  false,
+				Details: fmt.Sprintf("SMTP probe deferred: probe limiter: %v", err),
+				Code:    types.CodeDeferred,
+			}, true
+		}
+	}
+	return types.CheckResult{}, false
+}
+
+// ReserveConnect applies the probe schedule, the quota and the limiter to
+// connections outside Check and CheckDomain, e.g. the greetings of
+// Validator.InspectDomain. It returns a deferred result if they may not
+// run now. mxRecords must be sorted by preference.
+func (c *SMTPChecker) ReserveConnect(ctx context.Context, domain string, mxRecords []*net.MX) (types.CheckResult, bool) {
+	if len(mxRecords) == 0 {
+		return types.CheckResult{}, false
+	}
+	return c.reserveProbe(ctx, domain, mxRecords)
+}
+
+// WarmUpStatus returns the warm-up state of every key probed today; nil
+// without SMTPConfig.WarmUp.
+func (c *SMTPChecker) WarmUpStatus() []warmup.Status {
+	if c.cfg.WarmUp == nil {
+		return nil
+	}
+	return c.cfg.WarmUp.Status()
+}
+
+// probeKey returns the key probes are paced by: the mailbox provider, or
+// the registrable domain of the primary MX host. mxRecords must be sorted
+// by preference.
+func probeKey(mxRecords []*net.MX) string {
+	hosts := mxHosts(mxRecords)
+	if key := provider.Detect(hosts); key != "" {
+		return key
+	}
+	return registrableDomain(hosts[0])
+}
+
+// reserveWarmUp applies the warm-up ramp to a RCPT probe. It returns a
+// deferred result if the probe may not run today.
+func (c *SMTPChecker) reserveWarmUp(email string, mxRecords []*net.MX) (types.CheckResult, bool) {
+	if c.cfg.WarmUp == nil {
+		return types.CheckResult{}, false
+	}
+	key := probeKey(mxRecords)
+	ok, reason, resume := c.cfg.WarmUp.Reserve(key)
+	if ok {
+		return types.CheckResult{}, false
+	}
+	if c.cfg.Hooks.OnThrottle != nil {
+		c.cfg.Hooks.OnThrottle(email, key, "warmup", 0, true)
+	}
 	return types.CheckResult{
-		Level:   level,
+		Level:   types.LevelSMTP,
 		Passed:  false,
-		Details: fmt.Sprintf("SMTP probe failed on all MX hosts: %v", lastErr),
+		Details: "SMTP probe deferred: " + reason + " for " + key,
+		Code:    types.CodeDeferred,
+		Meta:    map[string]string{"resume_at": resume.UTC().Format(time.RFC3339)},
+	}, true
+}
+
+// CheckDomain verifies SMTP connectivity for domain-only validation
+// (parse.NewDomain input): the first MX host that completes the banner and
+// EHLO exchange passes the level. No mail transaction is started.
+func (c *SMTPChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.sanitize(c.checkConnect(ctx, email))
+}
+
+func (c *SMTPChecker) checkConnect(ctx context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelSMTP
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid domain"}
+	}
+	if c.paused.Load() {
+		return pausedResult
+	}
+
+	mxRecords, failed := c.mailExchangers(ctx, email.Domain)
+	if failed != nil {
+		return *failed
+	}
+
+	policy := c.policy(email.Domain, mxRecords)
+	if policy.Strategy == StrategySkip {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  true,
+			Details: "SMTP probe skipped by domain policy",
+			Code:    types.CodeSkipped,
+		}
+	}
+	if c.port != nil && c.port.blocked() {
+		return portBlockedResult
+	}
+	if res, deferred := c.reserveProbe(ctx, email.Raw, mxRecords); deferred {
+		return res
+	}
+	return c.connectHosts(ctx, email.Raw, mxRecords, policy)
+}
+
+// connectHosts opens an SMTP session with the MX hosts in order until one
+// accepts. mxRecords must be sorted by preference.
+func (c *SMTPChecker) connectHosts(ctx context.Context, email string, mxRecords []*net.MX, policy SMTPPolicy) types.CheckResult {
+	level := types.LevelSMTP
+	maxHosts := policy.maxHosts(len(mxRecords))
+
+	var attempts []types.ProbeAttempt
+	for i := 0; i < maxHosts; i++ {
+		if ctx.Err() != nil {
+			return types.CheckResult{Level: level, Passed: false, Details: "context cancelled", Attempts: attempts}
+		}
+
+		mxHost := strings.TrimSuffix(mxRecords[i].Host, ".")
+		r, ok := c.attempt(ctx, maxHosts-i, func(deadline time.Time) hostReply {
+			code, msg, err := c.pool.CheckConnectWith(mxHost, smtppool.ProbeOptions{
+				ConnectTimeout: policy.ConnectTimeout,
+				CommandTimeout: policy.CommandTimeout,
+				OnDial:         c.onDial(ctx, email, mxHost),
+				Deadline:       deadline,
+				Context:        ctx,
+			})
+			return hostReply{code: code, msg: msg, err: err}
+		})
+		attempts = append(attempts, r.attempt(mxHost))
+		if !ok {
+			return types.CheckResult{Level: level, Passed: false, Details: "context cancelled", Attempts: attempts}
+		}
+		if r.err != nil {
+			continue
+		}
+		c.lastProbe.Store(time.Now().UnixNano())
+		return types.CheckResult{
+			Level:    level,
+			Passed:   true,
+			Details:  "SMTP server accepted connection",
+			MXHost:   mxHost,
+			SMTPCode: r.code,
+			Attempts: attempts,
+		}
+	}
+
+	return types.CheckResult{
+		Level:    level,
+		Passed:   false,
+		Details:  "SMTP connection failed on all MX hosts: " + describeAttempts(attempts),
+		Attempts: attempts,
 	}
 }
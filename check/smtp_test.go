@@ -3,7 +3,10 @@ package check_test
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,7 +15,9 @@ import (
 	"github.com/optimode/emailkit/check"
 	"github.com/optimode/emailkit/internal/dnscache"
 	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/internal/schedule"
 	"github.com/optimode/emailkit/internal/smtppool"
+	"github.com/optimode/emailkit/internal/warmup"
 	"github.com/optimode/emailkit/types"
 )
 
@@ -55,6 +60,14 @@ func testSMTPServer(server net.Conn, banner string, responses map[string]string)
 }
 
 func newTestSMTPChecker(mxRecords []*net.MX, dial func(string, string, time.Duration) (net.Conn, error)) (*check.SMTPChecker, func()) {
+	return newTestSMTPCheckerWithConfig(check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+	}, mxRecords, dial)
+}
+
+func newTestSMTPCheckerWithConfig(cfg check.SMTPConfig, mxRecords []*net.MX, dial func(string, string, time.Duration) (net.Conn, error)) (*check.SMTPChecker, func()) {
 	cache := dnscache.NewWithResolver(2*time.Second, 1*time.Minute, &mockMXResolver{
 		records: mxRecords,
 	})
@@ -69,11 +82,7 @@ func newTestSMTPChecker(mxRecords []*net.MX, dial func(string, string, time.Dura
 		Dial:            dial,
 	})
 
-	checker := check.NewSMTPChecker(check.SMTPConfig{
-		HeloDomain: "test.com",
-		MailFrom:   "verify@test.com",
-		MaxMXHosts: 1,
-	}, cache, pool)
+	checker := check.NewSMTPChecker(cfg, cache, pool)
 
 	cleanup := func() { _ = pool.Close() }
 	return checker, cleanup
@@ -119,6 +128,36 @@ func TestSMTPChecker_RejectedRCPT(t *testing.T) {
 	assert.Equal(t, types.LevelSMTP, result.Level)
 	assert.False(t, result.Passed)
 	assert.Equal(t, 550, result.SMTPCode)
+	assert.Equal(t, types.CodeMailboxUnknown, result.Code)
+}
+
+func TestSMTPChecker_Sanitize(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+		Sanitize:   func(s string) string { return strings.ReplaceAll(s, "test@example.com", "[email]") },
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "MAIL FROM": "250 OK",
+			"RCPT TO": "550 5.1.1 <test@example.com>: user unknown",
+		}
+		go testSMTPServer(server, "220 smtp.example.com ESMTP", responses)
+		return client, nil
+	})
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, "RCPT rejected: 550 5.1.1 <[email]>: user unknown", result.Details)
+	assert.Equal(t, types.CodeMailboxUnknown, result.Code)
+	if assert.Len(t, result.Attempts, 1) {
+		assert.Equal(t, "550 5.1.1 <[email]>: user unknown", result.Attempts[0].Message)
+	}
 }
 
 func TestSMTPChecker_ConnectionError(t *testing.T) {
@@ -197,6 +236,162 @@ func TestSMTPChecker_TemporaryFailure(t *testing.T) {
 	assert.Contains(t, result.Details, "SMTP probe failed")
 }
 
+func TestSMTPChecker_MailboxFull(t *testing.T) {
+	for _, reply := range []string{
+		"452 4.2.2 The email account that you tried to reach is over quota.",
+		"552 5.2.2 Mailbox full",
+	} {
+		t.Run(reply[:3], func(t *testing.T) {
+			mxRecords := []*net.MX{{Host: "mx1.example.com.", Pref: 10}, {Host: "mx2.example.com.", Pref: 20}}
+			dials := 0
+			c, cleanup := newTestSMTPCheckerWithConfig(check.SMTPConfig{
+				HeloDomain: "test.com",
+				MailFrom:   "verify@test.com",
+			}, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+				dials++
+				client, server := net.Pipe()
+				responses := map[string]string{
+					"EHLO": "250 OK", "MAIL FROM": "250 OK",
+					"RCPT TO": reply,
+				}
+				go testSMTPServer(server, "220 smtp.example.com ESMTP", responses)
+				return client, nil
+			})
+			defer cleanup()
+
+			result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+			assert.False(t, result.Passed)
+			assert.Equal(t, types.CodeMailboxFull, result.Code)
+			assert.Equal(t, "temporarily_undeliverable", result.Meta["deliverability"])
+			assert.Contains(t, result.Details, "mailbox full")
+			assert.Equal(t, 1, dials, "other MX hosts are not tried")
+		})
+	}
+}
+
+// hangingConn is a server end that never answers.
+func hangingConn() net.Conn {
+	client, server := net.Pipe()
+	go func() {
+		defer func() { _ = server.Close() }()
+		_, _ = io.Copy(io.Discard, server)
+	}()
+	return client
+}
+
+func twoMXConfig() check.SMTPConfig {
+	return check.SMTPConfig{HeloDomain: "test.com", MailFrom: "verify@test.com", MaxMXHosts: 2}
+}
+
+func TestSMTPChecker_Attempts(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx2.example.com.", Pref: 20}, {Host: "mx1.example.com.", Pref: 10}}
+	c, cleanup := newTestSMTPCheckerWithConfig(twoMXConfig(), mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		if strings.HasPrefix(address, "mx1.") {
+			return nil, fmt.Errorf("connection refused")
+		}
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "MAIL FROM": "250 OK",
+			"RCPT TO": "550 5.1.1 User unknown",
+		}
+		go testSMTPServer(server, "220 smtp.example.com ESMTP", responses)
+		return client, nil
+	})
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, "mx2.example.com", result.MXHost)
+	if assert.Len(t, result.Attempts, 2) {
+		assert.Equal(t, "mx1.example.com", result.Attempts[0].Host)
+		assert.Contains(t, result.Attempts[0].Error, "connection refused")
+		assert.Zero(t, result.Attempts[0].Code)
+		assert.Equal(t, "mx2.example.com", result.Attempts[1].Host)
+		assert.Equal(t, 550, result.Attempts[1].Code)
+		assert.Equal(t, "550 5.1.1 User unknown", result.Attempts[1].Message)
+		assert.Positive(t, result.Attempts[1].Latency)
+	}
+}
+
+func TestSMTPChecker_AllHostsFailedDetails(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx1.example.com.", Pref: 10}, {Host: "mx2.example.com.", Pref: 20}}
+	c, cleanup := newTestSMTPCheckerWithConfig(twoMXConfig(), mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		if strings.HasPrefix(address, "mx1.") {
+			return nil, fmt.Errorf("connection refused")
+		}
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "MAIL FROM": "250 OK",
+			"RCPT TO": "421 4.3.2 Service shutting down",
+		}
+		go testSMTPServer(server, "220 smtp.example.com ESMTP", responses)
+		return client, nil
+	})
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+
+	// What the first host said isn't lost behind the last one
+	assert.False(t, result.Passed)
+	assert.Equal(t, "SMTP probe failed on all MX hosts: "+
+		"mx1.example.com: connect to mx1.example.com:25: connection refused; "+
+		"mx2.example.com: 421 4.3.2 Service shutting down", result.Details)
+	assert.Len(t, result.Attempts, 2)
+}
+
+func TestSMTPChecker_PerHostDeadline(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx1.example.com.", Pref: 10}, {Host: "mx2.example.com.", Pref: 20}}
+	c, cleanup := newTestSMTPCheckerWithConfig(twoMXConfig(), mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		if strings.HasPrefix(address, "mx1.") {
+			return hangingConn(), nil
+		}
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "MAIL FROM": "250 OK",
+			"RCPT TO": "250 OK",
+		}
+		go testSMTPServer(server, "220 smtp.example.com ESMTP", responses)
+		return client, nil
+	})
+	defer cleanup()
+
+	// mx1 hangs but gets only half of the budget; mx2 still answers in time
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	result := c.Check(ctx, parse.NewEmail("test@example.com"))
+
+	assert.True(t, result.Passed, result.Details)
+	assert.Equal(t, "mx2.example.com", result.MXHost)
+	if assert.Len(t, result.Attempts, 2) {
+		assert.Contains(t, result.Attempts[0].Error, "timeout")
+		assert.Equal(t, 250, result.Attempts[1].Code)
+	}
+}
+
+func TestSMTPChecker_CancelDuringProbe(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	c, cleanup := newTestSMTPChecker(mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return hangingConn(), nil
+	})
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	result := c.Check(ctx, parse.NewEmail("test@example.com"))
+
+	// Returns on cancellation, not after the 5s command timeout
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.False(t, result.Passed)
+	assert.Equal(t, "context cancelled", result.Details)
+	if assert.Len(t, result.Attempts, 1) {
+		assert.Equal(t, "mx.example.com", result.Attempts[0].Host)
+		assert.Equal(t, "context cancelled", result.Attempts[0].Error)
+		assert.GreaterOrEqual(t, result.Attempts[0].Latency, 50*time.Millisecond)
+	}
+}
+
 func TestSMTPChecker_ConnectionReuse(t *testing.T) {
 	dialCount := 0
 	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
@@ -226,3 +421,325 @@ func TestSMTPChecker_ConnectionReuse(t *testing.T) {
 	// Should have reused the connection (only 1 dial)
 	assert.Equal(t, 1, dialCount)
 }
+
+func TestSMTPChecker_CheckDomain(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	c, cleanup := newTestSMTPChecker(mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		// No MAIL FROM / RCPT TO responses: a transaction would time out
+		responses := map[string]string{"EHLO": "250 OK"}
+		go testSMTPServer(server, "220 smtp.example.com ESMTP", responses)
+		return client, nil
+	})
+	defer cleanup()
+
+	result := c.CheckDomain(context.Background(), parse.NewDomain("example.com"))
+
+	assert.Equal(t, types.LevelSMTP, result.Level)
+	assert.True(t, result.Passed)
+	assert.Equal(t, "SMTP server accepted connection", result.Details)
+	assert.Equal(t, "mx.example.com", result.MXHost)
+	assert.Equal(t, 250, result.SMTPCode)
+}
+
+func TestSMTPChecker_Paused(t *testing.T) {
+	dials := 0
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	c, cleanup := newTestSMTPChecker(mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		dials++
+		client, server := net.Pipe()
+		go testSMTPServer(server, "220 smtp.example.com ESMTP", map[string]string{"EHLO": "250 OK", "MAIL FROM": "250 OK", "RCPT TO": "250 OK"})
+		return client, nil
+	})
+	defer cleanup()
+
+	c.SetPaused(true)
+	assert.True(t, c.Paused())
+	for _, res := range []types.CheckResult{
+		c.Check(context.Background(), parse.NewEmail("test@example.com")),
+		c.CheckDomain(context.Background(), parse.NewDomain("example.com")),
+	} {
+		assert.True(t, res.Passed)
+		assert.Equal(t, types.CodeSkipped, res.Code)
+		assert.Equal(t, "skipped: SMTP probing paused", res.Details)
+	}
+	assert.Zero(t, dials)
+
+	c.SetPaused(false)
+	res := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+	assert.Contains(t, res.Details, "RCPT TO accepted")
+	assert.Equal(t, 1, dials)
+}
+
+func TestSMTPChecker_ConnectOnly(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	var mu sync.Mutex
+	var commands []string
+	c, cleanup := newTestSMTPCheckerWithConfig(check.SMTPConfig{
+		HeloDomain:  "test.com",
+		MaxMXHosts:  1,
+		ConnectOnly: true,
+	}, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer func() { _ = server.Close() }()
+			_, _ = fmt.Fprintf(server, "220 smtp.example.com ESMTP\r\n")
+			buf := make([]byte, 4096)
+			for {
+				n, err := server.Read(buf)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				commands = append(commands, strings.TrimSpace(string(buf[:n])))
+				mu.Unlock()
+				_, _ = fmt.Fprintf(server, "250 OK\r\n")
+			}
+		}()
+		return client, nil
+	})
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+
+	assert.True(t, result.Passed)
+	assert.Equal(t, "SMTP server accepted connection (mailbox not verified)", result.Details)
+	assert.Equal(t, "connect", result.Meta["probe"])
+	assert.Empty(t, result.Code)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"EHLO test.com"}, commands, "no mail transaction")
+}
+
+func TestSMTPChecker_CheckDomain_ConnectionError(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	c, cleanup := newTestSMTPChecker(mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return nil, fmt.Errorf("connection refused")
+	})
+	defer cleanup()
+
+	result := c.CheckDomain(context.Background(), parse.NewDomain("example.com"))
+
+	assert.False(t, result.Passed)
+	assert.Contains(t, result.Details, "SMTP connection failed on all MX hosts")
+}
+
+func TestSMTPChecker_ScheduleOutsideWindow(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	now := time.Date(2026, 3, 2, 20, 0, 0, 0, time.UTC) // Monday 20:00
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+		Schedule: schedule.New(schedule.Config{
+			Windows: []schedule.Window{{Start: 9 * time.Hour, End: 17 * time.Hour}},
+			Now:     func() time.Time { return now },
+		}),
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return nil, fmt.Errorf("should not be called")
+	})
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("test@example.com"))
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, types.CodeDeferred, result.Code)
+	assert.Equal(t, "SMTP probe deferred: outside probing window", result.Details)
+	assert.Equal(t, "2026-03-03T09:00:00Z", result.Meta["resume_at"])
+}
+
+func TestSMTPChecker_ScheduleDailyBudget(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "alt1.aspmx.l.google.com.", Pref: 10}}
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+		Schedule: schedule.New(schedule.Config{
+			Budgets: map[string]int{"google": 1},
+			Now:     func() time.Time { return now },
+		}),
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "RSET": "250 OK",
+			"MAIL FROM": "250 OK", "RCPT TO": "250 OK",
+		}
+		go testSMTPServer(server, "220 mx.google.com ESMTP", responses)
+		return client, nil
+	})
+	defer cleanup()
+
+	first := c.Check(context.Background(), parse.NewEmail("a@example.com"))
+	assert.True(t, first.Passed)
+
+	second := c.Check(context.Background(), parse.NewEmail("b@example.com"))
+	assert.False(t, second.Passed)
+	assert.Equal(t, types.CodeDeferred, second.Code)
+	assert.Equal(t, "SMTP probe deferred: daily budget exhausted for google", second.Details)
+	assert.Equal(t, "2026-03-03T00:00:00Z", second.Meta["resume_at"])
+
+	// The budget resets the next day
+	now = now.Add(24 * time.Hour)
+	third := c.Check(context.Background(), parse.NewEmail("c@example.com"))
+	assert.True(t, third.Passed)
+}
+
+func TestSMTPChecker_WarmUp(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "alt1.aspmx.l.google.com.", Pref: 10}}
+	now := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+		WarmUp: warmup.New(warmup.Config{
+			Start:   time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+			Days:    14,
+			Initial: 1,
+			Growth:  2, // day 2: 2 probes
+			Now:     func() time.Time { return now },
+		}),
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "RSET": "250 OK",
+			"MAIL FROM": "250 OK", "RCPT TO": "250 OK",
+		}
+		go testSMTPServer(server, "220 mx.google.com ESMTP", responses)
+		return client, nil
+	})
+	defer cleanup()
+
+	assert.True(t, c.Check(context.Background(), parse.NewEmail("a@example.com")).Passed)
+	assert.True(t, c.Check(context.Background(), parse.NewEmail("b@example.com")).Passed)
+	third := c.Check(context.Background(), parse.NewEmail("c@example.com"))
+	assert.False(t, third.Passed)
+	assert.Equal(t, types.CodeDeferred, third.Code)
+	assert.Equal(t, "SMTP probe deferred: warm-up budget exhausted for google", third.Details)
+	assert.Equal(t, "2026-03-04T00:00:00Z", third.Meta["resume_at"])
+	assert.Equal(t, []warmup.Status{{Key: "google", Day: 2, Budget: 2, Probes: 2, Accepted: 2}}, c.WarmUpStatus())
+}
+
+// keyLimiter records the keys it is asked to pace and returns err.
+type keyLimiter struct {
+	keys []string
+	err  error
+}
+
+func (l *keyLimiter) Wait(_ context.Context, key string) error {
+	l.keys = append(l.keys, key)
+	return l.err
+}
+
+func TestSMTPChecker_Limiter(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx1.mail.example.net.", Pref: 10}}
+	limiter := &keyLimiter{}
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+		Limiter:    limiter,
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "RSET": "250 OK",
+			"MAIL FROM": "250 OK", "RCPT TO": "250 OK",
+		}
+		go testSMTPServer(server, "220 mx1.mail.example.net ESMTP", responses)
+		return client, nil
+	})
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.True(t, result.Passed)
+	assert.Equal(t, []string{"example.net"}, limiter.keys)
+
+	limiter.err = fmt.Errorf("backend unavailable")
+	result = c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.False(t, result.Passed)
+	assert.Equal(t, types.CodeDeferred, result.Code)
+	assert.Equal(t, "SMTP probe deferred: probe limiter: backend unavailable", result.Details)
+}
+
+func TestSMTPChecker_Hooks(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx1.mail.example.net.", Pref: 10}}
+	limiter := &keyLimiter{}
+	var dials, throttles []string
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+		Limiter:    limiter,
+		Hooks: check.SMTPHooks{
+			OnDial: func(email, host string, _ time.Duration, err error) {
+				dials = append(dials, fmt.Sprintf("%s %s %v", email, host, err))
+			},
+			OnThrottle: func(email, key, reason string, _ time.Duration, deferred bool) {
+				throttles = append(throttles, fmt.Sprintf("%s %s %s %v", email, key, reason, deferred))
+			},
+		},
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "RSET": "250 OK",
+			"MAIL FROM": "250 OK", "RCPT TO": "250 OK",
+		}
+		go testSMTPServer(server, "220 mx1.mail.example.net ESMTP", responses)
+		return client, nil
+	})
+	defer cleanup()
+
+	for _, addr := range []string{"a@example.com", "b@example.com"} {
+		assert.True(t, c.Check(context.Background(), parse.NewEmail(addr)).Passed)
+	}
+	limiter.err = fmt.Errorf("backend unavailable")
+	assert.False(t, c.Check(context.Background(), parse.NewEmail("c@example.com")).Passed)
+
+	assert.Equal(t, []string{"a@example.com mx1.mail.example.net <nil>"}, dials)
+	assert.WithinDuration(t, time.Now(), c.LastProbe(), time.Minute)
+	assert.Equal(t, []string{"c@example.com example.net limiter true"}, throttles)
+}
+
+// countQuota allows n probes.
+type countQuota struct{ n int }
+
+func (q *countQuota) Reserve() (bool, time.Time) {
+	if q.n == 0 {
+		return false, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	}
+	q.n--
+	return true, time.Time{}
+}
+
+func TestSMTPChecker_Quota(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx1.mail.example.net.", Pref: 10}}
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+		Quota:      &countQuota{n: 1},
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "RSET": "250 OK",
+			"MAIL FROM": "250 OK", "RCPT TO": "250 OK",
+		}
+		go testSMTPServer(server, "220 mx1.mail.example.net ESMTP", responses)
+		return client, nil
+	})
+	defer cleanup()
+
+	assert.True(t, c.Check(context.Background(), parse.NewEmail("a@example.com")).Passed)
+
+	result := c.Check(context.Background(), parse.NewEmail("b@example.com"))
+	assert.False(t, result.Passed)
+	assert.Equal(t, types.CodeDeferred, result.Code)
+	assert.Equal(t, "SMTP probe deferred: daily probe quota exhausted", result.Details)
+	assert.Equal(t, "2026-03-03T00:00:00Z", result.Meta["resume_at"])
+}
@@ -0,0 +1,64 @@
+package check
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/optimode/emailkit/internal/provider"
+)
+
+// SMTPStrategy selects how the SMTP level probes a domain.
+type SMTPStrategy string
+
+const (
+	// StrategyRCPT runs the full RCPT TO probe (the default).
+	StrategyRCPT SMTPStrategy = "rcpt"
+	// StrategyConnect only checks that an MX host accepts an SMTP session,
+	// for servers where RCPT TO answers are meaningless or risky.
+	StrategyConnect SMTPStrategy = "connect"
+	// StrategySkip sends no SMTP traffic; the level passes with CodeSkipped.
+	StrategySkip SMTPStrategy = "skip"
+)
+
+// SMTPPolicy overrides SMTP probing settings for one domain. Zero fields
+// keep the checker's defaults.
+type SMTPPolicy struct {
+	MaxMXHosts     int           // MX hosts to try in order
+	ConnectTimeout time.Duration // TCP connect timeout per host
+	CommandTimeout time.Duration // response timeout per SMTP exchange
+	Strategy       SMTPStrategy  // default: StrategyRCPT
+}
+
+// policy returns the effective policy for the domain. mxRecords must be
+// sorted by preference.
+func (c *SMTPChecker) policy(domain string, mxRecords []*net.MX) SMTPPolicy {
+	var p SMTPPolicy
+	if c.cfg.Policy != nil {
+		p = c.cfg.Policy(domain, provider.Detect(mxHosts(mxRecords)))
+	}
+	if p.MaxMXHosts <= 0 {
+		p.MaxMXHosts = c.cfg.MaxMXHosts
+	}
+	if p.Strategy == "" {
+		p.Strategy = StrategyRCPT
+	}
+	return p
+}
+
+// maxHosts returns how many of n MX hosts to try.
+func (p SMTPPolicy) maxHosts(n int) int {
+	if p.MaxMXHosts <= 0 || p.MaxMXHosts > n {
+		return n
+	}
+	return p.MaxMXHosts
+}
+
+// mxHosts returns the MX host names without the trailing dot.
+func mxHosts(mxRecords []*net.MX) []string {
+	hosts := make([]string, len(mxRecords))
+	for i, mx := range mxRecords {
+		hosts[i] = strings.TrimSuffix(mx.Host, ".")
+	}
+	return hosts
+}
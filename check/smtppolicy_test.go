@@ -0,0 +1,134 @@
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// acceptingDial serves every dial with a server that accepts all commands,
+// except for hosts in down, which refuse connections.
+func acceptingDial(down ...string) func(string, string, time.Duration) (net.Conn, error) {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		host, _, _ := net.SplitHostPort(address)
+		for _, d := range down {
+			if host == d {
+				return nil, fmt.Errorf("connection refused")
+			}
+		}
+		client, server := net.Pipe()
+		responses := map[string]string{
+			"EHLO": "250 OK", "RSET": "250 OK", "NOOP": "250 OK",
+			"MAIL FROM": "250 OK", "RCPT TO": "250 OK",
+		}
+		go testSMTPServer(server, "220 mx.example.com ESMTP", responses)
+		return client, nil
+	}
+}
+
+func TestSMTPChecker_PolicySkip(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		Policy: func(domain, provider string) check.SMTPPolicy {
+			return check.SMTPPolicy{Strategy: check.StrategySkip}
+		},
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return nil, fmt.Errorf("should not be called")
+	})
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+
+	assert.True(t, result.Passed)
+	assert.Equal(t, types.CodeSkipped, result.Code)
+	assert.Equal(t, "SMTP probe skipped by domain policy", result.Details)
+}
+
+func TestSMTPChecker_PolicyConnect(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "mx.example.com.", Pref: 10}}
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		Policy: func(domain, provider string) check.SMTPPolicy {
+			return check.SMTPPolicy{Strategy: check.StrategyConnect}
+		},
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, acceptingDial())
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+
+	assert.True(t, result.Passed)
+	assert.Equal(t, types.CodeSkipped, result.Code)
+	assert.Equal(t, "SMTP server accepted connection (RCPT probe skipped by domain policy)", result.Details)
+	assert.Equal(t, "mx.example.com", result.MXHost)
+}
+
+func TestSMTPChecker_PolicyMaxMXHosts(t *testing.T) {
+	mxRecords := []*net.MX{
+		{Host: "mx1.example.com.", Pref: 10},
+		{Host: "mx2.example.com.", Pref: 20},
+	}
+	var seen []string
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		MaxMXHosts: 1,
+		Policy: func(domain, provider string) check.SMTPPolicy {
+			seen = append(seen, domain)
+			if domain == "flaky.example" {
+				return check.SMTPPolicy{MaxMXHosts: 2}
+			}
+			return check.SMTPPolicy{}
+		},
+	}
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, acceptingDial("mx1.example.com"))
+	defer cleanup()
+
+	// The default tries only the primary MX, which is down
+	result := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+	assert.False(t, result.Passed)
+
+	// The override falls through to the backup MX
+	result = c.Check(context.Background(), parse.NewEmail("user@flaky.example"))
+	assert.True(t, result.Passed)
+	assert.Equal(t, "mx2.example.com", result.MXHost)
+	assert.Equal(t, []string{"example.com", "flaky.example"}, seen)
+}
+
+func TestSMTPChecker_PolicyProviderAndTimeout(t *testing.T) {
+	mxRecords := []*net.MX{{Host: "aspmx.l.google.com.", Pref: 10}}
+	var gotProvider string
+	var gotTimeout time.Duration
+	cfg := check.SMTPConfig{
+		HeloDomain: "test.com",
+		MailFrom:   "verify@test.com",
+		Policy: func(domain, provider string) check.SMTPPolicy {
+			gotProvider = provider
+			return check.SMTPPolicy{ConnectTimeout: 750 * time.Millisecond}
+		},
+	}
+	dial := acceptingDial()
+	c, cleanup := newTestSMTPCheckerWithConfig(cfg, mxRecords, func(network, address string, timeout time.Duration) (net.Conn, error) {
+		gotTimeout = timeout
+		return dial(network, address, timeout)
+	})
+	defer cleanup()
+
+	result := c.Check(context.Background(), parse.NewEmail("user@example.com"))
+
+	assert.True(t, result.Passed)
+	assert.Equal(t, "google", gotProvider)
+	assert.Equal(t, 750*time.Millisecond, gotTimeout)
+}
@@ -0,0 +1,92 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// SPFConfig is the SPF checker configuration.
+type SPFConfig struct {
+	// Require fails domains without an SPF record instead of flagging
+	// them, and domains whose TXT lookup failed.
+	Require bool
+}
+
+// SPFChecker reports whether the domain publishes an SPF record and how
+// strict its policy is. Meta "spf" is "present" or "none", and "spf.all"
+// the qualifier of the record's "all" mechanism ("-all", "~all", "?all",
+// "+all", or "" without one). A domain without a record passes with
+// CodeSPFMissing, or fails with Require; lookup trouble passes with the
+// policy unknown unless Require is set.
+type SPFChecker struct {
+	cfg    SPFConfig
+	lookup func(name string) ([]string, error)
+}
+
+// NewSPFChecker creates an SPF checker that looks up TXT records with
+// lookup, e.g. dnscache.Cache.LookupTXT.
+func NewSPFChecker(cfg SPFConfig, lookup func(name string) ([]string, error)) *SPFChecker {
+	return &SPFChecker{cfg: cfg, lookup: lookup}
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input);
+// the SPF level only looks at the domain.
+func (c *SPFChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the SPF level: one cached TXT lookup.
+func (c *SPFChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelSPF, Cost: CostDNS}
+}
+
+func (c *SPFChecker) Check(_ context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelSPF
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	domain := strings.ToLower(email.Domain)
+	txt, err := c.lookup(domain)
+	if err != nil && !isNotFound(err) {
+		res := types.CheckResult{Level: level, Passed: true, Details: fmt.Sprintf("SPF unknown: TXT lookup failed: %v", err)}
+		if c.cfg.Require {
+			res.Passed, res.Code = false, dnsErrorCode(err)
+		}
+		return res
+	}
+
+	record := findSPF(txt)
+	if record == "" {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  !c.cfg.Require,
+			Details: "no SPF record",
+			Code:    types.CodeSPFMissing,
+			Meta:    map[string]string{"spf": "none", "spf.all": ""},
+		}
+	}
+	all := spfAll(record)
+	details := "SPF record without an all mechanism"
+	switch all {
+	case "-all":
+		details = "SPF record rejects other senders (-all)"
+	case "~all":
+		details = "SPF record soft-fails other senders (~all)"
+	case "?all":
+		details = "SPF record is neutral on other senders (?all)"
+	case "+all":
+		details = "SPF record allows any sender (+all)"
+	}
+	return types.CheckResult{
+		Level:   level,
+		Passed:  true,
+		Details: details,
+		Meta:    map[string]string{"spf": "present", "spf.all": all},
+	}
+}
@@ -0,0 +1,68 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestSPFChecker(t *testing.T) {
+	txt := map[string][]string{
+		"strict.example":   {"acme=abc", "v=spf1 include:_spf.example.net -all"},
+		"soft.example":     {"v=spf1 mx ~all"},
+		"open.example":     {"v=spf1 all"},
+		"redirect.example": {"v=spf1 redirect=_spf.example.net"},
+		"none.example":     {"google-site-verification=xyz"},
+	}
+	lookup := func(name string) ([]string, error) {
+		if name == "down.example" {
+			return nil, &net.DNSError{Err: "i/o timeout", Name: name, IsTimeout: true}
+		}
+		if records, ok := txt[name]; ok {
+			return records, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		require bool
+		email   string
+		passed  bool
+		code    types.CheckCode
+		spf     string
+		all     string
+	}{
+		{"hard fail", false, "jane@Strict.example", true, "", "present", "-all"},
+		{"soft fail", false, "jane@soft.example", true, "", "present", "~all"},
+		{"pass all", false, "jane@open.example", true, "", "present", "+all"},
+		{"no all", false, "jane@redirect.example", true, "", "present", ""},
+		{"missing", false, "jane@none.example", true, types.CodeSPFMissing, "none", ""},
+		{"nxdomain", false, "jane@nowhere.example", true, types.CodeSPFMissing, "none", ""},
+		{"missing required", true, "jane@none.example", false, types.CodeSPFMissing, "none", ""},
+		{"lookup failure", false, "jane@down.example", true, "", "", ""},
+		{"lookup failure required", true, "jane@down.example", false, types.CodeDNSTimeout, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := check.NewSPFChecker(check.SPFConfig{Require: tt.require}, lookup)
+			r := c.Check(ctx, parse.NewEmail(tt.email))
+			assert.Equal(t, types.LevelSPF, r.Level)
+			assert.Equal(t, tt.passed, r.Passed, r.Details)
+			assert.Equal(t, tt.code, r.Code)
+			assert.Equal(t, tt.spf, r.Meta["spf"])
+			assert.Equal(t, tt.all, r.Meta["spf.all"])
+		})
+	}
+
+	r := check.NewSPFChecker(check.SPFConfig{}, lookup).Check(ctx, parse.NewEmail("not-an-email"))
+	assert.False(t, r.Passed)
+	assert.Equal(t, "skipped: invalid email", r.Details)
+}
@@ -2,6 +2,7 @@ package check
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"unicode"
 
@@ -17,6 +18,11 @@ func NewSyntaxChecker() *SyntaxChecker {
 	return &SyntaxChecker{}
 }
 
+// Capabilities declares the syntax level: offline, it runs first.
+func (c *SyntaxChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelSyntax, Cost: CostOffline}
+}
+
 func (c *SyntaxChecker) Check(_ context.Context, email parse.Email) types.CheckResult {
 	level := types.LevelSyntax
 
@@ -46,6 +52,12 @@ func (c *SyntaxChecker) Check(_ context.Context, email parse.Email) types.CheckR
 		}
 	}
 
+	// Punycode labels must round-trip, otherwise a broken "xn--" label
+	// would silently pass as a plain ASCII domain
+	if err := parse.CheckPunycode(email.Domain); err != nil {
+		return types.CheckResult{Level: level, Passed: false, Details: punycodeDetails(err)}
+	}
+
 	// Domain validation (use Unicode form for user-friendly error messages;
 	// IDNA2008 validation was already done during parsing)
 	if err := validateDomain(email.DomainUnicode); err != "" {
@@ -55,6 +67,34 @@ func (c *SyntaxChecker) Check(_ context.Context, email parse.Email) types.CheckR
 	return types.CheckResult{Level: level, Passed: true, Details: "syntax ok"}
 }
 
+// CheckDomain validates a bare domain (parse.NewDomain input) with the
+// same domain rules Check applies to the part after '@'.
+func (c *SyntaxChecker) CheckDomain(_ context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelSyntax
+
+	if email.Raw == "" {
+		return types.CheckResult{Level: level, Passed: false, Details: "empty domain"}
+	}
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "invalid domain syntax"}
+	}
+	if err := parse.CheckPunycode(email.Domain); err != nil {
+		return types.CheckResult{Level: level, Passed: false, Details: punycodeDetails(err)}
+	}
+	if err := validateDomain(email.DomainUnicode); err != "" {
+		return types.CheckResult{Level: level, Passed: false, Details: err}
+	}
+	return types.CheckResult{Level: level, Passed: true, Details: "syntax ok"}
+}
+
+// punycodeDetails maps a parse.CheckPunycode error to error text.
+func punycodeDetails(err error) string {
+	if errors.Is(err, parse.ErrPunycodeMismatch) {
+		return "domain Punycode does not round-trip"
+	}
+	return "domain contains invalid Punycode label"
+}
+
 // hasQuotedLocal checks if the raw email has a quoted local part.
 func hasQuotedLocal(raw string) bool {
 	atIdx := strings.LastIndex(raw, "@")
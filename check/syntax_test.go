@@ -42,6 +42,9 @@ func TestSyntaxChecker(t *testing.T) {
 		{"valid IDN japanese", "user@例え.jp", true},
 		{"valid IDN cyrillic", "user@почта.рф", true},
 		{"valid Punycode", "user@xn--mnchen-3ya.de", true},
+		{"undecodable Punycode", "user@xn--zz.com", false},
+		{"non-round-tripping Punycode", "user@xn--abc-.com", false},
+		{"empty Punycode label", "user@mail.xn--.com", false},
 
 		// EAI (Email Address Internationalization / RFC 6531)
 		{"valid EAI chinese local", "用户@example.com", true},
@@ -57,3 +60,29 @@ func TestSyntaxChecker(t *testing.T) {
 		})
 	}
 }
+
+func TestSyntaxChecker_CheckDomain(t *testing.T) {
+	c := check.NewSyntaxChecker()
+	ctx := context.Background()
+
+	tests := []struct {
+		domain string
+		wantOK bool
+	}{
+		{"example.com", true},
+		{"münchen.de", true},
+		{"xn--mnchen-3ya.de", true},
+		{"", false},
+		{"user@example.com", false},
+		{"localhost", false},
+		{"example-.com", false},
+		{"xn--abc-.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			result := c.CheckDomain(ctx, parse.NewDomain(tt.domain))
+			assert.Equal(t, tt.wantOK, result.Passed, "Details: %s", result.Details)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package check
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// ValidationContext is the state one validation shares along its levels:
+// the parsed input, the Findings about its domain, the results of the
+// levels that ran so far and scratch values a level leaves for later ones.
+// The Validator attaches one per validation with WithValidation, so every
+// level reaches it through the ctx of Check without a change to the
+// Checker interface. It is safe for concurrent use, and its methods are
+// no-ops on a nil *ValidationContext.
+type ValidationContext struct {
+	// Email is the parsed input of the validation.
+	Email parse.Email
+
+	findings *Findings
+	mu       sync.Mutex
+	checks   []types.CheckResult
+	scratch  map[any]any
+}
+
+type validationKey struct{}
+
+// WithValidation returns ctx carrying a new ValidationContext for email.
+func WithValidation(ctx context.Context, email parse.Email) (context.Context, *ValidationContext) {
+	vc := &ValidationContext{Email: email, findings: &Findings{}}
+	return context.WithValue(ctx, validationKey{}, vc), vc
+}
+
+// ValidationFrom returns the ValidationContext of ctx, or nil.
+func ValidationFrom(ctx context.Context) *ValidationContext {
+	vc, _ := ctx.Value(validationKey{}).(*ValidationContext)
+	return vc
+}
+
+// Findings returns what the levels learned about the domain so far, or
+// nil on a nil *ValidationContext.
+func (vc *ValidationContext) Findings() *Findings {
+	if vc == nil {
+		return nil
+	}
+	return vc.findings
+}
+
+// AddCheck records the result of a level that ran.
+func (vc *ValidationContext) AddCheck(r types.CheckResult) {
+	if vc == nil {
+		return
+	}
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	vc.checks = append(vc.checks, r)
+}
+
+// Checks returns the results of the levels that ran so far, in order.
+func (vc *ValidationContext) Checks() []types.CheckResult {
+	if vc == nil {
+		return nil
+	}
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return slices.Clone(vc.checks)
+}
+
+// CheckFor returns the result of the level that ran so far, if any.
+func (vc *ValidationContext) CheckFor(level types.CheckLevel) (types.CheckResult, bool) {
+	if vc == nil {
+		return types.CheckResult{}, false
+	}
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	for _, r := range vc.checks {
+		if r.Level == level {
+			return r, true
+		}
+	}
+	return types.CheckResult{}, false
+}
+
+// Set stores a scratch value under key for the levels after this one. As
+// with context values, key should be of an unexported type of the level's
+// package so that levels don't collide.
+func (vc *ValidationContext) Set(key, value any) {
+	if vc == nil {
+		return
+	}
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	if vc.scratch == nil {
+		vc.scratch = make(map[any]any)
+	}
+	vc.scratch[key] = value
+}
+
+// Value returns the scratch value stored under key, or nil.
+func (vc *ValidationContext) Value(key any) any {
+	if vc == nil {
+		return nil
+	}
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return vc.scratch[key]
+}
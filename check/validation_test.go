@@ -0,0 +1,44 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestValidationContext(t *testing.T) {
+	type key struct{}
+	ctx, vc := check.WithValidation(context.Background(), parse.NewEmail("jane@example.com"))
+
+	assert.Same(t, vc, check.ValidationFrom(ctx))
+	assert.NotNil(t, vc.Findings())
+	assert.Equal(t, "example.com", vc.Email.Domain)
+
+	vc.AddCheck(types.CheckResult{Level: types.LevelDNS, Passed: true})
+	vc.Set(key{}, 42)
+	checks := vc.Checks()
+	checks[0].Passed = false
+	r, ok := vc.CheckFor(types.LevelDNS)
+	assert.True(t, ok)
+	assert.True(t, r.Passed, "Checks returns a copy")
+	_, ok = vc.CheckFor(types.LevelSMTP)
+	assert.False(t, ok)
+	assert.Equal(t, 42, vc.Value(key{}))
+	assert.Nil(t, vc.Value("other"))
+}
+
+func TestValidationContext_NilIsNoOp(t *testing.T) {
+	vc := check.ValidationFrom(context.Background())
+	vc.AddCheck(types.CheckResult{Level: types.LevelDNS})
+	vc.Set("k", 1)
+	assert.Nil(t, vc.Checks())
+	assert.Nil(t, vc.Findings())
+	assert.Nil(t, vc.Value("k"))
+	_, ok := vc.CheckFor(types.LevelDNS)
+	assert.False(t, ok)
+}
@@ -0,0 +1,113 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// VerificationConfig is the verification checker configuration.
+type VerificationConfig struct {
+	// Token is the TXT record content to look for, e.g.
+	// "acme-verification=3f9a2c". Records are compared with surrounding
+	// space trimmed, case-sensitively.
+	Token string
+	// TokenFor returns the token issued to a domain, overriding Token.
+	// An empty token fails the level.
+	TokenFor func(domain string) string
+	// Name is a label prepended to the domain, e.g. "_acme-verify" for
+	// _acme-verify.example.com; empty means the domain itself.
+	Name string
+	// Registrable when true looks up the registrable domain (eTLD+1), so
+	// a token on example.com also verifies eu.example.com addresses.
+	Registrable bool
+}
+
+// VerificationChecker checks that the domain publishes a caller-specified
+// TXT token, e.g. to prove domain ownership during B2B onboarding. A
+// missing token fails with CodeTokenMissing; a failed lookup with
+// CodeDNSTimeout or CodeDNSError. Meta "record" holds the name queried.
+type VerificationChecker struct {
+	cfg    VerificationConfig
+	lookup func(name string) ([]string, error)
+}
+
+// NewVerificationChecker creates a verification checker that looks up TXT
+// records with lookup, e.g. dnscache.Cache.LookupTXT.
+func NewVerificationChecker(cfg VerificationConfig, lookup func(name string) ([]string, error)) *VerificationChecker {
+	return &VerificationChecker{cfg: cfg, lookup: lookup}
+}
+
+// CheckDomain is Check for domain-only validation (parse.NewDomain input);
+// the verification level only looks at the domain.
+func (c *VerificationChecker) CheckDomain(ctx context.Context, email parse.Email) types.CheckResult {
+	return c.Check(ctx, email)
+}
+
+// Capabilities declares the verification level: one cached TXT lookup.
+func (c *VerificationChecker) Capabilities() Capabilities {
+	return Capabilities{Level: types.LevelVerification, Cost: CostDNS}
+}
+
+func (c *VerificationChecker) Check(_ context.Context, email parse.Email) types.CheckResult {
+	level := types.LevelVerification
+
+	if !email.Valid {
+		return types.CheckResult{Level: level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	domain := strings.ToLower(email.Domain)
+	if c.cfg.Registrable {
+		domain = registrableDomain(domain)
+	}
+	name := domain
+	if c.cfg.Name != "" {
+		name = strings.TrimSuffix(c.cfg.Name, ".") + "." + domain
+	}
+	meta := map[string]string{"record": name}
+
+	token := c.cfg.Token
+	if c.cfg.TokenFor != nil {
+		token = c.cfg.TokenFor(domain)
+	}
+	if token == "" {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  false,
+			Details: fmt.Sprintf("no verification token issued for %s", domain),
+			Code:    types.CodeTokenMissing,
+			Meta:    meta,
+		}
+	}
+
+	records, err := c.lookup(name)
+	if err != nil && !isNotFound(err) {
+		return types.CheckResult{
+			Level:   level,
+			Passed:  false,
+			Details: fmt.Sprintf("TXT lookup failed: %v", err),
+			Code:    dnsErrorCode(err),
+			Meta:    meta,
+		}
+	}
+	for _, r := range records {
+		if strings.TrimSpace(r) == token {
+			return types.CheckResult{
+				Level:   level,
+				Passed:  true,
+				Details: fmt.Sprintf("verification token found at %s", name),
+				Meta:    meta,
+			}
+		}
+	}
+	return types.CheckResult{
+		Level:   level,
+		Passed:  false,
+		Details: fmt.Sprintf("no verification token at %s", name),
+		Code:    types.CodeTokenMissing,
+		Meta:    meta,
+	}
+}
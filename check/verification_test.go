@@ -0,0 +1,69 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestVerificationChecker(t *testing.T) {
+	txt := map[string][]string{
+		"example.com":         {"v=spf1 -all", " acme=abc "},
+		"_verify.example.com": {"acme=xyz"},
+	}
+	var queried []string
+	lookup := func(name string) ([]string, error) {
+		queried = append(queried, name)
+		if name == "down.example" {
+			return nil, &net.DNSError{Err: "i/o timeout", Name: name, IsTimeout: true}
+		}
+		if records, ok := txt[name]; ok {
+			return records, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		cfg    check.VerificationConfig
+		email  string
+		passed bool
+		code   types.CheckCode
+		record string
+	}{
+		{"apex", check.VerificationConfig{Token: "acme=abc"}, "jane@example.com", true, "", "example.com"},
+		{"case-sensitive", check.VerificationConfig{Token: "ACME=abc"}, "jane@example.com", false, types.CodeTokenMissing, "example.com"},
+		{"label", check.VerificationConfig{Token: "acme=xyz", Name: "_verify"}, "jane@Example.com", true, "", "_verify.example.com"},
+		{"subdomain", check.VerificationConfig{Token: "acme=abc"}, "jane@eu.example.com", false, types.CodeTokenMissing, "eu.example.com"},
+		{"registrable", check.VerificationConfig{Token: "acme=abc", Registrable: true}, "jane@eu.example.com", true, "", "example.com"},
+		{"token for domain", check.VerificationConfig{TokenFor: func(d string) string { return map[string]string{"example.com": "acme=abc"}[d] }}, "jane@example.com", true, "", "example.com"},
+		{"no token issued", check.VerificationConfig{TokenFor: func(string) string { return "" }}, "jane@example.com", false, types.CodeTokenMissing, "example.com"},
+		{"lookup failure", check.VerificationConfig{Token: "acme=abc"}, "jane@down.example", false, types.CodeDNSTimeout, "down.example"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := check.NewVerificationChecker(tt.cfg, lookup)
+			r := c.Check(ctx, parse.NewEmail(tt.email))
+			assert.Equal(t, types.LevelVerification, r.Level)
+			assert.Equal(t, tt.passed, r.Passed, r.Details)
+			assert.Equal(t, tt.code, r.Code)
+			assert.Equal(t, tt.record, r.Meta["record"])
+		})
+	}
+
+	queried = nil
+	c := check.NewVerificationChecker(check.VerificationConfig{TokenFor: func(string) string { return "" }}, lookup)
+	c.Check(ctx, parse.NewEmail("jane@example.com"))
+	assert.Empty(t, queried, "no lookup without a token")
+
+	r := c.Check(ctx, parse.NewEmail("not-an-email"))
+	assert.False(t, r.Passed)
+	assert.Equal(t, "skipped: invalid email", r.Details)
+}
@@ -0,0 +1,92 @@
+package emailkit
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Checkpoint records how far a stopped bulk run got, as a resume token:
+// marshal it to JSON when the run stops and pass it back as
+// ConcurrencyOptions.Resume to continue without repeating finished work.
+// Input indexes count addresses (for ValidateReader, after the header row
+// and skipped lines), from 0.
+type Checkpoint struct {
+	// Next is the index of the first address the run had not reached.
+	Next int `json:"next"`
+	// Pending are the indexes before Next that were not validated, in
+	// ascending order.
+	Pending []int `json:"pending,omitempty"`
+}
+
+// Done reports whether the address at input index i was validated before
+// the run stopped.
+func (c *Checkpoint) Done(i int) bool {
+	if i >= c.Next {
+		return false
+	}
+	_, pending := slices.BinarySearch(c.Pending, i)
+	return !pending
+}
+
+// resumed carries over what prev, the Checkpoint a run resumed from,
+// still records as pending beyond c.Next.
+func (c Checkpoint) resumed(prev *Checkpoint) Checkpoint {
+	if prev == nil || prev.Next <= c.Next {
+		return c
+	}
+	for _, p := range prev.Pending {
+		if p >= c.Next {
+			c.Pending = append(c.Pending, p)
+		}
+	}
+	c.Next = prev.Next
+	return c
+}
+
+// StoppedError is returned (or yielded) by a bulk run stopped through
+// ConcurrencyOptions.Stop. It wraps ErrStopped.
+type StoppedError struct {
+	Checkpoint Checkpoint
+}
+
+func (e *StoppedError) Error() string {
+	return fmt.Sprintf("%v: %d addresses reached, %d of them not validated",
+		ErrStopped, e.Checkpoint.Next, len(e.Checkpoint.Pending))
+}
+
+func (e *StoppedError) Unwrap() error { return ErrStopped }
+
+// checkpointAt builds the Checkpoint of a run stopped in the chunk
+// [off, end) of the input, given which of the chunk's emails were not
+// validated.
+func checkpointAt(off, end int, stopped []bool) Checkpoint {
+	cp := Checkpoint{Next: end}
+	for i, s := range stopped {
+		if s {
+			cp.Pending = append(cp.Pending, off+i)
+		}
+	}
+	return cp
+}
+
+// seq returns the integers in [from, to).
+func seq(from, to int) []int {
+	s := make([]int, 0, max(to-from, 0))
+	for i := from; i < to; i++ {
+		s = append(s, i)
+	}
+	return s
+}
+
+// isClosed reports whether ch is closed; a nil channel never is.
+func isClosed(ch <-chan struct{}) bool {
+	if ch == nil {
+		return false
+	}
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,130 @@
+package emailkit_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+// stoppingChecker closes stop once it has seen after checks.
+type stoppingChecker struct {
+	mu    sync.Mutex
+	seen  int
+	after int
+	stop  chan struct{}
+}
+
+func (c *stoppingChecker) Check(_ context.Context, _ emailkit.Email) emailkit.CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seen++
+	if c.seen == c.after {
+		close(c.stop)
+	}
+	return emailkit.CheckResult{Level: "custom", Passed: true}
+}
+
+func TestValidateMany_Stop(t *testing.T) {
+	stop := make(chan struct{})
+	v := emailkit.New().With(&stoppingChecker{after: 2, stop: stop})
+	emails := []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"}
+
+	results, err := v.ValidateMany(context.Background(), emails, emailkit.ConcurrencyOptions{Workers: 1, Stop: stop})
+	require.ErrorIs(t, err, emailkit.ErrStopped)
+	var stopped *emailkit.StoppedError
+	require.ErrorAs(t, err, &stopped)
+	assert.Equal(t, emailkit.Checkpoint{Next: 4, Pending: []int{2, 3}}, stopped.Checkpoint)
+
+	require.Len(t, results, 4)
+	assert.True(t, results[1].Valid, "in-flight validations finish")
+	assert.Equal(t, "c@example.com", results[2].Email)
+	assert.True(t, results[2].Truncated)
+	assert.Equal(t, emailkit.ReachabilityUnknown, results[2].Reachability)
+	assert.Equal(t, emailkit.CodeCancelled, results[2].Checks[0].Code)
+}
+
+func TestValidateMany_StopOnBatch(t *testing.T) {
+	stop := make(chan struct{})
+	v := emailkit.New().With(&stoppingChecker{after: 3, stop: stop})
+	emails := []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com", "f@example.com"}
+
+	var flushed []string
+	_, err := v.ValidateMany(context.Background(), emails, emailkit.ConcurrencyOptions{
+		Workers:  1,
+		MaxBatch: 2,
+		Stop:     stop,
+		OnBatch: func(offset int, results []emailkit.Result) error {
+			for _, r := range results {
+				flushed = append(flushed, r.Email)
+			}
+			return nil
+		},
+	})
+	var stopped *emailkit.StoppedError
+	require.ErrorAs(t, err, &stopped)
+	assert.Equal(t, emailkit.Checkpoint{Next: 4, Pending: []int{3}}, stopped.Checkpoint)
+	assert.Equal(t, emails[:4], flushed, "the stopped chunk is flushed, later ones are not started")
+}
+
+func TestValidateReader_StopAndResume(t *testing.T) {
+	input := "email\na@example.com\nb@example.com\nc@example.com\nd@example.com\ne@example.com\n"
+
+	stop := make(chan struct{})
+	v := emailkit.New().With(&stoppingChecker{after: 3, stop: stop})
+	var first []string
+	var cp emailkit.Checkpoint
+	for res, err := range v.ValidateReader(context.Background(), strings.NewReader(input), emailkit.ConcurrencyOptions{Workers: 1, MaxBatch: 2, Stop: stop}) {
+		if err != nil {
+			var stopped *emailkit.StoppedError
+			require.ErrorAs(t, err, &stopped)
+			cp = stopped.Checkpoint
+			continue
+		}
+		first = append(first, res.Email)
+	}
+	assert.Equal(t, []string{"a@example.com", "b@example.com", "c@example.com"}, first)
+	assert.Equal(t, emailkit.Checkpoint{Next: 4, Pending: []int{3}}, cp)
+
+	// The token survives a round trip through a file
+	token, err := json.Marshal(cp)
+	require.NoError(t, err)
+	var resume emailkit.Checkpoint
+	require.NoError(t, json.Unmarshal(token, &resume))
+
+	v = emailkit.New()
+	var rest []string
+	for res, err := range v.ValidateReader(context.Background(), strings.NewReader(input), emailkit.ConcurrencyOptions{Resume: &resume}) {
+		require.NoError(t, err)
+		rest = append(rest, res.Email)
+	}
+	assert.Equal(t, []string{"d@example.com", "e@example.com"}, rest)
+}
+
+func TestValidateReader_StopBeforeChunkKeepsResumeState(t *testing.T) {
+	stop := make(chan struct{})
+	close(stop)
+	v := emailkit.New()
+	resume := &emailkit.Checkpoint{Next: 10, Pending: []int{1, 7}}
+
+	var err error
+	for _, e := range v.ValidateReader(context.Background(), strings.NewReader(strings.Repeat("x@example.com\n", 12)), emailkit.ConcurrencyOptions{Stop: stop, Resume: resume}) {
+		err = e
+	}
+	var stopped *emailkit.StoppedError
+	require.ErrorAs(t, err, &stopped)
+	assert.Equal(t, emailkit.Checkpoint{Next: 10, Pending: []int{1, 7}}, stopped.Checkpoint)
+}
+
+func TestCheckpoint_Done(t *testing.T) {
+	cp := emailkit.Checkpoint{Next: 5, Pending: []int{1, 3}}
+	for i, want := range []bool{true, false, true, false, true, false, false} {
+		assert.Equal(t, want, cp.Done(i), "index %d", i)
+	}
+}
@@ -0,0 +1,349 @@
+// Command listgen builds the embedded disposable domain list.
+//
+// It merges disposable domain sources (files, http(s) URLs, or - for
+// stdin), normalizes every entry to lowercase ASCII (Punycode), drops
+// invalid entries, duplicates and excluded domains, and writes the sorted
+// list with a diff report against the previous version:
+//
+//	go run ./cmd/listgen -merge \
+//	    https://raw.githubusercontent.com/disposable-email-domains/disposable-email-domains/main/disposable_email_blocklist.conf \
+//	    extra.txt
+//
+// Sources hold one domain per line; blank lines and comments (# or //)
+// are ignored, and leading "*." or "@" are stripped. The output is
+// reproducible: the same sources always produce the same file.
+//
+// Flags:
+//
+//	-o file        list to write (default internal/disposable/list.txt)
+//	-merge         keep the entries of the current list
+//	-exclude file  domains never to list, e.g. real providers listed by mistake
+//	-report file   write the diff report there instead of stderr
+//	-check         write nothing; exit 1 if the list would change (for CI)
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+func main() {
+	out := flag.String("o", "internal/disposable/list.txt", "list to write")
+	merge := flag.Bool("merge", false, "keep the entries of the current list")
+	exclude := flag.String("exclude", "", "file of domains never to list")
+	report := flag.String("report", "", "write the diff report to this file instead of stderr")
+	check := flag.Bool("check", false, "write nothing; exit 1 if the list would change")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: listgen [flags] source...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	code, err := run(context.Background(), options{
+		out:     *out,
+		merge:   *merge,
+		exclude: *exclude,
+		report:  *report,
+		check:   *check,
+		sources: flag.Args(),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "listgen:", err)
+		os.Exit(2)
+	}
+	os.Exit(code)
+}
+
+type options struct {
+	out     string
+	merge   bool
+	exclude string
+	report  string
+	check   bool
+	sources []string
+}
+
+func run(ctx context.Context, o options) (int, error) {
+	if len(o.sources) == 0 && !o.merge {
+		return 0, errors.New("no sources (pass files or URLs, or -merge)")
+	}
+
+	old, err := readList(o.out)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	var excluded []string
+	if o.exclude != "" {
+		f, err := os.Open(o.exclude)
+		if err != nil {
+			return 0, err
+		}
+		excluded, _, err = parseSource(f)
+		f.Close()
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", o.exclude, err)
+		}
+	}
+
+	b := newBuilder(excluded)
+	if o.merge {
+		b.add(o.out, old)
+	}
+	for _, src := range o.sources {
+		domains, rejected, err := fetch(ctx, src)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", src, err)
+		}
+		b.add(src, domains)
+		b.reject(src, rejected)
+	}
+
+	list := b.list()
+	d := diff(old, list)
+
+	w := io.Writer(os.Stderr)
+	if o.report != "" {
+		f, err := os.Create(o.report)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		w = f
+	}
+	writeReport(w, d, b)
+
+	if o.check {
+		if d.changed() {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	if !d.changed() {
+		return 0, nil
+	}
+	return 0, writeList(o.out, list, o.sources)
+}
+
+// fetch reads a source: a file, an http(s) URL, or - for stdin.
+func fetch(ctx context.Context, src string) (domains, rejected []string, err error) {
+	switch {
+	case src == "-":
+		return parseSource(os.Stdin)
+	case strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://"):
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, nil, fmt.Errorf("HTTP %s", resp.Status)
+		}
+		return parseSource(resp.Body)
+	default:
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		return parseSource(f)
+	}
+}
+
+// parseSource reads one domain per line. Entries that don't normalize to
+// a valid domain are returned as rejected.
+func parseSource(r io.Reader) (domains, rejected []string, err error) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if d, ok := normalize(line); ok {
+			domains = append(domains, d)
+		} else {
+			rejected = append(rejected, line)
+		}
+	}
+	return domains, rejected, sc.Err()
+}
+
+// normalize returns the lowercase ASCII form of a list entry, or false if
+// it is not a registrable or subdomain name: a public suffix alone, an IP
+// address or a malformed name.
+func normalize(entry string) (string, bool) {
+	// Inline comments and wildcard or address prefixes
+	if i := strings.IndexAny(entry, " \t#"); i >= 0 {
+		entry = entry[:i]
+	}
+	entry = strings.TrimPrefix(entry, "*.")
+	entry = strings.TrimPrefix(entry, "@")
+	entry = strings.TrimSuffix(entry, ".")
+
+	d, err := idna.Lookup.ToASCII(entry)
+	if err != nil || d == "" || len(d) > 253 {
+		return "", false
+	}
+	labels := strings.Split(d, ".")
+	if len(labels) < 2 {
+		return "", false
+	}
+	if tld := labels[len(labels)-1]; strings.Trim(tld, "0123456789") == "" {
+		return "", false // an IP address or a numeric TLD
+	}
+	if ps, _ := publicsuffix.PublicSuffix(d); ps == d {
+		return "", false
+	}
+	return d, true
+}
+
+// builder merges sources.
+type builder struct {
+	domains  map[string]string // domain -> first source listing it
+	excluded map[string]bool
+	dropped  map[string]bool // listed but excluded
+	rejected map[string][]string
+}
+
+func newBuilder(excluded []string) *builder {
+	b := &builder{
+		domains:  make(map[string]string),
+		excluded: make(map[string]bool),
+		dropped:  make(map[string]bool),
+		rejected: make(map[string][]string),
+	}
+	for _, d := range excluded {
+		b.excluded[d] = true
+	}
+	return b
+}
+
+func (b *builder) add(src string, domains []string) {
+	for _, d := range domains {
+		if b.excluded[d] {
+			b.dropped[d] = true
+			continue
+		}
+		if _, ok := b.domains[d]; !ok {
+			b.domains[d] = src
+		}
+	}
+}
+
+func (b *builder) reject(src string, entries []string) {
+	if len(entries) > 0 {
+		b.rejected[src] = append(b.rejected[src], entries...)
+	}
+}
+
+// list returns the merged domains, sorted.
+func (b *builder) list() []string {
+	list := make([]string, 0, len(b.domains))
+	for d := range b.domains {
+		list = append(list, d)
+	}
+	slices.Sort(list)
+	return list
+}
+
+// listDiff is the difference between two sorted lists.
+type listDiff struct {
+	added, removed []string
+	total          int
+}
+
+func (d listDiff) changed() bool { return len(d.added) > 0 || len(d.removed) > 0 }
+
+// diff compares the current list (in any order) with the new sorted one.
+func diff(old, list []string) listDiff {
+	before := make(map[string]bool, len(old))
+	for _, d := range old {
+		before[d] = true
+	}
+	d := listDiff{total: len(list)}
+	for _, s := range list {
+		if !before[s] {
+			d.added = append(d.added, s)
+		}
+		delete(before, s)
+	}
+	for s := range before {
+		d.removed = append(d.removed, s)
+	}
+	slices.Sort(d.removed)
+	return d
+}
+
+func writeReport(w io.Writer, d listDiff, b *builder) {
+	fmt.Fprintf(w, "%d domains: %d added, %d removed\n", d.total, len(d.added), len(d.removed))
+	for _, s := range d.added {
+		fmt.Fprintf(w, "+ %s\t(%s)\n", s, b.domains[s])
+	}
+	for _, s := range d.removed {
+		fmt.Fprintf(w, "- %s\n", s)
+	}
+	dropped := make([]string, 0, len(b.dropped))
+	for s := range b.dropped {
+		dropped = append(dropped, s)
+	}
+	slices.Sort(dropped)
+	for _, s := range dropped {
+		fmt.Fprintf(w, "excluded %s\n", s)
+	}
+	srcs := make([]string, 0, len(b.rejected))
+	for src := range b.rejected {
+		srcs = append(srcs, src)
+	}
+	slices.Sort(srcs)
+	for _, src := range srcs {
+		for _, e := range b.rejected[src] {
+			fmt.Fprintf(w, "invalid %q\t(%s)\n", e, src)
+		}
+	}
+}
+
+// readList reads a list file as written by writeList.
+func readList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	domains, _, err := parseSource(f)
+	return domains, err
+}
+
+func writeList(path string, list, sources []string) error {
+	var sb strings.Builder
+	sb.WriteString("# Known disposable/temporary email domains\n")
+	sb.WriteString("# Generated by cmd/listgen; edit the sources, not this file\n")
+	for _, src := range sources {
+		if src != "-" {
+			fmt.Fprintf(&sb, "# Source: %s\n", src)
+		}
+	}
+	sb.WriteString("# Format: one domain per line (lowercase ASCII), # for comments\n\n")
+	for _, d := range list {
+		sb.WriteString(d)
+		sb.WriteByte('\n')
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0o644)
+}
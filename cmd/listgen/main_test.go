@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"Mailinator.COM", "mailinator.com", true},
+		{"*.tempmail.dev", "tempmail.dev", true},
+		{"@trashmail.net", "trashmail.net", true},
+		{"yopmail.fr.", "yopmail.fr", true},
+		{"spam.example  # inline comment", "spam.example", true},
+		{"wegwerf-münchen.de", "xn--wegwerf-mnchen-osb.de", true},
+		{"localhost", "", false},
+		{"co.uk", "", false},
+		{"192.168.0.1", "", false},
+		{"bad_domain!.com", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := normalize(tt.in)
+		assert.Equal(t, tt.ok, ok, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "list.txt")
+	require.NoError(t, os.WriteFile(out, []byte("# old\nmailinator.com\ngone.example\n"), 0o644))
+	src1 := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(src1, []byte("# source a\nmailinator.com\nYOPMAIL.com\nnot a domain\ngmail.com\n"), 0o644))
+	src2 := filepath.Join(dir, "b.txt")
+	require.NoError(t, os.WriteFile(src2, []byte("yopmail.com\n*.tempmail.dev\n"), 0o644))
+	exclude := filepath.Join(dir, "exclude.txt")
+	require.NoError(t, os.WriteFile(exclude, []byte("gmail.com\n"), 0o644))
+	report := filepath.Join(dir, "report.txt")
+
+	o := options{out: out, exclude: exclude, report: report, sources: []string{src1, src2}}
+
+	// -check reports the pending change without writing
+	o.check = true
+	code, err := run(context.Background(), o)
+	require.NoError(t, err)
+	assert.Equal(t, 1, code)
+	data, _ := os.ReadFile(out)
+	assert.Contains(t, string(data), "gone.example")
+
+	o.check = false
+	code, err = run(context.Background(), o)
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+
+	list, err := readList(out)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mailinator.com", "tempmail.dev", "yopmail.com"}, list)
+
+	data, err = os.ReadFile(report)
+	require.NoError(t, err)
+	rep := string(data)
+	assert.Contains(t, rep, "3 domains: 2 added, 1 removed")
+	assert.Contains(t, rep, "+ yopmail.com\t("+src1+")")
+	assert.Contains(t, rep, "- gone.example")
+	assert.Contains(t, rep, "excluded gmail.com")
+	assert.Contains(t, rep, `invalid "not a domain"`)
+
+	// Regenerating is reproducible: nothing changes
+	o.check = true
+	code, err = run(context.Background(), o)
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+}
+
+func TestRun_Merge(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "list.txt")
+	require.NoError(t, os.WriteFile(out, []byte("mailinator.com\n"), 0o644))
+	src := filepath.Join(dir, "new.txt")
+	require.NoError(t, os.WriteFile(src, []byte("trashmail.net\n"), 0o644))
+
+	_, err := run(context.Background(), options{out: out, merge: true, report: filepath.Join(dir, "r.txt"), sources: []string{src}})
+	require.NoError(t, err)
+	list, err := readList(out)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mailinator.com", "trashmail.net"}, list)
+}
+
+func TestWriteReport_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	writeReport(&buf, diff([]string{"a.com"}, []string{"a.com"}), newBuilder(nil))
+	assert.Equal(t, "1 domains: 0 added, 0 removed\n", buf.String())
+}
+
+func TestEmbeddedListIsNormalized(t *testing.T) {
+	list, err := readList("../../internal/disposable/list.txt")
+	require.NoError(t, err)
+	for _, d := range list {
+		n, ok := normalize(d)
+		assert.True(t, ok && n == d, "entry %q", d)
+	}
+	assert.False(t, strings.Contains(strings.Join(list, "\n"), " "))
+}
@@ -0,0 +1,341 @@
+// Package daemon runs a Validator as a long-lived service, e.g. a sidecar
+// that validates addresses for an application over HTTP:
+//
+//	v := emailkit.New().WithDNS().WithSMTP(opts)
+//	d, err := daemon.New(v, daemon.Options{StatePath: "/var/lib/emailkit/state.json", AdminToken: token})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	go func() { _ = d.Run(ctx) }()
+//	log.Fatal(http.ListenAndServe("127.0.0.1:8080", d.Handler()))
+//
+// The Daemon restores the Validator's warm state (see
+// emailkit.Validator.ExportState) from StatePath when created, saves it
+// every SaveInterval and once more when Run returns, and counts the
+// validations it serves. Its Handler serves:
+//
+//	GET    /validate?email=...       the Result, as JSON
+//	GET    /stats                    Stats
+//	GET    /health                   Validator.Health; 503 when unhealthy
+//	POST   /admin/cache/purge        drop the cached DNS answers
+//	POST   /admin/smtp/pause         pause the SMTP level
+//	POST   /admin/smtp/resume        resume it
+//	POST   /admin/state/save         save the state now
+//	GET    /admin/domains/{domain}   Validator.DomainState of the domain
+//	DELETE /admin/domains/{domain}   Validator.Invalidate the domain
+//
+// The admin endpoints require "Authorization: Bearer <AdminToken>" and are
+// not served at all without an AdminToken. /validate probes mail servers
+// on the caller's behalf: bind the listener to loopback or a private
+// interface, or set a ValidateToken it then requires the same way.
+package daemon
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/internal/features"
+)
+
+func init() {
+	features.Register(emailkit.Feature{
+		Name:        "daemon",
+		Stability:   emailkit.StabilityStable,
+		Package:     "github.com/optimode/emailkit/daemon",
+		Description: "long-lived service with state persistence, stats and admin endpoints",
+	})
+}
+
+// Options configures a Daemon.
+type Options struct {
+	// StatePath is the file the warm state is restored from and saved to.
+	// Default: "" (not persisted)
+	StatePath string
+	// SaveInterval is how often Run saves the state. Default: 5m
+	SaveInterval time.Duration
+	// AdminToken is the bearer token of the admin endpoints. Default: ""
+	// (admin endpoints not served)
+	AdminToken string
+	// ValidateToken is the bearer token /validate requires. Default: ""
+	// (open to anyone who can reach the listener)
+	ValidateToken string
+	// Health configures the /health endpoint.
+	Health emailkit.HealthOptions
+	// Now is injectable for testing. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Stats counts what a Daemon has done since it was created.
+type Stats struct {
+	Started time.Time     `json:"started"`
+	Uptime  time.Duration `json:"uptime"`
+	// Validations counts Validate calls: Valid and Invalid results, and
+	// Errors.
+	Validations int64 `json:"validations"`
+	Valid       int64 `json:"valid"`
+	Invalid     int64 `json:"invalid"`
+	Errors      int64 `json:"errors"`
+	// CachePurges counts PurgeCache calls.
+	CachePurges int64 `json:"cachePurges"`
+	// SMTPPaused is set while the SMTP level is paused.
+	SMTPPaused bool `json:"smtpPaused"`
+	// LastSave is when the state was last saved; zero if never.
+	LastSave time.Time `json:"lastSave,omitzero"`
+	// SaveError is the error of the last save attempt, if it failed.
+	SaveError string `json:"saveError,omitempty"`
+}
+
+// Daemon owns a Validator for the lifetime of a service. It is safe for
+// concurrent use.
+type Daemon struct {
+	v       *emailkit.Validator
+	opts    Options
+	started time.Time
+
+	valid, invalid, failures atomic.Int64
+	purges                   atomic.Int64
+
+	saveMu   sync.Mutex // serializes saves; guards lastSave and saveErr
+	lastSave time.Time
+	saveErr  error
+}
+
+// New returns a Daemon for v and restores the state saved at
+// opts.StatePath, if the file exists. The Daemon does not close v.
+func New(v *emailkit.Validator, opts Options) (*Daemon, error) {
+	if opts.SaveInterval <= 0 {
+		opts.SaveInterval = 5 * time.Minute
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	d := &Daemon{v: v, opts: opts, started: opts.Now()}
+	if opts.StatePath == "" {
+		return d, nil
+	}
+	f, err := os.Open(opts.StatePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("daemon: restore state: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := v.ImportState(f); err != nil {
+		return nil, fmt.Errorf("daemon: restore state from %s: %w", opts.StatePath, err)
+	}
+	return d, nil
+}
+
+// Validator returns the Validator the Daemon owns.
+func (d *Daemon) Validator() *emailkit.Validator {
+	return d.v
+}
+
+// Run saves the state every SaveInterval until ctx is done, then saves it
+// once more and returns that save's error. Without a StatePath it only
+// waits for ctx.
+func (d *Daemon) Run(ctx context.Context) error {
+	if d.opts.StatePath == "" {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := time.NewTicker(d.opts.SaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = d.Save() // recorded in Stats
+		case <-ctx.Done():
+			return d.Save()
+		}
+	}
+}
+
+// Save writes the state to StatePath, replacing the file atomically. It is
+// a no-op without a StatePath.
+func (d *Daemon) Save() error {
+	if d.opts.StatePath == "" {
+		return nil
+	}
+	d.saveMu.Lock()
+	defer d.saveMu.Unlock()
+	err := d.writeState()
+	if err == nil {
+		d.lastSave = d.opts.Now()
+	}
+	d.saveErr = err
+	return err
+}
+
+// writeState writes the state to a temporary file next to StatePath and
+// renames it over StatePath, so a crash never leaves a truncated file.
+func (d *Daemon) writeState() error {
+	dir, base := filepath.Split(d.opts.StatePath)
+	if dir == "" {
+		dir = "."
+	}
+	f, err := os.CreateTemp(dir, base+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("daemon: save state: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }() // fails harmlessly after the rename
+	if err := d.v.ExportState(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("daemon: save state: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("daemon: save state: %w", err)
+	}
+	if err := os.Rename(f.Name(), d.opts.StatePath); err != nil {
+		return fmt.Errorf("daemon: save state: %w", err)
+	}
+	return nil
+}
+
+// Validate validates email with the Validator and counts the outcome.
+func (d *Daemon) Validate(ctx context.Context, email string) (emailkit.Result, error) {
+	r, err := d.v.Validate(ctx, email)
+	switch {
+	case err != nil:
+		d.failures.Add(1)
+	case r.Valid:
+		d.valid.Add(1)
+	default:
+		d.invalid.Add(1)
+	}
+	return r, err
+}
+
+// PurgeCache drops the Validator's cached DNS answers and returns how many
+// were dropped.
+func (d *Daemon) PurgeCache() int {
+	d.purges.Add(1)
+	return d.v.PurgeDNSCache()
+}
+
+// Stats returns the counters.
+func (d *Daemon) Stats() Stats {
+	s := Stats{
+		Started:     d.started,
+		Uptime:      d.opts.Now().Sub(d.started),
+		Valid:       d.valid.Load(),
+		Invalid:     d.invalid.Load(),
+		Errors:      d.failures.Load(),
+		CachePurges: d.purges.Load(),
+		SMTPPaused:  d.v.SMTPPaused(),
+	}
+	s.Validations = s.Valid + s.Invalid + s.Errors
+	d.saveMu.Lock()
+	s.LastSave = d.lastSave
+	if d.saveErr != nil {
+		s.SaveError = d.saveErr.Error()
+	}
+	d.saveMu.Unlock()
+	return s
+}
+
+// Handler returns the HTTP handler serving the endpoints listed in the
+// package documentation.
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /validate", func(w http.ResponseWriter, r *http.Request) {
+		if d.opts.ValidateToken != "" && !authorized(r, d.opts.ValidateToken) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("invalid validate token"))
+			return
+		}
+		email := r.URL.Query().Get("email")
+		if email == "" {
+			writeError(w, http.StatusBadRequest, errors.New("missing email parameter"))
+			return
+		}
+		res, err := d.Validate(r.Context(), email)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	})
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, d.Stats())
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		h := d.v.Health(r.Context(), d.opts.Health)
+		status := http.StatusOK
+		if !h.Healthy {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, h)
+	})
+
+	if d.opts.AdminToken == "" {
+		return mux
+	}
+	admin := func(pattern string, f func(r *http.Request) (any, error)) {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			if !authorized(r, d.opts.AdminToken) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, errors.New("invalid admin token"))
+				return
+			}
+			body, err := f(r)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, body)
+		})
+	}
+	admin("POST /admin/cache/purge", func(*http.Request) (any, error) {
+		return map[string]int{"purged": d.PurgeCache()}, nil
+	})
+	admin("POST /admin/smtp/pause", func(*http.Request) (any, error) {
+		d.v.PauseSMTP(true)
+		return map[string]bool{"smtpPaused": d.v.SMTPPaused()}, nil
+	})
+	admin("POST /admin/smtp/resume", func(*http.Request) (any, error) {
+		d.v.PauseSMTP(false)
+		return map[string]bool{"smtpPaused": d.v.SMTPPaused()}, nil
+	})
+	admin("POST /admin/state/save", func(*http.Request) (any, error) {
+		if err := d.Save(); err != nil {
+			return nil, err
+		}
+		return d.Stats(), nil
+	})
+	admin("GET /admin/domains/{domain}", func(r *http.Request) (any, error) {
+		return d.v.DomainState(r.PathValue("domain")), nil
+	})
+	admin("DELETE /admin/domains/{domain}", func(r *http.Request) (any, error) {
+		return d.v.Invalidate(r.Context(), r.PathValue("domain"))
+	})
+	return mux
+}
+
+// authorized reports whether r carries token as its bearer token.
+func authorized(r *http.Request, token string) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,192 @@
+package daemon_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/daemon"
+)
+
+func newValidator(t *testing.T) (*emailkit.Validator, *emailkit.FakeNetwork) {
+	t.Helper()
+	n := emailkit.NewFakeNetwork().AddDomain("example.com", emailkit.FakeDomain{Mailboxes: []string{"alice"}})
+	v := emailkit.NewOffline(n).WithDNS().WithSMTP(emailkit.SMTPOptions{HeloDomain: "myapp.com", MailFrom: "verify@myapp.com"})
+	t.Cleanup(func() { _ = v.Close() })
+	return v, n
+}
+
+// do sends a request to h and decodes the JSON answer into out.
+func do(t *testing.T, h http.Handler, method, target, token string, out any) int {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if out != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out), rec.Body.String())
+	}
+	return rec.Code
+}
+
+func TestDaemon_ValidateAndStats(t *testing.T) {
+	v, _ := newValidator(t)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	d, err := daemon.New(v, daemon.Options{Now: func() time.Time { return now }})
+	require.NoError(t, err)
+	h := d.Handler()
+
+	var res emailkit.Result
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodGet, "/validate?email=alice@example.com", "", &res))
+	assert.True(t, res.Valid)
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodGet, "/validate?email=bob@example.com", "", &res))
+	assert.False(t, res.Valid)
+	assert.Equal(t, http.StatusBadRequest, do(t, h, http.MethodGet, "/validate", "", nil))
+
+	now = now.Add(time.Minute)
+	var st daemon.Stats
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodGet, "/stats", "", &st))
+	assert.Equal(t, int64(2), st.Validations)
+	assert.Equal(t, int64(1), st.Valid)
+	assert.Equal(t, int64(1), st.Invalid)
+	assert.Equal(t, time.Minute, st.Uptime)
+	assert.True(t, st.LastSave.IsZero())
+}
+
+func TestDaemon_Admin(t *testing.T) {
+	v, n := newValidator(t)
+	d, err := daemon.New(v, daemon.Options{AdminToken: "t0ken"})
+	require.NoError(t, err)
+	h := d.Handler()
+
+	assert.Equal(t, http.StatusUnauthorized, do(t, h, http.MethodPost, "/admin/smtp/pause", "", nil))
+	assert.Equal(t, http.StatusUnauthorized, do(t, h, http.MethodPost, "/admin/smtp/pause", "wrong", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, do(t, h, http.MethodGet, "/admin/smtp/pause", "t0ken", nil))
+
+	var paused map[string]bool
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodPost, "/admin/smtp/pause", "t0ken", &paused))
+	assert.True(t, paused["smtpPaused"])
+	r, err := d.Validate(context.Background(), "bob@example.com")
+	require.NoError(t, err)
+	smtp, _ := r.CheckFor(emailkit.LevelSMTP)
+	assert.Equal(t, emailkit.CodeSkipped, smtp.Code)
+	assert.Empty(t, n.Probed())
+
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodPost, "/admin/smtp/resume", "t0ken", &paused))
+	assert.False(t, paused["smtpPaused"])
+	assert.False(t, d.Stats().SMTPPaused)
+
+	var purged map[string]int
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodPost, "/admin/cache/purge", "t0ken", &purged))
+	assert.Equal(t, 1, purged["purged"])
+	assert.Equal(t, int64(1), d.Stats().CachePurges)
+}
+
+func TestDaemon_AdminDomains(t *testing.T) {
+	v, _ := newValidator(t)
+	d, err := daemon.New(v, daemon.Options{AdminToken: "t0ken"})
+	require.NoError(t, err)
+	h := d.Handler()
+	_, err = d.Validate(context.Background(), "alice@example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, do(t, h, http.MethodGet, "/admin/domains/example.com", "", nil))
+
+	var st emailkit.DomainState
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodGet, "/admin/domains/example.com", "t0ken", &st))
+	require.NotNil(t, st.MX)
+	assert.Equal(t, map[string]int{"mx.example.com": 1}, st.IdleConns)
+
+	var inv emailkit.Invalidated
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodDelete, "/admin/domains/example.com", "t0ken", &inv))
+	assert.Equal(t, emailkit.Invalidated{Domain: "example.com", DNS: 1, Conns: 1}, inv)
+	assert.Nil(t, v.DomainState("example.com").MX)
+}
+
+func TestDaemon_ValidateToken(t *testing.T) {
+	v, _ := newValidator(t)
+	d, err := daemon.New(v, daemon.Options{ValidateToken: "v4lid", AdminToken: "t0ken"})
+	require.NoError(t, err)
+	h := d.Handler()
+
+	assert.Equal(t, http.StatusUnauthorized, do(t, h, http.MethodGet, "/validate?email=alice@example.com", "", nil))
+	assert.Equal(t, http.StatusUnauthorized, do(t, h, http.MethodGet, "/validate?email=alice@example.com", "t0ken", nil))
+	var res emailkit.Result
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodGet, "/validate?email=alice@example.com", "v4lid", &res))
+	assert.True(t, res.Valid)
+	assert.Equal(t, int64(1), d.Stats().Validations, "rejected requests don't validate")
+}
+
+func TestDaemon_AdminDisabled(t *testing.T) {
+	v, _ := newValidator(t)
+	d, err := daemon.New(v, daemon.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, do(t, d.Handler(), http.MethodPost, "/admin/cache/purge", "", nil))
+}
+
+func TestDaemon_Health(t *testing.T) {
+	n := emailkit.NewFakeNetwork().AddDomain("example.com", emailkit.FakeDomain{})
+	d, err := daemon.New(emailkit.NewOffline(n).WithDNS(), daemon.Options{Health: emailkit.HealthOptions{DNSProbe: "mx.example.com"}})
+	require.NoError(t, err)
+	var h emailkit.Health
+	assert.Equal(t, http.StatusOK, do(t, d.Handler(), http.MethodGet, "/health", "", &h))
+	assert.True(t, h.Healthy)
+
+	d, err = daemon.New(emailkit.NewOffline(n).WithDNS(), daemon.Options{Health: emailkit.HealthOptions{DNSProbe: "nowhere.example"}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, do(t, d.Handler(), http.MethodGet, "/health", "", &h))
+}
+
+func TestDaemon_StatePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	v, _ := newValidator(t)
+	d, err := daemon.New(v, daemon.Options{StatePath: path, SaveInterval: time.Millisecond})
+	require.NoError(t, err)
+	_, err = d.Validate(context.Background(), "alice@example.com")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx) }()
+	require.Eventually(t, func() bool { return !d.Stats().LastSave.IsZero() }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	// A restarted daemon answers from the restored MX cache: the domain
+	// is gone from the network now
+	n := emailkit.NewFakeNetwork()
+	v2 := emailkit.NewOffline(n).WithDNS()
+	_, err = daemon.New(v2, daemon.Options{StatePath: path})
+	require.NoError(t, err)
+	r, err := v2.Validate(context.Background(), "alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, r.Valid, "%+v", r.Checks)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func TestDaemon_StateErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+	v, _ := newValidator(t)
+	_, err := daemon.New(v, daemon.Options{StatePath: path})
+	assert.ErrorContains(t, err, "daemon: restore state")
+
+	d, err := daemon.New(v, daemon.Options{StatePath: filepath.Join(t.TempDir(), "missing", "state.json")})
+	require.NoError(t, err)
+	assert.Error(t, d.Save())
+	assert.Contains(t, d.Stats().SaveError, "daemon: save state")
+}
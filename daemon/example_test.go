@@ -0,0 +1,55 @@
+package daemon_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os/signal"
+	"syscall"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/daemon"
+)
+
+func ExampleDaemon_Handler() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+
+	v := emailkit.New().WithDNS().WithSMTP(emailkit.SMTPOptions{HeloDomain: "myapp.com", MailFrom: "verify@myapp.com"})
+	defer func() { _ = v.Close() }()
+	d, err := daemon.New(v, daemon.Options{
+		StatePath:  "/var/lib/emailkit/state.json",
+		AdminToken: "change me",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	srv := &http.Server{Addr: ":8080", Handler: d.Handler()}
+	go func() { _ = srv.ListenAndServe() }()
+	// Saves the state every five minutes, and once more on SIGTERM
+	if err := d.Run(ctx); err != nil {
+		log.Print(err)
+	}
+	_ = srv.Shutdown(context.Background())
+}
+
+func ExampleDaemon_Stats() {
+	n := emailkit.NewFakeNetwork().AddDomain("example.com", emailkit.FakeDomain{Mailboxes: []string{"alice"}})
+	d, _ := daemon.New(emailkit.NewOffline(n).WithDNS(), daemon.Options{})
+
+	srv := httptest.NewServer(d.Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/validate?email=alice@example.com")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	_ = resp.Body.Close()
+
+	st := d.Stats()
+	fmt.Println(resp.StatusCode, st.Validations, st.Valid)
+	// Output: 200 1 1
+}
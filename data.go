@@ -0,0 +1,53 @@
+package emailkit
+
+import (
+	"io"
+
+	"github.com/optimode/emailkit/internal/compliance"
+	"github.com/optimode/emailkit/internal/disposable"
+	"github.com/optimode/emailkit/internal/features"
+	"github.com/optimode/emailkit/internal/freemail"
+)
+
+// LoadDisposableDomains replaces the disposable domain list of the domain
+// level, embedded or not, with the domains read from r: one per line,
+// lines starting with "#" are comments. It returns how many domains it
+// read; on a read error the list is left as it was. Validators pick up the
+// new list immediately. Built with the emailkit_nodisposable tag, the
+// binary has no embedded list and the level only matches what was loaded.
+func LoadDisposableDomains(r io.Reader) (int, error) {
+	return disposable.Load(r)
+}
+
+// LoadFreeProviders replaces the free mailbox provider list of the
+// free_provider level, embedded or not, with the domains read from r, in
+// the format of LoadDisposableDomains. Validators pick up the new list
+// immediately. The emailkit_nofreemail build tag leaves the embedded list
+// out of the binary.
+func LoadFreeProviders(r io.Reader) (int, error) {
+	return freemail.Load(r)
+}
+
+// LoadCompliancePolicy replaces the compliance policy pack, embedded or
+// not, with the one read from r: "<sanctioned|restricted> <domain or TLD>
+// [note]" per line, lines starting with "#" are comments. It returns how
+// many entries it read; on an error the pack is left as it was. Validators
+// pick up the new pack immediately. The emailkit_nocompliance build tag
+// leaves the embedded pack out of the binary.
+func LoadCompliancePolicy(r io.Reader) (int, error) {
+	return compliance.Load(r)
+}
+
+// init reports the embedded datasets compiled into the binary.
+func init() {
+	const pkg = "github.com/optimode/emailkit"
+	if disposable.Embedded {
+		features.Register(Feature{Name: "data.disposable", Stability: StabilityStable, Package: pkg, Description: "embedded disposable domain list"})
+	}
+	if freemail.Embedded {
+		features.Register(Feature{Name: "data.freemail", Stability: StabilityStable, Package: pkg, Description: "embedded free mailbox provider list"})
+	}
+	if compliance.Embedded {
+		features.Register(Feature{Name: "data.compliance", Stability: StabilityStable, Package: pkg, Description: "embedded compliance policy pack"})
+	}
+}
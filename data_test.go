@@ -0,0 +1,99 @@
+package emailkit_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/internal/compliance"
+	"github.com/optimode/emailkit/internal/disposable"
+	"github.com/optimode/emailkit/internal/freemail"
+)
+
+// restore reloads an embedded dataset from its source file.
+func restore(t *testing.T, load func(io.Reader) (int, error), path string) {
+	t.Cleanup(func() { loadFile(t, load, path) })
+}
+
+// loadFile loads a dataset from its source file, e.g. one left out by an
+// emailkit_no* build tag.
+func loadFile(t *testing.T, load func(io.Reader) (int, error), path string) {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	_, err = load(f)
+	require.NoError(t, err)
+}
+
+func TestLoadDisposableDomains(t *testing.T) {
+	restore(t, emailkit.LoadDisposableDomains, "internal/disposable/list.txt")
+	v := emailkit.New().WithDomain()
+	ctx := context.Background()
+
+	n, err := emailkit.LoadDisposableDomains(strings.NewReader("# runtime list\nThrowaway.example\n\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	res, err := v.Validate(ctx, "user@throwaway.example")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	res, err = v.Validate(ctx, "user@mailinator.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid, "the loaded list replaces the embedded one")
+}
+
+func TestLoadFreeProviders(t *testing.T) {
+	restore(t, emailkit.LoadFreeProviders, "internal/freemail/list.txt")
+
+	_, err := emailkit.LoadFreeProviders(strings.NewReader("webmail.example\n"))
+	require.NoError(t, err)
+
+	res, err := emailkit.New().WithFreeProvider().Validate(context.Background(), "user@webmail.example")
+	require.NoError(t, err)
+	assert.True(t, res.FreeProvider)
+}
+
+func TestLoadCompliancePolicy(t *testing.T) {
+	restore(t, emailkit.LoadCompliancePolicy, "internal/compliance/policy.txt")
+	loadFile(t, emailkit.LoadCompliancePolicy, "internal/compliance/policy.txt")
+	v := emailkit.New().WithCompliancePolicy()
+	ctx := context.Background()
+
+	_, err := emailkit.LoadCompliancePolicy(strings.NewReader("embargoed example\n"))
+	assert.Error(t, err)
+	assert.NotEmpty(t, emailkit.CompliancePolicy(), "a bad policy leaves the pack as it was")
+
+	n, err := emailkit.LoadCompliancePolicy(strings.NewReader("sanctioned embargoed.example  Test\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []emailkit.ComplianceEntry{{Domain: "embargoed.example", Code: emailkit.CodeSanctioned, Note: "Test"}}, emailkit.CompliancePolicy())
+
+	// Validators built before the load use the new pack
+	res, err := v.Validate(ctx, "user@embargoed.example")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	res, err = v.Validate(ctx, "user@example.ir")
+	require.NoError(t, err)
+	assert.True(t, res.Valid, "the loaded pack replaces the embedded one")
+}
+
+func TestFeatures_EmbeddedData(t *testing.T) {
+	var names []string
+	for _, f := range emailkit.Features() {
+		names = append(names, f.Name)
+	}
+	for name, embedded := range map[string]bool{
+		"data.disposable": disposable.Embedded,
+		"data.freemail":   freemail.Embedded,
+		"data.compliance": compliance.Embedded,
+	} {
+		assert.Equal(t, embedded, slices.Contains(names, name), name)
+	}
+}
@@ -17,19 +17,131 @@
 //	    Validate(ctx, "user@example.com")
 package emailkit
 
-import "github.com/optimode/emailkit/types"
+import (
+	"context"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
 
 // CheckResult is a re-export from the types package so that consumers
 // don't need to import the types package directly.
 type CheckResult = types.CheckResult
 
+// ProbeAttempt is one MX host tried by the SMTP level, as listed in
+// CheckResult.Attempts.
+type ProbeAttempt = types.ProbeAttempt
+
+// Email is the parsed email address handed to every level: the raw input,
+// the local part, and the domain in both ASCII/Punycode (Domain) and
+// Unicode (DomainUnicode) form. Valid is false if the input could not be parsed.
+type Email = parse.Email
+
 // CheckLevel is a re-export.
 type CheckLevel = types.CheckLevel
 
+// CheckCode is a re-export.
+type CheckCode = types.CheckCode
+
+// Severity is a re-export.
+type Severity = types.Severity
+
+// Category is a re-export.
+type Category = types.Category
+
+// Resolver is a re-export of the DNS resolver interface used by the network
+// levels. *net.Resolver satisfies it.
+type Resolver = check.Resolver
+
+// Findings is a re-export of what the levels of one validation learned
+// about the domain: the DNS level's MX records and mailbox provider, or
+// that the domain accepts no mail. Custom levels read it with
+// ValidationFrom(ctx).Findings().
+type Findings = check.Findings
+
+// ValidationContext is a re-export of the state one validation shares
+// along its levels: the parsed input, its Findings, the results of the
+// levels that ran so far and scratch values. Levels read it with
+// ValidationFrom(ctx).
+type ValidationContext = check.ValidationContext
+
+// ValidationFrom returns the ValidationContext of a validation's ctx, or
+// nil outside one. A nil *ValidationContext reports nothing.
+func ValidationFrom(ctx context.Context) *ValidationContext { return check.ValidationFrom(ctx) }
+
 // Level constants re-exported.
 const (
-	LevelSyntax = types.LevelSyntax
-	LevelDNS    = types.LevelDNS
-	LevelDomain = types.LevelDomain
-	LevelSMTP   = types.LevelSMTP
+	LevelSyntax       = types.LevelSyntax
+	LevelDNS          = types.LevelDNS
+	LevelDomain       = types.LevelDomain
+	LevelSMTP         = types.LevelSMTP
+	LevelNS           = types.LevelNS
+	LevelRegistration = types.LevelRegistration
+	LevelGeo          = types.LevelGeo
+	LevelCompliance   = types.LevelCompliance
+	LevelSender       = types.LevelSender
+	LevelRole         = types.LevelRole
+	LevelFreeProvider = types.LevelFreeProvider
+	LevelVerification = types.LevelVerification
+	LevelDNSBL        = types.LevelDNSBL
+	LevelSPF          = types.LevelSPF
+	LevelPipeline     = types.LevelPipeline
+	LevelAllowlist    = types.LevelAllowlist
+	LevelBlocklist    = types.LevelBlocklist
+)
+
+// Severity constants re-exported.
+const (
+	SeverityError = types.SeverityError
+	SeverityWarn  = types.SeverityWarn
+	SeverityInfo  = types.SeverityInfo
+)
+
+// Category constants re-exported.
+const (
+	CategorySyntax         = types.CategorySyntax
+	CategoryInfrastructure = types.CategoryInfrastructure
+	CategoryReputation     = types.CategoryReputation
+	CategoryPolicy         = types.CategoryPolicy
+)
+
+// Code constants re-exported.
+const (
+	CodeCancelled = types.CodeCancelled
+	CodeError     = types.CodeError
+
+	CodeAccepted           = types.CodeAccepted
+	CodeMailboxUnknown     = types.CodeMailboxUnknown
+	CodeMailboxFull        = types.CodeMailboxFull
+	CodeMailboxDisabled    = types.CodeMailboxDisabled
+	CodeBadDomain          = types.CodeBadDomain
+	CodeGreylisted         = types.CodeGreylisted
+	CodeRateLimited        = types.CodeRateLimited
+	CodeBlocked            = types.CodeBlocked
+	CodePolicy             = types.CodePolicy
+	CodeServiceUnavailable = types.CodeServiceUnavailable
+	CodeTemporary          = types.CodeTemporary
+	CodeUnknown            = types.CodeUnknown
+	CodeCatchAll           = types.CodeCatchAll
+	CodeDeferred           = types.CodeDeferred
+	CodeSkipped            = types.CodeSkipped
+	CodeParked             = types.CodeParked
+	CodeDNSTimeout         = types.CodeDNSTimeout
+	CodeDNSError           = types.CodeDNSError
+	CodeTimedOut           = types.CodeTimedOut
+	CodeAllowlisted        = types.CodeAllowlisted
+	CodeBlocklisted        = types.CodeBlocklisted
+	CodeGeoBlocked         = types.CodeGeoBlocked
+	CodeSanctioned         = types.CodeSanctioned
+	CodeRestricted         = types.CodeRestricted
+	CodeSenderRole         = types.CodeSenderRole
+	CodeSenderNoBounce     = types.CodeSenderNoBounce
+	CodeSenderSPFFail      = types.CodeSenderSPFFail
+	CodeSenderNoDMARC      = types.CodeSenderNoDMARC
+	CodeRole               = types.CodeRole
+	CodeFreeProvider       = types.CodeFreeProvider
+	CodeTokenMissing       = types.CodeTokenMissing
+	CodeDNSBLListed        = types.CodeDNSBLListed
+	CodeSPFMissing         = types.CodeSPFMissing
 )
@@ -0,0 +1,167 @@
+package emailkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// FieldNaming is the key style of an Encoder.
+type FieldNaming int
+
+const (
+	// CamelCase keys, e.g. "mxHost", as json.Marshal writes them.
+	CamelCase FieldNaming = iota
+	// SnakeCase keys, e.g. "mx_host". Meta keys are data and keep their
+	// spelling.
+	SnakeCase
+)
+
+// EncoderOptions shapes the JSON an Encoder writes.
+type EncoderOptions struct {
+	// Naming is the key style. Default: CamelCase
+	Naming FieldNaming
+	// ZeroSMTPCode writes smtpCode 0 on checks without an SMTP reply
+	// instead of leaving the key out, for schemas with a non-null column.
+	// Default: false
+	ZeroSMTPCode bool
+	// Timestamp adds a "timestamp" key, the time of encoding in RFC 3339
+	// (UTC), to every result. Default: false
+	Timestamp bool
+}
+
+// Encoder writes Results as JSON, one per line, shaped by EncoderOptions
+// to match an existing schema (e.g. a data warehouse table) without
+// wrapper structs. The fields and their order are those of json.Marshal;
+// only the key style and empty-value handling change.
+type Encoder struct {
+	w    io.Writer
+	opts EncoderOptions
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer, opts ...EncoderOptions) *Encoder {
+	e := &Encoder{w: w}
+	if len(opts) > 0 {
+		e.opts = opts[0]
+	}
+	return e
+}
+
+// Encode writes r followed by a newline.
+func (e *Encoder) Encode(r Result) error {
+	obj := e.object(reflect.ValueOf(r))
+	if e.opts.Timestamp {
+		obj = append(object{{e.key("timestamp"), time.Now().UTC().Format(time.RFC3339)}}, obj...)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+// object converts a struct to an ordered object, following its json tags.
+func (e *Encoder) object(v reflect.Value) object {
+	var out object
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fv := v.Field(i)
+		omit := opts == "omitempty" && !(e.opts.ZeroSMTPCode && name == "smtpCode")
+		if omit && isEmpty(fv) {
+			continue
+		}
+		out = append(out, member{e.key(name), e.value(fv)})
+	}
+	return out
+}
+
+// value converts structs, and slices of them, to ordered objects.
+func (e *Encoder) value(v reflect.Value) any {
+	switch {
+	case v.Kind() == reflect.Struct && v.Type() != reflect.TypeFor[time.Time]():
+		return e.object(v)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct && !v.IsNil():
+		out := make([]object, v.Len())
+		for i := range out {
+			out[i] = e.object(v.Index(i))
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// key renders a camelCase json tag name in the configured style.
+func (e *Encoder) key(name string) string {
+	if e.opts.Naming != SnakeCase {
+		return name
+	}
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isEmpty reports whether omitempty drops v, as encoding/json decides it.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		return false
+	}
+	return v.IsZero()
+}
+
+// object is a JSON object that keeps the order of its members.
+type object []member
+
+type member struct {
+	key   string
+	value any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
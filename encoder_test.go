@@ -0,0 +1,84 @@
+package emailkit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit"
+)
+
+func encoderResult() emailkit.Result {
+	return emailkit.Result{
+		Email:                     "alice@example.com",
+		Valid:                     false,
+		Reachability:              emailkit.ReachabilityUndeliverable,
+		DeliverabilityProbability: 0.5,
+		Checks: []emailkit.CheckResult{
+			{Level: emailkit.LevelSyntax, Passed: true, Details: "syntax ok", Severity: emailkit.SeverityInfo},
+			{
+				Level: emailkit.LevelSMTP, Passed: false, MXHost: "mx.example.com", SMTPCode: 550,
+				Code: emailkit.CodeMailboxUnknown, Meta: map[string]string{"probe": "rcpt", "catchAll": "no"},
+				Attempts: []emailkit.ProbeAttempt{{Host: "mx.example.com", Code: 550, Latency: time.Millisecond}},
+			},
+		},
+	}
+}
+
+func TestEncoder_MatchesJSONMarshal(t *testing.T) {
+	r := encoderResult()
+	var buf bytes.Buffer
+	assert.NoError(t, emailkit.NewEncoder(&buf).Encode(r))
+
+	want, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want)+"\n", buf.String())
+}
+
+func TestEncoder_SnakeCase(t *testing.T) {
+	var buf bytes.Buffer
+	enc := emailkit.NewEncoder(&buf, emailkit.EncoderOptions{Naming: emailkit.SnakeCase})
+	assert.NoError(t, enc.Encode(encoderResult()))
+
+	var out map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Contains(t, out, "deliverability_probability")
+	assert.NotContains(t, out, "deliverabilityProbability")
+	smtp := out["checks"].([]any)[1].(map[string]any)
+	assert.Equal(t, "mx.example.com", smtp["mx_host"])
+	assert.Equal(t, float64(550), smtp["smtp_code"])
+	assert.Equal(t, "no", smtp["meta"].(map[string]any)["catchAll"], "meta keys are data")
+	assert.Equal(t, float64(550), smtp["attempts"].([]any)[0].(map[string]any)["code"])
+}
+
+func TestEncoder_ZeroSMTPCode(t *testing.T) {
+	r := encoderResult()
+	for _, zero := range []bool{false, true} {
+		var buf bytes.Buffer
+		assert.NoError(t, emailkit.NewEncoder(&buf, emailkit.EncoderOptions{ZeroSMTPCode: zero}).Encode(r))
+
+		var out struct{ Checks []map[string]any }
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		_, ok := out.Checks[0]["smtpCode"]
+		assert.Equal(t, zero, ok)
+		assert.NotContains(t, out.Checks[0], "mxHost", "other empty fields stay out")
+	}
+}
+
+func TestEncoder_Timestamp(t *testing.T) {
+	var buf bytes.Buffer
+	enc := emailkit.NewEncoder(&buf, emailkit.EncoderOptions{Timestamp: true})
+	before := time.Now().Truncate(time.Second)
+	assert.NoError(t, enc.Encode(encoderResult()))
+	assert.NoError(t, enc.Encode(encoderResult()))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	var out struct{ Timestamp time.Time }
+	assert.NoError(t, json.Unmarshal(lines[0], &out))
+	assert.False(t, out.Timestamp.Before(before))
+	assert.Equal(t, time.UTC, out.Timestamp.Location())
+}
@@ -1,13 +1,92 @@
 package emailkit
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrNoChecksConfigured is returned when Validate() is called
 	// but no validation level is configured (not even syntax).
 	ErrNoChecksConfigured = errors.New("emailkit: no validation checks configured")
 
-	// ErrInvalidSMTPOptions is returned when WithSMTP is called
-	// but HeloDomain or MailFrom is missing.
+	// ErrInvalidSMTPOptions is wrapped by the *OptionsError returned when
+	// WithSMTP is called without HeloDomain or MailFrom, or with an
+	// out-of-range option.
 	ErrInvalidSMTPOptions = errors.New("emailkit: SMTPOptions requires HeloDomain and MailFrom")
+
+	// ErrUnknownLevel is returned when a pipeline refers to a level name
+	// that is neither built in nor registered with RegisterLevel.
+	ErrUnknownLevel = errors.New("emailkit: unknown validation level")
+
+	// ErrInvalidPrivacyOptions is wrapped by the *OptionsError returned
+	// when WithPrivacy is called with neither a Salt nor a Hasher.
+	ErrInvalidPrivacyOptions = errors.New("emailkit: PrivacyOptions requires Salt or Hasher")
+
+	// ErrInvalidSchedule is wrapped by the *OptionsError returned when
+	// SMTPOptions.Schedule has a malformed window or a negative budget.
+	ErrInvalidSchedule = errors.New("emailkit: invalid probe schedule")
+
+	// ErrUnknownTenant is returned by Manager methods for a tenant name
+	// that was not added with AddTenant.
+	ErrUnknownTenant = errors.New("emailkit: unknown tenant")
+
+	// ErrQuotaExceeded is returned by Manager.Validate and ValidateMany
+	// once a tenant's daily validations are spent.
+	ErrQuotaExceeded = errors.New("emailkit: tenant quota exceeded")
+
+	// ErrInvalidBlocklist is returned when WithBlocklist is given a
+	// pattern that is not a valid regular expression, or
+	// WithBlocklistHashes a hash that is not hex SHA-256.
+	ErrInvalidBlocklist = errors.New("emailkit: invalid blocklist entry")
+
+	// ErrCanaryFailed is wrapped by the *CanaryError ValidateMany returns
+	// when ConcurrencyOptions.Canaries were misclassified.
+	ErrCanaryFailed = errors.New("emailkit: canary addresses misclassified")
+
+	// ErrStopped is wrapped by the *StoppedError a bulk run returns when
+	// ConcurrencyOptions.Stop was closed before every email was validated.
+	ErrStopped = errors.New("emailkit: bulk run stopped")
+
+	// ErrThresholdExceeded is wrapped by the *ThresholdError Summary.Check
+	// returns when a run exceeds its Thresholds.
+	ErrThresholdExceeded = errors.New("emailkit: result thresholds exceeded")
+
+	// ErrQueueFull is returned by TwoPhase.Validate, along with the fast
+	// phase's Result, when the deep phase could not be scheduled because
+	// TwoPhaseOptions.QueueSize validations are already waiting.
+	ErrQueueFull = errors.New("emailkit: deep validation queue full")
+
+	// ErrClosed is returned by TwoPhase.Validate, along with the fast
+	// phase's Result, after Close.
+	ErrClosed = errors.New("emailkit: closed")
 )
+
+// OptionsError is the configuration error for an option value that
+// violates a constraint, returned on Validate. Use errors.As to find the
+// option at fault; it also matches the sentinel of its options type, if
+// there is one (errors.Is(err, ErrInvalidSMTPOptions)).
+type OptionsError struct {
+	// Field is the option, qualified by its type, e.g. "SMTPOptions.Port"
+	// or "SMTPOptions.Identities[1].MailFrom".
+	Field string
+	// Value is the rejected value; nil for a missing required option.
+	Value any
+	// Constraint is the rule violated, e.g. "must be a port number (1-65535)".
+	Constraint string
+	// Err is the sentinel wrapped, or nil.
+	Err error
+}
+
+func (e *OptionsError) Error() string {
+	switch v := e.Value.(type) {
+	case nil:
+		return fmt.Sprintf("emailkit: %s: %s", e.Field, e.Constraint)
+	case string:
+		return fmt.Sprintf("emailkit: %s %q: %s", e.Field, v, e.Constraint)
+	default:
+		return fmt.Sprintf("emailkit: %s %v: %s", e.Field, v, e.Constraint)
+	}
+}
+
+func (e *OptionsError) Unwrap() error { return e.Err }
@@ -0,0 +1,137 @@
+package emailkit
+
+import (
+	"time"
+
+	"github.com/optimode/emailkit/check"
+)
+
+// Event is a typed notification from a Validator: ValidationStarted,
+// CheckCompleted, SMTPDialed, CacheHit, Throttled or DisposableCandidate.
+// Subscribers switch on the concrete type; more event types may be added
+// later.
+//
+// In privacy mode (see WithPrivacy) the Email fields hold the hash and
+// check details are redacted, as in Result.
+type Event interface {
+	event()
+}
+
+// ValidationStarted is emitted when a Validate, ValidateAll or
+// ValidateDomain run (including each run of ValidateMany and ValidateSeq)
+// starts. With ConcurrencyOptions.Prefilter, the offline run of each
+// unique address is a run of its own.
+type ValidationStarted struct {
+	Time  time.Time
+	Email string // the domain for ValidateDomain
+}
+
+// CheckCompleted is emitted after each level ran.
+type CheckCompleted struct {
+	Time     time.Time
+	Email    string
+	Result   CheckResult
+	Duration time.Duration
+}
+
+// SMTPDialed is emitted when the SMTP level opened a new connection to an
+// MX host. Probes over pooled connections don't dial.
+type SMTPDialed struct {
+	Time     time.Time
+	Email    string
+	Host     string
+	Duration time.Duration
+	Err      error // nil if the connection was established
+}
+
+// CacheHit is emitted when an MX lookup was answered without a DNS query.
+type CacheHit struct {
+	Time   time.Time
+	Domain string
+	// Shared is true if the answer came from the MXStore rather than the
+	// validator's own cache.
+	Shared bool
+}
+
+// Throttled is emitted when pacing held back or deferred work:
+//
+//   - Reason "qps": ValidateMany waited for its MaxQPS budget (Key is empty)
+//   - Reason "limiter": the SMTP ProbeLimiter held a probe back, or refused it
+//   - Reason "schedule": the ProbeSchedule deferred a probe
+//   - Reason "warmup": the SMTP WarmUp ramp deferred a probe
+//   - Reason "quota": a Manager tenant's MaxProbesPerDay deferred a probe
+//
+// Key is the provider or MX domain the SMTP probe was paced by. Deferred
+// is true if the work did not run.
+type Throttled struct {
+	Time     time.Time
+	Email    string
+	Key      string
+	Reason   string
+	Waited   time.Duration
+	Deferred bool
+}
+
+func (ValidationStarted) event() {}
+func (CheckCompleted) event()    {}
+func (SMTPDialed) event()        {}
+func (CacheHit) event()          {}
+func (Throttled) event()         {}
+
+// Subscriber receives a Validator's events. HandleEvent is called
+// synchronously from the goroutine doing the work, possibly from many
+// goroutines at once, so it must be safe for concurrent use and should
+// not block: hand slow work off to a channel.
+type Subscriber interface {
+	HandleEvent(e Event)
+}
+
+// SubscriberFunc adapts a function to the Subscriber interface.
+type SubscriberFunc func(e Event)
+
+// HandleEvent calls f(e).
+func (f SubscriberFunc) HandleEvent(e Event) { f(e) }
+
+// WithSubscriber registers s to receive the validator's events, decoupled
+// from logging, e.g. for dashboards or per-tenant accounting. It may be
+// called at any point of the builder chain, and more than once.
+func (v *Validator) WithSubscriber(s Subscriber) *Validator {
+	v.subscribers = append(v.subscribers, s)
+	return v
+}
+
+// observed reports whether anybody listens, so that events are only
+// built when needed.
+func (v *Validator) observed() bool {
+	return len(v.subscribers) > 0
+}
+
+// emit delivers e to every subscriber in registration order.
+func (v *Validator) emit(e Event) {
+	for _, s := range v.subscribers {
+		s.HandleEvent(e)
+	}
+}
+
+// smtpHooks translates the SMTP checker's hooks into events.
+func (v *Validator) smtpHooks() check.SMTPHooks {
+	return check.SMTPHooks{
+		OnDial: func(email, host string, elapsed time.Duration, err error) {
+			if v.observed() {
+				v.emit(SMTPDialed{Time: time.Now(), Email: v.label(email), Host: host, Duration: elapsed, Err: err})
+			}
+		},
+		OnThrottle: func(email, key, reason string, waited time.Duration, deferred bool) {
+			if v.observed() {
+				v.emit(Throttled{Time: time.Now(), Email: v.label(email), Key: key, Reason: reason, Waited: waited, Deferred: deferred})
+			}
+		},
+	}
+}
+
+// onCacheHit is the DNS cache hit hook.
+func (v *Validator) onCacheHit(domain string, shared bool) {
+	if v.observed() {
+		v.emit(CacheHit{Time: time.Now(), Domain: domain, Shared: shared})
+	}
+}
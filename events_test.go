@@ -0,0 +1,98 @@
+package emailkit_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+// eventLog is a concurrency-safe Subscriber recording every event.
+type eventLog struct {
+	mu     sync.Mutex
+	events []emailkit.Event
+}
+
+func (l *eventLog) HandleEvent(e emailkit.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, e)
+}
+
+func TestWithSubscriber_Events(t *testing.T) {
+	log := &eventLog{}
+	v := emailkit.New().
+		WithSubscriber(log).
+		WithResolver(&stubResolver{
+			mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+		}).
+		WithDNS()
+
+	for range 2 {
+		_, err := v.Validate(context.Background(), "user@example.com")
+		require.NoError(t, err)
+	}
+
+	var kinds []string
+	for _, e := range log.events {
+		switch e := e.(type) {
+		case emailkit.ValidationStarted:
+			assert.Equal(t, "user@example.com", e.Email)
+			kinds = append(kinds, "started")
+		case emailkit.CheckCompleted:
+			assert.True(t, e.Result.Passed)
+			kinds = append(kinds, "completed:"+string(e.Result.Level))
+		case emailkit.CacheHit:
+			assert.Equal(t, "example.com", e.Domain)
+			assert.False(t, e.Shared)
+			kinds = append(kinds, "cache_hit")
+		}
+	}
+	assert.Equal(t, []string{
+		"started", "completed:syntax", "completed:dns",
+		"started", "completed:syntax", "cache_hit", "completed:dns",
+	}, kinds)
+}
+
+func TestWithSubscriber_Privacy(t *testing.T) {
+	log := &eventLog{}
+	v := emailkit.New().
+		WithPrivacy(emailkit.PrivacyOptions{Hasher: func(string) string { return "hashed" }}).
+		WithSubscriber(log)
+
+	_, err := v.Validate(context.Background(), "user@example.com")
+	require.NoError(t, err)
+
+	require.Len(t, log.events, 2)
+	assert.Equal(t, "hashed", log.events[0].(emailkit.ValidationStarted).Email)
+	assert.Equal(t, "hashed", log.events[1].(emailkit.CheckCompleted).Email)
+}
+
+func TestWithSubscriber_Throttled(t *testing.T) {
+	log := &eventLog{}
+	v := emailkit.New().WithSubscriber(log)
+
+	_, err := v.ValidateMany(context.Background(),
+		[]string{"a@example.com", "b@example.com", "c@example.com"},
+		emailkit.ConcurrencyOptions{Workers: 1, MaxQPS: 50})
+	require.NoError(t, err)
+
+	var throttled []emailkit.Throttled
+	for _, e := range log.events {
+		if th, ok := e.(emailkit.Throttled); ok {
+			throttled = append(throttled, th)
+		}
+	}
+	// The first validation has a token, the others wait ~20ms each
+	require.Len(t, throttled, 2)
+	for _, th := range throttled {
+		assert.Equal(t, "qps", th.Reason)
+		assert.False(t, th.Deferred)
+		assert.Positive(t, th.Waited)
+	}
+}
@@ -1,8 +1,16 @@
 package emailkit_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/optimode/emailkit"
 )
@@ -14,6 +22,16 @@ func ExampleNew() {
 	// Output: true
 }
 
+func ExampleIsValidSyntax() {
+	fmt.Println(emailkit.IsValidSyntax("user@example.com"))
+	fmt.Println(emailkit.IsValidSyntax("user@münchen.de"))
+	fmt.Println(emailkit.IsValidSyntax("user@@example.com"))
+	// Output:
+	// true
+	// true
+	// false
+}
+
 func ExampleValidator_Validate() {
 	v := emailkit.New()
 
@@ -70,6 +88,358 @@ func ExampleValidator_ValidateMany() {
 	// bob@example.com      valid=true
 }
 
+func ExampleValidator_ValidateDomain() {
+	v := emailkit.New().WithDomain()
+	for _, domain := range []string{"example.com", "mailinator.com", "not a domain"} {
+		result, _ := v.ValidateDomain(context.Background(), domain)
+		fmt.Println(domain, result.Valid)
+	}
+	// Output:
+	// example.com true
+	// mailinator.com false
+	// not a domain false
+}
+
+func ExampleValidator_InspectDomain() {
+	v := emailkit.New()
+	report, err := v.InspectDomain(context.Background(), "example.com")
+	if err != nil {
+		fmt.Println("inspect failed:", err)
+		return
+	}
+	for _, mx := range report.MX {
+		fmt.Println(mx.Pref, mx.Host, len(mx.IPs))
+	}
+}
+
+func ExampleValidator_ValidateMany_prefilter() {
+	v := emailkit.New().WithDomain()
+	emails := []string{"alice@example.com", "temp@mailinator.com", "alice@example.com"}
+
+	results, _ := v.ValidateMany(context.Background(), emails, emailkit.ConcurrencyOptions{
+		Prefilter: true,
+	})
+
+	for _, r := range results {
+		fmt.Printf("%-20s valid=%v checks=%d\n", r.Email, r.Valid, len(r.Checks))
+	}
+	// Output:
+	// alice@example.com    valid=true checks=2
+	// temp@mailinator.com  valid=false checks=2
+	// alice@example.com    valid=true checks=2
+}
+
+func ExampleValidator_ValidateMany_maxBatch() {
+	v := emailkit.New()
+	emails := []string{"alice@example.com", "invalid", "bob@example.com"}
+
+	_, _ = v.ValidateMany(context.Background(), emails, emailkit.ConcurrencyOptions{
+		MaxBatch: 2,
+		OnBatch: func(offset int, results []emailkit.Result) error {
+			fmt.Printf("chunk at %d: %d results\n", offset, len(results))
+			return nil
+		},
+	})
+	// Output:
+	// chunk at 0: 2 results
+	// chunk at 2: 1 results
+}
+
+func ExampleCheckpoint() {
+	// The resume token a stopped run left behind: addresses 0–3 were
+	// reached, address 2 was not validated
+	token := []byte(`{"next":4,"pending":[2]}`)
+	var cp emailkit.Checkpoint
+	_ = json.Unmarshal(token, &cp)
+
+	v := emailkit.New()
+	list := "a@example.com\nb@example.com\nc@example.com\nd@example.com\ne@example.com\n"
+	for result, err := range v.ValidateReader(context.Background(), strings.NewReader(list), emailkit.ConcurrencyOptions{Resume: &cp}) {
+		if err != nil {
+			break
+		}
+		fmt.Println(result.Email)
+	}
+	// Output:
+	// c@example.com
+	// e@example.com
+}
+
+func ExampleSummary_Check() {
+	v := emailkit.New()
+	results, _ := v.ValidateMany(context.Background(), []string{"alice@example.com", "invalid", "bob@example.com"})
+
+	summary := emailkit.Summarize(results)
+	fmt.Printf("%d/%d invalid\n", summary.Invalid, summary.Total)
+
+	err := summary.Check(emailkit.Thresholds{MaxInvalid: 0.02})
+	var te *emailkit.ThresholdError
+	if errors.As(err, &te) {
+		fmt.Println("exit", te.ExitCode())
+	}
+	// Output:
+	// 1/3 invalid
+	// exit 2
+}
+
+func ExampleOptionsError() {
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Port:       "smtp",
+	})
+	_, err := v.Validate(context.Background(), "user@example.com")
+
+	var oe *emailkit.OptionsError
+	if errors.As(err, &oe) {
+		fmt.Println(oe.Field, oe.Constraint)
+	}
+	fmt.Println(errors.Is(err, emailkit.ErrInvalidSMTPOptions))
+	// Output:
+	// SMTPOptions.Port must be a port number (1-65535)
+	// true
+}
+
+func ExampleDefaultCatchAllFingerprints() {
+	for _, fp := range emailkit.DefaultCatchAllFingerprints() {
+		fmt.Println(fp.Name)
+	}
+	// Output:
+	// yahoo
+	// proofpoint
+	// mimecast
+	// barracuda
+	// deferred-verification
+}
+
+func ExampleValidator_WithAllowlist() {
+	v := emailkit.New().WithDomain().
+		WithAllowlist([]string{"partner.example"}, []string{"qa@mailinator.com"})
+
+	for _, email := range []string{"qa@mailinator.com", "spam@mailinator.com", "bob@mail.partner.example"} {
+		result, _ := v.Validate(context.Background(), email)
+		fmt.Println(email, result.Valid, result.Checks[len(result.Checks)-1].Details)
+	}
+	// Output:
+	// qa@mailinator.com true allowlisted address
+	// spam@mailinator.com false disposable email domain detected
+	// bob@mail.partner.example true allowlisted domain partner.example
+}
+
+func ExampleValidator_WithRole() {
+	v := emailkit.New().WithRole(emailkit.RoleOptions{
+		Roles: append(emailkit.DefaultRoles(), "careers"),
+	})
+
+	for _, email := range []string{"jane@example.com", "Sales-EU@example.com", "careers+2026@example.com"} {
+		result, _ := v.Validate(context.Background(), email)
+		c, _ := result.CheckFor(emailkit.LevelRole)
+		fmt.Println(email, result.Valid, result.Severity(), c.Details)
+	}
+	// Output:
+	// jane@example.com true info not a role account
+	// Sales-EU@example.com true warn sales@ is a role account
+	// careers+2026@example.com true warn careers@ is a role account
+}
+
+func ExampleValidator_WithFreeProvider() {
+	v := emailkit.New().WithFreeProvider()
+
+	for _, email := range []string{"jane@gmail.com", "jane@acme.example"} {
+		result, _ := v.Validate(context.Background(), email)
+		fmt.Println(email, result.Valid, result.FreeProvider)
+	}
+	// Output:
+	// jane@gmail.com true true
+	// jane@acme.example true false
+}
+
+func ExampleValidator_WithVerification() {
+	n := emailkit.NewFakeNetwork().
+		AddDomain("acme.example", emailkit.FakeDomain{Mailboxes: []string{"jane"}}).
+		AddDomain("_acme-verify.acme.example", emailkit.FakeDomain{TXT: []string{"acme-verification=3f9a2c"}}).
+		AddDomain("other.example", emailkit.FakeDomain{Mailboxes: []string{"joe"}})
+
+	v := emailkit.NewOffline(n).WithVerification(emailkit.VerificationOptions{
+		Token: "acme-verification=3f9a2c",
+		Name:  "_acme-verify",
+	})
+
+	for _, email := range []string{"jane@acme.example", "joe@other.example"} {
+		result, _ := v.Validate(context.Background(), email)
+		c, _ := result.CheckFor(emailkit.LevelVerification)
+		fmt.Println(email, result.Valid, c.Details)
+	}
+	// Output:
+	// jane@acme.example true verification token found at _acme-verify.acme.example
+	// joe@other.example false no verification token at _acme-verify.other.example
+}
+
+func ExampleValidator_WithSPF() {
+	n := emailkit.NewFakeNetwork().
+		AddDomain("strict.example", emailkit.FakeDomain{TXT: []string{"v=spf1 mx -all"}}).
+		AddDomain("soft.example", emailkit.FakeDomain{TXT: []string{"v=spf1 mx ~all"}}).
+		AddDomain("bare.example", emailkit.FakeDomain{})
+
+	v := emailkit.NewOffline(n).WithSPF()
+
+	for _, email := range []string{"jane@strict.example", "joe@soft.example", "ann@bare.example"} {
+		result, _ := v.Validate(context.Background(), email)
+		c, _ := result.CheckFor(emailkit.LevelSPF)
+		fmt.Println(email, c.Passed, c.Meta["spf"], c.Details)
+	}
+	// Output:
+	// jane@strict.example true present SPF record rejects other senders (-all)
+	// joe@soft.example true present SPF record soft-fails other senders (~all)
+	// ann@bare.example true none no SPF record
+}
+
+func ExampleValidator_WithBlocklist() {
+	v := emailkit.New().WithBlocklist([]string{"abuser@example.com"}, []string{`@spam\.example$`})
+
+	for _, email := range []string{"Abuser@example.com", "x@spam.example", "alice@example.com"} {
+		result, _ := v.Validate(context.Background(), email)
+		fmt.Println(email, result.Valid, result.Checks[0].Details)
+	}
+	// Output:
+	// Abuser@example.com false blocklisted address
+	// x@spam.example false blocklisted by pattern @spam\.example$
+	// alice@example.com true syntax ok
+}
+
+func ExampleValidator_WithBlocklistHashes() {
+	// A partner shares its suppression list as SHA-256 hashes
+	shared := []string{emailkit.HashAddress("abuser@example.com")}
+	v := emailkit.New().WithBlocklistHashes(shared)
+
+	result, _ := v.Validate(context.Background(), "Abuser@Example.com")
+	fmt.Println(result.Valid, result.Checks[0].Details)
+	// Output:
+	// false blocklisted by hash
+}
+
+func ExampleValidator_ValidateWithSuggestion() {
+	v := emailkit.New().WithDomain()
+
+	out, _ := v.ValidateWithSuggestion(context.Background(), "jane.doe@gmial.com")
+	if out.Suggested != nil && out.Suggested.Valid {
+		fmt.Println("Did you mean", out.Suggestion+"?")
+	}
+	// Output:
+	// Did you mean jane.doe@gmail.com?
+}
+
+func ExampleLocale_Suggestion() {
+	v := emailkit.New().WithDomain()
+	loc := emailkit.LocaleFromAcceptLanguage("de-AT, de;q=0.9, en;q=0.5")
+
+	out, _ := v.ValidateWithSuggestion(context.Background(), "jane.doe@gmial.com")
+	fmt.Println(loc.Suggestion(out))
+	// Output:
+	// Meinten Sie jane.doe@gmail.com?
+}
+
+func ExampleLocale_Address() {
+	fmt.Println(emailkit.Locale{}.Address("jan@xn--mnchen-3ya.de"))
+	fmt.Println(emailkit.Locale{ASCII: true}.Address("jan@münchen.de"))
+	// Output:
+	// jan@münchen.de
+	// jan@xn--mnchen-3ya.de
+}
+
+func ExampleFamilies() {
+	for _, f := range emailkit.Families([]string{
+		"promo1@example.com",
+		"jane.doe+news@gmail.com",
+		"promo2@example.com",
+		"janedoe@gmail.com",
+		"promo3@example.com",
+		"alice@example.com",
+	}) {
+		fmt.Println(f.Kind, f.Key, len(f.Members))
+	}
+	// Output:
+	// numbered promo#@example.com 3
+	// alias janedoe@gmail.com 2
+}
+
+func ExampleMerge() {
+	ctx := context.Background()
+	signup, _ := emailkit.New().WithDomain().Validate(ctx, "alice@gmial.com")
+	later := emailkit.Result{Email: "alice@gmial.com", Checks: []emailkit.CheckResult{
+		{Level: emailkit.LevelSMTP, Passed: false, Code: emailkit.CodeMailboxUnknown},
+	}}
+
+	merged := emailkit.Merge(signup, later)
+	for _, c := range merged.Checks {
+		fmt.Println(c.Level, c.Passed, c.Meta["merged.from"])
+	}
+	fmt.Println(merged.Valid)
+	// Output:
+	// syntax true 0
+	// domain true 0
+	// smtp false 1
+	// false
+}
+
+func ExampleNewTwoPhase() {
+	tp := emailkit.NewTwoPhase(
+		emailkit.New(),
+		emailkit.New().WithDomain(), // e.g. WithDNS().WithSMTP(...)
+		emailkit.TwoPhaseOptions{OnComplete: func(email string, r emailkit.Result, err error) {
+			fmt.Println("verified:", email, r.Valid)
+		}},
+	)
+
+	res, _ := tp.Validate(context.Background(), "alice@mailinator.com")
+	fmt.Println("accepted:", res.Email, res.Valid)
+
+	_ = tp.Close(context.Background())
+	// Output:
+	// accepted: alice@mailinator.com true
+	// verified: alice@mailinator.com false
+}
+
+func ExampleValidator_ValidateSeq() {
+	v := emailkit.New()
+	emails := slices.Values([]string{"alice@example.com", "invalid"})
+
+	for result, err := range v.ValidateSeq(context.Background(), emails) {
+		if err != nil {
+			break
+		}
+		fmt.Println(result.Email, result.Valid)
+	}
+	// Output:
+	// alice@example.com true
+	// invalid false
+}
+
+func ExampleValidator_ValidateReader() {
+	v := emailkit.New()
+	csv := "name,email\nAlice,alice@example.com\nBob,invalid@\n"
+
+	for result, err := range v.ValidateReader(context.Background(), strings.NewReader(csv)) {
+		if err != nil {
+			break
+		}
+		fmt.Println(result.Email, result.Valid)
+	}
+	// Output:
+	// alice@example.com true
+	// invalid@ false
+}
+
+func ExampleNewFromConfig() {
+	var cfg emailkit.PipelineConfig
+	_ = json.Unmarshal([]byte(`{"levels": [{"name": "domain", "options": {"CheckTypos": false}}]}`), &cfg)
+
+	v := emailkit.NewFromConfig(cfg)
+	result, _ := v.Validate(context.Background(), "user@mailinator.com")
+	fmt.Println(result.Valid, result.Checks[1].Details)
+	// Output: false disposable email domain detected
+}
+
 func ExampleResult_CheckFor() {
 	v := emailkit.New()
 	result, _ := v.Validate(context.Background(), "user@example.com")
@@ -91,6 +461,377 @@ func ExampleResult_FailedChecks() {
 	// [syntax] invalid email syntax
 }
 
+func ExampleResult_MarshalCompact() {
+	v := emailkit.New()
+	result, _ := v.Validate(context.Background(), "missing-at-sign")
+
+	data, _ := result.MarshalCompact()
+	fmt.Println(string(data))
+	// Output:
+	// {"email":"missing-at-sign","valid":false,"reachability":"undeliverable","severity":"error","failed":"syntax","details":"invalid email syntax"}
+}
+
+func ExampleResult_Severity() {
+	v := emailkit.New().WithDomain()
+	result, _ := v.Validate(context.Background(), "user@gmial.com")
+	fmt.Println(result.Valid, result.Severity())
+	// Output: true warn
+}
+
+func ExampleValidator_WithScoring() {
+	v := emailkit.New().WithDomain().WithScoring(emailkit.ScoringOptions{
+		RoleAccount: 60,
+		NotProbed:   -1, // no SMTP level here: don't count it
+	})
+
+	for _, email := range []string{"jane@example.com", "info@example.com", "jane@gmial.com", "temp@mailinator.com"} {
+		result, _ := v.Validate(context.Background(), email)
+		fmt.Println(email, result.RiskScore)
+	}
+	// Output:
+	// jane@example.com 0
+	// info@example.com 60
+	// jane@gmial.com 30
+	// temp@mailinator.com 90
+}
+
+func ExampleCheckResult() {
+	v := emailkit.New().WithDomain()
+	result, _ := v.Validate(context.Background(), "temp@mailinator.com")
+
+	// Filter on category and severity instead of level names
+	for _, c := range result.Checks {
+		if c.Category == emailkit.CategoryReputation && c.Severity == emailkit.SeverityError {
+			fmt.Printf("[%s] %s\n", c.Level, c.Details)
+		}
+	}
+	// Output:
+	// [domain] disposable email domain detected
+}
+
+func ExampleResult_Flatten() {
+	v := emailkit.New().WithDomain()
+	result, _ := v.Validate(context.Background(), "temp@mailinator.com")
+
+	row := result.Flatten()
+	fmt.Println(row.Email, row.Valid, *row.SyntaxOK, *row.DomainOK, row.DNSOK == nil, row.Disposable, row.FailedLevel)
+	// Output: temp@mailinator.com false true false true true domain
+}
+
+func ExampleNewEncoder() {
+	v := emailkit.New()
+	result, _ := v.Validate(context.Background(), "user@example.com")
+
+	var buf bytes.Buffer
+	enc := emailkit.NewEncoder(&buf, emailkit.EncoderOptions{Naming: emailkit.SnakeCase, ZeroSMTPCode: true})
+	_ = enc.Encode(result)
+	fmt.Print(buf.String())
+	// Output:
+	// {"email":"user@example.com","valid":true,"reachability":"unknown","checks":[{"level":"syntax","passed":true,"details":"syntax ok","smtp_code":0,"severity":"info","category":"syntax"}]}
+}
+
+func ExampleResult_Explanation() {
+	v := emailkit.New().WithDomain()
+	result, _ := v.Validate(context.Background(), "user@gmial.com")
+	fmt.Println(result.Explanation())
+	// Output: user@gmial.com is valid: it passed the syntax and domain checks. Note that it may be a typo for gmail.com.
+}
+
+func ExampleFeatures() {
+	for _, f := range emailkit.Features() {
+		if f.Name == emailkit.LevelSMTP {
+			fmt.Println(f.Name, f.Stability)
+		}
+	}
+	// Output: smtp stable
+}
+
+func ExampleExplainSMTP() {
+	e := emailkit.ExplainSMTP(550, "5.1.1 The email account that you tried to reach does not exist.")
+	fmt.Println(e.Category, e.EnhancedCode, e.Permanent, e.Provider)
+	// Output: mailbox_unknown 5.1.1 true google
+}
+
+func ExampleRedactPII() {
+	fmt.Println(emailkit.RedactPII("550 5.1.1 <jane@example.com>: rejected from 203.0.113.7"))
+	// Output: 550 5.1.1 <[email]>: rejected from [ip]
+}
+
+func ExampleValidator_WithPrivacy() {
+	v := emailkit.New().WithPrivacy(emailkit.PrivacyOptions{
+		Hasher: func(email string) string { return fmt.Sprintf("len:%d", len(email)) },
+	})
+	result, _ := v.Validate(context.Background(), "user@example.com")
+	fmt.Println(result.Email, result.Valid)
+	// Output: len:16 true
+}
+
+func ExampleValidator_WithSMTPConnect() {
+	// Only EHLO is sent: no MAIL FROM, no RCPT TO
+	v := emailkit.New().WithDNS().WithSMTPConnect(emailkit.SMTPOptions{HeloDomain: "myapp.com"})
+	defer func() { _ = v.Close() }()
+
+	result, _ := v.Validate(context.Background(), "user@example.com")
+	if c, ok := result.CheckFor(emailkit.LevelSMTP); ok && c.Passed {
+		fmt.Println("domain accepts mail:", c.Details)
+	}
+}
+
+func ExampleValidator_WithGeo() {
+	// Reject domains whose mail is handled in embargoed countries; the
+	// countries found are in Meta either way
+	v := emailkit.New().WithDNS().WithGeo(emailkit.GeoOptions{BlockCountries: []string{"KP", "IR"}})
+
+	result, _ := v.Validate(context.Background(), "user@example.com")
+	if c, ok := result.CheckFor(emailkit.LevelGeo); ok {
+		fmt.Println(c.Code, c.Meta["countries"])
+	}
+}
+
+func ExampleValidator_WithDNSBL() {
+	v := emailkit.New().WithDNS().WithDNSBL("zen.spamhaus.org")
+
+	result, _ := v.Validate(context.Background(), "user@example.com")
+	if c, ok := result.CheckFor(emailkit.LevelDNSBL); ok && c.Code == emailkit.CodeDNSBLListed {
+		fmt.Println("listed:", c.Meta["listed"])
+	}
+}
+
+func ExampleValidator_WithCompliancePolicy() {
+	v := emailkit.New().WithCompliancePolicy(emailkit.ComplianceOptions{
+		Sanctioned: []string{"embargoed.example"}, // in addition to the embedded pack
+		Exempt:     []string{"gov"},               // we sell to US agencies
+	})
+
+	for _, email := range []string{"user@example.ir", "user@agency.gov", "user@army.mil"} {
+		result, _ := v.Validate(context.Background(), email)
+		c, _ := result.CheckFor(emailkit.LevelCompliance)
+		fmt.Printf("%s valid=%v: %s\n", email, result.Valid, c.Details)
+	}
+	// Output:
+	// user@example.ir valid=false: sanctioned TLD ir (Iran)
+	// user@agency.gov valid=true: no compliance restriction
+	// user@army.mil valid=true: restricted TLD mil (US military)
+}
+
+func ExampleValidator_WithSender() {
+	// Onboarding a customer's From address on a sending platform
+	v := emailkit.New().WithSender(emailkit.SenderOptions{
+		SendingIPs:   []string{"192.0.2.25", "192.0.2.26"},
+		RequireDMARC: true,
+	})
+
+	result, _ := v.Validate(context.Background(), "newsletter@example.com")
+	if c, ok := result.CheckFor(emailkit.LevelSender); ok {
+		fmt.Println(c.Passed, c.Code, c.Meta["spf"], c.Meta["dmarc"])
+	}
+}
+
+func ExampleNewOffline() {
+	n := emailkit.NewFakeNetwork().
+		AddDomain("example.com", emailkit.FakeDomain{Mailboxes: []string{"alice"}}).
+		AddDomain("full.example", emailkit.FakeDomain{Full: []string{"bob"}})
+
+	v := emailkit.NewOffline(n).WithDNS().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "verifier.test",
+		MailFrom:   "verify@verifier.test",
+	})
+	defer func() { _ = v.Close() }()
+
+	for _, email := range []string{"alice@example.com", "carol@example.com", "bob@full.example", "dave@nowhere.example"} {
+		result, _ := v.Validate(context.Background(), email)
+		c := result.Checks[len(result.Checks)-1]
+		fmt.Println(email, result.Valid, c.Level, c.SMTPCode)
+	}
+	// Output:
+	// alice@example.com true smtp 250
+	// carol@example.com false smtp 550
+	// bob@full.example false smtp 452
+	// dave@nowhere.example false dns 0
+}
+
+func ExampleNewMemoryGreylistStore() {
+	store := emailkit.NewMemoryGreylistStore()
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Greylist:   store, // share the store between validators and batches
+	})
+	defer func() { _ = v.Close() }()
+
+	fmt.Println("validator created with greylist store")
+	// Output: validator created with greylist store
+}
+
+func ExampleProbeSchedule() {
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Schedule: &emailkit.ProbeSchedule{
+			Windows:            []string{"09:00-17:00"},
+			Weekdays:           []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+			DailyBudget:        map[string]int{"google": 500, "microsoft": 300},
+			DefaultDailyBudget: 100,
+		},
+	})
+	defer func() { _ = v.Close() }()
+
+	fmt.Println("validator created with probe schedule")
+	// Output: validator created with probe schedule
+}
+
+func ExampleWarmUp() {
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "probe3.myapp.com",
+		MailFrom:   "verify@myapp.com",
+		WarmUp: &emailkit.WarmUp{
+			Start:              time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+			Days:               21,
+			InitialDailyProbes: 20,
+		},
+	})
+	defer func() { _ = v.Close() }()
+
+	fmt.Println("validator created with warm-up ramp")
+	// Output: validator created with warm-up ramp
+}
+
+func ExampleValidator_Analytics() {
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain:      "myapp.com",
+		MailFrom:        "verify@myapp.com",
+		AnalyticsWindow: 15 * time.Minute,
+	})
+	defer func() { _ = v.Close() }()
+
+	for _, p := range v.Analytics().Providers {
+		fmt.Printf("%s: %.0f%% accepted, %.0f%% 4xx, %.0f%% 5xx, %s avg\n",
+			p.Key, p.AcceptRate*100, p.TemporaryRate*100, p.PermanentRate*100, p.AvgLatency)
+	}
+}
+
+func ExampleValidator_WithDisposableCandidates() {
+	v := emailkit.New().
+		WithDomain().
+		WithRegistration().
+		WithSMTP(emailkit.SMTPOptions{HeloDomain: "myapp.com", MailFrom: "verify@myapp.com"}).
+		WithDisposableCandidates().
+		WithSubscriber(emailkit.SubscriberFunc(func(e emailkit.Event) {
+			if c, ok := e.(emailkit.DisposableCandidate); ok {
+				log.Printf("review %s: %v after %d validations", c.Domain, c.Signals, c.Validations)
+			}
+		}))
+	defer func() { _ = v.Close() }()
+}
+
+func ExampleValidator_CheckIdentities() {
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "probe1.myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Identities: []emailkit.SMTPIdentity{
+			{HeloDomain: "probe2.myapp.com", MailFrom: "verify@mail.myapp.com"},
+		},
+		VerifyIdentities: true, // skip identities that fail verification
+	})
+	defer func() { _ = v.Close() }()
+
+	health, _ := v.CheckIdentities(context.Background())
+	for _, h := range health {
+		if !h.Healthy {
+			fmt.Println(h.Identity.MailFrom, h.Problems)
+		}
+	}
+}
+
+func ExampleWithWorker() {
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain:        "probe1.myapp.com",
+		MailFrom:          "verify@myapp.com",
+		Identities:        []emailkit.SMTPIdentity{{HeloDomain: "probe2.myapp.com", MailFrom: "verify@mail.myapp.com"}},
+		IdentityPerWorker: true,
+	})
+	defer func() { _ = v.Close() }()
+
+	// Your own worker pool: worker n always probes as identity n mod 2
+	var wg sync.WaitGroup
+	for n, email := range []string{"jane@example.com", "john@example.org"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = v.Validate(emailkit.WithWorker(context.Background(), n), email)
+		}()
+	}
+	wg.Wait()
+}
+
+func ExampleValidator_WithCalibration() {
+	// Your own bounce data says unprobed mailboxes deliver 80% of the time
+	v := emailkit.New().WithCalibration(emailkit.Calibration{
+		emailkit.SignalNoSMTP: 0.80,
+	})
+	result, _ := v.Validate(context.Background(), "user@example.com")
+	fmt.Println(result.DeliverabilityProbability)
+	// Output: 0.8
+}
+
+func ExampleValidator_ExportState() {
+	v := emailkit.New().WithDNS()
+
+	var state bytes.Buffer
+	if err := v.ExportState(&state); err != nil {
+		fmt.Println("export failed:", err)
+		return
+	}
+
+	// Later, in the next run
+	next := emailkit.New().WithDNS()
+	if err := next.ImportState(&state); err != nil {
+		fmt.Println("import failed:", err)
+		return
+	}
+	fmt.Println("state restored")
+	// Output: state restored
+}
+
+func ExampleValidator_SaveMXSnapshot() {
+	v := emailkit.New().WithDNS()
+	// ... nightly bulk run ...
+
+	var snapshot bytes.Buffer // e.g. a file kept between runs
+	if err := v.SaveMXSnapshot(&snapshot); err != nil {
+		fmt.Println("save failed:", err)
+		return
+	}
+
+	// The next night: start with yesterday's MX knowledge
+	next := emailkit.New().WithDNS()
+	n, err := next.LoadMXSnapshot(&snapshot, 48*time.Hour)
+	if err != nil {
+		fmt.Println("load failed:", err)
+		return
+	}
+	fmt.Println("domains loaded:", n)
+	// Output: domains loaded: 0
+}
+
+func ExampleValidator_WithWatchdog() {
+	v := emailkit.New().WithDomain().WithWatchdog(time.Minute)
+
+	result, _ := v.Validate(context.Background(), "john.doe@gmail.com")
+	fmt.Println(result.Valid)
+	// Output: true
+}
+
+func ExampleValidator_WithOutput() {
+	v := emailkit.New().WithDomain().WithOutput(emailkit.OutputOptions{OnlyFailures: true})
+
+	result, _ := v.Validate(context.Background(), "user@gmial.com")
+	for _, c := range result.Checks {
+		fmt.Println(c.Level, c.Suggestion) // "syntax ok" is left out
+	}
+	// Output: domain gmail.com
+}
+
 func ExampleValidator_WithDomain() {
 	v := emailkit.New().WithDomain()
 
@@ -101,6 +842,17 @@ func ExampleValidator_WithDomain() {
 	// Output: true gmail.com
 }
 
+func ExampleValidator_WithDomain_typoCorpus() {
+	opts := emailkit.DomainOptions{CheckTypos: true, TypoThreshold: 2}
+	opts.TypoCorpus = []string{"acme.io", "globex.com"} // e.g. your customer domains
+	v := emailkit.New().WithDomain(opts)
+
+	result, _ := v.Validate(context.Background(), "alice@acmme.io")
+	domain, _ := result.CheckFor(emailkit.LevelDomain)
+	fmt.Println(domain.Suggestion)
+	// Output: acme.io
+}
+
 func ExampleValidator_Close() {
 	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
 		HeloDomain: "myapp.com",
@@ -111,3 +863,158 @@ func ExampleValidator_Close() {
 	fmt.Println("validator created with SMTP pool")
 	// Output: validator created with SMTP pool
 }
+
+func ExampleValidator_WithSubscriber() {
+	v := emailkit.New().WithSubscriber(emailkit.SubscriberFunc(func(e emailkit.Event) {
+		switch e := e.(type) {
+		case emailkit.ValidationStarted:
+			fmt.Println("started", e.Email)
+		case emailkit.CheckCompleted:
+			fmt.Println(e.Result.Level, e.Result.Passed)
+		}
+	}))
+
+	_, _ = v.Validate(context.Background(), "user@example.com")
+	// Output:
+	// started user@example.com
+	// syntax true
+}
+
+func ExampleManager() {
+	m := emailkit.NewManager()
+	defer func() { _ = m.Close() }()
+
+	if err := m.AddTenant("acme", emailkit.TenantProfile{MaxValidationsPerDay: 1}); err != nil {
+		fmt.Println("add tenant:", err)
+		return
+	}
+
+	result, _ := m.Validate(context.Background(), "acme", "user@example.com")
+	fmt.Println(result.Valid)
+	_, err := m.Validate(context.Background(), "acme", "other@example.com")
+	fmt.Println(errors.Is(err, emailkit.ErrQuotaExceeded))
+
+	stats, _ := m.Stats("acme")
+	fmt.Println(stats.Validations, stats.QuotaRejected)
+	// Output:
+	// true
+	// true
+	// 1 1
+}
+
+func ExampleCanaries() {
+	v := emailkit.New() // syntax only: cannot reject a well-formed address
+
+	_, err := v.ValidateMany(context.Background(), []string{"user@example.com"}, emailkit.ConcurrencyOptions{
+		Canaries: emailkit.Canaries{Bad: []string{"nobody@example.com"}},
+	})
+	var canaryErr *emailkit.CanaryError
+	if errors.As(err, &canaryErr) {
+		fmt.Println(canaryErr.Failures[0].Email, canaryErr.Failures[0].Hint)
+	}
+	// Output: nobody@example.com known-bad address accepted: catch-all, skipped or policy-relaxed checks
+}
+
+func ExampleValidator_SMTPPortBlocked() {
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+	})
+	defer func() { _ = v.Close() }()
+
+	// After a batch: were SMTP checks skipped because port 25 is blocked?
+	fmt.Println(v.SMTPPortBlocked())
+	// Output: false
+}
+
+func ExampleValidator_Health() {
+	v := emailkit.New().WithDNS()
+
+	h := v.Health(context.Background())
+	if !h.Healthy {
+		fmt.Println("not ready:", h.DNS.Error)
+	}
+}
+
+// providerChecker is a custom level reading the DNS level's findings.
+type providerChecker struct{}
+
+func (providerChecker) Check(ctx context.Context, _ emailkit.Email) emailkit.CheckResult {
+	f := emailkit.ValidationFrom(ctx).Findings()
+	if reason := f.NoMail(); reason != "" {
+		return emailkit.CheckResult{Level: "provider", Details: "skipped: " + reason}
+	}
+	return emailkit.CheckResult{Level: "provider", Passed: true, Details: "provider: " + f.Provider()}
+}
+
+func ExampleValidationContext_Findings() {
+	n := emailkit.NewFakeNetwork().
+		AddDomain("example.com", emailkit.FakeDomain{MX: []string{"aspmx.l.google.com"}}).
+		AddDomain("nowhere.example", emailkit.FakeDomain{NXDomain: true})
+
+	v := emailkit.NewOffline(n).WithDNS().With(providerChecker{})
+
+	for _, email := range []string{"alice@example.com", "bob@nowhere.example"} {
+		result, _ := v.ValidateAll(context.Background(), email)
+		c, _ := result.CheckFor("provider")
+		fmt.Println(email, c.Details)
+	}
+	// Output:
+	// alice@example.com provider: google
+	// bob@nowhere.example skipped: NXDOMAIN
+}
+
+// checkedChecker is a custom level reading the results of the levels
+// before it from the ValidationContext.
+type checkedChecker struct{}
+
+func (checkedChecker) Check(ctx context.Context, email emailkit.Email) emailkit.CheckResult {
+	vc := emailkit.ValidationFrom(ctx)
+	var levels []string
+	for _, c := range vc.Checks() {
+		levels = append(levels, string(c.Level))
+	}
+	return emailkit.CheckResult{Level: "checked", Passed: true, Details: email.Domain + " after " + strings.Join(levels, ",")}
+}
+
+func ExampleValidationFrom() {
+	n := emailkit.NewFakeNetwork().AddDomain("example.com", emailkit.FakeDomain{Mailboxes: []string{"alice"}})
+
+	v := emailkit.NewOffline(n).WithDNS().With(checkedChecker{})
+
+	result, _ := v.Validate(context.Background(), "alice@example.com")
+	c, _ := result.CheckFor("checked")
+	fmt.Println(c.Details)
+	// Output:
+	// example.com after syntax,dns
+}
+
+func ExampleValidator_WithAutoOrder() {
+	v := emailkit.New().
+		WithAutoOrder().
+		WithDNS().
+		WithRegistration().
+		WithDomain()
+
+	for _, c := range v.Levels() {
+		fmt.Println(c.Level, c.Cost)
+	}
+	// Output:
+	// syntax offline
+	// domain offline
+	// dns dns
+	// registration network
+}
+
+func ExampleLoadDisposableDomains() {
+	// In a binary built with -tags emailkit_nodisposable, e.g. from a
+	// file shipped next to it or fetched at startup
+	n, err := emailkit.LoadDisposableDomains(strings.NewReader("mailinator.com\nthrowaway.example\n"))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(n, "disposable domains")
+
+	result, _ := emailkit.New().WithDomain().Validate(context.Background(), "user@throwaway.example")
+	fmt.Println(result.Valid) // false
+}
@@ -0,0 +1,25 @@
+package emailkit
+
+import (
+	"github.com/optimode/emailkit/internal/bounce"
+	"github.com/optimode/emailkit/internal/redact"
+)
+
+// Explanation is the interpretation of an SMTP response: the bounce
+// category (one of the SMTP Code* constants), whether it is permanent,
+// the enhanced status code if present, and a human-readable description.
+type Explanation = bounce.Explanation
+
+// ExplainSMTP maps an SMTP reply code and response text to a bounce
+// category. It understands enhanced status codes (e.g. "5.1.1") and
+// well-known provider wording, so it works equally on live SMTP results
+// (CheckResult.SMTPCode and Details) and on historical bounce logs.
+func ExplainSMTP(code int, msg string) Explanation {
+	return bounce.Explain(code, msg)
+}
+
+// RedactPII replaces email addresses and IP addresses in s with the
+// placeholders "[email]" and "[ip]". It is suitable as SMTPOptions.Sanitize.
+func RedactPII(s string) string {
+	return redact.PII(s)
+}
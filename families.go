@@ -0,0 +1,186 @@
+package emailkit
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/optimode/emailkit/internal/parse"
+)
+
+// FamilyKind tells how the members of a Family are related.
+type FamilyKind string
+
+const (
+	// FamilyAlias: the members reach the same mailbox — they differ in a
+	// plus tag ("jane+shop@") or, at Gmail, in dots.
+	FamilyAlias FamilyKind = "alias"
+	// FamilyNumbered: the members share a base and differ in a numeric
+	// suffix ("promo1@", "promo2@", "promo17@"), as in scripted signups.
+	FamilyNumbered FamilyKind = "numbered"
+	// FamilyRandom: the members are random-looking local parts (high
+	// character entropy, letters and digits interleaved) of the same length
+	// and letter/digit pattern at the same domain, as bots generate them.
+	FamilyRandom FamilyKind = "random"
+)
+
+// Family is a group of addresses that likely belong to the same person
+// or bot; see Families.
+type Family struct {
+	// Key identifies the family: the normalized mailbox for
+	// FamilyAlias, the base with "#" for the number for FamilyNumbered
+	// ("promo#@example.com"), and "~" and the letter/digit pattern for
+	// FamilyRandom ("~a9aa9a9a@example.com").
+	Key     string     `json:"key"`
+	Kind    FamilyKind `json:"kind"`
+	Members []string   `json:"members"`
+}
+
+// Families clusters emails into duplicate families — plus-tag and
+// Gmail-dot aliases of one mailbox, sequentially numbered addresses, and
+// random-looking addresses of one shape — for fraud screening. It
+// returns the families of two or more distinct addresses, largest first;
+// members keep their input spelling and order. Addresses that don't parse
+// (e.g. hashed in privacy mode) are ignored.
+//
+// The grouping is a heuristic: "anna1@" and "anna2@" at a large provider
+// may be two people. Weigh families by size and by Kind.
+func Families(emails []string) []Family {
+	groups := make(map[string]*Family)
+	var order []string
+	seen := make(map[string]bool)
+	for _, email := range emails {
+		key, ok := familyKey(email)
+		if !ok || seen[strings.ToLower(email)] {
+			continue
+		}
+		seen[strings.ToLower(email)] = true
+		g, ok := groups[key]
+		if !ok {
+			g = &Family{Key: key}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.Members = append(g.Members, email)
+	}
+
+	var out []Family
+	for _, key := range order {
+		g := groups[key]
+		if len(g.Members) < 2 {
+			continue
+		}
+		g.Kind, g.Key = familyKind(*g)
+		out = append(out, *g)
+	}
+	slices.SortStableFunc(out, func(a, b Family) int { return cmp.Compare(len(b.Members), len(a.Members)) })
+	return out
+}
+
+// familyKind classifies a family by its key and members, and returns its
+// final key.
+func familyKind(f Family) (FamilyKind, string) {
+	if strings.HasPrefix(f.Key, "~") {
+		return FamilyRandom, f.Key
+	}
+	first := mailboxKey(parse.NewEmail(f.Members[0]))
+	for _, m := range f.Members[1:] {
+		if mailboxKey(parse.NewEmail(m)) != first {
+			return FamilyNumbered, f.Key
+		}
+	}
+	return FamilyAlias, first
+}
+
+// familyKey returns the key of the family email belongs to.
+func familyKey(email string) (string, bool) {
+	e := parse.NewEmail(email)
+	if !e.Valid || e.Local == "" {
+		return "", false
+	}
+	local, domain := mailbox(e)
+	if randomLooking(local) {
+		return "~" + shape(local) + "@" + domain, true
+	}
+	return numberedBase(local) + "@" + domain, true
+}
+
+// mailboxKey is the normalized mailbox of e: what the provider delivers to.
+func mailboxKey(e Email) string {
+	local, domain := mailbox(e)
+	return local + "@" + domain
+}
+
+// mailbox normalizes e to the mailbox the provider delivers to: lowercase,
+// without the plus tag and, at Gmail, without dots.
+func mailbox(e Email) (local, domain string) {
+	local, _, _ = strings.Cut(strings.ToLower(e.Local), "+")
+	domain = strings.ToLower(e.Domain)
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		local, domain = strings.ReplaceAll(local, ".", ""), "gmail.com"
+	}
+	return local, domain
+}
+
+// numberedBase replaces a numeric suffix of 1-6 digits (and a separator
+// before it) with "#" if at least 3 characters remain.
+func numberedBase(local string) string {
+	base := strings.TrimRightFunc(local, unicode.IsDigit)
+	if n := len(local) - len(base); n == 0 || n > 6 {
+		return local
+	}
+	base = strings.TrimRight(base, "._-")
+	if len(base) < 3 {
+		return local
+	}
+	return base + "#"
+}
+
+// randomLooking reports whether local looks machine-generated: at least 8
+// characters, letters and digits alternating at least 3 times, and an
+// entropy of 3 bits per character or more (no character repeats much).
+func randomLooking(local string) bool {
+	if len(local) < 8 {
+		return false
+	}
+	switches := 0
+	for i := 1; i < len(local); i++ {
+		if isDigit(local[i]) != isDigit(local[i-1]) {
+			switches++
+		}
+	}
+	return switches >= 3 && entropy(local) >= 3
+}
+
+func isDigit(b byte) bool { return '0' <= b && b <= '9' }
+
+// entropy returns the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// shape maps letters to "a", digits to "9" and keeps other characters.
+func shape(local string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r):
+			return 'a'
+		case unicode.IsDigit(r):
+			return '9'
+		}
+		return r
+	}, local)
+}
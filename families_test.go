@@ -0,0 +1,74 @@
+package emailkit_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestFamilies(t *testing.T) {
+	families := emailkit.Families([]string{
+		"promo1@example.com",
+		"Jane.Doe+shop@gmail.com",
+		"promo2@example.com",
+		"janedoe@googlemail.com",
+		"promo_17@example.com",
+		"x7fk2q9z@bots.example",
+		"k3jw8p1z@bots.example",
+		"alice@example.com",
+		"promo1@example.com", // repeated
+		"PROMO2@example.com", // repeated, other case
+		"invalid",
+		"a1@example.com", // base too short
+		"a2@example.com",
+		"bob1234567@example.com", // suffix too long
+		"bob7654321@example.com",
+		"q8z3m1x7@other.example", // other domain
+	})
+
+	require.Len(t, families, 3)
+	assert.Equal(t, emailkit.Family{
+		Key:     "promo#@example.com",
+		Kind:    emailkit.FamilyNumbered,
+		Members: []string{"promo1@example.com", "promo2@example.com", "promo_17@example.com"},
+	}, families[0])
+	assert.Equal(t, emailkit.Family{
+		Key:     "janedoe@gmail.com",
+		Kind:    emailkit.FamilyAlias,
+		Members: []string{"Jane.Doe+shop@gmail.com", "janedoe@googlemail.com"},
+	}, families[1])
+	assert.Equal(t, emailkit.Family{
+		Key:     "~a9aa9a9a@bots.example",
+		Kind:    emailkit.FamilyRandom,
+		Members: []string{"x7fk2q9z@bots.example", "k3jw8p1z@bots.example"},
+	}, families[2])
+}
+
+func TestFamilies_NotRandom(t *testing.T) {
+	// Readable local parts with digits are not random-looking
+	assert.Empty(t, emailkit.Families([]string{"jennifer1980x@example.com", "annabelle22x@example.com"}))
+	assert.Empty(t, emailkit.Families(nil))
+}
+
+func TestSummary_Families(t *testing.T) {
+	var s emailkit.Summary
+	for _, email := range []string{
+		"promo1@example.com", "promo2@example.com", "promo3@example.com", "promo2@example.com",
+		"jane+a@example.com", "jane+b@example.com",
+		"alice@example.com",
+		"5f3a9c…hash",
+	} {
+		s.Add(emailkit.Result{Email: email, Valid: true})
+	}
+	assert.Equal(t, 8, s.Total)
+	assert.Equal(t, map[int]int{3: 1, 2: 1}, s.FamilySizes)
+	assert.Equal(t, 5, s.InFamilies)
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"familySizes":{"2":1,"3":1},"inFamilies":5`)
+}
@@ -0,0 +1,59 @@
+package emailkit
+
+import "github.com/optimode/emailkit/internal/features"
+
+// Feature is a subsystem compiled into the binary, with its API stability
+// tier; see Features.
+type Feature = features.Feature
+
+// Stability is the API stability tier of a Feature.
+type Stability = features.Stability
+
+const (
+	// StabilityStable marks features covered by semantic versioning.
+	StabilityStable = features.Stable
+	// StabilityExperimental marks features under emailkit/x/, which may
+	// change or be removed in any minor release.
+	StabilityExperimental = features.Experimental
+)
+
+// Features reports the subsystems compiled into the binary, sorted by
+// name: the levels and subsystems of this package, plus those of every
+// emailkit package the program imports (redisstore, the experimental
+// packages under emailkit/x/). Use it in version endpoints and startup
+// logs, or to refuse to start when a required feature is missing.
+//
+// A feature keeps its Name when it is promoted from experimental to
+// stable; only its Stability and Package change.
+func Features() []Feature {
+	return features.All()
+}
+
+func init() {
+	const pkg = "github.com/optimode/emailkit"
+	features.Register(
+		Feature{Name: LevelSyntax, Stability: StabilityStable, Package: pkg, Description: "RFC 5321/5322 syntax level"},
+		Feature{Name: LevelDNS, Stability: StabilityStable, Package: pkg, Description: "MX lookup level"},
+		Feature{Name: LevelNS, Stability: StabilityStable, Package: pkg, Description: "nameserver delegation and parked domain level"},
+		Feature{Name: LevelRegistration, Stability: StabilityStable, Package: pkg, Description: "RDAP registration level"},
+		Feature{Name: LevelGeo, Stability: StabilityStable, Package: pkg, Description: "mail host country and ASN level"},
+		Feature{Name: LevelCompliance, Stability: StabilityStable, Package: pkg, Description: "sanctioned and restricted domain policy level"},
+		Feature{Name: LevelSender, Stability: StabilityStable, Package: pkg, Description: "sender address level (bounce MX, SPF, DMARC, roles)"},
+		Feature{Name: LevelRole, Stability: StabilityStable, Package: pkg, Description: "role account level"},
+		Feature{Name: LevelFreeProvider, Stability: StabilityStable, Package: pkg, Description: "free consumer mailbox provider level"},
+		Feature{Name: LevelVerification, Stability: StabilityStable, Package: pkg, Description: "TXT verification token level"},
+		Feature{Name: LevelDNSBL, Stability: StabilityStable, Package: pkg, Description: "DNS blocklist level for mail host addresses"},
+		Feature{Name: LevelSPF, Stability: StabilityStable, Package: pkg, Description: "SPF record presence and policy level"},
+		Feature{Name: LevelDomain, Stability: StabilityStable, Package: pkg, Description: "disposable, provider rule and typo level"},
+		Feature{Name: LevelSMTP, Stability: StabilityStable, Package: pkg, Description: "SMTP RCPT TO probe level"},
+		Feature{Name: "lists", Stability: StabilityStable, Package: pkg, Description: "allowlist and blocklist"},
+		Feature{Name: "calibration", Stability: StabilityStable, Package: pkg, Description: "deliverability probability"},
+		Feature{Name: "scoring", Stability: StabilityStable, Package: pkg, Description: "weighted risk score"},
+		Feature{Name: "privacy", Stability: StabilityStable, Package: pkg, Description: "hashed and redacted results"},
+		Feature{Name: "inspect", Stability: StabilityStable, Package: pkg, Description: "mail infrastructure report"},
+		Feature{Name: "bulk", Stability: StabilityStable, Package: pkg, Description: "batch, streaming and resumable validation"},
+		Feature{Name: "manager", Stability: StabilityStable, Package: pkg, Description: "multi-tenant validator profiles"},
+		Feature{Name: "events", Stability: StabilityStable, Package: pkg, Description: "typed event stream"},
+		Feature{Name: "watchdog", Stability: StabilityStable, Package: pkg, Description: "level watchdog"},
+	)
+}
@@ -0,0 +1,27 @@
+package emailkit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestFeatures(t *testing.T) {
+	fs := emailkit.Features()
+
+	byName := make(map[string]emailkit.Feature)
+	var names []string
+	for _, f := range fs {
+		byName[f.Name] = f
+		names = append(names, f.Name)
+	}
+	assert.IsIncreasing(t, names)
+	for _, level := range []emailkit.CheckLevel{emailkit.LevelSyntax, emailkit.LevelDNS, emailkit.LevelSMTP} {
+		assert.Equal(t, emailkit.StabilityStable, byName[level].Stability, level)
+		assert.Equal(t, "github.com/optimode/emailkit", byName[level].Package, level)
+	}
+	// Not imported by this test binary
+	assert.NotContains(t, byName, "redisstore")
+}
@@ -0,0 +1,83 @@
+package emailkit
+
+// FlatResult is a Result as a single flat row, for bulk inserts into SQL
+// tables: db tags for sqlx and similar mappers, json tags for loaders of
+// JSON lines. The per-level outcomes are nil if the level did not run, so
+// they map to NULL.
+type FlatResult struct {
+	Email        string       `db:"email" json:"email"`
+	Valid        bool         `db:"valid" json:"valid"`
+	Truncated    bool         `db:"truncated" json:"truncated"`
+	Reachability Reachability `db:"reachability" json:"reachability"`
+	Severity     Severity     `db:"severity" json:"severity"`
+	// Score is Result.RiskScore and Probability its
+	// DeliverabilityProbability; 0 unless enabled.
+	Score       int     `db:"score" json:"score"`
+	Probability float64 `db:"probability" json:"probability"`
+
+	SyntaxOK *bool `db:"syntax_ok" json:"syntax_ok"`
+	DNSOK    *bool `db:"dns_ok" json:"dns_ok"`
+	DomainOK *bool `db:"domain_ok" json:"domain_ok"`
+	SMTPOK   *bool `db:"smtp_ok" json:"smtp_ok"`
+
+	MXHost       string `db:"mx_host" json:"mx_host"`
+	SMTPCode     int    `db:"smtp_code" json:"smtp_code"`
+	Disposable   bool   `db:"disposable" json:"disposable"`
+	CatchAll     bool   `db:"catch_all" json:"catch_all"`
+	Role         bool   `db:"role" json:"role"` // a role account, see WithRole
+	Parked       bool   `db:"parked" json:"parked"`
+	FreeProvider bool   `db:"free_provider" json:"free_provider"` // see WithFreeProvider
+	Suggestion   string `db:"suggestion" json:"suggestion"`
+
+	// FailedLevel, Code and Details describe the first failed check.
+	FailedLevel CheckLevel `db:"failed_level" json:"failed_level"`
+	Code        CheckCode  `db:"code" json:"code"`
+	Details     string     `db:"details" json:"details"`
+}
+
+// Flatten returns r as a FlatResult. MXHost and SMTPCode come from the
+// SMTP level; Disposable is set when the domain level failed on a
+// disposable domain; CatchAll, Role and Parked when a check carries
+// CodeCatchAll, CodeRole or CodeParked.
+func (r Result) Flatten() FlatResult {
+	out := FlatResult{
+		Email:        r.Email,
+		Valid:        r.Valid,
+		Truncated:    r.Truncated,
+		Reachability: r.Reachability,
+		Severity:     r.Severity(),
+		Score:        r.RiskScore,
+		Probability:  r.DeliverabilityProbability,
+		FreeProvider: r.FreeProvider,
+	}
+	for _, c := range r.Checks {
+		passed := c.Passed
+		switch c.Level {
+		case LevelSyntax:
+			out.SyntaxOK = &passed
+		case LevelDNS:
+			out.DNSOK = &passed
+		case LevelDomain:
+			out.DomainOK = &passed
+			out.Disposable = !c.Passed && c.Category == CategoryReputation
+		case LevelSMTP:
+			out.SMTPOK = &passed
+			out.MXHost, out.SMTPCode = c.MXHost, c.SMTPCode
+		}
+		switch c.Code {
+		case CodeCatchAll:
+			out.CatchAll = true
+		case CodeRole:
+			out.Role = true
+		case CodeParked:
+			out.Parked = true
+		}
+		if c.Suggestion != "" && out.Suggestion == "" {
+			out.Suggestion = c.Suggestion
+		}
+		if !c.Passed && out.FailedLevel == "" {
+			out.FailedLevel, out.Code, out.Details = c.Level, c.Code, c.Details
+		}
+	}
+	return out
+}
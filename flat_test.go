@@ -0,0 +1,61 @@
+package emailkit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestResult_Flatten(t *testing.T) {
+	v := emailkit.NewOffline(fakeNetwork()).WithDomain().WithDNS().WithRole().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain:     "verifier.test",
+		MailFrom:       "verify@verifier.test",
+		ConnectTimeout: time.Second,
+		CommandTimeout: time.Second,
+	}).WithScoring()
+	defer func() { _ = v.Close() }()
+	ctx := context.Background()
+
+	res, err := v.Validate(ctx, "alice@example.com")
+	assert.NoError(t, err)
+	f := res.Flatten()
+	assert.Equal(t, "alice@example.com", f.Email)
+	assert.True(t, f.Valid)
+	assert.Equal(t, emailkit.ReachabilityDeliverable, f.Reachability)
+	assert.Equal(t, emailkit.SeverityInfo, f.Severity)
+	for _, ok := range []*bool{f.SyntaxOK, f.DNSOK, f.DomainOK, f.SMTPOK} {
+		if assert.NotNil(t, ok) {
+			assert.True(t, *ok)
+		}
+	}
+	assert.Equal(t, "mx.example.com", f.MXHost)
+	assert.Equal(t, 250, f.SMTPCode)
+	assert.Empty(t, f.FailedLevel)
+
+	res, _ = v.Validate(ctx, "nobody@example.com")
+	f = res.Flatten()
+	assert.False(t, f.Valid)
+	assert.False(t, *f.SMTPOK)
+	assert.Equal(t, 550, f.SMTPCode)
+	assert.Equal(t, emailkit.LevelSMTP, f.FailedLevel)
+	assert.Equal(t, emailkit.CodeMailboxUnknown, f.Code)
+	assert.Equal(t, 100, f.Score)
+
+	res, _ = v.Validate(ctx, "info@mailinator.com")
+	f = res.Flatten()
+	assert.True(t, f.Disposable)
+	assert.Nil(t, f.SMTPOK, "short-circuited levels are NULL")
+	assert.Equal(t, emailkit.LevelDomain, f.FailedLevel)
+
+	res, _ = emailkit.New().WithDomain().WithRole().Validate(ctx, "sales@gmial.com")
+	f = res.Flatten()
+	assert.True(t, f.Valid)
+	assert.True(t, f.Role)
+	assert.False(t, f.Disposable)
+	assert.Equal(t, "gmail.com", f.Suggestion)
+	assert.Nil(t, f.DNSOK)
+}
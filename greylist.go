@@ -0,0 +1,18 @@
+package emailkit
+
+import (
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/greylist"
+)
+
+// GreylistStore persists greylisting first-attempt timestamps per MX host
+// and recipient (see SMTPOptions.Greylist). Implement it on top of a shared
+// database to keep the state across process restarts.
+type GreylistStore = check.GreylistStore
+
+// NewMemoryGreylistStore returns an in-process GreylistStore; entries
+// expire after 24 hours. Share one store between validators to carry the
+// state across ValidateMany batches.
+func NewMemoryGreylistStore() GreylistStore {
+	return greylist.NewMemoryStore(greylist.DefaultTTL)
+}
@@ -0,0 +1,110 @@
+package emailkit
+
+import (
+	"context"
+	"time"
+
+	"github.com/optimode/emailkit/internal/greylist"
+)
+
+// HealthOptions configures Validator.Health.
+type HealthOptions struct {
+	// DNSProbe is the name resolved to check that the resolver works.
+	// Default: "example.com"
+	DNSProbe string
+	// Timeout bounds the DNS check. Default: 2s
+	Timeout time.Duration
+}
+
+// Health is a snapshot of a Validator's runtime state for readiness and
+// liveness probes of services embedding emailkit.
+type Health struct {
+	Checked time.Time `json:"checked"`
+	// Healthy is false if the resolver is unreachable or outbound SMTP
+	// is blocked.
+	Healthy bool      `json:"healthy"`
+	DNS     DNSHealth `json:"dns"`
+	// SMTP is nil if the SMTP level is not configured.
+	SMTP *SMTPHealth `json:"smtp,omitempty"`
+	// MXCacheEntries is the number of cached MX lookups.
+	MXCacheEntries int `json:"mxCacheEntries"`
+	// GreylistEntries is the number of tracked greylisted recipients, for
+	// the built-in store; -1 for a custom GreylistStore.
+	GreylistEntries int `json:"greylistEntries"`
+}
+
+// DNSHealth reports whether the resolver answers.
+type DNSHealth struct {
+	Reachable bool          `json:"reachable"`
+	Latency   time.Duration `json:"latency"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// SMTPHealth reports the SMTP level's state.
+type SMTPHealth struct {
+	// PortBlocked is set when outbound SMTP was detected as blocked (see
+	// SMTPOptions.DisableBlockedPortDetection); SMTP checks are skipped.
+	PortBlocked bool `json:"portBlocked"`
+	// Paused is set while the level is paused with Validator.PauseSMTP.
+	Paused bool `json:"paused"`
+	// LastProbe is when an MX host last answered; zero if none has yet.
+	LastProbe time.Time `json:"lastProbe,omitzero"`
+	// IdleConns is the number of idle pooled connections, to IdleHosts hosts.
+	IdleConns int `json:"idleConns"`
+	IdleHosts int `json:"idleHosts"`
+	// HostConcurrency is the concurrent probe limit chosen for each MX
+	// host, with SMTPOptions.AdaptiveConns.
+	HostConcurrency map[string]int `json:"hostConcurrency,omitempty"`
+	// Identities counts the RCPT probes made with each SMTP identity, the
+	// primary HeloDomain/MailFrom first.
+	Identities []IdentityStats `json:"identities,omitempty"`
+	// WarmUp is the state of every provider probed today, with
+	// SMTPOptions.WarmUp.
+	WarmUp []WarmUpStatus `json:"warmUp,omitempty"`
+}
+
+// Health checks the resolver and reports the validator's cache, pool and
+// SMTP state. It is cheap enough for periodic readiness probes: the only
+// network traffic is one DNS lookup.
+func (v *Validator) Health(ctx context.Context, opts ...HealthOptions) Health {
+	var o HealthOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.DNSProbe == "" {
+		o.DNSProbe = "example.com"
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 2 * time.Second
+	}
+
+	h := Health{Checked: time.Now().UTC(), GreylistEntries: -1}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+	start := time.Now()
+	_, err := v.resolver.LookupHost(lookupCtx, o.DNSProbe)
+	h.DNS = DNSHealth{Reachable: err == nil, Latency: time.Since(start)}
+	if err != nil {
+		h.DNS.Error = err.Error()
+	}
+
+	if v.dnsCache != nil {
+		h.MXCacheEntries = v.dnsCache.Len()
+	}
+	switch s := v.greylist.(type) {
+	case nil:
+		h.GreylistEntries = 0
+	case *greylist.MemoryStore:
+		h.GreylistEntries = len(s.Entries())
+	}
+
+	if v.smtp != nil {
+		h.SMTP = &SMTPHealth{PortBlocked: v.smtp.PortBlocked(), Paused: v.smtp.Paused(), LastProbe: v.smtp.LastProbe(), Identities: v.smtp.IdentityStats(), WarmUp: v.smtp.WarmUpStatus()}
+		h.SMTP.IdleConns, h.SMTP.IdleHosts = v.smtpPool.Idle()
+		h.SMTP.HostConcurrency = v.smtpPool.HostLimits()
+	}
+
+	h.Healthy = h.DNS.Reachable && (h.SMTP == nil || !h.SMTP.PortBlocked)
+	return h
+}
@@ -0,0 +1,53 @@
+package emailkit_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestValidator_Health(t *testing.T) {
+	v := emailkit.New().WithResolver(&stubResolver{
+		mx:    map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+		hosts: map[string][]string{"probe.test": {"192.0.2.1"}},
+	}).WithDNS()
+
+	_, err := v.Validate(context.Background(), "user@example.com")
+	assert.NoError(t, err)
+
+	h := v.Health(context.Background(), emailkit.HealthOptions{DNSProbe: "probe.test"})
+	assert.True(t, h.Healthy)
+	assert.True(t, h.DNS.Reachable)
+	assert.Empty(t, h.DNS.Error)
+	assert.Equal(t, 1, h.MXCacheEntries)
+	assert.Equal(t, 0, h.GreylistEntries)
+	assert.Nil(t, h.SMTP)
+}
+
+func TestValidator_HealthResolverDown(t *testing.T) {
+	v := emailkit.New().WithResolver(&stubResolver{}).WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Greylist:   emailkit.NewMemoryGreylistStore(),
+	})
+	defer func() { _ = v.Close() }()
+
+	h := v.Health(context.Background())
+	assert.False(t, h.Healthy)
+	assert.False(t, h.DNS.Reachable)
+	assert.NotEmpty(t, h.DNS.Error)
+	if assert.NotNil(t, h.SMTP) {
+		assert.False(t, h.SMTP.PortBlocked)
+		assert.True(t, h.SMTP.LastProbe.IsZero())
+		assert.Equal(t, 0, h.SMTP.IdleConns)
+		assert.Nil(t, h.SMTP.HostConcurrency, "not adaptive")
+		if assert.Len(t, h.SMTP.Identities, 1) {
+			assert.Equal(t, "verify@myapp.com", h.SMTP.Identities[0].Identity.MailFrom)
+			assert.Zero(t, h.SMTP.Identities[0].Probes)
+		}
+	}
+}
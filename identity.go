@@ -0,0 +1,46 @@
+package emailkit
+
+import (
+	"context"
+
+	"github.com/optimode/emailkit/check"
+)
+
+// SMTPIdentity is a HELO name and MAIL FROM address pair used by the SMTP
+// level (see SMTPOptions.Identities).
+type SMTPIdentity = check.SMTPIdentity
+
+// IdentityHealth is the verification outcome of one SMTPIdentity, as
+// returned by CheckIdentities.
+type IdentityHealth = check.IdentityHealth
+
+// IdentityStats counts the RCPT probes made with one SMTPIdentity; see
+// SMTPHealth.Identities.
+type IdentityStats = check.IdentityStats
+
+// WithWorker tags ctx with the index of the worker goroutine validating
+// under it, for SMTPOptions.IdentityPerWorker. ValidateMany tags its
+// workers itself; use it when running your own pool of goroutines over
+// Validate.
+func WithWorker(ctx context.Context, worker int) context.Context {
+	return check.WithWorker(ctx, worker)
+}
+
+// CheckIdentities verifies every SMTP identity now — the primary
+// HeloDomain/MailFrom first, then SMTPOptions.Identities — and reports,
+// per identity, whether its HELO name resolves to the probe IP, whether
+// its MAIL FROM domain resolves, and the SPF result for the probe IP.
+// Run it at startup or from a health endpoint to flag misconfigured
+// identities before they hurt the probe acceptance rate.
+//
+// Returns nil if SMTP is not configured. DNS queries go through the
+// resolver set with WithResolver.
+func (v *Validator) CheckIdentities(ctx context.Context) ([]IdentityHealth, error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	if v.identities == nil {
+		return nil, nil
+	}
+	return v.identities.Verify(ctx), nil
+}
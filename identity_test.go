@@ -0,0 +1,83 @@
+package emailkit_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestCheckIdentities(t *testing.T) {
+	r := &stubResolver{
+		mx: map[string][]*net.MX{"myapp.com": {{Host: "mx.myapp.com.", Pref: 10}}},
+		hosts: map[string][]string{
+			"probe1.myapp.com": {"192.0.2.10"},
+			"probe2.myapp.com": {"192.0.2.10"},
+			"other.example":    {"198.51.100.1"},
+		},
+		txt: map[string][]string{"myapp.com": {"v=spf1 ip4:192.0.2.10 -all"}},
+	}
+	v := emailkit.New().WithResolver(r).WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "probe1.myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Identities: []emailkit.SMTPIdentity{
+			{HeloDomain: "probe2.myapp.com", MailFrom: "check@myapp.com"},
+			{HeloDomain: "probe3.myapp.com", MailFrom: "verify@other.example"},
+		},
+		ProbeIP: net.ParseIP("192.0.2.10"),
+	})
+	defer func() { _ = v.Close() }()
+
+	health, err := v.CheckIdentities(context.Background())
+	require.NoError(t, err)
+	require.Len(t, health, 3)
+
+	assert.Equal(t, "probe1.myapp.com", health[0].Identity.HeloDomain)
+	assert.True(t, health[0].Healthy)
+	assert.True(t, health[1].Healthy)
+	assert.False(t, health[2].Healthy)
+	assert.Equal(t, "none", health[2].SPF)
+	assert.Contains(t, health[2].Problems, "HELO name probe3.myapp.com does not resolve")
+}
+
+func TestCheckIdentities_SourceIPIsProbeIP(t *testing.T) {
+	r := &stubResolver{
+		mx:    map[string][]*net.MX{"myapp.com": {{Host: "mx.myapp.com.", Pref: 10}}},
+		hosts: map[string][]string{"probe1.myapp.com": {"192.0.2.10"}},
+		txt:   map[string][]string{"myapp.com": {"v=spf1 ip4:192.0.2.10 -all"}},
+	}
+	v := emailkit.New().WithResolver(r).WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "probe1.myapp.com",
+		MailFrom:   "verify@myapp.com",
+		SourceIP:   net.ParseIP("192.0.2.10"),
+	})
+	defer func() { _ = v.Close() }()
+
+	health, err := v.CheckIdentities(context.Background())
+	require.NoError(t, err)
+	require.Len(t, health, 1)
+	assert.Equal(t, "192.0.2.10", health[0].ProbeIP)
+	assert.True(t, health[0].Healthy)
+}
+
+func TestCheckIdentities_NoSMTP(t *testing.T) {
+	health, err := emailkit.New().CheckIdentities(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, health)
+}
+
+func TestWithSMTP_IncompleteIdentity(t *testing.T) {
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Identities: []emailkit.SMTPIdentity{{HeloDomain: "probe2.myapp.com"}},
+	})
+	defer func() { _ = v.Close() }()
+
+	_, err := v.Validate(context.Background(), "user@example.com")
+	assert.ErrorIs(t, err, emailkit.ErrInvalidSMTPOptions)
+}
@@ -0,0 +1,140 @@
+package emailkit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/internal/provider"
+)
+
+// DomainReport is the mail infrastructure picture of a domain, as
+// returned by InspectDomain.
+type DomainReport struct {
+	Domain   string     `json:"domain"`
+	Provider string     `json:"provider,omitempty"` // e.g. "google", "microsoft"; "" if unrecognized
+	MX       []MXReport `json:"mx"`
+}
+
+// MXReport describes one MX host of a DomainReport.
+type MXReport struct {
+	Host     string     `json:"host"`
+	Pref     uint16     `json:"pref"`
+	IPs      []IPReport `json:"ips,omitempty"`
+	Provider string     `json:"provider,omitempty"`
+	// SMTP fields are only filled when the Validator has SMTP configured.
+	Banner    string `json:"banner,omitempty"`
+	Software  string `json:"software,omitempty"` // MTA guess from banner/EHLO, e.g. "Postfix"
+	StartTLS  bool   `json:"startTLS"`
+	SMTPError string `json:"smtpError,omitempty"`
+	Error     string `json:"error,omitempty"` // address lookup failure
+}
+
+// IPReport describes one address of an MX host.
+type IPReport struct {
+	Address string   `json:"address"`
+	PTR     []string `json:"ptr,omitempty"`
+	ASN     string   `json:"asn,omitempty"`     // e.g. "15169"
+	ASName  string   `json:"asName,omitempty"`  // e.g. "GOOGLE, US"
+	Country string   `json:"country,omitempty"` // registration country of the address block, e.g. "US"
+}
+
+// InspectDomain reports the mail infrastructure of a domain for security
+// and deliverability review: MX hosts in preference order, their addresses
+// with PTR names, origin ASN and country (via Team Cymru's DNS service),
+// and the detected provider. If SMTP is configured (WithSMTP), every MX
+// host is also greeted to record its banner, STARTTLS support and a
+// software guess; no mail transaction is started. Greetings honor the SMTP
+// level's pause, blocked port detection, probe schedule, quota and
+// limiter; when they may not run, SMTPError says why.
+//
+// DNS queries go through the resolver set with WithResolver. Per-host
+// failures are recorded in the report; an error is returned only for
+// configuration errors, an invalid domain or a failed MX lookup.
+func (v *Validator) InspectDomain(ctx context.Context, domain string) (DomainReport, error) {
+	if v.err != nil {
+		return DomainReport{}, v.err
+	}
+	parsed := parse.NewDomain(domain)
+	if !parsed.Valid {
+		return DomainReport{Domain: parsed.Raw}, fmt.Errorf("emailkit: inspect %q: invalid domain", parsed.Raw)
+	}
+	report := DomainReport{Domain: parsed.Domain}
+
+	mxRecords, err := v.resolver.LookupMX(ctx, parsed.Domain)
+	if err != nil {
+		return report, fmt.Errorf("emailkit: inspect %q: MX lookup: %w", parsed.Domain, err)
+	}
+	sort.SliceStable(mxRecords, func(i, j int) bool {
+		return mxRecords[i].Pref < mxRecords[j].Pref
+	})
+
+	skip := v.greetSkipped(ctx, parsed.Domain, mxRecords)
+	hosts := make([]string, 0, len(mxRecords))
+	for _, mx := range mxRecords {
+		host := strings.TrimSuffix(mx.Host, ".")
+		hosts = append(hosts, host)
+		report.MX = append(report.MX, v.inspectMX(ctx, host, mx.Pref, skip))
+	}
+	report.Provider = provider.Detect(hosts)
+	return report, nil
+}
+
+// greetSkipped returns why the MX hosts of domain may not be greeted, or
+// "" if they may. mxRecords must be sorted by preference.
+func (v *Validator) greetSkipped(ctx context.Context, domain string, mxRecords []*net.MX) string {
+	switch {
+	case v.smtpPool == nil:
+		return ""
+	case v.SMTPPaused():
+		return "skipped: SMTP probing paused"
+	case v.SMTPPortBlocked():
+		return "skipped: outbound port 25 blocked"
+	}
+	if res, deferred := v.smtp.ReserveConnect(ctx, domain, mxRecords); deferred {
+		return res.Details
+	}
+	return ""
+}
+
+// inspectMX describes one MX host; skip, if set, is recorded as its
+// SMTPError instead of greeting it.
+func (v *Validator) inspectMX(ctx context.Context, host string, pref uint16, skip string) MXReport {
+	r := MXReport{Host: host, Pref: pref, Provider: provider.Detect([]string{host})}
+
+	addrs, err := v.resolver.LookupHost(ctx, host)
+	if err != nil {
+		r.Error = err.Error()
+	}
+	for _, addr := range addrs {
+		ip := IPReport{Address: addr}
+		if names, err := v.resolver.LookupAddr(ctx, addr); err == nil {
+			for _, n := range names {
+				ip.PTR = append(ip.PTR, strings.TrimSuffix(n, "."))
+			}
+		}
+		if info, err := (check.CymruGeo{Resolver: resolverRef{v}}).LookupIP(ctx, addr); err == nil {
+			ip.ASN, ip.ASName, ip.Country = info.ASN, info.ASName, info.Country
+		}
+		r.IPs = append(r.IPs, ip)
+	}
+
+	switch {
+	case v.smtpPool == nil || ctx.Err() != nil:
+	case skip != "":
+		r.SMTPError = skip
+	default:
+		g, err := v.smtpPool.Greet(ctx, host)
+		if err != nil {
+			r.SMTPError = err.Error()
+		}
+		r.Banner = g.Banner
+		r.StartTLS = g.StartTLS
+		r.Software = provider.Software(g.Banner, strings.Join(g.EHLO, "\n"))
+	}
+	return r
+}
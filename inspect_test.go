@@ -0,0 +1,97 @@
+package emailkit_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+func TestInspectDomain(t *testing.T) {
+	r := &stubResolver{
+		mx: map[string][]*net.MX{"example.com": {
+			{Host: "alt1.aspmx.l.google.com.", Pref: 20},
+			{Host: "aspmx.l.google.com.", Pref: 10},
+		}},
+		hosts: map[string][]string{
+			"aspmx.l.google.com": {"142.250.27.26", "2a00:1450:4025:c03::1a"},
+		},
+		addr: map[string][]string{"142.250.27.26": {"rb-in-f26.1e100.net."}},
+		txt: map[string][]string{
+			"26.27.250.142.origin.asn.cymru.com":                                                    {"15169 | 142.250.0.0/15 | US | arin | 2012-05-24"},
+			"a.1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.3.0.c.0.5.2.0.4.0.5.4.1.0.0.a.2.origin6.asn.cymru.com": {"15169 | 2a00:1450::/32 | IE | ripencc | 2009-10-05"},
+			"AS15169.asn.cymru.com":                                                                 {"15169 | US | arin | 2000-03-30 | GOOGLE, US"},
+		},
+	}
+	v := emailkit.New().WithResolver(r)
+
+	report, err := v.InspectDomain(context.Background(), "Example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com", report.Domain)
+	assert.Equal(t, "google", report.Provider)
+	require.Len(t, report.MX, 2)
+
+	primary := report.MX[0]
+	assert.Equal(t, "aspmx.l.google.com", primary.Host)
+	assert.Equal(t, uint16(10), primary.Pref)
+	require.Len(t, primary.IPs, 2)
+	assert.Equal(t, emailkit.IPReport{
+		Address: "142.250.27.26",
+		PTR:     []string{"rb-in-f26.1e100.net"},
+		ASN:     "15169",
+		ASName:  "GOOGLE, US",
+		Country: "US",
+	}, primary.IPs[0])
+	assert.Equal(t, "15169", primary.IPs[1].ASN)
+	assert.Equal(t, "IE", primary.IPs[1].Country)
+	assert.Empty(t, primary.Banner, "no SMTP configured")
+
+	backup := report.MX[1]
+	assert.Empty(t, backup.IPs)
+	assert.Contains(t, backup.Error, "no such host")
+}
+
+func TestInspectDomain_Errors(t *testing.T) {
+	v := emailkit.New().WithResolver(&stubResolver{})
+
+	_, err := v.InspectDomain(context.Background(), "not a domain")
+	assert.Error(t, err)
+
+	_, err = v.InspectDomain(context.Background(), "example.com")
+	assert.ErrorContains(t, err, "emailkit: inspect \"example.com\": MX lookup")
+}
+
+func TestInspectDomain_GreetReservation(t *testing.T) {
+	v := emailkit.NewOffline(fakeNetwork()).WithSMTP(emailkit.SMTPOptions{
+		HeloDomain:     "verifier.test",
+		MailFrom:       "verify@verifier.test",
+		ConnectTimeout: time.Second,
+		CommandTimeout: time.Second,
+		Schedule:       &emailkit.ProbeSchedule{DailyBudget: map[string]int{"example.com": 1}},
+	})
+	defer func() { _ = v.Close() }()
+	ctx := context.Background()
+
+	report, err := v.InspectDomain(ctx, "example.com")
+	require.NoError(t, err)
+	require.Len(t, report.MX, 1)
+	assert.NotEmpty(t, report.MX[0].Banner)
+	assert.Empty(t, report.MX[0].SMTPError)
+
+	// Greetings spend the daily budget like probes
+	report, err = v.InspectDomain(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Empty(t, report.MX[0].Banner)
+	assert.Contains(t, report.MX[0].SMTPError, "SMTP probe deferred")
+
+	v.PauseSMTP(true)
+	report, err = v.InspectDomain(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "skipped: SMTP probing paused", report.MX[0].SMTPError)
+}
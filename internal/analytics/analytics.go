@@ -0,0 +1,142 @@
+// Package analytics aggregates SMTP probe outcomes per provider and per
+// MX host over a rolling window.
+package analytics
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Config configures a Recorder.
+type Config struct {
+	Window  time.Duration    // span aggregated; default: 1h
+	Buckets int              // resolution of the window; default: 60
+	Now     func() time.Time // injectable for testing
+}
+
+// Stats are the aggregated outcomes of one key.
+type Stats struct {
+	Key       string
+	Probes    int64
+	Accepted  int64 // 2xx
+	Temporary int64 // 4xx
+	Permanent int64 // 5xx
+	Errors    int64 // no reply: connection or protocol failures
+	Latency   time.Duration
+}
+
+func (s *Stats) add(o Stats) {
+	s.Probes += o.Probes
+	s.Accepted += o.Accepted
+	s.Temporary += o.Temporary
+	s.Permanent += o.Permanent
+	s.Errors += o.Errors
+	s.Latency += o.Latency
+}
+
+// series is a ring of buckets; a bucket holds the outcomes of one slice
+// of the window, tagged with its slice number so stale ones are skipped.
+type series struct {
+	slot  []int64
+	stats []Stats
+}
+
+// Recorder aggregates outcomes. It is safe for concurrent use.
+type Recorder struct {
+	cfg    Config
+	bucket time.Duration
+
+	mu        sync.Mutex
+	providers map[string]*series
+	hosts     map[string]*series
+}
+
+// New creates a Recorder.
+func New(cfg Config) *Recorder {
+	if cfg.Window <= 0 {
+		cfg.Window = time.Hour
+	}
+	if cfg.Buckets <= 0 {
+		cfg.Buckets = 60
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Recorder{
+		cfg:       cfg,
+		bucket:    max(cfg.Window/time.Duration(cfg.Buckets), 1),
+		providers: make(map[string]*series),
+		hosts:     make(map[string]*series),
+	}
+}
+
+// Window returns the span aggregated.
+func (r *Recorder) Window() time.Duration { return r.cfg.Window }
+
+// Record counts one reply from host, an MX host of provider: code is the
+// SMTP reply code, 0 if the host gave none.
+func (r *Recorder) Record(provider, host string, code int, latency time.Duration) {
+	o := Stats{Probes: 1, Latency: latency}
+	switch {
+	case code >= 200 && code < 300:
+		o.Accepted = 1
+	case code >= 400 && code < 500:
+		o.Temporary = 1
+	case code >= 500:
+		o.Permanent = 1
+	default:
+		o.Errors = 1
+	}
+	slot := r.cfg.Now().UnixNano() / int64(r.bucket)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.add(r.providers, provider, slot, o)
+	r.add(r.hosts, host, slot, o)
+}
+
+// add counts o in key's bucket for slot. r.mu must be held.
+func (r *Recorder) add(m map[string]*series, key string, slot int64, o Stats) {
+	s := m[key]
+	if s == nil {
+		s = &series{slot: make([]int64, r.cfg.Buckets), stats: make([]Stats, r.cfg.Buckets)}
+		m[key] = s
+	}
+	i := slot % int64(r.cfg.Buckets)
+	if s.slot[i] != slot {
+		s.slot[i], s.stats[i] = slot, Stats{}
+	}
+	s.stats[i].add(o)
+}
+
+// Snapshot returns the outcomes inside the window per provider and per
+// MX host, by key. Keys without outcomes in the window are left out, and
+// dropped.
+func (r *Recorder) Snapshot() (providers, hosts []Stats) {
+	slot := r.cfg.Now().UnixNano() / int64(r.bucket)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sum(r.providers, slot), r.sum(r.hosts, slot)
+}
+
+// sum aggregates the live buckets of every key. r.mu must be held.
+func (r *Recorder) sum(m map[string]*series, now int64) []Stats {
+	var out []Stats
+	for key, s := range m {
+		total := Stats{Key: key}
+		for i, slot := range s.slot {
+			if slot > now-int64(r.cfg.Buckets) && slot <= now {
+				total.add(s.stats[i])
+			}
+		}
+		if total.Probes == 0 {
+			delete(m, key)
+			continue
+		}
+		out = append(out, total)
+	}
+	slices.SortFunc(out, func(a, b Stats) int { return cmp.Compare(a.Key, b.Key) })
+	return out
+}
@@ -0,0 +1,44 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/internal/analytics"
+)
+
+func TestRecorder(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	r := analytics.New(analytics.Config{Window: 10 * time.Minute, Buckets: 10, Now: func() time.Time { return now }})
+
+	r.Record("google", "mx1.google.com", 250, 100*time.Millisecond)
+	r.Record("google", "mx2.google.com", 451, 300*time.Millisecond)
+	now = now.Add(5 * time.Minute)
+	r.Record("google", "mx1.google.com", 550, 200*time.Millisecond)
+	r.Record("example.com", "mx.example.com", 0, time.Second)
+
+	providers, hosts := r.Snapshot()
+	assert.Equal(t, []analytics.Stats{
+		{Key: "example.com", Probes: 1, Errors: 1, Latency: time.Second},
+		{Key: "google", Probes: 3, Accepted: 1, Temporary: 1, Permanent: 1, Latency: 600 * time.Millisecond},
+	}, providers)
+	assert.Len(t, hosts, 3)
+	assert.Equal(t, analytics.Stats{Key: "mx1.google.com", Probes: 2, Accepted: 1, Permanent: 1, Latency: 300 * time.Millisecond}, hosts[1])
+
+	// The first outcomes leave the window
+	now = now.Add(6 * time.Minute)
+	providers, _ = r.Snapshot()
+	assert.Equal(t, []analytics.Stats{
+		{Key: "example.com", Probes: 1, Errors: 1, Latency: time.Second},
+		{Key: "google", Probes: 1, Permanent: 1, Latency: 200 * time.Millisecond},
+	}, providers)
+
+	// A bucket reused after a full turn starts empty
+	now = now.Add(10 * time.Minute)
+	r.Record("google", "mx1.google.com", 250, time.Millisecond)
+	providers, hosts = r.Snapshot()
+	assert.Equal(t, []analytics.Stats{{Key: "google", Probes: 1, Accepted: 1, Latency: time.Millisecond}}, providers)
+	assert.Len(t, hosts, 1)
+}
@@ -0,0 +1,201 @@
+// Package bounce maps SMTP reply codes, enhanced status codes (RFC 3463)
+// and well-known provider response texts to bounce categories.
+package bounce
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/optimode/emailkit/types"
+)
+
+// Explanation is the interpretation of an SMTP response.
+type Explanation struct {
+	Code         int             `json:"code"`
+	EnhancedCode string          `json:"enhancedCode,omitempty"` // e.g. "5.1.1"
+	Category     types.CheckCode `json:"category"`
+	Permanent    bool            `json:"permanent"` // 5xx except mailbox full: retrying won't help
+	Provider     string          `json:"provider,omitempty"`
+	Description  string          `json:"description"`
+}
+
+var enhancedRe = regexp.MustCompile(`\b([245])\.(\d{1,3})\.(\d{1,3})\b`)
+
+// textPattern recognizes a response by a lowercase substring.
+// Provider is set for provider-specific wording.
+type textPattern struct {
+	substr   string
+	category types.CheckCode
+	provider string
+}
+
+// textPatterns are checked in order, before enhanced and basic codes,
+// because response text is usually the most specific signal. Generic
+// mailbox wording is the exception: under an X.7.X (security or policy)
+// enhanced code, "does not exist" or "suspended" is about the sender, so
+// the code wins (see mailboxWording).
+var textPatterns = []textPattern{
+	// Provider-specific responses
+	{"the email account that you tried to reach does not exist", types.CodeMailboxUnknown, "google"},
+	{"the email account that you tried to reach is over quota", types.CodeMailboxFull, "google"},
+	{"the email account that you tried to reach is disabled", types.CodeMailboxDisabled, "google"},
+	{"recipient address rejected: access denied", types.CodeMailboxUnknown, "microsoft"},
+	{"requested action not taken: mailbox unavailable", types.CodeMailboxUnknown, "microsoft"},
+	{"part of their network is on our block list", types.CodeBlocked, "microsoft"},
+	{"[ts01]", types.CodeBlocked, "yahoo"},
+	{"[ts03]", types.CodeBlocked, "yahoo"},
+	{"[tss04]", types.CodeRateLimited, "yahoo"},
+	{"[tsn01]", types.CodeMailboxUnknown, "yahoo"},
+
+	// Generic wording
+	{"greylist", types.CodeGreylisted, ""},
+	{"graylist", types.CodeGreylisted, ""},
+	{"over quota", types.CodeMailboxFull, ""},
+	{"quota exceeded", types.CodeMailboxFull, ""},
+	{"mailbox full", types.CodeMailboxFull, ""},
+	{"mailbox is full", types.CodeMailboxFull, ""},
+	{"insufficient storage", types.CodeMailboxFull, ""},
+	{"user unknown", types.CodeMailboxUnknown, ""},
+	{"unknown user", types.CodeMailboxUnknown, ""},
+	{"no such user", types.CodeMailboxUnknown, ""},
+	{"nosuchuser", types.CodeMailboxUnknown, ""},
+	{"user not found", types.CodeMailboxUnknown, ""},
+	{"recipient not found", types.CodeMailboxUnknown, ""},
+	{"invalid recipient", types.CodeMailboxUnknown, ""},
+	{"does not exist", types.CodeMailboxUnknown, ""},
+	{"account disabled", types.CodeMailboxDisabled, ""},
+	{"account has been disabled", types.CodeMailboxDisabled, ""},
+	{"mailbox disabled", types.CodeMailboxDisabled, ""},
+	{"inactive", types.CodeMailboxDisabled, ""},
+	{"suspended", types.CodeMailboxDisabled, ""},
+	{"spamhaus", types.CodeBlocked, ""},
+	{"blacklist", types.CodeBlocked, ""},
+	{"blocklist", types.CodeBlocked, ""},
+	{"block list", types.CodeBlocked, ""},
+	{"dnsbl", types.CodeBlocked, ""},
+	{"reputation", types.CodeBlocked, ""},
+	{"rate limit", types.CodeRateLimited, ""},
+	{"too many", types.CodeRateLimited, ""},
+	{"throttl", types.CodeRateLimited, ""},
+}
+
+// enhancedCategories maps "subject.detail" of an enhanced status code.
+var enhancedCategories = map[string]types.CheckCode{
+	"1.1":  types.CodeMailboxUnknown,  // bad destination mailbox address
+	"1.2":  types.CodeBadDomain,       // bad destination system address
+	"1.3":  types.CodeMailboxUnknown,  // bad destination mailbox address syntax
+	"1.6":  types.CodeMailboxDisabled, // destination mailbox has moved
+	"2.1":  types.CodeMailboxDisabled, // mailbox disabled
+	"2.2":  types.CodeMailboxFull,     // mailbox full
+	"3.1":  types.CodeServiceUnavailable,
+	"3.2":  types.CodeServiceUnavailable,
+	"4.4":  types.CodeBadDomain, // unable to route
+	"7.1":  types.CodeBlocked,   // delivery not authorized
+	"7.26": types.CodeBlocked,   // multiple authentication checks failed
+}
+
+// basicCategories maps plain reply codes (RFC 5321).
+var basicCategories = map[int]types.CheckCode{
+	250: types.CodeAccepted,
+	251: types.CodeAccepted,
+	252: types.CodeAccepted,
+	421: types.CodeServiceUnavailable,
+	450: types.CodeTemporary,
+	451: types.CodeTemporary,
+	452: types.CodeMailboxFull,
+	550: types.CodeMailboxUnknown,
+	551: types.CodeMailboxUnknown,
+	552: types.CodeMailboxFull,
+	553: types.CodeMailboxUnknown,
+	554: types.CodePolicy,
+}
+
+var descriptions = map[types.CheckCode]string{
+	types.CodeAccepted:           "the server accepted the recipient",
+	types.CodeMailboxUnknown:     "the mailbox does not exist",
+	types.CodeMailboxFull:        "the mailbox is over its storage quota; delivery may succeed once it is emptied",
+	types.CodeMailboxDisabled:    "the mailbox exists but is disabled or has moved",
+	types.CodeBadDomain:          "the destination domain or mail system is invalid",
+	types.CodeGreylisted:         "the server is greylisting; a later retry will likely succeed",
+	types.CodeRateLimited:        "the server is throttling the sender",
+	types.CodeBlocked:            "the sender (IP or domain) is blocked by the receiver's policy or a blocklist",
+	types.CodePolicy:             "the transaction was refused for policy reasons",
+	types.CodeServiceUnavailable: "the mail service is unavailable",
+	types.CodeTemporary:          "temporary failure; retrying later may succeed",
+	types.CodeUnknown:            "unrecognized response",
+}
+
+// Explain interprets an SMTP reply code and its response text.
+// Text patterns win over enhanced status codes, which win over the basic
+// code, except that X.7.X policy codes win over generic mailbox wording.
+func Explain(code int, msg string) Explanation {
+	e := Explanation{
+		Code:      code,
+		Category:  types.CodeUnknown,
+		Permanent: code >= 500,
+	}
+	policy := false // X.7.X: security or policy status
+	if m := enhancedRe.FindStringSubmatch(msg); m != nil {
+		e.EnhancedCode = m[0]
+		policy = m[2] == "7"
+	}
+
+	lower := strings.ToLower(msg)
+	matched := false
+	for _, p := range textPatterns {
+		if policy && mailboxWording(p) {
+			continue
+		}
+		if strings.Contains(lower, p.substr) {
+			e.Category, e.Provider = p.category, p.provider
+			matched = true
+			break
+		}
+	}
+	if !matched && e.EnhancedCode != "" {
+		parts := strings.SplitN(e.EnhancedCode, ".", 2)
+		if cat, ok := enhancedCategories[parts[1]]; ok {
+			e.Category = cat
+			matched = true
+		} else if policy {
+			e.Category = types.CodePolicy
+			matched = true
+		}
+	}
+	if !matched {
+		if cat, ok := basicCategories[code]; ok {
+			e.Category = cat
+		} else if code >= 200 && code < 300 {
+			e.Category = types.CodeAccepted
+		} else if code >= 400 && code < 500 {
+			e.Category = types.CodeTemporary
+		}
+	}
+
+	// A permanent reply is not greylisting, whatever its wording: it is
+	// treated as policy
+	if e.Category == types.CodeGreylisted && code >= 500 {
+		e.Category = types.CodePolicy
+	}
+	// A full mailbox exists and recovers once emptied, even after a 552
+	if e.Category == types.CodeMailboxFull {
+		e.Permanent = false
+	}
+
+	e.Description = descriptions[e.Category]
+	return e
+}
+
+// mailboxWording reports whether p is generic wording about the
+// recipient's mailbox, which policy rejections reuse for the sender
+// ("sender domain does not exist", "sending IP suspended").
+func mailboxWording(p textPattern) bool {
+	if p.provider != "" {
+		return false
+	}
+	switch p.category {
+	case types.CodeMailboxUnknown, types.CodeMailboxDisabled, types.CodeMailboxFull:
+		return true
+	}
+	return false
+}
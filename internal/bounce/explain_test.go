@@ -0,0 +1,59 @@
+package bounce_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/internal/bounce"
+	"github.com/optimode/emailkit/types"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      int
+		msg       string
+		category  types.CheckCode
+		permanent bool
+		enhanced  string
+		provider  string
+	}{
+		{"accepted", 250, "2.1.5 OK", types.CodeAccepted, false, "2.1.5", ""},
+		{"gmail unknown", 550, "5.1.1 The email account that you tried to reach does not exist. Please try double-checking", types.CodeMailboxUnknown, true, "5.1.1", "google"},
+		{"gmail over quota", 452, "4.2.2 The email account that you tried to reach is over quota.", types.CodeMailboxFull, false, "4.2.2", "google"},
+		{"microsoft access denied", 550, "5.4.1 Recipient address rejected: Access denied", types.CodeMailboxUnknown, true, "5.4.1", "microsoft"},
+		{"yahoo deferred", 421, "4.7.0 [TSS04] Messages from 1.2.3.4 temporarily deferred", types.CodeRateLimited, false, "4.7.0", "yahoo"},
+		{"greylisted", 450, "4.7.1 Greylisted, please try again later", types.CodeGreylisted, false, "4.7.1", ""},
+		{"spamhaus", 554, "5.7.1 Service unavailable; client host blocked using zen.spamhaus.org", types.CodeBlocked, true, "5.7.1", ""},
+		{"policy over mailbox wording", 550, "5.7.1 Sender domain does not exist", types.CodeBlocked, true, "5.7.1", ""},
+		{"policy suspended", 550, "5.7.1 Sending IP suspended", types.CodeBlocked, true, "5.7.1", ""},
+		{"unmapped policy code", 550, "5.7.0 Account inactive, contact postmaster", types.CodePolicy, true, "5.7.0", ""},
+		{"mailbox wording", 550, "5.2.0 Account inactive", types.CodeMailboxDisabled, true, "5.2.0", ""},
+		{"enhanced only", 550, "5.2.1 requested action aborted", types.CodeMailboxDisabled, true, "5.2.1", ""},
+		{"enhanced bad domain", 550, "5.1.2 host unknown", types.CodeBadDomain, true, "5.1.2", ""},
+		{"basic 552", 552, "requested mail action aborted", types.CodeMailboxFull, false, "", ""},
+		{"basic 550", 550, "Requested action not taken", types.CodeMailboxUnknown, true, "", ""},
+		{"basic 421", 421, "closing channel", types.CodeServiceUnavailable, false, "", ""},
+		{"unmapped 4xx", 471, "local problem", types.CodeTemporary, false, "", ""},
+		{"unmapped 5xx", 559, "weird", types.CodeUnknown, true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := bounce.Explain(tt.code, tt.msg)
+			assert.Equal(t, tt.code, e.Code)
+			assert.Equal(t, tt.category, e.Category)
+			assert.Equal(t, tt.permanent, e.Permanent)
+			assert.Equal(t, tt.enhanced, e.EnhancedCode)
+			assert.Equal(t, tt.provider, e.Provider)
+			assert.NotEmpty(t, e.Description)
+		})
+	}
+}
+
+func TestExplain_GreylistWordingOnPermanentCode(t *testing.T) {
+	e := bounce.Explain(550, "greylisting policy violation")
+	assert.Equal(t, types.CodePolicy, e.Category)
+	assert.True(t, e.Permanent)
+}
@@ -0,0 +1,91 @@
+// Package compliance holds the embedded compliance policy pack: sanctioned
+// jurisdictions and government-restricted domains.
+package compliance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Kinds of policy entries.
+const (
+	Sanctioned = "sanctioned"
+	Restricted = "restricted"
+)
+
+// Entry is a policy entry.
+type Entry struct {
+	Kind   string // Sanctioned or Restricted
+	Domain string // lowercase ASCII domain or TLD
+	Note   string // e.g. "Iran"
+}
+
+// pack is a policy pack with its entries indexed by domain.
+type pack struct {
+	entries  []Entry
+	byDomain map[string]Entry // the last entry of a domain wins
+}
+
+var policy atomic.Pointer[pack]
+
+func init() {
+	entries, _ := parse(strings.NewReader(rawPolicy))
+	store(entries)
+}
+
+func store(entries []Entry) {
+	p := &pack{entries: entries, byDomain: make(map[string]Entry, len(entries))}
+	for _, e := range entries {
+		p.byDomain[e.Domain] = e
+	}
+	policy.Store(p)
+}
+
+// Builtin returns the entries of the policy pack, in file order: the
+// embedded one, or the one given to Load.
+func Builtin() []Entry {
+	return slices.Clone(policy.Load().entries)
+}
+
+// Lookup returns the entry of the current pack for a domain or TLD,
+// without matching parent domains.
+func Lookup(domain string) (Entry, bool) {
+	e, ok := policy.Load().byDomain[domain]
+	return e, ok
+}
+
+// Load replaces the policy pack with the one read from r, in the format
+// of the embedded policy.txt, and returns how many entries it read. On an
+// error the pack is left as it was.
+func Load(r io.Reader) (int, error) {
+	entries, err := parse(r)
+	if err != nil {
+		return 0, err
+	}
+	store(entries)
+	return len(entries), nil
+}
+
+func parse(r io.Reader) ([]Entry, error) {
+	var out []Entry
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 2 || (fields[0] != Sanctioned && fields[0] != Restricted) {
+			return nil, fmt.Errorf("compliance: line %d: want \"sanctioned|restricted <domain> [note]\"", n)
+		}
+		out = append(out, Entry{
+			Kind:   fields[0],
+			Domain: strings.ToLower(fields[1]),
+			Note:   strings.Join(fields[2:], " "),
+		})
+	}
+	return out, sc.Err()
+}
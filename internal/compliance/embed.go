@@ -0,0 +1,12 @@
+//go:build !emailkit_nocompliance
+
+package compliance
+
+import _ "embed"
+
+// Embedded reports whether the policy pack is compiled into the binary;
+// the emailkit_nocompliance build tag leaves it out.
+const Embedded = true
+
+//go:embed policy.txt
+var rawPolicy string
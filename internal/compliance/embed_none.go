@@ -0,0 +1,10 @@
+//go:build emailkit_nocompliance
+
+package compliance
+
+// Embedded reports whether the policy pack is compiled into the binary;
+// the emailkit_nocompliance build tag leaves it out.
+const Embedded = false
+
+// rawPolicy is empty: the policy is loaded at runtime with Load.
+var rawPolicy string
@@ -1,9 +1,45 @@
 package disposable
 
-import "strings"
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+var disposableSet atomic.Pointer[map[string]struct{}]
+
+func init() {
+	set, _ := parse(strings.NewReader(rawList))
+	disposableSet.Store(&set)
+}
 
 // IsDisposable returns whether the given domain is a known disposable domain.
 func IsDisposable(domain string) bool {
-	_, ok := disposableSet[strings.ToLower(domain)]
+	_, ok := (*disposableSet.Load())[strings.ToLower(domain)]
 	return ok
 }
+
+// Load replaces the list with the domains read from r, one per line ("#"
+// starts a comment line), and returns how many it read. On a read error
+// the list is left as it was.
+func Load(r io.Reader) (int, error) {
+	set, err := parse(r)
+	if err != nil {
+		return 0, err
+	}
+	disposableSet.Store(&set)
+	return len(set), nil
+}
+
+func parse(r io.Reader) (map[string]struct{}, error) {
+	set := make(map[string]struct{})
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			set[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return set, sc.Err()
+}
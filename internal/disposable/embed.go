@@ -1,21 +1,12 @@
+//go:build !emailkit_nodisposable
+
 package disposable
 
-import (
-	_ "embed"
-	"strings"
-)
+import _ "embed"
+
+// Embedded reports whether the list is compiled into the binary; the
+// emailkit_nodisposable build tag leaves it out.
+const Embedded = true
 
 //go:embed list.txt
 var rawList string
-
-var disposableSet map[string]struct{}
-
-func init() {
-	disposableSet = make(map[string]struct{})
-	for _, line := range strings.Split(rawList, "\n") {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "#") {
-			disposableSet[strings.ToLower(line)] = struct{}{}
-		}
-	}
-}
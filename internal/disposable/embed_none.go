@@ -0,0 +1,10 @@
+//go:build emailkit_nodisposable
+
+package disposable
+
+// Embedded reports whether the list is compiled into the binary; the
+// emailkit_nodisposable build tag leaves it out.
+const Embedded = false
+
+// rawList is empty: the list is loaded at runtime with Load.
+var rawList string
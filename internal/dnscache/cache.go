@@ -1,10 +1,15 @@
 // Package dnscache provides a thread-safe, TTL-based cache for DNS MX lookups
 // with singleflight deduplication for concurrent requests to the same domain.
+// TXT lookups can share the cache's TTL and retry policy via LookupTXT.
 package dnscache
 
 import (
 	"context"
+	"errors"
+	"math/rand/v2"
 	"net"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 )
@@ -15,25 +20,98 @@ import (
 type Cache struct {
 	mu            sync.Mutex
 	entries       map[string]*entry
+	txt           map[string]*txtEntry
 	cacheTTL      time.Duration
 	lookupTimeout time.Duration
 	// resolver is injectable for testing
 	resolver interface {
 		LookupMX(ctx context.Context, name string) ([]*net.MX, error)
 	}
+	store Store // optional shared tier, see SetStore
+	// onHit, if set, is called for lookups answered without a query,
+	// see SetHitHook
+	onHit func(domain string, shared bool)
+	retry RetryPolicy // see SetRetry
+}
+
+// RetryPolicy repeats lookups that failed because of the resolver (a
+// timeout, SERVFAIL) before the failure is cached. NXDOMAIN is an answer
+// and never retried. The zero value does not retry.
+type RetryPolicy struct {
+	// Attempts is the total number of queries; 0 or 1 means no retry.
+	Attempts int
+	// Backoff is the wait before the second query, doubled for each
+	// further one.
+	Backoff time.Duration
+	// Jitter (0..1) randomizes each wait by up to ±Jitter of it, so
+	// workers retrying the same outage don't query in lockstep.
+	Jitter float64
+}
+
+// wait returns the pause before query attempt+1 (attempt is 1-based).
+func (p RetryPolicy) wait(attempt int) time.Duration {
+	d := p.Backoff << (attempt - 1)
+	if p.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// Store is an optional second cache tier shared between processes.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Load returns the stored entry for domain; ok is false on a miss.
+	Load(ctx context.Context, domain string) (e Entry, ok bool, err error)
+	// Save stores a completed lookup until e.Expires.
+	Save(ctx context.Context, e Entry) error
 }
 
 type entry struct {
-	records []*net.MX
+	records  []*net.MX
+	err      error
+	expires  time.Time
+	rtt      time.Duration // time the DNS query took; zero if not queried here
+	resolved time.Time     // when the answer was obtained; zero if unknown
+	done     chan struct{} // closed when lookup is complete
+}
+
+// txtEntry is a TXT lookup; TXT answers are kept apart from MX entries.
+type txtEntry struct {
+	records []string
 	err     error
 	expires time.Time
-	done    chan struct{} // closed when lookup is complete
+	done    chan struct{}
+}
+
+// txtResolver is implemented by resolvers that can look up TXT records,
+// such as *net.Resolver.
+type txtResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// Source says where a lookup's answer came from.
+type Source string
+
+const (
+	SourceResolver Source = "resolver" // a DNS query made for this lookup
+	SourceCache    Source = "cache"    // a cached answer
+	SourceInflight Source = "inflight" // joined a concurrent query for the same domain
+	SourceStore    Source = "store"    // the shared store (see SetStore)
+)
+
+// Info describes how LookupMXInfo answered.
+type Info struct {
+	Source Source
+	// RTT is how long the DNS query behind the answer took, also for
+	// cached answers; zero for answers from the store or Restore.
+	RTT time.Duration
 }
 
 // New creates a DNS cache with the given lookup timeout and cache TTL.
 func New(lookupTimeout, cacheTTL time.Duration) *Cache {
 	return &Cache{
 		entries:       make(map[string]*entry),
+		txt:           make(map[string]*txtEntry),
 		cacheTTL:      cacheTTL,
 		lookupTimeout: lookupTimeout,
 		resolver:      &net.Resolver{},
@@ -52,6 +130,12 @@ func NewWithResolver(lookupTimeout, cacheTTL time.Duration, r interface {
 // LookupMX returns MX records for the domain, using the cache when possible.
 // Concurrent lookups for the same domain are deduplicated via singleflight.
 func (c *Cache) LookupMX(domain string) ([]*net.MX, error) {
+	records, _, err := c.LookupMXInfo(domain)
+	return records, err
+}
+
+// LookupMXInfo is LookupMX that also reports where the answer came from.
+func (c *Cache) LookupMXInfo(domain string) ([]*net.MX, Info, error) {
 	c.mu.Lock()
 
 	if e, ok := c.entries[domain]; ok {
@@ -59,31 +143,267 @@ func (c *Cache) LookupMX(domain string) ([]*net.MX, error) {
 		case <-e.done:
 			// Completed entry - check if still valid
 			if time.Now().Before(e.expires) {
+				onHit := c.onHit
 				c.mu.Unlock()
-				return copyMX(e.records), e.err
+				if onHit != nil {
+					onHit(domain, false)
+				}
+				return copyMX(e.records), Info{Source: SourceCache, RTT: e.rtt}, e.err
 			}
 			// Expired, fall through to refresh
 		default:
 			// Lookup in progress - wait for it
+			onHit := c.onHit
 			c.mu.Unlock()
 			<-e.done
-			return copyMX(e.records), e.err
+			if onHit != nil {
+				onHit(domain, false)
+			}
+			return copyMX(e.records), Info{Source: SourceInflight, RTT: e.rtt}, e.err
 		}
 	}
 
 	// Start new lookup
 	e := &entry{done: make(chan struct{})}
 	c.entries[domain] = e
+	store, onHit := c.store, c.onHit
 	c.mu.Unlock()
 
-	ctx, cancel := context.WithTimeout(context.Background(), c.lookupTimeout)
-	defer cancel()
+	// Another process may already have resolved the domain
+	if store != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.lookupTimeout)
+		defer cancel()
+		if se, ok, err := store.Load(ctx, domain); err == nil && ok && time.Now().Before(se.Expires) {
+			e.records, e.err, e.expires = copyMX(se.Records), se.Err, se.Expires
+			close(e.done)
+			if onHit != nil {
+				onHit(domain, true)
+			}
+			return copyMX(e.records), Info{Source: SourceStore}, e.err
+		}
+	}
+
+	e.records, e.rtt, e.err = c.query(domain)
+	e.resolved = time.Now()
+	e.expires = e.resolved.Add(c.cacheTTL)
+	close(e.done)
+
+	// Share answers, but not transient failures: one timeout must not
+	// fail the domain for every process
+	if store != nil && (e.err == nil || isNotFound(e.err)) {
+		ctx, cancel := context.WithTimeout(context.Background(), c.lookupTimeout)
+		defer cancel()
+		_ = store.Save(ctx, Entry{Domain: domain, Records: copyMX(e.records), Err: e.err, Expires: e.expires})
+	}
+
+	return copyMX(e.records), Info{Source: SourceResolver, RTT: e.rtt}, e.err
+}
 
-	e.records, e.err = c.resolver.LookupMX(ctx, domain)
+// query resolves domain, retrying resolver failures per the retry policy.
+// rtt is the duration of the last query.
+func (c *Cache) query(domain string) (records []*net.MX, rtt time.Duration, err error) {
+	c.mu.Lock()
+	retry := c.retry
+	c.mu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), c.lookupTimeout)
+		start := time.Now()
+		records, err = c.resolver.LookupMX(ctx, domain)
+		rtt = time.Since(start)
+		cancel()
+		if err == nil || isNotFound(err) || attempt >= retry.Attempts {
+			return records, rtt, err
+		}
+		time.Sleep(retry.wait(attempt))
+	}
+}
+
+// LookupTXT returns the TXT records of name, cached and deduplicated like
+// MX lookups with the same TTL and retry policy. TXT answers are local to
+// the cache: they are not shared through the store, counted by Len or
+// included in Entries. The resolver must implement LookupTXT.
+func (c *Cache) LookupTXT(name string) ([]string, error) {
+	c.mu.Lock()
+	if e, ok := c.txt[name]; ok {
+		select {
+		case <-e.done:
+			if time.Now().Before(e.expires) {
+				c.mu.Unlock()
+				return slices.Clone(e.records), e.err
+			}
+		default:
+			c.mu.Unlock()
+			<-e.done
+			return slices.Clone(e.records), e.err
+		}
+	}
+	e := &txtEntry{done: make(chan struct{})}
+	c.txt[name] = e
+	retry := c.retry
+	c.mu.Unlock()
+
+	r, ok := c.resolver.(txtResolver)
+	if !ok {
+		e.err = errors.New("dnscache: resolver does not look up TXT records")
+	}
+	for attempt := 1; ok; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), c.lookupTimeout)
+		e.records, e.err = r.LookupTXT(ctx, name)
+		cancel()
+		if e.err == nil || isNotFound(e.err) || attempt >= retry.Attempts {
+			break
+		}
+		time.Sleep(retry.wait(attempt))
+	}
 	e.expires = time.Now().Add(c.cacheTTL)
 	close(e.done)
+	return slices.Clone(e.records), e.err
+}
+
+// SetRetry sets the policy for retrying resolver failures before they are
+// cached. Default: no retries.
+func (c *Cache) SetRetry(p RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retry = p
+}
+
+// Forget drops the cached answer for domain, so the next lookup queries
+// again. An in-flight lookup is left alone.
+func (c *Cache) Forget(domain string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[domain]; ok {
+		select {
+		case <-e.done:
+			delete(c.entries, domain)
+		default:
+		}
+	}
+}
 
-	return copyMX(e.records), e.err
+// Lookup returns the completed, unexpired MX answer cached for domain
+// without querying; ok is false if there is none.
+func (c *Cache) Lookup(domain string) (e Entry, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ce, found := c.entries[domain]
+	if !found {
+		return Entry{}, false
+	}
+	select {
+	case <-ce.done:
+	default:
+		return Entry{}, false
+	}
+	if !time.Now().Before(ce.expires) {
+		return Entry{}, false
+	}
+	return Entry{Domain: domain, Records: copyMX(ce.records), Err: ce.err, Expires: ce.expires}, true
+}
+
+// TXTNames returns the sorted names at or under domain whose TXT answers
+// are cached, e.g. "example.com" and "_dmarc.example.com".
+func (c *Cache) TXTNames(domain string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var names []string
+	now := time.Now()
+	for name, e := range c.txt {
+		select {
+		case <-e.done:
+		default:
+			continue
+		}
+		if now.Before(e.expires) && underDomain(name, domain) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Invalidate drops the completed MX answer of domain and the TXT answers
+// at or under it, and returns how many were dropped. In-flight lookups
+// are left alone, and the shared store is not touched.
+func (c *Cache) Invalidate(domain string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	if e, ok := c.entries[domain]; ok {
+		select {
+		case <-e.done:
+			delete(c.entries, domain)
+			n++
+		default:
+		}
+	}
+	for name, e := range c.txt {
+		if !underDomain(name, domain) {
+			continue
+		}
+		select {
+		case <-e.done:
+			delete(c.txt, name)
+			n++
+		default:
+		}
+	}
+	return n
+}
+
+// underDomain reports whether name is domain or one of its subdomains.
+func underDomain(name, domain string) bool {
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// Purge drops every completed MX and TXT answer and returns how many
+// were dropped. In-flight lookups are left alone, and the shared store
+// is not touched.
+func (c *Cache) Purge() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for domain, e := range c.entries {
+		select {
+		case <-e.done:
+			delete(c.entries, domain)
+			n++
+		default:
+		}
+	}
+	for name, e := range c.txt {
+		select {
+		case <-e.done:
+			delete(c.txt, name)
+			n++
+		default:
+		}
+	}
+	return n
+}
+
+// SetStore attaches a shared store consulted on local misses. Successful
+// and NXDOMAIN answers are written to it; transient failures are not.
+func (c *Cache) SetStore(s Store) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store = s
+}
+
+// SetHitHook sets a function called for every lookup answered without a
+// DNS query: from the cache, by joining an in-flight lookup, or from the
+// shared store (shared is true). It must not block.
+func (c *Cache) SetHitHook(f func(domain string, shared bool)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onHit = f
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
 }
 
 // Len returns the number of entries in the cache (for diagnostics).
@@ -93,6 +413,56 @@ func (c *Cache) Len() int {
 	return len(c.entries)
 }
 
+// Entry is an exported view of a completed cache entry.
+type Entry struct {
+	Domain  string
+	Records []*net.MX
+	Err     error
+	Expires time.Time
+}
+
+// Entries returns the completed, unexpired entries, e.g. for persisting
+// warm state between runs. In-flight lookups are skipped.
+func (c *Cache) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	out := make([]Entry, 0, len(c.entries))
+	for domain, e := range c.entries {
+		select {
+		case <-e.done:
+		default:
+			continue
+		}
+		if !now.Before(e.expires) {
+			continue
+		}
+		out = append(out, Entry{Domain: domain, Records: copyMX(e.records), Err: e.err, Expires: e.expires})
+	}
+	return out
+}
+
+// Restore adds entries to the cache, keeping their expiry. Expired entries
+// and domains that already have an entry are skipped.
+func (c *Cache) Restore(entries []Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for _, in := range entries {
+		if !now.Before(in.Expires) {
+			continue
+		}
+		if _, ok := c.entries[in.Domain]; ok {
+			continue
+		}
+		e := &entry{records: copyMX(in.Records), err: in.Err, expires: in.Expires, done: make(chan struct{})}
+		close(e.done)
+		c.entries[in.Domain] = e
+	}
+}
+
 // copyMX returns a deep copy of MX records to prevent callers from
 // mutating cached data (e.g., via sort.Slice).
 func copyMX(records []*net.MX) []*net.MX {
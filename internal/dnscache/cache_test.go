@@ -124,3 +124,274 @@ func TestCache_ReturnsCopy(t *testing.T) {
 	recs1[0].Host = "modified."
 	assert.NotEqual(t, recs1[0].Host, recs2[0].Host)
 }
+
+func TestCache_EntriesRestore(t *testing.T) {
+	r := &mockResolver{records: []*net.MX{{Host: "mx.example.com.", Pref: 10}}}
+	c := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	_, _ = c.LookupMX("example.com")
+
+	entries := c.Entries()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "example.com", entries[0].Domain)
+	assert.Equal(t, "mx.example.com.", entries[0].Records[0].Host)
+
+	expired := dnscache.Entry{Domain: "old.com", Expires: time.Now().Add(-time.Second)}
+	fresh := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	fresh.Restore(append(entries, expired))
+	assert.Equal(t, 1, fresh.Len())
+
+	// Restored entries answer without a lookup
+	recs, err := fresh.LookupMX("example.com")
+	assert.NoError(t, err)
+	assert.Len(t, recs, 1)
+	assert.Equal(t, int64(1), r.calls.Load())
+}
+
+// mapStore is an in-memory Store standing in for a shared backend.
+type mapStore struct {
+	mu      sync.Mutex
+	entries map[string]dnscache.Entry
+}
+
+func (s *mapStore) Load(_ context.Context, domain string) (dnscache.Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[domain]
+	return e, ok, nil
+}
+
+func (s *mapStore) Save(_ context.Context, e dnscache.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[e.Domain] = e
+	return nil
+}
+
+func TestCache_SharedStore(t *testing.T) {
+	store := &mapStore{entries: map[string]dnscache.Entry{}}
+	r := &mockResolver{records: []*net.MX{{Host: "mx.example.com.", Pref: 10}}}
+
+	// The first process resolves and publishes the answer
+	c1 := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	c1.SetStore(store)
+	_, err := c1.LookupMX("example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), r.calls.Load())
+
+	// A second process finds it in the store without resolving
+	c2 := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	c2.SetStore(store)
+	recs, err := c2.LookupMX("example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "mx.example.com.", recs[0].Host)
+	assert.Equal(t, int64(1), r.calls.Load())
+}
+
+func TestCache_SharedStoreSkipsTransientErrors(t *testing.T) {
+	store := &mapStore{entries: map[string]dnscache.Entry{}}
+
+	timeout := &mockResolver{err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}
+	c := dnscache.NewWithResolver(2*time.Second, time.Minute, timeout)
+	c.SetStore(store)
+	_, err := c.LookupMX("flaky.example")
+	assert.Error(t, err)
+
+	nx := &mockResolver{err: &net.DNSError{Err: "no such host", IsNotFound: true}}
+	c = dnscache.NewWithResolver(2*time.Second, time.Minute, nx)
+	c.SetStore(store)
+	_, err = c.LookupMX("gone.example")
+	assert.Error(t, err)
+
+	_, flaky := store.entries["flaky.example"]
+	_, gone := store.entries["gone.example"]
+	assert.False(t, flaky)
+	assert.True(t, gone)
+}
+
+func TestCache_HitHook(t *testing.T) {
+	store := &mapStore{entries: map[string]dnscache.Entry{
+		"shared.example": {Domain: "shared.example", Records: []*net.MX{{Host: "mx.shared.example.", Pref: 10}}, Expires: time.Now().Add(time.Minute)},
+	}}
+	r := &mockResolver{records: []*net.MX{{Host: "mx.example.com.", Pref: 10}}}
+	c := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	c.SetStore(store)
+
+	var hits []string
+	c.SetHitHook(func(domain string, shared bool) {
+		if shared {
+			domain += " (shared)"
+		}
+		hits = append(hits, domain)
+	})
+
+	for _, d := range []string{"example.com", "example.com", "shared.example", "shared.example"} {
+		_, err := c.LookupMX(d)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{"example.com", "shared.example (shared)", "shared.example"}, hits)
+}
+
+func TestCache_LookupMXInfo(t *testing.T) {
+	store := &mapStore{entries: map[string]dnscache.Entry{
+		"shared.example": {Domain: "shared.example", Records: []*net.MX{{Host: "mx.shared.example.", Pref: 10}}, Expires: time.Now().Add(time.Minute)},
+	}}
+	r := &mockResolver{records: []*net.MX{{Host: "mx.example.com.", Pref: 10}}}
+	c := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	c.SetStore(store)
+
+	_, info, err := c.LookupMXInfo("example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, dnscache.SourceResolver, info.Source)
+	rtt := info.RTT
+
+	_, info, err = c.LookupMXInfo("example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, dnscache.Info{Source: dnscache.SourceCache, RTT: rtt}, info, "cached answers keep the query's RTT")
+
+	_, info, err = c.LookupMXInfo("shared.example")
+	assert.NoError(t, err)
+	assert.Equal(t, dnscache.SourceStore, info.Source)
+}
+
+func TestCache_Forget(t *testing.T) {
+	r := &mockResolver{err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}}
+	c := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+
+	_, err := c.LookupMX("example.com")
+	assert.Error(t, err)
+	_, _ = c.LookupMX("example.com")
+	assert.Equal(t, int64(1), r.calls.Load(), "the failure is cached")
+
+	c.Forget("example.com")
+	_, _ = c.LookupMX("example.com")
+	assert.Equal(t, int64(2), r.calls.Load())
+}
+
+func TestCache_Purge(t *testing.T) {
+	r := &mockResolver{records: []*net.MX{{Host: "mx.example.com.", Pref: 10}}}
+	c := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+
+	_, _ = c.LookupMX("example.com")
+	_, _ = c.LookupMX("example.org")
+	assert.Equal(t, 2, c.Purge())
+	assert.Equal(t, 0, c.Len())
+
+	_, _ = c.LookupMX("example.com")
+	assert.Equal(t, int64(3), r.calls.Load())
+	assert.Equal(t, 1, c.Purge())
+}
+
+// flakyResolver fails the first failures lookups with err.
+type flakyResolver struct {
+	failures int64
+	err      error
+	calls    atomic.Int64
+}
+
+func (f *flakyResolver) LookupMX(_ context.Context, _ string) ([]*net.MX, error) {
+	if f.calls.Add(1) <= f.failures {
+		return nil, f.err
+	}
+	return []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil
+}
+
+func TestCache_Retry(t *testing.T) {
+	servfail := &net.DNSError{Err: "server misbehaving", IsTemporary: true}
+	policy := dnscache.RetryPolicy{Attempts: 3, Backoff: time.Millisecond, Jitter: 0.5}
+
+	r := &flakyResolver{failures: 2, err: servfail}
+	c := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	c.SetRetry(policy)
+	recs, err := c.LookupMX("example.com")
+	assert.NoError(t, err, "recovered before the failure was cached")
+	assert.Len(t, recs, 1)
+	assert.Equal(t, int64(3), r.calls.Load())
+
+	// Out of attempts: the failure is returned and cached
+	r = &flakyResolver{failures: 5, err: servfail}
+	c = dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	c.SetRetry(policy)
+	_, err = c.LookupMX("example.com")
+	assert.Error(t, err)
+	_, _ = c.LookupMX("example.com")
+	assert.Equal(t, int64(3), r.calls.Load())
+
+	// NXDOMAIN is an answer
+	r = &flakyResolver{failures: 5, err: &net.DNSError{Err: "no such host", IsNotFound: true}}
+	c = dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	c.SetRetry(policy)
+	_, err = c.LookupMX("example.com")
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), r.calls.Load())
+
+	// No policy, no retry
+	r = &flakyResolver{failures: 1, err: servfail}
+	c = dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	_, err = c.LookupMX("example.com")
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), r.calls.Load())
+}
+
+// txtResolver answers MX and TXT lookups.
+type txtResolver struct {
+	mockResolver
+	txt map[string][]string
+}
+
+func (r *txtResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	r.calls.Add(1)
+	if txt, ok := r.txt[name]; ok {
+		return txt, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func TestCache_LookupTXT(t *testing.T) {
+	r := &txtResolver{txt: map[string][]string{"_verify.example.com": {"token=abc"}}}
+	c := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+
+	txt, err := c.LookupTXT("_verify.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"token=abc"}, txt)
+	txt[0] = "mutated"
+	txt, _ = c.LookupTXT("_verify.example.com")
+	assert.Equal(t, []string{"token=abc"}, txt, "callers get copies")
+
+	_, err = c.LookupTXT("_verify.other.example")
+	assert.Error(t, err)
+	_, _ = c.LookupTXT("_verify.other.example")
+	assert.Equal(t, int64(2), r.calls.Load(), "answers and NXDOMAIN are cached")
+	assert.Equal(t, 0, c.Len(), "TXT entries are not MX entries")
+
+	// A resolver without LookupTXT
+	_, err = dnscache.NewWithResolver(2*time.Second, time.Minute, &mockResolver{}).LookupTXT("example.com")
+	assert.Error(t, err)
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	r := &txtResolver{
+		mockResolver: mockResolver{records: []*net.MX{{Host: "mx.example.com.", Pref: 10}}},
+		txt:          map[string][]string{"example.com": {"v=spf1 -all"}, "_dmarc.example.com": {"v=DMARC1; p=none"}},
+	}
+	c := dnscache.NewWithResolver(2*time.Second, time.Minute, r)
+	_, _ = c.LookupMX("example.com")
+	_, _ = c.LookupMX("notexample.com")
+	_, _ = c.LookupTXT("example.com")
+	_, _ = c.LookupTXT("_dmarc.example.com")
+	_, _ = c.LookupTXT("notexample.com")
+
+	e, ok := c.Lookup("example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "mx.example.com.", e.Records[0].Host)
+	_, ok = c.Lookup("example.org")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"_dmarc.example.com", "example.com"}, c.TXTNames("example.com"))
+
+	assert.Equal(t, 3, c.Invalidate("example.com"))
+	_, ok = c.Lookup("example.com")
+	assert.False(t, ok)
+	assert.Empty(t, c.TXTNames("example.com"))
+	_, ok = c.Lookup("notexample.com")
+	assert.True(t, ok, "other domains are kept")
+	assert.Equal(t, []string{"notexample.com"}, c.TXTNames("notexample.com"))
+}
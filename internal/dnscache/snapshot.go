@@ -0,0 +1,140 @@
+package dnscache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Snapshot format
+//
+// A snapshot is JSON Lines: a header object, then one object per domain.
+//
+//	{"format":"emailkit-mx-snapshot","version":1,"saved":"2026-10-16T02:00:00Z"}
+//	{"domain":"example.com","mx":[{"host":"mx.example.com.","pref":10}],"resolved":"2026-10-16T01:58:12Z"}
+//	{"domain":"gone.example","nxdomain":true,"resolved":"2026-10-16T01:59:40Z"}
+//
+// Entries hold answers only: MX records, or NXDOMAIN ("nxdomain": true).
+// Resolver failures (timeouts, SERVFAIL) are never written. "resolved" is
+// when the answer was obtained. Unknown fields are ignored, so the format
+// can grow without a version bump.
+
+const (
+	snapshotFormat  = "emailkit-mx-snapshot"
+	snapshotVersion = 1
+)
+
+type snapshotHeader struct {
+	Format  string    `json:"format"`
+	Version int       `json:"version"`
+	Saved   time.Time `json:"saved"`
+}
+
+type snapshotEntry struct {
+	Domain   string       `json:"domain"`
+	MX       []snapshotMX `json:"mx,omitempty"`
+	NXDomain bool         `json:"nxdomain,omitempty"`
+	Resolved time.Time    `json:"resolved"`
+}
+
+type snapshotMX struct {
+	Host string `json:"host"`
+	Pref uint16 `json:"pref"`
+}
+
+// SaveSnapshot writes every cached answer, including expired ones, in the
+// snapshot format, for LoadSnapshot in a later run. In-flight lookups and
+// resolver failures are skipped.
+func (c *Cache) SaveSnapshot(w io.Writer) error {
+	c.mu.Lock()
+	entries := make([]snapshotEntry, 0, len(c.entries))
+	for domain, e := range c.entries {
+		select {
+		case <-e.done:
+		default:
+			continue
+		}
+		if e.err != nil && !isNotFound(e.err) {
+			continue
+		}
+		se := snapshotEntry{Domain: domain, NXDomain: e.err != nil, Resolved: e.resolved.UTC()}
+		if se.Resolved.IsZero() {
+			se.Resolved = e.expires.Add(-c.cacheTTL).UTC()
+		}
+		for _, mx := range e.records {
+			se.MX = append(se.MX, snapshotMX{Host: mx.Host, Pref: mx.Pref})
+		}
+		entries = append(entries, se)
+	}
+	c.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := enc.Encode(snapshotHeader{Format: snapshotFormat, Version: snapshotVersion, Saved: time.Now().UTC()}); err != nil {
+		return err
+	}
+	for _, se := range entries {
+		if err := enc.Encode(se); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// LoadSnapshot adds the answers of a snapshot resolved within maxAge to the
+// cache, each valid for one cache TTL from now. Domains already in the
+// cache are kept. It returns the number of entries added.
+func (c *Cache) LoadSnapshot(r io.Reader, maxAge time.Duration) (int, error) {
+	dec := json.NewDecoder(r)
+	var h snapshotHeader
+	if err := dec.Decode(&h); err != nil {
+		return 0, fmt.Errorf("snapshot header: %w", err)
+	}
+	if h.Format != snapshotFormat {
+		return 0, fmt.Errorf("not an MX snapshot (format %q)", h.Format)
+	}
+	if h.Version != snapshotVersion {
+		return 0, fmt.Errorf("unsupported MX snapshot version %d", h.Version)
+	}
+
+	now := time.Now()
+	var loaded []snapshotEntry
+	for {
+		var se snapshotEntry
+		err := dec.Decode(&se)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("snapshot entry %d: %w", len(loaded)+1, err)
+		}
+		if se.Domain == "" || now.Sub(se.Resolved) > maxAge {
+			continue
+		}
+		loaded = append(loaded, se)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	added := 0
+	for _, se := range loaded {
+		if _, ok := c.entries[se.Domain]; ok {
+			continue
+		}
+		e := &entry{resolved: se.Resolved, expires: now.Add(c.cacheTTL), done: make(chan struct{})}
+		for _, mx := range se.MX {
+			e.records = append(e.records, &net.MX{Host: mx.Host, Pref: mx.Pref})
+		}
+		if se.NXDomain {
+			e.err = &net.DNSError{Err: "no such host", Name: se.Domain, IsNotFound: true}
+		}
+		close(e.done)
+		c.entries[se.Domain] = e
+		added++
+	}
+	return added, nil
+}
@@ -0,0 +1,54 @@
+package dnscache_test
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit/internal/dnscache"
+)
+
+func TestSnapshot_SkipsResolverFailures(t *testing.T) {
+	c := dnscache.NewWithResolver(2*time.Second, time.Minute, &mockResolver{err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}})
+	_, _ = c.LookupMX("flaky.example")
+
+	var buf bytes.Buffer
+	require.NoError(t, c.SaveSnapshot(&buf))
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"), "header only")
+}
+
+func TestSnapshot_KeepsExpiredAnswers(t *testing.T) {
+	r := &mockResolver{records: []*net.MX{{Host: "mx.example.com.", Pref: 10}}}
+	c := dnscache.NewWithResolver(2*time.Second, time.Millisecond, r)
+	_, _ = c.LookupMX("example.com")
+	time.Sleep(5 * time.Millisecond)
+	assert.Empty(t, c.Entries(), "expired for lookups")
+
+	var buf bytes.Buffer
+	require.NoError(t, c.SaveSnapshot(&buf))
+
+	c2 := dnscache.NewWithResolver(2*time.Second, time.Minute, &mockResolver{})
+	n, err := c2.LoadSnapshot(&buf, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	recs, err := c2.LookupMX("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "mx.example.com.", recs[0].Host)
+}
+
+func TestSnapshot_Errors(t *testing.T) {
+	c := dnscache.New(time.Second, time.Minute)
+	for _, in := range []string{
+		``,
+		`{"format":"emailkit-mx-snapshot","version":2}`,
+		"{\"format\":\"emailkit-mx-snapshot\",\"version\":1}\n{broken",
+	} {
+		_, err := c.LoadSnapshot(strings.NewReader(in), time.Hour)
+		assert.Error(t, err, in)
+	}
+}
@@ -0,0 +1,62 @@
+// Package features records the subsystems compiled into a binary, for
+// emailkit.Features. Every emailkit package registers its features from
+// an init function, so a package that isn't imported reports nothing.
+package features
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Stability is the API stability tier of a feature.
+type Stability string
+
+const (
+	// Stable features follow semantic versioning: no breaking change
+	// before the next major version.
+	Stable Stability = "stable"
+	// Experimental features live under emailkit/x/ and may change or go
+	// away in any minor release.
+	Experimental Stability = "experimental"
+)
+
+// Feature is one subsystem compiled into the binary.
+type Feature struct {
+	// Name identifies the feature; it doesn't change when an
+	// experimental feature is promoted to stable.
+	Name        string    `json:"name"`
+	Stability   Stability `json:"stability"`
+	Package     string    `json:"package"` // import path that provides it
+	Description string    `json:"description,omitempty"`
+}
+
+var (
+	mu  sync.Mutex
+	all = make(map[string]Feature)
+)
+
+// Register records features. It panics if a name is registered twice,
+// which is a bug in emailkit: call it only from init functions.
+func Register(fs ...Feature) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, f := range fs {
+		if _, dup := all[f.Name]; dup {
+			panic(fmt.Sprintf("features: %q registered twice", f.Name))
+		}
+		all[f.Name] = f
+	}
+}
+
+// All returns the registered features sorted by name.
+func All() []Feature {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make([]Feature, 0, len(all))
+	for _, f := range all {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,26 @@
+package features_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/internal/features"
+)
+
+func TestRegister(t *testing.T) {
+	features.Register(
+		features.Feature{Name: "zz-test-b", Stability: features.Experimental},
+		features.Feature{Name: "zz-test-a", Stability: features.Stable},
+	)
+
+	all := features.All()
+	var names []string
+	for _, f := range all {
+		names = append(names, f.Name)
+	}
+	assert.IsIncreasing(t, names)
+	assert.Contains(t, names, "zz-test-a")
+
+	assert.Panics(t, func() { features.Register(features.Feature{Name: "zz-test-a"}) })
+}
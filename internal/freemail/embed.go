@@ -0,0 +1,12 @@
+//go:build !emailkit_nofreemail
+
+package freemail
+
+import _ "embed"
+
+// Embedded reports whether the list is compiled into the binary; the
+// emailkit_nofreemail build tag leaves it out.
+const Embedded = true
+
+//go:embed list.txt
+var rawList string
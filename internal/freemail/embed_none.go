@@ -0,0 +1,10 @@
+//go:build emailkit_nofreemail
+
+package freemail
+
+// Embedded reports whether the list is compiled into the binary; the
+// emailkit_nofreemail build tag leaves it out.
+const Embedded = false
+
+// rawList is empty: the list is loaded at runtime with Load.
+var rawList string
@@ -0,0 +1,47 @@
+// Package freemail holds the embedded list of free consumer mailbox
+// providers.
+package freemail
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+var freeSet atomic.Pointer[map[string]struct{}]
+
+func init() {
+	set, _ := parse(strings.NewReader(rawList))
+	freeSet.Store(&set)
+}
+
+// IsFree returns whether domain is a known free mailbox provider.
+func IsFree(domain string) bool {
+	_, ok := (*freeSet.Load())[strings.ToLower(domain)]
+	return ok
+}
+
+// Load replaces the list with the domains read from r, one per line ("#"
+// starts a comment line), and returns how many it read. On a read error
+// the list is left as it was.
+func Load(r io.Reader) (int, error) {
+	set, err := parse(r)
+	if err != nil {
+		return 0, err
+	}
+	freeSet.Store(&set)
+	return len(set), nil
+}
+
+func parse(r io.Reader) (map[string]struct{}, error) {
+	set := make(map[string]struct{})
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			set[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return set, sc.Err()
+}
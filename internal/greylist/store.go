@@ -0,0 +1,98 @@
+// Package greylist provides the in-memory greylisting state store.
+package greylist
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a first-attempt timestamp is kept. Greylisting
+// windows are minutes long; a day covers retries across batches.
+const DefaultTTL = 24 * time.Hour
+
+// MemoryStore keeps first-greylisted timestamps in memory.
+// It is safe for concurrent use.
+type MemoryStore struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]time.Time
+	writes  int
+}
+
+// NewMemoryStore creates a store whose entries expire after ttl
+// (DefaultTTL if ttl <= 0).
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &MemoryStore{ttl: ttl, entries: make(map[string]time.Time)}
+}
+
+// FirstGreylisted returns when key was first greylisted.
+func (s *MemoryStore) FirstGreylisted(_ context.Context, key string) (time.Time, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.entries[key]
+	if ok && time.Since(t) > s.ttl {
+		delete(s.entries, key)
+		return time.Time{}, false, nil
+	}
+	return t, ok, nil
+}
+
+// MarkGreylisted records at as the first greylisting of key, unless an
+// earlier, unexpired timestamp is already stored.
+func (s *MemoryStore) MarkGreylisted(_ context.Context, key string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.entries[key]; ok && time.Since(t) <= s.ttl && !t.After(at) {
+		return nil
+	}
+	s.entries[key] = at
+
+	// Sweep expired entries now and then to bound memory
+	s.writes++
+	if s.writes%1024 == 0 {
+		for k, t := range s.entries {
+			if time.Since(t) > s.ttl {
+				delete(s.entries, k)
+			}
+		}
+	}
+	return nil
+}
+
+// Clear forgets key, e.g. once the recipient was accepted or rejected.
+func (s *MemoryStore) Clear(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// Entries returns a copy of the unexpired timestamps by key.
+func (s *MemoryStore) Entries() map[string]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make(map[string]time.Time, len(s.entries))
+	for k, t := range s.entries {
+		if time.Since(t) <= s.ttl {
+			out[k] = t
+		}
+	}
+	return out
+}
+
+// Restore merges timestamps into the store, keeping the earlier one on
+// conflicts. Expired timestamps are skipped.
+func (s *MemoryStore) Restore(entries map[string]time.Time) {
+	for k, t := range entries {
+		if time.Since(t) <= s.ttl {
+			_ = s.MarkGreylisted(context.Background(), k, t)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package greylist_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/internal/greylist"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	s := greylist.NewMemoryStore(0)
+
+	_, ok, err := s.FirstGreylisted(ctx, "mx|a@example.com")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	first := time.Now().Add(-time.Minute)
+	assert.NoError(t, s.MarkGreylisted(ctx, "mx|a@example.com", first))
+	// A later attempt keeps the first timestamp
+	assert.NoError(t, s.MarkGreylisted(ctx, "mx|a@example.com", time.Now()))
+
+	got, ok, err := s.FirstGreylisted(ctx, "mx|a@example.com")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, got.Equal(first))
+
+	assert.NoError(t, s.Clear(ctx, "mx|a@example.com"))
+	_, ok, _ = s.FirstGreylisted(ctx, "mx|a@example.com")
+	assert.False(t, ok)
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	s := greylist.NewMemoryStore(time.Hour)
+
+	assert.NoError(t, s.MarkGreylisted(ctx, "k", time.Now().Add(-2*time.Hour)))
+	_, ok, err := s.FirstGreylisted(ctx, "k")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// An expired entry is replaced by a new attempt
+	now := time.Now()
+	assert.NoError(t, s.MarkGreylisted(ctx, "k", now.Add(-3*time.Hour)))
+	assert.NoError(t, s.MarkGreylisted(ctx, "k", now))
+	got, ok, _ := s.FirstGreylisted(ctx, "k")
+	assert.True(t, ok)
+	assert.True(t, got.Equal(now))
+}
+
+func TestMemoryStore_EntriesRestore(t *testing.T) {
+	ctx := context.Background()
+	s := greylist.NewMemoryStore(time.Hour)
+	first := time.Now().Add(-time.Minute)
+	assert.NoError(t, s.MarkGreylisted(ctx, "k", first))
+
+	restored := greylist.NewMemoryStore(time.Hour)
+	entries := s.Entries()
+	entries["expired"] = time.Now().Add(-2 * time.Hour)
+	restored.Restore(entries)
+
+	got, ok, _ := restored.FirstGreylisted(ctx, "k")
+	assert.True(t, ok)
+	assert.True(t, got.Equal(first))
+	assert.Len(t, restored.Entries(), 1)
+}
@@ -0,0 +1,100 @@
+package levenshtein
+
+// BKTree is a Burkhard-Keller tree: an index for finding the words within
+// an edit distance of a query without comparing against every word. By
+// the triangle inequality only children whose edge distance lies within
+// maxDist of the query's distance to a node can hold matches.
+// Build it before use; concurrent lookups are safe.
+type BKTree struct {
+	root *bkNode
+	size int
+}
+
+type bkNode struct {
+	word     string
+	runes    []rune
+	order    int // insertion order, breaks distance ties
+	maxEdge  int // largest key in children
+	children map[int]*bkNode
+}
+
+// NewBKTree builds a tree over words. Duplicates are ignored.
+func NewBKTree(words []string) *BKTree {
+	t := &BKTree{}
+	for _, w := range words {
+		t.Add(w)
+	}
+	return t
+}
+
+// Add inserts word unless it is already present.
+func (t *BKTree) Add(word string) {
+	if t.root == nil {
+		t.root = &bkNode{word: word, runes: []rune(word)}
+		t.size = 1
+		return
+	}
+	n := t.root
+	for {
+		d := Distance(word, n.word)
+		if d == 0 {
+			return
+		}
+		child, ok := n.children[d]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[int]*bkNode)
+			}
+			n.children[d] = &bkNode{word: word, runes: []rune(word), order: t.size}
+			n.maxEdge = max(n.maxEdge, d)
+			t.size++
+			return
+		}
+		n = child
+	}
+}
+
+// Len returns the number of words in the tree.
+func (t *BKTree) Len() int { return t.size }
+
+// Closest returns the word nearest to query within maxDist and its
+// distance. Among equally distant words the earliest added wins.
+// ok is false if no word is within maxDist.
+//
+// The search radius shrinks to the best distance found so far, and a
+// node's distance is only computed as far as it can still matter: beyond
+// radius+maxEdge neither the node nor any of its children can match.
+func (t *BKTree) Closest(query string, maxDist int) (word string, dist int, ok bool) {
+	if t.root == nil {
+		return "", 0, false
+	}
+	best := (*bkNode)(nil)
+	bestDist := maxDist + 1
+
+	q := []rune(query)
+	var rows []int
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		radius := min(maxDist, bestDist)
+		d, ok := boundedDistance(q, n.runes, radius+n.maxEdge, &rows)
+		if !ok {
+			continue
+		}
+		if d < bestDist || (d == bestDist && best != nil && n.order < best.order) {
+			best, bestDist = n, d
+			radius = min(maxDist, bestDist)
+		}
+		for edge, child := range n.children {
+			if edge >= d-radius && edge <= d+radius {
+				stack = append(stack, child)
+			}
+		}
+	}
+	if best == nil {
+		return "", 0, false
+	}
+	return best.word, bestDist, true
+}
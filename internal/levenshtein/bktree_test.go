@@ -0,0 +1,114 @@
+package levenshtein_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/internal/levenshtein"
+)
+
+func TestBKTree_Closest(t *testing.T) {
+	tree := levenshtein.NewBKTree([]string{"acme.io", "acme.com", "globex.com", "initech.com", "acme.io"})
+	assert.Equal(t, 4, tree.Len(), "duplicates are ignored")
+
+	tests := []struct {
+		query    string
+		maxDist  int
+		want     string
+		wantDist int
+		wantOK   bool
+	}{
+		{"acme.io", 2, "acme.io", 0, true},
+		{"acmme.io", 2, "acme.io", 1, true},
+		{"globx.com", 2, "globex.com", 1, true},
+		{"acme.cm", 2, "acme.com", 1, true},
+		{"initech.com", 0, "initech.com", 0, true},
+		{"example.org", 2, "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, dist, ok := tree.Closest(tt.query, tt.maxDist)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantDist, dist)
+		})
+	}
+}
+
+func TestBKTree_TieBreak(t *testing.T) {
+	tree := levenshtein.NewBKTree([]string{"acme.de", "acme.io", "acme.fr"})
+	got, dist, ok := tree.Closest("acme.xx", 2)
+	assert.True(t, ok)
+	assert.Equal(t, 2, dist)
+	assert.Equal(t, "acme.de", got, "earliest added wins ties")
+}
+
+func TestBKTree_Empty(t *testing.T) {
+	var tree levenshtein.BKTree
+	_, _, ok := tree.Closest("acme.io", 2)
+	assert.False(t, ok)
+	assert.Zero(t, tree.Len())
+}
+
+// TestBKTree_MatchesLinearScan cross-checks the tree against a brute-force
+// search over a larger corpus.
+func TestBKTree_MatchesLinearScan(t *testing.T) {
+	words := make([]string, 0, 2000)
+	for i := range 2000 {
+		words = append(words, fmt.Sprintf("customer%d.example", i*7919%10007))
+	}
+	tree := levenshtein.NewBKTree(words)
+
+	for _, q := range []string{"customr42.example", "customer1234.exmple", "custoner999.example", "nothing.test"} {
+		bestDist, best := 3, ""
+		for _, w := range words {
+			if d := levenshtein.Distance(q, w); d < bestDist {
+				bestDist, best = d, w
+			}
+		}
+		got, dist, ok := tree.Closest(q, 2)
+		assert.Equal(t, best != "", ok, q)
+		if ok {
+			assert.Equal(t, bestDist, dist, q)
+			assert.Equal(t, best, got, q)
+		}
+	}
+}
+
+// benchmarkCorpus returns 10k pseudo-random domains of 5-12 letters.
+func benchmarkCorpus() []string {
+	rng := rand.New(rand.NewPCG(1, 2))
+	tlds := []string{".com", ".net", ".io", ".de", ".co.uk"}
+	words := make([]string, 0, 10000)
+	for range 10000 {
+		name := make([]byte, 5+rng.IntN(8))
+		for i := range name {
+			name[i] = byte('a' + rng.IntN(26))
+		}
+		words = append(words, string(name)+tlds[rng.IntN(len(tlds))])
+	}
+	return words
+}
+
+const benchmarkQuery = "acmme-corp.com"
+
+func BenchmarkBKTree_Closest(b *testing.B) {
+	tree := levenshtein.NewBKTree(benchmarkCorpus())
+	b.ResetTimer()
+	for b.Loop() {
+		tree.Closest(benchmarkQuery, 2)
+	}
+}
+
+func BenchmarkLinearScan(b *testing.B) {
+	words := benchmarkCorpus()
+	b.ResetTimer()
+	for b.Loop() {
+		for _, w := range words {
+			levenshtein.Distance(benchmarkQuery, w)
+		}
+	}
+}
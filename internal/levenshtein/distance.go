@@ -46,6 +46,68 @@ func Distance(s, t string) int {
 	return prev[len(sr)]
 }
 
+// DistanceAtMost computes the Levenshtein distance between s and t if it
+// is at most limit. It stops as soon as the distance is known to exceed
+// limit, returning limit+1 and false, which makes rejecting far-off words
+// much cheaper than a full Distance.
+func DistanceAtMost(s, t string, limit int) (int, bool) {
+	return boundedDistance([]rune(s), []rune(t), limit, nil)
+}
+
+// boundedDistance is DistanceAtMost on runes. rows is scratch space that
+// is grown as needed and may be reused across calls.
+func boundedDistance(sr, tr []rune, limit int, rows *[]int) (int, bool) {
+	if len(sr) > len(tr) {
+		sr, tr = tr, sr
+	}
+	// The length difference alone needs that many insertions.
+	if len(tr)-len(sr) > limit {
+		return limit + 1, false
+	}
+	if len(sr) == 0 {
+		return len(tr), true
+	}
+
+	var buf []int
+	if rows != nil {
+		buf = *rows
+	}
+	if cap(buf) < 2*(len(sr)+1) {
+		buf = make([]int, 2*(len(sr)+1))
+		if rows != nil {
+			*rows = buf
+		}
+	}
+	prev := buf[:len(sr)+1]
+	curr := buf[len(sr)+1 : 2*(len(sr)+1)]
+	for i := range prev {
+		prev[i] = i
+	}
+
+	for j, tc := range tr {
+		curr[0] = j + 1
+		rowMin := curr[0]
+		for i, sc := range sr {
+			cost := 1
+			if sc == tc {
+				cost = 0
+			}
+			curr[i+1] = min3(curr[i]+1, prev[i+1]+1, prev[i]+cost)
+			rowMin = min(rowMin, curr[i+1])
+		}
+		// Row minima never decrease, so the final distance is at least this.
+		if rowMin > limit {
+			return limit + 1, false
+		}
+		prev, curr = curr, prev
+	}
+
+	if d := prev[len(sr)]; d <= limit {
+		return d, true
+	}
+	return limit + 1, false
+}
+
 func min3(a, b, c int) int {
 	if a < b {
 		if a < c {
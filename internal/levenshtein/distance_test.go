@@ -28,3 +28,30 @@ func TestDistance(t *testing.T) {
 		})
 	}
 }
+
+func TestDistanceAtMost(t *testing.T) {
+	tests := []struct {
+		s, t   string
+		limit  int
+		want   int
+		wantOK bool
+	}{
+		{"gmail.com", "gmail.com", 0, 0, true},
+		{"gmial.com", "gmail.com", 2, 2, true},
+		{"gmial.com", "gmail.com", 1, 2, false},
+		{"a", "abcdef", 2, 3, false}, // length difference alone exceeds limit
+		{"", "ab", 2, 2, true},
+		{"yahoo.com", "gmail.com", 2, 3, false},
+		{"yahoo.com", "gmail.com", 5, 5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s+"->"+tt.t, func(t *testing.T) {
+			got, ok := levenshtein.DistanceAtMost(tt.s, tt.t, tt.limit)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+			if ok {
+				assert.Equal(t, levenshtein.Distance(tt.s, tt.t), got)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"errors"
 	"net/mail"
 	"strings"
 
@@ -43,6 +44,28 @@ func NewEmail(raw string) Email {
 	return buildEmail(raw, parts[0], parts[1])
 }
 
+// NewDomain builds an Email for domain-only validation: Local is empty
+// and Raw is the trimmed input. A trailing root dot is dropped.
+// Valid is false if the input is empty, contains '@' or fails IDNA2008.
+func NewDomain(raw string) Email {
+	raw = strings.TrimSpace(raw)
+	domain := strings.TrimSuffix(raw, ".")
+	if domain == "" || strings.ContainsAny(domain, "@ ") {
+		return Email{Raw: raw, Valid: false}
+	}
+
+	asciiDomain, unicodeDomain, ok := convertDomain(strings.ToLower(domain))
+	if !ok {
+		return Email{Raw: raw, Valid: false}
+	}
+	return Email{
+		Raw:           raw,
+		Domain:        asciiDomain,
+		DomainUnicode: unicodeDomain,
+		Valid:         true,
+	}
+}
+
 // parseManual handles email addresses that net/mail.ParseAddress rejects,
 // such as those with Unicode local parts (RFC 6531 SMTPUTF8).
 func parseManual(raw string) Email {
@@ -107,3 +130,35 @@ func convertDomain(domain string) (ascii, unicode string, ok bool) {
 	}
 	return domain, u, true
 }
+
+var (
+	// ErrInvalidPunycode is returned by CheckPunycode when an "xn--" label
+	// cannot be decoded.
+	ErrInvalidPunycode = errors.New("invalid Punycode label")
+
+	// ErrPunycodeMismatch is returned by CheckPunycode when an "xn--" label
+	// decodes, but re-encoding the result does not yield the original label
+	// (e.g. "xn--abc-" which is just "abc" in disguise).
+	ErrPunycodeMismatch = errors.New("label does not round-trip through Punycode")
+)
+
+// CheckPunycode verifies that every "xn--" label of the ASCII domain
+// round-trips through IDNA2008: it must decode to Unicode, and encoding
+// that Unicode form again must give back the same label.
+// Returns nil for domains without Punycode labels.
+func CheckPunycode(domain string) error {
+	for _, label := range strings.Split(strings.ToLower(domain), ".") {
+		if !strings.HasPrefix(label, "xn--") {
+			continue
+		}
+		u, err := idna.Lookup.ToUnicode(label)
+		if err != nil {
+			return ErrInvalidPunycode
+		}
+		a, err := idna.Lookup.ToASCII(u)
+		if err != nil || a != label {
+			return ErrPunycodeMismatch
+		}
+	}
+	return nil
+}
@@ -90,3 +90,41 @@ func TestNewEmail_DomainCaseNormalization(t *testing.T) {
 	assert.True(t, e.Valid)
 	assert.Equal(t, "example.com", e.Domain)
 }
+
+func TestNewDomain(t *testing.T) {
+	e := parse.NewDomain("  Example.COM.  ")
+	assert.True(t, e.Valid)
+	assert.Empty(t, e.Local)
+	assert.Equal(t, "Example.COM.", e.Raw)
+	assert.Equal(t, "example.com", e.Domain)
+
+	e = parse.NewDomain("münchen.de")
+	assert.True(t, e.Valid)
+	assert.Equal(t, "xn--mnchen-3ya.de", e.Domain)
+	assert.Equal(t, "münchen.de", e.DomainUnicode)
+
+	for _, raw := range []string{"", ".", "user@example.com", "exa mple.com"} {
+		assert.False(t, parse.NewDomain(raw).Valid, "expected invalid for %q", raw)
+	}
+}
+
+func TestCheckPunycode(t *testing.T) {
+	tests := []struct {
+		domain  string
+		wantErr error
+	}{
+		{"example.com", nil},
+		{"xn--mnchen-3ya.de", nil},
+		{"XN--MNCHEN-3YA.DE", nil},
+		{"xn--80a1acny.xn--p1ai", nil},
+		{"xn--zz.com", parse.ErrInvalidPunycode},
+		{"xn--0.com", parse.ErrInvalidPunycode},
+		{"xn--.com", parse.ErrPunycodeMismatch},
+		{"xn--abc-.com", parse.ErrPunycodeMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			assert.ErrorIs(t, parse.CheckPunycode(tt.domain), tt.wantErr)
+		})
+	}
+}
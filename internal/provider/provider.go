@@ -0,0 +1,120 @@
+// Package provider identifies mailbox providers and MTA software from
+// MX host names and SMTP greetings.
+package provider
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Provider names returned by Detect.
+const (
+	Google     = "google"
+	Microsoft  = "microsoft"
+	Yahoo      = "yahoo"
+	Apple      = "apple"
+	Proton     = "proton"
+	Zoho       = "zoho"
+	Fastmail   = "fastmail"
+	Yandex     = "yandex"
+	MailRu     = "mailru"
+	GMX        = "gmx"
+	Proofpoint = "proofpoint"
+	Mimecast   = "mimecast"
+	Barracuda  = "barracuda"
+	AmazonSES  = "amazon-ses"
+	Mailgun    = "mailgun"
+	GoDaddy    = "godaddy"
+	OVH        = "ovh"
+	IONOS      = "ionos"
+)
+
+// mxSuffixes maps MX host suffixes to providers. Checked in order.
+var mxSuffixes = []struct {
+	suffix   string
+	provider string
+}{
+	{"google.com", Google},
+	{"googlemail.com", Google},
+	{"protection.outlook.com", Microsoft},
+	{"outlook.com", Microsoft},
+	{"hotmail.com", Microsoft},
+	{"yahoodns.net", Yahoo},
+	{"icloud.com", Apple},
+	{"protonmail.ch", Proton},
+	{"zoho.com", Zoho},
+	{"zoho.eu", Zoho},
+	{"messagingengine.com", Fastmail},
+	{"yandex.net", Yandex},
+	{"yandex.ru", Yandex},
+	{"mail.ru", MailRu},
+	{"gmx.net", GMX},
+	{"web.de", GMX},
+	{"pphosted.com", Proofpoint},
+	{"ppe-hosted.com", Proofpoint},
+	{"mimecast.com", Mimecast},
+	{"barracudanetworks.com", Barracuda},
+	{"amazonaws.com", AmazonSES},
+	{"mailgun.org", Mailgun},
+	{"secureserver.net", GoDaddy},
+	{"ovh.net", OVH},
+	{"kundenserver.de", IONOS},
+	{"ionos.com", IONOS},
+}
+
+// Detect returns the provider operating the given MX hosts, or "" if none
+// is recognized. The first recognized host wins, so pass hosts in
+// preference order.
+func Detect(mxHosts []string) string {
+	for _, h := range mxHosts {
+		if p := detectHost(h); p != "" {
+			return p
+		}
+	}
+	return ""
+}
+
+func detectHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, s := range mxSuffixes {
+		if host == s.suffix || strings.HasSuffix(host, "."+s.suffix) {
+			return s.provider
+		}
+	}
+	return ""
+}
+
+// softwarePatterns recognize MTA software from the banner or EHLO text.
+var softwarePatterns = []struct {
+	re       *regexp.Regexp
+	software string
+}{
+	{regexp.MustCompile(`(?i)\bgsmtp\b`), "Google SMTP"},
+	{regexp.MustCompile(`(?i)microsoft esmtp mail service|outlook\.com|exchange`), "Microsoft Exchange"},
+	{regexp.MustCompile(`(?i)\bpostfix\b`), "Postfix"},
+	{regexp.MustCompile(`(?i)\bexim\b`), "Exim"},
+	{regexp.MustCompile(`(?i)\bsendmail\b`), "Sendmail"},
+	{regexp.MustCompile(`(?i)\bqmail\b`), "qmail"},
+	{regexp.MustCompile(`(?i)\bopensmtpd\b`), "OpenSMTPD"},
+	{regexp.MustCompile(`(?i)\bharaka\b`), "Haraka"},
+	{regexp.MustCompile(`(?i)\bzimbra\b`), "Zimbra"},
+	{regexp.MustCompile(`(?i)\bhmailserver\b`), "hMailServer"},
+	{regexp.MustCompile(`(?i)\bmdaemon\b`), "MDaemon"},
+	{regexp.MustCompile(`(?i)\bkerio\b`), "Kerio Connect"},
+	{regexp.MustCompile(`(?i)\bicewarp\b`), "IceWarp"},
+	{regexp.MustCompile(`(?i)\bcommunigate\b`), "CommuniGate Pro"},
+	{regexp.MustCompile(`(?i)\bmimecast\b`), "Mimecast"},
+	{regexp.MustCompile(`(?i)\bproofpoint\b|pphosted`), "Proofpoint"},
+}
+
+// Software guesses the MTA software from an SMTP banner and EHLO response.
+// Returns "" if nothing matches.
+func Software(banner, ehlo string) string {
+	text := banner + "\n" + ehlo
+	for _, p := range softwarePatterns {
+		if p.re.MatchString(text) {
+			return p.software
+		}
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/internal/provider"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		hosts []string
+		want  string
+	}{
+		{[]string{"aspmx.l.google.com."}, provider.Google},
+		{[]string{"example-com.mail.protection.outlook.com"}, provider.Microsoft},
+		{[]string{"mta5.am0.yahoodns.net"}, provider.Yahoo},
+		{[]string{"mx0a-001.pphosted.com"}, provider.Proofpoint},
+		{[]string{"mx.example.com", "alt1.aspmx.l.google.com"}, provider.Google},
+		{[]string{"mx.notgoogle.com"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, provider.Detect(tt.hosts), "%v", tt.hosts)
+	}
+}
+
+func TestSoftware(t *testing.T) {
+	tests := []struct {
+		banner string
+		want   string
+	}{
+		{"220 mx.google.com ESMTP a1-20020a gsmtp", "Google SMTP"},
+		{"220 mail.example.com ESMTP Postfix (Debian/GNU)", "Postfix"},
+		{"220 mx.example.com ESMTP Exim 4.96 Mon, 01 Jan 2024", "Exim"},
+		{"220 BN8NAM11FT.mail.protection.outlook.com Microsoft ESMTP MAIL Service ready", "Microsoft Exchange"},
+		{"220 mx.example.com ESMTP ready", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, provider.Software(tt.banner, ""), tt.banner)
+	}
+}
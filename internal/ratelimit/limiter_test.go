@@ -0,0 +1,64 @@
+package ratelimit_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/internal/ratelimit"
+)
+
+func TestLimiter_Paces(t *testing.T) {
+	l := ratelimit.New(100, 1) // one token every 10ms
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 11; i++ {
+		assert.NoError(t, l.Wait(ctx))
+	}
+	// First token is immediate, the next 10 take ~10ms each
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestLimiter_Burst(t *testing.T) {
+	l := ratelimit.New(1, 5)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, l.Wait(ctx))
+	}
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestLimiter_Concurrent(t *testing.T) {
+	l := ratelimit.New(200, 1)
+	ctx := context.Background()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				assert.NoError(t, l.Wait(ctx))
+			}
+		}()
+	}
+	wg.Wait()
+	// 20 tokens at 200/s: at least 19 × 5ms regardless of worker count
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestLimiter_ContextCancelled(t *testing.T) {
+	l := ratelimit.New(0.1, 1)
+	assert.NoError(t, l.Wait(context.Background())) // consume the burst
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+}
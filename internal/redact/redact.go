@@ -0,0 +1,40 @@
+// Package redact strips personal data (email addresses and IP addresses)
+// from free-form text such as SMTP server responses.
+package redact
+
+import (
+	"net"
+	"regexp"
+)
+
+const (
+	// EmailPlaceholder replaces redacted email addresses.
+	EmailPlaceholder = "[email]"
+	// IPPlaceholder replaces redacted IP addresses.
+	IPPlaceholder = "[ip]"
+)
+
+var (
+	emailRe = regexp.MustCompile(`[^\s<>()\[\]"',;:]+@[^\s<>()\[\]"',;:]+\.[^\s<>()\[\]"',;:.]+`)
+	ipv4Re  = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	// Candidate IPv6 tokens; each match is confirmed with net.ParseIP so that
+	// times like "10:30:00" are left alone.
+	ipv6Re = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}(?:%\w+)?`)
+)
+
+// PII replaces email addresses and IPv4/IPv6 addresses in s with placeholders.
+func PII(s string) string {
+	s = emailRe.ReplaceAllString(s, EmailPlaceholder)
+	s = ipv4Re.ReplaceAllStringFunc(s, func(m string) string {
+		if net.ParseIP(m) == nil {
+			return m
+		}
+		return IPPlaceholder
+	})
+	return ipv6Re.ReplaceAllStringFunc(s, func(m string) string {
+		if net.ParseIP(m) == nil {
+			return m
+		}
+		return IPPlaceholder
+	})
+}
@@ -0,0 +1,30 @@
+package redact_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/internal/redact"
+)
+
+func TestPII(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"5.1.1 <john.doe@example.com>: Recipient address rejected", "5.1.1 <[email]>: Recipient address rejected"},
+		{"550 user jane+tag@sub.example.co.uk unknown", "550 user [email] unknown"},
+		{"Client host [203.0.113.7] blocked using zen.spamhaus.org", "Client host [[ip]] blocked using zen.spamhaus.org"},
+		{"connection from 2001:db8::1 refused", "connection from [ip] refused"},
+		{"try again at 10:30:00", "try again at 10:30:00"},
+		{"5.7.1 version 999.1.2.3 not an ip", "5.7.1 version 999.1.2.3 not an ip"},
+		{"250 2.1.5 OK", "250 2.1.5 OK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, redact.PII(tt.in))
+		})
+	}
+}
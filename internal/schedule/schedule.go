@@ -0,0 +1,203 @@
+// Package schedule restricts SMTP probing to configured daily windows and
+// paces it to a daily budget per provider.
+package schedule
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Window is a daily time range as offsets from midnight. An End before
+// Start wraps around midnight (e.g. 22:00-06:00).
+type Window struct {
+	Start, End time.Duration
+}
+
+// ParseWindow parses "HH:MM-HH:MM".
+func ParseWindow(s string) (Window, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return Window{}, fmt.Errorf("schedule: window %q: want HH:MM-HH:MM", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return Window{}, fmt.Errorf("schedule: window %q: %w", s, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return Window{}, fmt.Errorf("schedule: window %q: %w", s, err)
+	}
+	return Window{Start: start, End: end}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// contains reports whether the offset from midnight falls in the window.
+func (w Window) contains(offset time.Duration) bool {
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// Config configures a Schedule.
+type Config struct {
+	Windows       []Window         // empty: any time of day
+	Weekdays      []time.Weekday   // empty: every day
+	Location      *time.Location   // default: UTC
+	Budgets       map[string]int   // daily probes per key (provider)
+	DefaultBudget int              // for keys not in Budgets; 0 = unlimited
+	Now           func() time.Time // injectable for testing
+}
+
+// Reasons returned by Reserve.
+const (
+	ReasonOutsideWindow = "outside probing window"
+	ReasonBudget        = "daily budget exhausted"
+)
+
+// Schedule decides whether a probe may run now. It is safe for
+// concurrent use.
+type Schedule struct {
+	cfg  Config
+	mu   sync.Mutex
+	day  string
+	used map[string]int
+}
+
+// New creates a Schedule.
+func New(cfg Config) *Schedule {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Schedule{cfg: cfg, used: make(map[string]int)}
+}
+
+// Reserve takes one probe from key's daily budget. If the probe may not run
+// now it returns false, the reason and the earliest time it may.
+func (s *Schedule) Reserve(key string) (ok bool, reason string, resume time.Time) {
+	now := s.cfg.Now().In(s.cfg.Location)
+	if !s.open(now) {
+		return false, ReasonOutsideWindow, s.nextOpen(now)
+	}
+
+	limit, limited := s.cfg.Budgets[key]
+	if !limited {
+		limit = s.cfg.DefaultBudget
+		limited = limit > 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if day := now.Format(time.DateOnly); day != s.day {
+		s.day = day
+		clear(s.used)
+	}
+	if limited && s.used[key] >= limit {
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, s.cfg.Location)
+		return false, ReasonBudget, s.nextOpen(midnight)
+	}
+	s.used[key]++
+	return true, "", time.Time{}
+}
+
+// Counters returns the probes taken today by key and the day they count
+// for (YYYY-MM-DD in Location); nil before the first probe of the day.
+func (s *Schedule) Counters() (day string, used map[string]int) {
+	today := s.cfg.Now().In(s.cfg.Location).Format(time.DateOnly)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.day != today || len(s.used) == 0 {
+		return today, nil
+	}
+	return today, maps.Clone(s.used)
+}
+
+// Restore loads counters returned by Counters, e.g. before a restart. It
+// ignores counters of another day than today and keeps the larger count
+// of a key already probed.
+func (s *Schedule) Restore(day string, used map[string]int) {
+	today := s.cfg.Now().In(s.cfg.Location).Format(time.DateOnly)
+	if day != today {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.day != today {
+		s.day = today
+		clear(s.used)
+	}
+	for key, n := range used {
+		s.used[key] = max(s.used[key], n)
+	}
+}
+
+// open reports whether t is on an allowed weekday and inside a window.
+func (s *Schedule) open(t time.Time) bool {
+	if len(s.cfg.Weekdays) > 0 {
+		allowed := false
+		for _, d := range s.cfg.Weekdays {
+			if t.Weekday() == d {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	if len(s.cfg.Windows) == 0 {
+		return true
+	}
+	offset := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()))
+	for _, w := range s.cfg.Windows {
+		if w.contains(offset) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextOpen returns the earliest time at or after t when probing is
+// allowed, looking up to a week ahead; zero if never.
+func (s *Schedule) nextOpen(t time.Time) time.Time {
+	if s.open(t) {
+		return t
+	}
+	windows := s.cfg.Windows
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	var best time.Time
+	for day := 0; day <= 7; day++ {
+		base := midnight.AddDate(0, 0, day)
+		// Probing opens at a window start, or at midnight when a new
+		// weekday begins inside a window that wraps around midnight
+		candidates := []time.Time{base}
+		for _, w := range windows {
+			candidates = append(candidates, base.Add(w.Start))
+		}
+		for _, start := range candidates {
+			if start.Before(t) || !s.open(start) {
+				continue
+			}
+			if best.IsZero() || start.Before(best) {
+				best = start
+			}
+		}
+		if !best.IsZero() {
+			return best
+		}
+	}
+	return best
+}
@@ -0,0 +1,122 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit/internal/schedule"
+)
+
+func clock(t *time.Time) func() time.Time { return func() time.Time { return *t } }
+
+func TestParseWindow(t *testing.T) {
+	w, err := schedule.ParseWindow("09:30-17:00")
+	require.NoError(t, err)
+	assert.Equal(t, schedule.Window{Start: 9*time.Hour + 30*time.Minute, End: 17 * time.Hour}, w)
+
+	for _, bad := range []string{"", "9-17", "09:00", "25:00-26:00"} {
+		_, err := schedule.ParseWindow(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestSchedule_Windows(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC) // Monday
+	s := schedule.New(schedule.Config{
+		Windows: []schedule.Window{{Start: 9 * time.Hour, End: 17 * time.Hour}},
+		Now:     clock(&now),
+	})
+
+	ok, reason, resume := s.Reserve("google")
+	assert.False(t, ok)
+	assert.Equal(t, schedule.ReasonOutsideWindow, reason)
+	assert.Equal(t, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), resume)
+
+	now = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	ok, _, _ = s.Reserve("google")
+	assert.True(t, ok)
+
+	now = time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	_, _, resume = s.Reserve("google")
+	assert.Equal(t, time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), resume)
+}
+
+func TestSchedule_OvernightWindowAndWeekdays(t *testing.T) {
+	now := time.Date(2026, 3, 6, 23, 0, 0, 0, time.UTC) // Friday night
+	s := schedule.New(schedule.Config{
+		Windows:  []schedule.Window{{Start: 22 * time.Hour, End: 6 * time.Hour}},
+		Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Now:      clock(&now),
+	})
+
+	ok, _, _ := s.Reserve("x")
+	assert.True(t, ok)
+
+	// Saturday early morning: inside the window, but not a weekday
+	now = time.Date(2026, 3, 7, 2, 0, 0, 0, time.UTC)
+	ok, _, resume := s.Reserve("x")
+	assert.False(t, ok)
+	assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), resume) // Monday 00:00, still inside 22-06
+}
+
+func TestSchedule_Budget(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	s := schedule.New(schedule.Config{
+		Budgets:       map[string]int{"google": 2},
+		DefaultBudget: 1,
+		Now:           clock(&now),
+	})
+
+	ok, _, _ := s.Reserve("google")
+	assert.True(t, ok)
+	ok, _, _ = s.Reserve("google")
+	assert.True(t, ok)
+	ok, reason, resume := s.Reserve("google")
+	assert.False(t, ok)
+	assert.Equal(t, schedule.ReasonBudget, reason)
+	assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), resume)
+
+	ok, _, _ = s.Reserve("example.net")
+	assert.True(t, ok)
+	ok, _, _ = s.Reserve("example.net")
+	assert.False(t, ok)
+
+	// Budgets reset the next day
+	now = now.Add(24 * time.Hour)
+	ok, _, _ = s.Reserve("google")
+	assert.True(t, ok)
+}
+
+func TestSchedule_Restore(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	cfg := schedule.Config{Budgets: map[string]int{"google": 3}, Now: clock(&now)}
+	s := schedule.New(cfg)
+	day, used := s.Counters()
+	assert.Equal(t, "2026-03-02", day)
+	assert.Nil(t, used)
+	for range 2 {
+		ok, _, _ := s.Reserve("google")
+		require.True(t, ok)
+	}
+	day, used = s.Counters()
+	assert.Equal(t, map[string]int{"google": 2}, used)
+
+	// A restarted process picks up today's count
+	restarted := schedule.New(cfg)
+	restarted.Restore(day, used)
+	ok, _, _ := restarted.Reserve("google")
+	assert.True(t, ok)
+	ok, reason, _ := restarted.Reserve("google")
+	assert.False(t, ok)
+	assert.Equal(t, schedule.ReasonBudget, reason)
+
+	// Yesterday's counters are ignored
+	now = now.AddDate(0, 0, 1)
+	fresh := schedule.New(cfg)
+	fresh.Restore(day, used)
+	_, used = fresh.Counters()
+	assert.Nil(t, used)
+}
@@ -0,0 +1,155 @@
+package smtppool
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// DialerOptions tunes the TCP connections the pool opens, e.g. for network
+// appliances that drop idle or unmarked connections.
+type DialerOptions struct {
+	// Dialer is the base dialer, copied; its Timeout is lowered to the
+	// probe's connect timeout. Default: a zero net.Dialer
+	Dialer *net.Dialer
+	// KeepAlive overrides the dialer's TCP keep-alive period; negative
+	// disables keep-alives. Default: 0 (the dialer's, 15s for Go's)
+	KeepAlive time.Duration
+	// DisableNoDelay turns Nagle's algorithm back on (Go sets TCP_NODELAY).
+	DisableNoDelay bool
+	// DSCP is the DiffServ code point (0-63) set on outgoing packets, in
+	// the IPv4 TOS or IPv6 traffic class byte. Default: 0 (unmarked)
+	DSCP int
+	// SourceIP is the local address connections originate from, on hosts
+	// with several; only remote addresses of its family are dialed. It
+	// replaces the dialer's LocalAddr. Default: the zero Addr (the kernel
+	// picks)
+	SourceIP netip.Addr
+	// Proxy is the URL of a SOCKS5 or HTTP CONNECT proxy the connections
+	// are tunneled through (see ParseProxy); the options above apply to
+	// the connection to the proxy. Default: "" (direct)
+	Proxy string
+	// ProxyDialer opens the connections instead, e.g. a Dialer of
+	// golang.org/x/net/proxy; Proxy and the options above don't apply.
+	ProxyDialer ProxyDialer
+}
+
+// Dial returns a dial function for Config.Dial built from o.
+func (o DialerOptions) Dial() func(network, address string, timeout time.Duration) (net.Conn, error) {
+	if o.ProxyDialer != nil {
+		return func(network, address string, timeout time.Duration) (net.Conn, error) {
+			return dialProxyDialer(o.ProxyDialer, network, address, timeout)
+		}
+	}
+	var proxy *url.URL
+	if o.Proxy != "" {
+		u, err := ParseProxy(o.Proxy)
+		if err != nil {
+			return func(string, string, time.Duration) (net.Conn, error) { return nil, err }
+		}
+		proxy = u
+	}
+
+	var base net.Dialer
+	if o.Dialer != nil {
+		base = *o.Dialer
+	}
+	if o.KeepAlive != 0 {
+		base.KeepAlive = o.KeepAlive
+	}
+	if o.SourceIP.IsValid() {
+		base.LocalAddr = &net.TCPAddr{IP: o.SourceIP.Unmap().AsSlice()}
+	}
+	if o.DSCP != 0 {
+		mark := func(network string, c syscall.RawConn) error { return setDSCP(network, c, o.DSCP) }
+		if control := base.ControlContext; control != nil {
+			base.ControlContext = func(ctx context.Context, network, address string, c syscall.RawConn) error {
+				if err := control(ctx, network, address, c); err != nil {
+					return err
+				}
+				return mark(network, c)
+			}
+		} else {
+			control := base.Control
+			base.Control = func(network, address string, c syscall.RawConn) error {
+				if control != nil {
+					if err := control(network, address, c); err != nil {
+						return err
+					}
+				}
+				return mark(network, c)
+			}
+		}
+	}
+
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		d := base
+		if timeout > 0 && (d.Timeout <= 0 || timeout < d.Timeout) {
+			d.Timeout = timeout
+		}
+		target := address
+		if proxy != nil {
+			target = proxy.Host
+		}
+		var deadline time.Time
+		if d.Timeout > 0 {
+			deadline = time.Now().Add(d.Timeout)
+		}
+		conn, err := d.Dial(network, target)
+		if err != nil {
+			if proxy != nil {
+				return nil, &ProxyError{Proxy: proxy.Host, Err: err}
+			}
+			return nil, err
+		}
+		if tcp, ok := conn.(*net.TCPConn); ok && o.DisableNoDelay {
+			if err := tcp.SetNoDelay(false); err != nil {
+				_ = conn.Close()
+				return nil, err
+			}
+		}
+		if proxy == nil {
+			return conn, nil
+		}
+		return tunnel(conn, proxy, address, deadline)
+	}
+}
+
+// DialContext opens a connection; see Config.DialContext.
+type DialContext func(ctx context.Context, network, address string) (net.Conn, error)
+
+// dialWithin dials address with dial, bounding ctx by timeout unless it
+// is zero.
+func dialWithin(ctx context.Context, dial DialContext, network, address string, timeout time.Duration) (net.Conn, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return dial(ctx, network, address)
+}
+
+// tunnel opens a tunnel to address through the proxy on conn, by
+// deadline unless it is zero.
+func tunnel(conn net.Conn, proxy *url.URL, address string, deadline time.Time) (net.Conn, error) {
+	fail := func(err error) (net.Conn, error) {
+		_ = conn.Close()
+		return nil, &ProxyError{Proxy: proxy.Host, Err: err}
+	}
+	if !deadline.IsZero() {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fail(err)
+		}
+	}
+	tc, err := handshakeProxy(conn, proxy, address)
+	if err != nil {
+		return fail(err)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return fail(err)
+	}
+	return tc, nil
+}
@@ -0,0 +1,86 @@
+package smtppool_test
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit/internal/smtppool"
+)
+
+func TestDialerOptions_Dial(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			_ = c.Close()
+		}
+	}()
+
+	var controlled bool
+	dial := smtppool.DialerOptions{
+		Dialer: &net.Dialer{
+			Timeout: time.Hour,
+			Control: func(network, address string, c syscall.RawConn) error {
+				controlled = true
+				return nil
+			},
+		},
+		KeepAlive:      30 * time.Second,
+		DisableNoDelay: true,
+		DSCP:           46, // EF
+	}.Dial()
+
+	conn, err := dial("tcp", l.Addr().String(), time.Second)
+	if err != nil && strings.Contains(err.Error(), "DSCP marking is not supported") {
+		t.Skip(err)
+	}
+	require.NoError(t, err)
+	_ = conn.Close()
+	assert.True(t, controlled, "the dialer's own Control still runs")
+}
+
+func TestDialerOptions_SourceIP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+	remote := make(chan net.Addr, 1)
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		remote <- c.RemoteAddr()
+		_ = c.Close()
+	}()
+
+	// All of 127.0.0.0/8 is local on Linux; elsewhere only 127.0.0.1 may be
+	source := netip.MustParseAddr("127.0.0.2")
+	dial := smtppool.DialerOptions{
+		Dialer:   &net.Dialer{LocalAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 3)}},
+		SourceIP: source,
+	}.Dial()
+	conn, err := dial("tcp", l.Addr().String(), time.Second)
+	if err != nil && strings.Contains(err.Error(), "assign requested address") {
+		t.Skip(err)
+	}
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	assert.Equal(t, source.String(), conn.LocalAddr().(*net.TCPAddr).IP.String())
+	assert.Equal(t, source.String(), (<-remote).(*net.TCPAddr).IP.String())
+
+	// Only addresses of the source's family are dialed
+	_, err = smtppool.DialerOptions{SourceIP: netip.MustParseAddr("::1")}.Dial()("tcp", l.Addr().String(), time.Second)
+	assert.Error(t, err)
+}
@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package smtppool
+
+import (
+	"errors"
+	"syscall"
+)
+
+// setDSCP is not supported on this platform.
+func setDSCP(string, syscall.RawConn, int) error {
+	return errors.New("smtppool: DSCP marking is not supported on this platform")
+}
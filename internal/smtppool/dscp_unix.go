@@ -0,0 +1,24 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package smtppool
+
+import (
+	"strings"
+	"syscall"
+)
+
+// setDSCP marks the socket's packets with the DiffServ code point dscp.
+func setDSCP(network string, c syscall.RawConn, dscp int) error {
+	var err error
+	ctlErr := c.Control(func(fd uintptr) {
+		if strings.HasSuffix(network, "6") {
+			err = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_TCLASS, dscp<<2)
+		} else {
+			err = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_TOS, dscp<<2)
+		}
+	})
+	if ctlErr != nil {
+		return ctlErr
+	}
+	return err
+}
@@ -4,9 +4,12 @@ package smtppool
 
 import (
 	"bufio"
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -22,7 +25,35 @@ type Config struct {
 	MaxConnsPerHost int           // max idle connections per MX host (default: 3)
 	MaxUsesPerConn  int           // max RCPT checks per connection before reconnect (default: 100)
 	MaxConnAge      time.Duration // max lifetime of a connection (default: 5m)
-	// Dial is injectable for testing. Defaults to net.DialTimeout.
+	// Adaptive limits the concurrent probes to each MX host, starting at
+	// MinConnsPerHost: the limit grows by one after as many healthy
+	// replies in a row as the limit, up to MaxConnsPerHost, and halves
+	// after a deferral (a 4xx reply or a failed connection). Probes over
+	// the limit wait for a slot until their connect timeout or deadline.
+	Adaptive        bool
+	MinConnsPerHost int // adaptive starting and minimum limit (default: 1)
+	// MaxLineLength and MaxResponseSize bound what a server may send in
+	// one reply line and one (multi-line) reply, so a broken or malicious
+	// server can't stream unbounded data into memory. RFC 5321 allows 512
+	// octets per line; the defaults leave room for servers that exceed
+	// it. Default: 4096 and 65536 bytes
+	MaxLineLength   int
+	MaxResponseSize int
+	// Dialer tunes the TCP connections when Dial is nil.
+	Dialer DialerOptions
+	// TLSMode selects plaintext sessions, STARTTLS upgrades or implicit
+	// TLS (SMTPS). Default: TLSNone
+	TLSMode TLSMode
+	// TLSConfig configures the TLS client of TLSStartTLS and TLSImplicit;
+	// its ServerName defaults to the MX host. Default: Go's defaults
+	TLSConfig *tls.Config
+	// DialContext opens the connections instead of Dialer, e.g. a custom
+	// transport; its context is the probe's (ProbeOptions.Context) and
+	// ends with the connect timeout. TLSMode still applies on top.
+	// Default: nil
+	DialContext DialContext
+	// Dial is injectable for testing. Defaults to DialContext, else
+	// Dialer.Dial().
 	Dial func(network, address string, timeout time.Duration) (net.Conn, error)
 }
 
@@ -31,12 +62,39 @@ type Pool struct {
 	cfg    Config
 	mu     sync.Mutex
 	hosts  map[string][]*conn
+	limits map[string]*hostLimit // by MX host, with Config.Adaptive
 	closed bool
 }
 
+// hostLimit is the adaptive concurrency limit of one MX host.
+type hostLimit struct {
+	limit    int           // concurrent probes allowed
+	inFlight int           // probes holding a slot
+	streak   int           // healthy replies since the limit last changed
+	wake     chan struct{} // closed and replaced when a slot frees up
+}
+
+// TLSMode selects how the pool secures SMTP sessions.
+type TLSMode int
+
+const (
+	// TLSNone keeps sessions in plaintext.
+	TLSNone TLSMode = iota
+	// TLSStartTLS upgrades a new session with STARTTLS right after EHLO
+	// when the server advertises it, and repeats EHLO; servers that don't
+	// advertise it are probed in plaintext.
+	TLSStartTLS
+	// TLSImplicit starts TLS before the banner, for SMTPS endpoints
+	// (usually port 465).
+	TLSImplicit
+)
+
 type conn struct {
+	host      string // MX host, the TLS server name
 	netConn   net.Conn
 	reader    *bufio.Reader
+	maxLine   int // Config.MaxLineLength
+	maxSize   int // Config.MaxResponseSize
 	writer    *bufio.Writer
 	createdAt time.Time
 	uses      int
@@ -44,8 +102,8 @@ type conn struct {
 
 // New creates a new SMTP connection pool.
 func New(cfg Config) *Pool {
-	if cfg.Dial == nil {
-		cfg.Dial = net.DialTimeout
+	if cfg.Dial == nil && cfg.DialContext == nil {
+		cfg.Dial = cfg.Dialer.Dial()
 	}
 	if cfg.MaxConnsPerHost <= 0 {
 		cfg.MaxConnsPerHost = 3
@@ -56,33 +114,224 @@ func New(cfg Config) *Pool {
 	if cfg.MaxConnAge <= 0 {
 		cfg.MaxConnAge = 5 * time.Minute
 	}
+	if cfg.MaxLineLength <= 0 {
+		cfg.MaxLineLength = 4096
+	}
+	if cfg.MaxResponseSize <= 0 {
+		cfg.MaxResponseSize = 64 << 10
+	}
+	if cfg.MinConnsPerHost <= 0 {
+		cfg.MinConnsPerHost = 1
+	}
+	cfg.MinConnsPerHost = min(cfg.MinConnsPerHost, cfg.MaxConnsPerHost)
 	return &Pool{
-		cfg:   cfg,
-		hosts: make(map[string][]*conn),
+		cfg:    cfg,
+		hosts:  make(map[string][]*conn),
+		limits: make(map[string]*hostLimit),
 	}
 }
 
+// Identity is the HELO name and MAIL FROM address a probe presents.
+type Identity struct {
+	HeloDomain string
+	MailFrom   string
+}
+
+// ProbeOptions overrides pool settings for a single probe. Zero fields
+// keep the pool's Config values.
+type ProbeOptions struct {
+	Identity       Identity
+	ConnectTimeout time.Duration
+	CommandTimeout time.Duration
+	// OnDial, if set, is called after the probe had to open a new
+	// connection, with the time the dial took and its error.
+	OnDial func(elapsed time.Duration, err error)
+	// Deadline, if set, bounds the whole probe: the dial and the commands
+	// don't run past it, whatever the timeouts. A probe whose deadline has
+	// passed fails with os.ErrDeadlineExceeded without dialing.
+	Deadline time.Time
+	// Context, if set, is passed to Config.DialContext, so cancelling the
+	// probe stops its dial. Default: context.Background()
+	Context context.Context
+}
+
 // CheckRCPT performs an SMTP RCPT TO check using a pooled connection.
 // For new connections: Banner → EHLO → MAIL FROM → RCPT TO
 // For reused connections: RSET → MAIL FROM → RCPT TO
 // Returns the RCPT TO response code and message.
 func (p *Pool) CheckRCPT(mxHost, email string) (code int, msg string, err error) {
-	c, isNew, err := p.get(mxHost)
+	return p.CheckRCPTWith(mxHost, email, ProbeOptions{})
+}
+
+// CheckRCPTWith is CheckRCPT with per-probe options. Connections are
+// pooled per MX host and HELO name, since EHLO is only sent once per
+// connection.
+func (p *Pool) CheckRCPTWith(mxHost, email string, o ProbeOptions) (code int, msg string, err error) {
+	o = p.resolve(o)
+	if o.expired() {
+		return 0, "", os.ErrDeadlineExceeded
+	}
+	key := mxHost
+	if o.Identity.HeloDomain != p.cfg.HeloDomain {
+		key = mxHost + "|" + o.Identity.HeloDomain
+	}
+	if err := p.acquire(mxHost, o); err != nil {
+		return 0, "", err
+	}
+	defer func() { p.release(mxHost, code, err) }()
+	c, isNew, err := p.get(key, mxHost, o)
 	if err != nil {
 		return 0, "", err
 	}
 
-	code, msg, err = p.doCheck(c, mxHost, email, isNew)
+	code, msg, err = p.doCheck(c, email, o, isNew)
+	if !isNew && errors.Is(err, errStale) {
+		// The server closed the idle connection: retry once on a new one
+		_ = c.netConn.Close()
+		if c, err = p.dialNew(mxHost, o); err != nil {
+			return 0, "", err
+		}
+		code, msg, err = p.doCheck(c, email, o, true)
+	}
 	if err != nil {
 		// Connection is broken, discard it
 		_ = c.netConn.Close()
 		return 0, "", err
 	}
 
+	if code == 421 {
+		// The server is closing the connection
+		_ = c.netConn.Close()
+	} else {
+		p.put(key, c)
+	}
+	return code, msg, nil
+}
+
+// CheckConnect verifies that the MX host accepts SMTP sessions without
+// starting a mail transaction.
+// For new connections: Banner → EHLO (the EHLO response is returned)
+// For reused connections: NOOP
+func (p *Pool) CheckConnect(mxHost string) (code int, msg string, err error) {
+	return p.CheckConnectWith(mxHost, ProbeOptions{})
+}
+
+// CheckConnectWith is CheckConnect with per-probe timeouts. The identity
+// is ignored: pooled connections greet with the configured HELO name.
+func (p *Pool) CheckConnectWith(mxHost string, o ProbeOptions) (code int, msg string, err error) {
+	o = p.resolve(o)
+	if o.expired() {
+		return 0, "", os.ErrDeadlineExceeded
+	}
+	if err := p.acquire(mxHost, o); err != nil {
+		return 0, "", err
+	}
+	defer func() { p.release(mxHost, code, err) }()
+	c, isNew, err := p.get(mxHost, mxHost, o)
+	if err != nil {
+		return 0, "", err
+	}
+
+	code, msg, err = p.doConnect(c, isNew, o)
+	if !isNew && errors.Is(err, errStale) {
+		_ = c.netConn.Close()
+		if c, err = p.dialNew(mxHost, o); err != nil {
+			return 0, "", err
+		}
+		code, msg, err = p.doConnect(c, true, o)
+	}
+	if err != nil {
+		_ = c.netConn.Close()
+		return 0, "", err
+	}
+
 	p.put(mxHost, c)
 	return code, msg, nil
 }
 
+// resolve fills the zero fields of o from the pool configuration.
+func (p *Pool) resolve(o ProbeOptions) ProbeOptions {
+	if o.Identity.HeloDomain == "" {
+		o.Identity.HeloDomain = p.cfg.HeloDomain
+	}
+	if o.Identity.MailFrom == "" {
+		o.Identity.MailFrom = p.cfg.MailFrom
+	}
+	if o.ConnectTimeout <= 0 {
+		o.ConnectTimeout = p.cfg.ConnectTimeout
+	}
+	if o.CommandTimeout <= 0 {
+		o.CommandTimeout = p.cfg.CommandTimeout
+	}
+	if !o.Deadline.IsZero() {
+		left := time.Until(o.Deadline)
+		if o.ConnectTimeout <= 0 || left < o.ConnectTimeout {
+			o.ConnectTimeout = left
+		}
+		if o.CommandTimeout <= 0 || left < o.CommandTimeout {
+			o.CommandTimeout = left
+		}
+	}
+	return o
+}
+
+// expired reports whether the probe's deadline has passed.
+func (o ProbeOptions) expired() bool {
+	return !o.Deadline.IsZero() && !time.Now().Before(o.Deadline)
+}
+
+// commandDeadline is when the commands of a probe starting now must end.
+func (o ProbeOptions) commandDeadline() time.Time {
+	d := time.Now().Add(o.CommandTimeout)
+	if !o.Deadline.IsZero() && o.Deadline.Before(d) {
+		return o.Deadline
+	}
+	return d
+}
+
+// Greeting is what an MX host says before a mail transaction starts.
+type Greeting struct {
+	Banner   string   // full banner line(s)
+	EHLO     []string // EHLO response lines without the reply code
+	StartTLS bool     // STARTTLS is advertised
+}
+
+// Greet opens a dedicated (unpooled) connection to the MX host, records its
+// banner and EHLO response, then sends QUIT. ctx bounds the dial.
+func (p *Pool) Greet(ctx context.Context, mxHost string) (Greeting, error) {
+	c, err := p.dial(ctx, mxHost, p.cfg.ConnectTimeout)
+	if err != nil {
+		return Greeting{}, err
+	}
+	defer func() {
+		sendQuit(c)
+		_ = c.netConn.Close()
+	}()
+
+	if err := c.netConn.SetDeadline(time.Now().Add(p.cfg.CommandTimeout)); err != nil {
+		return Greeting{}, fmt.Errorf("set deadline: %w", err)
+	}
+
+	var g Greeting
+	_, lines, err := readLines(c)
+	if err != nil {
+		return g, fmt.Errorf("read banner: %w", err)
+	}
+	g.Banner = strings.Join(lines, " | ")
+
+	if _, lines, err = ehlo(c, p.cfg.HeloDomain); err != nil {
+		return g, err
+	}
+	for _, l := range lines {
+		ext := strings.TrimPrefix(replyText(l), "-")
+		g.EHLO = append(g.EHLO, ext)
+		if strings.EqualFold(ext, "STARTTLS") {
+			g.StartTLS = true
+		}
+	}
+	return g, nil
+}
+
 // Close closes all connections in the pool.
 func (p *Pool) Close() error {
 	p.mu.Lock()
@@ -99,8 +348,147 @@ func (p *Pool) Close() error {
 	return nil
 }
 
-// get retrieves an existing connection from the pool or creates a new one.
-func (p *Pool) get(mxHost string) (*conn, bool, error) {
+// Idle returns the number of idle pooled connections and of the hosts
+// (per HELO name) they are pooled for.
+func (p *Pool) Idle() (conns, hosts int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, list := range p.hosts {
+		if len(list) > 0 {
+			conns += len(list)
+			hosts++
+		}
+	}
+	return conns, hosts
+}
+
+// IdleConns returns the number of idle pooled connections to mxHost,
+// across HELO names.
+func (p *Pool) IdleConns(mxHost string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for key, list := range p.hosts {
+		if hostOf(key) == mxHost {
+			n += len(list)
+		}
+	}
+	return n
+}
+
+// CloseHost closes the idle pooled connections to mxHost, across HELO
+// names, and returns how many it closed. It also forgets the host's
+// adaptive concurrency limit unless probes are in flight; connections in
+// use are returned to the pool as usual.
+func (p *Pool) CloseHost(mxHost string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for key, list := range p.hosts {
+		if hostOf(key) != mxHost {
+			continue
+		}
+		for _, c := range list {
+			sendQuit(c)
+			_ = c.netConn.Close()
+		}
+		n += len(list)
+		delete(p.hosts, key)
+	}
+	if h := p.limits[mxHost]; h != nil && h.inFlight == 0 {
+		delete(p.limits, mxHost)
+	}
+	return n
+}
+
+// hostOf returns the MX host of a pool key ("host" or "host|helo").
+func hostOf(key string) string {
+	host, _, _ := strings.Cut(key, "|")
+	return host
+}
+
+// HostLimits returns the adaptive concurrency limit chosen for every MX
+// host probed so far; nil unless Config.Adaptive is set.
+func (p *Pool) HostLimits() map[string]int {
+	if !p.cfg.Adaptive {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make(map[string]int, len(p.limits))
+	for host, h := range p.limits {
+		out[host] = h.limit
+	}
+	return out
+}
+
+// acquire takes a probe slot for mxHost under the adaptive limit, waiting
+// until the probe's deadline (or its connect timeout) for one to free up.
+func (p *Pool) acquire(mxHost string, o ProbeOptions) error {
+	if !p.cfg.Adaptive {
+		return nil
+	}
+	var expire <-chan time.Time
+	if deadline := o.Deadline; !deadline.IsZero() || o.ConnectTimeout > 0 {
+		if deadline.IsZero() {
+			deadline = time.Now().Add(o.ConnectTimeout)
+		}
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		expire = timer.C
+	}
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return errors.New("smtppool: pool is closed")
+		}
+		h := p.limits[mxHost]
+		if h == nil {
+			h = &hostLimit{limit: p.cfg.MinConnsPerHost, wake: make(chan struct{})}
+			p.limits[mxHost] = h
+		}
+		if h.inFlight < h.limit {
+			h.inFlight++
+			p.mu.Unlock()
+			return nil
+		}
+		wake, limit := h.wake, h.limit
+		p.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-expire:
+			return fmt.Errorf("smtppool: %s: no free connection slot (adaptive limit %d)", mxHost, limit)
+		}
+	}
+}
+
+// release frees the slot taken by acquire and adapts the host's limit to
+// the probe's outcome: additive increase after a streak of healthy
+// replies, multiplicative decrease after a deferral.
+func (p *Pool) release(mxHost string, code int, err error) {
+	if !p.cfg.Adaptive {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h := p.limits[mxHost]
+	h.inFlight--
+	if err != nil || (code >= 400 && code < 500) {
+		h.limit = max(p.cfg.MinConnsPerHost, h.limit/2)
+		h.streak = 0
+	} else if h.streak++; h.streak >= h.limit && h.limit < p.cfg.MaxConnsPerHost {
+		h.limit++
+		h.streak = 0
+	}
+	close(h.wake)
+	h.wake = make(chan struct{})
+}
+
+// get retrieves an existing connection from the pool under key, or
+// creates a new one to mxHost.
+func (p *Pool) get(key, mxHost string, o ProbeOptions) (*conn, bool, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -108,7 +496,7 @@ func (p *Pool) get(mxHost string) (*conn, bool, error) {
 		return nil, false, errors.New("smtppool: pool is closed")
 	}
 
-	conns := p.hosts[mxHost]
+	conns := p.hosts[key]
 
 	// Try to find a reusable connection (LIFO for better locality)
 	for i := len(conns) - 1; i >= 0; i-- {
@@ -122,79 +510,101 @@ func (p *Pool) get(mxHost string) (*conn, bool, error) {
 		}
 		// Take this connection out of the pool
 		conns = append(conns[:i], conns[i+1:]...)
-		p.hosts[mxHost] = conns
+		p.hosts[key] = conns
 		return c, false, nil
 	}
-	p.hosts[mxHost] = conns
+	p.hosts[key] = conns
 
 	// No reusable connection, create a new one
-	c, err := p.dial(mxHost)
+	c, err := p.dialNew(mxHost, o)
+	return c, true, err
+}
+
+// dialNew opens a new connection to mxHost for a probe, reporting the
+// dial to o.OnDial.
+func (p *Pool) dialNew(mxHost string, o ProbeOptions) (*conn, error) {
+	start := time.Now()
+	ctx := o.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c, err := p.dial(ctx, mxHost, o.ConnectTimeout)
+	if o.OnDial != nil {
+		o.OnDial(time.Since(start), err)
+	}
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
-	return c, true, nil
+	return c, nil
 }
 
-// put returns a connection to the pool for reuse.
-func (p *Pool) put(mxHost string, c *conn) {
+// put returns a connection to the pool for reuse under key.
+func (p *Pool) put(key string, c *conn) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if p.closed || len(p.hosts[mxHost]) >= p.cfg.MaxConnsPerHost {
+	limit := p.cfg.MaxConnsPerHost
+	if h := p.limits[hostOf(key)]; h != nil {
+		limit = h.limit // don't keep idle what the host may not use
+	}
+	if p.closed || len(p.hosts[key]) >= limit {
 		sendQuit(c)
 		_ = c.netConn.Close()
 		return
 	}
 
-	p.hosts[mxHost] = append(p.hosts[mxHost], c)
+	p.hosts[key] = append(p.hosts[key], c)
 }
 
-// dial creates a new TCP connection to the MX host.
-func (p *Pool) dial(mxHost string) (*conn, error) {
+// dial creates a new TCP connection to the MX host; ctx only reaches
+// Config.DialContext.
+func (p *Pool) dial(ctx context.Context, mxHost string, timeout time.Duration) (*conn, error) {
 	address := net.JoinHostPort(mxHost, p.cfg.Port)
-	netConn, err := p.cfg.Dial("tcp", address, p.cfg.ConnectTimeout)
+	var netConn net.Conn
+	var err error
+	if p.cfg.Dial != nil {
+		netConn, err = p.cfg.Dial("tcp", address, timeout)
+	} else {
+		netConn, err = dialWithin(ctx, p.cfg.DialContext, "tcp", address, timeout)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("connect to %s: %w", address, err)
 	}
+	if p.cfg.TLSMode == TLSImplicit {
+		if netConn, err = p.handshake(netConn, mxHost, time.Now().Add(timeout)); err != nil {
+			return nil, fmt.Errorf("connect to %s: %w", address, err)
+		}
+	}
 
 	return &conn{
+		host:      mxHost,
 		netConn:   netConn,
 		reader:    bufio.NewReader(netConn),
+		maxLine:   p.cfg.MaxLineLength,
+		maxSize:   p.cfg.MaxResponseSize,
 		writer:    bufio.NewWriter(netConn),
 		createdAt: time.Now(),
 	}, nil
 }
 
 // doCheck performs the SMTP check on a connection.
-func (p *Pool) doCheck(c *conn, mxHost, email string, isNew bool) (int, string, error) {
-	deadline := time.Now().Add(p.cfg.CommandTimeout)
-	if err := c.netConn.SetDeadline(deadline); err != nil {
+func (p *Pool) doCheck(c *conn, email string, o ProbeOptions, isNew bool) (int, string, error) {
+	if err := c.netConn.SetDeadline(o.commandDeadline()); err != nil {
 		return 0, "", fmt.Errorf("set deadline: %w", err)
 	}
 
 	if isNew {
-		// Read banner
-		code, msg, err := readResponse(c.reader)
-		if err != nil {
-			return 0, "", fmt.Errorf("read banner: %w", err)
-		}
-		if code >= 500 {
-			return 0, "", fmt.Errorf("server rejected connection: %d %s", code, msg)
-		}
-
-		// EHLO
-		code, msg, err = command(c, fmt.Sprintf("EHLO %s\r\n", p.cfg.HeloDomain))
-		if err != nil {
-			return 0, "", fmt.Errorf("EHLO failed: %w", err)
-		}
-		if code >= 400 {
-			return 0, "", fmt.Errorf("EHLO rejected: %d %s", code, msg)
+		if _, _, err := p.greet(c, o.Identity.HeloDomain); err != nil {
+			return 0, "", err
 		}
 	} else {
 		// RSET to start a fresh transaction on the reused connection
 		code, msg, err := command(c, "RSET\r\n")
 		if err != nil {
-			return 0, "", fmt.Errorf("RSET failed: %w", err)
+			return 0, "", fmt.Errorf("RSET failed: %w: %w", errStale, err)
+		}
+		if code == 421 {
+			return 0, "", fmt.Errorf("RSET rejected: %w: %d %s", errStale, code, msg)
 		}
 		if code >= 400 {
 			return 0, "", fmt.Errorf("RSET rejected: %d %s", code, msg)
@@ -202,13 +612,16 @@ func (p *Pool) doCheck(c *conn, mxHost, email string, isNew bool) (int, string,
 	}
 
 	// MAIL FROM
-	code, msg, err := command(c, fmt.Sprintf("MAIL FROM:<%s>\r\n", p.cfg.MailFrom))
+	code, msg, err := command(c, fmt.Sprintf("MAIL FROM:<%s>\r\n", o.Identity.MailFrom))
 	if err != nil {
 		return 0, "", fmt.Errorf("MAIL FROM failed: %w", err)
 	}
 	if code >= 500 {
 		return code, msg, nil
 	}
+	if code == 421 && !isNew {
+		return 0, "", fmt.Errorf("MAIL FROM temporary failure: %w: %d %s", errStale, code, msg)
+	}
 	if code >= 400 {
 		return 0, "", fmt.Errorf("MAIL FROM temporary failure: %d %s", code, msg)
 	}
@@ -223,15 +636,135 @@ func (p *Pool) doCheck(c *conn, mxHost, email string, isNew bool) (int, string,
 	return code, msg, nil
 }
 
+// doConnect performs the connection-level check on a connection.
+func (p *Pool) doConnect(c *conn, isNew bool, o ProbeOptions) (int, string, error) {
+	if err := c.netConn.SetDeadline(o.commandDeadline()); err != nil {
+		return 0, "", fmt.Errorf("set deadline: %w", err)
+	}
+	if isNew {
+		return p.greet(c, p.cfg.HeloDomain)
+	}
+	code, msg, err := command(c, "NOOP\r\n")
+	if err != nil {
+		return 0, "", fmt.Errorf("NOOP failed: %w: %w", errStale, err)
+	}
+	if code == 421 {
+		return 0, "", fmt.Errorf("NOOP rejected: %w: %d %s", errStale, code, msg)
+	}
+	if code >= 400 {
+		return 0, "", fmt.Errorf("NOOP rejected: %d %s", code, msg)
+	}
+	return code, msg, nil
+}
+
+// greet reads the banner of a new connection and sends EHLO with helo.
+// Returns the EHLO response.
+func (p *Pool) greet(c *conn, helo string) (int, string, error) {
+	code, msg, err := readResponse(c)
+	if err != nil {
+		return 0, "", fmt.Errorf("read banner: %w", err)
+	}
+	if code >= 500 {
+		return 0, "", fmt.Errorf("server rejected connection: %d %s", code, msg)
+	}
+
+	code, lines, err := ehlo(c, helo)
+	if err != nil {
+		return 0, "", err
+	}
+	if p.cfg.TLSMode == TLSStartTLS && advertises(lines, "STARTTLS") {
+		if err := p.startTLS(c); err != nil {
+			return 0, "", err
+		}
+		if code, lines, err = ehlo(c, helo); err != nil {
+			return 0, "", err
+		}
+	}
+	return code, strings.Join(lines, " | "), nil
+}
+
+// ehlo sends EHLO with helo and returns the reply lines.
+func ehlo(c *conn, helo string) (int, []string, error) {
+	if err := writeCommand(c, fmt.Sprintf("EHLO %s\r\n", helo)); err != nil {
+		return 0, nil, fmt.Errorf("EHLO failed: %w", err)
+	}
+	code, lines, err := readLines(c)
+	if err != nil {
+		return 0, nil, fmt.Errorf("EHLO failed: %w", err)
+	}
+	if code >= 400 {
+		return 0, nil, fmt.Errorf("EHLO rejected: %d %s", code, strings.Join(lines, " | "))
+	}
+	return code, lines, nil
+}
+
+// advertises reports whether the EHLO reply lines list the extension.
+func advertises(lines []string, ext string) bool {
+	for _, l := range lines {
+		keyword, _, _ := strings.Cut(strings.TrimPrefix(replyText(l), "-"), " ")
+		if strings.EqualFold(keyword, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// startTLS upgrades the session of c with STARTTLS (RFC 3207), under the
+// deadline already set on the connection.
+func (p *Pool) startTLS(c *conn) error {
+	code, msg, err := command(c, "STARTTLS\r\n")
+	if err != nil {
+		return fmt.Errorf("STARTTLS failed: %w", err)
+	}
+	if code != 220 {
+		return fmt.Errorf("STARTTLS rejected: %d %s", code, msg)
+	}
+	tc, err := p.handshake(c.netConn, c.host, time.Time{})
+	if err != nil {
+		return err
+	}
+	c.netConn, c.reader, c.writer = tc, bufio.NewReader(tc), bufio.NewWriter(tc)
+	return nil
+}
+
+// handshake runs a TLS client handshake with mxHost over raw, bounded by
+// deadline unless it is zero, and returns the TLS connection.
+func (p *Pool) handshake(raw net.Conn, mxHost string, deadline time.Time) (net.Conn, error) {
+	cfg := &tls.Config{}
+	if p.cfg.TLSConfig != nil {
+		cfg = p.cfg.TLSConfig.Clone()
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = strings.TrimSuffix(mxHost, ".")
+	}
+	if !deadline.IsZero() {
+		if err := raw.SetDeadline(deadline); err != nil {
+			_ = raw.Close()
+			return nil, fmt.Errorf("set deadline: %w", err)
+		}
+	}
+	tc := tls.Client(raw, cfg)
+	if err := tc.Handshake(); err != nil {
+		_ = raw.Close()
+		return nil, fmt.Errorf("TLS handshake: %w", err)
+	}
+	return tc, nil
+}
+
 // command sends an SMTP command and reads the response.
 func command(c *conn, cmd string) (int, string, error) {
-	if _, err := c.writer.WriteString(cmd); err != nil {
+	if err := writeCommand(c, cmd); err != nil {
 		return 0, "", err
 	}
-	if err := c.writer.Flush(); err != nil {
-		return 0, "", err
+	return readResponse(c)
+}
+
+// writeCommand sends an SMTP command without reading the response.
+func writeCommand(c *conn, cmd string) error {
+	if _, err := c.writer.WriteString(cmd); err != nil {
+		return err
 	}
-	return readResponse(c.reader)
+	return c.writer.Flush()
 }
 
 // sendQuit sends a QUIT command (best-effort, ignores errors).
@@ -242,27 +775,112 @@ func sendQuit(c *conn) {
 }
 
 // readResponse reads a (possibly multi-line) SMTP response.
-func readResponse(r *bufio.Reader) (code int, full string, err error) {
-	var lines []string
+func readResponse(c *conn) (code int, full string, err error) {
+	code, lines, err := readLines(c)
+	if err != nil {
+		return 0, "", err
+	}
+	return code, strings.Join(lines, " | "), nil
+}
+
+// readLines reads a (possibly multi-line) SMTP response and returns the
+// reply code of the last line and every line.
+//
+// It is lenient with what real servers send: leading whitespace is
+// dropped, blank lines are skipped, a bare code ("220") is a complete
+// line, and inside a multi-line reply a line without a code is taken as
+// a continuation of the text (some MTAs wrap long banners that way). The
+// last line is the first one with a code not followed by '-'.
+//
+// A line over the connection's maximum line length, or a reply over its
+// maximum size, fails with errResponseTooLarge; the connection can't be
+// reused after that.
+func readLines(c *conn) (code int, lines []string, err error) {
+	size := 0
 	for {
-		line, readErr := r.ReadString('\n')
+		line, readErr := readLine(c.reader, c.maxLine)
 		if readErr != nil {
-			return 0, "", fmt.Errorf("read SMTP response: %w", readErr)
+			return 0, nil, fmt.Errorf("read SMTP response: %w", readErr)
+		}
+		if size += len(line); size > c.maxSize {
+			return 0, nil, fmt.Errorf("read SMTP response: %w: over %d bytes", errResponseTooLarge, c.maxSize)
 		}
-		line = strings.TrimRight(line, "\r\n")
-		if len(line) < 3 {
-			return 0, "", errors.New("SMTP response line too short")
+		line = strings.TrimLeft(strings.TrimRight(line, "\r\n"), " \t")
+		if line == "" {
+			continue
+		}
+		if !hasReplyCode(line) {
+			if len(lines) > 0 {
+				lines = append(lines, line)
+				continue
+			}
+			return 0, nil, fmt.Errorf("malformed SMTP response line %q", truncate(line, 64))
 		}
 		lines = append(lines, line)
-		// If the 4th character is not '-', this is the last line
-		if len(line) < 4 || line[3] != '-' {
+		if len(line) == 3 || line[3] != '-' {
 			break
 		}
 	}
 
 	lastLine := lines[len(lines)-1]
-	if _, err := fmt.Sscanf(lastLine[:3], "%d", &code); err != nil {
-		return 0, "", fmt.Errorf("invalid SMTP response code %q: %w", lastLine[:3], err)
+	code = int(lastLine[0]-'0')*100 + int(lastLine[1]-'0')*10 + int(lastLine[2]-'0')
+	return code, lines, nil
+}
+
+// errStale marks a reused connection the server has closed or is closing
+// (an I/O error or 421 before the transaction started); the probe is
+// retried once on a new connection.
+var errStale = errors.New("stale connection")
+
+// errResponseTooLarge marks a reply over the line length or size limits.
+var errResponseTooLarge = errors.New("SMTP response too large")
+
+// readLine reads one line of at most limit bytes, newline included,
+// without buffering more than that.
+func readLine(r *bufio.Reader, limit int) (string, error) {
+	var buf []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(buf)+len(chunk) > limit {
+			return "", fmt.Errorf("%w: line over %d bytes", errResponseTooLarge, limit)
+		}
+		buf = append(buf, chunk...)
+		switch {
+		case err == nil:
+			return string(buf), nil
+		case !errors.Is(err, bufio.ErrBufferFull):
+			return "", err
+		}
 	}
-	return code, strings.Join(lines, " | "), nil
+}
+
+// hasReplyCode reports whether line starts with a three-digit reply code
+// (2xx to 5xx) followed by nothing, a space, a tab or '-'.
+func hasReplyCode(line string) bool {
+	if len(line) < 3 || line[0] < '2' || line[0] > '5' {
+		return false
+	}
+	for i := 1; i < 3; i++ {
+		if line[i] < '0' || line[i] > '9' {
+			return false
+		}
+	}
+	return len(line) == 3 || line[3] == ' ' || line[3] == '-' || line[3] == '\t'
+}
+
+// replyText returns the text of a reply line without its code and
+// separator; continuation lines without a code are returned as is.
+func replyText(line string) string {
+	if !hasReplyCode(line) {
+		return strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(line[3:])
+}
+
+// truncate shortens s to at most n bytes for error messages.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
 }
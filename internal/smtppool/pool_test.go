@@ -1,14 +1,30 @@
 package smtppool_test
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
 	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/optimode/emailkit/internal/smtppool"
+	"github.com/optimode/emailkit/test/integration/smtpd"
 )
 
 // mockSMTPServer simulates an SMTP server on a net.Pipe connection.
@@ -112,6 +128,193 @@ func TestPool_DifferentHosts(t *testing.T) {
 	assert.Equal(t, 2, dialCount) // different hosts, different connections
 }
 
+func TestPool_CheckRCPTWith(t *testing.T) {
+	var mu sync.Mutex
+	var commands []string
+	dialCount := 0
+
+	cfg := smtppool.Config{
+		HeloDomain:      "test.com",
+		MailFrom:        "verify@test.com",
+		ConnectTimeout:  5 * time.Second,
+		CommandTimeout:  5 * time.Second,
+		Port:            "25",
+		MaxConnsPerHost: 2,
+		Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			dialCount++
+			client, server := net.Pipe()
+			go func() {
+				defer func() { _ = server.Close() }()
+				_, _ = fmt.Fprintf(server, "220 mock.smtp ESMTP\r\n")
+				buf := make([]byte, 4096)
+				for {
+					n, err := server.Read(buf)
+					if err != nil {
+						return
+					}
+					cmd := strings.TrimSpace(string(buf[:n]))
+					mu.Lock()
+					commands = append(commands, cmd)
+					mu.Unlock()
+					if cmd == "QUIT" {
+						return
+					}
+					_, _ = fmt.Fprintf(server, "250 OK\r\n")
+				}
+			}()
+			return client, nil
+		},
+	}
+
+	pool := smtppool.New(cfg)
+	defer func() { _ = pool.Close() }()
+
+	alt := smtppool.ProbeOptions{Identity: smtppool.Identity{HeloDomain: "probe2.test.com", MailFrom: "check@probe2.test.com"}}
+	_, _, err := pool.CheckRCPT("mx.example.com", "user1@example.com")
+	assert.NoError(t, err)
+	code, _, err := pool.CheckRCPTWith("mx.example.com", "user2@example.com", alt)
+	assert.NoError(t, err)
+	assert.Equal(t, 250, code)
+	_, _, err = pool.CheckRCPTWith("mx.example.com", "user3@example.com", alt)
+	assert.NoError(t, err)
+
+	// One connection per HELO name, each reused for its own identity
+	assert.Equal(t, 2, dialCount)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, commands, "EHLO probe2.test.com")
+	assert.Contains(t, commands, "MAIL FROM:<check@probe2.test.com>")
+	assert.Contains(t, commands, "RCPT TO:<user3@example.com>")
+}
+
+func TestPool_CloseHost(t *testing.T) {
+	dialCount := 0
+	cfg := smtppool.Config{
+		HeloDomain:      "test.com",
+		MailFrom:        "verify@test.com",
+		ConnectTimeout:  5 * time.Second,
+		CommandTimeout:  5 * time.Second,
+		Port:            "25",
+		MaxConnsPerHost: 2,
+		Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			dialCount++
+			client, server := net.Pipe()
+			go func() {
+				defer func() { _ = server.Close() }()
+				_, _ = fmt.Fprintf(server, "220 mock.smtp ESMTP\r\n")
+				buf := make([]byte, 4096)
+				for {
+					n, err := server.Read(buf)
+					if err != nil || strings.TrimSpace(string(buf[:n])) == "QUIT" {
+						return
+					}
+					_, _ = fmt.Fprintf(server, "250 OK\r\n")
+				}
+			}()
+			return client, nil
+		},
+	}
+
+	pool := smtppool.New(cfg)
+	defer func() { _ = pool.Close() }()
+
+	alt := smtppool.ProbeOptions{Identity: smtppool.Identity{HeloDomain: "probe2.test.com", MailFrom: "check@probe2.test.com"}}
+	_, _, err := pool.CheckRCPT("mx.example.com", "user1@example.com")
+	require.NoError(t, err)
+	_, _, err = pool.CheckRCPTWith("mx.example.com", "user2@example.com", alt)
+	require.NoError(t, err)
+	_, _, err = pool.CheckRCPT("mx.other.com", "user@other.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, pool.IdleConns("mx.example.com"))
+	assert.Equal(t, 2, pool.CloseHost("mx.example.com"))
+	assert.Equal(t, 0, pool.IdleConns("mx.example.com"))
+	assert.Equal(t, 1, pool.IdleConns("mx.other.com"))
+	assert.Equal(t, 0, pool.CloseHost("mx.example.com"))
+
+	// The next probe dials afresh
+	_, _, err = pool.CheckRCPT("mx.example.com", "user3@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 4, dialCount)
+}
+
+func TestPool_DialContext(t *testing.T) {
+	var addresses []string
+	var deadline time.Time
+	cfg := smtppool.Config{
+		HeloDomain:      "test.com",
+		MailFrom:        "verify@test.com",
+		ConnectTimeout:  5 * time.Second,
+		CommandTimeout:  5 * time.Second,
+		Port:            "25",
+		MaxConnsPerHost: 1,
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			addresses = append(addresses, address)
+			deadline, _ = ctx.Deadline()
+			client, server := net.Pipe()
+			go mockSMTPServer(server, map[string]string{"EHLO": "250 OK", "MAIL FROM": "250 OK", "RCPT TO": "250 OK"})
+			return client, nil
+		},
+	}
+	pool := smtppool.New(cfg)
+	defer func() { _ = pool.Close() }()
+
+	start := time.Now()
+	code, _, err := pool.CheckRCPTWith("mx.example.com", "user@example.com", smtppool.ProbeOptions{ConnectTimeout: time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, 250, code)
+	assert.Equal(t, []string{"mx.example.com:25"}, addresses)
+	assert.WithinDuration(t, start.Add(time.Second), deadline, 500*time.Millisecond, "bounded by the connect timeout")
+
+	// The probe's context reaches the dial
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = pool.CheckRCPTWith("mx2.example.com", "user@example.com", smtppool.ProbeOptions{Context: ctx})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = pool.Greet(ctx, "mx3.example.com")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, addresses, 1)
+}
+
+func TestPool_Deadline(t *testing.T) {
+	var timeouts []time.Duration
+	cfg := smtppool.Config{
+		HeloDomain:     "test.com",
+		MailFrom:       "verify@test.com",
+		ConnectTimeout: 5 * time.Second,
+		CommandTimeout: 5 * time.Second,
+		Port:           "25",
+		Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			timeouts = append(timeouts, timeout)
+			client, server := net.Pipe()
+			// A server that never answers the banner
+			go func() {
+				defer func() { _ = server.Close() }()
+				_, _ = server.Read(make([]byte, 1))
+			}()
+			return client, nil
+		},
+	}
+	pool := smtppool.New(cfg)
+	defer func() { _ = pool.Close() }()
+
+	start := time.Now()
+	_, _, err := pool.CheckRCPTWith("mx.example.com", "user@example.com", smtppool.ProbeOptions{Deadline: start.Add(50 * time.Millisecond)})
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	if assert.Len(t, timeouts, 1) {
+		assert.LessOrEqual(t, timeouts[0], 50*time.Millisecond)
+	}
+
+	// A passed deadline fails without dialing
+	_, _, err = pool.CheckRCPTWith("mx.example.com", "user@example.com", smtppool.ProbeOptions{Deadline: start})
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	assert.Len(t, timeouts, 1)
+}
+
 func TestPool_RejectedRCPT(t *testing.T) {
 	cfg := smtppool.Config{
 		HeloDomain:     "test.com",
@@ -158,6 +361,49 @@ func TestPool_ConnectionError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestPool_OnDial(t *testing.T) {
+	fail := false
+	cfg := smtppool.Config{
+		HeloDomain:     "test.com",
+		MailFrom:       "verify@test.com",
+		ConnectTimeout: 1 * time.Second,
+		CommandTimeout: 1 * time.Second,
+		Port:           "25",
+		Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			if fail {
+				return nil, fmt.Errorf("connection refused")
+			}
+			client, server := net.Pipe()
+			go mockSMTPServer(server, map[string]string{
+				"EHLO": "250 OK", "RSET": "250 OK",
+				"MAIL FROM": "250 OK", "RCPT TO": "250 OK",
+			})
+			return client, nil
+		},
+	}
+	pool := smtppool.New(cfg)
+	defer func() { _ = pool.Close() }()
+
+	var dials []error
+	o := smtppool.ProbeOptions{OnDial: func(_ time.Duration, err error) { dials = append(dials, err) }}
+
+	_, _, err := pool.CheckRCPTWith("mx.example.com", "a@example.com", o)
+	assert.NoError(t, err)
+	_, _, err = pool.CheckRCPTWith("mx.example.com", "b@example.com", o) // reused: no dial
+	assert.NoError(t, err)
+	conns, hosts := pool.Idle()
+	assert.Equal(t, 1, conns)
+	assert.Equal(t, 1, hosts)
+	fail = true
+	_, _, err = pool.CheckRCPTWith("mx2.example.com", "c@example.com", o)
+	assert.Error(t, err)
+
+	if assert.Len(t, dials, 2) {
+		assert.NoError(t, dials[0])
+		assert.ErrorContains(t, dials[1], "connection refused")
+	}
+}
+
 func TestPool_CloseAndReject(t *testing.T) {
 	cfg := smtppool.Config{
 		HeloDomain:     "test.com",
@@ -183,3 +429,619 @@ func TestPool_CloseAndReject(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "closed")
 }
+
+func TestPool_CheckConnect(t *testing.T) {
+	var commands []string
+	cfg := smtppool.Config{
+		HeloDomain:     "test.com",
+		MailFrom:       "verify@test.com",
+		ConnectTimeout: 5 * time.Second,
+		CommandTimeout: 5 * time.Second,
+		Port:           "25",
+		Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			client, server := net.Pipe()
+			responses := map[string]string{
+				"EHLO": "250 mock.smtp greets test.com",
+				"NOOP": "250 OK",
+			}
+			go func() {
+				mockSMTPServer(&recordingConn{Conn: server, commands: &commands}, responses)
+			}()
+			return client, nil
+		},
+	}
+
+	pool := smtppool.New(cfg)
+	defer func() { _ = pool.Close() }()
+
+	// New connection: banner + EHLO, no mail transaction
+	code, msg, err := pool.CheckConnect("mx.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 250, code)
+	assert.Contains(t, msg, "greets")
+
+	// Reused connection: NOOP
+	code, _, err = pool.CheckConnect("mx.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 250, code)
+
+	assert.Equal(t, []string{"EHLO", "NOOP"}, commands)
+}
+
+func TestPool_CheckConnect_EHLORejected(t *testing.T) {
+	cfg := smtppool.Config{
+		HeloDomain:     "test.com",
+		MailFrom:       "verify@test.com",
+		ConnectTimeout: 5 * time.Second,
+		CommandTimeout: 5 * time.Second,
+		Port:           "25",
+		Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			client, server := net.Pipe()
+			go mockSMTPServer(server, map[string]string{"EHLO": "554 go away"})
+			return client, nil
+		},
+	}
+
+	pool := smtppool.New(cfg)
+	defer func() { _ = pool.Close() }()
+
+	_, _, err := pool.CheckConnect("mx.example.com")
+	assert.ErrorContains(t, err, "EHLO rejected")
+}
+
+// recordingConn records the verb of every command the client sends.
+type recordingConn struct {
+	net.Conn
+	commands *[]string
+}
+
+func (c *recordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n >= 4 {
+		*c.commands = append(*c.commands, string(b[:4]))
+	}
+	return n, err
+}
+
+func TestPool_Greet(t *testing.T) {
+	dialCount := 0
+	cfg := smtppool.Config{
+		HeloDomain:     "test.com",
+		MailFrom:       "verify@test.com",
+		ConnectTimeout: 5 * time.Second,
+		CommandTimeout: 5 * time.Second,
+		Port:           "25",
+		Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			dialCount++
+			client, server := net.Pipe()
+			responses := map[string]string{
+				"EHLO": "250-mock.smtp greets test.com\r\n250-SIZE 35882577\r\n250 STARTTLS",
+			}
+			go mockSMTPServer(server, responses)
+			return client, nil
+		},
+	}
+
+	pool := smtppool.New(cfg)
+	defer func() { _ = pool.Close() }()
+
+	g, err := pool.Greet(context.Background(), "mx.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "220 mock.smtp ESMTP", g.Banner)
+	assert.Equal(t, []string{"mock.smtp greets test.com", "SIZE 35882577", "STARTTLS"}, g.EHLO)
+	assert.True(t, g.StartTLS)
+
+	// Greet connections are not pooled
+	_, err = pool.Greet(context.Background(), "mx.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, dialCount)
+}
+
+func TestPool_Adaptive(t *testing.T) {
+	hold := make(chan struct{})
+	p := smtppool.New(smtppool.Config{
+		HeloDomain:      "test.com",
+		MailFrom:        "verify@test.com",
+		ConnectTimeout:  time.Second,
+		CommandTimeout:  time.Second,
+		Port:            "25",
+		MaxConnsPerHost: 3,
+		Adaptive:        true,
+		Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			client, server := net.Pipe()
+			go func() {
+				defer func() { _ = server.Close() }()
+				_, _ = fmt.Fprintf(server, "220 mock.smtp ESMTP\r\n")
+				buf := make([]byte, 4096)
+				for {
+					n, err := server.Read(buf)
+					if err != nil {
+						return
+					}
+					switch cmd := string(buf[:n]); {
+					case strings.HasPrefix(cmd, "QUIT"):
+						return
+					case strings.HasPrefix(cmd, "RCPT TO:<busy@"):
+						_, _ = fmt.Fprintf(server, "421 4.7.0 Too many connections\r\n")
+					case strings.HasPrefix(cmd, "RCPT TO:<slow@"):
+						<-hold
+						_, _ = fmt.Fprintf(server, "250 OK\r\n")
+					default:
+						_, _ = fmt.Fprintf(server, "250 OK\r\n")
+					}
+				}
+			}()
+			return client, nil
+		},
+	})
+	defer func() { _ = p.Close() }()
+
+	assert.Empty(t, p.HostLimits())
+
+	// Healthy replies grow the limit: to 2 after one, to 3 after two more
+	for range 3 {
+		code, _, err := p.CheckRCPT("mx.example.com", "user@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, 250, code)
+	}
+	assert.Equal(t, map[string]int{"mx.example.com": 3}, p.HostLimits())
+
+	// A deferral halves it
+	code, _, err := p.CheckRCPT("mx.example.com", "busy@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 421, code)
+	assert.Equal(t, map[string]int{"mx.example.com": 1}, p.HostLimits())
+
+	// At the limit, another probe waits for a slot until its deadline
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _ = p.CheckRCPT("mx.example.com", "slow@example.com")
+	}()
+	time.Sleep(20 * time.Millisecond)
+	_, _, err = p.CheckRCPTWith("mx.example.com", "user@example.com", smtppool.ProbeOptions{Deadline: time.Now().Add(50 * time.Millisecond)})
+	assert.ErrorContains(t, err, "no free connection slot")
+	close(hold)
+	<-done
+
+	code, _, err = p.CheckRCPT("mx.example.com", "user@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 250, code)
+}
+
+// bannerServer sends banner (raw bytes, written in chunks with delay
+// between them), then answers EHLO. It reads commands while still
+// writing the banner, so a client giving up on it can QUIT.
+func bannerServer(chunks []string, delay time.Duration) func(string, string, time.Duration) (net.Conn, error) {
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			for _, c := range chunks {
+				time.Sleep(delay)
+				if _, err := io.WriteString(server, c); err != nil {
+					return
+				}
+			}
+		}()
+		go func() {
+			defer func() { _ = server.Close() }()
+			buf := make([]byte, 4096)
+			for {
+				n, err := server.Read(buf)
+				if err != nil || strings.HasPrefix(string(buf[:n]), "QUIT") {
+					return
+				}
+				_, _ = io.WriteString(server, "250 OK\r\n")
+			}
+		}()
+		return client, nil
+	}
+}
+
+// TestPool_BannerCorpus greets servers with banners captured from real
+// MTAs (testdata/banners); every one must parse.
+func TestPool_BannerCorpus(t *testing.T) {
+	files, err := filepath.Glob("testdata/banners/*.txt")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, f := range files {
+		t.Run(strings.TrimSuffix(filepath.Base(f), ".txt"), func(t *testing.T) {
+			raw, err := os.ReadFile(f)
+			require.NoError(t, err)
+			p := smtppool.New(smtppool.Config{
+				HeloDomain:     "test.com",
+				MailFrom:       "verify@test.com",
+				ConnectTimeout: time.Second,
+				CommandTimeout: time.Second,
+				Port:           "25",
+				Dial:           bannerServer([]string{string(raw)}, 0),
+			})
+			defer func() { _ = p.Close() }()
+
+			g, err := p.Greet(context.Background(), "mx.example.com")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(g.Banner, "220"), g.Banner)
+
+			code, _, err := p.CheckConnect("mx.example.com")
+			require.NoError(t, err)
+			assert.Equal(t, 250, code)
+		})
+	}
+}
+
+func TestPool_BannerEdgeCases(t *testing.T) {
+	tests := []struct {
+		name    string
+		chunks  []string
+		delay   time.Duration
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "multi-line",
+			chunks: []string{"220-mx.example.org ESMTP\r\n220-No UCE\r\n220 ready\r\n"},
+			want:   "220-mx.example.org ESMTP | 220-No UCE | 220 ready",
+		},
+		{
+			name:   "informational lines sent slowly",
+			chunks: []string{"220-mx.example.org ESMTP\r\n", "220-please wait\r\n", "220 ready\r\n"},
+			delay:  100 * time.Millisecond,
+			want:   "220-mx.example.org ESMTP | 220-please wait | 220 ready",
+		},
+		{
+			name:   "line split across writes",
+			chunks: []string{"22", "0 mx.example", ".org ESMTP\r\n"},
+			delay:  20 * time.Millisecond,
+			want:   "220 mx.example.org ESMTP",
+		},
+		{
+			name:   "bare code",
+			chunks: []string{"220\r\n"},
+			want:   "220",
+		},
+		{
+			name:   "leading whitespace and blank lines",
+			chunks: []string{"\r\n  220-mx.example.org\r\n\r\n220 ready\r\n"},
+			want:   "220-mx.example.org | 220 ready",
+		},
+		{
+			name:   "continuation without code",
+			chunks: []string{"220-mx.example.org\r\n Welcome\r\n220 ready\r\n"},
+			want:   "220-mx.example.org | Welcome | 220 ready",
+		},
+		{
+			name:    "no reply code",
+			chunks:  []string{"ESMTP ready\r\n"},
+			wantErr: `malformed SMTP response line "ESMTP ready"`,
+		},
+		{
+			name:    "two-digit code",
+			chunks:  []string{"22\r\n"},
+			wantErr: "malformed SMTP response line",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := smtppool.New(smtppool.Config{
+				HeloDomain:     "test.com",
+				MailFrom:       "verify@test.com",
+				ConnectTimeout: time.Second,
+				CommandTimeout: time.Second,
+				Port:           "25",
+				Dial:           bannerServer(tt.chunks, tt.delay),
+			})
+			defer func() { _ = p.Close() }()
+
+			g, err := p.Greet(context.Background(), "mx.example.com")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, g.Banner)
+		})
+	}
+}
+
+func TestPool_ResponseLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		banner string
+	}{
+		{"endless line", strings.Repeat("220 ", 1<<20)},
+		{"endless multi-line reply", strings.Repeat("220-"+strings.Repeat("x", 200)+"\r\n", 1<<12)},
+		{"endless blank lines", strings.Repeat("\r\n", 1<<16)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := smtppool.New(smtppool.Config{
+				HeloDomain:      "test.com",
+				MailFrom:        "verify@test.com",
+				ConnectTimeout:  time.Second,
+				CommandTimeout:  time.Second,
+				Port:            "25",
+				MaxLineLength:   512,
+				MaxResponseSize: 8 << 10,
+				Dial:            bannerServer([]string{tt.banner}, 0),
+			})
+			defer func() { _ = p.Close() }()
+
+			_, err := p.Greet(context.Background(), "mx.example.com")
+			assert.ErrorContains(t, err, "SMTP response too large")
+			_, _, err = p.CheckRCPT("mx.example.com", "user@example.com")
+			assert.ErrorContains(t, err, "SMTP response too large")
+		})
+	}
+}
+
+// closingServer answers like an SMTP server that sends reply to the
+// command after the first limit ones on a connection (e.g. 421 when it
+// drops idle connections), then hangs up.
+func closingServer(dials *int, limit int, reply string) func(string, string, time.Duration) (net.Conn, error) {
+	var mu sync.Mutex
+	return func(network, address string, timeout time.Duration) (net.Conn, error) {
+		mu.Lock()
+		*dials++
+		mu.Unlock()
+		client, server := net.Pipe()
+		go func() {
+			defer func() { _ = server.Close() }()
+			_, _ = io.WriteString(server, "220 mock.smtp ESMTP\r\n")
+			buf := make([]byte, 4096)
+			for n := 0; ; n++ {
+				read, err := server.Read(buf)
+				if err != nil || strings.HasPrefix(string(buf[:read]), "QUIT") {
+					return
+				}
+				if n == limit {
+					if reply != "" {
+						_, _ = io.WriteString(server, reply+"\r\n")
+					}
+					return
+				}
+				_, _ = io.WriteString(server, "250 OK\r\n")
+			}
+		}()
+		return client, nil
+	}
+}
+
+func TestPool_StaleConnectionRetry(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int // commands answered before the reply
+		reply string
+	}{
+		{"421 at RSET", 3, "421 4.4.2 mock.smtp Error: timeout exceeded"},
+		{"421 at MAIL FROM", 4, "421 4.7.0 Too many messages, closing connection"},
+		{"closed without reply", 3, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dials := 0
+			p := smtppool.New(smtppool.Config{
+				HeloDomain:     "test.com",
+				MailFrom:       "verify@test.com",
+				ConnectTimeout: time.Second,
+				CommandTimeout: time.Second,
+				Port:           "25",
+				Dial:           closingServer(&dials, tt.limit, tt.reply),
+			})
+			defer func() { _ = p.Close() }()
+
+			// EHLO, MAIL, RCPT on the first connection; the reused one
+			// fails at RSET (or, if RSET passes, at MAIL FROM)
+			for range 2 {
+				code, _, err := p.CheckRCPT("mx.example.com", "user@example.com")
+				require.NoError(t, err)
+				assert.Equal(t, 250, code)
+			}
+			assert.Equal(t, 2, dials, "retried once on a new connection")
+		})
+	}
+}
+
+func TestPool_StaleConnectionRetriedOnce(t *testing.T) {
+	dials := 0
+	fresh := false
+	var mu sync.Mutex
+	p := smtppool.New(smtppool.Config{
+		HeloDomain:     "test.com",
+		MailFrom:       "verify@test.com",
+		ConnectTimeout: time.Second,
+		CommandTimeout: time.Second,
+		Port:           "25",
+		Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if fresh {
+				// The new connection is refused outright
+				dials++
+				return nil, errors.New("connection refused")
+			}
+			fresh = true
+			return closingServer(&dials, 3, "421 4.4.2 timeout exceeded")(network, address, timeout)
+		},
+	})
+	defer func() { _ = p.Close() }()
+
+	_, _, err := p.CheckRCPT("mx.example.com", "user@example.com")
+	require.NoError(t, err)
+	_, _, err = p.CheckRCPT("mx.example.com", "user@example.com")
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Equal(t, 2, dials)
+}
+
+func TestPool_421AtRCPTDiscardsConnection(t *testing.T) {
+	dials := 0
+	p := smtppool.New(smtppool.Config{
+		HeloDomain:     "test.com",
+		MailFrom:       "verify@test.com",
+		ConnectTimeout: time.Second,
+		CommandTimeout: time.Second,
+		Port:           "25",
+		Dial:           closingServer(&dials, 2, "421 4.7.0 Try again later, closing connection"),
+	})
+	defer func() { _ = p.Close() }()
+
+	code, _, err := p.CheckRCPT("mx.example.com", "user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 421, code)
+	conns, _ := p.Idle()
+	assert.Zero(t, conns, "a closing connection is not pooled")
+}
+
+// tlsPool returns a Pool dialing addr in the given TLS mode, trusting any
+// certificate.
+func tlsPool(addr string, mode smtppool.TLSMode) *smtppool.Pool {
+	return smtppool.New(smtppool.Config{
+		HeloDomain:      "test.com",
+		MailFrom:        "verify@test.com",
+		ConnectTimeout:  5 * time.Second,
+		CommandTimeout:  5 * time.Second,
+		Port:            "25",
+		MaxConnsPerHost: 1,
+		MaxUsesPerConn:  10,
+		MaxConnAge:      time.Minute,
+		TLSMode:         mode,
+		TLSConfig:       &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // self-signed test server
+		Dial: func(network, _ string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout(network, addr, timeout)
+		},
+	})
+}
+
+func TestPool_StartTLS(t *testing.T) {
+	s, err := smtpd.Start(smtpd.Config{Mode: smtpd.ModeStartTLS, Users: []string{"alice"}})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	// The server refuses MAIL FROM until the session is upgraded
+	plain := tlsPool(s.Addr().String(), smtppool.TLSNone)
+	defer func() { _ = plain.Close() }()
+	code, _, err := plain.CheckRCPT("mx.example.com", "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 530, code)
+
+	pool := tlsPool(s.Addr().String(), smtppool.TLSStartTLS)
+	defer func() { _ = pool.Close() }()
+	code, _, err = pool.CheckRCPT("mx.example.com", "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 250, code)
+	code, _, err = pool.CheckRCPT("mx.example.com", "bob@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 550, code)
+}
+
+func TestPool_StartTLSNotAdvertised(t *testing.T) {
+	cfg := smtppool.Config{
+		HeloDomain:      "test.com",
+		MailFrom:        "verify@test.com",
+		ConnectTimeout:  5 * time.Second,
+		CommandTimeout:  5 * time.Second,
+		Port:            "25",
+		MaxConnsPerHost: 1,
+		TLSMode:         smtppool.TLSStartTLS,
+		Dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			client, server := net.Pipe()
+			go mockSMTPServer(server, map[string]string{"EHLO": "250 OK", "MAIL FROM": "250 OK", "RCPT TO": "250 OK"})
+			return client, nil
+		},
+	}
+	pool := smtppool.New(cfg)
+	defer func() { _ = pool.Close() }()
+
+	code, _, err := pool.CheckRCPT("mx.example.com", "user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 250, code)
+}
+
+func TestPool_ImplicitTLS(t *testing.T) {
+	cert, err := selfSigned()
+	require.NoError(t, err)
+	s, err := smtpd.New(smtpd.Config{Users: []string{"alice"}})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = s.Serve(tls.NewListener(l, cert)) }()
+
+	pool := tlsPool(l.Addr().String(), smtppool.TLSImplicit)
+	defer func() { _ = pool.Close() }()
+	code, _, err := pool.CheckRCPT("mx.example.com", "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 250, code)
+
+	// A plaintext server fails the handshake
+	plain, err := smtpd.Start(smtpd.Config{})
+	require.NoError(t, err)
+	defer func() { _ = plain.Close() }()
+	pool = tlsPool(plain.Addr().String(), smtppool.TLSImplicit)
+	defer func() { _ = pool.Close() }()
+	_, _, err = pool.CheckRCPT("mx.example.com", "alice@example.com")
+	assert.ErrorContains(t, err, "TLS handshake")
+}
+
+func TestPool_ImplicitTLSVerifiesServerName(t *testing.T) {
+	cert, err := selfSigned()
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(leaf)
+
+	s, err := smtpd.New(smtpd.Config{Users: []string{"alice"}})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = s.Serve(tls.NewListener(l, cert)) }()
+
+	pool := smtppool.New(smtppool.Config{
+		HeloDomain:      "test.com",
+		MailFrom:        "verify@test.com",
+		ConnectTimeout:  5 * time.Second,
+		CommandTimeout:  5 * time.Second,
+		Port:            "465",
+		MaxConnsPerHost: 1,
+		TLSMode:         smtppool.TLSImplicit,
+		TLSConfig:       &tls.Config{RootCAs: roots},
+		Dial: func(network, _ string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout(network, l.Addr().String(), timeout)
+		},
+	})
+	defer func() { _ = pool.Close() }()
+
+	// The server name is the MX host without its trailing dot
+	code, _, err := pool.CheckRCPT("mx.example.com.", "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 250, code)
+
+	_, _, err = pool.CheckRCPT("mx.example.net.", "alice@example.com")
+	var certErr *tls.CertificateVerificationError
+	assert.ErrorAs(t, err, &certErr)
+}
+
+// selfSigned returns a server TLS config with a certificate for
+// mx.example.com signed by itself.
+func selfSigned() (*tls.Config, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mx.example.com"},
+		DNSNames:     []string{"mx.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}, nil
+}
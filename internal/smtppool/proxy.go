@@ -0,0 +1,253 @@
+package smtppool
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ProxyDialer opens connections through a proxy. The Dialer of
+// golang.org/x/net/proxy satisfies it; if the dialer also has a
+// DialContext method, that is used, bounded by the connect timeout.
+type ProxyDialer interface {
+	Dial(network, address string) (net.Conn, error)
+}
+
+// ProxyError is a failure to reach the proxy set in DialerOptions.Proxy
+// or to open a tunnel through it, as opposed to a failure of the MX host.
+type ProxyError struct {
+	Proxy string // host:port of the proxy
+	Err   error
+}
+
+func (e *ProxyError) Error() string { return "proxy " + e.Proxy + ": " + e.Err.Error() }
+
+func (e *ProxyError) Unwrap() error { return e.Err }
+
+type contextDialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// dialProxyDialer dials address with d within timeout.
+func dialProxyDialer(d ProxyDialer, network, address string, timeout time.Duration) (net.Conn, error) {
+	cd, ok := d.(contextDialer)
+	if !ok {
+		return d.Dial(network, address)
+	}
+	return dialWithin(context.Background(), cd.DialContext, network, address, timeout)
+}
+
+// ParseProxy parses a proxy URL: "socks5://[user:password@]host:port"
+// ("socks5h" is accepted as a synonym) or "http://[user:password@]host:port"
+// for an HTTP CONNECT proxy.
+func ParseProxy(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err // without the URL, which may hold a password
+		}
+		return nil, err
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h", "http":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (want socks5 or http)", u.Scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return nil, errors.New("proxy URL needs a host and port")
+	}
+	return u, nil
+}
+
+// handshakeProxy asks the proxy on conn, described by u, to connect to
+// address, and returns the tunnel. The proxy resolves host names.
+func handshakeProxy(conn net.Conn, u *url.URL, address string) (net.Conn, error) {
+	if u.Scheme == "http" {
+		return httpConnect(conn, u, address)
+	}
+	return socks5Tunnel(conn, u, address)
+}
+
+// httpConnect opens a tunnel with an HTTP CONNECT request.
+func httpConnect(conn net.Conn, u *url.URL, address string) (net.Conn, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if u.User != nil {
+		password, _ := u.User.Password()
+		token := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+token)
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy refused CONNECT: %s", resp.Status)
+	}
+	if br.Buffered() > 0 {
+		// The server's banner may have arrived with the proxy's answer
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a net.Conn whose first bytes were already buffered.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+// SOCKS5 protocol constants (RFC 1928, RFC 1929).
+const (
+	socks5Version      = 0x05
+	socks5NoAuth       = 0x00
+	socks5UserPassword = 0x02
+	socks5NoAcceptable = 0xff
+	socks5CmdConnect   = 0x01
+	socks5IPv4         = 0x01
+	socks5Domain       = 0x03
+	socks5IPv6         = 0x04
+)
+
+// socks5Replies are the failure replies of a SOCKS5 server.
+var socks5Replies = map[byte]string{
+	0x01: "general server failure",
+	0x02: "connection not allowed by ruleset",
+	0x03: "network unreachable",
+	0x04: "host unreachable",
+	0x05: "connection refused",
+	0x06: "TTL expired",
+	0x07: "command not supported",
+	0x08: "address type not supported",
+}
+
+// socks5Tunnel opens a tunnel with a SOCKS5 CONNECT request, logging in
+// with the URL's user and password if it has them.
+func socks5Tunnel(conn net.Conn, u *url.URL, address string) (net.Conn, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", portStr)
+	}
+
+	method := byte(socks5NoAuth)
+	if u.User != nil {
+		method = socks5UserPassword
+	}
+	if _, err := conn.Write([]byte{socks5Version, 1, method}); err != nil {
+		return nil, err
+	}
+	var reply [2]byte
+	if _, err := io.ReadFull(conn, reply[:]); err != nil {
+		return nil, err
+	}
+	switch {
+	case reply[0] != socks5Version:
+		return nil, fmt.Errorf("not a SOCKS5 proxy (version %d)", reply[0])
+	case reply[1] == socks5NoAcceptable:
+		return nil, errors.New("SOCKS5 proxy accepts no offered authentication method")
+	case reply[1] != method:
+		return nil, fmt.Errorf("SOCKS5 proxy chose unoffered method %d", reply[1])
+	}
+	if method == socks5UserPassword {
+		if err := socks5Login(conn, u.User); err != nil {
+			return nil, err
+		}
+	}
+
+	req := []byte{socks5Version, socks5CmdConnect, 0}
+	switch ip := net.ParseIP(host); {
+	case ip != nil && ip.To4() != nil:
+		req = append(append(req, socks5IPv4), ip.To4()...)
+	case ip != nil:
+		req = append(append(req, socks5IPv6), ip.To16()...)
+	case len(host) > 255:
+		return nil, fmt.Errorf("host name %q too long for SOCKS5", host)
+	default:
+		req = append(append(req, socks5Domain, byte(len(host))), host...)
+	}
+	req = binary.BigEndian.AppendUint16(req, uint16(port))
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	// VER REP RSV ATYP, then the bound address and port, which are unused
+	var head [4]byte
+	if _, err := io.ReadFull(conn, head[:]); err != nil {
+		return nil, err
+	}
+	if head[1] != 0 {
+		msg, ok := socks5Replies[head[1]]
+		if !ok {
+			msg = fmt.Sprintf("reply %d", head[1])
+		}
+		return nil, fmt.Errorf("SOCKS5 proxy refused CONNECT: %s", msg)
+	}
+	var skip int
+	switch head[3] {
+	case socks5IPv4:
+		skip = net.IPv4len
+	case socks5IPv6:
+		skip = net.IPv6len
+	case socks5Domain:
+		var n [1]byte
+		if _, err := io.ReadFull(conn, n[:]); err != nil {
+			return nil, err
+		}
+		skip = int(n[0])
+	default:
+		return nil, fmt.Errorf("SOCKS5 proxy sent address type %d", head[3])
+	}
+	if _, err := io.CopyN(io.Discard, conn, int64(skip+2)); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// socks5Login authenticates with a user name and password (RFC 1929).
+func socks5Login(conn net.Conn, user *url.Userinfo) error {
+	name := user.Username()
+	password, _ := user.Password()
+	if len(name) > 255 || len(password) > 255 {
+		return errors.New("SOCKS5 user name or password too long")
+	}
+	req := append([]byte{1, byte(len(name))}, name...)
+	req = append(append(req, byte(len(password))), password...)
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+	var reply [2]byte
+	if _, err := io.ReadFull(conn, reply[:]); err != nil {
+		return err
+	}
+	if reply[1] != 0 {
+		return errors.New("SOCKS5 proxy rejected the user name and password")
+	}
+	return nil
+}
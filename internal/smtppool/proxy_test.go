@@ -0,0 +1,253 @@
+package smtppool_test
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit/internal/smtppool"
+	"github.com/optimode/emailkit/test/integration/smtpd"
+)
+
+// fakeProxy accepts proxy clients with handshake, then relays them to
+// backend. It records the targets the clients asked for.
+type fakeProxy struct {
+	l         net.Listener
+	backend   string
+	handshake func(c net.Conn, br *bufio.Reader) (target string, ok bool)
+
+	mu      sync.Mutex
+	targets []string
+}
+
+func startProxy(t *testing.T, backend string, handshake func(net.Conn, *bufio.Reader) (string, bool)) *fakeProxy {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	p := &fakeProxy{l: l, backend: backend, handshake: handshake}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go p.serve(c)
+		}
+	}()
+	return p
+}
+
+func (p *fakeProxy) serve(c net.Conn) {
+	defer func() { _ = c.Close() }()
+	br := bufio.NewReader(c)
+	target, ok := p.handshake(c, br)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	p.targets = append(p.targets, target)
+	p.mu.Unlock()
+	b, err := net.Dial("tcp", p.backend)
+	if err != nil {
+		return
+	}
+	defer func() { _ = b.Close() }()
+	go func() { _, _ = io.Copy(b, br) }()
+	_, _ = io.Copy(c, b)
+}
+
+func (p *fakeProxy) Targets() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.targets...)
+}
+
+// socks5Handshake serves the SOCKS5 handshake, requiring user and password
+// if user is set.
+func socks5Handshake(user, password string) func(net.Conn, *bufio.Reader) (string, bool) {
+	return func(c net.Conn, br *bufio.Reader) (string, bool) {
+		head := make([]byte, 2)
+		if _, err := io.ReadFull(br, head); err != nil {
+			return "", false
+		}
+		methods := make([]byte, head[1])
+		if _, err := io.ReadFull(br, methods); err != nil {
+			return "", false
+		}
+		if user == "" {
+			_, _ = c.Write([]byte{5, 0})
+		} else {
+			_, _ = c.Write([]byte{5, 2})
+			ver, _ := br.ReadByte()
+			u := make([]byte, readLen(br))
+			_, _ = io.ReadFull(br, u)
+			pw := make([]byte, readLen(br))
+			_, _ = io.ReadFull(br, pw)
+			if ver != 1 || string(u) != user || string(pw) != password {
+				_, _ = c.Write([]byte{1, 1})
+				return "", false
+			}
+			_, _ = c.Write([]byte{1, 0})
+		}
+		req := make([]byte, 4)
+		if _, err := io.ReadFull(br, req); err != nil || req[1] != 1 {
+			return "", false
+		}
+		var host string
+		switch req[3] {
+		case 1:
+			ip := make([]byte, 4)
+			_, _ = io.ReadFull(br, ip)
+			host = net.IP(ip).String()
+		case 3:
+			name := make([]byte, readLen(br))
+			_, _ = io.ReadFull(br, name)
+			host = string(name)
+		}
+		port := make([]byte, 2)
+		_, _ = io.ReadFull(br, port)
+		_, _ = c.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), true
+	}
+}
+
+func readLen(br *bufio.Reader) int {
+	n, _ := br.ReadByte()
+	return int(n)
+}
+
+// connectHandshake serves an HTTP CONNECT request, requiring the
+// Proxy-Authorization auth if set.
+func connectHandshake(auth string) func(net.Conn, *bufio.Reader) (string, bool) {
+	return func(c net.Conn, br *bufio.Reader) (string, bool) {
+		req, err := http.ReadRequest(br)
+		if err != nil || req.Method != http.MethodConnect {
+			return "", false
+		}
+		if auth != "" && req.Header.Get("Proxy-Authorization") != auth {
+			_, _ = io.WriteString(c, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+			return "", false
+		}
+		_, _ = io.WriteString(c, "HTTP/1.1 200 Connection established\r\n\r\n")
+		return req.Host, true
+	}
+}
+
+func proxyPool(opts smtppool.DialerOptions) *smtppool.Pool {
+	return smtppool.New(smtppool.Config{
+		HeloDomain:      "test.com",
+		MailFrom:        "verify@test.com",
+		ConnectTimeout:  5 * time.Second,
+		CommandTimeout:  5 * time.Second,
+		Port:            "25",
+		MaxConnsPerHost: 1,
+		Dialer:          opts,
+	})
+}
+
+func TestDialerOptions_Proxy(t *testing.T) {
+	s, err := smtpd.Start(smtpd.Config{Users: []string{"alice"}})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:pa ss"))
+
+	tests := []struct {
+		name      string
+		handshake func(net.Conn, *bufio.Reader) (string, bool)
+		url       string
+		host      string
+		target    string
+	}{
+		{"socks5", socks5Handshake("", ""), "socks5://%s", "mx.example.com", "mx.example.com:25"},
+		{"socks5 login", socks5Handshake("user", "pa ss"), "socks5h://user:pa%%20ss@%s", "mx.example.com.", "mx.example.com.:25"},
+		{"socks5 ip", socks5Handshake("", ""), "socks5://%s", "192.0.2.1", "192.0.2.1:25"},
+		{"http connect", connectHandshake(""), "http://%s", "mx.example.com", "mx.example.com:25"},
+		{"http connect login", connectHandshake(basic), "http://user:pa%%20ss@%s", "mx.example.com", "mx.example.com:25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := startProxy(t, s.Addr().String(), tt.handshake)
+			pool := proxyPool(smtppool.DialerOptions{Proxy: fmt.Sprintf(tt.url, p.l.Addr().String())})
+			defer func() { _ = pool.Close() }()
+
+			code, _, err := pool.CheckRCPT(tt.host, "alice@example.com")
+			require.NoError(t, err)
+			assert.Equal(t, 250, code)
+			assert.Equal(t, []string{tt.target}, p.Targets())
+		})
+	}
+}
+
+func TestDialerOptions_ProxyRefused(t *testing.T) {
+	s, err := smtpd.Start(smtpd.Config{})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	p := startProxy(t, s.Addr().String(), socks5Handshake("user", "secret"))
+	pool := proxyPool(smtppool.DialerOptions{Proxy: "socks5://user:wrong@" + p.l.Addr().String()})
+	defer func() { _ = pool.Close() }()
+	_, _, err = pool.CheckRCPT("mx.example.com", "alice@example.com")
+	assert.ErrorContains(t, err, "rejected the user name and password")
+
+	p = startProxy(t, s.Addr().String(), connectHandshake("Basic x"))
+	pool = proxyPool(smtppool.DialerOptions{Proxy: "http://" + p.l.Addr().String()})
+	defer func() { _ = pool.Close() }()
+	_, _, err = pool.CheckRCPT("mx.example.com", "alice@example.com")
+	assert.ErrorContains(t, err, "proxy refused CONNECT: 407")
+	var proxyErr *smtppool.ProxyError
+	require.ErrorAs(t, err, &proxyErr)
+	assert.Equal(t, p.l.Addr().String(), proxyErr.Proxy)
+
+	pool = proxyPool(smtppool.DialerOptions{Proxy: "ftp://proxy.example:21"})
+	defer func() { _ = pool.Close() }()
+	_, _, err = pool.CheckRCPT("mx.example.com", "alice@example.com")
+	assert.ErrorContains(t, err, `unsupported proxy scheme "ftp"`)
+}
+
+// recordingDialer is a ProxyDialer dialing addr, whatever it is asked for.
+type recordingDialer struct {
+	addr  string
+	asked []string
+}
+
+func (d *recordingDialer) Dial(network, address string) (net.Conn, error) {
+	d.asked = append(d.asked, address)
+	return net.Dial(network, d.addr)
+}
+
+func TestDialerOptions_ProxyDialer(t *testing.T) {
+	s, err := smtpd.Start(smtpd.Config{Users: []string{"alice"}})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	d := &recordingDialer{addr: s.Addr().String()}
+	pool := proxyPool(smtppool.DialerOptions{ProxyDialer: d, Proxy: "socks5://ignored.example:1080"})
+	defer func() { _ = pool.Close() }()
+	code, _, err := pool.CheckRCPT("mx.example.com", "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 250, code)
+	assert.Equal(t, []string{"mx.example.com:25"}, d.asked)
+}
+
+func TestParseProxy(t *testing.T) {
+	for _, ok := range []string{"socks5://proxy.example:1080", "socks5h://u:p@10.0.0.1:1080", "http://[2001:db8::1]:3128"} {
+		_, err := smtppool.ParseProxy(ok)
+		assert.NoError(t, err, ok)
+	}
+	for _, bad := range []string{"https://proxy.example:443", "socks5://proxy.example", "proxy.example:1080", "http://:3128", "%zz"} {
+		_, err := smtppool.ParseProxy(bad)
+		assert.Error(t, err, bad)
+	}
+}
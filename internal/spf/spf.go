@@ -0,0 +1,416 @@
+// Package spf evaluates SPF records (RFC 7208) to tell whether an IP
+// address may send mail for a domain.
+//
+// The evaluator covers the mechanisms in common use (all, include, a, mx,
+// ip4, ip6, exists, redirect) and the basic macros. ptr is treated as not
+// matching, as RFC 7208 discourages it, and exp= is ignored.
+package spf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Result is an SPF check_host result.
+type Result string
+
+const (
+	None      Result = "none"
+	Neutral   Result = "neutral"
+	Pass      Result = "pass"
+	Fail      Result = "fail"
+	SoftFail  Result = "softfail"
+	TempError Result = "temperror"
+	PermError Result = "permerror"
+)
+
+// maxLookups is the RFC 7208 §4.6.4 limit on DNS-querying terms.
+const maxLookups = 10
+
+// Resolver is the DNS interface the evaluator needs. *net.Resolver satisfies it.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// Check evaluates the SPF record of domain for ip and the MAIL FROM
+// address sender. The error explains TempError and PermError results.
+func Check(ctx context.Context, r Resolver, ip net.IP, domain, sender string) (Result, error) {
+	e := &evaluator{r: r, ip: ip, sender: sender}
+	return e.checkHost(ctx, strings.TrimSuffix(strings.ToLower(domain), "."))
+}
+
+type evaluator struct {
+	r       Resolver
+	ip      net.IP
+	sender  string
+	lookups int
+}
+
+func (e *evaluator) checkHost(ctx context.Context, domain string) (Result, error) {
+	record, err := e.record(ctx, domain)
+	if err != nil {
+		return TempError, err
+	}
+	if record == "" {
+		return None, nil
+	}
+	if strings.Contains(record, "\x00") {
+		return PermError, fmt.Errorf("multiple SPF records for %s", domain)
+	}
+
+	var redirect string
+	for _, term := range strings.Fields(record)[1:] {
+		if name, value, ok := strings.Cut(term, "="); ok && !strings.ContainsAny(name, ":/") {
+			if strings.EqualFold(name, "redirect") {
+				redirect = value
+			}
+			continue // exp= and unknown modifiers are ignored
+		}
+
+		qualifier := Pass
+		switch term[0] {
+		case '+':
+			term = term[1:]
+		case '-':
+			qualifier, term = Fail, term[1:]
+		case '~':
+			qualifier, term = SoftFail, term[1:]
+		case '?':
+			qualifier, term = Neutral, term[1:]
+		}
+
+		match, res, err := e.mechanism(ctx, domain, term)
+		if err != nil {
+			return res, err
+		}
+		if match {
+			return qualifier, nil
+		}
+	}
+
+	if redirect != "" {
+		if err := e.count(); err != nil {
+			return PermError, err
+		}
+		target, err := e.expand(redirect, domain)
+		if err != nil {
+			return PermError, err
+		}
+		res, err := e.checkHost(ctx, target)
+		if res == None {
+			return PermError, fmt.Errorf("redirect target %s has no SPF record", target)
+		}
+		return res, err
+	}
+	return Neutral, nil
+}
+
+// mechanism reports whether the mechanism matches. A non-nil error aborts
+// the evaluation with the returned result.
+func (e *evaluator) mechanism(ctx context.Context, domain, term string) (bool, Result, error) {
+	name, arg, _ := strings.Cut(term, ":")
+	name = strings.ToLower(name)
+	// "a/24" and "mx/24" carry a CIDR suffix without a domain
+	if i := strings.IndexByte(name, '/'); i >= 0 && arg == "" {
+		name, arg = name[:i], name[i:]
+	}
+
+	switch name {
+	case "all":
+		return true, "", nil
+
+	case "ip4", "ip6":
+		_, network, err := parseNetwork(arg, name == "ip6")
+		if err != nil {
+			return false, PermError, err
+		}
+		return network.Contains(e.ip), "", nil
+
+	case "include":
+		if err := e.count(); err != nil {
+			return false, PermError, err
+		}
+		target, err := e.expand(arg, domain)
+		if err != nil {
+			return false, PermError, err
+		}
+		res, err := e.checkHost(ctx, target)
+		switch res {
+		case Pass:
+			return true, "", nil
+		case TempError:
+			return false, TempError, err
+		case PermError, None:
+			if err == nil {
+				err = fmt.Errorf("include target %s has no SPF record", target)
+			}
+			return false, PermError, err
+		}
+		return false, "", nil
+
+	case "a", "mx":
+		if err := e.count(); err != nil {
+			return false, PermError, err
+		}
+		spec, cidr4, cidr6, err := splitCIDR(arg)
+		if err != nil {
+			return false, PermError, err
+		}
+		target := domain
+		if spec != "" {
+			if target, err = e.expand(spec, domain); err != nil {
+				return false, PermError, err
+			}
+		}
+		hosts := []string{target}
+		if name == "mx" {
+			mxs, err := e.r.LookupMX(ctx, target)
+			if err != nil && !isNotFound(err) {
+				return false, TempError, err
+			}
+			hosts = hosts[:0]
+			for _, mx := range mxs {
+				hosts = append(hosts, mx.Host)
+			}
+		}
+		for _, host := range hosts {
+			addrs, err := e.r.LookupHost(ctx, host)
+			if err != nil && !isNotFound(err) {
+				return false, TempError, err
+			}
+			for _, a := range addrs {
+				if addr := net.ParseIP(a); addr != nil && matchCIDR(e.ip, addr, cidr4, cidr6) {
+					return true, "", nil
+				}
+			}
+		}
+		return false, "", nil
+
+	case "exists":
+		if err := e.count(); err != nil {
+			return false, PermError, err
+		}
+		target, err := e.expand(arg, domain)
+		if err != nil {
+			return false, PermError, err
+		}
+		addrs, err := e.r.LookupHost(ctx, target)
+		if err != nil && !isNotFound(err) {
+			return false, TempError, err
+		}
+		return len(addrs) > 0, "", nil
+
+	case "ptr":
+		if err := e.count(); err != nil {
+			return false, PermError, err
+		}
+		return false, "", nil
+	}
+	return false, PermError, fmt.Errorf("unknown mechanism %q", term)
+}
+
+// record returns the domain's SPF record, "" if it has none, or the
+// records joined with NUL if it has several.
+func (e *evaluator) record(ctx context.Context, domain string) (string, error) {
+	txts, err := e.r.LookupTXT(ctx, domain)
+	if err != nil {
+		if isNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	var records []string
+	for _, txt := range txts {
+		if strings.EqualFold(txt, "v=spf1") || strings.HasPrefix(strings.ToLower(txt), "v=spf1 ") {
+			records = append(records, txt)
+		}
+	}
+	return strings.Join(records, "\x00"), nil
+}
+
+func (e *evaluator) count() error {
+	e.lookups++
+	if e.lookups > maxLookups {
+		return errors.New("too many DNS lookups")
+	}
+	return nil
+}
+
+// expand expands the macros of a domain-spec (RFC 7208 §7).
+func (e *evaluator) expand(spec, domain string) (string, error) {
+	if !strings.Contains(spec, "%") {
+		return strings.TrimSuffix(strings.ToLower(spec), "."), nil
+	}
+	local, senderDomain, ok := strings.Cut(e.sender, "@")
+	if !ok {
+		local, senderDomain = "postmaster", e.sender
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(spec); i++ {
+		if spec[i] != '%' {
+			b.WriteByte(spec[i])
+			continue
+		}
+		if i+1 >= len(spec) {
+			return "", fmt.Errorf("bad macro in %q", spec)
+		}
+		i++
+		switch spec[i] {
+		case '%':
+			b.WriteByte('%')
+			continue
+		case '_':
+			b.WriteByte(' ')
+			continue
+		case '-':
+			b.WriteString("%20")
+			continue
+		case '{':
+		default:
+			return "", fmt.Errorf("bad macro in %q", spec)
+		}
+		end := strings.IndexByte(spec[i:], '}')
+		if end < 2 {
+			return "", fmt.Errorf("bad macro in %q", spec)
+		}
+		macro := spec[i+1 : i+end]
+		i += end
+
+		var value string
+		switch macro[0] | 0x20 {
+		case 's':
+			value = e.sender
+		case 'l':
+			value = local
+		case 'o':
+			value = senderDomain
+		case 'd', 'h':
+			value = domain
+		case 'i':
+			value = dottedIP(e.ip)
+		case 'v':
+			value = "in-addr"
+			if e.ip.To4() == nil {
+				value = "ip6"
+			}
+		case 'p':
+			value = "unknown"
+		default:
+			return "", fmt.Errorf("bad macro letter in %q", spec)
+		}
+		b.WriteString(transform(value, macro[1:]))
+	}
+	return strings.TrimSuffix(strings.ToLower(b.String()), "."), nil
+}
+
+// transform applies a macro's digits, reversal and delimiters.
+func transform(value, mods string) string {
+	digits := 0
+	for len(mods) > 0 && mods[0] >= '0' && mods[0] <= '9' {
+		digits = digits*10 + int(mods[0]-'0')
+		mods = mods[1:]
+	}
+	reverse := len(mods) > 0 && (mods[0] == 'r' || mods[0] == 'R')
+	if reverse {
+		mods = mods[1:]
+	}
+	delims := mods
+	if delims == "" {
+		delims = "."
+	}
+	parts := strings.FieldsFunc(value, func(r rune) bool { return strings.ContainsRune(delims, r) })
+	if reverse {
+		for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+			parts[i], parts[j] = parts[j], parts[i]
+		}
+	}
+	if digits > 0 && digits < len(parts) {
+		parts = parts[len(parts)-digits:]
+	}
+	return strings.Join(parts, ".")
+}
+
+// dottedIP is the %{i} form: dotted quad, or dotted nibbles for IPv6.
+func dottedIP(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		return v4.String()
+	}
+	var b strings.Builder
+	for i, x := range ip.To16() {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(strconv.FormatUint(uint64(x>>4), 16))
+		b.WriteByte('.')
+		b.WriteString(strconv.FormatUint(uint64(x&0xf), 16))
+	}
+	return b.String()
+}
+
+// parseNetwork parses an ip4/ip6 argument, with an optional CIDR length.
+func parseNetwork(arg string, v6 bool) (net.IP, *net.IPNet, error) {
+	if !strings.Contains(arg, "/") {
+		if v6 {
+			arg += "/128"
+		} else {
+			arg += "/32"
+		}
+	}
+	ip, network, err := net.ParseCIDR(arg)
+	if err != nil || (ip.To4() == nil) != v6 {
+		return nil, nil, fmt.Errorf("invalid network %q", arg)
+	}
+	return ip, network, nil
+}
+
+// splitCIDR splits "domain/24//64" into the domain-spec and both lengths
+// (-1 when absent).
+func splitCIDR(arg string) (spec string, cidr4, cidr6 int, err error) {
+	cidr4, cidr6 = -1, -1
+	spec, suffix, found := strings.Cut(arg, "/")
+	if !found {
+		return spec, cidr4, cidr6, nil
+	}
+	suffix = "/" + suffix
+	if i := strings.Index(suffix, "//"); i >= 0 {
+		if cidr6, err = strconv.Atoi(suffix[i+2:]); err != nil || cidr6 < 0 || cidr6 > 128 {
+			return "", 0, 0, fmt.Errorf("invalid CIDR %q", arg)
+		}
+		suffix = suffix[:i]
+	}
+	if suffix != "" {
+		if cidr4, err = strconv.Atoi(suffix[1:]); err != nil || cidr4 < 0 || cidr4 > 32 {
+			return "", 0, 0, fmt.Errorf("invalid CIDR %q", arg)
+		}
+	}
+	return spec, cidr4, cidr6, nil
+}
+
+// matchCIDR reports whether ip and addr agree on the CIDR prefix of their family.
+func matchCIDR(ip, addr net.IP, cidr4, cidr6 int) bool {
+	if (ip.To4() == nil) != (addr.To4() == nil) {
+		return false
+	}
+	bits, size := cidr6, 128
+	if ip.To4() != nil {
+		bits, size = cidr4, 32
+		ip, addr = ip.To4(), addr.To4()
+	}
+	if bits < 0 {
+		bits = size
+	}
+	mask := net.CIDRMask(bits, size)
+	return ip.Mask(mask).Equal(addr.Mask(mask))
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
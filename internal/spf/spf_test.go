@@ -0,0 +1,113 @@
+package spf_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit/internal/spf"
+)
+
+type fakeResolver struct {
+	txt   map[string][]string
+	hosts map[string][]string
+	mx    map[string][]string
+	fail  map[string]bool // names whose lookups fail with a temporary error
+}
+
+func (r *fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if r.fail[name] {
+		return nil, errors.New("timeout")
+	}
+	if txt, ok := r.txt[name]; ok {
+		return txt, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	var mxs []*net.MX
+	for _, h := range r.mx[name] {
+		mxs = append(mxs, &net.MX{Host: h, Pref: 10})
+	}
+	return mxs, nil
+}
+
+func TestCheck(t *testing.T) {
+	r := &fakeResolver{
+		txt: map[string][]string{
+			"example.com":        {"google-site-verification=abc", "v=spf1 ip4:192.0.2.0/24 include:_spf.example.net -all"},
+			"_spf.example.net":   {"v=spf1 ip6:2001:db8::/32 a:relay.example.net ~all"},
+			"soft.example":       {"v=spf1 ~all"},
+			"mx.example":         {"v=spf1 mx/24 -all"},
+			"redirect.example":   {"v=spf1 redirect=example.com"},
+			"double.example":     {"v=spf1 -all", "v=spf1 +all"},
+			"broken.example":     {"v=spf1 include:missing.example -all"},
+			"macro.example":      {"v=spf1 exists:%{i}._spf.%{d} -all"},
+			"bad.example":        {"v=spf1 ip4:not-an-ip -all"},
+			"temp.example":       {"v=spf1 include:flaky.example -all"},
+			"neutral.example":    {"v=spf1 ip4:203.0.113.1"},
+			"mixed-case.example": {"V=SPF1 +A -ALL"},
+		},
+		hosts: map[string][]string{
+			"relay.example.net":               {"198.51.100.7"},
+			"mail.mx.example":                 {"203.0.113.10"},
+			"198.51.100.9._spf.macro.example": {"127.0.0.2"},
+			"mixed-case.example":              {"198.51.100.1"},
+		},
+		mx:   map[string][]string{"mx.example": {"mail.mx.example"}},
+		fail: map[string]bool{"flaky.example": true},
+	}
+
+	tests := []struct {
+		ip     string
+		domain string
+		want   spf.Result
+	}{
+		{"192.0.2.55", "example.com", spf.Pass},
+		{"2001:db8::1", "example.com", spf.Pass},
+		{"198.51.100.7", "example.com", spf.Pass}, // a: inside an include
+		{"198.51.100.8", "example.com", spf.Fail},
+		{"192.0.2.1", "soft.example", spf.SoftFail},
+		{"203.0.113.99", "mx.example", spf.Pass},
+		{"203.0.114.1", "mx.example", spf.Fail},
+		{"192.0.2.1", "redirect.example", spf.Pass},
+		{"192.0.2.1", "nospf.example", spf.None},
+		{"192.0.2.1", "double.example", spf.PermError},
+		{"192.0.2.1", "broken.example", spf.PermError},
+		{"198.51.100.9", "macro.example", spf.Pass},
+		{"198.51.100.10", "macro.example", spf.Fail},
+		{"192.0.2.1", "bad.example", spf.PermError},
+		{"192.0.2.1", "temp.example", spf.TempError},
+		{"192.0.2.1", "neutral.example", spf.Neutral},
+		{"198.51.100.1", "mixed-case.example", spf.Pass},
+	}
+	for _, tt := range tests {
+		got, _ := spf.Check(context.Background(), r, net.ParseIP(tt.ip), tt.domain, "verify@"+tt.domain)
+		assert.Equal(t, tt.want, got, "%s on %s", tt.ip, tt.domain)
+	}
+}
+
+func TestCheck_LookupLimit(t *testing.T) {
+	r := &fakeResolver{txt: map[string][]string{}}
+	// A chain of includes longer than the 10-lookup limit
+	for i := range 12 {
+		r.txt[fmt.Sprintf("d%d.example", i)] = []string{fmt.Sprintf("v=spf1 include:d%d.example -all", i+1)}
+	}
+
+	got, err := spf.Check(context.Background(), r, net.ParseIP("192.0.2.1"), "d0.example", "verify@d0.example")
+
+	assert.Equal(t, spf.PermError, got)
+	assert.ErrorContains(t, err, "too many DNS lookups")
+}
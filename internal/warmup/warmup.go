@@ -0,0 +1,183 @@
+// Package warmup ramps up SMTP probe volume from a new identity: a daily
+// budget per provider that grows day by day, held back for a provider
+// that starts throttling.
+package warmup
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Config configures a Ramp.
+type Config struct {
+	Start           time.Time        // day 1 of the ramp
+	Days            int              // length of the ramp; later days are unlimited
+	Initial         int              // daily probes per key on day 1
+	Growth          float64          // budget factor from one day to the next
+	MaxThrottleRate float64          // throttled share of a key's probes that stops it for the day; 0 = never
+	MinSamples      int              // probes of a key before MaxThrottleRate applies
+	Location        *time.Location   // time zone of the day boundaries; default: UTC
+	Now             func() time.Time // injectable for testing
+}
+
+// Reasons returned by Reserve.
+const (
+	ReasonBudget    = "warm-up budget exhausted"
+	ReasonThrottled = "warm-up paused: provider throttling"
+)
+
+// Status is a key's warm-up state today.
+type Status struct {
+	Key       string `json:"key"`
+	Day       int    `json:"day"`
+	Budget    int    `json:"budget"` // 0 once the ramp is over
+	Probes    int    `json:"probes"`
+	Accepted  int    `json:"accepted"`
+	Throttled int    `json:"throttled"`
+	Paused    bool   `json:"paused,omitempty"` // stopped for the day by MaxThrottleRate
+}
+
+// Ramp decides whether a probe may run today. It is safe for concurrent
+// use.
+type Ramp struct {
+	cfg   Config
+	start time.Time // midnight of Start in Location
+
+	mu    sync.Mutex
+	today string
+	keys  map[string]*Status
+}
+
+// New creates a Ramp.
+func New(cfg Config) *Ramp {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	s := cfg.Start.In(cfg.Location)
+	return &Ramp{
+		cfg:   cfg,
+		start: time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, cfg.Location),
+		keys:  make(map[string]*Status),
+	}
+}
+
+// day returns the ramp day of t, 1-based; days before Start count as
+// day 1.
+func (r *Ramp) day(t time.Time) int {
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, r.cfg.Location)
+	// Round: days across a DST change are not 24h long
+	n := int(math.Round(d.Sub(r.start).Hours()/24)) + 1
+	return max(n, 1)
+}
+
+// Budget returns the daily probes per key on a ramp day; 0 (unlimited)
+// after the ramp.
+func (r *Ramp) Budget(day int) int {
+	if day > r.cfg.Days {
+		return 0
+	}
+	return int(math.Round(float64(r.cfg.Initial) * math.Pow(r.cfg.Growth, float64(day-1))))
+}
+
+// status returns key's state for today, starting a new day if needed.
+// r.mu must be held.
+func (r *Ramp) status(key string, now time.Time) *Status {
+	if today := now.Format(time.DateOnly); today != r.today {
+		r.today = today
+		clear(r.keys)
+	}
+	s := r.keys[key]
+	if s == nil {
+		day := r.day(now)
+		s = &Status{Key: key, Day: day, Budget: r.Budget(day)}
+		r.keys[key] = s
+	}
+	return s
+}
+
+// Reserve takes one probe from key's budget for today. If the probe may
+// not run it returns false, the reason and the next midnight.
+func (r *Ramp) Reserve(key string) (ok bool, reason string, resume time.Time) {
+	now := r.cfg.Now().In(r.cfg.Location)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.status(key, now)
+	if s.Budget == 0 {
+		s.Probes++
+		return true, "", time.Time{}
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, r.cfg.Location)
+	if s.Paused {
+		return false, ReasonThrottled, midnight
+	}
+	if s.Probes >= s.Budget {
+		return false, ReasonBudget, midnight
+	}
+	s.Probes++
+	return true, "", time.Time{}
+}
+
+// Record counts the outcome of a probe Reserve let through.
+func (r *Ramp) Record(key string, accepted, throttled bool) {
+	now := r.cfg.Now().In(r.cfg.Location)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.status(key, now)
+	if accepted {
+		s.Accepted++
+	}
+	if throttled {
+		s.Throttled++
+	}
+	if s.Budget > 0 && r.cfg.MaxThrottleRate > 0 && s.Probes >= r.cfg.MinSamples &&
+		float64(s.Throttled)/float64(max(s.Probes, 1)) > r.cfg.MaxThrottleRate {
+		s.Paused = true
+	}
+}
+
+// Counters returns Status and the day it is for (YYYY-MM-DD in Location).
+func (r *Ramp) Counters() (day string, keys []Status) {
+	return r.cfg.Now().In(r.cfg.Location).Format(time.DateOnly), r.Status()
+}
+
+// Restore loads counters returned by Counters, e.g. before a restart. It
+// ignores counters of another day than today and keeps the larger counts
+// of a key already probed; the ramp day and budget follow the current
+// configuration.
+func (r *Ramp) Restore(day string, keys []Status) {
+	now := r.cfg.Now().In(r.cfg.Location)
+	if day != now.Format(time.DateOnly) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range keys {
+		s := r.status(k.Key, now)
+		s.Probes = max(s.Probes, k.Probes)
+		s.Accepted = max(s.Accepted, k.Accepted)
+		s.Throttled = max(s.Throttled, k.Throttled)
+		s.Paused = s.Paused || k.Paused
+	}
+}
+
+// Status returns the state of every key probed today, by key.
+func (r *Ramp) Status() []Status {
+	now := r.cfg.Now().In(r.cfg.Location)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now.Format(time.DateOnly) != r.today {
+		return nil
+	}
+	out := make([]Status, 0, len(r.keys))
+	for _, s := range r.keys {
+		out = append(out, *s)
+	}
+	slices.SortFunc(out, func(a, b Status) int { return cmp.Compare(a.Key, b.Key) })
+	return out
+}
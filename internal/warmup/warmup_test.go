@@ -0,0 +1,112 @@
+package warmup_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit/internal/warmup"
+)
+
+func TestRamp_Budget(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := warmup.New(warmup.Config{
+		Start:   time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC),
+		Days:    3,
+		Initial: 2,
+		Growth:  2,
+		Now:     func() time.Time { return now },
+	})
+	assert.Equal(t, []int{2, 4, 8, 0}, []int{r.Budget(1), r.Budget(2), r.Budget(3), r.Budget(4)})
+
+	for range 2 {
+		ok, _, _ := r.Reserve("google")
+		require.True(t, ok)
+	}
+	ok, reason, resume := r.Reserve("google")
+	assert.False(t, ok)
+	assert.Equal(t, warmup.ReasonBudget, reason)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), resume)
+	ok, _, _ = r.Reserve("microsoft")
+	assert.True(t, ok, "budgets are per key")
+
+	now = now.AddDate(0, 0, 1)
+	assert.Empty(t, r.Status(), "a new day")
+	for range 4 {
+		ok, _, _ = r.Reserve("google")
+		require.True(t, ok)
+	}
+	ok, _, _ = r.Reserve("google")
+	assert.False(t, ok)
+
+	now = now.AddDate(0, 0, 2) // day 4: the ramp is over
+	for range 100 {
+		ok, _, _ = r.Reserve("google")
+		require.True(t, ok)
+	}
+	assert.Equal(t, []warmup.Status{{Key: "google", Day: 4, Probes: 100}}, r.Status())
+}
+
+func TestRamp_Throttling(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := warmup.New(warmup.Config{
+		Start:           now,
+		Days:            14,
+		Initial:         100,
+		Growth:          1.5,
+		MaxThrottleRate: 0.2,
+		MinSamples:      5,
+		Now:             func() time.Time { return now },
+	})
+	for i := range 5 {
+		ok, _, _ := r.Reserve("yahoo")
+		require.True(t, ok)
+		r.Record("yahoo", i < 3, i >= 3)
+	}
+	ok, reason, _ := r.Reserve("yahoo")
+	assert.False(t, ok)
+	assert.Equal(t, warmup.ReasonThrottled, reason)
+	assert.Equal(t, []warmup.Status{{Key: "yahoo", Day: 1, Budget: 100, Probes: 5, Accepted: 3, Throttled: 2, Paused: true}}, r.Status())
+
+	now = now.AddDate(0, 0, 1)
+	ok, _, _ = r.Reserve("yahoo")
+	assert.True(t, ok, "the pause lasts for the day")
+}
+
+func TestRamp_Restore(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	cfg := warmup.Config{
+		Start:           time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Days:            3,
+		Initial:         2,
+		Growth:          2,
+		MaxThrottleRate: 0.5,
+		Now:             func() time.Time { return now },
+	}
+	r := warmup.New(cfg)
+	for range 3 {
+		ok, _, _ := r.Reserve("google")
+		require.True(t, ok)
+		r.Record("google", true, false)
+	}
+	day, keys := r.Counters()
+	assert.Equal(t, "2026-03-02", day)
+
+	// A restarted process picks up today's counts
+	restarted := warmup.New(cfg)
+	restarted.Restore(day, keys)
+	assert.Equal(t, []warmup.Status{{Key: "google", Day: 2, Budget: 4, Probes: 3, Accepted: 3}}, restarted.Status())
+	ok, _, _ := restarted.Reserve("google")
+	assert.True(t, ok)
+	ok, reason, _ := restarted.Reserve("google")
+	assert.False(t, ok)
+	assert.Equal(t, warmup.ReasonBudget, reason)
+
+	// Yesterday's counts are ignored
+	now = now.AddDate(0, 0, 1)
+	fresh := warmup.New(cfg)
+	fresh.Restore(day, keys)
+	assert.Empty(t, fresh.Status())
+}
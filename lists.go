@@ -0,0 +1,191 @@
+package emailkit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/types"
+)
+
+// WithAllowlist marks domains (with their subdomains) and addresses as
+// known good, e.g. internal test addresses or partner domains with broken
+// MX records. A matching email — or domain, in ValidateDomain — is Valid
+// without running any level: its only check is a passing LevelAllowlist
+// check with CodeAllowlisted whose Details name the entry that matched.
+// The allowlist takes precedence over every level. Entries are
+// case-insensitive and IDNs match in Unicode or Punycode form; repeated
+// calls add to the list.
+func (v *Validator) WithAllowlist(domains, addresses []string) *Validator {
+	if v.allowlist == nil {
+		v.allowlist = newAccessList()
+	}
+	for _, d := range domains {
+		v.allowlist.domains[listDomain(d)] = true
+	}
+	for _, a := range addresses {
+		v.allowlist.addresses[listAddress(parse.NewEmail(a))] = true
+	}
+	return v
+}
+
+// WithBlocklist rejects known-abusive addresses before any level runs:
+// addresses are matched exactly (case-insensitively, IDNs in either form)
+// and patterns are regular expressions matched against the normalized
+// address — lowercase local part, "@", ASCII domain — e.g.
+// `@spam\.example$` or `^abuse-\d+@`. A match is not Valid and its only
+// check is a failing LevelBlocklist check with CodeBlocklisted whose
+// Details name the entry. The allowlist wins over the blocklist;
+// ValidateDomain ignores both address and pattern entries. An invalid
+// pattern is a configuration error (ErrInvalidBlocklist). Repeated calls
+// add to the list.
+func (v *Validator) WithBlocklist(addresses, patterns []string) *Validator {
+	if v.blocklist == nil {
+		v.blocklist = newAccessList()
+	}
+	for _, a := range addresses {
+		v.blocklist.addresses[listAddress(parse.NewEmail(a))] = true
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			v.err = fmt.Errorf("%w: %v", ErrInvalidBlocklist, err)
+			continue
+		}
+		v.blocklist.patterns = append(v.blocklist.patterns, re)
+	}
+	return v
+}
+
+// WithBlocklistHashes adds hashed addresses to the blocklist, for
+// suppression lists shared between companies without exposing the
+// addresses: each entry is the hex-encoded SHA-256 of a normalized address
+// as computed by HashAddress. Input is normalized and hashed the same way
+// before lookup. A match fails like WithBlocklist, with Details
+// "blocklisted by hash". An entry that is not 64 hex digits is a
+// configuration error (ErrInvalidBlocklist). Repeated calls add to the
+// list.
+func (v *Validator) WithBlocklistHashes(hashes []string) *Validator {
+	if v.blocklist == nil {
+		v.blocklist = newAccessList()
+	}
+	for _, h := range hashes {
+		var sum [sha256.Size]byte
+		h = strings.TrimSpace(h)
+		if len(h) != hex.EncodedLen(sha256.Size) {
+			v.err = fmt.Errorf("%w: %q is not a hex SHA-256 hash", ErrInvalidBlocklist, h)
+			continue
+		}
+		if _, err := hex.Decode(sum[:], []byte(h)); err != nil {
+			v.err = fmt.Errorf("%w: %q is not a hex SHA-256 hash", ErrInvalidBlocklist, h)
+			continue
+		}
+		v.blocklist.hashes[sum] = true
+	}
+	return v
+}
+
+// HashAddress returns the hex-encoded SHA-256 of email in the normalized
+// form WithBlocklistHashes compares: lowercase local part, "@", lowercase
+// ASCII (Punycode) domain, surrounding whitespace trimmed. For ASCII
+// addresses that is the digest of the trimmed, lowercased address, the
+// usual convention for shared suppression lists.
+func HashAddress(email string) string {
+	sum := sha256.Sum256([]byte(listAddress(parse.NewEmail(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+// accessList holds normalized domains and addresses, patterns, and
+// SHA-256 hashes of normalized addresses.
+type accessList struct {
+	domains   map[string]bool
+	addresses map[string]bool
+	patterns  []*regexp.Regexp
+	hashes    map[[sha256.Size]byte]bool
+}
+
+func newAccessList() *accessList {
+	return &accessList{
+		domains:   make(map[string]bool),
+		addresses: make(map[string]bool),
+		hashes:    make(map[[sha256.Size]byte]bool),
+	}
+}
+
+// match describes the entry email matches, e.g. "allowlisted domain
+// example.com" for prefix "allowlisted". A nil list matches nothing.
+func (l *accessList) match(email Email, prefix string) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+	if email.Local != "" || !email.Valid {
+		addr := listAddress(email)
+		if l.addresses[addr] {
+			return prefix + " address", true
+		}
+		for _, re := range l.patterns {
+			if re.MatchString(addr) {
+				return prefix + " by pattern " + re.String(), true
+			}
+		}
+		if len(l.hashes) > 0 && l.hashes[sha256.Sum256([]byte(addr))] {
+			return prefix + " by hash", true
+		}
+	}
+	if d, ok := l.domain(email); ok {
+		return prefix + " domain " + d, true
+	}
+	return "", false
+}
+
+// listCheck runs the allowlist and the blocklist on email. ok is false if
+// neither matched.
+func (v *Validator) listCheck(email Email) (cr CheckResult, ok bool) {
+	if details, ok := v.allowlist.match(email, "allowlisted"); ok {
+		return CheckResult{Level: types.LevelAllowlist, Passed: true, Details: details, Code: types.CodeAllowlisted}, true
+	}
+	if details, ok := v.blocklist.match(email, "blocklisted"); ok {
+		return CheckResult{Level: types.LevelBlocklist, Passed: false, Details: details, Code: types.CodeBlocklisted}, true
+	}
+	return CheckResult{}, false
+}
+
+// domain returns the listed domain that email's domain equals or is a
+// subdomain of.
+func (l *accessList) domain(email Email) (string, bool) {
+	if !email.Valid || len(l.domains) == 0 {
+		return "", false
+	}
+	d := email.Domain
+	for {
+		if l.domains[d] {
+			return d, true
+		}
+		i := strings.IndexByte(d, '.')
+		if i < 0 {
+			return "", false
+		}
+		d = d[i+1:]
+	}
+}
+
+// listDomain normalizes a list entry to the ASCII form of parse.Email.Domain.
+func listDomain(d string) string {
+	if parsed := parse.NewDomain(d); parsed.Valid {
+		return parsed.Domain
+	}
+	return strings.ToLower(strings.TrimSpace(d))
+}
+
+// listAddress normalizes an address for list lookups: the local part is
+// compared case-insensitively, the domain in ASCII form. Unparsable input
+// is compared as is, lowercased.
+func listAddress(email Email) string {
+	if !email.Valid {
+		return strings.ToLower(email.Raw)
+	}
+	return strings.ToLower(email.Local) + "@" + email.Domain
+}
@@ -0,0 +1,147 @@
+package emailkit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+func TestWithAllowlist(t *testing.T) {
+	resolver := &stubResolver{} // every domain is NXDOMAIN
+	v := emailkit.New().WithResolver(resolver).WithDNS().WithDomain().
+		WithAllowlist([]string{"Partner.example", "bücher.example"}, []string{"QA+Test@Mailinator.com", "not an address"})
+	ctx := context.Background()
+
+	tests := []struct {
+		email   string
+		details string
+	}{
+		{"qa+test@mailinator.com", "allowlisted address"},
+		{"QA+TEST@MAILINATOR.COM", "allowlisted address"},
+		{"alice@partner.example", "allowlisted domain partner.example"},
+		{"bob@mx.eu.PARTNER.example", "allowlisted domain partner.example"},
+		{"carol@xn--bcher-kva.example", "allowlisted domain xn--bcher-kva.example"},
+		{"not an address", "allowlisted address"},
+	}
+	for _, tt := range tests {
+		res, err := v.Validate(ctx, tt.email)
+		require.NoError(t, err)
+		assert.True(t, res.Valid, tt.email)
+		require.Len(t, res.Checks, 1, tt.email)
+		assert.Equal(t, emailkit.LevelAllowlist, res.Checks[0].Level)
+		assert.Equal(t, emailkit.CodeAllowlisted, res.Checks[0].Code)
+		assert.Equal(t, tt.details, res.Checks[0].Details, tt.email)
+		assert.Equal(t, emailkit.SeverityInfo, res.Severity())
+	}
+
+	// Not listed: the pipeline runs
+	res, err := v.Validate(ctx, "other@mailinator.com")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	res, err = v.Validate(ctx, "alice@notpartner.example")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+
+	// Domain-only validation matches domains only
+	res, err = v.ValidateDomain(ctx, "partner.example")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+	assert.Equal(t, emailkit.LevelAllowlist, res.Checks[0].Level)
+	res, err = v.ValidateDomain(ctx, "mailinator.com")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+}
+
+func TestWithAllowlist_Prefilter(t *testing.T) {
+	v := emailkit.New().WithDomain().WithAllowlist(nil, []string{"qa@mailinator.com"})
+	results, err := v.ValidateMany(context.Background(), []string{"qa@mailinator.com", "x@mailinator.com"}, emailkit.ConcurrencyOptions{Prefilter: true})
+	require.NoError(t, err)
+	assert.True(t, results[0].Valid, "the prefilter honors the allowlist")
+	assert.False(t, results[1].Valid)
+}
+
+func TestWithBlocklist(t *testing.T) {
+	v := emailkit.New().WithDomain().
+		WithBlocklist([]string{"Abuser@Example.com"}, []string{`@spam\.example$`, `^abuse-\d+@`}).
+		WithAllowlist(nil, []string{"abuse-1@example.com"})
+	ctx := context.Background()
+
+	tests := []struct {
+		email   string
+		details string
+	}{
+		{"abuser@EXAMPLE.com", "blocklisted address"},
+		{"anyone@spam.example", `blocklisted by pattern @spam\.example$`},
+		{"abuse-42@example.com", `blocklisted by pattern ^abuse-\d+@`},
+	}
+	for _, tt := range tests {
+		res, err := v.Validate(ctx, tt.email)
+		require.NoError(t, err)
+		assert.False(t, res.Valid, tt.email)
+		require.Len(t, res.Checks, 1, tt.email)
+		assert.Equal(t, emailkit.LevelBlocklist, res.Checks[0].Level)
+		assert.Equal(t, emailkit.CodeBlocklisted, res.Checks[0].Code)
+		assert.Equal(t, tt.details, res.Checks[0].Details)
+	}
+
+	res, err := v.Validate(ctx, "abuse-1@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid, "the allowlist wins")
+
+	res, err = v.Validate(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+	assert.Len(t, res.Checks, 2)
+
+	res, err = v.ValidateDomain(ctx, "spam.example")
+	require.NoError(t, err)
+	assert.True(t, res.Valid, "address patterns don't apply to domains")
+
+	results, err := v.ValidateMany(ctx, []string{"abuser@example.com", "alice@example.com"}, emailkit.ConcurrencyOptions{Prefilter: true})
+	require.NoError(t, err)
+	assert.Equal(t, emailkit.LevelBlocklist, results[0].Checks[0].Level, "rejected by the prefilter")
+	assert.True(t, results[1].Valid)
+}
+
+func TestWithBlocklist_InvalidPattern(t *testing.T) {
+	v := emailkit.New().WithBlocklist(nil, []string{"(unclosed"})
+	_, err := v.Validate(context.Background(), "a@example.com")
+	assert.ErrorIs(t, err, emailkit.ErrInvalidBlocklist)
+}
+
+func TestWithBlocklistHashes(t *testing.T) {
+	// sha256("abuser@example.com"), as shared by a partner
+	const hash = "aaf597306dff99dd6beefc4317c0f829ed499e69a39e778cd263c5ebae23c921"
+	assert.Equal(t, hash, emailkit.HashAddress("abuser@example.com"))
+	assert.Equal(t, hash, emailkit.HashAddress("  Abuser@EXAMPLE.com "))
+	assert.Equal(t, emailkit.HashAddress("jo@xn--bcher-kva.example"), emailkit.HashAddress("jo@bücher.example"))
+
+	shared := []string{hash, strings.ToUpper(emailkit.HashAddress("x@y.example"))}
+	v := emailkit.New().WithBlocklistHashes(shared)
+	ctx := context.Background()
+
+	res, err := v.Validate(ctx, "ABUSER@example.com")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	assert.Equal(t, emailkit.CodeBlocklisted, res.Checks[0].Code)
+	assert.Equal(t, "blocklisted by hash", res.Checks[0].Details)
+
+	res, err = v.Validate(ctx, "x@y.example")
+	require.NoError(t, err)
+	assert.False(t, res.Valid, "upper-case hex is accepted")
+
+	res, err = v.Validate(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+
+	for _, bad := range []string{hash[:10], strings.Repeat("zz", 32)} {
+		_, err := emailkit.New().WithBlocklistHashes([]string{bad}).Validate(ctx, "a@example.com")
+		assert.ErrorIs(t, err, emailkit.ErrInvalidBlocklist, bad)
+		assert.ErrorContains(t, err, "emailkit: invalid blocklist entry: ", bad)
+	}
+}
@@ -0,0 +1,139 @@
+package emailkit
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+
+	"github.com/optimode/emailkit/internal/parse"
+)
+
+// Locale formats the user-facing strings emailkit produces — typo
+// suggestions, explanations, addresses and percentages — for a reader.
+// The zero value is English with internationalized domains in Unicode.
+type Locale struct {
+	// Language is the language of translated messages, one of Languages.
+	// Unsupported languages fall back to English. Default: "en"
+	Language string
+	// ASCII renders internationalized domains in their Punycode form
+	// ("xn--mnchen-3ya.de"), for support staff, logs and systems that
+	// don't handle Unicode. Default: false (Unicode, "münchen.de", for
+	// end users)
+	ASCII bool
+}
+
+// localeMessages holds the translated messages of a language.
+type localeMessages struct {
+	suggestion string // "Did you mean %s?"
+	percent    string // "%d%%"
+}
+
+// nbsp is the no-break space that keeps a number with its sign.
+const nbsp = "\u00a0"
+
+var locales = map[string]localeMessages{
+	"de": {suggestion: "Meinten Sie %s?", percent: "%d" + nbsp + "%%"},
+	"en": {suggestion: "Did you mean %s?", percent: "%d%%"},
+	"es": {suggestion: "¿Quisiste decir %s?", percent: "%d" + nbsp + "%%"},
+	"fr": {suggestion: "Vouliez-vous dire %s" + nbsp + "?", percent: "%d" + nbsp + "%%"},
+	"it": {suggestion: "Intendevi %s?", percent: "%d%%"},
+	"nl": {suggestion: "Bedoelde u %s?", percent: "%d%%"},
+	"pt": {suggestion: "Você quis dizer %s?", percent: "%d%%"},
+}
+
+// Languages lists the languages Locale translates messages into, sorted.
+func Languages() []string {
+	out := make([]string, 0, len(locales))
+	for lang := range locales {
+		out = append(out, lang)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// LocaleFromAcceptLanguage returns the Locale for an HTTP Accept-Language
+// header, e.g. "fr-CH, fr;q=0.9, en;q=0.8": the supported language with
+// the highest weight, matched on the primary subtag. It falls back to
+// English when none is supported or the header is empty or malformed.
+func LocaleFromAcceptLanguage(header string) Locale {
+	best, bestQ := "en", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if _, ok := locales[lang]; ok && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return Locale{Language: best}
+}
+
+func (l Locale) messages() localeMessages {
+	if m, ok := locales[strings.ToLower(l.Language)]; ok {
+		return m
+	}
+	return locales["en"]
+}
+
+// Address renders an address, or a bare domain, with its domain in
+// Unicode or, with ASCII, in Punycode. Input that doesn't parse (e.g. a
+// privacy-mode hash) is returned unchanged.
+func (l Locale) Address(email string) string {
+	if !strings.Contains(email, "@") {
+		return l.domain(email)
+	}
+	e := parse.NewEmail(email)
+	if !e.Valid {
+		return email
+	}
+	if l.ASCII {
+		return e.Local + "@" + e.Domain
+	}
+	return e.Local + "@" + e.DomainUnicode
+}
+
+// domain renders a bare domain.
+func (l Locale) domain(domain string) string {
+	conv := idna.Display.ToUnicode
+	if l.ASCII {
+		conv = idna.Lookup.ToASCII
+	}
+	out, err := conv(domain)
+	if err != nil {
+		return domain
+	}
+	return out
+}
+
+// Percent renders a probability (0..1) as a whole percentage, e.g. "97%"
+// in English and "97 %" in German.
+func (l Locale) Percent(p float64) string {
+	return fmt.Sprintf(l.messages().percent, int(p*100+0.5))
+}
+
+// Suggestion returns the "did you mean" prompt for a ValidateWithSuggestion
+// result, e.g. "Did you mean jane@gmail.com?", or "" if there is no
+// suggestion or the suggested address did not validate.
+func (l Locale) Suggestion(sr SuggestionResult) string {
+	if sr.Suggestion == "" || (sr.Suggested != nil && !sr.Suggested.Valid) {
+		return ""
+	}
+	return fmt.Sprintf(l.messages().suggestion, l.Address(sr.Suggestion))
+}
+
+// Explanation is Result.Explanation with the addresses rendered by
+// Address and the deliverability probability by Percent. The sentences
+// themselves are in English.
+func (l Locale) Explanation(r Result) string {
+	return r.explanation(l.Address, l.Percent)
+}
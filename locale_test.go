@@ -0,0 +1,87 @@
+package emailkit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestLocaleFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"de-DE", "de"},
+		{"fr-CH, fr;q=0.9, en;q=0.8", "fr"},
+		{"ja, pt-BR;q=0.7, en;q=0.5", "pt"},
+		{"en;q=0.2, NL;q=0.9", "nl"},
+		{"es;q=0, it;q=0.1", "it"},
+		{"de;q=x, es", "es"},
+		{"ja, zh", "en"},
+		{"*", "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, emailkit.LocaleFromAcceptLanguage(tt.header).Language)
+		})
+	}
+}
+
+func TestLocale_Address(t *testing.T) {
+	unicode := emailkit.Locale{}
+	ascii := emailkit.Locale{ASCII: true}
+
+	assert.Equal(t, "jan@münchen.de", unicode.Address("jan@xn--mnchen-3ya.de"))
+	assert.Equal(t, "jan@münchen.de", unicode.Address("jan@münchen.de"))
+	assert.Equal(t, "jan@xn--mnchen-3ya.de", ascii.Address("jan@münchen.de"))
+	assert.Equal(t, "münchen.de", unicode.Address("xn--mnchen-3ya.de"))
+	assert.Equal(t, "xn--mnchen-3ya.de", ascii.Address("münchen.de"))
+	assert.Equal(t, "jane@gmail.com", ascii.Address("jane@gmail.com"))
+	assert.Equal(t, "not an address@", unicode.Address("not an address@"))
+}
+
+func TestLocale_Percent(t *testing.T) {
+	assert.Equal(t, "97%", emailkit.Locale{}.Percent(0.97))
+	assert.Equal(t, "97 %", emailkit.Locale{Language: "de"}.Percent(0.968))
+	assert.Equal(t, "60%", emailkit.Locale{Language: "xx"}.Percent(0.6))
+}
+
+func TestLocale_Suggestion(t *testing.T) {
+	sr := emailkit.SuggestionResult{
+		Suggestion: "jan@xn--mnchen-3ya.de",
+		Suggested:  &emailkit.Result{Valid: true},
+	}
+	assert.Equal(t, "Did you mean jan@münchen.de?", emailkit.Locale{}.Suggestion(sr))
+	assert.Equal(t, "Meinten Sie jan@xn--mnchen-3ya.de?", emailkit.Locale{Language: "DE", ASCII: true}.Suggestion(sr))
+	assert.Equal(t, "¿Quisiste decir jan@münchen.de?", emailkit.LocaleFromAcceptLanguage("es-MX").Suggestion(sr))
+	assert.Equal(t, "Vouliez-vous dire jan@münchen.de ?", emailkit.Locale{Language: "fr"}.Suggestion(sr))
+
+	sr.Suggested.Valid = false
+	assert.Empty(t, emailkit.Locale{}.Suggestion(sr), "the suggestion did not validate")
+	assert.Empty(t, emailkit.Locale{}.Suggestion(emailkit.SuggestionResult{}))
+}
+
+func TestLocale_Explanation(t *testing.T) {
+	r := emailkit.Result{
+		Email:                     "jan@xn--mnchn-kva.de",
+		Valid:                     true,
+		DeliverabilityProbability: 0.85,
+		Checks: []emailkit.CheckResult{
+			{Level: emailkit.LevelSyntax, Passed: true},
+			{Level: emailkit.LevelDomain, Passed: true, Suggestion: "xn--mnchen-3ya.de"},
+		},
+	}
+	assert.Equal(t,
+		"jan@münchn.de is valid: it passed the syntax and domain checks. Note that it may be a typo for münchen.de. Estimated deliverability: 85 %.",
+		emailkit.Locale{Language: "de"}.Explanation(r))
+	assert.Equal(t,
+		"jan@xn--mnchn-kva.de is valid: it passed the syntax and domain checks. Note that it may be a typo for xn--mnchen-3ya.de. Estimated deliverability: 85%.",
+		r.Explanation())
+}
+
+func TestLanguages(t *testing.T) {
+	assert.Equal(t, []string{"de", "en", "es", "fr", "it", "nl", "pt"}, emailkit.Languages())
+}
@@ -0,0 +1,404 @@
+package emailkit
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/optimode/emailkit/internal/smtppool"
+)
+
+// TenantProfile configures one tenant of a Manager.
+type TenantProfile struct {
+	// Configure adds the tenant's levels to a fresh validator, e.g.
+	//
+	//	func(v *Validator) *Validator { return v.WithDNS().WithSMTP(opts) }
+	//
+	// Default: nil (syntax checking only)
+	Configure func(v *Validator) *Validator
+	// MaxValidationsPerDay caps Validate calls and ValidateMany entries.
+	// Once spent, they fail with ErrQuotaExceeded. Default: 0 (unlimited)
+	MaxValidationsPerDay int
+	// MaxProbesPerDay caps SMTP probes. Once spent, the SMTP level reports
+	// CodeDeferred with the reset time in Meta["resume_at"].
+	// Default: 0 (unlimited)
+	MaxProbesPerDay int
+}
+
+// ManagerOptions configures a Manager.
+type ManagerOptions struct {
+	// Location is the time zone in which daily quotas reset. Default: UTC
+	Location *time.Location
+}
+
+// TenantStats are a tenant's counters since it was added.
+type TenantStats struct {
+	Validations     int64 // validations run
+	Valid           int64
+	Invalid         int64
+	QuotaRejected   int64 // validations refused by MaxValidationsPerDay
+	SMTPProbes      int64 // SMTP probes that passed the quota
+	ProbesDeferred  int64 // SMTP probes deferred by MaxProbesPerDay
+	ValidationsLeft int   // today; -1 if unlimited
+	ProbesLeft      int   // today; -1 if unlimited
+}
+
+// Manager runs named validator profiles for multiple tenants, e.g. in a
+// SaaS product exposing validation to its own customers. Each tenant has
+// its own pipeline, caches, daily quotas and stats; tenants with the same
+// HELO name and SMTP transport settings share pooled connections.
+// A Manager is safe for concurrent use. Call Close when done.
+type Manager struct {
+	loc     *time.Location
+	pools   *poolSet
+	mu      sync.RWMutex
+	tenants map[string]*tenant
+}
+
+// tenant is one Manager profile.
+type tenant struct {
+	v           *Validator
+	validations *dailyQuota
+	probes      *dailyQuota
+	validated   atomic.Int64
+	valid       atomic.Int64
+	invalid     atomic.Int64
+}
+
+// NewManager creates an empty Manager.
+func NewManager(opts ...ManagerOptions) *Manager {
+	var o ManagerOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Location == nil {
+		o.Location = time.UTC
+	}
+	return &Manager{
+		loc:     o.Location,
+		pools:   &poolSet{pools: make(map[poolKey]*smtppool.Pool)},
+		tenants: make(map[string]*tenant),
+	}
+}
+
+// AddTenant builds the tenant's validator from p and registers it under
+// name, replacing (and closing) a previous tenant of that name. Stats
+// start from zero. It returns the validator's configuration error, if
+// any, and does not register the tenant then.
+func (m *Manager) AddTenant(name string, p TenantProfile) error {
+	t := &tenant{
+		validations: newDailyQuota(p.MaxValidationsPerDay, m.loc),
+		probes:      newDailyQuota(p.MaxProbesPerDay, m.loc),
+	}
+	v := New()
+	v.pools = m.pools
+	v.probeQuota = t.probes
+	if p.Configure != nil {
+		v = p.Configure(v)
+	}
+	if v.err != nil {
+		_ = v.Close()
+		return fmt.Errorf("emailkit: tenant %q: %w", name, v.err)
+	}
+	t.v = v
+
+	m.mu.Lock()
+	old := m.tenants[name]
+	m.tenants[name] = t
+	m.mu.Unlock()
+	if old != nil {
+		_ = old.v.Close()
+	}
+	return nil
+}
+
+// RemoveTenant unregisters and closes a tenant. Unknown names are ignored.
+func (m *Manager) RemoveTenant(name string) {
+	m.mu.Lock()
+	t := m.tenants[name]
+	delete(m.tenants, name)
+	m.mu.Unlock()
+	if t != nil {
+		_ = t.v.Close()
+	}
+}
+
+// Tenant returns the tenant's validator, e.g. for ExportState or
+// CheckIdentities. Validating through it bypasses the validation quota
+// and the validation stats.
+func (m *Manager) Tenant(name string) (*Validator, bool) {
+	t, err := m.tenant(name)
+	if err != nil {
+		return nil, false
+	}
+	return t.v, true
+}
+
+// Validate validates email with the tenant's profile. It returns
+// ErrUnknownTenant for unregistered tenants and ErrQuotaExceeded once the
+// tenant's daily validations are spent.
+func (m *Manager) Validate(ctx context.Context, tenantName, email string) (Result, error) {
+	t, err := m.tenant(tenantName)
+	if err != nil {
+		return Result{}, err
+	}
+	if ok, resume := t.validations.Reserve(); !ok {
+		return Result{}, quotaError(tenantName, resume)
+	}
+	res, err := t.v.Validate(ctx, email)
+	if err == nil {
+		t.count(res)
+	}
+	return res, err
+}
+
+// ValidateMany is Validator.ValidateMany with the tenant's profile. Entries
+// beyond the tenant's daily validations get an error Result (see
+// Validator.ValidateMany) and the returned error wraps ErrQuotaExceeded.
+func (m *Manager) ValidateMany(ctx context.Context, tenantName string, emails []string, opts ...ConcurrencyOptions) ([]Result, error) {
+	t, err := m.tenant(tenantName)
+	if err != nil {
+		return nil, err
+	}
+	allowed := len(emails)
+	var quotaErr error
+	for i := range emails {
+		if ok, resume := t.validations.Reserve(); !ok {
+			allowed, quotaErr = i, quotaError(tenantName, resume)
+			break
+		}
+	}
+
+	var o ConcurrencyOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	onBatch := o.OnBatch
+	var stopped bool // onBatch returned an error
+	if onBatch != nil {
+		o.OnBatch = func(offset int, results []Result) error {
+			for _, r := range results {
+				t.count(r)
+			}
+			err := onBatch(offset, results)
+			stopped = err != nil
+			return err
+		}
+	}
+
+	results, err := t.v.ValidateMany(ctx, emails[:allowed], o)
+	if onBatch == nil {
+		for _, r := range results {
+			t.count(r)
+		}
+	}
+	if onBatch == nil {
+		for _, email := range emails[allowed:] {
+			results = append(results, t.v.errorResult(email, quotaErr))
+		}
+	} else if !stopped {
+		// Flush the rejected entries in chunks too
+		size := o.MaxBatch
+		if size <= 0 {
+			size = len(emails)
+		}
+		for off := allowed; off < len(emails); off += size {
+			chunk := emails[off:min(off+size, len(emails))]
+			rejected := make([]Result, len(chunk))
+			for i, email := range chunk {
+				rejected[i] = t.v.errorResult(email, quotaErr)
+			}
+			if cbErr := onBatch(off, rejected); cbErr != nil {
+				return nil, cbErr
+			}
+		}
+	}
+	if err == nil {
+		err = quotaErr
+	}
+	return results, err
+}
+
+// Stats returns the tenant's counters.
+func (m *Manager) Stats(tenantName string) (TenantStats, error) {
+	t, err := m.tenant(tenantName)
+	if err != nil {
+		return TenantStats{}, err
+	}
+	_, vRejected, vLeft := t.validations.counts()
+	pUsed, pRejected, pLeft := t.probes.counts()
+	return TenantStats{
+		Validations:     t.validated.Load(),
+		Valid:           t.valid.Load(),
+		Invalid:         t.invalid.Load(),
+		QuotaRejected:   vRejected,
+		SMTPProbes:      pUsed,
+		ProbesDeferred:  pRejected,
+		ValidationsLeft: vLeft,
+		ProbesLeft:      pLeft,
+	}, nil
+}
+
+// Tenants returns the registered tenant names in no particular order.
+func (m *Manager) Tenants() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.tenants))
+	for name := range m.tenants {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Close closes every tenant and the shared SMTP connection pools.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	tenants := m.tenants
+	m.tenants = make(map[string]*tenant)
+	m.mu.Unlock()
+
+	for _, t := range tenants {
+		_ = t.v.Close()
+	}
+	return m.pools.close()
+}
+
+func (m *Manager) tenant(name string) (*tenant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.tenants[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTenant, name)
+	}
+	return t, nil
+}
+
+func (t *tenant) count(r Result) {
+	t.validated.Add(1)
+	if r.Valid {
+		t.valid.Add(1)
+	} else {
+		t.invalid.Add(1)
+	}
+}
+
+func quotaError(tenant string, resume time.Time) error {
+	return fmt.Errorf("%w: tenant %q daily validations spent until %s",
+		ErrQuotaExceeded, tenant, resume.UTC().Format(time.RFC3339))
+}
+
+// dailyQuota counts reservations against a limit that resets at midnight
+// in loc. A limit <= 0 is unlimited. It implements check.ProbeQuota.
+type dailyQuota struct {
+	limit    int
+	loc      *time.Location
+	now      func() time.Time
+	mu       sync.Mutex
+	day      string
+	used     int   // today
+	total    int64 // since creation
+	rejected int64
+}
+
+func newDailyQuota(limit int, loc *time.Location) *dailyQuota {
+	return &dailyQuota{limit: limit, loc: loc, now: time.Now}
+}
+
+// Reserve takes one unit. If none is left it returns false and the time
+// the quota resets.
+func (q *dailyQuota) Reserve() (bool, time.Time) {
+	now := q.now().In(q.loc)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.roll(now)
+	if q.limit > 0 && q.used >= q.limit {
+		q.rejected++
+		return false, time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, q.loc)
+	}
+	q.used++
+	q.total++
+	return true, time.Time{}
+}
+
+// counts returns the totals and what is left today (-1 if unlimited).
+func (q *dailyQuota) counts() (total, rejected int64, left int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.roll(q.now().In(q.loc))
+	left = -1
+	if q.limit > 0 {
+		left = q.limit - q.used
+	}
+	return q.total, q.rejected, left
+}
+
+// roll resets today's usage on a new day. q.mu must be held.
+func (q *dailyQuota) roll(now time.Time) {
+	if day := now.Format(time.DateOnly); day != q.day {
+		q.day = day
+		q.used = 0
+	}
+}
+
+// poolKey identifies SMTP pools that can share connections: EHLO is sent
+// once per connection, so only validators with the same HELO name and
+// transport settings, TLS included, can reuse each other's.
+type poolKey struct {
+	helo            string
+	port            string
+	connectTimeout  time.Duration
+	commandTimeout  time.Duration
+	maxConnsPerHost int
+	adaptive        bool
+	dialer          smtppool.DialerOptions // ProxyDialer cleared, see proxyDialer
+	proxyDialer     any
+	dialContext     *byte // unique per pool: funcs can't be compared
+	tlsMode         smtppool.TLSMode
+	tlsConfig       *tls.Config
+}
+
+// poolSet holds the SMTP pools shared by a Manager's tenants.
+type poolSet struct {
+	mu    sync.Mutex
+	pools map[poolKey]*smtppool.Pool
+}
+
+// get returns the shared pool for cfg, creating it on first use.
+func (s *poolSet) get(cfg smtppool.Config) *smtppool.Pool {
+	dialer := cfg.Dialer
+	dialer.ProxyDialer = nil
+	key := poolKey{cfg.HeloDomain, cfg.Port, cfg.ConnectTimeout, cfg.CommandTimeout, cfg.MaxConnsPerHost, cfg.Adaptive, dialer, nil, nil, cfg.TLSMode, cfg.TLSConfig}
+	// A ProxyDialer of a type that can't be a map key gets a pool of its own
+	if pd := cfg.Dialer.ProxyDialer; pd != nil && reflect.TypeOf(pd).Comparable() {
+		key.proxyDialer = pd
+	} else if pd != nil {
+		key.proxyDialer = new(byte)
+	}
+	if cfg.DialContext != nil {
+		key.dialContext = new(byte)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pools[key]
+	if !ok {
+		p = smtppool.New(cfg)
+		s.pools[key] = p
+	}
+	return p
+}
+
+func (s *poolSet) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for key, p := range s.pools {
+		errs = append(errs, p.Close())
+		delete(s.pools, key)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,137 @@
+package emailkit_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestManager_UnknownTenant(t *testing.T) {
+	m := emailkit.NewManager()
+	defer func() { _ = m.Close() }()
+
+	_, err := m.Validate(context.Background(), "acme", "user@example.com")
+	assert.ErrorIs(t, err, emailkit.ErrUnknownTenant)
+	_, err = m.Stats("acme")
+	assert.ErrorIs(t, err, emailkit.ErrUnknownTenant)
+	_, ok := m.Tenant("acme")
+	assert.False(t, ok)
+}
+
+func TestManager_ProfilesAreIsolated(t *testing.T) {
+	m := emailkit.NewManager()
+	defer func() { _ = m.Close() }()
+
+	require.NoError(t, m.AddTenant("syntax-only", emailkit.TenantProfile{}))
+	require.NoError(t, m.AddTenant("dns", emailkit.TenantProfile{
+		Configure: func(v *emailkit.Validator) *emailkit.Validator {
+			return v.WithResolver(&stubResolver{}).WithDNS()
+		},
+	}))
+	assert.ElementsMatch(t, []string{"syntax-only", "dns"}, m.Tenants())
+
+	res, err := m.Validate(context.Background(), "syntax-only", "user@nomx.example")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+
+	res, err = m.Validate(context.Background(), "dns", "user@nomx.example")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+
+	m.RemoveTenant("dns")
+	assert.Equal(t, []string{"syntax-only"}, m.Tenants())
+}
+
+func TestManager_ConfigurationError(t *testing.T) {
+	m := emailkit.NewManager()
+	defer func() { _ = m.Close() }()
+
+	err := m.AddTenant("broken", emailkit.TenantProfile{
+		Configure: func(v *emailkit.Validator) *emailkit.Validator {
+			return v.WithSMTP(emailkit.SMTPOptions{})
+		},
+	})
+	assert.ErrorIs(t, err, emailkit.ErrInvalidSMTPOptions)
+	assert.Empty(t, m.Tenants())
+}
+
+func TestManager_ValidationQuota(t *testing.T) {
+	m := emailkit.NewManager()
+	defer func() { _ = m.Close() }()
+	require.NoError(t, m.AddTenant("acme", emailkit.TenantProfile{MaxValidationsPerDay: 4}))
+	ctx := context.Background()
+
+	_, err := m.Validate(ctx, "acme", "user@example.com")
+	require.NoError(t, err)
+	_, err = m.Validate(ctx, "acme", "not-an-email")
+	require.NoError(t, err)
+
+	results, err := m.ValidateMany(ctx, "acme", []string{"a@example.com", "b@example.com", "c@example.com"})
+	assert.ErrorIs(t, err, emailkit.ErrQuotaExceeded)
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Valid)
+	assert.True(t, results[1].Valid)
+	assert.Equal(t, "c@example.com", results[2].Email)
+	assert.Equal(t, emailkit.CodeError, results[2].Checks[0].Code)
+
+	_, err = m.Validate(ctx, "acme", "user@example.com")
+	assert.ErrorIs(t, err, emailkit.ErrQuotaExceeded)
+
+	// Quota-rejected entries are flushed through OnBatch too
+	var flushed []string
+	_, err = m.ValidateMany(ctx, "acme", []string{"d@example.com", "e@example.com"}, emailkit.ConcurrencyOptions{
+		OnBatch: func(_ int, rs []emailkit.Result) error {
+			for _, r := range rs {
+				flushed = append(flushed, r.Email)
+			}
+			return nil
+		},
+	})
+	assert.ErrorIs(t, err, emailkit.ErrQuotaExceeded)
+	assert.Equal(t, []string{"d@example.com", "e@example.com"}, flushed)
+
+	stats, err := m.Stats("acme")
+	require.NoError(t, err)
+	assert.Equal(t, emailkit.TenantStats{
+		Validations:     4,
+		Valid:           3,
+		Invalid:         1,
+		QuotaRejected:   3,
+		ValidationsLeft: 0,
+		ProbesLeft:      -1,
+	}, stats)
+}
+
+func TestManager_ProbeQuotaReported(t *testing.T) {
+	m := emailkit.NewManager()
+	defer func() { _ = m.Close() }()
+	require.NoError(t, m.AddTenant("acme", emailkit.TenantProfile{
+		MaxProbesPerDay: 100,
+		Configure: func(v *emailkit.Validator) *emailkit.Validator {
+			return v.WithResolver(&stubResolver{
+				mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+			}).WithSMTP(emailkit.SMTPOptions{
+				HeloDomain: "myapp.com",
+				MailFrom:   "verify@myapp.com",
+				PerDomain: func(string) emailkit.DomainSMTPPolicy {
+					return emailkit.DomainSMTPPolicy{Strategy: emailkit.StrategySkip}
+				},
+			})
+		},
+	}))
+
+	res, err := m.Validate(context.Background(), "acme", "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+
+	// Skipped by policy: no probe was spent
+	stats, err := m.Stats("acme")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.SMTPProbes)
+	assert.Equal(t, 100, stats.ProbesLeft)
+}
@@ -0,0 +1,76 @@
+package emailkit
+
+import (
+	"maps"
+	"strconv"
+)
+
+// Merge combines results of the same address validated by different
+// pipelines, oldest first — e.g. a syntax and domain check at signup and
+// a deep DNS and SMTP check later — into one Result, level by level: a
+// level's check comes from the latest result that ran it, so a later
+// SMTP probe replaces an earlier one while the signup's domain check
+// stays. Checks cut short (CodeCancelled) never replace a finished check
+// of an earlier result.
+//
+// Every merged check records its provenance in Meta["merged.from"], the
+// index of its result among the arguments. Levels keep the order in
+// which they first appear. The merged Result is Valid if every merged
+// check passed, and Truncated if one of them was cut short or every input
+// was truncated; its DeliverabilityProbability is that of the latest
+// result that has one, or 0 if the merge is truncated, and its RiskScore
+// that of the latest result that has one. Its Reachability and
+// FreeProvider are derived from the merged checks.
+func Merge(results ...Result) Result {
+	var out Result
+	if len(results) == 0 {
+		return out
+	}
+	index := map[CheckLevel]int{} // level -> position in out.Checks
+	truncated := true
+	for i, r := range results {
+		if r.Email != "" {
+			out.Email = r.Email
+		}
+		if !r.Truncated {
+			truncated = false
+		}
+		if r.DeliverabilityProbability > 0 {
+			out.DeliverabilityProbability = r.DeliverabilityProbability
+		}
+		if r.RiskScore > 0 {
+			out.RiskScore = r.RiskScore
+		}
+		for _, c := range r.Checks {
+			c.Meta = maps.Clone(c.Meta)
+			if c.Meta == nil {
+				c.Meta = map[string]string{}
+			}
+			c.Meta["merged.from"] = strconv.Itoa(i)
+			j, seen := index[c.Level]
+			switch {
+			case !seen:
+				index[c.Level] = len(out.Checks)
+				out.Checks = append(out.Checks, c)
+			case c.Code != CodeCancelled || out.Checks[j].Code == CodeCancelled:
+				out.Checks[j] = c
+			}
+		}
+	}
+
+	out.Valid = len(out.Checks) > 0
+	for _, c := range out.Checks {
+		if c.Code == CodeCancelled {
+			truncated = true
+		}
+		if !c.Passed {
+			out.Valid = false
+		}
+	}
+	if truncated {
+		out.Truncated, out.Valid, out.DeliverabilityProbability = true, false, 0
+	}
+	out.Reachability = reachability(out)
+	out.FreeProvider = out.hasCode(CodeFreeProvider)
+	return out
+}
@@ -0,0 +1,69 @@
+package emailkit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestMerge(t *testing.T) {
+	signup := emailkit.Result{Email: "alice@example.com", Valid: true, Checks: []emailkit.CheckResult{
+		{Level: emailkit.LevelSyntax, Passed: true},
+		{Level: emailkit.LevelDomain, Passed: true, Suggestion: "example.org"},
+	}}
+	deep := emailkit.Result{Email: "alice@example.com", Valid: false, Checks: []emailkit.CheckResult{
+		{Level: emailkit.LevelSyntax, Passed: true},
+		{Level: emailkit.LevelDNS, Passed: true},
+		{Level: emailkit.LevelSMTP, Passed: false, Code: emailkit.CodeMailboxUnknown, Meta: map[string]string{"probe": "rcpt"}},
+	}}
+
+	m := emailkit.Merge(signup, deep)
+	assert.Equal(t, "alice@example.com", m.Email)
+	assert.False(t, m.Valid)
+	assert.False(t, m.Truncated)
+	levels := make([]emailkit.CheckLevel, len(m.Checks))
+	from := make([]string, len(m.Checks))
+	for i, c := range m.Checks {
+		levels[i], from[i] = c.Level, c.Meta["merged.from"]
+	}
+	assert.Equal(t, []emailkit.CheckLevel{emailkit.LevelSyntax, emailkit.LevelDomain, emailkit.LevelDNS, emailkit.LevelSMTP}, levels)
+	assert.Equal(t, []string{"1", "0", "1", "1"}, from)
+	c, _ := m.CheckFor(emailkit.LevelSMTP)
+	assert.Equal(t, "rcpt", c.Meta["probe"])
+	assert.NotContains(t, deep.Checks[2].Meta, "merged.from", "inputs are not modified")
+
+	// A later probe that passes replaces the failure
+	retry := emailkit.Result{Email: "alice@example.com", Valid: true, DeliverabilityProbability: 0.9, RiskScore: 20, Checks: []emailkit.CheckResult{
+		{Level: emailkit.LevelSMTP, Passed: true},
+	}}
+	m = emailkit.Merge(signup, deep, retry)
+	assert.True(t, m.Valid)
+	assert.Equal(t, 0.9, m.DeliverabilityProbability)
+	assert.Equal(t, 20, m.RiskScore)
+}
+
+func TestMerge_Truncated(t *testing.T) {
+	done := emailkit.Result{Email: "a@example.com", Valid: true, Checks: []emailkit.CheckResult{
+		{Level: emailkit.LevelSyntax, Passed: true},
+		{Level: emailkit.LevelSMTP, Passed: true},
+	}}
+	cut := emailkit.Result{Email: "a@example.com", Truncated: true, Checks: []emailkit.CheckResult{
+		{Level: emailkit.LevelSyntax, Passed: true},
+		{Level: emailkit.LevelSMTP, Code: emailkit.CodeCancelled},
+	}}
+
+	m := emailkit.Merge(done, cut)
+	assert.True(t, m.Valid, "a cancelled check doesn't replace a finished one")
+	c, _ := m.CheckFor(emailkit.LevelSMTP)
+	assert.Equal(t, "0", c.Meta["merged.from"])
+
+	m = emailkit.Merge(cut)
+	assert.True(t, m.Truncated)
+	assert.False(t, m.Valid)
+
+	require.Empty(t, emailkit.Merge().Checks)
+	assert.False(t, emailkit.Merge().Valid)
+}
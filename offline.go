@@ -0,0 +1,394 @@
+package emailkit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/optimode/emailkit/internal/parse"
+)
+
+// FakeDomain is the behavior of a domain on a FakeNetwork. The zero value
+// is a domain with one MX host, "mx.<domain>", whose server rejects every
+// recipient with 550 5.1.1.
+type FakeDomain struct {
+	// MX lists the MX hosts by preference. Default: "mx.<domain>"
+	MX []string
+	// NoMX leaves out MX records: mail goes to the domain's own address
+	// (the implicit MX of RFC 5321).
+	NoMX bool
+	// NullMX publishes a null MX (RFC 7505): the domain accepts no mail.
+	NullMX bool
+	// NXDomain makes the domain nonexistent: lookups answer NXDOMAIN and
+	// the registry doesn't know it.
+	NXDomain bool
+	// DNSTimeout makes every lookup of the domain time out.
+	DNSTimeout bool
+	// NS lists the nameservers. Default: "ns1.<domain>", "ns2.<domain>"
+	NS []string
+	// TXT lists the TXT records, e.g. an SPF record.
+	TXT []string
+	// Registered is the registration date the fake RDAP registry reports.
+	// Default: 2000-01-01
+	Registered time.Time
+
+	// Mailboxes are the local parts that exist (case-insensitively);
+	// RCPT TO for any other is answered 550 5.1.1.
+	Mailboxes []string
+	// Full are local parts whose mailbox is full (452 4.2.2).
+	Full []string
+	// CatchAll accepts every recipient.
+	CatchAll bool
+	// Greylist answers the first RCPT TO of every recipient with 451 4.7.1.
+	Greylist bool
+	// RCPTReply, if set, answers every RCPT TO with this reply, e.g.
+	// "550 5.7.1 Service refused".
+	RCPTReply string
+	// Unreachable refuses connections to the domain's mail hosts.
+	Unreachable bool
+}
+
+// FakeNetwork is an in-memory, deterministic DNS, RDAP and SMTP backend
+// for hermetic tests of full pipelines; see NewOffline. Domains that were
+// not added don't exist. It is safe for concurrent use.
+type FakeNetwork struct {
+	mu         sync.Mutex
+	domains    map[string]FakeDomain // by lowercase ASCII domain
+	hosts      map[string]string     // mail host → domain
+	greylisted map[string]bool
+	probed     []string
+}
+
+// NewFakeNetwork creates an empty FakeNetwork.
+func NewFakeNetwork() *FakeNetwork {
+	return &FakeNetwork{
+		domains:    make(map[string]FakeDomain),
+		hosts:      make(map[string]string),
+		greylisted: make(map[string]bool),
+	}
+}
+
+// AddDomain adds or replaces a domain (IDNs in either form) and returns n.
+func (n *FakeNetwork) AddDomain(domain string, d FakeDomain) *FakeNetwork {
+	name := fakeName(domain)
+	if parsed := parse.NewDomain(domain); parsed.Valid {
+		name = fakeName(parsed.Domain)
+	}
+	if len(d.MX) == 0 {
+		d.MX = []string{"mx." + name}
+	}
+	if len(d.NS) == 0 {
+		d.NS = []string{"ns1." + name, "ns2." + name}
+	}
+	if d.Registered.IsZero() {
+		d.Registered = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.domains[name] = d
+	n.hosts[name] = name
+	if !d.NoMX && !d.NullMX {
+		for _, h := range d.MX {
+			n.hosts[fakeName(h)] = name
+		}
+	}
+	return n
+}
+
+// Probed returns the recipients probed with RCPT TO so far, in order.
+func (n *FakeNetwork) Probed() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return slices.Clone(n.probed)
+}
+
+func fakeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// lookup returns the domain of name, or the DNS error it answers with.
+func (n *FakeNetwork) lookup(name string) (FakeDomain, error) {
+	name = fakeName(name)
+	n.mu.Lock()
+	d, ok := n.domains[name]
+	n.mu.Unlock()
+	switch {
+	case !ok || d.NXDomain:
+		return FakeDomain{}, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	case d.DNSTimeout:
+		return FakeDomain{}, &net.DNSError{Err: "i/o timeout", Name: name, IsTimeout: true, IsTemporary: true}
+	}
+	return d, nil
+}
+
+// LookupMX implements Resolver.
+func (n *FakeNetwork) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	d, err := n.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case d.NullMX:
+		return []*net.MX{{Host: ".", Pref: 0}}, nil
+	case d.NoMX:
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	mx := make([]*net.MX, len(d.MX))
+	for i, h := range d.MX {
+		mx[i] = &net.MX{Host: fakeName(h) + ".", Pref: uint16(10 * (i + 1))}
+	}
+	return mx, nil
+}
+
+// LookupHost implements Resolver. Every mail host and domain on the
+// network has one address in 192.0.2.0/24 (TEST-NET-1).
+func (n *FakeNetwork) LookupHost(_ context.Context, host string) ([]string, error) {
+	host = fakeName(host)
+	n.mu.Lock()
+	domain, ok := n.hosts[host]
+	n.mu.Unlock()
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	if _, err := n.lookup(domain); err != nil {
+		return nil, err
+	}
+	return []string{fakeAddr(host)}, nil
+}
+
+// LookupNS implements Resolver.
+func (n *FakeNetwork) LookupNS(_ context.Context, name string) ([]*net.NS, error) {
+	d, err := n.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	ns := make([]*net.NS, len(d.NS))
+	for i, h := range d.NS {
+		ns[i] = &net.NS{Host: fakeName(h) + "."}
+	}
+	return ns, nil
+}
+
+// LookupTXT implements Resolver.
+func (n *FakeNetwork) LookupTXT(_ context.Context, name string) ([]string, error) {
+	d, err := n.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.TXT) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return slices.Clone(d.TXT), nil
+}
+
+// LookupAddr implements Resolver.
+func (n *FakeNetwork) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var names []string
+	for host := range n.hosts {
+		if fakeAddr(host) == addr {
+			names = append(names, host+".")
+		}
+	}
+	if len(names) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// probeNameserver answers for the nameservers of the domains on the
+// network, in place of a DNS query sent to them.
+func (n *FakeNetwork) probeNameserver(_ context.Context, nameserver, domain string) error {
+	d, err := n.lookup(domain)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(d.NS, fakeName(nameserver)) {
+		return fmt.Errorf("nameserver %s does not answer for %s", nameserver, domain)
+	}
+	return nil
+}
+
+// fakeAddr derives a stable 192.0.2.0/24 address from a host name.
+func fakeAddr(host string) string {
+	var h uint32 = 2166136261 // FNV-1a
+	for i := 0; i < len(host); i++ {
+		h = (h ^ uint32(host[i])) * 16777619
+	}
+	return fmt.Sprintf("192.0.2.%d", 1+h%254)
+}
+
+// dial connects to the fake SMTP server of the mail host in address.
+func (n *FakeNetwork) dial(_, address string, _ time.Duration) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	host = fakeName(host)
+	n.mu.Lock()
+	domain, ok := n.hosts[host]
+	d := n.domains[domain]
+	n.mu.Unlock()
+	if !ok || d.NXDomain || d.Unreachable {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connect %s: connection refused", address)}
+	}
+	client, server := net.Pipe()
+	go n.serve(server, host)
+	return client, nil
+}
+
+// serve speaks SMTP on conn as mail host host.
+func (n *FakeNetwork) serve(conn net.Conn, host string) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	reply := func(s string) bool {
+		_, err := io.WriteString(conn, s+"\r\n")
+		return err == nil
+	}
+	if !reply("220 " + host + " ESMTP emailkit fake") {
+		return
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+		var ok bool
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ok = reply("250-" + host + "\r\n250-PIPELINING\r\n250 8BITMIME")
+		case "HELO", "MAIL", "RSET", "NOOP":
+			ok = reply("250 2.0.0 OK")
+		case "RCPT":
+			ok = reply(n.rcpt(arg))
+		case "VRFY":
+			ok = reply("252 2.5.2 Cannot VRFY user")
+		case "QUIT":
+			reply("221 2.0.0 Bye")
+			return
+		default:
+			ok = reply("502 5.5.2 Command not recognized")
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// rcpt answers RCPT TO:<addr> per the recipient domain's behavior.
+func (n *FakeNetwork) rcpt(arg string) string {
+	addr := arg
+	if i, j := strings.IndexByte(arg, '<'), strings.IndexByte(arg, '>'); i >= 0 && j > i {
+		addr = arg[i+1 : j]
+	}
+	at := strings.LastIndexByte(addr, '@')
+	if at < 0 {
+		return "501 5.1.3 Bad recipient address syntax"
+	}
+	local, domain := strings.ToLower(addr[:at]), fakeName(addr[at+1:])
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.probed = append(n.probed, addr)
+	d, ok := n.domains[domain]
+	switch {
+	case !ok:
+		return "550 5.7.1 Relaying denied"
+	case d.RCPTReply != "":
+		return d.RCPTReply
+	case d.Greylist && !n.greylisted[local+"@"+domain]:
+		n.greylisted[local+"@"+domain] = true
+		return "451 4.7.1 Greylisted, please try again later"
+	case d.CatchAll:
+		return "250 2.1.5 OK"
+	case slices.ContainsFunc(d.Full, func(m string) bool { return strings.EqualFold(m, local) }):
+		return "452 4.2.2 Mailbox full"
+	case slices.ContainsFunc(d.Mailboxes, func(m string) bool { return strings.EqualFold(m, local) }):
+		return "250 2.1.5 OK"
+	}
+	return "550 5.1.1 User unknown"
+}
+
+// fakeRDAPBase is the RDAP service of every TLD on a FakeNetwork.
+const fakeRDAPBase = "https://rdap.emailkit.invalid/"
+
+// httpClient returns a client answering RDAP bootstrap and domain queries
+// from the network.
+func (n *FakeNetwork) httpClient() *http.Client {
+	return &http.Client{Transport: fakeRDAP{n}}
+}
+
+// fakeRDAP is the http.RoundTripper of FakeNetwork.httpClient.
+type fakeRDAP struct{ n *FakeNetwork }
+
+func (t fakeRDAP) RoundTrip(req *http.Request) (*http.Response, error) {
+	respond := func(status int, body any) (*http.Response, error) {
+		b, _ := json.Marshal(body)
+		return &http.Response{
+			StatusCode: status,
+			Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			Header:     http.Header{"Content-Type": {"application/rdap+json"}},
+			Body:       io.NopCloser(bytes.NewReader(b)),
+			Request:    req,
+		}, nil
+	}
+
+	t.n.mu.Lock()
+	defer t.n.mu.Unlock()
+	zone, isQuery := strings.CutPrefix(req.URL.String(), fakeRDAPBase+"domain/")
+	if !isQuery {
+		// The bootstrap file: every TLD is served by fakeRDAPBase
+		tlds := []string{}
+		for name := range t.n.domains {
+			tld := name[strings.LastIndexByte(name, '.')+1:]
+			if !slices.Contains(tlds, tld) {
+				tlds = append(tlds, tld)
+			}
+		}
+		slices.Sort(tlds)
+		return respond(http.StatusOK, map[string]any{"services": [][][]string{{tlds, {fakeRDAPBase}}}})
+	}
+
+	zone = fakeName(zone)
+	for name, d := range t.n.domains {
+		if !d.NXDomain && (name == zone || strings.HasSuffix(name, "."+zone)) {
+			return respond(http.StatusOK, map[string]any{
+				"events": []map[string]string{{"eventAction": "registration", "eventDate": d.Registered.UTC().Format(time.RFC3339)}},
+			})
+		}
+	}
+	return respond(http.StatusNotFound, map[string]any{"errorCode": 404})
+}
+
+// NewOffline creates a Validator wired to n instead of the network: DNS
+// lookups go to n (as with WithResolver), the SMTP levels connect to n's
+// in-memory mail servers, and the registration level queries n's fake
+// RDAP registry. Configure the pipeline as usual; a downstream CI suite
+// then exercises every level, the SMTP code paths included, hermetically
+// and deterministically:
+//
+//	n := emailkit.NewFakeNetwork().
+//		AddDomain("example.com", emailkit.FakeDomain{Mailboxes: []string{"alice"}}).
+//		AddDomain("catchall.example", emailkit.FakeDomain{CatchAll: true})
+//	v := emailkit.NewOffline(n).WithDNS().WithSMTP(smtpOpts)
+//
+// SMTPOptions.Dialer and the other socket options don't apply.
+func NewOffline(n *FakeNetwork) *Validator {
+	v := New().WithResolver(n)
+	v.offline = n
+	return v
+}
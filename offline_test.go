@@ -0,0 +1,203 @@
+package emailkit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+func fakeNetwork() *emailkit.FakeNetwork {
+	return emailkit.NewFakeNetwork().
+		AddDomain("example.com", emailkit.FakeDomain{
+			Mailboxes: []string{"alice", "Bob"},
+			Full:      []string{"carol"},
+			TXT:       []string{"v=spf1 mx -all"},
+		}).
+		AddDomain("catchall.example", emailkit.FakeDomain{CatchAll: true}).
+		AddDomain("grey.example", emailkit.FakeDomain{Greylist: true, Mailboxes: []string{"dave"}}).
+		AddDomain("blocked.example", emailkit.FakeDomain{RCPTReply: "550 5.7.1 Service refused"}).
+		AddDomain("down.example", emailkit.FakeDomain{Unreachable: true}).
+		AddDomain("nullmx.example", emailkit.FakeDomain{NullMX: true}).
+		AddDomain("slow.example", emailkit.FakeDomain{DNSTimeout: true}).
+		AddDomain("implicit.example", emailkit.FakeDomain{NoMX: true}).
+		AddDomain("new.example", emailkit.FakeDomain{Mailboxes: []string{"frank"}, Registered: time.Now().Add(-24 * time.Hour)})
+}
+
+func TestNewOffline(t *testing.T) {
+	n := fakeNetwork()
+	v := emailkit.NewOffline(n).WithDNS().WithNS().WithRegistration().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain:                  "verifier.test",
+		MailFrom:                    "verify@verifier.test",
+		ConnectTimeout:              time.Second,
+		CommandTimeout:              time.Second,
+		DisableCatchAllFingerprints: true,
+	})
+	defer func() { _ = v.Close() }()
+	ctx := context.Background()
+
+	tests := []struct {
+		email string
+		valid bool
+		level string
+		code  string
+	}{
+		{"alice@example.com", true, "", ""},
+		{"BOB@example.com", true, "", ""},
+		{"zed@example.com", false, emailkit.LevelSMTP, emailkit.CodeMailboxUnknown},
+		{"carol@example.com", false, emailkit.LevelSMTP, emailkit.CodeMailboxFull},
+		{"anyone@catchall.example", true, "", ""},
+		{"dave@grey.example", false, emailkit.LevelSMTP, emailkit.CodeGreylisted},
+		{"x@blocked.example", false, emailkit.LevelSMTP, emailkit.CodeBlocked},
+		{"x@slow.example", false, emailkit.LevelDNS, emailkit.CodeDNSTimeout},
+		{"x@nowhere.example", false, emailkit.LevelDNS, emailkit.CodeBadDomain},
+		{"x@implicit.example", false, emailkit.LevelDNS, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			res, err := v.Validate(ctx, tt.email)
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, res.Valid)
+			if tt.valid {
+				return
+			}
+			c := res.Checks[len(res.Checks)-1]
+			assert.Equal(t, tt.level, c.Level, c.Details)
+			if tt.code != "" {
+				assert.Equal(t, tt.code, c.Code, c.Details)
+			}
+		})
+	}
+
+	// Greylisting lets the retry through
+	res, err := v.Validate(ctx, "dave@grey.example")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+
+	res, err = v.Validate(ctx, "frank@new.example")
+	require.NoError(t, err)
+	c, _ := res.CheckFor(emailkit.LevelRegistration)
+	assert.NotEmpty(t, c.Meta["registered"])
+
+	assert.Contains(t, n.Probed(), "alice@example.com")
+}
+
+func TestNewOffline_Unreachable(t *testing.T) {
+	v := emailkit.NewOffline(fakeNetwork()).WithDNS().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain:     "verifier.test",
+		MailFrom:       "verify@verifier.test",
+		ConnectTimeout: time.Second,
+	})
+	defer func() { _ = v.Close() }()
+
+	res, err := v.Validate(context.Background(), "x@down.example")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	c, _ := res.CheckFor(emailkit.LevelSMTP)
+	assert.Contains(t, c.Details, "connection refused")
+}
+
+func TestNewOffline_WarmUp(t *testing.T) {
+	v := emailkit.NewOffline(fakeNetwork()).WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "verifier.test",
+		MailFrom:   "verify@verifier.test",
+		WarmUp:     &emailkit.WarmUp{Start: time.Now(), InitialDailyProbes: 2},
+	})
+	defer func() { _ = v.Close() }()
+	ctx := context.Background()
+
+	for _, email := range []string{"alice@example.com", "bob@example.com"} {
+		res, err := v.Validate(ctx, email)
+		require.NoError(t, err)
+		assert.True(t, res.Valid)
+	}
+	res, err := v.Validate(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	c, _ := res.CheckFor(emailkit.LevelSMTP)
+	assert.Equal(t, emailkit.CodeDeferred, c.Code)
+
+	h := v.Health(ctx)
+	require.NotNil(t, h.SMTP)
+	require.Len(t, h.SMTP.WarmUp, 1)
+	assert.Equal(t, emailkit.WarmUpStatus{Key: "example.com", Day: 1, Budget: 2, Probes: 2, Accepted: 2}, h.SMTP.WarmUp[0])
+}
+
+func TestValidator_Analytics(t *testing.T) {
+	assert.Equal(t, emailkit.ProbeAnalytics{}, emailkit.New().Analytics())
+
+	v := emailkit.NewOffline(fakeNetwork()).WithSMTP(emailkit.SMTPOptions{
+		HeloDomain:      "verifier.test",
+		MailFrom:        "verify@verifier.test",
+		AnalyticsWindow: time.Minute,
+	})
+	defer func() { _ = v.Close() }()
+	ctx := context.Background()
+	for _, email := range []string{"alice@example.com", "bob@example.com", "zed@example.com", "dave@grey.example", "x@down.example"} {
+		_, err := v.Validate(ctx, email)
+		require.NoError(t, err)
+	}
+
+	a := v.Analytics()
+	assert.Equal(t, time.Minute, a.Window)
+	require.Len(t, a.Providers, 3)
+	s := a.Providers[1]
+	assert.Equal(t, "example.com", s.Key)
+	assert.Equal(t, int64(3), s.Attempts)
+	assert.Equal(t, int64(2), s.Accepted)
+	assert.Equal(t, int64(1), s.Permanent)
+	assert.InDelta(t, 2.0/3, s.AcceptRate, 1e-9)
+	assert.Equal(t, emailkit.ProbeStats{Key: "grey.example", Attempts: 1, Temporary: 1, TemporaryRate: 1, AvgLatency: a.Providers[2].AvgLatency}, a.Providers[2])
+	assert.Equal(t, int64(1), a.Providers[0].Errors, "down.example")
+	assert.Len(t, a.MXHosts, 3)
+}
+
+func TestNewOffline_Registration(t *testing.T) {
+	n := emailkit.NewFakeNetwork().AddDomain("example.com", emailkit.FakeDomain{})
+	v := emailkit.NewOffline(n).WithRegistration()
+	ctx := context.Background()
+
+	res, err := v.ValidateDomain(ctx, "mail.example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+
+	res, err = v.ValidateDomain(ctx, "other.com")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+}
+
+func TestFakeNetwork_Resolver(t *testing.T) {
+	n := fakeNetwork()
+	ctx := context.Background()
+
+	mx, err := n.LookupMX(ctx, "Example.COM.")
+	require.NoError(t, err)
+	require.Len(t, mx, 1)
+	assert.Equal(t, "mx.example.com.", mx[0].Host)
+
+	addrs, err := n.LookupHost(ctx, "mx.example.com")
+	require.NoError(t, err)
+	again, _ := n.LookupHost(ctx, "mx.example.com")
+	assert.Equal(t, addrs, again, "addresses are deterministic")
+
+	names, err := n.LookupAddr(ctx, addrs[0])
+	require.NoError(t, err)
+	assert.Contains(t, names, "mx.example.com.")
+
+	txt, err := n.LookupTXT(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v=spf1 mx -all"}, txt)
+
+	ns, err := n.LookupNS(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Len(t, ns, 2)
+
+	_, err = n.LookupMX(ctx, "implicit.example")
+	assert.Error(t, err)
+	_, err = n.LookupHost(ctx, "implicit.example")
+	assert.NoError(t, err)
+}
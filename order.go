@@ -0,0 +1,122 @@
+package emailkit
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/optimode/emailkit/check"
+)
+
+// Capabilities is a re-export of what a level declares about itself for
+// WithAutoOrder: its CheckLevel, its LevelCost and whether it provides or
+// needs the domain's MX records.
+type Capabilities = check.Capabilities
+
+// LevelCost classes what running a level takes, from cheapest to dearest.
+type LevelCost = check.Cost
+
+// Level cost classes.
+const (
+	CostOffline = check.CostOffline // no network: embedded lists and parsing
+	CostDNS     = check.CostDNS     // DNS lookups, mostly cached
+	CostNetwork = check.CostNetwork // other network requests, e.g. RDAP or HTTPS
+	CostSMTP    = check.CostSMTP    // SMTP sessions with the domain's mail hosts
+)
+
+// CapabilityDeclarer is implemented by levels that declare their
+// Capabilities. All built-in levels implement it; levels that don't count
+// as CostNetwork, without a CheckLevel.
+type CapabilityDeclarer interface {
+	Capabilities() Capabilities
+}
+
+// WithAutoOrder orders the levels by what they cost instead of the order
+// they were added in, so that cheap offline levels reject what they can
+// before any lookup and SMTP probes run last: offline levels first, then
+// DNS, other network requests and SMTP. Levels that need the MX records
+// run after the level that resolves them (the DNS level), which shares
+// them through the validation's Findings so they are resolved once. A
+// level added twice runs once, with the options it was added with last.
+// Levels of the same cost keep their relative order. It applies to the
+// levels added before and after it.
+func (v *Validator) WithAutoOrder() *Validator {
+	v.autoOrder = true
+	v.checkers = orderLevels(v.checkers)
+	return v
+}
+
+// Levels returns the Capabilities of the pipeline's levels in the order
+// they run.
+func (v *Validator) Levels() []Capabilities {
+	out := make([]Capabilities, len(v.checkers))
+	for i, c := range v.checkers {
+		out[i] = capabilities(c)
+	}
+	return out
+}
+
+// add appends a level to the pipeline, ordered if WithAutoOrder is set.
+func (v *Validator) add(c Checker) {
+	v.checkers = append(v.checkers, c)
+	if v.autoOrder {
+		v.checkers = orderLevels(v.checkers)
+	}
+}
+
+// capabilities returns the Capabilities c declares, or the default.
+func capabilities(c Checker) Capabilities {
+	if d, ok := c.(CapabilityDeclarer); ok {
+		return d.Capabilities()
+	}
+	return Capabilities{Cost: CostNetwork}
+}
+
+// orderLevels drops all but the last of each declared level and sorts the
+// rest stably by cost, levels needing MX records costing at least as much
+// as the level providing them, and by providers before the other levels
+// and those before consumers within a cost.
+func orderLevels(checkers []Checker) []Checker {
+	type level struct {
+		c    Checker
+		cost LevelCost
+		rank int
+	}
+	caps := make([]Capabilities, len(checkers))
+	last := make(map[CheckLevel]int)
+	provider := LevelCost(-1)
+	for i, c := range checkers {
+		caps[i] = capabilities(c)
+		if caps[i].Level != "" {
+			last[caps[i].Level] = i
+		}
+		if caps[i].ProvidesMX && caps[i].Cost > provider {
+			provider = caps[i].Cost
+		}
+	}
+
+	levels := make([]level, 0, len(checkers))
+	for i, c := range checkers {
+		cp := caps[i]
+		if cp.Level != "" && last[cp.Level] != i {
+			continue
+		}
+		l := level{c: c, cost: cp.Cost, rank: 1}
+		switch {
+		case cp.ProvidesMX:
+			l.rank = 0
+		case cp.NeedsMX:
+			l.rank = 2
+			l.cost = max(l.cost, provider)
+		}
+		levels = append(levels, l)
+	}
+	slices.SortStableFunc(levels, func(a, b level) int {
+		return cmp.Or(cmp.Compare(a.cost, b.cost), cmp.Compare(a.rank, b.rank))
+	})
+
+	out := make([]Checker, len(levels))
+	for i, l := range levels {
+		out[i] = l.c
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package emailkit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+// offlineChecker is a custom level declaring that it needs no network.
+type offlineChecker struct{ suffixChecker }
+
+func (offlineChecker) Capabilities() emailkit.Capabilities {
+	return emailkit.Capabilities{Level: "suffix", Cost: emailkit.CostOffline}
+}
+
+func levelNames(v *emailkit.Validator) []emailkit.CheckLevel {
+	var out []emailkit.CheckLevel
+	for _, c := range v.Levels() {
+		out = append(out, c.Level)
+	}
+	return out
+}
+
+func TestWithAutoOrder(t *testing.T) {
+	smtp := emailkit.SMTPOptions{HeloDomain: "verifier.test", MailFrom: "verify@verifier.test"}
+
+	v := emailkit.New().
+		WithSMTP(smtp).
+		WithDNSBL("zen.spamhaus.org").
+		WithRegistration().
+		WithAutoOrder().
+		WithDNS().
+		With(suffixChecker{suffix: ".test"}).
+		WithDomain().
+		With(offlineChecker{suffixChecker{suffix: ".invalid"}})
+	defer func() { _ = v.Close() }()
+
+	assert.Equal(t, []emailkit.CheckLevel{
+		emailkit.LevelSyntax, emailkit.LevelDomain, "suffix",
+		emailkit.LevelDNS, emailkit.LevelDNSBL,
+		emailkit.LevelRegistration, "",
+		emailkit.LevelSMTP,
+	}, levelNames(v))
+	assert.Equal(t, emailkit.CostNetwork, v.Levels()[6].Cost, "undeclared levels count as network")
+}
+
+func TestWithAutoOrder_Deduplicates(t *testing.T) {
+	v := emailkit.New().WithAutoOrder().
+		WithDomain(emailkit.DomainOptions{CheckDisposable: false}).
+		WithDomain()
+
+	assert.Equal(t, []emailkit.CheckLevel{emailkit.LevelSyntax, emailkit.LevelDomain}, levelNames(v))
+	res, err := v.Validate(context.Background(), "user@mailinator.com")
+	require.NoError(t, err)
+	assert.False(t, res.Valid, "the options added last apply")
+}
+
+func TestWithAutoOrder_Default(t *testing.T) {
+	v := emailkit.New().WithDNS().WithDomain()
+	assert.Equal(t, []emailkit.CheckLevel{emailkit.LevelSyntax, emailkit.LevelDNS, emailkit.LevelDomain}, levelNames(v))
+
+	var cfg emailkit.PipelineConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"auto_order": true, "levels": [{"name": "dns"}, {"name": "domain"}]}`), &cfg))
+	assert.Equal(t, []emailkit.CheckLevel{emailkit.LevelSyntax, emailkit.LevelDomain, emailkit.LevelDNS}, levelNames(emailkit.NewFromConfig(cfg)))
+}
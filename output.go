@@ -0,0 +1,35 @@
+package emailkit
+
+// OutputOptions shapes the Results a Validator returns.
+type OutputOptions struct {
+	// OnlyFailures drops passing checks without caveats ("syntax ok",
+	// "domain ok") from Result.Checks, keeping failures and passes that
+	// carry a Suggestion or Code, so Severity is unchanged. It shrinks bulk
+	// exports of mostly valid rows. DeliverabilityProbability is computed
+	// before the checks are dropped. Default: false
+	OnlyFailures bool
+}
+
+// WithOutput sets the output options.
+func (v *Validator) WithOutput(o OutputOptions) *Validator {
+	v.output = o
+	return v
+}
+
+// shape applies the output options to a finished Result.
+func (v *Validator) shape(r Result) Result {
+	if !v.output.OnlyFailures || len(r.Checks) == 0 {
+		return r
+	}
+	kept := make([]CheckResult, 0, len(r.Checks))
+	for _, c := range r.Checks {
+		if checkSeverity(c) != SeverityInfo {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	r.Checks = kept
+	return r
+}
@@ -0,0 +1,42 @@
+package emailkit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+func TestWithOutput_OnlyFailures(t *testing.T) {
+	v := emailkit.New().WithDomain().WithOutput(emailkit.OutputOptions{OnlyFailures: true}).WithCalibration()
+	ctx := context.Background()
+
+	res, err := v.Validate(ctx, "john.doe@gmail.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+	assert.Empty(t, res.Checks)
+	assert.Equal(t, emailkit.SeverityInfo, res.Severity())
+	assert.InDelta(t, 0.85, res.DeliverabilityProbability, 1e-9, "computed from all checks")
+
+	// Passes with caveats are kept
+	res, err = v.Validate(ctx, "user@gmial.com")
+	require.NoError(t, err)
+	require.Len(t, res.Checks, 1)
+	assert.Equal(t, "gmail.com", res.Checks[0].Suggestion)
+	assert.Equal(t, emailkit.SeverityWarn, res.Severity())
+
+	res, err = v.ValidateAll(ctx, "user@mailinator.com")
+	require.NoError(t, err)
+	require.Len(t, res.Checks, 1)
+	assert.Equal(t, emailkit.LevelDomain, res.Checks[0].Level)
+	assert.False(t, res.Checks[0].Passed)
+
+	results, err := v.ValidateMany(ctx, []string{"john.doe@gmail.com", "not-an-email"})
+	require.NoError(t, err)
+	assert.Empty(t, results[0].Checks)
+	require.Len(t, results[1].Checks, 1)
+	assert.Equal(t, emailkit.LevelSyntax, results[1].Checks[0].Level)
+}
@@ -0,0 +1,26 @@
+package parquet_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/parquet"
+)
+
+func ExampleWriter_OnBatch() {
+	var buf bytes.Buffer // or an *os.File
+	pw := parquet.NewWriter(&buf, parquet.Options{Compression: parquet.Gzip})
+
+	v := emailkit.New().WithDomain()
+	_, err := v.ValidateMany(context.Background(), []string{"alice@example.com", "temp@mailinator.com"}, emailkit.ConcurrencyOptions{
+		MaxBatch: 10000,
+		OnBatch:  pw.OnBatch(), // one or more row groups per chunk
+	})
+	if err == nil {
+		err = pw.Close() // writes the footer
+	}
+	fmt.Println(err, bytes.HasPrefix(buf.Bytes(), []byte("PAR1")))
+	// Output: <nil> true
+}
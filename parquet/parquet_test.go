@@ -0,0 +1,259 @@
+package parquet_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/internal/disposable"
+	"github.com/optimode/emailkit/parquet"
+)
+
+// tstruct is a decoded Thrift struct: field ID to value (int64, []byte,
+// bool, []any or tstruct).
+type tstruct map[int16]any
+
+// decoder reads the Thrift compact protocol, independently of the writer.
+type decoder struct {
+	r *bytes.Reader
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (d *decoder) byte() byte {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (d *decoder) value(typ byte) any {
+	switch typ {
+	case 1, 2:
+		return typ == 1
+	case 5, 6:
+		v, err := binary.ReadVarint(d.r)
+		if err != nil {
+			panic(err)
+		}
+		return v
+	case 8:
+		b := make([]byte, d.uvarint())
+		_, _ = io.ReadFull(d.r, b)
+		return b
+	case 9:
+		h := d.byte()
+		n, elem := int(h>>4), h&0x0f
+		if n == 15 {
+			n = int(d.uvarint())
+		}
+		out := make([]any, n)
+		for i := range out {
+			out[i] = d.value(elem)
+		}
+		return out
+	case 12:
+		return d.structure()
+	}
+	panic("unexpected thrift type")
+}
+
+func (d *decoder) structure() tstruct {
+	s := tstruct{}
+	var id int16
+	for {
+		h := d.byte()
+		if h == 0 {
+			return s
+		}
+		if delta := int16(h >> 4); delta != 0 {
+			id += delta
+		} else {
+			v, _ := binary.ReadVarint(d.r)
+			id = int16(v)
+		}
+		s[id] = d.value(h & 0x0f)
+	}
+}
+
+// readFile decodes a Parquet file written by Writer into its column names
+// and, per column, the values of every row (nil for NULL).
+func readFile(t *testing.T, data []byte) (names []string, rows int64, values map[string][]any) {
+	t.Helper()
+	require.True(t, bytes.HasPrefix(data, []byte("PAR1")))
+	require.True(t, bytes.HasSuffix(data, []byte("PAR1")))
+	n := binary.LittleEndian.Uint32(data[len(data)-8:])
+	footer := data[len(data)-8-int(n) : len(data)-8]
+	meta := (&decoder{bytes.NewReader(footer)}).structure()
+
+	schema := meta[2].([]any)
+	assert.Equal(t, "schema", string(schema[0].(tstruct)[4].([]byte)))
+	types := map[string]int64{}
+	optional := map[string]bool{}
+	for _, e := range schema[1:] {
+		el := e.(tstruct)
+		name := string(el[4].([]byte))
+		names = append(names, name)
+		types[name] = el[1].(int64)
+		optional[name] = el[3].(int64) == 1
+	}
+	rows = meta[3].(int64)
+
+	values = map[string][]any{}
+	for _, g := range meta[4].([]any) {
+		group := g.(tstruct)
+		groupRows := int(group[3].(int64))
+		for _, c := range group[1].([]any) {
+			cm := c.(tstruct)[3].(tstruct)
+			name := string(cm[3].([]any)[0].([]byte))
+			r := bytes.NewReader(data[cm[9].(int64):])
+			header := (&decoder{r}).structure()
+			page := make([]byte, header[3].(int64))
+			_, _ = io.ReadFull(r, page)
+			if cm[4].(int64) == 2 {
+				zr, err := gzip.NewReader(bytes.NewReader(page))
+				require.NoError(t, err)
+				page, err = io.ReadAll(zr)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, header[2].(int64), int64(len(page)))
+			assert.Equal(t, int64(groupRows), header[5].(tstruct)[1].(int64))
+			values[name] = append(values[name], decodePage(page, types[name], optional[name], groupRows)...)
+		}
+	}
+	return names, rows, values
+}
+
+// decodePage decodes PLAIN values after RLE definition levels.
+func decodePage(page []byte, typ int64, optional bool, rows int) []any {
+	present := make([]bool, rows)
+	for i := range present {
+		present[i] = true
+	}
+	if optional {
+		n := binary.LittleEndian.Uint32(page)
+		r := bytes.NewReader(page[4 : 4+n])
+		for i := 0; i < rows; {
+			h, _ := binary.ReadUvarint(r)
+			level, _ := r.ReadByte()
+			for range h >> 1 {
+				present[i] = level == 1
+				i++
+			}
+		}
+		page = page[4+n:]
+	}
+	out := make([]any, rows)
+	bit := 0
+	for i := range out {
+		if !present[i] {
+			continue
+		}
+		switch typ {
+		case 0:
+			out[i] = page[bit/8]&(1<<(bit%8)) != 0
+			bit++
+		case 2:
+			out[i] = int64(binary.LittleEndian.Uint64(page))
+			page = page[8:]
+		case 5:
+			out[i] = math.Float64frombits(binary.LittleEndian.Uint64(page))
+			page = page[8:]
+		case 6:
+			n := binary.LittleEndian.Uint32(page)
+			out[i] = string(page[4 : 4+n])
+			page = page[4+n:]
+		}
+	}
+	return out
+}
+
+func results(t *testing.T) []emailkit.Result {
+	if !disposable.Embedded {
+		// Built with emailkit_nodisposable: the fixture's disposable domain
+		_, err := emailkit.LoadDisposableDomains(strings.NewReader("mailinator.com\n"))
+		require.NoError(t, err)
+	}
+	v := emailkit.New().WithDomain().WithRole().WithScoring()
+	var out []emailkit.Result
+	for _, email := range []string{"jane@example.com", "info@gmial.com", "temp@mailinator.com", "not-an-email", "bob@example.org"} {
+		r, err := v.Validate(context.Background(), email)
+		require.NoError(t, err)
+		out = append(out, r)
+	}
+	return out
+}
+
+func TestWriter(t *testing.T) {
+	for _, compression := range []parquet.Compression{parquet.Uncompressed, parquet.Gzip} {
+		var buf bytes.Buffer
+		w := parquet.NewWriter(&buf, parquet.Options{RowGroupSize: 2, Compression: compression})
+		in := results(t)
+		require.NoError(t, w.Write(in...))
+		require.NoError(t, w.Close())
+
+		names, rows, values := readFile(t, buf.Bytes())
+		assert.Equal(t, int64(len(in)), rows)
+		assert.Equal(t, []string{"email", "valid", "truncated", "reachability", "severity", "score", "probability",
+			"syntax_ok", "dns_ok", "domain_ok", "smtp_ok", "mx_host", "smtp_code", "disposable", "catch_all",
+			"role", "parked", "free_provider", "suggestion", "failed_level", "code", "details"}, names)
+
+		assert.Equal(t, []any{"jane@example.com", "info@gmial.com", "temp@mailinator.com", "not-an-email", "bob@example.org"}, values["email"])
+		assert.Equal(t, []any{true, true, false, false, true}, values["valid"])
+		assert.Equal(t, []any{true, true, true, false, true}, values["syntax_ok"])
+		assert.Equal(t, []any{true, true, false, nil, true}, values["domain_ok"], "not-an-email short-circuits")
+		assert.Equal(t, []any{nil, nil, nil, nil, nil}, values["dns_ok"])
+		assert.Equal(t, []any{false, false, true, false, false}, values["disposable"])
+		assert.Equal(t, []any{false, true, false, false, false}, values["role"])
+		assert.Equal(t, []any{"", "gmail.com", "", "", ""}, values["suggestion"])
+		for i, r := range in {
+			assert.Equal(t, int64(r.RiskScore), values["score"][i])
+			assert.Equal(t, 0.0, values["probability"][i])
+		}
+	}
+}
+
+func TestWriter_OnBatch(t *testing.T) {
+	emails := []string{"a@example.com", "b@example.com", "c@example.com"}
+	var buf bytes.Buffer
+	w := parquet.NewWriter(&buf)
+	_, err := emailkit.New().ValidateMany(context.Background(), emails, emailkit.ConcurrencyOptions{
+		MaxBatch: 2,
+		OnBatch:  w.OnBatch(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, rows, values := readFile(t, buf.Bytes())
+	assert.Equal(t, int64(3), rows)
+	assert.Equal(t, []any{"a@example.com", "b@example.com", "c@example.com"}, values["email"])
+
+	assert.ErrorIs(t, w.Write(emailkit.Result{}), parquet.ErrClosed)
+	assert.ErrorIs(t, w.Close(), parquet.ErrClosed)
+}
+
+func TestWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w := parquet.NewWriter(&buf)
+	require.NoError(t, w.Close())
+
+	names, rows, _ := readFile(t, buf.Bytes())
+	assert.Equal(t, int64(0), rows)
+	assert.Len(t, names, 22)
+}
@@ -0,0 +1,83 @@
+package parquet
+
+import "encoding/binary"
+
+// Thrift compact protocol types.
+const (
+	tI32    = 5
+	tI64    = 6
+	tBinary = 8
+	tList   = 9
+	tStruct = 12
+)
+
+// thrift encodes the Thrift compact protocol, which Parquet uses for its
+// page headers and file metadata. Only what the writer needs is covered.
+type thrift struct {
+	buf  []byte
+	last []int16 // last field ID of each open struct
+}
+
+func (t *thrift) uvarint(v uint64) { t.buf = binary.AppendUvarint(t.buf, v) }
+
+func (t *thrift) varint(v int64) { t.buf = binary.AppendVarint(t.buf, v) } // zigzag
+
+func (t *thrift) field(id int16, typ byte) {
+	last := &t.last[len(t.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|typ)
+	} else {
+		t.buf = append(t.buf, typ)
+		t.varint(int64(id))
+	}
+	*last = id
+}
+
+func (t *thrift) i32(id int16, v int32) {
+	t.field(id, tI32)
+	t.varint(int64(v))
+}
+
+func (t *thrift) i64(id int16, v int64) {
+	t.field(id, tI64)
+	t.varint(v)
+}
+
+func (t *thrift) string(id int16, v string) {
+	t.field(id, tBinary)
+	t.uvarint(uint64(len(v)))
+	t.buf = append(t.buf, v...)
+}
+
+// list starts a list field of n elements of typ; the caller writes them.
+func (t *thrift) list(id int16, typ byte, n int) {
+	t.field(id, tList)
+	if n < 15 {
+		t.buf = append(t.buf, byte(n)<<4|typ)
+	} else {
+		t.buf = append(t.buf, 0xf0|typ)
+		t.uvarint(uint64(n))
+	}
+}
+
+// begin starts a struct: the top-level one or a list element.
+func (t *thrift) begin() { t.last = append(t.last, 0) }
+
+// structField starts a struct field.
+func (t *thrift) structField(id int16) {
+	t.field(id, tStruct)
+	t.begin()
+}
+
+func (t *thrift) end() {
+	t.buf = append(t.buf, 0) // stop
+	t.last = t.last[:len(t.last)-1]
+}
+
+// elemI32 and elemString write list elements.
+func (t *thrift) elemI32(v int32) { t.varint(int64(v)) }
+
+func (t *thrift) elemString(v string) {
+	t.uvarint(uint64(len(v)))
+	t.buf = append(t.buf, v...)
+}
@@ -0,0 +1,54 @@
+package emailkit
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/optimode/emailkit/internal/parse"
+)
+
+// prefetchMX looks up the MX records of the domains of emails into the
+// DNS cache, with up to workers lookups at a time, in the domain order the
+// validation workers follow. Validations then find their answer cached or
+// join the lookup in flight instead of waiting for it one after the other.
+// The returned function waits for the prefetch to end; it stops early
+// once ctx is done or stop is closed.
+func (v *Validator) prefetchMX(ctx context.Context, emails []string, workers int, stop <-chan struct{}) (wait func()) {
+	if v.dnsCache == nil || workers <= 0 {
+		return func() {}
+	}
+	var domains []string
+	for _, e := range emails {
+		if parsed := parse.NewEmail(e); parsed.Valid {
+			domains = append(domains, parsed.Domain)
+		}
+	}
+	slices.Sort(domains)
+	domains = slices.Compact(domains)
+
+	next := make(chan string)
+	var wg sync.WaitGroup
+	for range min(workers, len(domains)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for domain := range next {
+				_, _ = v.dnsCache.LookupMX(domain)
+			}
+		}()
+	}
+	go func() {
+		defer close(next)
+		for _, domain := range domains {
+			select {
+			case next <- domain:
+			case <-ctx.Done():
+				return
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return wg.Wait
+}
@@ -0,0 +1,57 @@
+package emailkit_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+// slowMX answers every MX lookup after a delay, counting lookups per name.
+type slowMX struct {
+	stubResolver
+	delay time.Duration
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (r *slowMX) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	r.mu.Lock()
+	r.calls[name]++
+	r.mu.Unlock()
+	time.Sleep(r.delay)
+	return []*net.MX{{Host: "mx." + name + ".", Pref: 10}}, nil
+}
+
+func TestValidateMany_PrefetchDNS(t *testing.T) {
+	var emails []string
+	for i := range 8 {
+		emails = append(emails, fmt.Sprintf("a@d%d.example", i), fmt.Sprintf("b@d%d.example", i))
+	}
+	emails = append(emails, "not-an-email")
+
+	r := &slowMX{delay: 50 * time.Millisecond, calls: map[string]int{}}
+	v := emailkit.New().WithDNS().WithResolver(r)
+
+	start := time.Now()
+	results, err := v.ValidateMany(context.Background(), emails, emailkit.ConcurrencyOptions{Workers: 1, PrefetchDNS: 8})
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+
+	for i, res := range results[:16] {
+		assert.True(t, res.Valid, emails[i])
+	}
+	assert.Len(t, r.calls, 8)
+	for domain, n := range r.calls {
+		assert.Equal(t, 1, n, domain)
+	}
+	// One worker alone would wait 8 × 50ms for the lookups
+	assert.Less(t, elapsed, 250*time.Millisecond)
+}
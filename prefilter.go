@@ -0,0 +1,102 @@
+package emailkit
+
+import (
+	"context"
+	"slices"
+
+	"github.com/optimode/emailkit/internal/parse"
+)
+
+// prefilterPlan is the outcome of the ValidateMany prefilter stage:
+// duplicates collapsed, and addresses rejected by the offline levels
+// already resolved, so only survivors reach the network levels.
+type prefilterPlan struct {
+	emails    []string
+	survivors []string // unique addresses that passed the offline levels
+	rejected  []Result // unique addresses that failed them
+	// offline holds the offline checks of survivors[i], reused when the
+	// survivor is validated (see runReusing)
+	offline [][]CheckResult
+	// slot maps each input to survivors[i] (i >= 0) or rejected[-i-1]
+	slot []int
+}
+
+// isOffline reports whether a level declares it works without network
+// access.
+func isOffline(c Checker) bool {
+	return capabilities(c).Cost == CostOffline
+}
+
+// prefilter deduplicates the batch and runs the offline levels (syntax,
+// domain, role and other levels declaring CostOffline) on every unique
+// address.
+func (v *Validator) prefilter(ctx context.Context, emails []string) *prefilterPlan {
+	plan := &prefilterPlan{emails: emails, slot: make([]int, len(emails))}
+	seen := make(map[string]int, len(emails))
+
+	for i, e := range emails {
+		parsed := parse.NewEmail(e)
+		key := parsed.Raw
+		if parsed.Valid {
+			key = parsed.Local + "@" + parsed.Domain
+		}
+		if slot, ok := seen[key]; ok {
+			plan.slot[i] = slot
+			continue
+		}
+
+		res := v.runChecks(ctx, e, parsed, true, func(ctx context.Context, c Checker) (CheckResult, bool) {
+			if !isOffline(c) {
+				return CheckResult{}, false
+			}
+			return c.Check(ctx, parsed), true
+		})
+		slot := len(plan.survivors)
+		if res.Valid {
+			plan.survivors = append(plan.survivors, e)
+			plan.offline = append(plan.offline, res.Checks)
+		} else {
+			plan.rejected = append(plan.rejected, v.anonymize(v.shape(res)))
+			slot = -len(plan.rejected)
+		}
+		seen[key] = slot
+		plan.slot[i] = slot
+	}
+	return plan
+}
+
+// validate validates survivors[i] without running its offline levels
+// again.
+func (p *prefilterPlan) validate(ctx context.Context, v *Validator, i int) (Result, error) {
+	if v.err != nil {
+		return Result{}, v.err
+	}
+	return v.runReusing(ctx, p.survivors[i], true, p.offline[i]), nil
+}
+
+// expand maps the survivors' results back to every input position.
+func (p *prefilterPlan) expand(v *Validator, survivorResults []Result) []Result {
+	results := make([]Result, len(p.emails))
+	for i, slot := range p.slot {
+		var r Result
+		if slot >= 0 {
+			r = survivorResults[slot]
+		} else {
+			r = p.rejected[-slot-1]
+		}
+		// Duplicates share a result but keep their own input
+		r.Email = v.label(p.emails[i])
+		r.Checks = slices.Clone(r.Checks)
+		results[i] = r
+	}
+	return results
+}
+
+// expandFlags maps per-survivor flags back to every input position.
+func (p *prefilterPlan) expandFlags(survivorFlags []bool) []bool {
+	flags := make([]bool, len(p.emails))
+	for i, slot := range p.slot {
+		flags[i] = slot >= 0 && survivorFlags[slot]
+	}
+	return flags
+}
@@ -0,0 +1,77 @@
+package emailkit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/optimode/emailkit/internal/redact"
+)
+
+// WithPrivacy enables hash-only results for GDPR-conscious storage:
+// Result.Email holds a salted hash of the input instead of the address,
+// and email and IP addresses are redacted from every check's Details and
+// SMTP Attempts.
+// Validation itself still sees the raw address.
+func (v *Validator) WithPrivacy(opts PrivacyOptions) *Validator {
+	if opts.Hasher == nil {
+		if len(opts.Salt) == 0 {
+			v.setErr(&OptionsError{Field: "PrivacyOptions.Salt", Constraint: "required without a Hasher", Err: ErrInvalidPrivacyOptions})
+			return v
+		}
+		salt := append([]byte(nil), opts.Salt...)
+		opts.Hasher = func(email string) string {
+			mac := hmac.New(sha256.New, salt)
+			mac.Write([]byte(email))
+			return hex.EncodeToString(mac.Sum(nil))
+		}
+	}
+	v.hasher = opts.Hasher
+	return v
+}
+
+// anonymize applies the privacy mode, if enabled, to a finished Result.
+func (v *Validator) anonymize(r Result) Result {
+	if v.hasher == nil {
+		return r
+	}
+	raw := r.Email
+	r.Email = v.hasher(raw)
+	if len(r.Checks) > 0 {
+		checks := make([]CheckResult, len(r.Checks))
+		for i, c := range r.Checks {
+			c.Details = anonymizeText(c.Details, raw)
+			if len(c.Attempts) > 0 {
+				attempts := make([]ProbeAttempt, len(c.Attempts))
+				for j, a := range c.Attempts {
+					a.Message = anonymizeText(a.Message, raw)
+					a.Error = anonymizeText(a.Error, raw)
+					attempts[j] = a
+				}
+				c.Attempts = attempts
+			}
+			checks[i] = c
+		}
+		r.Checks = checks
+	}
+	return r
+}
+
+// anonymizeText redacts the raw address and any email or IP address
+// from s.
+func anonymizeText(s, raw string) string {
+	if raw != "" {
+		s = strings.ReplaceAll(s, raw, redact.EmailPlaceholder)
+	}
+	return redact.PII(s)
+}
+
+// label identifies an email in error messages without leaking it
+// when privacy mode is enabled.
+func (v *Validator) label(email string) string {
+	if v.hasher == nil {
+		return email
+	}
+	return v.hasher(email)
+}
@@ -0,0 +1,108 @@
+package emailkit_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+// echoChecker fails with Details that echo the address and a client IP,
+// like a chatty MX server would.
+type echoChecker struct{}
+
+func (echoChecker) Check(_ context.Context, email emailkit.Email) emailkit.CheckResult {
+	return emailkit.CheckResult{
+		Level:   "echo",
+		Passed:  false,
+		Details: "rejected " + email.Raw + " from 203.0.113.7",
+		Attempts: []emailkit.ProbeAttempt{
+			{Host: "mx.example.com", Code: 550, Message: "550 <" + email.Raw + ">: unknown"},
+		},
+	}
+}
+
+func TestWithPrivacy_HashesEmail(t *testing.T) {
+	salt := []byte("pepper")
+	v := emailkit.New().WithPrivacy(emailkit.PrivacyOptions{Salt: salt})
+
+	result, err := v.Validate(context.Background(), "user@example.com")
+	require.NoError(t, err)
+
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte("user@example.com"))
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), result.Email)
+	assert.True(t, result.Valid)
+}
+
+func TestWithPrivacy_RedactsDetails(t *testing.T) {
+	v := emailkit.New().With(echoChecker{}).WithPrivacy(emailkit.PrivacyOptions{Salt: []byte("s")})
+
+	result, err := v.ValidateAll(context.Background(), "jane.doe@example.com")
+	require.NoError(t, err)
+	require.Len(t, result.Checks, 2)
+
+	details := result.Checks[1].Details
+	assert.NotContains(t, details, "jane.doe@example.com")
+	assert.NotContains(t, details, "203.0.113.7")
+	assert.Equal(t, "rejected [email] from [ip]", details)
+	assert.Equal(t, "550 <[email]>: unknown", result.Checks[1].Attempts[0].Message)
+}
+
+func TestWithPrivacy_InvalidInputNotLeaked(t *testing.T) {
+	v := emailkit.New().With(echoChecker{}).WithPrivacy(emailkit.PrivacyOptions{Salt: []byte("s")})
+
+	result, err := v.ValidateAll(context.Background(), "not an email")
+	require.NoError(t, err)
+	assert.NotEqual(t, "not an email", result.Email)
+	for _, c := range result.Checks {
+		assert.NotContains(t, c.Details, "not an email")
+	}
+}
+
+func TestWithPrivacy_CustomHasher(t *testing.T) {
+	v := emailkit.New().WithPrivacy(emailkit.PrivacyOptions{
+		Hasher: func(email string) string { return "h:" + strings.ToLower(email) },
+	})
+
+	result, err := v.Validate(context.Background(), "User@Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "h:user@example.com", result.Email)
+}
+
+func TestWithPrivacy_RequiresSaltOrHasher(t *testing.T) {
+	v := emailkit.New().WithPrivacy(emailkit.PrivacyOptions{})
+
+	_, err := v.Validate(context.Background(), "user@example.com")
+	assert.ErrorIs(t, err, emailkit.ErrInvalidPrivacyOptions)
+}
+
+func TestWithPrivacy_GreylistKeys(t *testing.T) {
+	ctx := context.Background()
+	store := emailkit.NewMemoryGreylistStore()
+	// WithPrivacy after WithSMTP still applies to the greylist keys
+	v := emailkit.NewOffline(fakeNetwork()).WithDNS().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "verifier.test",
+		MailFrom:   "verify@verifier.test",
+		Greylist:   store,
+	}).WithPrivacy(emailkit.PrivacyOptions{Hasher: func(s string) string { return "h:" + s }})
+	defer func() { _ = v.Close() }()
+
+	_, err := v.Validate(ctx, "dave@grey.example")
+	require.NoError(t, err)
+
+	st := v.DomainState("grey.example")
+	assert.Len(t, st.Greylisted, 1)
+	assert.Contains(t, st.Greylisted, "mx.grey.example|h:dave@grey.example")
+
+	inv, err := v.Invalidate(ctx, "grey.example")
+	require.NoError(t, err)
+	assert.Equal(t, 1, inv.Greylist)
+}
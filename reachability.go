@@ -0,0 +1,90 @@
+package emailkit
+
+// Reachability is the verdict of a Result on whether mail to the address
+// would arrive, with more nuance than Valid: a greylisted probe and a
+// hard 550 are both invalid, but only the 550 is Undeliverable.
+//
+// It is derived from the checks, in this order:
+//
+//   - Unknown for a truncated result.
+//   - Undeliverable if a check failed on its merits: bad syntax, a
+//     domain without mail servers, a disposable domain, a mailbox the
+//     server rejected, a blocklist or policy match, and so on.
+//   - Unknown if a check failed without an answer about the address:
+//     greylisting, temporary and rate limiting replies, a server that
+//     refused the verifier (CodeBlocked), DNS timeouts and errors,
+//     deferred, cancelled, abandoned or crashed checks.
+//   - Risky if a check failed because the mailbox is full, or every
+//     check passed but one carries a caveat: a catch-all domain
+//     (CodeCatchAll), a parked domain (CodeParked), a restricted domain
+//     (CodeRestricted) or a suggested correction.
+//   - Deliverable if every check passed and the SMTP level accepted the
+//     mailbox (a RCPT TO probe, not only a connection), or the address
+//     is allowlisted.
+//   - Unknown otherwise: the checks found nothing wrong, but no mailbox
+//     was probed.
+type Reachability string
+
+// Reachability verdicts.
+const (
+	ReachabilityDeliverable   Reachability = "deliverable"
+	ReachabilityUndeliverable Reachability = "undeliverable"
+	ReachabilityRisky         Reachability = "risky"
+	ReachabilityUnknown       Reachability = "unknown"
+)
+
+// unknownCodes mark failed checks that give no answer about the address.
+var unknownCodes = map[CheckCode]bool{
+	CodeGreylisted:         true,
+	CodeTemporary:          true,
+	CodeRateLimited:        true,
+	CodeServiceUnavailable: true,
+	CodeBlocked:            true,
+	CodeDeferred:           true,
+	CodeDNSTimeout:         true,
+	CodeDNSError:           true,
+	CodeCancelled:          true,
+	CodeError:              true,
+	CodeUnknown:            true,
+	CodeTimedOut:           true,
+}
+
+// riskyCodes mark passed checks with a caveat.
+var riskyCodes = map[CheckCode]bool{
+	CodeCatchAll:   true,
+	CodeParked:     true,
+	CodeRestricted: true,
+}
+
+// reachability derives r's Reachability; see Reachability for the rules.
+func reachability(r Result) Reachability {
+	if r.Truncated {
+		return ReachabilityUnknown
+	}
+	unknown, risky, probed := false, false, false
+	for _, c := range r.Checks {
+		switch {
+		case c.Passed && c.Code == CodeAllowlisted:
+			return ReachabilityDeliverable
+		case !c.Passed && c.Code == CodeMailboxFull:
+			risky = true
+		case !c.Passed && unknownCodes[c.Code]:
+			unknown = true
+		case !c.Passed:
+			return ReachabilityUndeliverable
+		case riskyCodes[c.Code] || c.Suggestion != "":
+			risky = true
+		case c.Level == LevelSMTP && c.Code == "" && c.Meta["probe"] != "connect":
+			probed = true
+		}
+	}
+	switch {
+	case unknown:
+		return ReachabilityUnknown
+	case risky:
+		return ReachabilityRisky
+	case probed:
+		return ReachabilityDeliverable
+	}
+	return ReachabilityUnknown
+}
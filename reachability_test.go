@@ -0,0 +1,72 @@
+package emailkit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestResult_Reachability(t *testing.T) {
+	v := emailkit.NewOffline(fakeNetwork()).WithDNS().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain:     "verifier.test",
+		MailFrom:       "verify@verifier.test",
+		ConnectTimeout: time.Second,
+	})
+	defer func() { _ = v.Close() }()
+	ctx := context.Background()
+
+	tests := []struct {
+		email string
+		want  emailkit.Reachability
+	}{
+		{"alice@example.com", emailkit.ReachabilityDeliverable},
+		{"zed@example.com", emailkit.ReachabilityUndeliverable},
+		{"carol@example.com", emailkit.ReachabilityRisky}, // mailbox full
+		{"dave@grey.example", emailkit.ReachabilityUnknown},
+		{"x@blocked.example", emailkit.ReachabilityUnknown},
+		{"x@slow.example", emailkit.ReachabilityUnknown},
+		{"x@nowhere.example", emailkit.ReachabilityUndeliverable},
+		{"not-an-email", emailkit.ReachabilityUndeliverable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			res, err := v.Validate(ctx, tt.email)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, res.Reachability)
+		})
+	}
+}
+
+func TestResult_ReachabilityWithoutProbe(t *testing.T) {
+	ctx := context.Background()
+
+	res, err := emailkit.New().Validate(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+	assert.Equal(t, emailkit.ReachabilityUnknown, res.Reachability, "no mailbox was probed")
+
+	res, err = emailkit.New().WithDomain().Validate(ctx, "alice@gmial.com")
+	require.NoError(t, err)
+	assert.Equal(t, emailkit.ReachabilityRisky, res.Reachability, "a suggested correction")
+
+	parked := fixedChecker{res: emailkit.CheckResult{Level: emailkit.LevelNS, Passed: true, Code: emailkit.CodeParked}}
+	res, err = emailkit.New().With(parked).Validate(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, emailkit.ReachabilityRisky, res.Reachability)
+
+	res, err = emailkit.New().WithAllowlist([]string{"example.com"}, nil).With(parked).Validate(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, emailkit.ReachabilityDeliverable, res.Reachability)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	res, err = emailkit.New().Validate(cancelled, "alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Truncated)
+	assert.Equal(t, emailkit.ReachabilityUnknown, res.Reachability)
+}
@@ -0,0 +1,154 @@
+package emailkit
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/optimode/emailkit/internal/ratelimit"
+)
+
+// readerBatch is the default chunk size of ValidateReader.
+const readerBatch = 1000
+
+// ValidateReader validates the addresses read from r and yields each Result
+// in input order. r holds one address per line, or CSV as exported by
+// marketing tools and database dumps: the address is the first field
+// containing an "@" (quoted fields are unquoted), a leading header row
+// without one is skipped, and blank lines and lines starting with '#' are
+// ignored. A row with no "@" in any field is validated as its first field.
+//
+// The input is read and validated in chunks of opts.MaxBatch addresses
+// (default 1000) with ValidateMany's concurrency, rate limiting, prefilter
+// and canaries, so memory stays bounded however long r is. OnBatch is not
+// used: results are yielded instead.
+//
+// Closing opts.Stop (e.g. on SIGINT) lets the validations in progress
+// finish, yields their results, and ends with a *StoppedError whose
+// Checkpoint, passed back as opts.Resume with the same input, skips the
+// addresses already validated.
+//
+// The error is non-nil for configuration errors, read errors, when ctx is
+// done before a chunk starts (yielded with its first email, marked
+// Truncated) and when a chunk returns one (a misclassified canary, ctx
+// expiring mid-chunk; yielded after the chunk's results). It is yielded
+// once and iteration stops.
+func (v *Validator) ValidateReader(ctx context.Context, r io.Reader, opts ...ConcurrencyOptions) iter.Seq2[Result, error] {
+	return func(yield func(Result, error) bool) {
+		if v.err != nil {
+			yield(Result{}, v.err)
+			return
+		}
+		var o ConcurrencyOptions
+		if len(opts) > 0 {
+			o = opts[0]
+		}
+		var limiter *ratelimit.Limiter
+		if o.MaxQPS > 0 {
+			limiter = ratelimit.New(o.MaxQPS, 1)
+		}
+		size := o.MaxBatch
+		if size <= 0 {
+			size = readerBatch
+		}
+
+		// flush validates and yields a chunk; idx holds the input index of
+		// each of its emails. false stops the iteration.
+		flush := func(chunk []string, idx []int) bool {
+			if len(chunk) == 0 {
+				return true
+			}
+			if err := ctx.Err(); err != nil {
+				yield(v.anonymize(Result{Email: chunk[0], Truncated: true, Reachability: ReachabilityUnknown}), err)
+				return false
+			}
+			if isClosed(o.Stop) {
+				yield(Result{}, &StoppedError{Checkpoint: Checkpoint{Next: idx[0]}.resumed(o.Resume)})
+				return false
+			}
+			results, stopped, err := v.validateBatch(ctx, chunk, o, limiter)
+			for i, res := range results {
+				if stopped != nil && stopped[i] {
+					continue
+				}
+				if !yield(res, nil) {
+					return false
+				}
+			}
+			if stopped != nil {
+				cp := Checkpoint{Next: idx[len(idx)-1] + 1}
+				for i, s := range stopped {
+					if s {
+						cp.Pending = append(cp.Pending, idx[i])
+					}
+				}
+				err = &StoppedError{Checkpoint: cp.resumed(o.Resume)}
+			}
+			if err != nil {
+				yield(Result{}, err)
+				return false
+			}
+			return true
+		}
+
+		cr := csv.NewReader(r)
+		cr.Comment = '#'
+		cr.FieldsPerRecord = -1
+		cr.LazyQuotes = true
+		cr.TrimLeadingSpace = true
+		cr.ReuseRecord = true
+
+		chunk := make([]string, 0, size)
+		idx := make([]int, 0, size)
+		first := true
+		for n := 0; ; {
+			record, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				if flush(chunk, idx) {
+					yield(Result{}, fmt.Errorf("emailkit: read emails: %w", err))
+				}
+				return
+			}
+			email, ok := recordEmail(record)
+			if first {
+				first = false
+				email = strings.TrimPrefix(email, "\ufeff") // UTF-8 BOM
+				if !ok {
+					continue // header row
+				}
+			}
+			i := n
+			n++
+			if o.Resume != nil && o.Resume.Done(i) {
+				continue
+			}
+			chunk = append(chunk, email)
+			idx = append(idx, i)
+			if len(chunk) == size {
+				if !flush(chunk, idx) {
+					return
+				}
+				chunk, idx = chunk[:0], idx[:0]
+			}
+		}
+		flush(chunk, idx)
+	}
+}
+
+// recordEmail picks the address out of a CSV record: the first field
+// containing "@", else the first field. ok is false if no field has "@".
+func recordEmail(record []string) (email string, ok bool) {
+	for _, f := range record {
+		if f = strings.TrimSpace(f); strings.Contains(f, "@") {
+			return f, true
+		}
+	}
+	return strings.TrimSpace(record[0]), false
+}
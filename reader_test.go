@@ -0,0 +1,99 @@
+package emailkit_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+func TestValidateReader_Lines(t *testing.T) {
+	v := emailkit.New()
+	input := "alice@example.com\n\n# comment\r\n  bob@example.com  \ninvalid\n"
+
+	var got []string
+	for res, err := range v.ValidateReader(context.Background(), strings.NewReader(input), emailkit.ConcurrencyOptions{MaxBatch: 2}) {
+		require.NoError(t, err)
+		got = append(got, res.Email+" "+map[bool]string{true: "valid", false: "invalid"}[res.Valid])
+	}
+	assert.Equal(t, []string{"alice@example.com valid", "bob@example.com valid", "invalid invalid"}, got)
+}
+
+func TestValidateReader_CSV(t *testing.T) {
+	v := emailkit.New()
+	input := "\ufeffname,email,signup\n" +
+		"Alice,alice@example.com,2026-01-02\n" +
+		"\"Doe, Bob\",\"bob@example.com\",2026-01-03\n" +
+		"Carol,,2026-01-04\n"
+
+	var emails []string
+	for res, err := range v.ValidateReader(context.Background(), strings.NewReader(input)) {
+		require.NoError(t, err)
+		emails = append(emails, res.Email)
+	}
+	// The header is skipped; a row without an address is validated as its first field
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com", "Carol"}, emails)
+}
+
+type failingReader struct{ data string }
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, errors.New("disk on fire")
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestValidateReader_ReadError(t *testing.T) {
+	v := emailkit.New()
+	var emails []string
+	var gotErr error
+	for res, err := range v.ValidateReader(context.Background(), &failingReader{data: "alice@example.com\n"}) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		emails = append(emails, res.Email)
+	}
+	assert.Equal(t, []string{"alice@example.com"}, emails, "addresses read before the error are validated")
+	require.Error(t, gotErr)
+	assert.ErrorContains(t, gotErr, "emailkit: read emails: ")
+	assert.Contains(t, gotErr.Error(), "disk on fire")
+}
+
+func TestValidateReader_CancelledContext(t *testing.T) {
+	v := emailkit.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var results []emailkit.Result
+	var gotErr error
+	for res, err := range v.ValidateReader(ctx, strings.NewReader("a@example.com\nb@example.com\nc@example.com\n"), emailkit.ConcurrencyOptions{MaxBatch: 2}) {
+		if err != nil {
+			gotErr = err
+			assert.Equal(t, "a@example.com", res.Email)
+			assert.True(t, res.Truncated)
+			continue
+		}
+		results = append(results, res)
+	}
+	require.ErrorIs(t, gotErr, context.Canceled)
+	assert.Empty(t, results)
+}
+
+func TestValidateReader_Break(t *testing.T) {
+	v := emailkit.New()
+	n := 0
+	for range v.ValidateReader(context.Background(), strings.NewReader("a@example.com\nb@example.com\nc@example.com\n")) {
+		n++
+		break
+	}
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,172 @@
+package redisstore
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// errNil is the reply to a GET on a missing key.
+var errNil = errors.New("redisstore: nil reply")
+
+// conn is one RESP connection.
+type conn struct {
+	netConn net.Conn
+	r       *bufio.Reader
+	w       *bufio.Writer
+}
+
+// get returns an idle connection or dials a new one.
+func (s *Store) get(ctx context.Context) (*conn, error) {
+	select {
+	case c := <-s.idle:
+		return c, nil
+	default:
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, s.opts.DialTimeout)
+	defer cancel()
+	nc, err := s.opts.Dial(dialCtx, "tcp", s.opts.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("redisstore: connect to %s: %w", s.opts.Addr, err)
+	}
+	c := &conn{netConn: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+	if s.opts.Password != "" {
+		if _, err := c.do(ctx, s.opts.Timeout, "AUTH", s.opts.Password); err != nil {
+			_ = nc.Close()
+			return nil, err
+		}
+	}
+	if s.opts.DB != 0 {
+		if _, err := c.do(ctx, s.opts.Timeout, "SELECT", strconv.Itoa(s.opts.DB)); err != nil {
+			_ = nc.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// put returns a healthy connection to the idle list.
+func (s *Store) put(c *conn) {
+	select {
+	case s.idle <- c:
+	default:
+		_ = c.netConn.Close()
+	}
+}
+
+// do runs one command on a pooled connection.
+func (s *Store) do(ctx context.Context, args ...string) (any, error) {
+	c, err := s.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := c.do(ctx, s.opts.Timeout, args...)
+	var redisErr redisError
+	if err != nil && !errors.Is(err, errNil) && !errors.As(err, &redisErr) {
+		// Broken connection: the reply stream can't be trusted anymore
+		_ = c.netConn.Close()
+		return nil, err
+	}
+	s.put(c)
+	return reply, err
+}
+
+// redisError is an error reply ("-ERR ...").
+type redisError string
+
+func (e redisError) Error() string { return "redisstore: " + string(e) }
+
+// do writes a command and reads its reply.
+func (c *conn) do(ctx context.Context, timeout time.Duration, args ...string) (any, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.netConn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, fmt.Errorf("redisstore: write: %w", err)
+	}
+	return readReply(c.r)
+}
+
+// readReply parses one RESP2 reply: string, int64, []any, or an error.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redisstore: read: %w", err)
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redisstore: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		n, err := strconv.ParseInt(body, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("redisstore: malformed integer %q", body)
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redisstore: malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, errNil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("redisstore: read: %w", err)
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redisstore: malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, errNil
+		}
+		// An error element (e.g. in an EXEC reply) is returned once the
+		// whole array is read, so the connection stays in sync
+		out := make([]any, n)
+		var elemErr error
+		for i := range out {
+			v, err := readReply(r)
+			var redisErr redisError
+			switch {
+			case err == nil, errors.Is(err, errNil):
+				out[i] = v
+			case errors.As(err, &redisErr):
+				if elemErr == nil {
+					elemErr = err
+				}
+			default:
+				return nil, err
+			}
+		}
+		if elemErr != nil {
+			return nil, elemErr
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("redisstore: unknown reply type %q", kind)
+}
@@ -0,0 +1,22 @@
+package redisstore_test
+
+import (
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/redisstore"
+)
+
+func ExampleNew() {
+	store := redisstore.New(redisstore.Options{Addr: "redis.internal:6379"})
+	defer func() { _ = store.Close() }()
+
+	v := emailkit.New().
+		WithDNS().
+		WithMXStore(store).
+		WithSMTP(emailkit.SMTPOptions{
+			HeloDomain: "myapp.com",
+			MailFrom:   "verify@myapp.com",
+			Greylist:   store,
+			Limiter:    store.Limiter(map[string]float64{"gmail": 5}, 2),
+		})
+	defer v.Close()
+}
@@ -0,0 +1,240 @@
+// Package redisstore implements emailkit's shared-state interfaces on
+// Redis, so a horizontally scaled fleet of validation workers shares what
+// it learns and paces its probes together instead of each process
+// hammering the same providers independently:
+//
+//   - MX lookups: Store implements emailkit.MXStore (Validator.WithMXStore)
+//   - probe pacing: Store.Limiter returns an emailkit.ProbeLimiter
+//     (SMTPOptions.Limiter)
+//   - greylisting suppression: Store implements emailkit.GreylistStore
+//     (SMTPOptions.Greylist)
+//
+// The package speaks RESP directly and adds no dependencies. It needs
+// Redis 5 or later (the limiter script reads the server clock).
+package redisstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/internal/features"
+)
+
+func init() {
+	features.Register(emailkit.Feature{
+		Name:        "redisstore",
+		Stability:   emailkit.StabilityStable,
+		Package:     "github.com/optimode/emailkit/redisstore",
+		Description: "Redis-backed MX cache, probe limiter and greylist store",
+	})
+}
+
+// Options configures a Store.
+type Options struct {
+	// Addr is the Redis address. Default: "localhost:6379"
+	Addr     string
+	Password string
+	DB       int
+	// Prefix is prepended to every key. Default: "emailkit:"
+	Prefix string
+	// DialTimeout bounds connecting. Default: 5s
+	DialTimeout time.Duration
+	// Timeout bounds each command. Default: 2s
+	Timeout time.Duration
+	// MaxIdle is the number of idle connections kept. Default: 4
+	MaxIdle int
+	// GreylistTTL is how long greylisting timestamps are kept. Default: 24h
+	GreylistTTL time.Duration
+	// Dial is injectable for testing. Defaults to net.Dialer.DialContext.
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// Store is a Redis-backed emailkit.MXStore and emailkit.GreylistStore.
+// It is safe for concurrent use.
+type Store struct {
+	opts Options
+	idle chan *conn
+}
+
+var (
+	_ emailkit.MXStore        = (*Store)(nil)
+	_ emailkit.MXStoreDeleter = (*Store)(nil)
+	_ emailkit.GreylistStore  = (*Store)(nil)
+)
+
+// New creates a Store. Connections are opened on first use.
+func New(opts Options) *Store {
+	if opts.Addr == "" {
+		opts.Addr = "localhost:6379"
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "emailkit:"
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Second
+	}
+	if opts.MaxIdle <= 0 {
+		opts.MaxIdle = 4
+	}
+	if opts.GreylistTTL <= 0 {
+		opts.GreylistTTL = 24 * time.Hour
+	}
+	if opts.Dial == nil {
+		var d net.Dialer
+		opts.Dial = d.DialContext
+	}
+	return &Store{opts: opts, idle: make(chan *conn, opts.MaxIdle)}
+}
+
+// Close closes the idle connections.
+func (s *Store) Close() error {
+	for {
+		select {
+		case c := <-s.idle:
+			_ = c.netConn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// LoadMX implements emailkit.MXStore.
+func (s *Store) LoadMX(ctx context.Context, domain string) (emailkit.DNSStateEntry, bool, error) {
+	reply, err := s.do(ctx, "GET", s.opts.Prefix+"mx:"+domain)
+	if errors.Is(err, errNil) {
+		return emailkit.DNSStateEntry{}, false, nil
+	}
+	if err != nil {
+		return emailkit.DNSStateEntry{}, false, err
+	}
+	var e emailkit.DNSStateEntry
+	if err := json.Unmarshal([]byte(reply.(string)), &e); err != nil {
+		return emailkit.DNSStateEntry{}, false, fmt.Errorf("redisstore: decode MX entry: %w", err)
+	}
+	return e, true, nil
+}
+
+// StoreMX implements emailkit.MXStore. The key expires with the entry.
+func (s *Store) StoreMX(ctx context.Context, e emailkit.DNSStateEntry) error {
+	ttl := time.Until(e.Expires)
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("redisstore: encode MX entry: %w", err)
+	}
+	_, err = s.do(ctx, "SET", s.opts.Prefix+"mx:"+e.Domain, string(data), "PX", millis(ttl))
+	return err
+}
+
+// DeleteMX implements emailkit.MXStoreDeleter.
+func (s *Store) DeleteMX(ctx context.Context, domain string) error {
+	_, err := s.do(ctx, "DEL", s.opts.Prefix+"mx:"+domain)
+	return err
+}
+
+// FirstGreylisted implements emailkit.GreylistStore.
+func (s *Store) FirstGreylisted(ctx context.Context, key string) (time.Time, bool, error) {
+	reply, err := s.do(ctx, "GET", s.opts.Prefix+"greylist:"+key)
+	if errors.Is(err, errNil) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	ns, err := strconv.ParseInt(reply.(string), 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("redisstore: decode greylist entry: %w", err)
+	}
+	return time.Unix(0, ns), true, nil
+}
+
+// MarkGreylisted implements emailkit.GreylistStore. The first writer in
+// the fleet wins (SET NX).
+func (s *Store) MarkGreylisted(ctx context.Context, key string, at time.Time) error {
+	_, err := s.do(ctx, "SET", s.opts.Prefix+"greylist:"+key, strconv.FormatInt(at.UnixNano(), 10),
+		"PX", millis(s.opts.GreylistTTL), "NX")
+	if errors.Is(err, errNil) {
+		return nil // already marked
+	}
+	return err
+}
+
+// Clear implements emailkit.GreylistStore.
+func (s *Store) Clear(ctx context.Context, key string) error {
+	_, err := s.do(ctx, "DEL", s.opts.Prefix+"greylist:"+key)
+	return err
+}
+
+// gcraScript reserves the next slot of a GCRA rate limiter and returns
+// how many milliseconds the caller must wait for it. ARGV[1] is the
+// emission interval in milliseconds.
+const gcraScript = `local t = redis.call('TIME')
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+local interval = tonumber(ARGV[1])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then tat = now end
+redis.call('SET', KEYS[1], tat + interval, 'PX', tat - now + interval)
+return tat - now`
+
+// Limiter returns an emailkit.ProbeLimiter that paces probes fleet-wide
+// to rates[key] per second, or defaultRate for keys not in rates. A rate
+// <= 0 leaves the key unpaced.
+func (s *Store) Limiter(rates map[string]float64, defaultRate float64) emailkit.ProbeLimiter {
+	return &limiter{s: s, rates: rates, defaultRate: defaultRate}
+}
+
+type limiter struct {
+	s           *Store
+	rates       map[string]float64
+	defaultRate float64
+}
+
+// Wait implements emailkit.ProbeLimiter.
+func (l *limiter) Wait(ctx context.Context, key string) error {
+	rate, ok := l.rates[key]
+	if !ok {
+		rate = l.defaultRate
+	}
+	if rate <= 0 {
+		return nil
+	}
+	interval := max(1, int64(math.Round(1000/rate)))
+
+	reply, err := l.s.do(ctx, "EVAL", gcraScript, "1", l.s.opts.Prefix+"rate:"+key, strconv.FormatInt(interval, 10))
+	if err != nil {
+		return err
+	}
+	wait, ok := reply.(int64)
+	if !ok {
+		return fmt.Errorf("redisstore: unexpected limiter reply %v", reply)
+	}
+	if wait <= 0 {
+		return nil
+	}
+	t := time.NewTimer(time.Duration(wait) * time.Millisecond)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		// The reserved slot is not given back: other workers simply see a
+		// slightly slower rate, which is the safe direction
+		return ctx.Err()
+	}
+}
+
+func millis(d time.Duration) string {
+	return strconv.FormatInt(max(1, d.Milliseconds()), 10)
+}
@@ -0,0 +1,321 @@
+package redisstore_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/redisstore"
+)
+
+// fakeRedis is an in-process RESP server implementing the handful of
+// commands the store uses. EVAL is emulated in Go (the GCRA script).
+type fakeRedis struct {
+	mu       sync.Mutex
+	data     map[string]string
+	ttl      map[string]int64 // key -> PX of the last SET
+	password string
+	commands []string
+	dials    int
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{data: map[string]string{}, ttl: map[string]int64{}}
+}
+
+func (f *fakeRedis) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	f.mu.Lock()
+	f.dials++
+	f.mu.Unlock()
+	client, server := net.Pipe()
+	go f.serve(server)
+	return client, nil
+}
+
+func (f *fakeRedis) serve(c net.Conn) {
+	defer func() { _ = c.Close() }()
+	r := bufio.NewReader(c)
+	authed := f.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, args[0])
+		var reply string
+		switch {
+		case args[0] == "AUTH":
+			if args[1] == f.password {
+				authed = true
+				reply = "+OK\r\n"
+			} else {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			reply = "-NOAUTH Authentication required.\r\n"
+		default:
+			reply = f.exec(args)
+		}
+		f.mu.Unlock()
+		if _, err := io.WriteString(c, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) exec(args []string) string {
+	switch args[0] {
+	case "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		v, ok := f.data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "SET":
+		key, val := args[1], args[2]
+		nx := false
+		for i := 3; i < len(args); i++ {
+			switch args[i] {
+			case "NX":
+				nx = true
+			case "PX":
+				i++
+				f.ttl[key], _ = strconv.ParseInt(args[i], 10, 64)
+			}
+		}
+		if _, exists := f.data[key]; exists && nx {
+			return "$-1\r\n"
+		}
+		f.data[key] = val
+		return "+OK\r\n"
+	case "DEL":
+		_, ok := f.data[args[1]]
+		delete(f.data, args[1])
+		if ok {
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	case "EVAL":
+		key := args[3]
+		if strings.HasSuffix(key, ":poison") {
+			return "*3\r\n:1\r\n-ERR script failed\r\n:2\r\n"
+		}
+		interval, _ := strconv.ParseInt(args[4], 10, 64)
+		now := time.Now().UnixMilli()
+		tat := now
+		if v, ok := f.data[key]; ok {
+			tat, _ = strconv.ParseInt(v, 10, 64)
+		}
+		tat = max(tat, now)
+		f.data[key] = strconv.FormatInt(tat+interval, 10)
+		return fmt.Sprintf(":%d\r\n", tat-now)
+	}
+	return "-ERR unknown command\r\n"
+}
+
+// readCommand parses one RESP array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		hdr, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(hdr[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func TestStore_MX(t *testing.T) {
+	f := newFakeRedis()
+	s := redisstore.New(redisstore.Options{Dial: f.dial})
+	defer func() { _ = s.Close() }()
+	ctx := context.Background()
+
+	_, ok, err := s.LoadMX(ctx, "example.com")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	want := emailkit.DNSStateEntry{
+		Domain:  "example.com",
+		MX:      []emailkit.MXRecord{{Host: "mx.example.com.", Pref: 10}},
+		Expires: time.Now().Add(time.Hour).Round(0).UTC(),
+	}
+	require.NoError(t, s.StoreMX(ctx, want))
+
+	got, ok, err := s.LoadMX(ctx, "example.com")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, want.MX, got.MX)
+	assert.True(t, want.Expires.Equal(got.Expires))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	assert.InDelta(t, time.Hour.Milliseconds(), f.ttl["emailkit:mx:example.com"], 1000)
+	assert.Equal(t, 1, f.dials, "connection is reused")
+}
+
+func TestStore_MXSkipsExpired(t *testing.T) {
+	f := newFakeRedis()
+	s := redisstore.New(redisstore.Options{Dial: f.dial})
+	ctx := context.Background()
+
+	require.NoError(t, s.StoreMX(ctx, emailkit.DNSStateEntry{Domain: "old.com", Expires: time.Now().Add(-time.Minute)}))
+	_, ok, err := s.LoadMX(ctx, "old.com")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestStore_DeleteMX(t *testing.T) {
+	f := newFakeRedis()
+	s := redisstore.New(redisstore.Options{Dial: f.dial})
+	ctx := context.Background()
+
+	require.NoError(t, s.StoreMX(ctx, emailkit.DNSStateEntry{Domain: "example.com", Expires: time.Now().Add(time.Hour)}))
+	require.NoError(t, s.DeleteMX(ctx, "example.com"))
+	_, ok, err := s.LoadMX(ctx, "example.com")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	require.NoError(t, s.DeleteMX(ctx, "missing.com"))
+}
+
+func TestStore_Greylist(t *testing.T) {
+	f := newFakeRedis()
+	s := redisstore.New(redisstore.Options{Dial: f.dial, Prefix: "fleet:"})
+	ctx := context.Background()
+
+	first := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, s.MarkGreylisted(ctx, "mx.example.com|a@example.com", first))
+	// A second worker marking later does not move the first timestamp
+	require.NoError(t, s.MarkGreylisted(ctx, "mx.example.com|a@example.com", first.Add(time.Minute)))
+
+	at, ok, err := s.FirstGreylisted(ctx, "mx.example.com|a@example.com")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, first.Equal(at))
+
+	require.NoError(t, s.Clear(ctx, "mx.example.com|a@example.com"))
+	_, ok, err = s.FirstGreylisted(ctx, "mx.example.com|a@example.com")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	assert.Equal(t, 24*time.Hour.Milliseconds(), f.ttl["fleet:greylist:mx.example.com|a@example.com"])
+}
+
+func TestStore_Auth(t *testing.T) {
+	f := newFakeRedis()
+	f.password = "secret"
+	ctx := context.Background()
+
+	s := redisstore.New(redisstore.Options{Dial: f.dial, Password: "secret", DB: 2})
+	_, _, err := s.LoadMX(ctx, "example.com")
+	require.NoError(t, err)
+
+	f.mu.Lock()
+	assert.Equal(t, []string{"AUTH", "SELECT", "GET"}, f.commands)
+	f.mu.Unlock()
+
+	bad := redisstore.New(redisstore.Options{Dial: f.dial, Password: "wrong"})
+	_, _, err = bad.LoadMX(ctx, "example.com")
+	assert.ErrorContains(t, err, "WRONGPASS")
+}
+
+func TestStore_DialError(t *testing.T) {
+	s := redisstore.New(redisstore.Options{
+		Addr: "redis.internal:6379",
+		Dial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+	})
+	_, _, err := s.LoadMX(context.Background(), "example.com")
+	assert.EqualError(t, err, "redisstore: connect to redis.internal:6379: connection refused")
+}
+
+func TestLimiter(t *testing.T) {
+	f := newFakeRedis()
+	s := redisstore.New(redisstore.Options{Dial: f.dial})
+	l := s.Limiter(map[string]float64{"gmail": 1, "free": 0}, 1000)
+	ctx := context.Background()
+
+	// The first gmail probe goes immediately, the second must wait ~1s
+	require.NoError(t, l.Wait(ctx, "gmail"))
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Wait(short, "gmail"), context.DeadlineExceeded)
+
+	// Unlisted keys use the default rate
+	for range 3 {
+		require.NoError(t, l.Wait(ctx, "example.com"))
+	}
+
+	// A zero rate never touches Redis
+	f.mu.Lock()
+	n := len(f.commands)
+	f.mu.Unlock()
+	require.NoError(t, l.Wait(ctx, "free"))
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	assert.Len(t, f.commands, n)
+	assert.Contains(t, f.data, "emailkit:rate:gmail")
+}
+
+func TestStore_ErrorInArray(t *testing.T) {
+	f := newFakeRedis()
+	s := redisstore.New(redisstore.Options{Dial: f.dial})
+	defer func() { _ = s.Close() }()
+	ctx := context.Background()
+
+	err := s.Limiter(nil, 1000).Wait(ctx, "poison")
+	assert.ErrorContains(t, err, "ERR script failed")
+
+	// The rest of the array was read: the pooled connection stays in sync
+	_, found, err := s.LoadMX(ctx, "example.com")
+	require.NoError(t, err)
+	assert.False(t, found)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	assert.Equal(t, 1, f.dials)
+}
+
+func TestFeatureRegistered(t *testing.T) {
+	var found bool
+	for _, f := range emailkit.Features() {
+		if f.Name == "redisstore" {
+			found = true
+			assert.Equal(t, emailkit.StabilityStable, f.Stability)
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,236 @@
+package emailkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// LevelFactory builds a Checker from its raw JSON options.
+// opts is nil when the level is enabled without options.
+type LevelFactory func(opts json.RawMessage) (Checker, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]LevelFactory)
+)
+
+// builtinLevels are configured through their With* methods and cannot be
+// registered by third parties.
+var builtinLevels = map[string]func(v *Validator, opts json.RawMessage) error{
+	LevelSyntax: func(*Validator, json.RawMessage) error { return nil }, // always on
+	LevelDNS: func(v *Validator, opts json.RawMessage) error {
+		o := defaultDNSOptions()
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithDNS(o)
+		return nil
+	},
+	LevelNS: func(v *Validator, opts json.RawMessage) error {
+		o := defaultNSOptions()
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithNS(o)
+		return nil
+	},
+	LevelRegistration: func(v *Validator, opts json.RawMessage) error {
+		o := defaultRegistrationOptions()
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithRegistration(o)
+		return nil
+	},
+	LevelGeo: func(v *Validator, opts json.RawMessage) error {
+		o := defaultGeoOptions()
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithGeo(o)
+		return nil
+	},
+	LevelCompliance: func(v *Validator, opts json.RawMessage) error {
+		var o ComplianceOptions
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithCompliancePolicy(o)
+		return nil
+	},
+	LevelSender: func(v *Validator, opts json.RawMessage) error {
+		o := defaultSenderOptions()
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithSender(o)
+		return nil
+	},
+	LevelRole: func(v *Validator, opts json.RawMessage) error {
+		var o RoleOptions
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithRole(o)
+		return nil
+	},
+	LevelFreeProvider: func(v *Validator, opts json.RawMessage) error {
+		var o FreeProviderOptions
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithFreeProvider(o)
+		return nil
+	},
+	LevelVerification: func(v *Validator, opts json.RawMessage) error {
+		var o VerificationOptions
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithVerification(o)
+		return nil
+	},
+	LevelDNSBL: func(v *Validator, opts json.RawMessage) error {
+		var o struct{ Zones []string }
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithDNSBL(o.Zones...)
+		return nil
+	},
+	LevelSPF: func(v *Validator, opts json.RawMessage) error {
+		var o SPFOptions
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithSPF(o)
+		return nil
+	},
+	LevelDomain: func(v *Validator, opts json.RawMessage) error {
+		o := defaultDomainOptions()
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithDomain(o)
+		return nil
+	},
+	LevelSMTP: func(v *Validator, opts json.RawMessage) error {
+		var o SMTPOptions
+		if err := decodeOptions(opts, &o); err != nil {
+			return err
+		}
+		v.WithSMTP(o)
+		return nil
+	},
+}
+
+// RegisterLevel makes a third-party validation level available by name,
+// so that users can enable it with WithLevel or in a PipelineConfig without
+// code changes in emailkit. It is meant to be called from the init function
+// of the package providing the level.
+// RegisterLevel panics if the name is empty, is a built-in level, or is
+// already registered, or if factory is nil.
+func RegisterLevel(name string, factory LevelFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" || factory == nil {
+		panic("emailkit: RegisterLevel requires a name and a factory")
+	}
+	if _, ok := builtinLevels[name]; ok {
+		panic("emailkit: RegisterLevel: " + name + " is a built-in level")
+	}
+	if _, dup := registry[name]; dup {
+		panic("emailkit: RegisterLevel called twice for level " + name)
+	}
+	registry[name] = factory
+}
+
+// RegisteredLevels returns the sorted names of all third-party levels.
+func RegisteredLevels() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PipelineConfig describes a validation pipeline by level names, e.g.
+// decoded from a JSON config file:
+//
+//	{"levels": [
+//	    {"name": "dns", "options": {"FallbackToA": true}},
+//	    {"name": "domain"},
+//	    {"name": "reputation", "options": {"apiKey": "..."}}
+//	]}
+//
+// Options of built-in levels use the field names of the matching Options
+// struct (durations in nanoseconds); unset fields keep their defaults.
+type PipelineConfig struct {
+	Levels []LevelConfig `json:"levels"`
+	// AutoOrder runs the levels ordered by cost rather than as listed
+	// (see Validator.WithAutoOrder).
+	AutoOrder bool `json:"auto_order,omitempty"`
+}
+
+// LevelConfig enables one level with optional raw JSON options.
+type LevelConfig struct {
+	Name    string          `json:"name"`
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// NewFromConfig creates a Validator with the levels listed in cfg, in order
+// unless cfg.AutoOrder is set.
+// Configuration errors (unknown level, bad options) are returned on Validate().
+func NewFromConfig(cfg PipelineConfig) *Validator {
+	v := New()
+	if cfg.AutoOrder {
+		v.WithAutoOrder()
+	}
+	for _, l := range cfg.Levels {
+		v.WithLevel(l.Name, l.Options)
+	}
+	return v
+}
+
+// WithLevel adds a level by name: either a built-in level ("dns", "ns",
+// "registration", "geo", "compliance", "sender", "role", "free_provider",
+// "verification", "dnsbl", "spf", "domain", "smtp"; "syntax" is always
+// on) or one registered with RegisterLevel. opts holds the level's JSON options and may be nil.
+func (v *Validator) WithLevel(name string, opts json.RawMessage) *Validator {
+	if build, ok := builtinLevels[name]; ok {
+		if err := build(v, opts); err != nil {
+			v.setErr(fmt.Errorf("emailkit: level %q options: %w", name, err))
+		}
+		return v
+	}
+
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		v.setErr(fmt.Errorf("%w: %q", ErrUnknownLevel, name))
+		return v
+	}
+
+	c, err := factory(opts)
+	if err != nil {
+		v.setErr(fmt.Errorf("emailkit: level %q: %w", name, err))
+		return v
+	}
+	return v.With(c)
+}
+
+// decodeOptions decodes raw JSON options over the defaults in dst.
+func decodeOptions(opts json.RawMessage, dst any) error {
+	if len(opts) == 0 {
+		return nil
+	}
+	return json.Unmarshal(opts, dst)
+}
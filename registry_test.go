@@ -0,0 +1,102 @@
+package emailkit_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit"
+)
+
+// suffixChecker is a third-party level rejecting domains with a given suffix.
+type suffixChecker struct{ suffix string }
+
+func (c suffixChecker) Check(_ context.Context, email emailkit.Email) emailkit.CheckResult {
+	if strings.HasSuffix(email.Domain, c.suffix) {
+		return emailkit.CheckResult{Level: "suffix", Passed: false, Details: "blocked suffix"}
+	}
+	return emailkit.CheckResult{Level: "suffix", Passed: true}
+}
+
+func init() {
+	emailkit.RegisterLevel("test-suffix", func(opts json.RawMessage) (emailkit.Checker, error) {
+		cfg := struct {
+			Suffix string `json:"suffix"`
+		}{Suffix: ".invalid"}
+		if len(opts) > 0 {
+			if err := json.Unmarshal(opts, &cfg); err != nil {
+				return nil, err
+			}
+		}
+		if cfg.Suffix == "" {
+			return nil, errors.New("suffix must not be empty")
+		}
+		return suffixChecker{suffix: cfg.Suffix}, nil
+	})
+}
+
+func TestWith_CustomChecker(t *testing.T) {
+	v := emailkit.New().With(suffixChecker{suffix: ".test"})
+
+	res, err := v.Validate(context.Background(), "user@example.test")
+	assert.NoError(t, err)
+	assert.False(t, res.Valid)
+	assert.Equal(t, "suffix", res.Checks[1].Level)
+}
+
+func TestNewFromConfig(t *testing.T) {
+	var cfg emailkit.PipelineConfig
+	err := json.Unmarshal([]byte(`{"levels": [
+		{"name": "domain", "options": {"CheckTypos": false}},
+		{"name": "test-suffix", "options": {"suffix": ".example"}}
+	]}`), &cfg)
+	assert.NoError(t, err)
+
+	v := emailkit.NewFromConfig(cfg)
+	ctx := context.Background()
+
+	// Disposable detection keeps its default (true)
+	res, err := v.Validate(ctx, "user@mailinator.com")
+	assert.NoError(t, err)
+	assert.False(t, res.Valid)
+	assert.Equal(t, emailkit.LevelDomain, res.Checks[1].Level)
+
+	// CheckTypos was overridden: no suggestion
+	res, err = v.Validate(ctx, "user@gmial.com")
+	assert.NoError(t, err)
+	assert.True(t, res.Valid)
+	assert.Empty(t, res.Checks[1].Suggestion)
+
+	res, err = v.Validate(ctx, "user@foo.example")
+	assert.NoError(t, err)
+	assert.False(t, res.Valid)
+	assert.Equal(t, "suffix", res.Checks[2].Level)
+}
+
+func TestWithLevel_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := emailkit.New().WithLevel("no-such-level", nil).Validate(ctx, "user@example.com")
+	assert.ErrorIs(t, err, emailkit.ErrUnknownLevel)
+
+	_, err = emailkit.New().WithLevel("test-suffix", json.RawMessage(`{"suffix": ""}`)).Validate(ctx, "user@example.com")
+	assert.ErrorContains(t, err, "suffix must not be empty")
+
+	_, err = emailkit.New().WithLevel("dns", json.RawMessage(`{"Timeout": "soon"}`)).Validate(ctx, "user@example.com")
+	assert.ErrorContains(t, err, `level "dns" options`)
+}
+
+func TestRegisterLevel_Panics(t *testing.T) {
+	factory := func(json.RawMessage) (emailkit.Checker, error) { return suffixChecker{}, nil }
+	assert.Panics(t, func() { emailkit.RegisterLevel("test-suffix", factory) })
+	assert.Panics(t, func() { emailkit.RegisterLevel("dns", factory) })
+	assert.Panics(t, func() { emailkit.RegisterLevel("", factory) })
+}
+
+func TestRegisteredLevels(t *testing.T) {
+	assert.Contains(t, emailkit.RegisteredLevels(), "test-suffix")
+}
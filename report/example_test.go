@@ -0,0 +1,44 @@
+package report_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/report"
+)
+
+func ExampleRender() {
+	results, err := emailkit.New().ValidateMany(context.Background(), []string{
+		"alice@example.com",
+		"not-an-address",
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	f, err := os.CreateTemp("", "list-quality-*.md")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	defer func() { _ = f.Close() }()
+	if err := report.Render(f, results, report.Markdown); err != nil {
+		fmt.Println(err)
+	}
+}
+
+func ExampleNewData() {
+	d := report.NewData([]emailkit.Result{
+		{Email: "alice@example.com", Valid: true},
+		{Email: "bob@example.com", Checks: []emailkit.CheckResult{{Level: emailkit.LevelSMTP, Code: emailkit.CodeMailboxUnknown}}},
+	})
+	fmt.Println(d.Summary.Invalid, d.Problems[0].Email, d.Codes[0].Name)
+	fmt.Println(d.Chart.JSON())
+	// Output:
+	// 1 bob@example.com mailbox_unknown
+	// {"verdicts":{"invalid":1,"mailbox_full":0,"truncated":0,"valid":1},"codes":[{"name":"mailbox_unknown","count":1}],"domains":[{"domain":"example.com","total":2,"valid":1,"failed":1}]}
+}
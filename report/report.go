@@ -0,0 +1,245 @@
+// Package report renders the results of a bulk run as a human-readable
+// list-quality report — summary tables, failure reasons, the domains with
+// the most problems and the addresses that didn't pass — for campaign
+// approvals and tickets:
+//
+//	results, _ := v.ValidateMany(ctx, emails)
+//	f, _ := os.Create("list-quality.html")
+//	defer f.Close()
+//	err := report.Render(f, results, report.HTML)
+//
+// Both built-in templates embed the numbers as JSON (see Chart), ready
+// for a charting library. For a template of your own, execute it on
+// NewData(results).
+package report
+
+import (
+	"cmp"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/internal/features"
+)
+
+func init() {
+	features.Register(emailkit.Feature{
+		Name:        "report",
+		Stability:   emailkit.StabilityStable,
+		Package:     "github.com/optimode/emailkit/report",
+		Description: "HTML and Markdown list-quality reports",
+	})
+}
+
+// Built-in template names for Render.
+const (
+	HTML     = "html"
+	Markdown = "markdown"
+)
+
+// MaxDomains is the number of domains listed in Data.Domains.
+const MaxDomains = 20
+
+// ErrUnknownTemplate is returned by Render for a template name other than
+// HTML or Markdown.
+var ErrUnknownTemplate = errors.New("report: unknown template")
+
+//go:embed templates
+var templateFS embed.FS
+
+var funcs = map[string]any{
+	"pct": func(f float64) string { return fmt.Sprintf("%.1f%%", 100*f) },
+	// cell escapes a Markdown table cell.
+	"cell": func(s string) string {
+		return strings.NewReplacer("|", `\|`, "<", "&lt;", ">", "&gt;", "\n", " ", "\r", "").Replace(s)
+	},
+}
+
+var (
+	htmlTemplate     = htmltemplate.Must(htmltemplate.New("report.html.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/report.html.tmpl"))
+	markdownTemplate = texttemplate.Must(texttemplate.New("report.md.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/report.md.tmpl"))
+)
+
+// Render writes the report on results to w with a built-in template:
+// HTML (a self-contained page) or Markdown.
+func Render(w io.Writer, results []emailkit.Result, templateName string) error {
+	d := NewData(results)
+	switch templateName {
+	case HTML:
+		return htmlTemplate.Execute(w, d)
+	case Markdown:
+		return markdownTemplate.Execute(w, d)
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownTemplate, templateName)
+	}
+}
+
+// Data is what the templates render.
+type Data struct {
+	Generated time.Time
+	Summary   emailkit.Summary
+	// Codes counts the reasons of invalid results (Summary.Codes), most
+	// frequent first.
+	Codes []Count
+	// Domains lists up to MaxDomains domains with the most failed
+	// results, then the most results. Addresses hashed in privacy mode
+	// have no domain and are left out.
+	Domains []DomainCount
+	// Problems are the results that are not valid, in input order.
+	Problems []Row
+	// Chart holds the numbers of the report for charting; see Chart.JSON.
+	Chart Chart
+}
+
+// Count is a named tally.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// DomainCount tallies the results of one domain.
+type DomainCount struct {
+	Domain string `json:"domain"`
+	Total  int    `json:"total"`
+	Valid  int    `json:"valid"`
+	Failed int    `json:"failed"` // invalid, mailbox full or truncated
+}
+
+// Row is one result in the report.
+type Row struct {
+	Email string
+	// Verdict is "valid", "invalid", "mailbox full" or "truncated".
+	Verdict string
+	// Code is the code of the first failed check, or its level if it has
+	// no code.
+	Code        string
+	Explanation string
+	Probability float64
+}
+
+// Chart is the charts-ready form of a report.
+type Chart struct {
+	Verdicts map[string]int `json:"verdicts"`
+	Codes    []Count        `json:"codes"`
+	Domains  []DomainCount  `json:"domains"`
+}
+
+// JSON returns c encoded as JSON.
+func (c Chart) JSON() string {
+	b, _ := json.Marshal(c) // only maps, slices, strings and ints
+	return string(b)
+}
+
+// NewData computes the report data on results.
+func NewData(results []emailkit.Result) Data {
+	d := Data{Generated: time.Now().UTC(), Summary: emailkit.Summarize(results)}
+	for name, n := range d.Summary.Codes {
+		d.Codes = append(d.Codes, Count{Name: name, Count: n})
+	}
+	slices.SortFunc(d.Codes, func(a, b Count) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Name, b.Name))
+	})
+
+	domains := make(map[string]*DomainCount)
+	for _, r := range results {
+		row := newRow(r)
+		if row.Verdict != "valid" {
+			d.Problems = append(d.Problems, row)
+		}
+		at := strings.LastIndexByte(r.Email, '@')
+		if at < 0 || at == len(r.Email)-1 {
+			continue
+		}
+		domain := strings.ToLower(r.Email[at+1:])
+		dc, ok := domains[domain]
+		if !ok {
+			dc = &DomainCount{Domain: domain}
+			domains[domain] = dc
+		}
+		dc.Total++
+		if row.Verdict == "valid" {
+			dc.Valid++
+		} else {
+			dc.Failed++
+		}
+	}
+	for _, dc := range domains {
+		d.Domains = append(d.Domains, *dc)
+	}
+	slices.SortFunc(d.Domains, func(a, b DomainCount) int {
+		return cmp.Or(cmp.Compare(b.Failed, a.Failed), cmp.Compare(b.Total, a.Total), cmp.Compare(a.Domain, b.Domain))
+	})
+	if len(d.Domains) > MaxDomains {
+		d.Domains = d.Domains[:MaxDomains]
+	}
+
+	d.Chart = Chart{
+		Verdicts: map[string]int{
+			"valid":        d.Summary.Valid,
+			"invalid":      d.Summary.Invalid,
+			"mailbox_full": d.Summary.MailboxFull,
+			"truncated":    d.Summary.Truncated,
+		},
+		Codes:   d.Codes,
+		Domains: d.Domains,
+	}
+	if d.Chart.Codes == nil {
+		d.Chart.Codes = []Count{}
+	}
+	if d.Chart.Domains == nil {
+		d.Chart.Domains = []DomainCount{}
+	}
+	return d
+}
+
+// ValidRate returns the share of valid results.
+func (d Data) ValidRate() float64 { return d.rate(d.Summary.Valid) }
+
+// InvalidRate returns the share of invalid results.
+func (d Data) InvalidRate() float64 { return d.rate(d.Summary.Invalid) }
+
+// MailboxFullRate returns the share of results with a full mailbox.
+func (d Data) MailboxFullRate() float64 { return d.rate(d.Summary.MailboxFull) }
+
+// TruncatedRate returns the share of truncated results.
+func (d Data) TruncatedRate() float64 { return d.rate(d.Summary.Truncated) }
+
+// WarnRate returns the share of valid results with caveats.
+func (d Data) WarnRate() float64 { return d.rate(d.Summary.Warn) }
+
+func (d Data) rate(n int) float64 {
+	if d.Summary.Total == 0 {
+		return 0
+	}
+	return float64(n) / float64(d.Summary.Total)
+}
+
+func newRow(r emailkit.Result) Row {
+	row := Row{Email: r.Email, Explanation: r.Explanation(), Probability: r.DeliverabilityProbability}
+	s := emailkit.Summarize([]emailkit.Result{r})
+	switch {
+	case s.Truncated > 0:
+		row.Verdict = "truncated"
+	case s.MailboxFull > 0:
+		row.Verdict = "mailbox full"
+	case s.Invalid > 0:
+		row.Verdict = "invalid"
+	default:
+		row.Verdict = "valid"
+	}
+	for _, c := range r.Checks {
+		if !c.Passed {
+			row.Code = cmp.Or(c.Code, c.Level)
+			break
+		}
+	}
+	return row
+}
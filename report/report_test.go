@@ -0,0 +1,119 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/report"
+)
+
+func valid(email string) emailkit.Result {
+	return emailkit.Result{Email: email, Valid: true, Checks: []emailkit.CheckResult{{Level: emailkit.LevelSyntax, Passed: true}}}
+}
+
+func failed(email string, c emailkit.CheckResult) emailkit.Result {
+	return emailkit.Result{Email: email, Checks: []emailkit.CheckResult{{Level: emailkit.LevelSyntax, Passed: true}, c}}
+}
+
+func results() []emailkit.Result {
+	return []emailkit.Result{
+		valid("a@example.com"),
+		valid("b@Example.com"),
+		failed("c@example.com", emailkit.CheckResult{Level: emailkit.LevelSMTP, Code: emailkit.CodeMailboxUnknown, SMTPCode: 550}),
+		failed("d@gone.example", emailkit.CheckResult{Level: emailkit.LevelDNS, Details: "no MX records"}),
+		failed("e@full.example", emailkit.CheckResult{Level: emailkit.LevelSMTP, Code: emailkit.CodeMailboxFull, SMTPCode: 452}),
+		{Email: "f@slow.example", Truncated: true},
+		failed("<b>x|y</b>@gone.example", emailkit.CheckResult{Level: emailkit.LevelDNS}),
+	}
+}
+
+func TestNewData(t *testing.T) {
+	d := report.NewData(results())
+
+	assert.Equal(t, 7, d.Summary.Total)
+	assert.InDelta(t, 2.0/7, d.ValidRate(), 1e-9)
+	assert.InDelta(t, 3.0/7, d.InvalidRate(), 1e-9)
+	assert.Equal(t, []report.Count{{Name: emailkit.LevelDNS, Count: 2}, {Name: emailkit.CodeMailboxUnknown, Count: 1}}, d.Codes)
+
+	require.Len(t, d.Domains, 4)
+	assert.Equal(t, report.DomainCount{Domain: "gone.example", Total: 2, Failed: 2}, d.Domains[0])
+	assert.Equal(t, report.DomainCount{Domain: "example.com", Total: 3, Valid: 2, Failed: 1}, d.Domains[1])
+
+	require.Len(t, d.Problems, 5)
+	assert.Equal(t, "c@example.com", d.Problems[0].Email)
+	assert.Equal(t, "invalid", d.Problems[0].Verdict)
+	assert.Equal(t, emailkit.CodeMailboxUnknown, d.Problems[0].Code)
+	assert.Equal(t, emailkit.LevelDNS, d.Problems[1].Code)
+	assert.Equal(t, "mailbox full", d.Problems[2].Verdict)
+	assert.Equal(t, "truncated", d.Problems[3].Verdict)
+	assert.NotEmpty(t, d.Problems[0].Explanation)
+
+	var chart map[string]any
+	require.NoError(t, json.Unmarshal([]byte(d.Chart.JSON()), &chart))
+	assert.Equal(t, map[string]any{"valid": 2.0, "invalid": 3.0, "mailbox_full": 1.0, "truncated": 1.0}, chart["verdicts"])
+}
+
+func TestNewData_MaxDomains(t *testing.T) {
+	var rs []emailkit.Result
+	for i := range report.MaxDomains + 5 {
+		rs = append(rs, valid(fmt.Sprintf("u@d%02d.example", i)))
+	}
+	rs = append(rs, valid("hashed-address-without-domain"))
+	d := report.NewData(rs)
+	assert.Len(t, d.Domains, report.MaxDomains)
+	assert.Empty(t, d.Problems)
+}
+
+func TestRender_HTML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.Render(&buf, results(), report.HTML))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.Contains(t, out, `<td>Invalid</td><td class="n">3</td><td class="n">42.9%</td>`)
+	assert.Contains(t, out, "<code>mailbox_unknown</code>")
+	assert.Contains(t, out, "&lt;b&gt;x|y&lt;/b&gt;@gone.example")
+	assert.NotContains(t, out, "<b>x")
+
+	_, chart, ok := strings.Cut(out, `<script type="application/json" id="emailkit-chart-data">`)
+	require.True(t, ok)
+	chart, _, _ = strings.Cut(chart, "</script>")
+	var c report.Chart
+	require.NoError(t, json.Unmarshal([]byte(chart), &c))
+	assert.Equal(t, 2, c.Verdicts["valid"])
+}
+
+func TestRender_Markdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.Render(&buf, results(), report.Markdown))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "# Email list quality report"))
+	assert.Contains(t, out, "| Invalid | 3 | 42.9% |")
+	assert.Contains(t, out, "| `dns` | 2 |")
+	assert.Contains(t, out, "| gone.example | 2 | 0 | 2 |")
+	assert.Contains(t, out, `| &lt;b&gt;x\|y&lt;/b&gt;@gone.example | invalid | `+"`dns`")
+	assert.Contains(t, out, "```json\n{\"verdicts\":")
+}
+
+func TestRender_Empty(t *testing.T) {
+	for _, name := range []string{report.HTML, report.Markdown} {
+		var buf bytes.Buffer
+		require.NoError(t, report.Render(&buf, nil, name))
+		assert.Contains(t, buf.String(), "for 0 addresses")
+		assert.NotContains(t, buf.String(), "did not pass")
+	}
+}
+
+func TestRender_UnknownTemplate(t *testing.T) {
+	err := report.Render(&bytes.Buffer{}, results(), "pdf")
+	assert.ErrorIs(t, err, report.ErrUnknownTemplate)
+	assert.Contains(t, err.Error(), `"pdf"`)
+}
@@ -1,11 +1,40 @@
 package emailkit
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 // Result is the full outcome of an email validation.
 // The Valid field is true only if all configured checks passed.
+// Truncated is true when the context was cancelled before the pipeline
+// finished; such results are never Valid, and the check that was cut short
+// (if any) carries CodeCancelled. Use it to tell "invalid" from
+// "we didn't finish checking".
+//
+// Reachability grades the outcome beyond Valid: Deliverable,
+// Undeliverable, Risky or Unknown (see Reachability for the rules).
+//
+// DeliverabilityProbability (0..1) is only set when WithCalibration is
+// enabled. It is 0 for truncated results and for definitive failures;
+// inconclusive SMTP outcomes (greylisting, temporary failures) keep a
+// non-zero probability even though Valid is false.
+//
+// RiskScore (0..100, higher is riskier) is only set when WithScoring is
+// enabled; see ScoringOptions for its signals.
+//
+// FreeProvider is true when the free_provider level (WithFreeProvider)
+// matched the domain.
 type Result struct {
-	Email  string        `json:"email"`
-	Valid  bool          `json:"valid"`
-	Checks []CheckResult `json:"checks"`
+	Email                     string        `json:"email"`
+	Valid                     bool          `json:"valid"`
+	Truncated                 bool          `json:"truncated,omitempty"`
+	Reachability              Reachability  `json:"reachability,omitempty"`
+	DeliverabilityProbability float64       `json:"deliverabilityProbability,omitempty"`
+	RiskScore                 int           `json:"riskScore,omitempty"`
+	FreeProvider              bool          `json:"freeProvider,omitempty"`
+	Checks                    []CheckResult `json:"checks"`
 }
 
 // FailedChecks returns those CheckResults that did not pass.
@@ -29,3 +58,341 @@ func (r Result) CheckFor(level CheckLevel) (CheckResult, bool) {
 	}
 	return CheckResult{}, false
 }
+
+// hasCode reports whether a check of r carries code.
+func (r Result) hasCode(code CheckCode) bool {
+	for _, c := range r.Checks {
+		if c.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Severity grades the whole result: SeverityError for an invalid address,
+// SeverityWarn for a truncated run, a full mailbox (temporarily
+// undeliverable, not invalid) or a valid address with caveats (a
+// suggestion or a reason code on a passing check), SeverityInfo otherwise.
+func (r Result) Severity() Severity {
+	if r.Truncated {
+		return SeverityWarn
+	}
+	sev := SeverityInfo
+	for _, c := range r.Checks {
+		switch checkSeverity(c) {
+		case SeverityError:
+			return SeverityError
+		case SeverityWarn:
+			sev = SeverityWarn
+		}
+	}
+	if !r.Valid && sev == SeverityInfo {
+		return SeverityError
+	}
+	return sev
+}
+
+// checkSeverity grades a single check: failures are errors (except
+// cancellations and full mailboxes), passes with a suggestion or code are
+// warnings (except allowlist matches).
+func checkSeverity(c CheckResult) Severity {
+	switch {
+	case !c.Passed && (c.Code == CodeCancelled || c.Code == CodeMailboxFull):
+		return SeverityWarn
+	case !c.Passed:
+		return SeverityError
+	case c.Code == CodeAllowlisted:
+		return SeverityInfo
+	case c.Suggestion != "" || c.Code != "":
+		return SeverityWarn
+	default:
+		return SeverityInfo
+	}
+}
+
+// codeCategories categorizes checks by code, before their level.
+var codeCategories = map[CheckCode]Category{
+	CodeAllowlisted:    CategoryPolicy,
+	CodeBlocklisted:    CategoryPolicy,
+	CodeGeoBlocked:     CategoryPolicy,
+	CodeSanctioned:     CategoryPolicy,
+	CodeRestricted:     CategoryPolicy,
+	CodeSenderRole:     CategoryPolicy,
+	CodeRole:           CategoryPolicy,
+	CodeFreeProvider:   CategoryPolicy,
+	CodeTokenMissing:   CategoryPolicy,
+	CodeDNSBLListed:    CategoryReputation,
+	CodeSPFMissing:     CategoryInfrastructure,
+	CodeParked:         CategoryReputation,
+	CodeSenderNoBounce: CategoryInfrastructure,
+	CodeSenderSPFFail:  CategoryInfrastructure,
+	CodeSenderNoDMARC:  CategoryInfrastructure,
+}
+
+// levelCategories categorizes checks by level. Levels not listed, e.g.
+// LevelPipeline or third-party ones, get no category unless they set one.
+var levelCategories = map[CheckLevel]Category{
+	LevelSyntax:       CategorySyntax,
+	LevelDomain:       CategorySyntax,
+	LevelDNS:          CategoryInfrastructure,
+	LevelNS:           CategoryInfrastructure,
+	LevelSMTP:         CategoryInfrastructure,
+	LevelRegistration: CategoryInfrastructure,
+	LevelGeo:          CategoryPolicy,
+	LevelCompliance:   CategoryPolicy,
+	LevelSender:       CategoryPolicy,
+	LevelRole:         CategoryPolicy,
+	LevelFreeProvider: CategoryPolicy,
+	LevelVerification: CategoryPolicy,
+	LevelDNSBL:        CategoryReputation,
+	LevelSPF:          CategoryInfrastructure,
+	LevelAllowlist:    CategoryPolicy,
+	LevelBlocklist:    CategoryPolicy,
+}
+
+// classify fills in c's Severity and, unless the level set one, its
+// Category.
+func classify(c CheckResult) CheckResult {
+	c.Severity = checkSeverity(c)
+	if c.Category == "" {
+		if cat, ok := codeCategories[c.Code]; ok {
+			c.Category = cat
+		} else {
+			c.Category = levelCategories[c.Level]
+		}
+	}
+	return c
+}
+
+// Explanation composes a human-readable paragraph of why the address got
+// its verdict, e.g. for a support agent: the verdict, the reason of every
+// failed check, the caveats of passed ones (a catch-all domain, a skipped
+// mailbox probe, a suggested correction) and the deliverability
+// probability when WithCalibration is enabled. The wording is for people
+// and may change between releases; match on Code and Level instead.
+func (r Result) Explanation() string {
+	return r.explanation(func(email string) string { return email }, func(p float64) string {
+		return fmt.Sprintf("%.0f%%", p*100)
+	})
+}
+
+// explanation composes Explanation, rendering addresses with addr and the
+// probability with percent.
+func (r Result) explanation(addr func(string) string, percent func(float64) string) string {
+	var reasons, caveats []string
+	inconclusive := true
+	for _, c := range r.Checks {
+		switch {
+		case !c.Passed:
+			reasons = append(reasons, failureReason(c))
+			if _, ok := inconclusiveCodes[c.Code]; !ok && !unfinishedCodes[c.Code] {
+				inconclusive = false
+			}
+		case c.Code != "" && codeReasons[c.Code] != "":
+			caveats = append(caveats, codeReasons[c.Code])
+		case c.Level == LevelSMTP && c.Meta["probe"] == "connect":
+			caveats = append(caveats, "only the mail server's connection was checked, not the mailbox")
+		}
+		if c.Suggestion != "" {
+			caveats = append(caveats, fmt.Sprintf("it may be a typo for %s", addr(c.Suggestion)))
+		}
+	}
+
+	email := addr(r.Email)
+	var b strings.Builder
+	switch {
+	case r.Valid:
+		fmt.Fprintf(&b, "%s is valid: it passed %s.", email, passedLevels(r.Checks))
+	case r.Truncated:
+		fmt.Fprintf(&b, "%s could not be fully checked: validation stopped before every check ran, so the address is neither confirmed nor rejected.", email)
+	case len(reasons) > 0 && inconclusive:
+		fmt.Fprintf(&b, "%s could not be verified: %s.", email, strings.Join(reasons, "; "))
+	case len(reasons) > 0:
+		fmt.Fprintf(&b, "%s is invalid: %s.", email, strings.Join(reasons, "; "))
+	default:
+		fmt.Fprintf(&b, "%s is invalid.", email)
+	}
+	if len(caveats) > 0 {
+		fmt.Fprintf(&b, " Note that %s.", strings.Join(caveats, "; "))
+	}
+	if r.DeliverabilityProbability > 0 {
+		fmt.Fprintf(&b, " Estimated deliverability: %s.", percent(r.DeliverabilityProbability))
+	}
+	return b.String()
+}
+
+// codeReasons phrases every reason code for Explanation.
+var codeReasons = map[CheckCode]string{
+	CodeCancelled:          "validation was cancelled before the check finished",
+	CodeError:              "validation could not run",
+	CodeMailboxUnknown:     "the mail server says the mailbox does not exist",
+	CodeMailboxFull:        "the mailbox is full, so mail bounces until it is emptied",
+	CodeMailboxDisabled:    "the mailbox has been disabled",
+	CodeBadDomain:          "the domain does not exist or does not receive mail",
+	CodeGreylisted:         "the mail server deferred the check (greylisting) and a later retry may succeed",
+	CodeRateLimited:        "the mail server is rate-limiting checks",
+	CodeBlocked:            "the mail server refused to talk to the verifier",
+	CodePolicy:             "the mail server refused the recipient for policy reasons",
+	CodeServiceUnavailable: "the mail server was unavailable",
+	CodeTemporary:          "the mail server reported a temporary failure",
+	CodeUnknown:            "the mail server gave an unexpected answer",
+	CodeCatchAll:           "the domain accepts mail for any address, so the mailbox itself is unconfirmed",
+	CodeDeferred:           "the mailbox check was postponed by the probe policy",
+	CodeSkipped:            "the mailbox itself was not checked",
+	CodeDNSTimeout:         "the DNS lookup timed out, which says nothing about the domain",
+	CodeDNSError:           "the DNS lookup failed, which says nothing about the domain",
+	CodeTimedOut:           "a check took too long and was abandoned",
+	CodeAllowlisted:        "it is on the allowlist, so no check ran",
+	CodeBlocklisted:        "it is on the blocklist",
+	CodeParked:             "the domain looks parked: it resolves but is unlikely to receive mail",
+	CodeGeoBlocked:         "the domain's mail servers are located in a blocked country",
+	CodeSanctioned:         "the domain is in a sanctioned jurisdiction",
+	CodeRestricted:         "the domain belongs to a government or military organization with contact restrictions",
+	CodeSenderRole:         "the address is reserved for mail infrastructure and should not send",
+	CodeSenderNoBounce:     "the sender domain does not receive mail, so bounces would be lost",
+	CodeSenderSPFFail:      "the sender domain's SPF record does not allow mail from the sending servers",
+	CodeSenderNoDMARC:      "the sender domain has no DMARC record",
+	CodeRole:               "it is a role account, read by a team rather than a person",
+	CodeFreeProvider:       "it is at a free email provider",
+	CodeTokenMissing:       "the domain does not publish the verification token",
+	CodeDNSBLListed:        "its mail servers are on a DNS blocklist",
+	CodeSPFMissing:         "its domain publishes no SPF record",
+}
+
+// unfinishedCodes mark checks that did not get an answer; like
+// inconclusiveCodes, Explanation reports them as "could not be verified".
+var unfinishedCodes = map[CheckCode]bool{
+	CodeCancelled: true,
+	CodeError:     true,
+	CodeUnknown:   true,
+	CodeTimedOut:  true,
+}
+
+// levelNames names the levels in Explanation.
+var levelNames = map[CheckLevel]string{
+	LevelSyntax:       "syntax",
+	LevelDNS:          "DNS",
+	LevelDomain:       "domain",
+	LevelSMTP:         "mailbox",
+	LevelNS:           "nameserver",
+	LevelRegistration: "registration",
+	LevelGeo:          "location",
+	LevelCompliance:   "compliance",
+	LevelSender:       "sender",
+	LevelRole:         "role",
+	LevelFreeProvider: "free provider",
+	LevelVerification: "verification",
+	LevelDNSBL:        "DNSBL",
+	LevelSPF:          "SPF",
+	LevelAllowlist:    "allowlist",
+	LevelBlocklist:    "blocklist",
+	LevelPipeline:     "pipeline",
+}
+
+// failureReason phrases a failed check: its code if it has one, with the
+// SMTP reply code when there is one, or else the level and its details.
+func failureReason(c CheckResult) string {
+	reason, ok := codeReasons[c.Code]
+	if !ok {
+		if c.Details == "" {
+			return fmt.Sprintf("the %s check failed", levelName(c.Level))
+		}
+		return fmt.Sprintf("the %s check failed (%s)", levelName(c.Level), c.Details)
+	}
+	if c.SMTPCode != 0 {
+		return fmt.Sprintf("%s (SMTP %d)", reason, c.SMTPCode)
+	}
+	return reason
+}
+
+// passedLevels lists the levels of the passed checks, e.g. "the syntax,
+// DNS and mailbox checks".
+func passedLevels(checks []CheckResult) string {
+	var names []string
+	for _, c := range checks {
+		if c.Passed {
+			names = append(names, levelName(c.Level))
+		}
+	}
+	switch len(names) {
+	case 0:
+		return "every configured check"
+	case 1:
+		return "the " + names[0] + " check"
+	default:
+		return "the " + strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1] + " checks"
+	}
+}
+
+func levelName(level CheckLevel) string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return level
+}
+
+// compactResult is the one-line summary produced by MarshalCompact.
+type compactResult struct {
+	Email        string       `json:"email"`
+	Valid        bool         `json:"valid"`
+	Reachability Reachability `json:"reachability,omitempty"`
+	Severity     Severity     `json:"severity"`
+	Truncated    bool         `json:"truncated,omitempty"`
+	Failed       CheckLevel   `json:"failed,omitempty"`
+	Code         CheckCode    `json:"code,omitempty"`
+	Details      string       `json:"details,omitempty"`
+	Suggestion   string       `json:"suggestion,omitempty"`
+}
+
+// MarshalCompact encodes the result as a single-line JSON summary: the
+// verdict, its severity and only the first failing level (plus any
+// suggestion) instead of the full check array. Suited for storing
+// millions of rows.
+func (r Result) MarshalCompact() ([]byte, error) {
+	out := compactResult{
+		Email:        r.Email,
+		Valid:        r.Valid,
+		Reachability: r.Reachability,
+		Severity:     r.Severity(),
+		Truncated:    r.Truncated,
+	}
+	for _, c := range r.Checks {
+		if !c.Passed && out.Failed == "" {
+			out.Failed = c.Level
+			out.Code = c.Code
+			out.Details = c.Details
+		}
+		if c.Suggestion != "" && out.Suggestion == "" {
+			out.Suggestion = c.Suggestion
+		}
+	}
+	return json.Marshal(out)
+}
+
+// MarshalVerbose encodes the result with every check tagged by severity
+// and category, including all metadata. Suited for diagnostics and audit trails.
+func (r Result) MarshalVerbose() ([]byte, error) {
+	out := struct {
+		Email        string        `json:"email"`
+		Valid        bool          `json:"valid"`
+		Reachability Reachability  `json:"reachability,omitempty"`
+		Severity     Severity      `json:"severity"`
+		Truncated    bool          `json:"truncated"`
+		Checks       []CheckResult `json:"checks"`
+	}{
+		Email:        r.Email,
+		Valid:        r.Valid,
+		Reachability: r.Reachability,
+		Severity:     r.Severity(),
+		Truncated:    r.Truncated,
+		Checks:       make([]CheckResult, len(r.Checks)),
+	}
+	for i, c := range r.Checks {
+		// Results built by hand carry no classification
+		if c.Severity == "" {
+			c = classify(c)
+		}
+		out.Checks[i] = c
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,213 @@
+package emailkit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestResult_Severity(t *testing.T) {
+	tests := []struct {
+		name   string
+		result emailkit.Result
+		want   emailkit.Severity
+	}{
+		{
+			name: "valid",
+			result: emailkit.Result{Valid: true, Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelSyntax, Passed: true},
+			}},
+			want: emailkit.SeverityInfo,
+		},
+		{
+			name: "valid with suggestion",
+			result: emailkit.Result{Valid: true, Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelDomain, Passed: true, Suggestion: "gmail.com"},
+			}},
+			want: emailkit.SeverityWarn,
+		},
+		{
+			name: "invalid",
+			result: emailkit.Result{Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelSyntax, Passed: false},
+			}},
+			want: emailkit.SeverityError,
+		},
+		{
+			name: "mailbox full",
+			result: emailkit.Result{Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelSyntax, Passed: true},
+				{Level: emailkit.LevelSMTP, Passed: false, Code: emailkit.CodeMailboxFull},
+			}},
+			want: emailkit.SeverityWarn,
+		},
+		{
+			name: "invalid without failing check",
+			result: emailkit.Result{Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelSyntax, Passed: true},
+			}},
+			want: emailkit.SeverityError,
+		},
+		{
+			name:   "truncated",
+			result: emailkit.Result{Truncated: true},
+			want:   emailkit.SeverityWarn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.result.Severity())
+		})
+	}
+}
+
+func TestResult_MarshalCompact(t *testing.T) {
+	r := emailkit.Result{
+		Email: "user@gmial.com",
+		Checks: []emailkit.CheckResult{
+			{Level: emailkit.LevelSyntax, Passed: true, Details: "syntax ok"},
+			{Level: emailkit.LevelDomain, Passed: true, Details: "possible typo in domain", Suggestion: "gmail.com"},
+			{Level: emailkit.LevelDNS, Passed: false, Details: "no MX records found"},
+		},
+	}
+
+	data, err := r.MarshalCompact()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"email": "user@gmial.com",
+		"valid": false,
+		"severity": "error",
+		"failed": "dns",
+		"details": "no MX records found",
+		"suggestion": "gmail.com"
+	}`, string(data))
+	assert.NotContains(t, string(data), "\n")
+}
+
+func TestResult_MarshalVerbose(t *testing.T) {
+	r := emailkit.Result{
+		Email: "user@example.com",
+		Valid: true,
+		Checks: []emailkit.CheckResult{
+			{Level: emailkit.LevelSyntax, Passed: true, Details: "syntax ok"},
+			{Level: emailkit.LevelRegistration, Passed: true, Meta: map[string]string{"registered": "1995-08-14T04:00:00Z"}},
+		},
+	}
+
+	data, err := r.MarshalVerbose()
+	assert.NoError(t, err)
+
+	var out struct {
+		Severity  string `json:"severity"`
+		Truncated bool   `json:"truncated"`
+		Checks    []struct {
+			Level    string            `json:"level"`
+			Severity string            `json:"severity"`
+			Category string            `json:"category"`
+			Meta     map[string]string `json:"meta"`
+		} `json:"checks"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, "info", out.Severity)
+	assert.Len(t, out.Checks, 2)
+	assert.Equal(t, "info", out.Checks[0].Severity)
+	assert.Equal(t, "syntax", out.Checks[0].Category)
+	assert.Equal(t, "1995-08-14T04:00:00Z", out.Checks[1].Meta["registered"])
+}
+
+func TestCheckResult_SeverityCategory(t *testing.T) {
+	v := emailkit.New().WithDomain().WithBlocklist(nil, []string{`@spam\.example$`})
+	tests := []struct {
+		email    string
+		level    emailkit.CheckLevel
+		severity emailkit.Severity
+		category emailkit.Category
+	}{
+		{"jane@example.com", emailkit.LevelDomain, emailkit.SeverityInfo, emailkit.CategorySyntax},
+		{"not-an-email", emailkit.LevelSyntax, emailkit.SeverityError, emailkit.CategorySyntax},
+		{"jane@gmial.com", emailkit.LevelDomain, emailkit.SeverityWarn, emailkit.CategorySyntax},
+		{"temp@mailinator.com", emailkit.LevelDomain, emailkit.SeverityError, emailkit.CategoryReputation},
+		{"bob@spam.example", emailkit.LevelBlocklist, emailkit.SeverityError, emailkit.CategoryPolicy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			res, err := v.Validate(context.Background(), tt.email)
+			assert.NoError(t, err)
+			c, ok := res.CheckFor(tt.level)
+			assert.True(t, ok)
+			assert.Equal(t, tt.severity, c.Severity)
+			assert.Equal(t, tt.category, c.Category)
+		})
+	}
+}
+
+func TestResult_Explanation(t *testing.T) {
+	tests := []struct {
+		name   string
+		result emailkit.Result
+		want   string
+	}{
+		{
+			name: "valid",
+			result: emailkit.Result{Email: "jane@example.com", Valid: true, DeliverabilityProbability: 0.97, Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelSyntax, Passed: true},
+				{Level: emailkit.LevelDNS, Passed: true},
+				{Level: emailkit.LevelSMTP, Passed: true, SMTPCode: 250},
+			}},
+			want: "jane@example.com is valid: it passed the syntax, DNS and mailbox checks. Estimated deliverability: 97%.",
+		},
+		{
+			name: "valid with caveats",
+			result: emailkit.Result{Email: "jane@gmial.com", Valid: true, Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelDomain, Passed: true, Suggestion: "gmail.com"},
+				{Level: emailkit.LevelSMTP, Passed: true, Code: emailkit.CodeCatchAll},
+			}},
+			want: "jane@gmial.com is valid: it passed the domain and mailbox checks. " +
+				"Note that it may be a typo for gmail.com; the domain accepts mail for any address, so the mailbox itself is unconfirmed.",
+		},
+		{
+			name: "connect only",
+			result: emailkit.Result{Email: "jane@example.com", Valid: true, Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelSMTP, Passed: true, Meta: map[string]string{"probe": "connect"}},
+			}},
+			want: "jane@example.com is valid: it passed the mailbox check. Note that only the mail server's connection was checked, not the mailbox.",
+		},
+		{
+			name: "mailbox unknown",
+			result: emailkit.Result{Email: "jane@example.com", Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelSyntax, Passed: true},
+				{Level: emailkit.LevelSMTP, Passed: false, SMTPCode: 550, Code: emailkit.CodeMailboxUnknown},
+			}},
+			want: "jane@example.com is invalid: the mail server says the mailbox does not exist (SMTP 550).",
+		},
+		{
+			name: "failure without code",
+			result: emailkit.Result{Email: "jane@mailinator.com", Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelDomain, Passed: false, Details: "disposable email domain detected"},
+			}},
+			want: "jane@mailinator.com is invalid: the domain check failed (disposable email domain detected).",
+		},
+		{
+			name: "greylisted",
+			result: emailkit.Result{Email: "jane@example.com", DeliverabilityProbability: 0.7, Checks: []emailkit.CheckResult{
+				{Level: emailkit.LevelSMTP, Passed: false, SMTPCode: 451, Code: emailkit.CodeGreylisted},
+			}},
+			want: "jane@example.com could not be verified: the mail server deferred the check (greylisting) and a later retry may succeed (SMTP 451). " +
+				"Estimated deliverability: 70%.",
+		},
+		{
+			name:   "truncated",
+			result: emailkit.Result{Email: "jane@example.com", Truncated: true},
+			want:   "jane@example.com could not be fully checked: validation stopped before every check ran, so the address is neither confirmed nor rejected.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.result.Explanation())
+		})
+	}
+}
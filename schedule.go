@@ -0,0 +1,57 @@
+package emailkit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/optimode/emailkit/internal/schedule"
+)
+
+// ProbeSchedule restricts when the SMTP level probes and how often. Outside
+// the windows, or once a provider's daily budget is spent, the SMTP level
+// does not connect and reports CodeDeferred with the next allowed time in
+// Meta["resume_at"].
+type ProbeSchedule struct {
+	// Windows are daily probing windows as "HH:MM-HH:MM" in Location, e.g.
+	// "09:00-17:00". A window may wrap midnight ("22:00-06:00").
+	// Empty: any time of day.
+	Windows []string
+	// Weekdays limits probing to these days. Empty: every day.
+	Weekdays []time.Weekday
+	// Location is the time zone of Windows and of the daily budget reset.
+	// Default: UTC
+	Location *time.Location
+	// DailyBudget caps probes per day by provider ("google", "microsoft",
+	// ...; see InspectDomain) or, for unrecognised providers, by the
+	// registrable domain of the primary MX host.
+	DailyBudget map[string]int
+	// DefaultDailyBudget applies to keys not in DailyBudget. Default: 0 (unlimited)
+	DefaultDailyBudget int
+}
+
+// build parses the schedule.
+func (s *ProbeSchedule) build() (*schedule.Schedule, error) {
+	windows := make([]schedule.Window, 0, len(s.Windows))
+	for _, w := range s.Windows {
+		win, err := schedule.ParseWindow(w)
+		if err != nil {
+			return nil, &OptionsError{Field: "ProbeSchedule.Windows", Value: w, Constraint: err.Error(), Err: ErrInvalidSchedule}
+		}
+		windows = append(windows, win)
+	}
+	if s.DefaultDailyBudget < 0 {
+		return nil, &OptionsError{Field: "ProbeSchedule.DefaultDailyBudget", Value: s.DefaultDailyBudget, Constraint: "must not be negative", Err: ErrInvalidSchedule}
+	}
+	for key, n := range s.DailyBudget {
+		if n < 0 {
+			return nil, &OptionsError{Field: fmt.Sprintf("ProbeSchedule.DailyBudget[%q]", key), Value: n, Constraint: "must not be negative", Err: ErrInvalidSchedule}
+		}
+	}
+	return schedule.New(schedule.Config{
+		Windows:       windows,
+		Weekdays:      s.Weekdays,
+		Location:      s.Location,
+		Budgets:       s.DailyBudget,
+		DefaultBudget: s.DefaultDailyBudget,
+	}), nil
+}
@@ -0,0 +1,34 @@
+package emailkit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestProbeSchedule_InvalidWindow(t *testing.T) {
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Schedule:   &emailkit.ProbeSchedule{Windows: []string{"9am-5pm"}},
+	})
+	defer func() { _ = v.Close() }()
+
+	_, err := v.Validate(context.Background(), "user@example.com")
+	assert.ErrorIs(t, err, emailkit.ErrInvalidSchedule)
+}
+
+func TestProbeSchedule_NegativeBudget(t *testing.T) {
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Schedule:   &emailkit.ProbeSchedule{DailyBudget: map[string]int{"google": -1}},
+	})
+	defer func() { _ = v.Close() }()
+
+	_, err := v.Validate(context.Background(), "user@example.com")
+	assert.ErrorIs(t, err, emailkit.ErrInvalidSchedule)
+}
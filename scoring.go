@@ -0,0 +1,168 @@
+package emailkit
+
+import (
+	"time"
+
+	"github.com/optimode/emailkit/check"
+)
+
+// ScoringOptions weights the signals of Result.RiskScore, a 0–100 risk
+// score: the sum of the weights of the signals a result shows, capped at
+// 100. Zero fields take the default; a negative weight disables the
+// signal.
+type ScoringOptions struct {
+	// Syntax: the address is malformed, or breaks its provider's
+	// local-part rules. Default: 100
+	Syntax int
+	// NoMX: the domain has no mail servers. Default: 100
+	NoMX int
+	// Disposable: the domain is a disposable email service. Default: 90
+	Disposable int
+	// MailboxRejected: the mail server rejected the mailbox. Default: 100
+	MailboxRejected int
+	// Failure: any other check failed on its merits, e.g. a blocklist,
+	// geo or compliance match, or a third-party level. Default: 100
+	Failure int
+	// MailboxFull: the mailbox exists but is over its quota. Default: 50
+	MailboxFull int
+	// Inconclusive: a check got no answer (greylisting, temporary SMTP
+	// replies, DNS timeouts), or the run was cut short. Default: 40
+	Inconclusive int
+	// CatchAll: the domain accepts every recipient. Default: 40
+	CatchAll int
+	// Parked: the domain looks parked. Default: 60
+	Parked int
+	// YoungDomain: the domain was registered less than YoungDomainAge
+	// ago. Default: 30
+	YoungDomain int
+	// Typo: a level suggested a correction, e.g. gmial.com. Default: 30
+	Typo int
+	// RoleAccount: the local part is a role, e.g. info@ or support@, read
+	// by a team rather than a person: one of DefaultRoles, or flagged by
+	// the role level (WithRole). Default: 25
+	RoleAccount int
+	// NotProbed: no mailbox was probed, e.g. without the SMTP level or
+	// with WithSMTPConnect. Default: 20
+	NotProbed int
+}
+
+// DefaultScoring returns the built-in weights.
+func DefaultScoring() ScoringOptions {
+	return ScoringOptions{
+		Syntax:          100,
+		NoMX:            100,
+		Disposable:      90,
+		MailboxRejected: 100,
+		Failure:         100,
+		MailboxFull:     50,
+		Inconclusive:    40,
+		CatchAll:        40,
+		Parked:          60,
+		YoungDomain:     30,
+		Typo:            30,
+		RoleAccount:     25,
+		NotProbed:       20,
+	}
+}
+
+// withDefaults fills in the zero weights of o from DefaultScoring.
+func (o ScoringOptions) withDefaults() ScoringOptions {
+	d := DefaultScoring()
+	for _, w := range []struct{ dst, def *int }{
+		{&o.Syntax, &d.Syntax},
+		{&o.NoMX, &d.NoMX},
+		{&o.Disposable, &d.Disposable},
+		{&o.MailboxRejected, &d.MailboxRejected},
+		{&o.Failure, &d.Failure},
+		{&o.MailboxFull, &d.MailboxFull},
+		{&o.Inconclusive, &d.Inconclusive},
+		{&o.CatchAll, &d.CatchAll},
+		{&o.Parked, &d.Parked},
+		{&o.YoungDomain, &d.YoungDomain},
+		{&o.Typo, &d.Typo},
+		{&o.RoleAccount, &d.RoleAccount},
+		{&o.NotProbed, &d.NotProbed},
+	} {
+		if *w.dst == 0 {
+			*w.dst = *w.def
+		}
+	}
+	return o
+}
+
+// WithScoring enables Result.RiskScore, weighting its signals by opts
+// (see ScoringOptions). For list cleaning, a single threshold on the
+// score replaces inspecting every check.
+func (v *Validator) WithScoring(opts ...ScoringOptions) *Validator {
+	var o ScoringOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	o = o.withDefaults()
+	v.scoring = &o
+	return v
+}
+
+// defaultRoles spots role accounts for scoring without the role level.
+var defaultRoles = check.NewRoleChecker(check.RoleConfig{})
+
+// risk scores r; local is the local part of the address.
+func (o ScoringOptions) risk(r Result, local string) int {
+	if r.Truncated {
+		return min(max(o.Inconclusive, 0), 100)
+	}
+	// A signal counts once however many checks show it
+	seen := map[*int]bool{}
+	add := func(weight *int) { seen[weight] = true }
+
+	probed := false
+	for _, c := range r.Checks {
+		switch {
+		case !c.Passed && c.Code == CodeMailboxFull:
+			add(&o.MailboxFull)
+		case !c.Passed && unknownCodes[c.Code]:
+			add(&o.Inconclusive)
+		case !c.Passed && c.Level == LevelSyntax,
+			!c.Passed && c.Level == LevelDomain && c.Category == CategorySyntax:
+			add(&o.Syntax)
+		case !c.Passed && c.Level == LevelDomain && c.Category == CategoryReputation:
+			add(&o.Disposable)
+		case !c.Passed && c.Level == LevelDNS:
+			add(&o.NoMX)
+		case !c.Passed && c.Level == LevelSMTP:
+			add(&o.MailboxRejected)
+		case !c.Passed:
+			add(&o.Failure)
+		case c.Code == CodeCatchAll:
+			add(&o.CatchAll)
+		case c.Code == CodeParked:
+			add(&o.Parked)
+		case c.Level == LevelSMTP && c.Code == "" && c.Meta["probe"] != "connect":
+			probed = true
+		}
+		if c.Code == CodeRole {
+			add(&o.RoleAccount)
+		}
+		if c.Suggestion != "" {
+			add(&o.Typo)
+		}
+		if c.Code == CodeCatchAll && c.Level == LevelSMTP {
+			probed = true
+		}
+		if reg, err := time.Parse(time.RFC3339, c.Meta["registered"]); err == nil && time.Since(reg) < YoungDomainAge {
+			add(&o.YoungDomain)
+		}
+	}
+	if _, ok := defaultRoles.Role(local); ok {
+		add(&o.RoleAccount)
+	}
+	if !probed && r.Valid {
+		add(&o.NotProbed)
+	}
+
+	score := 0
+	for w := range seen {
+		score += max(*w, 0)
+	}
+	return min(score, 100)
+}
@@ -0,0 +1,76 @@
+package emailkit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/optimode/emailkit"
+)
+
+func TestWithScoring(t *testing.T) {
+	v := emailkit.NewOffline(fakeNetwork()).WithDomain().WithDNS().WithRegistration().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain:                  "verifier.test",
+		MailFrom:                    "verify@verifier.test",
+		ConnectTimeout:              time.Second,
+		CommandTimeout:              time.Second,
+		DisableCatchAllFingerprints: true,
+	}).WithScoring()
+	defer func() { _ = v.Close() }()
+
+	tests := []struct {
+		email string
+		want  int
+	}{
+		{"alice@example.com", 0},
+		{"carol@example.com", 50},             // mailbox full
+		{"nobody@example.com", 100},           // mailbox rejected
+		{"dave@grey.example", 40},             // greylisted
+		{"frank@new.example", 30},             // young domain
+		{"postmaster@catchall.example", 25},   // role account
+		{"user@nullmx.example", 100},          // no mail servers
+		{"temp@mailinator.com", 90},           // disposable
+		{"not-an-email", 100},                 // syntax
+		{"Support+news@catchall.example", 25}, // role account despite the tag
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			res, err := v.Validate(context.Background(), tt.email)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, res.RiskScore)
+		})
+	}
+}
+
+func TestWithScoring_Weights(t *testing.T) {
+	v := emailkit.New().WithDomain().WithScoring(emailkit.ScoringOptions{
+		NotProbed:   -1, // disabled
+		RoleAccount: 60,
+	})
+	ctx := context.Background()
+
+	res, _ := v.Validate(ctx, "jane@example.com")
+	assert.Equal(t, 0, res.RiskScore)
+	res, _ = v.Validate(ctx, "info@example.com")
+	assert.Equal(t, 60, res.RiskScore)
+	res, _ = v.Validate(ctx, "info@gmial.com")
+	assert.Equal(t, 90, res.RiskScore) // role account + default typo weight
+	res, _ = v.Validate(ctx, "info@mailinator.com")
+	assert.Equal(t, 100, res.RiskScore, "capped at 100")
+
+	// Without WithScoring there is no score
+	res, _ = emailkit.New().Validate(ctx, "info@example.com")
+	assert.Zero(t, res.RiskScore)
+}
+
+func TestWithScoring_Truncated(t *testing.T) {
+	v := emailkit.New().WithDomain().WithScoring()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, _ := v.Validate(ctx, "jane@example.com")
+	assert.True(t, res.Truncated)
+	assert.Equal(t, emailkit.DefaultScoring().Inconclusive, res.RiskScore)
+}
@@ -0,0 +1,61 @@
+package emailkit
+
+import (
+	"context"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/dnscache"
+)
+
+// MXStore shares MX lookup results between validators, typically a fleet
+// of worker processes behind one backend (see package redisstore), so a
+// domain is resolved once per TTL for the whole fleet. Entries use the
+// ExportState form. Implementations must be safe for concurrent use.
+type MXStore interface {
+	// LoadMX returns the stored entry for domain; ok is false on a miss.
+	LoadMX(ctx context.Context, domain string) (e DNSStateEntry, ok bool, err error)
+	// StoreMX stores an entry until e.Expires.
+	StoreMX(ctx context.Context, e DNSStateEntry) error
+}
+
+// MXStoreDeleter is implemented by MXStores that can drop an entry, so
+// Validator.Invalidate also clears the shared tier.
+type MXStoreDeleter interface {
+	// DeleteMX removes the stored entry for domain, if any.
+	DeleteMX(ctx context.Context, domain string) error
+}
+
+// ProbeLimiter paces SMTP probes per mailbox provider (e.g. "google") or,
+// for other hosts, per registrable domain of the primary MX host (see
+// SMTPOptions.Limiter). Implementations must be safe for concurrent use.
+type ProbeLimiter = check.ProbeLimiter
+
+// WithMXStore adds a shared second tier to the MX lookup cache: local
+// misses are looked up in s before resolving, and answers (including
+// NXDOMAIN, but not timeouts or SERVFAIL) are written back.
+func (v *Validator) WithMXStore(s MXStore) *Validator {
+	v.mxStore = s
+	return v
+}
+
+// mxStoreRef adapts the Validator's current MXStore to the DNS cache, so
+// that WithMXStore also affects levels added before it was called.
+type mxStoreRef struct{ v *Validator }
+
+func (r mxStoreRef) Load(ctx context.Context, domain string) (dnscache.Entry, bool, error) {
+	if r.v.mxStore == nil {
+		return dnscache.Entry{}, false, nil
+	}
+	de, ok, err := r.v.mxStore.LoadMX(ctx, domain)
+	if err != nil || !ok {
+		return dnscache.Entry{}, false, err
+	}
+	return fromStateEntry(de), true, nil
+}
+
+func (r mxStoreRef) Save(ctx context.Context, e dnscache.Entry) error {
+	if r.v.mxStore == nil {
+		return nil
+	}
+	return r.v.mxStore.StoreMX(ctx, toStateEntry(e))
+}
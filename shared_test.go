@@ -0,0 +1,52 @@
+package emailkit_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+// memoryMXStore is an MXStore standing in for a shared backend.
+type memoryMXStore struct {
+	mu      sync.Mutex
+	entries map[string]emailkit.DNSStateEntry
+}
+
+func (s *memoryMXStore) LoadMX(_ context.Context, domain string) (emailkit.DNSStateEntry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[domain]
+	return e, ok, nil
+}
+
+func (s *memoryMXStore) StoreMX(_ context.Context, e emailkit.DNSStateEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[e.Domain] = e
+	return nil
+}
+
+func TestWithMXStore_SharedBetweenValidators(t *testing.T) {
+	store := &memoryMXStore{entries: map[string]emailkit.DNSStateEntry{}}
+
+	// The first worker resolves example.com and publishes the answer
+	first := emailkit.New().WithResolver(&stubResolver{
+		mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+	}).WithDNS().WithMXStore(store)
+	res, err := first.Validate(context.Background(), "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+	assert.Equal(t, []emailkit.MXRecord{{Host: "mx.example.com.", Pref: 10}}, store.entries["example.com"].MX)
+
+	// The second worker's resolver knows nothing, the store answers
+	second := emailkit.New().WithResolver(&stubResolver{}).WithDNS().WithMXStore(store)
+	res, err = second.Validate(context.Background(), "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+}
@@ -0,0 +1,197 @@
+package emailkit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/optimode/emailkit/internal/dnscache"
+	"github.com/optimode/emailkit/internal/greylist"
+)
+
+// stateVersion is bumped on incompatible changes to State.
+const stateVersion = 1
+
+// State is the warm state of a Validator as written by ExportState:
+// MX lookup cache entries (including negative answers), the greylisting
+// timestamps of the built-in greylist store and today's probe counters of
+// the SMTP level's Schedule budgets and WarmUp ramp. Expired entries and
+// counters of a past day are never exported and are dropped on import.
+type State struct {
+	Version  int                  `json:"version"`
+	Exported time.Time            `json:"exported"`
+	DNS      []DNSStateEntry      `json:"dns,omitempty"`
+	Greylist map[string]time.Time `json:"greylist,omitempty"`
+	Budgets  *BudgetState         `json:"budgets,omitempty"`
+	WarmUp   *WarmUpState         `json:"warmUp,omitempty"`
+}
+
+// BudgetState is today's probes by ProbeSchedule budget key.
+type BudgetState struct {
+	Day    string         `json:"day"` // YYYY-MM-DD in ProbeSchedule.Location
+	Probes map[string]int `json:"probes"`
+}
+
+// WarmUpState is today's WarmUp state by provider.
+type WarmUpState struct {
+	Day       string         `json:"day"` // YYYY-MM-DD in WarmUp.Location
+	Providers []WarmUpStatus `json:"providers"`
+}
+
+// DNSStateEntry is one cached MX lookup.
+type DNSStateEntry struct {
+	Domain   string     `json:"domain"`
+	MX       []MXRecord `json:"mx,omitempty"`
+	Error    string     `json:"error,omitempty"`
+	NotFound bool       `json:"notFound,omitempty"` // NXDOMAIN / no such host
+	Expires  time.Time  `json:"expires"`
+}
+
+// MXRecord is a serializable MX record.
+type MXRecord struct {
+	Host string `json:"host"`
+	Pref uint16 `json:"pref"`
+}
+
+// ExportState writes the validator's warm state as JSON, so short-lived
+// CLI or batch processes can persist it between runs (see ImportState).
+func (v *Validator) ExportState(w io.Writer) error {
+	st := State{Version: stateVersion, Exported: time.Now().UTC()}
+
+	if v.dnsCache != nil {
+		for _, e := range v.dnsCache.Entries() {
+			st.DNS = append(st.DNS, toStateEntry(e))
+		}
+	}
+	if ms, ok := v.greylist.(*greylist.MemoryStore); ok {
+		st.Greylist = ms.Entries()
+	}
+	if v.schedule != nil {
+		if day, used := v.schedule.Counters(); used != nil {
+			st.Budgets = &BudgetState{Day: day, Probes: used}
+		}
+	}
+	if v.warmUp != nil {
+		if day, keys := v.warmUp.Counters(); len(keys) > 0 {
+			st.WarmUp = &WarmUpState{Day: day, Providers: keys}
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(st); err != nil {
+		return fmt.Errorf("emailkit: export state: %w", err)
+	}
+	return nil
+}
+
+// ImportState loads state written by ExportState. Call it after the levels
+// are configured: sections for components the validator doesn't use (no
+// DNS cache, a custom greylist store, no Schedule or WarmUp) are ignored.
+// Entries already present in the validator are kept; of two probe counts
+// for a provider, the larger.
+func (v *Validator) ImportState(r io.Reader) error {
+	var st State
+	if err := json.NewDecoder(r).Decode(&st); err != nil {
+		return fmt.Errorf("emailkit: import state: %w", err)
+	}
+	if st.Version != stateVersion {
+		return fmt.Errorf("emailkit: import state: unsupported version %d", st.Version)
+	}
+
+	if v.dnsCache != nil {
+		entries := make([]dnscache.Entry, 0, len(st.DNS))
+		for _, de := range st.DNS {
+			entries = append(entries, fromStateEntry(de))
+		}
+		v.dnsCache.Restore(entries)
+	}
+	if ms, ok := v.greylist.(*greylist.MemoryStore); ok {
+		ms.Restore(st.Greylist)
+	}
+	if v.schedule != nil && st.Budgets != nil {
+		v.schedule.Restore(st.Budgets.Day, st.Budgets.Probes)
+	}
+	if v.warmUp != nil && st.WarmUp != nil {
+		v.warmUp.Restore(st.WarmUp.Day, st.WarmUp.Providers)
+	}
+	return nil
+}
+
+// PurgeDNSCache drops the cached MX and TXT answers, so the next lookups
+// query again, and returns how many were dropped. A shared MXStore is not
+// touched.
+func (v *Validator) PurgeDNSCache() int {
+	if v.dnsCache == nil {
+		return 0
+	}
+	return v.dnsCache.Purge()
+}
+
+// SaveMXSnapshot writes the MX cache as a snapshot for LoadMXSnapshot:
+// JSON Lines, a header line followed by one line per domain with its MX
+// records or NXDOMAIN and when it was resolved, e.g.
+//
+//	{"format":"emailkit-mx-snapshot","version":1,"saved":"2026-10-16T02:00:00Z"}
+//	{"domain":"example.com","mx":[{"host":"mx.example.com.","pref":10}],"resolved":"2026-10-16T01:58:12Z"}
+//	{"domain":"gone.example","nxdomain":true,"resolved":"2026-10-16T01:59:40Z"}
+//
+// Unlike ExportState it keeps answers past their cache TTL, so recurring
+// bulk jobs can start from the previous run's MX knowledge. Resolver
+// failures are not written.
+func (v *Validator) SaveMXSnapshot(w io.Writer) error {
+	c := v.dnsCache
+	if c == nil {
+		c = dnscache.New(0, 0) // header only
+	}
+	if err := c.SaveSnapshot(w); err != nil {
+		return fmt.Errorf("emailkit: save MX snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadMXSnapshot adds the answers of a SaveMXSnapshot snapshot that were
+// resolved within maxAge to the MX cache; each is then trusted for one
+// cache TTL before it is resolved again. Call it after the levels are
+// configured; without a DNS or SMTP level it does nothing. Domains already
+// cached are kept. It returns the number of domains loaded.
+func (v *Validator) LoadMXSnapshot(r io.Reader, maxAge time.Duration) (int, error) {
+	if v.dnsCache == nil {
+		return 0, nil
+	}
+	n, err := v.dnsCache.LoadSnapshot(r, maxAge)
+	if err != nil {
+		return 0, fmt.Errorf("emailkit: load MX snapshot: %w", err)
+	}
+	return n, nil
+}
+
+// toStateEntry converts a DNS cache entry to its serializable form.
+func toStateEntry(e dnscache.Entry) DNSStateEntry {
+	de := DNSStateEntry{Domain: e.Domain, Expires: e.Expires}
+	for _, mx := range e.Records {
+		de.MX = append(de.MX, MXRecord{Host: mx.Host, Pref: mx.Pref})
+	}
+	if e.Err != nil {
+		de.Error = e.Err.Error()
+		var dnsErr *net.DNSError
+		if errors.As(e.Err, &dnsErr) {
+			de.Error = dnsErr.Err
+			de.NotFound = dnsErr.IsNotFound
+		}
+	}
+	return de
+}
+
+// fromStateEntry is the inverse of toStateEntry.
+func fromStateEntry(de DNSStateEntry) dnscache.Entry {
+	e := dnscache.Entry{Domain: de.Domain, Expires: de.Expires}
+	for _, mx := range de.MX {
+		e.Records = append(e.Records, &net.MX{Host: mx.Host, Pref: mx.Pref})
+	}
+	if de.Error != "" {
+		e.Err = &net.DNSError{Err: de.Error, Name: de.Domain, IsNotFound: de.NotFound}
+	}
+	return e
+}
@@ -0,0 +1,190 @@
+package emailkit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+// countingResolver counts MX lookups on top of stubResolver.
+type countingResolver struct {
+	stubResolver
+	mxCalls atomic.Int32
+}
+
+func (r *countingResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.mxCalls.Add(1)
+	return r.stubResolver.LookupMX(ctx, name)
+}
+
+func TestExportImportState_DNS(t *testing.T) {
+	ctx := context.Background()
+	warm := &countingResolver{stubResolver: stubResolver{
+		mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+	}}
+	v := emailkit.New().WithDNS().WithResolver(warm)
+	_, _ = v.Validate(ctx, "user@example.com")
+	_, _ = v.Validate(ctx, "user@nxdomain.example")
+
+	var buf bytes.Buffer
+	require.NoError(t, v.ExportState(&buf))
+
+	var st emailkit.State
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &st))
+	assert.Equal(t, 1, st.Version)
+	assert.Len(t, st.DNS, 2)
+
+	// A fresh process: every lookup would go to the (empty) resolver
+	cold := &countingResolver{}
+	v2 := emailkit.New().WithDNS().WithResolver(cold)
+	require.NoError(t, v2.ImportState(&buf))
+
+	res, err := v2.Validate(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+
+	res, err = v2.Validate(ctx, "user@nxdomain.example")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	dns, _ := res.CheckFor(emailkit.LevelDNS)
+	assert.Contains(t, dns.Details, "no such host")
+
+	assert.Zero(t, cold.mxCalls.Load())
+}
+
+func TestPurgeDNSCache(t *testing.T) {
+	ctx := context.Background()
+	r := &countingResolver{stubResolver: stubResolver{
+		mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+	}}
+	v := emailkit.New().WithDNS().WithResolver(r)
+	assert.Zero(t, emailkit.New().PurgeDNSCache())
+
+	_, _ = v.Validate(ctx, "user@example.com")
+	_, _ = v.Validate(ctx, "user@example.com")
+	assert.Equal(t, int32(1), r.mxCalls.Load())
+
+	assert.Equal(t, 1, v.PurgeDNSCache())
+	_, _ = v.Validate(ctx, "user@example.com")
+	assert.Equal(t, int32(2), r.mxCalls.Load())
+}
+
+func TestImportState_Greylist(t *testing.T) {
+	since := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	in := `{"version":1,"greylist":{"mx.example.com|user@example.com":"` + since.Format(time.RFC3339) + `"}}`
+
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Greylist:   emailkit.NewMemoryGreylistStore(),
+	})
+	defer func() { _ = v.Close() }()
+	require.NoError(t, v.ImportState(strings.NewReader(in)))
+
+	var buf bytes.Buffer
+	require.NoError(t, v.ExportState(&buf))
+	var st emailkit.State
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &st))
+	assert.True(t, st.Greylist["mx.example.com|user@example.com"].Equal(since))
+}
+
+func TestImportState_ProbeCounters(t *testing.T) {
+	today := time.Now().UTC().Format(time.DateOnly)
+	in := `{"version":1,` +
+		`"budgets":{"day":"` + today + `","probes":{"example.com":1}},` +
+		`"warmUp":{"day":"` + today + `","providers":[{"key":"google","probes":7,"accepted":6}]},` +
+		`"dns":[{"domain":"example.com","mx":[{"host":"mx.example.com.","pref":10}],"expires":"` +
+		time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}]}`
+
+	v := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Schedule:   &emailkit.ProbeSchedule{DailyBudget: map[string]int{"example.com": 1}},
+		WarmUp:     &emailkit.WarmUp{Start: time.Now()},
+	})
+	defer func() { _ = v.Close() }()
+	require.NoError(t, v.ImportState(strings.NewReader(in)))
+
+	// The budget spent before the restart still counts
+	res, err := v.Validate(context.Background(), "user@example.com")
+	require.NoError(t, err)
+	smtp, _ := res.CheckFor(emailkit.LevelSMTP)
+	assert.Equal(t, emailkit.CodeDeferred, smtp.Code)
+
+	var buf bytes.Buffer
+	require.NoError(t, v.ExportState(&buf))
+	var st emailkit.State
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &st))
+	require.NotNil(t, st.Budgets)
+	assert.Equal(t, emailkit.BudgetState{Day: today, Probes: map[string]int{"example.com": 1}}, *st.Budgets)
+	require.NotNil(t, st.WarmUp)
+	assert.Equal(t, []emailkit.WarmUpStatus{{Key: "google", Day: 1, Budget: 50, Probes: 7, Accepted: 6}}, st.WarmUp.Providers)
+
+	// Counters of another day are dropped
+	v2 := emailkit.New().WithSMTP(emailkit.SMTPOptions{
+		HeloDomain: "myapp.com",
+		MailFrom:   "verify@myapp.com",
+		Schedule:   &emailkit.ProbeSchedule{DailyBudget: map[string]int{"example.com": 1}},
+	})
+	defer func() { _ = v2.Close() }()
+	require.NoError(t, v2.ImportState(strings.NewReader(`{"version":1,"budgets":{"day":"2020-01-01","probes":{"example.com":1}}}`)))
+	buf.Reset()
+	require.NoError(t, v2.ExportState(&buf))
+	assert.NotContains(t, buf.String(), "budgets")
+}
+
+func TestImportState_Errors(t *testing.T) {
+	v := emailkit.New().WithDNS()
+	assert.Error(t, v.ImportState(strings.NewReader("not json")))
+	assert.ErrorContains(t, v.ImportState(strings.NewReader(`{"version":99}`)), "unsupported version")
+}
+
+func TestMXSnapshot(t *testing.T) {
+	ctx := context.Background()
+	warm := &countingResolver{stubResolver: stubResolver{
+		mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+	}}
+	v := emailkit.New().WithDNS().WithResolver(warm)
+	_, _ = v.Validate(ctx, "user@example.com")
+	_, _ = v.Validate(ctx, "user@nxdomain.example")
+
+	var buf bytes.Buffer
+	require.NoError(t, v.SaveMXSnapshot(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"format":"emailkit-mx-snapshot"`)
+
+	// An entry from last week is too old to trust
+	old := time.Now().Add(-7 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	buf.WriteString(`{"domain":"stale.example","mx":[{"host":"mx.stale.example.","pref":10}],"resolved":"` + old + `"}` + "\n")
+
+	cold := &countingResolver{}
+	v2 := emailkit.New().WithDNS().WithResolver(cold)
+	n, err := v2.LoadMXSnapshot(&buf, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	res, err := v2.Validate(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+	res, err = v2.Validate(ctx, "user@nxdomain.example")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	assert.Zero(t, cold.mxCalls.Load())
+
+	_, _ = v2.Validate(ctx, "user@stale.example")
+	assert.Equal(t, int32(1), cold.mxCalls.Load())
+
+	_, err = v2.LoadMXSnapshot(strings.NewReader(`{"version":1}`), time.Hour)
+	assert.ErrorContains(t, err, "not an MX snapshot")
+}
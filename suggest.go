@@ -0,0 +1,81 @@
+package emailkit
+
+import (
+	"context"
+	"strings"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+)
+
+// SuggestionResult is the outcome of ValidateWithSuggestion.
+type SuggestionResult struct {
+	// Result is the validation of the address as entered.
+	Result Result `json:"result"`
+	// Suggestion is the corrected address, e.g. "jane@gmail.com" for
+	// "jane@gmial.com"; empty if no typo was detected. It is meant for
+	// display and is not hashed in privacy mode.
+	Suggestion string `json:"suggestion,omitempty"`
+	// Suggested is the validation of Suggestion; nil if there is none.
+	Suggested *Result `json:"suggested,omitempty"`
+}
+
+// ValidateWithSuggestion validates email like Validate and, if a level
+// suggests a correction (the domain level's typo detection, see
+// WithDomain), validates the corrected address too, so a signup form can
+// offer a "did you mean" alternative that is known to work.
+//
+// The typo check runs even when an earlier level failed the address, e.g.
+// when the DNS level finds no MX for "gmial.com". No suggestion is made
+// for a run cut short by ctx or for allow- or blocklisted addresses.
+func (v *Validator) ValidateWithSuggestion(ctx context.Context, email string) (SuggestionResult, error) {
+	if v.err != nil {
+		return SuggestionResult{}, v.err
+	}
+	parsed := parse.NewEmail(email)
+	out := SuggestionResult{Result: v.run(ctx, email, true)}
+	if out.Result.Truncated || !parsed.Valid {
+		return out, nil
+	}
+
+	domain := v.suggestedDomain(ctx, out.Result, parsed)
+	if domain == "" {
+		return out, nil
+	}
+	out.Suggestion = domain
+	if !strings.Contains(domain, "@") {
+		out.Suggestion = parsed.Local + "@" + domain
+	}
+	if strings.EqualFold(out.Suggestion, parsed.Raw) {
+		out.Suggestion = ""
+		return out, nil
+	}
+	suggested := v.run(ctx, out.Suggestion, true)
+	out.Suggested = &suggested
+	return out, nil
+}
+
+// suggestedDomain returns the first suggestion of r's checks or, if the
+// pipeline stopped before a domain level ran, of the domain levels (they
+// make no network calls).
+func (v *Validator) suggestedDomain(ctx context.Context, r Result, parsed Email) string {
+	for _, c := range r.Checks {
+		if c.Level == LevelAllowlist || c.Level == LevelBlocklist {
+			return "" // the lists decided, not the pipeline
+		}
+		if c.Suggestion != "" {
+			return c.Suggestion
+		}
+	}
+	if _, ran := r.CheckFor(LevelDomain); ran {
+		return ""
+	}
+	for _, c := range v.checkers {
+		if dc, ok := c.(*check.DomainChecker); ok {
+			if cr := dc.Check(ctx, parsed); cr.Suggestion != "" {
+				return cr.Suggestion
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package emailkit_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+func TestValidateWithSuggestion(t *testing.T) {
+	v := emailkit.New().WithDomain()
+
+	out, err := v.ValidateWithSuggestion(context.Background(), "jane.doe@gmial.com")
+	require.NoError(t, err)
+	assert.True(t, out.Result.Valid, "a typo suspicion doesn't fail the address")
+	assert.Equal(t, "jane.doe@gmail.com", out.Suggestion)
+	require.NotNil(t, out.Suggested)
+	assert.Equal(t, "jane.doe@gmail.com", out.Suggested.Email)
+	assert.True(t, out.Suggested.Valid)
+
+	out, err = v.ValidateWithSuggestion(context.Background(), "jane.doe@gmail.com")
+	require.NoError(t, err)
+	assert.True(t, out.Result.Valid)
+	assert.Empty(t, out.Suggestion)
+	assert.Nil(t, out.Suggested)
+}
+
+func TestValidateWithSuggestion_AfterEarlierFailure(t *testing.T) {
+	r := &stubResolver{mx: map[string][]*net.MX{"gmail.com": {{Host: "gmail-smtp-in.l.google.com.", Pref: 5}}}}
+	v := emailkit.New().WithResolver(r).WithDNS().WithDomain()
+
+	// The DNS level fails gmial.com (no MX) before the domain level runs
+	out, err := v.ValidateWithSuggestion(context.Background(), "jane.doe@gmial.com")
+	require.NoError(t, err)
+	assert.False(t, out.Result.Valid)
+	_, ran := out.Result.CheckFor(emailkit.LevelDomain)
+	assert.False(t, ran)
+
+	assert.Equal(t, "jane.doe@gmail.com", out.Suggestion)
+	require.NotNil(t, out.Suggested)
+	assert.True(t, out.Suggested.Valid, "%+v", out.Suggested.Checks)
+}
+
+func TestValidateWithSuggestion_NoSuggestion(t *testing.T) {
+	ctx := context.Background()
+
+	// Lists decide without the pipeline
+	v := emailkit.New().WithDomain().WithAllowlist([]string{"gmial.com"}, nil)
+	out, err := v.ValidateWithSuggestion(ctx, "jane.doe@gmial.com")
+	require.NoError(t, err)
+	assert.True(t, out.Result.Valid)
+	assert.Empty(t, out.Suggestion)
+
+	// Without a domain level there is no typo detection
+	out, err = emailkit.New().ValidateWithSuggestion(ctx, "jane.doe@gmial.com")
+	require.NoError(t, err)
+	assert.Empty(t, out.Suggestion)
+
+	// A cancelled run suggests nothing
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	out, err = emailkit.New().WithDomain().ValidateWithSuggestion(cancelled, "jane.doe@gmial.com")
+	require.NoError(t, err)
+	assert.True(t, out.Result.Truncated)
+	assert.Nil(t, out.Suggested)
+
+	_, err = emailkit.New().WithBlocklist(nil, []string{"("}).ValidateWithSuggestion(ctx, "jane.doe@gmial.com")
+	assert.ErrorIs(t, err, emailkit.ErrInvalidBlocklist)
+}
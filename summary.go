@@ -0,0 +1,229 @@
+package emailkit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Summary tallies the results of a bulk run for machine-readable reports
+// and for gating automation (e.g. pre-campaign list checks) with
+// Thresholds. The zero value is ready to use; it is not safe for
+// concurrent use.
+type Summary struct {
+	Total int `json:"total"`
+	// Valid, Invalid, MailboxFull and Truncated partition Total: a full
+	// mailbox (CodeMailboxFull, temporarily undeliverable) and a truncated
+	// result count as neither valid nor invalid.
+	Valid       int `json:"valid"`
+	Invalid     int `json:"invalid"`
+	MailboxFull int `json:"mailboxFull"`
+	Truncated   int `json:"truncated"`
+	// Warn counts valid results with caveats (Severity() == SeverityWarn).
+	Warn int `json:"warn"`
+	// Codes counts the code of each invalid result's first failing check;
+	// failures without a code are counted under their level.
+	Codes map[string]int `json:"codes,omitempty"`
+	// FamilySizes counts the duplicate families of the run (see
+	// Families) by size: family size → number of families. An address
+	// listed twice counts once; addresses hashed in privacy mode are not
+	// grouped.
+	FamilySizes map[int]int `json:"familySizes,omitempty"`
+	// InFamilies counts the distinct addresses that belong to a family.
+	InFamilies int `json:"inFamilies,omitempty"`
+
+	families map[string]int      // family key → distinct addresses
+	seen     map[string]struct{} // lowercase addresses grouped so far
+}
+
+// Add counts r.
+func (s *Summary) Add(r Result) {
+	s.Total++
+	s.addFamily(r.Email)
+	switch {
+	case r.Truncated:
+		s.Truncated++
+	case !r.Valid && isMailboxFull(r):
+		s.MailboxFull++
+	case !r.Valid:
+		s.Invalid++
+		for _, c := range r.Checks {
+			if c.Passed {
+				continue
+			}
+			key := c.Code
+			if key == "" {
+				key = c.Level
+			}
+			if s.Codes == nil {
+				s.Codes = make(map[string]int)
+			}
+			s.Codes[key]++
+			break
+		}
+	default:
+		s.Valid++
+		if r.Severity() == SeverityWarn {
+			s.Warn++
+		}
+	}
+}
+
+// addFamily counts email in its duplicate family.
+func (s *Summary) addFamily(email string) {
+	key, ok := familyKey(email)
+	if !ok {
+		return
+	}
+	lower := strings.ToLower(email)
+	if _, dup := s.seen[lower]; dup {
+		return
+	}
+	if s.seen == nil {
+		s.seen = make(map[string]struct{})
+		s.families = make(map[string]int)
+	}
+	s.seen[lower] = struct{}{}
+	n := s.families[key]
+	s.families[key] = n + 1
+	if n == 0 {
+		return
+	}
+	if s.FamilySizes == nil {
+		s.FamilySizes = make(map[int]int)
+	}
+	if n == 1 {
+		s.InFamilies += 2
+	} else {
+		s.InFamilies++
+		if s.FamilySizes[n]--; s.FamilySizes[n] == 0 {
+			delete(s.FamilySizes, n)
+		}
+	}
+	s.FamilySizes[n+1]++
+}
+
+// isMailboxFull reports whether every failed check of r is a full mailbox.
+func isMailboxFull(r Result) bool {
+	failed := r.FailedChecks()
+	for _, c := range failed {
+		if c.Code != CodeMailboxFull {
+			return false
+		}
+	}
+	return len(failed) > 0
+}
+
+// Summarize tallies results.
+func Summarize(results []Result) Summary {
+	var s Summary
+	for _, r := range results {
+		s.Add(r)
+	}
+	return s
+}
+
+// rate returns n as a share of Total.
+func (s Summary) rate(n int) float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(n) / float64(s.Total)
+}
+
+// Thresholds are the highest tolerated shares (0..1) of a run's results,
+// e.g. MaxInvalid: 0.02 fails a list with more than 2% invalid addresses.
+// A zero threshold is disabled.
+type Thresholds struct {
+	// MaxInvalid bounds invalid results; exceeding it is SeverityError.
+	MaxInvalid float64
+	// MaxWarn bounds valid results with caveats; exceeding it is
+	// SeverityWarn.
+	MaxWarn float64
+	// MaxTruncated bounds results the run didn't finish; exceeding it is
+	// SeverityWarn.
+	MaxTruncated float64
+}
+
+// Breach is one exceeded threshold.
+type Breach struct {
+	Metric   string   `json:"metric"` // "invalid", "warn" or "truncated"
+	Severity Severity `json:"severity"`
+	Limit    float64  `json:"limit"`
+	Actual   float64  `json:"actual"`
+}
+
+// ThresholdError is returned by Summary.Check when a run exceeds its
+// Thresholds. It wraps ErrThresholdExceeded.
+type ThresholdError struct {
+	Breaches []Breach
+}
+
+func (e *ThresholdError) Error() string {
+	parts := make([]string, len(e.Breaches))
+	for i, b := range e.Breaches {
+		parts[i] = fmt.Sprintf("%s %.2f%% > %.2f%%", b.Metric, b.Actual*100, b.Limit*100)
+	}
+	return fmt.Sprintf("%v: %s", ErrThresholdExceeded, strings.Join(parts, "; "))
+}
+
+func (e *ThresholdError) Unwrap() error { return ErrThresholdExceeded }
+
+// Severity is the worst severity among the breaches.
+func (e *ThresholdError) Severity() Severity {
+	for _, b := range e.Breaches {
+		if b.Severity == SeverityError {
+			return SeverityError
+		}
+	}
+	return SeverityWarn
+}
+
+// Exit codes for command-line tools gating on a Summary: ExitOK within
+// thresholds, ExitWarn when only warning thresholds were exceeded,
+// ExitError when an error threshold was.
+const (
+	ExitOK    = 0
+	ExitWarn  = 1
+	ExitError = 2
+)
+
+// ExitCode maps the breaches to ExitWarn or ExitError.
+func (e *ThresholdError) ExitCode() int {
+	if e.Severity() == SeverityError {
+		return ExitError
+	}
+	return ExitWarn
+}
+
+// Check compares the summary with t and returns a *ThresholdError listing
+// every exceeded threshold, or nil.
+func (s Summary) Check(t Thresholds) error {
+	var breaches []Breach
+	for _, m := range []struct {
+		metric   string
+		severity Severity
+		limit    float64
+		n        int
+	}{
+		{"invalid", SeverityError, t.MaxInvalid, s.Invalid},
+		{"warn", SeverityWarn, t.MaxWarn, s.Warn},
+		{"truncated", SeverityWarn, t.MaxTruncated, s.Truncated},
+	} {
+		if m.limit > 0 && s.rate(m.n) > m.limit {
+			breaches = append(breaches, Breach{Metric: m.metric, Severity: m.severity, Limit: m.limit, Actual: s.rate(m.n)})
+		}
+	}
+	if breaches == nil {
+		return nil
+	}
+	return &ThresholdError{Breaches: breaches}
+}
+
+// ExitCode is the exit code a command-line tool should use for this run
+// under t: ExitOK, ExitWarn or ExitError.
+func (s Summary) ExitCode(t Thresholds) int {
+	if err, ok := s.Check(t).(*ThresholdError); ok {
+		return err.ExitCode()
+	}
+	return ExitOK
+}
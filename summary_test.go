@@ -0,0 +1,62 @@
+package emailkit_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+func TestSummary(t *testing.T) {
+	results := []emailkit.Result{
+		{Valid: true, Checks: []emailkit.CheckResult{{Level: emailkit.LevelSyntax, Passed: true}}},
+		{Valid: true, Checks: []emailkit.CheckResult{{Level: emailkit.LevelDomain, Passed: true, Suggestion: "gmail.com"}}},
+		{Valid: false, Checks: []emailkit.CheckResult{{Level: emailkit.LevelSyntax, Passed: false}}},
+		{Valid: false, Checks: []emailkit.CheckResult{
+			{Level: emailkit.LevelSyntax, Passed: true},
+			{Level: emailkit.LevelDNS, Passed: false, Code: emailkit.CodeBadDomain},
+		}},
+		{Valid: false, Checks: []emailkit.CheckResult{{Level: emailkit.LevelSMTP, Passed: false, Code: emailkit.CodeMailboxFull}}},
+		{Truncated: true},
+	}
+	s := emailkit.Summarize(results)
+	assert.Equal(t, 6, s.Total)
+	assert.Equal(t, 2, s.Valid)
+	assert.Equal(t, 2, s.Invalid)
+	assert.Equal(t, 1, s.MailboxFull, "full mailboxes are not invalid")
+	assert.Equal(t, 1, s.Truncated)
+	assert.Equal(t, 1, s.Warn)
+	assert.Equal(t, map[string]int{emailkit.LevelSyntax: 1, emailkit.CodeBadDomain: 1}, s.Codes)
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total":6,"valid":2,"invalid":2,"mailboxFull":1,"truncated":1,"warn":1,"codes":{"syntax":1,"bad_domain":1}}`, string(data))
+}
+
+func TestSummary_Check(t *testing.T) {
+	s := emailkit.Summary{Total: 100, Valid: 95, Invalid: 3, Truncated: 2, Warn: 10}
+
+	require.NoError(t, s.Check(emailkit.Thresholds{}), "zero thresholds are disabled")
+	require.NoError(t, s.Check(emailkit.Thresholds{MaxInvalid: 0.05, MaxWarn: 0.2}))
+	assert.Equal(t, emailkit.ExitOK, s.ExitCode(emailkit.Thresholds{MaxInvalid: 0.05}))
+
+	err := s.Check(emailkit.Thresholds{MaxWarn: 0.05})
+	require.ErrorIs(t, err, emailkit.ErrThresholdExceeded)
+	var te *emailkit.ThresholdError
+	require.ErrorAs(t, err, &te)
+	assert.Equal(t, emailkit.SeverityWarn, te.Severity())
+	assert.Equal(t, emailkit.ExitWarn, te.ExitCode())
+
+	err = s.Check(emailkit.Thresholds{MaxInvalid: 0.02, MaxTruncated: 0.01})
+	require.ErrorAs(t, err, &te)
+	require.Len(t, te.Breaches, 2)
+	assert.Equal(t, emailkit.Breach{Metric: "invalid", Severity: emailkit.SeverityError, Limit: 0.02, Actual: 0.03}, te.Breaches[0])
+	assert.Equal(t, "truncated", te.Breaches[1].Metric)
+	assert.Equal(t, emailkit.ExitError, s.ExitCode(emailkit.Thresholds{MaxInvalid: 0.02, MaxTruncated: 0.01}))
+	assert.Contains(t, err.Error(), "invalid 3.00% > 2.00%")
+
+	assert.NoError(t, emailkit.Summary{}.Check(emailkit.Thresholds{MaxInvalid: 0.01}), "an empty run passes")
+}
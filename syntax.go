@@ -0,0 +1,114 @@
+package emailkit
+
+import (
+	"context"
+
+	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/parse"
+)
+
+var fallbackSyntax = check.NewSyntaxChecker()
+
+// IsValidSyntax reports whether s passes the syntax level, without building
+// a Validator. Plain ASCII addresses (the common case) are decided by an
+// allocation-free scanner; anything else — Unicode, quoted local parts,
+// IP literals, Punycode labels, surrounding whitespace — falls back to the
+// full parser, so the answer always matches New().Validate.
+func IsValidSyntax(s string) bool {
+	if valid, decided := fastSyntax(s); decided {
+		return valid
+	}
+	return fallbackSyntax.Check(context.Background(), parse.NewEmail(s)).Passed
+}
+
+// Character classes for fastSyntax.
+const (
+	classAtext = 1 << iota // RFC 5322 atext, as accepted by check/syntax.go
+	classLDH               // letter, digit or hyphen
+	classDigit
+)
+
+var charClass = func() (t [256]uint8) {
+	for c := 'a'; c <= 'z'; c++ {
+		t[c] = classAtext | classLDH
+		t[c-'a'+'A'] = classAtext | classLDH
+	}
+	for c := '0'; c <= '9'; c++ {
+		t[c] = classAtext | classLDH | classDigit
+	}
+	for _, c := range "!#$%&'*+/=?^_`{|}~" {
+		t[c] = classAtext
+	}
+	t['-'] = classAtext | classLDH
+	return t
+}()
+
+// fastSyntax checks a plain ASCII address. decided is false when s contains
+// anything outside the simple grammar and the full parser must decide.
+func fastSyntax(s string) (valid, decided bool) {
+	if len(s) == 0 || len(s) > 254 {
+		return false, true
+	}
+
+	// Local part: atext with single interior dots
+	at := -1
+	prevDot := true // a leading dot is invalid
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch == '@' {
+			at = i
+			break
+		}
+		if ch == '.' {
+			if prevDot {
+				return false, true
+			}
+			prevDot = true
+			continue
+		}
+		if charClass[ch]&classAtext == 0 {
+			return false, false
+		}
+		prevDot = false
+	}
+	if at < 1 || at > 64 || prevDot {
+		return false, true
+	}
+
+	// Domain: at least two LDH labels, no Punycode, TLD not all digits
+	labels := 0
+	start := at + 1
+	allDigits := true
+	for i := start; i <= len(s); i++ {
+		if i < len(s) && s[i] != '.' {
+			c := charClass[s[i]]
+			if c&classLDH == 0 {
+				if s[i] == '@' {
+					return false, true
+				}
+				return false, false
+			}
+			if c&classDigit == 0 {
+				allDigits = false
+			}
+			continue
+		}
+		n := i - start
+		if n == 0 || n > 63 || s[start] == '-' || s[i-1] == '-' {
+			return false, true
+		}
+		if n >= 4 && s[start+2] == '-' && s[start+3] == '-' && (s[start]|0x20) == 'x' && (s[start+1]|0x20) == 'n' {
+			return false, false
+		}
+		labels++
+		if i == len(s) && allDigits {
+			return false, true
+		}
+		start = i + 1
+		allDigits = true
+	}
+	if labels < 2 {
+		return false, true
+	}
+	return true, true
+}
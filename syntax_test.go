@@ -0,0 +1,100 @@
+package emailkit_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	emailkit "github.com/optimode/emailkit"
+)
+
+var syntaxCorpus = []string{
+	"user@example.com",
+	"John.Doe+tag@Sub.Example.CO.uk",
+	"a!#$%&'*+/=?^_`{|}~-b@example.com",
+	"x@a.b",
+	"",
+	"@example.com",
+	"user@",
+	"user",
+	"a@b@example.com",
+	".user@example.com",
+	"user.@example.com",
+	"us..er@example.com",
+	"user@localhost",
+	"user@example..com",
+	"user@-example.com",
+	"user@example-.com",
+	"user@example.123",
+	"user@123.example.com",
+	"user@example.com.",
+	"user@.example.com",
+	"user@exa_mple.com",
+	"user name@example.com",
+	" user@example.com ",
+	`"quoted local"@example.com`,
+	"user@[192.168.0.1]",
+	"user@münchen.de",
+	"用户@example.com",
+	"user@xn--mnchen-3ya.de",
+	"user@xn--abc-.com",
+	"user@XN--MNCHEN-3YA.DE",
+	"user,x@example.com",
+	"user@example.com\n",
+	"John Doe <user@example.com>",
+	"abcdefghijabcdefghijabcdefghijabcdefghijabcdefghijabcdefghijabcd@example.com",
+	"abcdefghijabcdefghijabcdefghijabcdefghijabcdefghijabcdefghijabcde@example.com",
+	"user@abcdefghijabcdefghijabcdefghijabcdefghijabcdefghijabcdefghijabc.com",
+	"user@abcdefghijabcdefghijabcdefghijabcdefghijabcdefghijabcdefghijabcd.com",
+}
+
+// fullSyntax is the reference answer: the syntax level of a Validator.
+func fullSyntax(s string) bool {
+	res, _ := emailkit.New().Validate(context.Background(), s)
+	return res.Valid
+}
+
+func TestIsValidSyntax_MatchesValidator(t *testing.T) {
+	for _, s := range syntaxCorpus {
+		assert.Equal(t, fullSyntax(s), emailkit.IsValidSyntax(s), "%q", s)
+	}
+}
+
+func TestIsValidSyntax_RandomMatchesValidator(t *testing.T) {
+	const alphabet = "aZ09.-_+@@..--x n\"[]ü"
+	rng := rand.New(rand.NewSource(1))
+	buf := make([]byte, 0, 24)
+	for i := 0; i < 20000; i++ {
+		buf = buf[:0]
+		for n := 3 + rng.Intn(20); n > 0; n-- {
+			buf = append(buf, alphabet[rng.Intn(len(alphabet))])
+		}
+		s := string(buf)
+		if !assert.Equal(t, fullSyntax(s), emailkit.IsValidSyntax(s), "%q", s) {
+			return
+		}
+	}
+}
+
+func TestIsValidSyntax_NoAllocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = emailkit.IsValidSyntax("John.Doe+tag@mail.example.com")
+	})
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkIsValidSyntax(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = emailkit.IsValidSyntax("John.Doe+tag@mail.example.com")
+	}
+}
+
+func BenchmarkIsValidSyntax_Fallback(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = emailkit.IsValidSyntax("用户@münchen.de")
+	}
+}
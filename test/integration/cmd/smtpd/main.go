@@ -0,0 +1,92 @@
+// Command smtpd runs the emailkit test SMTP server (package
+// test/integration/smtpd) for conformance runs against real DNS: point the
+// MX record of a throwaway domain at the host and run the integration
+// suite with EMAILKIT_IT_DOMAIN set (see test/integration/README.md).
+//
+//	go run ./test/integration/cmd/smtpd -listen :25 -mode strict -users known
+//
+// Flags:
+//
+//	-listen addr         address to listen on (default :25)
+//	-mode mode           strict, greylist, catch-all, tarpit or starttls (default strict)
+//	-hostname name       name in the banner, EHLO reply and TLS certificate
+//	-users list          comma-separated existing mailboxes (addresses or local parts)
+//	-greylist-delay d    how long greylist mode refuses a new triplet
+//	-tarpit-delay d      wait before every reply in tarpit mode (default 5s)
+//	-cert file, -key file  TLS certificate for STARTTLS (default self-signed)
+//	-v                   log every command and reply
+//
+// The server never delivers mail; it stops on SIGINT or SIGTERM.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/optimode/emailkit/test/integration/smtpd"
+)
+
+func main() {
+	listen := flag.String("listen", ":25", "address to listen on")
+	mode := flag.String("mode", string(smtpd.ModeStrict), "strict, greylist, catch-all, tarpit or starttls")
+	hostname := flag.String("hostname", "", "name in the banner, EHLO reply and TLS certificate (default: the host name)")
+	users := flag.String("users", "", "comma-separated existing mailboxes (addresses or local parts)")
+	greylistDelay := flag.Duration("greylist-delay", 0, "how long greylist mode refuses a new triplet")
+	tarpitDelay := flag.Duration("tarpit-delay", 0, "wait before every reply in tarpit mode (default 5s)")
+	cert := flag.String("cert", "", "TLS certificate file for STARTTLS (default: self-signed)")
+	key := flag.String("key", "", "TLS key file for STARTTLS")
+	verbose := flag.Bool("v", false, "log every command and reply")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: smtpd [flags]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := smtpd.Config{
+		Mode:          smtpd.Mode(*mode),
+		Hostname:      *hostname,
+		GreylistDelay: *greylistDelay,
+		TarpitDelay:   *tarpitDelay,
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname, _ = os.Hostname()
+	}
+	for _, u := range strings.Split(*users, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			cfg.Users = append(cfg.Users, u)
+		}
+	}
+	if *cert != "" || *key != "" {
+		pair, err := tls.LoadX509KeyPair(*cert, *key)
+		if err != nil {
+			log.Fatalf("smtpd: %v", err)
+		}
+		cfg.TLSConfig = &tls.Config{Certificates: []tls.Certificate{pair}, MinVersion: tls.VersionTLS12}
+	}
+	if *verbose {
+		cfg.Logf = log.Printf
+	}
+
+	s, err := smtpd.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		_ = s.Close()
+	}()
+
+	log.Printf("smtpd: %s mode on %s as %s", cfg.Mode, *listen, cfg.Hostname)
+	if err := s.ListenAndServe(*listen); err != nil {
+		log.Fatalf("smtpd: %v", err)
+	}
+}
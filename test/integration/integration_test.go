@@ -0,0 +1,280 @@
+//go:build integration
+
+// Package integration_test runs emailkit against real SMTP sessions: the
+// local suite starts a test server (package smtpd) on the loopback
+// interface in every mode, and the network suite probes a deployed server
+// through real DNS when EMAILKIT_IT_DOMAIN is set. Run with
+//
+//	go test -tags integration ./test/integration/...
+package integration_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/test/integration/smtpd"
+)
+
+// loopback resolves every domain's MX to 127.0.0.1.
+type loopback struct{}
+
+func (loopback) LookupMX(context.Context, string) ([]*net.MX, error) {
+	return []*net.MX{{Host: "127.0.0.1.", Pref: 10}}, nil
+}
+
+func (loopback) LookupHost(context.Context, string) ([]string, error) {
+	return []string{"127.0.0.1"}, nil
+}
+
+func (loopback) LookupNS(_ context.Context, name string) ([]*net.NS, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (loopback) LookupTXT(_ context.Context, name string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (loopback) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	return []string{"localhost."}, nil
+}
+
+func smtpOptions(port string) emailkit.SMTPOptions {
+	return emailkit.SMTPOptions{
+		HeloDomain:     "client.emailkit.test",
+		MailFrom:       "verify@client.emailkit.test",
+		Port:           port,
+		ConnectTimeout: 2 * time.Second,
+		CommandTimeout: 5 * time.Second,
+		MaxMXHosts:     1,
+	}
+}
+
+// local starts a server in cfg.Mode and returns a Validator probing it.
+func local(t *testing.T, cfg smtpd.Config, opts ...func(*emailkit.SMTPOptions)) (*smtpd.Server, *emailkit.Validator) {
+	t.Helper()
+	s, err := smtpd.Start(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	o := smtpOptions(s.Port())
+	for _, opt := range opts {
+		opt(&o)
+	}
+	v := emailkit.New().WithResolver(loopback{}).WithDNS().WithSMTP(o)
+	t.Cleanup(func() { _ = v.Close() })
+	return s, v
+}
+
+func smtpCheck(t *testing.T, r emailkit.Result) emailkit.CheckResult {
+	t.Helper()
+	c, ok := r.CheckFor(emailkit.LevelSMTP)
+	require.True(t, ok, "no SMTP check in %+v", r)
+	return c
+}
+
+func TestLocal_Strict(t *testing.T) {
+	_, v := local(t, smtpd.Config{Mode: smtpd.ModeStrict, Users: []string{"known"}})
+	ctx := context.Background()
+
+	r, err := v.Validate(ctx, "known@example.com")
+	require.NoError(t, err)
+	assert.True(t, r.Valid, "%+v", r.Checks)
+	assert.Equal(t, 250, smtpCheck(t, r).SMTPCode)
+
+	r, err = v.Validate(ctx, "unknown@example.com")
+	require.NoError(t, err)
+	assert.False(t, r.Valid)
+	assert.Equal(t, 550, smtpCheck(t, r).SMTPCode)
+	assert.Equal(t, emailkit.CodeMailboxUnknown, smtpCheck(t, r).Code)
+}
+
+func TestLocal_Connect(t *testing.T) {
+	s, err := smtpd.Start(smtpd.Config{Mode: smtpd.ModeStrict, Users: []string{"known"}})
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	v := emailkit.New().WithResolver(loopback{}).WithDNS().WithSMTPConnect(emailkit.SMTPOptions{
+		HeloDomain: "client.emailkit.test",
+		Port:       s.Port(),
+	})
+	defer func() { _ = v.Close() }()
+
+	// Unknown mailboxes pass too: only the session is checked
+	r, err := v.Validate(context.Background(), "unknown@example.com")
+	require.NoError(t, err)
+	assert.True(t, r.Valid, "%+v", r.Checks)
+	assert.Equal(t, "connect", smtpCheck(t, r).Meta["probe"])
+}
+
+func TestLocal_Greylist(t *testing.T) {
+	_, v := local(t, smtpd.Config{Mode: smtpd.ModeGreylist, Users: []string{"known"}})
+	ctx := context.Background()
+
+	r, err := v.Validate(ctx, "known@example.com")
+	require.NoError(t, err)
+	assert.False(t, r.Valid)
+	assert.Equal(t, emailkit.CodeGreylisted, smtpCheck(t, r).Code)
+	assert.Equal(t, 451, smtpCheck(t, r).SMTPCode)
+
+	// The retry passes the greylister
+	r, err = v.Validate(ctx, "known@example.com")
+	require.NoError(t, err)
+	assert.True(t, r.Valid, "%+v", r.Checks)
+}
+
+func TestLocal_GreylistStore(t *testing.T) {
+	_, v := local(t, smtpd.Config{Mode: smtpd.ModeGreylist, Users: []string{"known"}}, func(o *emailkit.SMTPOptions) {
+		o.Greylist = emailkit.NewMemoryGreylistStore()
+		o.GreylistWindow = time.Hour
+	})
+	ctx := context.Background()
+
+	for range 2 {
+		r, err := v.Validate(ctx, "known@example.com")
+		require.NoError(t, err)
+		c := smtpCheck(t, r)
+		assert.Equal(t, emailkit.CodeGreylisted, c.Code)
+		assert.NotEmpty(t, c.Meta["retry_after"])
+	}
+}
+
+func TestLocal_CatchAll(t *testing.T) {
+	_, v := local(t, smtpd.Config{Mode: smtpd.ModeCatchAll})
+	ctx := context.Background()
+
+	// A generic catch-all server is indistinguishable from a real mailbox
+	r, err := v.Validate(ctx, "x7fk2q9z@example.com")
+	require.NoError(t, err)
+	assert.True(t, r.Valid)
+	assert.Empty(t, smtpCheck(t, r).Code)
+
+	_, v = local(t, smtpd.Config{Mode: smtpd.ModeCatchAll}, func(o *emailkit.SMTPOptions) {
+		o.CatchAllFingerprints = []emailkit.CatchAllFingerprint{{
+			Name:   "smtpd",
+			Reply:  regexp.MustCompile(`all recipients accepted`),
+			Reason: "the test server accepts every recipient",
+		}}
+	})
+	r, err = v.Validate(ctx, "x7fk2q9z@example.com")
+	require.NoError(t, err)
+	assert.True(t, r.Valid)
+	assert.Equal(t, emailkit.CodeCatchAll, smtpCheck(t, r).Code)
+	assert.Equal(t, "smtpd", smtpCheck(t, r).Meta["catch_all"])
+}
+
+func TestLocal_Tarpit(t *testing.T) {
+	cfg := smtpd.Config{Mode: smtpd.ModeTarpit, Users: []string{"known"}, TarpitDelay: 200 * time.Millisecond}
+
+	// Banner, EHLO, MAIL and RCPT take 800ms, over the command timeout
+	_, v := local(t, cfg, func(o *emailkit.SMTPOptions) { o.CommandTimeout = 500 * time.Millisecond })
+	r, err := v.Validate(context.Background(), "known@example.com")
+	require.NoError(t, err)
+	assert.False(t, r.Valid)
+	assert.Contains(t, smtpCheck(t, r).Details, "SMTP probe failed on all MX hosts")
+	assert.Contains(t, smtpCheck(t, r).Details, "timeout")
+
+	_, v = local(t, cfg)
+	r, err = v.Validate(context.Background(), "known@example.com")
+	require.NoError(t, err)
+	assert.True(t, r.Valid, "%+v", r.Checks)
+}
+
+func TestLocal_StartTLS(t *testing.T) {
+	_, v := local(t, smtpd.Config{Mode: smtpd.ModeStartTLS, Hostname: "mx.emailkit.test", Users: []string{"known"}})
+	ctx := context.Background()
+
+	report, err := v.InspectDomain(ctx, "example.com")
+	require.NoError(t, err)
+	require.Len(t, report.MX, 1)
+	assert.True(t, report.MX[0].StartTLS)
+	assert.Contains(t, report.MX[0].Banner, "mx.emailkit.test")
+
+	// By default the probe doesn't negotiate TLS: servers that require it
+	// refuse MAIL FROM, and the address can't be verified
+	r, err := v.Validate(ctx, "known@example.com")
+	require.NoError(t, err)
+	assert.False(t, r.Valid)
+	assert.Equal(t, 530, smtpCheck(t, r).SMTPCode)
+
+	_, v = local(t, smtpd.Config{Mode: smtpd.ModeStartTLS, Hostname: "mx.emailkit.test", Users: []string{"known"}}, func(o *emailkit.SMTPOptions) {
+		o.TLSMode = emailkit.TLSModeSTARTTLS
+		o.TLSConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // self-signed test server
+	})
+	r, err = v.Validate(ctx, "known@example.com")
+	require.NoError(t, err)
+	assert.True(t, r.Valid, "%+v", r.Checks)
+	assert.Equal(t, 250, smtpCheck(t, r).SMTPCode)
+}
+
+// TestNetwork probes a deployed smtpd (test/integration/cmd/smtpd)
+// through real DNS. Configure it with:
+//
+//	EMAILKIT_IT_DOMAIN  throwaway domain whose MX points at the server (required)
+//	EMAILKIT_IT_MODE    the server's -mode (default strict)
+//	EMAILKIT_IT_USER    a mailbox in the server's -users (default known)
+//	EMAILKIT_IT_PORT    SMTP port (default 25)
+func TestNetwork(t *testing.T) {
+	domain := os.Getenv("EMAILKIT_IT_DOMAIN")
+	if domain == "" {
+		t.Skip("EMAILKIT_IT_DOMAIN not set")
+	}
+	mode := smtpd.Mode(getenv("EMAILKIT_IT_MODE", string(smtpd.ModeStrict)))
+	user := getenv("EMAILKIT_IT_USER", "known")
+	port := getenv("EMAILKIT_IT_PORT", "25")
+
+	o := smtpOptions(port)
+	o.ConnectTimeout, o.CommandTimeout = 10*time.Second, 30*time.Second
+	v := emailkit.New().WithDNS().WithSMTP(o)
+	defer func() { _ = v.Close() }()
+	ctx := context.Background()
+
+	report, err := v.InspectDomain(ctx, domain)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.MX, "no MX for %s", domain)
+	for _, mx := range report.MX {
+		assert.Empty(t, mx.SMTPError, "%s", mx.Host)
+		assert.Contains(t, mx.Banner, "emailkit test server", "%s is not a test server", mx.Host)
+	}
+
+	known := user + "@" + domain
+	unknown := "nobody-" + strconv.FormatInt(time.Now().UnixNano(), 36) + "@" + domain
+	switch mode {
+	case smtpd.ModeStrict:
+		r, err := v.Validate(ctx, known)
+		require.NoError(t, err)
+		assert.True(t, r.Valid, "%+v", r.Checks)
+		r, err = v.Validate(ctx, unknown)
+		require.NoError(t, err)
+		assert.Equal(t, emailkit.CodeMailboxUnknown, smtpCheck(t, r).Code)
+	case smtpd.ModeGreylist:
+		r, err := v.Validate(ctx, unknown)
+		require.NoError(t, err)
+		assert.Equal(t, emailkit.CodeGreylisted, smtpCheck(t, r).Code)
+	case smtpd.ModeCatchAll:
+		r, err := v.Validate(ctx, unknown)
+		require.NoError(t, err)
+		assert.True(t, r.Valid, "%+v", r.Checks)
+	case smtpd.ModeStartTLS:
+		r, err := v.Validate(ctx, known)
+		require.NoError(t, err)
+		assert.Equal(t, 530, smtpCheck(t, r).SMTPCode)
+	default:
+		t.Skipf("no network expectations for mode %q", mode)
+	}
+}
+
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,507 @@
+// Package smtpd is a small SMTP server for integration and conformance
+// tests. It speaks enough of RFC 5321 for recipient verification —
+// EHLO/HELO, STARTTLS, MAIL, RCPT, RSET, NOOP, VRFY, DATA and QUIT — and
+// misbehaves on purpose in the ways real MX hosts do: greylisting,
+// accepting every recipient, or answering slowly (see Mode).
+//
+// Messages sent with DATA are read and discarded; the server never
+// delivers mail. Run it locally in tests with Start, or deploy it with
+// test/integration/cmd/smtpd behind the MX record of a throwaway domain.
+package smtpd
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mode selects how the server answers recipients.
+type Mode string
+
+const (
+	// ModeStrict accepts the configured Users and rejects every other
+	// recipient with 550 5.1.1.
+	ModeStrict Mode = "strict"
+	// ModeGreylist answers the first RCPT of every client IP, sender and
+	// recipient triplet with 451 4.7.1 and behaves like ModeStrict once
+	// GreylistDelay has passed.
+	ModeGreylist Mode = "greylist"
+	// ModeCatchAll accepts every recipient, replying "250 2.1.5 Ok, all
+	// recipients accepted".
+	ModeCatchAll Mode = "catch-all"
+	// ModeTarpit behaves like ModeStrict but waits TarpitDelay before
+	// every reply, the banner included.
+	ModeTarpit Mode = "tarpit"
+	// ModeStartTLS behaves like ModeStrict and requires STARTTLS before
+	// MAIL FROM (530 5.7.0 otherwise).
+	ModeStartTLS Mode = "starttls"
+)
+
+// Modes lists every Mode.
+var Modes = []Mode{ModeStrict, ModeGreylist, ModeCatchAll, ModeTarpit, ModeStartTLS}
+
+// Config configures a Server.
+type Config struct {
+	// Mode selects the recipient behavior. Default: ModeStrict
+	Mode Mode
+	// Hostname is announced in the banner and the EHLO reply, and is the
+	// name of the generated TLS certificate. Default: "localhost"
+	Hostname string
+	// Users are the mailboxes that exist: full addresses, or local parts
+	// that exist in every domain. Matched case-insensitively.
+	Users []string
+	// GreylistDelay is how long ModeGreylist refuses a new triplet.
+	// Default: 0 (the first retry is accepted)
+	GreylistDelay time.Duration
+	// TarpitDelay is the wait before every reply in ModeTarpit. Default: 5s
+	TarpitDelay time.Duration
+	// TLSConfig is used for STARTTLS, which is advertised in every mode.
+	// Default: a self-signed certificate for Hostname
+	TLSConfig *tls.Config
+	// Logf, if set, receives one line per command and reply.
+	Logf func(format string, args ...any)
+}
+
+// Server is a test SMTP server. It is safe for concurrent use.
+type Server struct {
+	cfg   Config
+	users map[string]bool
+
+	mu        sync.Mutex
+	listeners []net.Listener
+	conns     map[net.Conn]struct{}
+	triplets  map[string]time.Time // greylisting: first attempt per triplet
+	closed    bool
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// New returns a Server for cfg. It fails on an unknown Mode or when the
+// TLS certificate can't be generated.
+func New(cfg Config) (*Server, error) {
+	if cfg.Mode == "" {
+		cfg.Mode = ModeStrict
+	}
+	if !validMode(cfg.Mode) {
+		return nil, fmt.Errorf("smtpd: unknown mode %q", cfg.Mode)
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname = "localhost"
+	}
+	if cfg.TarpitDelay <= 0 {
+		cfg.TarpitDelay = 5 * time.Second
+	}
+	if cfg.TLSConfig == nil {
+		tc, err := selfSigned(cfg.Hostname)
+		if err != nil {
+			return nil, fmt.Errorf("smtpd: TLS certificate: %w", err)
+		}
+		cfg.TLSConfig = tc
+	}
+	s := &Server{
+		cfg:      cfg,
+		users:    make(map[string]bool, len(cfg.Users)),
+		conns:    make(map[net.Conn]struct{}),
+		triplets: make(map[string]time.Time),
+		done:     make(chan struct{}),
+	}
+	for _, u := range cfg.Users {
+		s.users[strings.ToLower(strings.TrimSpace(u))] = true
+	}
+	return s, nil
+}
+
+// Start returns a Server for cfg serving on a random port of 127.0.0.1.
+// Close it when done.
+func Start(cfg Config) (*Server, error) {
+	s, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.listeners = append(s.listeners, l)
+	s.mu.Unlock()
+	go func() { _ = s.serve(l) }()
+	return s, nil
+}
+
+// Addr returns the address of the first listener, or nil before Serve or
+// Start.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.listeners) == 0 {
+		return nil
+	}
+	return s.listeners[0].Addr()
+}
+
+// Port returns the port of Addr, e.g. to set SMTPOptions.Port.
+func (s *Server) Port() string {
+	addr := s.Addr()
+	if addr == nil {
+		return ""
+	}
+	_, port, _ := net.SplitHostPort(addr.String())
+	return port
+}
+
+// ListenAndServe listens on the TCP address addr and calls Serve.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on l until Close. It returns nil after Close.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = l.Close()
+		return nil
+	}
+	s.listeners = append(s.listeners, l)
+	s.mu.Unlock()
+	return s.serve(l)
+}
+
+func (s *Server) serve(l net.Listener) error {
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return nil
+			default:
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+			return err
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = c.Close()
+			return nil
+		}
+		s.conns[c] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.wg.Done()
+			defer s.forget(c)
+			s.handle(c)
+		}()
+	}
+}
+
+// Close stops the listeners, drops open sessions and waits for them to
+// end.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	for _, l := range s.listeners {
+		_ = l.Close()
+	}
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) forget(c net.Conn) {
+	_ = c.Close()
+	s.mu.Lock()
+	delete(s.conns, c)
+	s.mu.Unlock()
+}
+
+// session is the state of one connection.
+type session struct {
+	conn   net.Conn
+	text   *textproto.Conn
+	client string // client IP
+	helo   bool
+	tls    bool
+	from   string
+	rcpts  int
+}
+
+func (s *Server) handle(c net.Conn) {
+	ss := &session{conn: c, text: textproto.NewConn(c), client: hostOf(c.RemoteAddr())}
+	if !s.reply(ss, 220, "%s ESMTP emailkit test server (%s)", s.cfg.Hostname, s.cfg.Mode) {
+		return
+	}
+	for {
+		line, err := ss.text.ReadLine()
+		if err != nil {
+			return
+		}
+		s.logf("%s < %s", ss.client, line)
+		verb, arg, _ := strings.Cut(line, " ")
+		if !s.command(ss, strings.ToUpper(verb), arg) {
+			return
+		}
+	}
+}
+
+// command runs one command. It returns false when the session is over.
+func (s *Server) command(ss *session, verb, arg string) bool {
+	switch verb {
+	case "EHLO":
+		if arg == "" {
+			return s.reply(ss, 501, "5.5.4 EHLO requires a domain")
+		}
+		ss.helo = true
+		ss.reset()
+		ext := []string{s.cfg.Hostname, "PIPELINING", "SIZE 10240000", "8BITMIME", "ENHANCEDSTATUSCODES"}
+		if !ss.tls {
+			ext = append(ext, "STARTTLS")
+		}
+		return s.replyLines(ss, 250, ext)
+	case "HELO":
+		if arg == "" {
+			return s.reply(ss, 501, "5.5.4 HELO requires a domain")
+		}
+		ss.helo = true
+		ss.reset()
+		return s.reply(ss, 250, "%s", s.cfg.Hostname)
+	case "STARTTLS":
+		if ss.tls {
+			return s.reply(ss, 503, "5.5.1 TLS already active")
+		}
+		if !s.reply(ss, 220, "2.0.0 Ready to start TLS") {
+			return false
+		}
+		tc := tls.Server(ss.conn, s.cfg.TLSConfig)
+		if err := tc.Handshake(); err != nil {
+			s.logf("%s TLS handshake: %v", ss.client, err)
+			return false
+		}
+		ss.conn, ss.text, ss.tls, ss.helo = tc, textproto.NewConn(tc), true, false
+		ss.reset()
+		return true
+	case "MAIL":
+		switch {
+		case !ss.helo:
+			return s.reply(ss, 503, "5.5.1 Send EHLO first")
+		case s.cfg.Mode == ModeStartTLS && !ss.tls:
+			return s.reply(ss, 530, "5.7.0 Must issue a STARTTLS command first")
+		case ss.from != "":
+			return s.reply(ss, 503, "5.5.1 Nested MAIL command")
+		}
+		from, ok := path(arg, "FROM:")
+		if !ok {
+			return s.reply(ss, 501, "5.5.4 Syntax: MAIL FROM:<address>")
+		}
+		ss.from = from
+		if from == "" {
+			ss.from = "<>"
+		}
+		return s.reply(ss, 250, "2.1.0 Ok")
+	case "RCPT":
+		if ss.from == "" {
+			return s.reply(ss, 503, "5.5.1 Send MAIL first")
+		}
+		rcpt, ok := path(arg, "TO:")
+		if !ok || rcpt == "" {
+			return s.reply(ss, 501, "5.5.4 Syntax: RCPT TO:<address>")
+		}
+		code, msg := s.recipient(ss, rcpt)
+		if code < 300 {
+			ss.rcpts++
+		}
+		return s.reply(ss, code, "%s", msg)
+	case "DATA":
+		if ss.rcpts == 0 {
+			return s.reply(ss, 503, "5.5.1 No valid recipients")
+		}
+		if !s.reply(ss, 354, "End data with <CR><LF>.<CR><LF>") {
+			return false
+		}
+		if _, err := ss.text.ReadDotBytes(); err != nil {
+			return false
+		}
+		ss.reset()
+		return s.reply(ss, 250, "2.0.0 Ok: discarded, this server delivers nothing")
+	case "RSET":
+		ss.reset()
+		return s.reply(ss, 250, "2.0.0 Ok")
+	case "NOOP":
+		return s.reply(ss, 250, "2.0.0 Ok")
+	case "VRFY":
+		return s.reply(ss, 252, "2.5.2 Cannot VRFY user, but will accept message and attempt delivery")
+	case "QUIT":
+		s.reply(ss, 221, "2.0.0 Bye")
+		return false
+	default:
+		return s.reply(ss, 502, "5.5.2 Command not recognized")
+	}
+}
+
+// recipient decides the RCPT reply for rcpt.
+func (s *Server) recipient(ss *session, rcpt string) (int, string) {
+	if s.cfg.Mode == ModeCatchAll {
+		return 250, "2.1.5 Ok, all recipients accepted"
+	}
+	if s.cfg.Mode == ModeGreylist && s.greylisted(ss.client+"|"+strings.ToLower(ss.from)+"|"+strings.ToLower(rcpt)) {
+		return 451, "4.7.1 Greylisted, please try again later"
+	}
+	if !s.exists(rcpt) {
+		return 550, fmt.Sprintf("5.1.1 <%s>: Recipient address rejected: User unknown", rcpt)
+	}
+	return 250, "2.1.5 Ok"
+}
+
+// greylisted records the first attempt of triplet and reports whether it
+// is still refused.
+func (s *Server) greylisted(triplet string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	first, ok := s.triplets[triplet]
+	if !ok {
+		s.triplets[triplet] = time.Now()
+		return true
+	}
+	return time.Since(first) < s.cfg.GreylistDelay
+}
+
+func (s *Server) exists(rcpt string) bool {
+	rcpt = strings.ToLower(rcpt)
+	if s.users[rcpt] {
+		return true
+	}
+	local, _, _ := strings.Cut(rcpt, "@")
+	return s.users[local]
+}
+
+func (ss *session) reset() {
+	ss.from, ss.rcpts = "", 0
+}
+
+func (s *Server) reply(ss *session, code int, format string, args ...any) bool {
+	return s.replyLines(ss, code, []string{fmt.Sprintf(format, args...)})
+}
+
+// replyLines writes a (multi-line) reply after the tarpit delay. It
+// returns false if the session is over.
+func (s *Server) replyLines(ss *session, code int, lines []string) bool {
+	if s.cfg.Mode == ModeTarpit {
+		select {
+		case <-time.After(s.cfg.TarpitDelay):
+		case <-s.done:
+			return false
+		}
+	}
+	var b strings.Builder
+	for i, l := range lines {
+		sep := "-"
+		if i == len(lines)-1 {
+			sep = " "
+		}
+		fmt.Fprintf(&b, "%d%s%s\r\n", code, sep, l)
+	}
+	s.logf("%s > %s", ss.client, strings.TrimSpace(b.String()))
+	w := bufio.NewWriter(ss.conn)
+	if _, err := w.WriteString(b.String()); err != nil {
+		return false
+	}
+	return w.Flush() == nil
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.cfg.Logf != nil {
+		s.cfg.Logf(format, args...)
+	}
+}
+
+// path extracts the address of "FROM:<a@b> PARAMS" after prefix.
+func path(arg, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+	rest := strings.TrimSpace(arg[len(prefix):])
+	if !strings.HasPrefix(rest, "<") {
+		return "", false
+	}
+	end := strings.IndexByte(rest, '>')
+	if end < 0 {
+		return "", false
+	}
+	return rest[1:end], true
+}
+
+func validMode(m Mode) bool {
+	for _, v := range Modes {
+		if v == m {
+			return true
+		}
+	}
+	return false
+}
+
+func hostOf(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// selfSigned returns a TLS configuration with a fresh self-signed
+// certificate for host.
+func selfSigned(host string) (*tls.Config, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		tmpl.DNSNames, tmpl.IPAddresses = nil, []net.IP{ip}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package smtpd_test
+
+import (
+	"crypto/tls"
+	"net/smtp"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit/test/integration/smtpd"
+)
+
+func start(t *testing.T, cfg smtpd.Config) *smtpd.Server {
+	t.Helper()
+	s, err := smtpd.Start(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+// session dials s and sends EHLO and MAIL FROM.
+func session(t *testing.T, s *smtpd.Server) *textproto.Conn {
+	t.Helper()
+	c, err := textproto.Dial("tcp", s.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	_, _, err = c.ReadResponse(220)
+	require.NoError(t, err)
+	cmd(t, c, 250, "EHLO client.test")
+	cmd(t, c, 250, "MAIL FROM:<verify@client.test>")
+	return c
+}
+
+func cmd(t *testing.T, c *textproto.Conn, want int, line string) string {
+	t.Helper()
+	require.NoError(t, c.PrintfLine("%s", line))
+	code, msg, _ := c.ReadResponse(0)
+	assert.Equal(t, want, code, "%s: %s", line, msg)
+	return msg
+}
+
+func TestNew_UnknownMode(t *testing.T) {
+	_, err := smtpd.New(smtpd.Config{Mode: "bouncy"})
+	assert.ErrorContains(t, err, `unknown mode "bouncy"`)
+}
+
+func TestServer_Strict(t *testing.T) {
+	s := start(t, smtpd.Config{Users: []string{"alice", "Bob@Example.com"}})
+	c := session(t, s)
+
+	cmd(t, c, 250, "RCPT TO:<alice@anywhere.test>")
+	cmd(t, c, 250, "RCPT TO:<bob@example.com>")
+	assert.Contains(t, cmd(t, c, 550, "RCPT TO:<bob@other.test>"), "5.1.1")
+	cmd(t, c, 250, "RSET")
+	cmd(t, c, 503, "RCPT TO:<alice@anywhere.test>")
+	cmd(t, c, 221, "QUIT")
+}
+
+func TestServer_CatchAll(t *testing.T) {
+	s := start(t, smtpd.Config{Mode: smtpd.ModeCatchAll})
+	c := session(t, s)
+
+	cmd(t, c, 250, "RCPT TO:<anyone@anywhere.test>")
+	cmd(t, c, 354, "DATA")
+	cmd(t, c, 250, "Subject: test\r\n\r\nbody\r\n.")
+}
+
+func TestServer_Greylist(t *testing.T) {
+	s := start(t, smtpd.Config{Mode: smtpd.ModeGreylist, Users: []string{"alice"}, GreylistDelay: 50 * time.Millisecond})
+	c := session(t, s)
+
+	assert.Contains(t, cmd(t, c, 451, "RCPT TO:<alice@example.com>"), "Greylisted")
+	cmd(t, c, 451, "RCPT TO:<alice@example.com>")
+	time.Sleep(60 * time.Millisecond)
+	cmd(t, c, 250, "RCPT TO:<alice@example.com>")
+	// Another triplet starts over
+	assert.Contains(t, cmd(t, c, 451, "RCPT TO:<bob@example.com>"), "Greylisted")
+}
+
+func TestServer_Tarpit(t *testing.T) {
+	s := start(t, smtpd.Config{Mode: smtpd.ModeTarpit, TarpitDelay: 30 * time.Millisecond})
+
+	begin := time.Now()
+	c, err := textproto.Dial("tcp", s.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = c.Close() }()
+	_, _, err = c.ReadResponse(220)
+	require.NoError(t, err)
+	cmd(t, c, 250, "NOOP")
+	assert.GreaterOrEqual(t, time.Since(begin), 60*time.Millisecond)
+}
+
+func TestServer_StartTLS(t *testing.T) {
+	s := start(t, smtpd.Config{Mode: smtpd.ModeStartTLS, Users: []string{"alice"}})
+
+	c, err := smtp.Dial(s.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = c.Close() }()
+	require.NoError(t, c.Hello("client.test"))
+	ok, _ := c.Extension("STARTTLS")
+	assert.True(t, ok)
+
+	err = c.Mail("verify@client.test")
+	assert.ErrorContains(t, err, "Must issue a STARTTLS command first")
+
+	require.NoError(t, c.StartTLS(&tls.Config{InsecureSkipVerify: true})) // self-signed
+	require.NoError(t, c.Mail("verify@client.test"))
+	assert.NoError(t, c.Rcpt("alice@example.com"))
+	assert.ErrorContains(t, c.Rcpt("bob@example.com"), "550")
+	assert.NoError(t, c.Quit())
+}
+
+func TestServer_Close(t *testing.T) {
+	s, err := smtpd.Start(smtpd.Config{Mode: smtpd.ModeTarpit, TarpitDelay: time.Hour})
+	require.NoError(t, err)
+
+	c, err := textproto.Dial("tcp", s.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = c.Close() }()
+
+	// A session stuck in the tarpit doesn't hold up Close
+	done := make(chan struct{})
+	go func() { _ = s.Close(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close blocked")
+	}
+}
@@ -0,0 +1,175 @@
+package emailkit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TwoPhaseOptions configures a TwoPhase.
+type TwoPhaseOptions struct {
+	// OnComplete receives the outcome of every deep phase, from a worker
+	// goroutine: the Merge of the fast and deep results, or the fast
+	// result and the deep pipeline's error (a configuration error, or the
+	// context's error after Close gave up waiting). Update your store
+	// here. Default: nil (outcomes are dropped)
+	OnComplete func(email string, result Result, err error)
+	// Workers is the number of deep validations run at once. Default: 4
+	Workers int
+	// QueueSize is the number of deep validations that may wait for a
+	// worker; beyond it Validate returns ErrQueueFull. Default: 1000
+	QueueSize int
+	// Timeout bounds each deep validation. Default: 0 (none)
+	Timeout time.Duration
+	// DeepInvalid when true runs the deep phase for addresses the fast
+	// phase rejected as well. Default: false (they are final)
+	DeepInvalid bool
+}
+
+// TwoPhase accepts addresses after a fast pipeline — syntax and domain,
+// no network — and verifies them with a slow one — DNS and SMTP — in the
+// background, the "accept now, verify deeply later" pattern of signup
+// forms:
+//
+//	tp := emailkit.NewTwoPhase(
+//		emailkit.New().WithDomain(),
+//		emailkit.New().WithDNS().WithSMTP(smtpOpts),
+//		emailkit.TwoPhaseOptions{OnComplete: func(email string, r emailkit.Result, err error) {
+//			if err == nil {
+//				db.SaveVerification(email, r)
+//			}
+//		}},
+//	)
+//	defer tp.Close(context.Background())
+//
+// A TwoPhase is safe for concurrent use. Call Close when done; it does
+// not close the validators.
+type TwoPhase struct {
+	fast, deep *Validator
+	opts       TwoPhaseOptions
+	queue      chan twoPhaseJob
+	ctx        context.Context // cancelled when Close gives up waiting
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+type twoPhaseJob struct {
+	ctx   context.Context // the caller's values, without its cancellation
+	email string
+	fast  Result
+}
+
+// NewTwoPhase creates a TwoPhase running fast inline and deep in the
+// background, and starts its workers.
+func NewTwoPhase(fast, deep *Validator, opts TwoPhaseOptions) *TwoPhase {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &TwoPhase{
+		fast:   fast,
+		deep:   deep,
+		opts:   opts,
+		queue:  make(chan twoPhaseJob, opts.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	t.wg.Add(opts.Workers)
+	for range opts.Workers {
+		go t.work()
+	}
+	return t
+}
+
+// Validate runs the fast phase and returns its Result, then schedules
+// the deep phase for a valid address (any address with DeepInvalid).
+// The deep phase outlives ctx but keeps its values. If it can't be
+// scheduled, the fast Result is returned along with ErrQueueFull or
+// ErrClosed; it is still the fast phase's verdict.
+func (t *TwoPhase) Validate(ctx context.Context, email string) (Result, error) {
+	res, err := t.fast.Validate(ctx, email)
+	if err != nil || res.Truncated || (!res.Valid && !t.opts.DeepInvalid) {
+		return res, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return res, ErrClosed
+	}
+	select {
+	case t.queue <- twoPhaseJob{ctx: context.WithoutCancel(ctx), email: email, fast: res}:
+		return res, nil
+	default:
+		return res, ErrQueueFull
+	}
+}
+
+// Pending returns the number of deep validations waiting for a worker.
+func (t *TwoPhase) Pending() int {
+	return len(t.queue)
+}
+
+// Close stops accepting addresses and waits for the scheduled deep
+// validations to finish. If ctx is done first, the remaining ones are
+// cancelled — OnComplete still receives them — and ctx's error is
+// returned once they have stopped. Safe to call multiple times.
+func (t *TwoPhase) Close(ctx context.Context) error {
+	t.mu.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.queue)
+	}
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	defer t.cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		t.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (t *TwoPhase) work() {
+	defer t.wg.Done()
+	for job := range t.queue {
+		res, err := t.runDeep(job)
+		if t.opts.OnComplete != nil {
+			t.opts.OnComplete(job.email, res, err)
+		}
+	}
+}
+
+// runDeep validates a job with the deep pipeline and merges the result
+// into the fast one.
+func (t *TwoPhase) runDeep(job twoPhaseJob) (Result, error) {
+	ctx, cancel := context.WithCancel(job.ctx)
+	defer cancel()
+	stop := context.AfterFunc(t.ctx, cancel)
+	defer stop()
+	if t.opts.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, t.opts.Timeout)
+		defer cancelTimeout()
+	}
+
+	deep, err := t.deep.Validate(ctx, job.email)
+	if err != nil {
+		return job.fast, err
+	}
+	return Merge(job.fast, deep), nil
+}
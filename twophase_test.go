@@ -0,0 +1,100 @@
+package emailkit_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+)
+
+// gateChecker blocks until its gate is closed or its context is done.
+type gateChecker struct{ gate chan struct{} }
+
+func (c gateChecker) Check(ctx context.Context, _ emailkit.Email) emailkit.CheckResult {
+	select {
+	case <-c.gate:
+		return emailkit.CheckResult{Level: "gate", Passed: true}
+	case <-ctx.Done():
+		return emailkit.CheckResult{Level: "gate", Code: emailkit.CodeCancelled}
+	}
+}
+
+func TestTwoPhase(t *testing.T) {
+	deep := emailkit.New().WithResolver(&stubResolver{
+		mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+	}).WithDNS()
+
+	var mu sync.Mutex
+	got := map[string]emailkit.Result{}
+	tp := emailkit.NewTwoPhase(emailkit.New().WithDomain(), deep, emailkit.TwoPhaseOptions{
+		OnComplete: func(email string, r emailkit.Result, err error) {
+			assert.NoError(t, err)
+			mu.Lock()
+			got[email] = r
+			mu.Unlock()
+		},
+	})
+	ctx := context.Background()
+
+	res, err := tp.Validate(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+	assert.Len(t, res.Checks, 2)
+
+	res, err = tp.Validate(ctx, "bob@nomx.example")
+	require.NoError(t, err)
+	assert.True(t, res.Valid, "the fast phase accepts it")
+
+	res, err = tp.Validate(ctx, "carol@mailinator.com")
+	require.NoError(t, err)
+	assert.False(t, res.Valid, "rejected by the fast phase, not verified further")
+
+	require.NoError(t, tp.Close(ctx))
+	require.Len(t, got, 2)
+	assert.True(t, got["alice@example.com"].Valid)
+	assert.False(t, got["bob@nomx.example"].Valid)
+	c, ok := got["alice@example.com"].CheckFor(emailkit.LevelDomain)
+	require.True(t, ok, "the deep result is merged with the fast one")
+	assert.Equal(t, "0", c.Meta["merged.from"])
+
+	_, err = tp.Validate(ctx, "dave@example.com")
+	assert.ErrorIs(t, err, emailkit.ErrClosed)
+	assert.NoError(t, tp.Close(ctx))
+}
+
+func TestTwoPhase_QueueFull(t *testing.T) {
+	gate := make(chan struct{})
+	var mu sync.Mutex
+	var errs []error
+	tp := emailkit.NewTwoPhase(emailkit.New(), emailkit.New().With(gateChecker{gate}), emailkit.TwoPhaseOptions{
+		Workers:   1,
+		QueueSize: 1,
+		OnComplete: func(_ string, _ emailkit.Result, err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		},
+	})
+	ctx := context.Background()
+
+	_, err := tp.Validate(ctx, "a@example.com") // taken by the worker
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return tp.Pending() == 0 }, time.Second, time.Millisecond)
+	_, err = tp.Validate(ctx, "b@example.com") // queued
+	require.NoError(t, err)
+	res, err := tp.Validate(ctx, "c@example.com")
+	assert.ErrorIs(t, err, emailkit.ErrQueueFull)
+	assert.True(t, res.Valid, "the fast result is still returned")
+
+	// Close gives up waiting and cancels the deep phases
+	closeCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, tp.Close(closeCtx), context.DeadlineExceeded)
+	assert.Len(t, errs, 2)
+}
@@ -3,14 +3,175 @@
 // to avoid circular imports.
 package types
 
+import "time"
+
 // CheckLevel identifies the validation level.
 type CheckLevel = string
 
 const (
-	LevelSyntax CheckLevel = "syntax"
-	LevelDNS    CheckLevel = "dns"
-	LevelDomain CheckLevel = "domain"
-	LevelSMTP   CheckLevel = "smtp"
+	LevelSyntax       CheckLevel = "syntax"
+	LevelDNS          CheckLevel = "dns"
+	LevelDomain       CheckLevel = "domain"
+	LevelSMTP         CheckLevel = "smtp"
+	LevelNS           CheckLevel = "ns"
+	LevelRegistration CheckLevel = "registration"
+	LevelGeo          CheckLevel = "geo"
+	LevelCompliance   CheckLevel = "compliance"
+	LevelSender       CheckLevel = "sender"
+	LevelRole         CheckLevel = "role"
+	LevelFreeProvider CheckLevel = "free_provider"
+	LevelVerification CheckLevel = "verification"
+	LevelDNSBL        CheckLevel = "dnsbl"
+	LevelSPF          CheckLevel = "spf"
+
+	// LevelAllowlist reports that an allowlist entry matched and no level
+	// ran (see Validator.WithAllowlist).
+	LevelAllowlist CheckLevel = "allowlist"
+	// LevelBlocklist reports that a blocklist entry matched and no level
+	// ran (see Validator.WithBlocklist).
+	LevelBlocklist CheckLevel = "blocklist"
+
+	// LevelPipeline is not a validation level: it reports that the
+	// pipeline itself could not run for an email (see ValidateMany).
+	LevelPipeline CheckLevel = "pipeline"
+)
+
+// CheckCode is a machine-readable reason attached to a CheckResult,
+// for outcomes that need to be told apart beyond Passed and Details.
+type CheckCode = string
+
+const (
+	// CodeCancelled marks a check that failed because the context was
+	// cancelled or timed out before it could finish.
+	CodeCancelled CheckCode = "cancelled"
+
+	// CodeError marks a LevelPipeline result for an email that could not
+	// be validated for a reason other than cancellation.
+	CodeError CheckCode = "error"
+
+	// SMTP response categories, shared by the SMTP level and the bounce
+	// knowledge base (emailkit.ExplainSMTP).
+	CodeAccepted           CheckCode = "accepted"
+	CodeMailboxUnknown     CheckCode = "mailbox_unknown"
+	CodeMailboxFull        CheckCode = "mailbox_full"
+	CodeMailboxDisabled    CheckCode = "mailbox_disabled"
+	CodeBadDomain          CheckCode = "bad_domain"
+	CodeGreylisted         CheckCode = "greylisted"
+	CodeRateLimited        CheckCode = "rate_limited"
+	CodeBlocked            CheckCode = "blocked"
+	CodePolicy             CheckCode = "policy"
+	CodeServiceUnavailable CheckCode = "service_unavailable"
+	CodeTemporary          CheckCode = "temporary"
+	CodeUnknown            CheckCode = "unknown"
+
+	// CodeCatchAll marks an SMTP level that passed on a domain which
+	// accepts every recipient, so acceptance says little about the mailbox.
+	CodeCatchAll CheckCode = "catch_all"
+
+	// CodeDeferred marks an SMTP level that did not probe because of the
+	// probe policy: outside the probing window, over the daily budget, or
+	// no healthy sender identity.
+	CodeDeferred CheckCode = "deferred"
+
+	// CodeSkipped marks an SMTP level that passed without probing the
+	// mailbox (no RCPT TO), e.g. because a domain policy said so. It says
+	// nothing about the mailbox.
+	CodeSkipped CheckCode = "skipped"
+
+	// CodeDNSTimeout and CodeDNSError mark a failed DNS or SMTP level
+	// whose MX lookup timed out, or got SERVFAIL or another resolver
+	// failure. They say nothing about the domain; an NXDOMAIN answer is
+	// CodeBadDomain instead.
+	CodeDNSTimeout CheckCode = "dns_timeout"
+	CodeDNSError   CheckCode = "dns_error"
+
+	// CodeTimedOut marks a level abandoned by the pipeline watchdog
+	// (Validator.WithWatchdog) because it ran too long.
+	CodeTimedOut CheckCode = "timed_out"
+
+	// CodeAllowlisted marks the LevelAllowlist check of an address or
+	// domain on the allowlist: it is valid without any level having run.
+	CodeAllowlisted CheckCode = "allowlisted"
+
+	// CodeBlocklisted marks the failed LevelBlocklist check of an address
+	// on the blocklist.
+	CodeBlocklisted CheckCode = "blocklisted"
+
+	// CodeParked marks an NS level that passed on a domain which looks
+	// parked (for sale or monetized): it resolves, but does not receive mail.
+	CodeParked CheckCode = "parked"
+
+	// CodeGeoBlocked marks a failed geo level: a mail host of the domain
+	// is located in one of GeoOptions.BlockCountries.
+	CodeGeoBlocked CheckCode = "geo_blocked"
+
+	// CodeSanctioned marks a failed compliance level: the domain is in a
+	// jurisdiction under sanctions, e.g. the .ir TLD.
+	CodeSanctioned CheckCode = "sanctioned"
+
+	// CodeRestricted marks a compliance level on a government or military
+	// domain, e.g. under .mil: it passes, unless
+	// ComplianceOptions.FailRestricted is set.
+	CodeRestricted CheckCode = "restricted"
+
+	// Sender level codes. CodeSenderRole: the local part is reserved for
+	// mail infrastructure (postmaster@, abuse@). CodeSenderNoBounce: the
+	// domain does not receive mail, so bounces are lost.
+	// CodeSenderSPFFail: the SPF record rejects the sending IPs, or all
+	// mail. CodeSenderNoDMARC: the domain has no DMARC record; the level
+	// passes unless SenderOptions.RequireDMARC is set.
+	CodeSenderRole     CheckCode = "sender_role"
+	CodeSenderNoBounce CheckCode = "sender_no_bounce"
+	CodeSenderSPFFail  CheckCode = "sender_spf_fail"
+	CodeSenderNoDMARC  CheckCode = "sender_no_dmarc"
+
+	// CodeRole marks a role level on a role account, e.g. info@ or
+	// sales@: it passes, unless RoleOptions.Fail is set.
+	CodeRole CheckCode = "role_account"
+
+	// CodeFreeProvider marks a free_provider level on an address at a
+	// free consumer mailbox provider, e.g. gmail.com: it passes, unless
+	// FreeProviderOptions.Fail is set.
+	CodeFreeProvider CheckCode = "free_provider"
+
+	// CodeTokenMissing marks a verification level on a domain that does
+	// not publish the expected TXT token.
+	CodeTokenMissing CheckCode = "token_missing"
+
+	// CodeDNSBLListed marks a dnsbl level that passed on a domain with a
+	// mail host address on one of the configured DNS blocklists.
+	CodeDNSBLListed CheckCode = "dnsbl_listed"
+
+	// CodeSPFMissing marks an spf level on a domain that publishes no SPF
+	// record: it passes, unless SPFOptions.Require is set.
+	CodeSPFMissing CheckCode = "spf_missing"
+)
+
+// Severity grades an outcome for filtering and display.
+type Severity = string
+
+const (
+	SeverityError Severity = "error"
+	SeverityWarn  Severity = "warn"
+	SeverityInfo  Severity = "info"
+)
+
+// Category groups outcomes by what they are about, across levels.
+type Category = string
+
+const (
+	// CategorySyntax: the address itself, e.g. its syntax, a provider's
+	// local-part rules or a typo in the domain.
+	CategorySyntax Category = "syntax"
+	// CategoryInfrastructure: the mail setup of the domain, e.g. its MX
+	// and NS records, its registration or its mail servers' replies.
+	CategoryInfrastructure Category = "infrastructure"
+	// CategoryReputation: what the domain is known for, e.g. a disposable
+	// or parked domain.
+	CategoryReputation Category = "reputation"
+	// CategoryPolicy: rules of the caller or the law, e.g. allow- and
+	// blocklists, geo and compliance restrictions, sender requirements.
+	CategoryPolicy Category = "policy"
 )
 
 // CheckResult is the outcome of a single validation level.
@@ -21,4 +182,26 @@ type CheckResult struct {
 	MXHost     string     `json:"mxHost,omitempty"`
 	SMTPCode   int        `json:"smtpCode,omitempty"`
 	Suggestion string     `json:"suggestion,omitempty"`
+	Code       CheckCode  `json:"code,omitempty"`
+	// Meta carries level-specific structured details, e.g. the
+	// registration date reported by the registration level.
+	Meta map[string]string `json:"meta,omitempty"`
+	// Attempts lists the MX hosts the SMTP level tried, in preference
+	// order, with what each of them answered.
+	Attempts []ProbeAttempt `json:"attempts,omitempty"`
+	// Severity and Category classify the outcome for filtering without
+	// matching on level names. The validator fills them in; a level may
+	// set Category itself, as the domain level does for a disposable
+	// domain (CategoryReputation).
+	Severity Severity `json:"severity,omitempty"`
+	Category Category `json:"category,omitempty"`
+}
+
+// ProbeAttempt is one MX host the SMTP level tried.
+type ProbeAttempt struct {
+	Host    string        `json:"host"`
+	Code    int           `json:"code,omitempty"`    // SMTP reply code; 0 if the host gave none
+	Message string        `json:"message,omitempty"` // full SMTP reply, lines joined by " | "
+	Error   string        `json:"error,omitempty"`   // connection or protocol failure
+	Latency time.Duration `json:"latency"`           // from the start of the attempt to its outcome
 }
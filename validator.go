@@ -2,33 +2,74 @@ package emailkit
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"net"
+	"net/netip"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/optimode/emailkit/check"
+	"github.com/optimode/emailkit/internal/analytics"
 	"github.com/optimode/emailkit/internal/dnscache"
 	"github.com/optimode/emailkit/internal/parse"
+	"github.com/optimode/emailkit/internal/ratelimit"
+	"github.com/optimode/emailkit/internal/schedule"
 	"github.com/optimode/emailkit/internal/smtppool"
+	"github.com/optimode/emailkit/internal/warmup"
 	"github.com/optimode/emailkit/types"
 )
 
-// checker is the internal interface for all validation levels.
-// Every check/ package type implements this.
-type checker interface {
-	Check(ctx context.Context, email parse.Email) types.CheckResult
+// Checker is the interface for all validation levels.
+// Every check/ package type implements it; third-party levels implement it
+// to be added with With() or registered by name with RegisterLevel().
+type Checker interface {
+	Check(ctx context.Context, email Email) CheckResult
+}
+
+// DomainOnlyChecker is implemented by levels that can validate a bare
+// domain. ValidateDomain runs only the levels that implement it; the email
+// they receive has an empty Local part. All built-in levels implement it
+// (the SMTP level checks connectivity instead of probing a recipient).
+type DomainOnlyChecker interface {
+	CheckDomain(ctx context.Context, domain Email) CheckResult
 }
 
 // Validator is the main fluent builder struct.
 // Instantiate with the New() function.
 // When using SMTP validation, call Close() when done to release pooled connections.
 type Validator struct {
-	checkers []checker
+	checkers []Checker
 	err      error // configuration error, returned on Validate()
+	resolver Resolver
 	dnsCache *dnscache.Cache
 	smtpPool *smtppool.Pool
+	hasher   func(string) string // privacy mode, see WithPrivacy
+	// calibration enables Result.DeliverabilityProbability, see WithCalibration
+	calibration Calibration
+	scoring     *ScoringOptions        // enables Result.RiskScore, see WithScoring
+	greylist    GreylistStore          // from SMTPOptions, for ExportState
+	schedule    *schedule.Schedule     // from SMTPOptions, for ExportState
+	warmUp      *warmup.Ramp           // from SMTPOptions, for ExportState
+	identities  *check.IdentityMonitor // SMTP identities, for CheckIdentities
+	mxStore     MXStore                // shared MX cache tier, see WithMXStore
+	subscribers []Subscriber           // see WithSubscriber
+	pools       *poolSet               // shared SMTP pools of a Manager
+	probeQuota  check.ProbeQuota       // per-tenant probe quota of a Manager
+	smtp        *check.SMTPChecker     // for SMTPPortBlocked
+	watchdog    time.Duration          // per-level bound, see WithWatchdog
+	output      OutputOptions          // see WithOutput
+	allowlist   *accessList            // see WithAllowlist
+	blocklist   *accessList            // see WithBlocklist
+	offline     *FakeNetwork           // see NewOffline
+	analytics   *analytics.Recorder    // SMTP probe outcomes, see Analytics
+	candidates  *candidateTracker      // see WithDisposableCandidates
+	autoOrder   bool                   // see WithAutoOrder
 }
 
 // New creates a new Validator. By default it only performs syntax checking.
@@ -36,12 +77,22 @@ type Validator struct {
 // address is a prerequisite for the other levels.
 func New() *Validator {
 	return &Validator{
-		checkers: []checker{
+		checkers: []Checker{
 			check.NewSyntaxChecker(),
 		},
+		resolver: net.DefaultResolver,
 	}
 }
 
+// WithResolver replaces the DNS resolver used by all network levels
+// (MX, NS and other lookups). Default: net.DefaultResolver.
+// It may be called at any point of the builder chain; levels added
+// earlier use the new resolver as well.
+func (v *Validator) WithResolver(r Resolver) *Validator {
+	v.resolver = r
+	return v
+}
+
 // WithDNS adds MX lookup validation to the pipeline.
 // Optionally overrides the default DNSOptions.
 // MX lookup results are cached and shared with the SMTP checker.
@@ -50,27 +101,272 @@ func (v *Validator) WithDNS(opts ...DNSOptions) *Validator {
 	if len(opts) > 0 {
 		o = opts[0]
 	}
+	if err := o.validate(); err != nil {
+		v.setErr(err)
+		return v
+	}
 	v.ensureDNSCache(o.Timeout)
-	v.checkers = append(v.checkers, check.NewDNSCheckerWithLookup(
+	v.dnsCache.SetRetry(dnscache.RetryPolicy(o.Retry))
+	v.add(check.NewDNSCheckerWithCache(
 		check.DNSConfig{
-			Timeout:     o.Timeout,
-			FallbackToA: o.FallbackToA,
+			Timeout:        o.Timeout,
+			FallbackToA:    o.FallbackToA,
+			DetectWildcard: o.DetectWildcard,
+			LookupHost:     resolverRef{v}.LookupHost,
+			Diagnostics:    o.Diagnostics,
+			ResolverName:   v.resolverName,
+			Retries:        o.Retries,
 		},
-		v.dnsCache.LookupMX,
+		v.dnsCache,
 	))
 	return v
 }
 
-// WithDomain adds domain-level validation (disposable + typo).
+// With adds a custom validation level to the pipeline.
+// It runs in registration order like the built-in levels, or by the
+// Capabilities it declares with WithAutoOrder.
+func (v *Validator) With(c Checker) *Validator {
+	v.add(c)
+	return v
+}
+
+// WithNS adds nameserver validation to the pipeline: the registrable domain
+// must have NS records and, by default, at least one nameserver must respond.
+// Failure details distinguish an unregistered domain from one with broken
+// nameservers; combined with WithDNS a registered but MX-less domain passes
+// this level and fails the DNS level.
+func (v *Validator) WithNS(opts ...NSOptions) *Validator {
+	o := defaultNSOptions()
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Timeout < 0 {
+		v.setErr(negative("NSOptions.Timeout", o.Timeout))
+		return v
+	}
+	if o.Timeout == 0 {
+		o.Timeout = defaultNSOptions().Timeout
+	}
+	cfg := check.NSConfig{
+		Timeout:          o.Timeout,
+		ProbeNameservers: o.ProbeNameservers,
+		DetectParking:    o.DetectParking,
+	}
+	if v.offline != nil {
+		cfg.Probe = v.offline.probeNameserver
+	}
+	v.add(check.NewNSChecker(cfg, resolverRef{v}))
+	return v
+}
+
+// WithRegistration adds an RDAP lookup confirming that the registrable domain
+// exists at its registry, catching typo'd unregistered domains that resolvers
+// with aggressive NXDOMAIN caching sometimes mis-signal.
+// If the registry can't be reached the level passes with an "unknown" detail.
+// The registration date, when reported, is available in Meta["registered"].
+func (v *Validator) WithRegistration(opts ...RegistrationOptions) *Validator {
+	o := defaultRegistrationOptions()
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Timeout < 0 {
+		v.setErr(negative("RegistrationOptions.Timeout", o.Timeout))
+		return v
+	}
+	if o.Timeout == 0 {
+		o.Timeout = defaultRegistrationOptions().Timeout
+	}
+	if o.HTTPClient == nil && v.offline != nil {
+		o.HTTPClient, o.BootstrapURL = v.offline.httpClient(), fakeRDAPBase+"dns.json"
+	}
+	v.add(check.NewRegistrationChecker(check.RegistrationConfig{
+		Timeout:      o.Timeout,
+		BootstrapURL: o.BootstrapURL,
+		HTTPClient:   o.HTTPClient,
+	}))
+	return v
+}
+
+// WithGeo adds geo enrichment: the level resolves the domain's mail hosts
+// (its MX hosts, or the domain itself without MX records) and locates
+// their addresses, reporting Meta["countries"] (ISO 3166-1 alpha-2,
+// comma-separated), Meta["asns"] and Meta["hosts"] for compliance review.
+// With GeoOptions.BlockCountries it fails domains hosted in one of those
+// countries with CodeGeoBlocked. Lookup trouble never fails the level.
+func (v *Validator) WithGeo(opts ...GeoOptions) *Validator {
+	o := defaultGeoOptions()
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if err := o.validate(); err != nil {
+		v.setErr(err)
+		return v
+	}
+	if o.Timeout == 0 {
+		o.Timeout = defaultGeoOptions().Timeout
+	}
+	v.add(check.NewGeoChecker(check.GeoConfig{
+		Timeout:        o.Timeout,
+		Provider:       o.Provider,
+		MaxHosts:       o.MaxHosts,
+		BlockCountries: o.BlockCountries,
+	}, resolverRef{v}))
+	return v
+}
+
+// WithDNSBL adds the dnsbl level, which checks the addresses of the
+// domain's mail hosts (its MX hosts, or the domain itself without MX
+// records) against the given DNS blocklist zones, e.g.
+// "zen.spamhaus.org", to spot spammy or abused infrastructure. A listed
+// address passes with CodeDNSBLListed and Meta["listed"]; lookup trouble
+// never fails the level. Some blocklists refuse queries that come through
+// public resolvers.
+func (v *Validator) WithDNSBL(zones ...string) *Validator {
+	if len(zones) == 0 {
+		v.setErr(&OptionsError{Field: "DNSBL zones", Constraint: "at least one is required"})
+		return v
+	}
+	v.add(check.NewDNSBLChecker(check.DNSBLConfig{
+		Zones:   zones,
+		Timeout: 5 * time.Second,
+	}, resolverRef{v}))
+	return v
+}
+
+// WithCompliancePolicy adds the compliance level, which matches the
+// domain against a policy of sanctioned jurisdictions and
+// government-restricted domains, without network lookups. A sanctioned
+// domain fails with CodeSanctioned; a restricted one passes with
+// CodeRestricted for review, or fails with FailRestricted.
+func (v *Validator) WithCompliancePolicy(opts ...ComplianceOptions) *Validator {
+	var o ComplianceOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	v.add(check.NewComplianceChecker(check.ComplianceConfig{
+		Sanctioned:     o.Sanctioned,
+		Restricted:     o.Restricted,
+		Exempt:         o.Exempt,
+		FailRestricted: o.FailRestricted,
+	}))
+	return v
+}
+
+// WithRole adds the role level, which flags role accounts such as
+// admin@, info@ or sales@: mailboxes read by a team rather than a person,
+// which marketers exclude before sending campaigns. A role account passes
+// with CodeRole (a warning), or fails with RoleOptions.Fail.
+func (v *Validator) WithRole(opts ...RoleOptions) *Validator {
+	var o RoleOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	v.add(check.NewRoleChecker(check.RoleConfig{Roles: o.Roles, Fail: o.Fail}))
+	return v
+}
+
+// WithFreeProvider adds the free_provider level, which flags addresses at
+// free consumer mailbox providers (gmail.com, outlook.com, ...) from an
+// embedded list, for B2B signup forms that reject or segment them. A match
+// passes with CodeFreeProvider and sets Result.FreeProvider, or fails with
+// FreeProviderOptions.Fail.
+func (v *Validator) WithFreeProvider(opts ...FreeProviderOptions) *Validator {
+	var o FreeProviderOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	v.add(check.NewFreeProviderChecker(check.FreeProviderConfig{Domains: o.Domains, Fail: o.Fail}))
+	return v
+}
+
+// WithVerification adds the verification level, which checks that the
+// domain publishes a TXT token you issued, e.g. to prove domain ownership
+// during B2B onboarding. A missing token fails with CodeTokenMissing.
+// TXT answers are cached alongside the MX lookups of the DNS level.
+func (v *Validator) WithVerification(opts VerificationOptions) *Validator {
+	if opts.Token == "" && opts.TokenFor == nil {
+		v.setErr(&OptionsError{Field: "VerificationOptions.Token", Constraint: "required without TokenFor"})
+		return v
+	}
+	v.ensureDNSCache(defaultDNSOptions().Timeout)
+	v.add(check.NewVerificationChecker(check.VerificationConfig{
+		Token:       opts.Token,
+		TokenFor:    opts.TokenFor,
+		Name:        opts.Name,
+		Registrable: opts.Registrable,
+	}, v.dnsCache.LookupTXT))
+	return v
+}
+
+// WithSPF adds the spf level, which reports whether the domain publishes
+// an SPF record and how strict its policy is: Meta["spf.all"] is "-all",
+// "~all", "?all", "+all" or "" without an all mechanism. Domains without
+// any mail authentication are a quality signal: they pass with
+// CodeSPFMissing, or fail with SPFOptions.Require. TXT answers are cached
+// alongside the MX lookups of the DNS level.
+func (v *Validator) WithSPF(opts ...SPFOptions) *Validator {
+	var o SPFOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	v.ensureDNSCache(defaultDNSOptions().Timeout)
+	v.add(check.NewSPFChecker(check.SPFConfig{Require: o.Require}, v.dnsCache.LookupTXT))
+	return v
+}
+
+// WithSender adds the sender level, which validates addresses that will
+// send mail (MAIL FROM) rather than receive it, e.g. in the onboarding
+// flow of a sending platform: the local part must not be reserved for
+// mail infrastructure, the domain must receive bounces, and its SPF and
+// DMARC records are checked. A sender pipeline typically has no SMTP
+// level, as a RCPT TO probe says nothing about sending. An invalid
+// SendingIPs entry is a configuration error.
+func (v *Validator) WithSender(opts ...SenderOptions) *Validator {
+	o := defaultSenderOptions()
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Timeout < 0 {
+		v.setErr(negative("SenderOptions.Timeout", o.Timeout))
+		return v
+	}
+	if o.Timeout == 0 {
+		o.Timeout = defaultSenderOptions().Timeout
+	}
+	var ips []net.IP
+	for _, s := range o.SendingIPs {
+		ip := net.ParseIP(strings.TrimSpace(s))
+		if ip == nil {
+			v.setErr(&OptionsError{Field: "SenderOptions.SendingIPs", Value: s, Constraint: "not an IP address"})
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	v.add(check.NewSenderChecker(check.SenderConfig{
+		Timeout:         o.Timeout,
+		SendingIPs:      ips,
+		RequireDMARC:    o.RequireDMARC,
+		RestrictedRoles: o.RestrictedRoles,
+	}, resolverRef{v}))
+	return v
+}
+
+// WithDomain adds domain-level validation (disposable, provider rules, typo).
 func (v *Validator) WithDomain(opts ...DomainOptions) *Validator {
 	o := defaultDomainOptions()
 	if len(opts) > 0 {
 		o = opts[0]
 	}
-	v.checkers = append(v.checkers, check.NewDomainChecker(check.DomainConfig{
-		CheckDisposable: o.CheckDisposable,
-		CheckTypos:      o.CheckTypos,
-		TypoThreshold:   o.TypoThreshold,
+	if err := o.validate(); err != nil {
+		v.setErr(err)
+		return v
+	}
+	v.add(check.NewDomainChecker(check.DomainConfig{
+		CheckDisposable:    o.CheckDisposable,
+		CheckTypos:         o.CheckTypos,
+		TypoThreshold:      o.TypoThreshold,
+		CheckProviderRules: o.CheckProviderRules,
+		TypoCorpus:         o.TypoCorpus,
 	}))
 	return v
 }
@@ -80,10 +376,34 @@ func (v *Validator) WithDomain(opts ...DomainOptions) *Validator {
 // Uses a connection pool for efficient bulk validation (connections reused via RSET).
 // Call Close() when done to release pooled connections.
 func (v *Validator) WithSMTP(opts SMTPOptions) *Validator {
-	if opts.HeloDomain == "" || opts.MailFrom == "" {
-		v.err = ErrInvalidSMTPOptions
+	if err := opts.validate(false); err != nil {
+		v.setErr(err)
+		return v
+	}
+	return v.withSMTP(opts, false)
+}
+
+// WithSMTPConnect adds a lighter SMTP level that only verifies that one of
+// the domain's MX hosts accepts a TCP connection and EHLO: no mail
+// transaction is started and no mailbox is probed. It suits users who
+// can't probe mailboxes, for ethical or legal reasons, but want more than
+// DNS. A pass is LevelSMTP with Meta "probe" ("connect") and never proves
+// the mailbox exists (see SignalSMTPConnect).
+//
+// SMTPOptions.HeloDomain is required; MailFrom, Identities,
+// VerifyIdentities, Greylist and the catch-all fingerprints don't apply.
+// Use WithSMTP or WithSMTPConnect, not both.
+func (v *Validator) WithSMTPConnect(opts SMTPOptions) *Validator {
+	if err := opts.validate(true); err != nil {
+		v.setErr(err)
 		return v
 	}
+	opts.MailFrom, opts.Identities, opts.VerifyIdentities = "", nil, false
+	opts.Greylist, opts.CatchAllFingerprints, opts.DisableCatchAllFingerprints = nil, nil, true
+	return v.withSMTP(opts, true)
+}
+
+func (v *Validator) withSMTP(opts SMTPOptions, connectOnly bool) *Validator {
 	// Apply defaults for unset values
 	def := defaultSMTPOptions()
 	if opts.ConnectTimeout == 0 {
@@ -95,78 +415,210 @@ func (v *Validator) WithSMTP(opts SMTPOptions) *Validator {
 	if opts.MaxMXHosts == 0 {
 		opts.MaxMXHosts = def.MaxMXHosts
 	}
-	if opts.Port == "" {
+	if opts.Port == "" && opts.TLSMode == TLSModeImplicit {
+		opts.Port = "465"
+	} else if opts.Port == "" {
 		opts.Port = def.Port
 	}
 	if opts.MaxConnsPerHost == 0 {
 		opts.MaxConnsPerHost = def.MaxConnsPerHost
 	}
 
+	var sched *schedule.Schedule
+	if opts.Schedule != nil {
+		var err error
+		if sched, err = opts.Schedule.build(); err != nil {
+			v.setErr(err)
+			return v
+		}
+	}
+
+	var warmUp *warmup.Ramp
+	if opts.WarmUp != nil {
+		warmUp = opts.WarmUp.build()
+	}
+
+	v.analytics = analytics.New(analytics.Config{Window: opts.AnalyticsWindow})
+	v.greylist = opts.Greylist
+	v.schedule, v.warmUp = sched, warmUp
+	var sourceIP netip.Addr
+	probeIP := opts.ProbeIP
+	if opts.SourceIP != nil {
+		sourceIP, _ = netip.AddrFromSlice(opts.SourceIP)
+		if probeIP == nil {
+			probeIP = opts.SourceIP
+		}
+	}
+	v.identities = check.NewIdentityMonitor(check.IdentityMonitorConfig{
+		Identities: append([]SMTPIdentity{{HeloDomain: opts.HeloDomain, MailFrom: opts.MailFrom}}, opts.Identities...),
+		ProbeIP:    probeIP,
+		Resolver:   resolverRef{v},
+	})
+	var monitor *check.IdentityMonitor
+	if opts.VerifyIdentities {
+		monitor = v.identities
+	}
+
 	// Ensure DNS cache exists (SMTP checker shares it for MX lookups)
 	v.ensureDNSCache(5 * opts.ConnectTimeout)
 
-	// Create SMTP connection pool
-	v.smtpPool = smtppool.New(smtppool.Config{
+	// Create SMTP connection pool, or share the Manager's
+	poolCfg := smtppool.Config{
 		HeloDomain:      opts.HeloDomain,
 		MailFrom:        opts.MailFrom,
 		ConnectTimeout:  opts.ConnectTimeout,
 		CommandTimeout:  opts.CommandTimeout,
 		Port:            opts.Port,
 		MaxConnsPerHost: opts.MaxConnsPerHost,
-	})
+		Adaptive:        opts.AdaptiveConns,
+		TLSMode:         opts.TLSMode,
+		TLSConfig:       opts.TLSConfig,
+		Dialer: smtppool.DialerOptions{
+			Dialer:         opts.Dialer,
+			KeepAlive:      opts.KeepAlive,
+			DisableNoDelay: opts.DisableNoDelay,
+			DSCP:           opts.DSCP,
+			SourceIP:       sourceIP,
+			Proxy:          opts.Proxy,
+			ProxyDialer:    opts.ProxyDialer,
+		},
+		DialContext: opts.DialContext,
+	}
+	if v.offline != nil {
+		poolCfg.Dial = v.offline.dial
+	}
+	if v.pools != nil {
+		v.smtpPool = v.pools.get(poolCfg)
+	} else {
+		v.smtpPool = smtppool.New(poolCfg)
+	}
 
-	v.checkers = append(v.checkers, check.NewSMTPChecker(
+	v.smtp = check.NewSMTPChecker(
 		check.SMTPConfig{
-			HeloDomain: opts.HeloDomain,
-			MailFrom:   opts.MailFrom,
-			MaxMXHosts: opts.MaxMXHosts,
+			HeloDomain:           opts.HeloDomain,
+			MailFrom:             opts.MailFrom,
+			MaxMXHosts:           opts.MaxMXHosts,
+			Sanitize:             opts.Sanitize,
+			Greylist:             opts.Greylist,
+			GreylistWindow:       opts.GreylistWindow,
+			HashLocal:            v.label, // reads the hasher per call: WithPrivacy may come later
+			Schedule:             sched,
+			WarmUp:               warmUp,
+			Analytics:            v.analytics,
+			Identities:           opts.Identities,
+			IdentityMonitor:      monitor,
+			IdentityPerWorker:    opts.IdentityPerWorker,
+			Policy:               opts.smtpPolicy(),
+			Limiter:              opts.Limiter,
+			Quota:                v.probeQuota,
+			DetectBlockedPort:    !opts.DisableBlockedPortDetection,
+			Hooks:                v.smtpHooks(),
+			CatchAllFingerprints: opts.catchAllFingerprints(),
+			ConnectOnly:          connectOnly,
 		},
 		v.dnsCache,
 		v.smtpPool,
-	))
+	)
+	v.add(v.smtp)
 	return v
 }
 
+// SMTPPortBlocked reports whether the SMTP level detected that outbound
+// SMTP connections are blocked (see SMTPOptions.DisableBlockedPortDetection),
+// e.g. to flag a job summary. While blocked, SMTP checks are skipped.
+func (v *Validator) SMTPPortBlocked() bool {
+	return v.smtp != nil && v.smtp.PortBlocked()
+}
+
+// PauseSMTP pauses the SMTP level, or resumes it with false, e.g. from an
+// admin endpoint while the probing IP is being delisted. While paused the
+// level passes with CodeSkipped and sends no SMTP traffic. No-op without
+// an SMTP level.
+func (v *Validator) PauseSMTP(paused bool) {
+	if v.smtp != nil {
+		v.smtp.SetPaused(paused)
+	}
+}
+
+// SMTPPaused reports whether the SMTP level is paused (see PauseSMTP).
+func (v *Validator) SMTPPaused() bool {
+	return v.smtp != nil && v.smtp.Paused()
+}
+
 // Close releases resources held by the Validator.
 // Must be called when using SMTP validation to close pooled connections.
 // Safe to call multiple times. No-op if no pooled resources exist.
 func (v *Validator) Close() error {
-	if v.smtpPool != nil {
+	if v.smtpPool != nil && v.pools == nil {
 		return v.smtpPool.Close()
 	}
 	return nil
 }
 
+// setErr records the first configuration error; it is returned on Validate().
+func (v *Validator) setErr(err error) {
+	if v.err == nil {
+		v.err = err
+	}
+}
+
 // ensureDNSCache creates a shared DNS cache if one doesn't exist yet.
 func (v *Validator) ensureDNSCache(lookupTimeout time.Duration) {
 	if v.dnsCache == nil {
-		v.dnsCache = dnscache.New(lookupTimeout, 5*time.Minute)
+		v.dnsCache = dnscache.NewWithResolver(lookupTimeout, 5*time.Minute, resolverRef{v})
+		v.dnsCache.SetStore(mxStoreRef{v})
+		v.dnsCache.SetHitHook(v.onCacheHit)
+		v.dnsCache.SetRetry(dnscache.RetryPolicy(defaultDNSOptions().Retry))
 	}
 }
 
+// resolverName describes the current resolver for DNS diagnostics: its
+// String method if it has one, "system" for net.DefaultResolver, or its type.
+func (v *Validator) resolverName() string {
+	switch r := v.resolver.(type) {
+	case fmt.Stringer:
+		return r.String()
+	case *net.Resolver:
+		if r == net.DefaultResolver {
+			return "system"
+		}
+	}
+	return fmt.Sprintf("%T", v.resolver)
+}
+
+// resolverRef resolves through the Validator's current resolver, so that
+// WithResolver also affects levels added before it was called.
+type resolverRef struct{ v *Validator }
+
+func (r resolverRef) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	return r.v.resolver.LookupMX(ctx, name)
+}
+
+func (r resolverRef) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return r.v.resolver.LookupHost(ctx, host)
+}
+
+func (r resolverRef) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	return r.v.resolver.LookupNS(ctx, name)
+}
+
+func (r resolverRef) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return r.v.resolver.LookupTXT(ctx, name)
+}
+
+func (r resolverRef) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return r.v.resolver.LookupAddr(ctx, addr)
+}
+
 // Validate runs all configured checks on the given email.
 // The pipeline short-circuits: if a level fails, subsequent levels are skipped.
-// Context can be used for timeout or cancellation.
+// Context can be used for timeout or cancellation; a cancelled run returns
+// a Result with Truncated set.
 func (v *Validator) Validate(ctx context.Context, email string) (Result, error) {
 	if v.err != nil {
 		return Result{}, v.err
 	}
-
-	parsed := parse.NewEmail(email)
-	result := Result{Email: email}
-
-	for _, c := range v.checkers {
-		cr := c.Check(ctx, parsed)
-		result.Checks = append(result.Checks, cr)
-
-		if !cr.Passed {
-			result.Valid = false
-			return result, nil // short-circuit
-		}
-	}
-
-	result.Valid = true
-	return result, nil
+	return v.run(ctx, email, true), nil
 }
 
 // ValidateAll runs all checks without short-circuiting.
@@ -175,43 +627,326 @@ func (v *Validator) ValidateAll(ctx context.Context, email string) (Result, erro
 	if v.err != nil {
 		return Result{}, v.err
 	}
+	return v.run(ctx, email, false), nil
+}
+
+// ValidateDomain validates a bare domain rather than a full address, for
+// vetting sender domains or bulk-checking domain lists. It runs the levels
+// that implement DomainOnlyChecker (all built-in levels; the SMTP level only
+// checks that an MX host accepts a session, without RCPT TO) and skips the
+// rest. The pipeline short-circuits like Validate; Result.Email holds the domain.
+func (v *Validator) ValidateDomain(ctx context.Context, domain string) (Result, error) {
+	if v.err != nil {
+		return Result{}, v.err
+	}
+	parsed := parse.NewDomain(domain)
+	return v.anonymize(v.shape(v.runChecks(ctx, parsed.Raw, parsed, true, func(ctx context.Context, c Checker) (CheckResult, bool) {
+		dc, ok := c.(DomainOnlyChecker)
+		if !ok {
+			return CheckResult{}, false
+		}
+		return dc.CheckDomain(ctx, parsed), true
+	}))), nil
+}
+
+// ValidateSeq validates a stream of emails one at a time, yielding each
+// Result as soon as it is available. It plugs into range-over-func loops
+// and the slices/maps iterator helpers without channels.
+//
+// The error is non-nil for configuration errors (yielded once) and when
+// ctx is done (yielded once with the next email, marked Truncated);
+// iteration stops after either.
+func (v *Validator) ValidateSeq(ctx context.Context, emails iter.Seq[string]) iter.Seq2[Result, error] {
+	return func(yield func(Result, error) bool) {
+		if v.err != nil {
+			yield(Result{}, v.err)
+			return
+		}
+		for email := range emails {
+			if err := ctx.Err(); err != nil {
+				yield(v.anonymize(Result{Email: email, Truncated: true, Reachability: ReachabilityUnknown}), err)
+				return
+			}
+			if !yield(v.run(ctx, email, true), nil) {
+				return
+			}
+		}
+	}
+}
+
+// errorResult stands in for the Result of an email that could not be
+// validated, so that batch results never contain zero-value entries.
+func (v *Validator) errorResult(email string, err error) Result {
+	cr := CheckResult{Level: LevelPipeline, Passed: false, Details: err.Error(), Code: CodeError}
+	truncated := errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrStopped)
+	if truncated {
+		cr.Code = CodeCancelled
+	}
+	r := Result{Email: email, Truncated: truncated, Checks: []CheckResult{classify(cr)}}
+	r.Reachability = reachability(r)
+	return v.anonymize(r)
+}
+
+// run executes the checkers in registration order (see WithAutoOrder).
+// With shortCircuit it stops at the first failing level.
+// If ctx is done, the run stops and the Result is marked Truncated.
+func (v *Validator) run(ctx context.Context, email string, shortCircuit bool) Result {
+	return v.runReusing(ctx, email, shortCircuit, nil)
+}
 
+// runReusing is run with the results of the offline levels that already
+// ran on email (see prefilter): each offline level takes the next of them,
+// if it is for its level, instead of running again.
+func (v *Validator) runReusing(ctx context.Context, email string, shortCircuit bool, offline []CheckResult) Result {
 	parsed := parse.NewEmail(email)
-	result := Result{Email: email, Valid: true}
+	result := v.runChecks(ctx, email, parsed, shortCircuit, func(ctx context.Context, c Checker) (CheckResult, bool) {
+		if len(offline) > 0 && isOffline(c) && offline[0].Level == capabilities(c).Level {
+			cr := offline[0]
+			offline = offline[1:]
+			return cr, true
+		}
+		return c.Check(ctx, parsed), true
+	})
+	if v.calibration != nil {
+		result.DeliverabilityProbability = v.calibration.deliverability(result)
+	}
+	if v.scoring != nil {
+		result.RiskScore = v.scoring.risk(result, parsed.Local)
+	}
+	if v.candidates != nil {
+		if cand, ok := v.candidates.observe(parsed.Domain, result); ok && v.observed() {
+			v.emit(cand)
+		}
+	}
+	return v.anonymize(v.shape(result))
+}
+
+// runChecks drives the pipeline for run and ValidateDomain. parsed is the
+// parsed input, for the allow- and blocklist. runLevel runs one level; returning
+// false skips the level entirely.
+func (v *Validator) runChecks(ctx context.Context, input string, parsed Email, shortCircuit bool, runLevel runLevelFunc) Result {
+	result := v.runLevels(ctx, input, parsed, shortCircuit, runLevel)
+	result.Reachability = reachability(result)
+	result.FreeProvider = result.hasCode(CodeFreeProvider)
+	return result
+}
+
+// runLevels runs the levels for runChecks.
+func (v *Validator) runLevels(ctx context.Context, input string, parsed Email, shortCircuit bool, runLevel runLevelFunc) Result {
+	result := Result{Email: input, Valid: true}
+	ctx, vc := check.WithValidation(ctx, parsed)
+	observed := v.observed()
+	if observed {
+		v.emit(ValidationStarted{Time: time.Now(), Email: v.label(input)})
+	}
+
+	if cr, ok := v.listCheck(parsed); ok {
+		cr = classify(cr)
+		if observed {
+			v.emitCheckCompleted(input, cr, 0)
+		}
+		result.Checks = append(result.Checks, cr)
+		result.Valid = cr.Passed
+		return result
+	}
 
 	for _, c := range v.checkers {
-		cr := c.Check(ctx, parsed)
+		if ctx.Err() != nil {
+			result.Valid = false
+			result.Truncated = true
+			return result
+		}
+
+		start := time.Now()
+		cr, ok := v.guard(ctx, c, runLevel)
+		if !ok {
+			continue
+		}
+		cr = classify(cr)
+		if observed {
+			v.emitCheckCompleted(input, cr, time.Since(start))
+		}
+		if !cr.Passed && ctx.Err() != nil {
+			// The level didn't fail on its own merit, it was cut short
+			cr.Code = types.CodeCancelled
+			cr.Severity = checkSeverity(cr)
+			vc.AddCheck(cr)
+			result.Checks = append(result.Checks, cr)
+			result.Valid = false
+			result.Truncated = true
+			return result
+		}
+
+		vc.AddCheck(cr)
 		result.Checks = append(result.Checks, cr)
 		if !cr.Passed {
 			result.Valid = false
-			// don't stop, continue
+			// A level abandoned by the watchdog proves nothing: carry on
+			if shortCircuit && cr.Code != types.CodeTimedOut {
+				return result
+			}
 		}
 	}
 
-	return result, nil
+	return result
+}
+
+// emitCheckCompleted emits a CheckCompleted event, privacy mode applied.
+func (v *Validator) emitCheckCompleted(input string, cr CheckResult, d time.Duration) {
+	r := v.anonymize(Result{Email: input, Checks: []CheckResult{cr}})
+	v.emit(CheckCompleted{Time: time.Now(), Email: r.Email, Result: r.Checks[0], Duration: d})
 }
 
 // ConcurrencyOptions configures concurrent processing for ValidateMany.
 type ConcurrencyOptions struct {
 	// Workers is the number of concurrent goroutines. Default: 5
 	Workers int
+	// MaxQPS is a global ceiling on validations started per second,
+	// shared by all workers (token bucket). Default: 0 (unlimited)
+	MaxQPS float64
+	// Prefilter deduplicates the batch and runs the offline levels (those
+	// declaring CostOffline: syntax, domain, role, ...) on every address
+	// first; only survivors are sent to the network levels, without
+	// running the offline levels again. Rejected addresses get a Result
+	// holding just the offline checks; survivors' results are unchanged.
+	// Default: false
+	Prefilter bool
+	// Canaries are validated with the batch to verify the pipeline; if any
+	// is misclassified ValidateMany returns a *CanaryError. With MaxBatch
+	// they run with every chunk. Default: none
+	Canaries Canaries
+	// MaxBatch splits the input into chunks of at most this many emails,
+	// validated one after the other, so only one chunk's jobs and results
+	// are in memory at a time (see OnBatch). Prefilter deduplicates within
+	// a chunk. Default: 0 (the whole input at once)
+	MaxBatch int
+	// OnBatch receives the results of each chunk, in input order, with
+	// the offset of the chunk's first email in the input; ValidateMany
+	// then returns a nil slice. Returning an error stops the run and
+	// ValidateMany returns it. The callback runs on the calling goroutine
+	// and may keep the slice. Default: nil (results are collected)
+	OnBatch func(offset int, results []Result) error
+	// Stop soft-cancels the run when closed, e.g. on SIGINT: validations
+	// in progress finish, no new ones start, the results so far are
+	// returned (or flushed to OnBatch) and the run ends with a
+	// *StoppedError holding a Checkpoint. Unlike cancelling ctx, no probe
+	// is cut short. Default: nil (run to completion)
+	Stop <-chan struct{}
+	// PrefetchDNS is the number of concurrent MX lookups made for the
+	// unique domains of each chunk before and while it is validated, so
+	// that DNS latency overlaps with the first SMTP probes instead of
+	// being paid domain by domain. It needs a level with the shared DNS
+	// cache (WithDNS, WithSMTP). Default: 0 (no prefetch)
+	PrefetchDNS int
+	// Resume continues a ValidateReader run from the Checkpoint of a
+	// stopped one over the same input: addresses it records as done are
+	// skipped. ValidateMany ignores it. Default: nil
+	Resume *Checkpoint
 }
 
 // ValidateMany validates multiple emails concurrently.
 // The result order matches the input slice order.
 // Emails are sorted by domain internally for optimal DNS cache and
 // SMTP connection pool utilization.
+//
+// Every entry carries its Email, even when the batch returns an error: an
+// email that could not be validated gets an invalid Result with a single
+// LevelPipeline check holding the error (CodeCancelled, with Truncated
+// set, if ctx was done; CodeError otherwise). The returned error is the
+// first such failure, or else a *CanaryError if canaries were
+// misclassified. Only configuration errors return a nil slice.
+//
+// With ConcurrencyOptions.MaxBatch the input is validated in chunks; with
+// OnBatch each chunk's results are handed to the callback instead of being
+// collected, and the returned slice is nil.
 func (v *Validator) ValidateMany(ctx context.Context, emails []string, opts ...ConcurrencyOptions) ([]Result, error) {
 	if v.err != nil {
 		return nil, v.err
 	}
+	var o ConcurrencyOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	var limiter *ratelimit.Limiter
+	if o.MaxQPS > 0 {
+		limiter = ratelimit.New(o.MaxQPS, 1)
+	}
+	if o.MaxBatch <= 0 && o.OnBatch == nil {
+		results, stopped, err := v.validateBatch(ctx, emails, o, limiter)
+		if stopped != nil {
+			err = &StoppedError{Checkpoint: checkpointAt(0, len(emails), stopped)}
+		}
+		return results, err
+	}
 
+	size := o.MaxBatch
+	if size <= 0 {
+		size = len(emails)
+	}
+	var all []Result
+	if o.OnBatch == nil {
+		all = make([]Result, 0, len(emails))
+	}
+	var firstErr error
+	for off := 0; off < len(emails); off += size {
+		end := min(off+size, len(emails))
+		results, stopped, err := v.validateBatch(ctx, emails[off:end], o, limiter)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if stopped != nil {
+			// Later chunks are not started; collected results still
+			// carry every email
+			cp := checkpointAt(off, end, stopped)
+			if o.OnBatch == nil {
+				all = append(all, results...)
+				for _, email := range emails[end:] {
+					all = append(all, v.errorResult(email, ErrStopped))
+				}
+				cp.Pending = append(cp.Pending, seq(end, len(emails))...)
+				cp.Next = len(emails)
+			} else if err := o.OnBatch(off, results); err != nil {
+				return nil, err
+			}
+			return all, &StoppedError{Checkpoint: cp}
+		}
+		if o.OnBatch == nil {
+			all = append(all, results...)
+			continue
+		}
+		if err := o.OnBatch(off, results); err != nil {
+			return nil, err
+		}
+	}
+	return all, firstErr
+}
+
+// validateBatch is ValidateMany for one chunk of the input. If o.Stop was
+// closed before every email was started, stopped flags (by input index)
+// those that were not; it is nil otherwise.
+func (v *Validator) validateBatch(ctx context.Context, emails []string, o ConcurrencyOptions, limiter *ratelimit.Limiter) (results []Result, stopped []bool, err error) {
 	workers := 5
-	if len(opts) > 0 && opts[0].Workers > 0 {
-		workers = opts[0].Workers
+	if o.Workers > 0 {
+		workers = o.Workers
+	}
+
+	var canaries Canaries
+	if o.Canaries.len() > 0 {
+		canaries = o.Canaries
+		emails = slices.Concat(emails, canaries.Good, canaries.Bad)
+	}
+
+	var plan *prefilterPlan
+	if o.Prefilter {
+		plan = v.prefilter(ctx, emails)
+		emails = plan.survivors
 	}
 
-	results := make([]Result, len(emails))
+	defer v.prefetchMX(ctx, emails, o.PrefetchDNS, o.Stop)()
+
+	results = make([]Result, len(emails))
+	skipped := make([]bool, len(emails))
+	var anySkipped atomic.Bool
 	type job struct {
 		idx    int
 		email  string
@@ -252,12 +987,42 @@ func (v *Validator) ValidateMany(ctx context.Context, emails []string, opts ...C
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			ctx := check.WithWorker(ctx, i)
 			for j := range jobs {
-				res, err := v.Validate(ctx, j.email)
+				if isClosed(o.Stop) {
+					results[j.idx] = v.errorResult(j.email, ErrStopped)
+					skipped[j.idx] = true
+					anySkipped.Store(true)
+					continue
+				}
+				if limiter != nil {
+					start := time.Now()
+					err := limiter.Wait(ctx)
+					if waited := time.Since(start); v.observed() && (err != nil || waited >= time.Millisecond) {
+						v.emit(Throttled{Time: time.Now(), Email: v.label(j.email), Reason: "qps", Waited: waited, Deferred: err != nil})
+					}
+					if err != nil {
+						results[j.idx] = v.errorResult(j.email, err)
+						mu.Lock()
+						if firstErr == nil {
+							firstErr = fmt.Errorf("validating %q: %w", v.label(j.email), err)
+						}
+						mu.Unlock()
+						continue
+					}
+				}
+				var res Result
+				var err error
+				if plan != nil {
+					res, err = plan.validate(ctx, v, j.idx)
+				} else {
+					res, err = v.Validate(ctx, j.email)
+				}
 				if err != nil {
+					results[j.idx] = v.errorResult(j.email, err)
 					mu.Lock()
 					if firstErr == nil {
-						firstErr = fmt.Errorf("validating %q: %w", j.email, err)
+						firstErr = fmt.Errorf("validating %q: %w", v.label(j.email), err)
 					}
 					mu.Unlock()
 					continue
@@ -268,5 +1033,27 @@ func (v *Validator) ValidateMany(ctx context.Context, emails []string, opts ...C
 	}
 
 	wg.Wait()
-	return results, firstErr
+	if anySkipped.Load() {
+		stopped = skipped
+	}
+	if plan != nil {
+		results = plan.expand(v, results)
+		if stopped != nil {
+			stopped = plan.expandFlags(stopped)
+		}
+	}
+	if n := canaries.len(); n > 0 {
+		canaryResults := results[len(results)-n:]
+		results = results[:len(results)-n]
+		if stopped != nil {
+			// Canaries that were not validated can't be verified
+			stopped = stopped[:len(stopped)-n]
+			if !slices.Contains(stopped, true) {
+				stopped = nil
+			}
+		} else if err := canaries.verify(canaryResults); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return results, stopped, firstErr
 }
@@ -0,0 +1,96 @@
+package emailkit
+
+import (
+	"time"
+
+	"github.com/optimode/emailkit/internal/warmup"
+)
+
+// WarmUp ramps up the SMTP probe volume of a new identity — a fresh IP
+// address, HELO name or MAIL FROM domain — over its first days, as mail
+// providers block identities that start at full volume. Each provider
+// ("google", "microsoft", ...; see InspectDomain) or, for unrecognised
+// providers, each primary MX domain gets a daily budget of RCPT probes
+// that grows day by day; past the budget, or once a provider throttles
+// too many probes, the SMTP level reports CodeDeferred with the next
+// midnight in Meta["resume_at"]. The state per provider is in
+// Health().SMTP.WarmUp. It is kept in memory; ExportState and
+// ImportState carry today's counts across a restart.
+type WarmUp struct {
+	// Start is the identity's first day of probing, day 1 of the ramp.
+	// Required.
+	Start time.Time
+	// Days is the length of the ramp; after it probing is unlimited.
+	// Default: 14
+	Days int
+	// InitialDailyProbes is the budget of each provider on day 1.
+	// Default: 50
+	InitialDailyProbes int
+	// Growth multiplies the budget from one day to the next, e.g. 50 on
+	// day 1 and about 9700 on day 14 with the defaults. Default: 1.5
+	Growth float64
+	// MaxThrottleRate pauses a provider for the rest of the day once
+	// this share of its probes today was throttled (421, rate limiting or
+	// blocking replies). Negative disables it. Default: 0.1
+	MaxThrottleRate float64
+	// MinSamples is the number of probes a provider gets each day before
+	// MaxThrottleRate applies. Default: 20
+	MinSamples int
+	// Location is the time zone of the day boundaries. Default: UTC
+	Location *time.Location
+}
+
+// WarmUpStatus is a provider's warm-up state today; see
+// SMTPHealth.WarmUp.
+type WarmUpStatus = warmup.Status
+
+func (w *WarmUp) validate() error {
+	invalid := func(field string, value any, constraint string) error {
+		return &OptionsError{Field: "SMTPOptions.WarmUp." + field, Value: value, Constraint: constraint, Err: ErrInvalidSMTPOptions}
+	}
+	switch {
+	case w.Start.IsZero():
+		return invalid("Start", nil, "required")
+	case w.Days < 0:
+		return invalid("Days", w.Days, "must not be negative")
+	case w.InitialDailyProbes < 0:
+		return invalid("InitialDailyProbes", w.InitialDailyProbes, "must not be negative")
+	case w.Growth != 0 && w.Growth < 1:
+		return invalid("Growth", w.Growth, "must be at least 1")
+	case w.MinSamples < 0:
+		return invalid("MinSamples", w.MinSamples, "must not be negative")
+	}
+	return nil
+}
+
+// build creates the ramp, applying the defaults.
+func (w *WarmUp) build() *warmup.Ramp {
+	cfg := warmup.Config{
+		Start:           w.Start,
+		Days:            w.Days,
+		Initial:         w.InitialDailyProbes,
+		Growth:          w.Growth,
+		MaxThrottleRate: w.MaxThrottleRate,
+		MinSamples:      w.MinSamples,
+		Location:        w.Location,
+	}
+	if cfg.Days == 0 {
+		cfg.Days = 14
+	}
+	if cfg.Initial == 0 {
+		cfg.Initial = 50
+	}
+	if cfg.Growth == 0 {
+		cfg.Growth = 1.5
+	}
+	switch {
+	case cfg.MaxThrottleRate == 0:
+		cfg.MaxThrottleRate = 0.1
+	case cfg.MaxThrottleRate < 0:
+		cfg.MaxThrottleRate = 0
+	}
+	if cfg.MinSamples == 0 {
+		cfg.MinSamples = 20
+	}
+	return warmup.New(cfg)
+}
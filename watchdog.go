@@ -0,0 +1,73 @@
+package emailkit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WithWatchdog bounds every level of the pipeline to timeout, on top of
+// the levels' own network timeouts. A level still running after timeout
+// is abandoned — its context is cancelled, but the pipeline doesn't wait
+// for it — and recorded as a failed CheckResult with CodeTimedOut; a
+// level that panics is recorded with CodeError. Either way the pipeline
+// continues with the next level, even in Validate, so one stuck level
+// doesn't hide the others. The Result is not Valid. Default: no watchdog
+// (levels run inline and panics propagate)
+func (v *Validator) WithWatchdog(timeout time.Duration) *Validator {
+	v.watchdog = timeout
+	return v
+}
+
+// runLevelFunc runs one level; returning false skips the level.
+type runLevelFunc func(ctx context.Context, c Checker) (CheckResult, bool)
+
+// guard runs a level under the watchdog, if one is set.
+func (v *Validator) guard(ctx context.Context, c Checker, runLevel runLevelFunc) (CheckResult, bool) {
+	if v.watchdog <= 0 {
+		return runLevel(ctx, c)
+	}
+
+	type outcome struct {
+		cr    CheckResult
+		ok    bool
+		panic any
+	}
+	levelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan outcome, 1) // buffered: an abandoned level must not block
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- outcome{panic: p}
+			}
+		}()
+		cr, ok := runLevel(levelCtx, c)
+		done <- outcome{cr: cr, ok: ok}
+	}()
+
+	timer := time.NewTimer(v.watchdog)
+	defer timer.Stop()
+	select {
+	case o := <-done:
+		if o.panic != nil {
+			return CheckResult{Level: checkerLevel(c), Passed: false, Details: fmt.Sprintf("level panicked: %v", o.panic), Code: CodeError}, true
+		}
+		return o.cr, o.ok
+	case <-timer.C:
+		return CheckResult{Level: checkerLevel(c), Passed: false, Details: fmt.Sprintf("level timed out after %s (watchdog)", v.watchdog), Code: CodeTimedOut}, true
+	case <-ctx.Done():
+		// Reported as cut short by runChecks
+		return CheckResult{Level: checkerLevel(c), Passed: false, Details: ctx.Err().Error()}, true
+	}
+}
+
+// checkerLevel names the level of a checker by its declared Capabilities,
+// for results the watchdog records in its place; levels that declare none
+// are reported as LevelPipeline.
+func checkerLevel(c Checker) CheckLevel {
+	if level := capabilities(c).Level; level != "" {
+		return level
+	}
+	return LevelPipeline
+}
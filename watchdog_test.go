@@ -0,0 +1,92 @@
+package emailkit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailkit "github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/x/gravatar"
+)
+
+// stuckChecker blocks until its context is cancelled, like a level held
+// up by a server that defeats the network deadlines.
+type stuckChecker struct{ abandoned chan struct{} }
+
+func (c stuckChecker) Check(ctx context.Context, _ emailkit.Email) emailkit.CheckResult {
+	<-ctx.Done()
+	close(c.abandoned)
+	return emailkit.CheckResult{Level: "stuck", Passed: true}
+}
+
+type panickingChecker struct{}
+
+func (panickingChecker) Check(context.Context, emailkit.Email) emailkit.CheckResult {
+	panic("boom")
+}
+
+func TestWithWatchdog_Timeout(t *testing.T) {
+	stuck := stuckChecker{abandoned: make(chan struct{})}
+	v := emailkit.New().
+		With(stuck).
+		With(fixedChecker{res: emailkit.CheckResult{Level: "after", Passed: true}}).
+		WithWatchdog(20 * time.Millisecond)
+
+	res, err := v.Validate(context.Background(), "user@example.com")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	assert.False(t, res.Truncated)
+	require.Len(t, res.Checks, 3, "the pipeline continues past the stuck level")
+	assert.Equal(t, emailkit.LevelPipeline, res.Checks[1].Level)
+	assert.Equal(t, emailkit.CodeTimedOut, res.Checks[1].Code)
+	assert.Contains(t, res.Checks[1].Details, "timed out after 20ms")
+	assert.Equal(t, "after", res.Checks[2].Level)
+
+	select {
+	case <-stuck.abandoned:
+	case <-time.After(time.Second):
+		t.Fatal("the abandoned level's context was not cancelled")
+	}
+}
+
+func TestWithWatchdog_DeclaredLevel(t *testing.T) {
+	// A Gravatar endpoint that never answers
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	v := emailkit.New().
+		With(gravatar.New(gravatar.Options{BaseURL: srv.URL, Timeout: time.Hour})).
+		WithWatchdog(20 * time.Millisecond)
+
+	res, err := v.Validate(context.Background(), "user@example.com")
+	require.NoError(t, err)
+	require.Len(t, res.Checks, 2)
+	assert.Equal(t, gravatar.Level, res.Checks[1].Level, "named by its declared capabilities")
+	assert.Equal(t, emailkit.CodeTimedOut, res.Checks[1].Code)
+}
+
+func TestWithWatchdog_Panic(t *testing.T) {
+	v := emailkit.New().With(panickingChecker{}).WithWatchdog(time.Second)
+
+	res, err := v.Validate(context.Background(), "user@example.com")
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	require.Len(t, res.Checks, 2)
+	assert.Equal(t, emailkit.CodeError, res.Checks[1].Code)
+	assert.Equal(t, "level panicked: boom", res.Checks[1].Details)
+}
+
+func TestWithWatchdog_FastLevels(t *testing.T) {
+	v := emailkit.New().WithDomain().WithWatchdog(time.Second)
+
+	res, err := v.Validate(context.Background(), "john.doe@gmail.com")
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+	assert.Len(t, res.Checks, 2)
+}
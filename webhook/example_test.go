@@ -0,0 +1,49 @@
+package webhook_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/webhook"
+)
+
+func ExampleWebhook_OnBatch() {
+	ctx := context.Background()
+	hook := webhook.New(webhook.Options{
+		URL:    "https://crm.example/hooks/emailkit",
+		Secret: []byte("shared secret"),
+	})
+
+	v := emailkit.New().WithDNS()
+	_, err := v.ValidateMany(ctx, []string{"alice@example.com", "bob@example.com"}, emailkit.ConcurrencyOptions{
+		MaxBatch: 500,
+		OnBatch:  hook.OnBatch(ctx), // one signed POST per chunk
+	})
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func ExampleVerify() {
+	secret := []byte("shared secret")
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /hooks/emailkit", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = webhook.Verify(secret, body, r.Header.Get(webhook.HeaderSignature), r.Header.Get(webhook.HeaderTimestamp), 5*time.Minute)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		// Decode body as a webhook.Payload; drop deliveries already seen
+		// by their Delivery ID
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
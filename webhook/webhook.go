@@ -0,0 +1,275 @@
+// Package webhook delivers the results of a bulk run to an HTTP endpoint,
+// one POST per chunk, so external systems such as CRMs ingest outcomes
+// without polling:
+//
+//	hook := webhook.New(webhook.Options{URL: "https://crm.example/hooks/emailkit", Secret: secret})
+//	_, err := v.ValidateMany(ctx, emails, emailkit.ConcurrencyOptions{
+//		MaxBatch: 500,
+//		OnBatch:  hook.OnBatch(ctx),
+//	})
+//
+// Each request is signed with HMAC-SHA256 over its timestamp and body
+// (see Sign and Verify) and retried with exponential backoff on network
+// errors, 429 and 5xx responses.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/internal/features"
+)
+
+func init() {
+	features.Register(emailkit.Feature{
+		Name:        "webhook",
+		Stability:   emailkit.StabilityStable,
+		Package:     "github.com/optimode/emailkit/webhook",
+		Description: "HMAC-signed result webhooks with retries",
+	})
+}
+
+// Request headers.
+const (
+	// HeaderSignature holds "sha256=" and the hex HMAC-SHA256 of
+	// "<timestamp>.<body>" under the shared secret.
+	HeaderSignature = "X-Emailkit-Signature"
+	// HeaderTimestamp holds the Unix time the request was signed at.
+	HeaderTimestamp = "X-Emailkit-Timestamp"
+	// HeaderDelivery holds the delivery ID, which stays the same across
+	// retries: receivers use it to drop duplicates.
+	HeaderDelivery = "X-Emailkit-Delivery"
+)
+
+var (
+	// ErrDeliveryFailed is wrapped by the error of a delivery that was
+	// rejected or ran out of attempts.
+	ErrDeliveryFailed = errors.New("webhook: delivery failed")
+
+	// ErrInvalidSignature is returned by Verify for a missing, malformed,
+	// wrong or expired signature.
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+)
+
+// Options configures a Webhook.
+type Options struct {
+	// URL is the endpoint the results are POSTed to.
+	URL string
+	// Secret is the HMAC key shared with the receiver. Requests are sent
+	// unsigned if it is empty.
+	Secret []byte
+	// Header is added to every request, e.g. an Authorization header.
+	Header http.Header
+	// Client sends the requests. Default: a client with a 30s timeout
+	Client *http.Client
+	// MaxAttempts is the number of tries per delivery. Default: 5
+	MaxAttempts int
+	// MinBackoff is the wait after the first failed attempt; it doubles
+	// after every further one, with jitter. Default: 1s
+	MinBackoff time.Duration
+	// MaxBackoff caps the wait between attempts, including waits asked for
+	// with Retry-After. Default: 1m
+	MaxBackoff time.Duration
+	// Now is injectable for testing. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Payload is the JSON body of a delivery.
+type Payload struct {
+	Delivery string            `json:"delivery"`
+	Offset   int               `json:"offset"` // input index of the first result
+	Results  []emailkit.Result `json:"results"`
+}
+
+// Webhook delivers results to an endpoint. It is safe for concurrent use.
+type Webhook struct {
+	opts Options
+}
+
+// New creates a Webhook.
+func New(opts Options) *Webhook {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Minute
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Webhook{opts: opts}
+}
+
+// OnBatch returns a callback for emailkit.ConcurrencyOptions.OnBatch that
+// delivers each chunk. A failed delivery stops the run with its error;
+// wrap the callback to log and continue instead.
+func (w *Webhook) OnBatch(ctx context.Context) func(offset int, results []emailkit.Result) error {
+	return func(offset int, results []emailkit.Result) error {
+		return w.Deliver(ctx, offset, results)
+	}
+}
+
+// Deliver POSTs results as a Payload and returns once the endpoint has
+// answered 2xx. Network errors, 408, 429 and 5xx responses are retried
+// up to MaxAttempts times, honoring Retry-After; other responses fail at
+// once. The error wraps ErrDeliveryFailed, or is ctx's error if it is
+// done first.
+func (w *Webhook) Deliver(ctx context.Context, offset int, results []emailkit.Result) error {
+	id := newDeliveryID()
+	body, err := json.Marshal(Payload{Delivery: id, Offset: offset, Results: results})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+	}
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := w.post(ctx, id, body)
+		if err == nil {
+			return nil
+		}
+		var perm permanentError
+		if errors.As(err, &perm) {
+			return fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		lastErr = err
+		if attempt == w.opts.MaxAttempts {
+			break
+		}
+		wait := retryAfter
+		if wait <= 0 {
+			wait = w.backoff(attempt)
+		}
+		timer := time.NewTimer(min(wait, w.opts.MaxBackoff))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return fmt.Errorf("%w after %d attempts: %v", ErrDeliveryFailed, w.opts.MaxAttempts, lastErr)
+}
+
+// permanentError is a response that retrying does not change.
+type permanentError struct{ error }
+
+// post sends one attempt. retryAfter is the wait the endpoint asked for,
+// or 0.
+func (w *Webhook) post(ctx context.Context, id string, body []byte) (retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, permanentError{err}
+	}
+	for k, vs := range w.opts.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, id)
+	if len(w.opts.Secret) > 0 {
+		ts := w.opts.Now().Unix()
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+		req.Header.Set(HeaderSignature, Sign(w.opts.Secret, ts, body))
+	}
+
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // let the connection be reused
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return parseRetryAfter(resp.Header.Get("Retry-After"), w.opts.Now()), fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return 0, permanentError{fmt.Errorf("endpoint answered %s", resp.Status)}
+}
+
+// backoff returns the wait after the given failed attempt: MinBackoff
+// doubled per attempt, with up to 50% jitter so retrying senders spread.
+func (w *Webhook) backoff(attempt int) time.Duration {
+	d := float64(w.opts.MinBackoff) * math.Pow(2, float64(attempt-1))
+	d = min(d, float64(w.opts.MaxBackoff))
+	return time.Duration(d/2 + mathrand.Float64()*d/2)
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP
+// date; 0 if absent or malformed.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+func newDeliveryID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Sign returns the signature header value for a body sent at timestamp
+// (Unix seconds): "sha256=" and the hex HMAC-SHA256 of
+// "<timestamp>.<body>" under secret.
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a received delivery, for receivers
+// written in Go: signature and timestamp are the values of
+// HeaderSignature and HeaderTimestamp. A timestamp more than maxAge from
+// now is rejected to stop replays; maxAge <= 0 skips that check. It
+// returns nil or an error wrapping ErrInvalidSignature.
+func Verify(secret, body []byte, signature, timestamp string, maxAge time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp %q", ErrInvalidSignature, timestamp)
+	}
+	if maxAge > 0 {
+		if age := time.Since(time.Unix(ts, 0)); age > maxAge || age < -maxAge {
+			return fmt.Errorf("%w: timestamp outside %s", ErrInvalidSignature, maxAge)
+		}
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/webhook"
+)
+
+var secret = []byte("s3cret")
+
+// receiver records deliveries and answers with the queued statuses, then
+// 204.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, body)
+	r.headers = append(r.headers, req.Header.Clone())
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func newHook(url string) *webhook.Webhook {
+	return webhook.New(webhook.Options{
+		URL:        url,
+		Secret:     secret,
+		Header:     http.Header{"Authorization": {"Bearer t"}},
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+	})
+}
+
+func TestDeliver(t *testing.T) {
+	rec := &receiver{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	results := []emailkit.Result{{Email: "a@example.com", Valid: true}}
+	require.NoError(t, newHook(srv.URL).Deliver(context.Background(), 500, results))
+
+	require.Len(t, rec.bodies, 1)
+	h := rec.headers[0]
+	assert.Equal(t, "application/json", h.Get("Content-Type"))
+	assert.Equal(t, "Bearer t", h.Get("Authorization"))
+	assert.NoError(t, webhook.Verify(secret, rec.bodies[0], h.Get(webhook.HeaderSignature), h.Get(webhook.HeaderTimestamp), time.Minute))
+
+	var p webhook.Payload
+	require.NoError(t, json.Unmarshal(rec.bodies[0], &p))
+	assert.Equal(t, h.Get(webhook.HeaderDelivery), p.Delivery)
+	assert.Equal(t, 500, p.Offset)
+	require.Len(t, p.Results, 1)
+	assert.Equal(t, "a@example.com", p.Results[0].Email)
+}
+
+func TestDeliver_Retries(t *testing.T) {
+	rec := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	require.NoError(t, newHook(srv.URL).Deliver(context.Background(), 0, nil))
+	require.Len(t, rec.headers, 3)
+	id := rec.headers[0].Get(webhook.HeaderDelivery)
+	assert.NotEmpty(t, id)
+	for _, h := range rec.headers {
+		assert.Equal(t, id, h.Get(webhook.HeaderDelivery), "the delivery ID is kept across retries")
+	}
+}
+
+func TestDeliver_GivesUp(t *testing.T) {
+	rec := &receiver{statuses: []int{500, 500, 500, 500, 500, 500}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	hook := webhook.New(webhook.Options{URL: srv.URL, MaxAttempts: 3, MinBackoff: time.Millisecond})
+	err := hook.Deliver(context.Background(), 0, nil)
+	require.ErrorIs(t, err, webhook.ErrDeliveryFailed)
+	assert.Contains(t, err.Error(), "after 3 attempts")
+	assert.Len(t, rec.bodies, 3)
+	assert.Empty(t, rec.headers[0].Get(webhook.HeaderSignature), "no secret, no signature")
+}
+
+func TestDeliver_PermanentFailure(t *testing.T) {
+	rec := &receiver{statuses: []int{http.StatusUnauthorized}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	err := newHook(srv.URL).Deliver(context.Background(), 0, nil)
+	require.ErrorIs(t, err, webhook.ErrDeliveryFailed)
+	assert.Len(t, rec.bodies, 1, "4xx is not retried")
+}
+
+func TestDeliver_Cancelled(t *testing.T) {
+	rec := &receiver{statuses: []int{500}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hook := webhook.New(webhook.Options{URL: srv.URL, MinBackoff: time.Hour})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	assert.ErrorIs(t, hook.Deliver(ctx, 0, nil), context.Canceled)
+}
+
+func TestOnBatch(t *testing.T) {
+	rec := &receiver{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	ctx := context.Background()
+	emails := []string{"a@example.com", "b@example.com", "not-an-address"}
+	_, err := emailkit.New().ValidateMany(ctx, emails, emailkit.ConcurrencyOptions{
+		MaxBatch: 2,
+		OnBatch:  newHook(srv.URL).OnBatch(ctx),
+	})
+	require.NoError(t, err)
+	require.Len(t, rec.bodies, 2)
+
+	var p webhook.Payload
+	require.NoError(t, json.Unmarshal(rec.bodies[1], &p))
+	assert.Equal(t, 2, p.Offset)
+	require.Len(t, p.Results, 1)
+	assert.False(t, p.Results[0].Valid)
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"results":[]}`)
+	now := time.Now().Unix()
+	ts := strconv.FormatInt(now, 10)
+	sig := webhook.Sign(secret, now, body)
+
+	assert.NoError(t, webhook.Verify(secret, body, sig, ts, time.Minute))
+	assert.ErrorIs(t, webhook.Verify([]byte("other"), body, sig, ts, time.Minute), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify(secret, []byte(`{}`), sig, ts, time.Minute), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify(secret, body, sig, "yesterday", time.Minute), webhook.ErrInvalidSignature)
+
+	old := now - 3600
+	oldSig := webhook.Sign(secret, old, body)
+	assert.ErrorIs(t, webhook.Verify(secret, body, oldSig, strconv.FormatInt(old, 10), time.Minute), webhook.ErrInvalidSignature)
+	assert.NoError(t, webhook.Verify(secret, body, oldSig, strconv.FormatInt(old, 10), 0))
+}
@@ -0,0 +1,23 @@
+// Package x is the root of emailkit's experimental namespace. Packages
+// under emailkit/x/ hold levels and subsystems whose API is not settled
+// yet (e.g. domain reputation, breach lookups, Gravatar): they may change
+// or be removed in any minor release, unlike the rest of the module,
+// which follows semantic versioning.
+//
+// An experimental package registers its features with
+// StabilityExperimental when imported, so emailkit.Features tells what a
+// binary depends on:
+//
+//	for _, f := range emailkit.Features() {
+//		if f.Stability == emailkit.StabilityExperimental {
+//			log.Printf("using experimental %s (%s)", f.Name, f.Package)
+//		}
+//	}
+//
+// Promotion: once its API has held for a minor release without breaking
+// changes, an experimental package moves into the stable tree (the root
+// package or check/). The feature keeps its name and becomes
+// StabilityStable. The x/ package stays for one more minor release as
+// type aliases and forwarding functions marked Deprecated, then is
+// removed.
+package x
@@ -0,0 +1,25 @@
+package gravatar_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/x/gravatar"
+)
+
+func ExampleNew() {
+	g := gravatar.New(gravatar.Options{
+		Client:  &http.Client{Transport: http.DefaultTransport},
+		Timeout: 2 * time.Second,
+	})
+	v := emailkit.New().WithDNS().With(g)
+	defer v.Close()
+}
+
+func ExampleHash() {
+	fmt.Println(gravatar.Hash("MyEmailAddress@example.com "))
+	// Output:
+	// 84059b07d4be67b806386c0aad8070a23f18836bbaae342275dc0a83414c32ee
+}
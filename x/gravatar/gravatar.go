@@ -0,0 +1,143 @@
+// Package gravatar reports whether an address has a Gravatar avatar: a
+// cheap hint that a real person uses it, since someone signed up and
+// uploaded a picture. It adds a "gravatar" level:
+//
+//	v := emailkit.New().WithDNS().With(gravatar.New(gravatar.Options{}))
+//
+// The level always passes; a found avatar is recorded in Meta "gravatar"
+// and "gravatar.url". Each check makes one HTTPS request to Gravatar,
+// which sees the SHA-256 hash of the address, never the address itself.
+//
+// The package is experimental (see package x).
+package gravatar
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/internal/features"
+)
+
+func init() {
+	features.Register(emailkit.Feature{
+		Name:        "gravatar",
+		Stability:   emailkit.StabilityExperimental,
+		Package:     "github.com/optimode/emailkit/x/gravatar",
+		Description: "Gravatar existence enrichment level",
+	})
+}
+
+// Level is the CheckLevel of the Checker.
+const Level emailkit.CheckLevel = "gravatar"
+
+// DefaultBaseURL is the Gravatar avatar endpoint.
+const DefaultBaseURL = "https://gravatar.com/avatar/"
+
+// Options configures a Checker.
+type Options struct {
+	// Client sends the requests. Default: http.DefaultClient
+	Client *http.Client
+	// Timeout bounds each request. Default: 3s
+	Timeout time.Duration
+	// BaseURL is the avatar endpoint the hash is appended to.
+	// Default: DefaultBaseURL
+	BaseURL string
+}
+
+// Checker is the gravatar level. It is safe for concurrent use.
+type Checker struct {
+	opts Options
+}
+
+// New returns a Checker.
+func New(opts Options) *Checker {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 3 * time.Second
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultBaseURL
+	}
+	if !strings.HasSuffix(opts.BaseURL, "/") {
+		opts.BaseURL += "/"
+	}
+	return &Checker{opts: opts}
+}
+
+// Hash returns the Gravatar hash of an address: the hex SHA-256 of the
+// trimmed, lowercased address.
+func Hash(address string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(address))))
+	return hex.EncodeToString(sum[:])
+}
+
+// URL returns the avatar URL of an address.
+func (c *Checker) URL(address string) string {
+	return c.opts.BaseURL + Hash(address)
+}
+
+// Exists reports whether address has an avatar. An error means Gravatar
+// did not give an answer.
+func (c *Checker) Exists(ctx context.Context, address string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	// d=404 answers 404 instead of a default image
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL(address)+"?d=404", nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.opts.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	_ = resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("gravatar answered %d", resp.StatusCode)
+}
+
+// Capabilities implements emailkit.CapabilityDeclarer: one HTTPS request.
+func (c *Checker) Capabilities() emailkit.Capabilities {
+	return emailkit.Capabilities{Level: Level, Cost: emailkit.CostNetwork}
+}
+
+// Check implements emailkit.Checker. It passes in every case: with Meta
+// "gravatar" "true" and "gravatar.url" when an avatar exists, "false"
+// when none does, and no Meta when Gravatar could not be asked.
+func (c *Checker) Check(ctx context.Context, email emailkit.Email) emailkit.CheckResult {
+	if !email.Valid {
+		return emailkit.CheckResult{Level: Level, Passed: false, Details: "skipped: invalid email"}
+	}
+
+	address := email.Local + "@" + email.Domain
+	found, err := c.Exists(ctx, address)
+	switch {
+	case err != nil:
+		return emailkit.CheckResult{Level: Level, Passed: true, Details: fmt.Sprintf("gravatar status unknown: %v", err), Category: emailkit.CategoryReputation}
+	case !found:
+		return emailkit.CheckResult{Level: Level, Passed: true, Details: "no gravatar", Category: emailkit.CategoryReputation, Meta: map[string]string{"gravatar": "false"}}
+	}
+	return emailkit.CheckResult{
+		Level:    Level,
+		Passed:   true,
+		Details:  "gravatar found",
+		Category: emailkit.CategoryReputation,
+		Meta:     map[string]string{"gravatar": "true", "gravatar.url": c.URL(address)},
+	}
+}
@@ -0,0 +1,85 @@
+package gravatar_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/x/gravatar"
+)
+
+func TestHash(t *testing.T) {
+	// sha256 of "myemailaddress@example.com"
+	assert.Equal(t, "84059b07d4be67b806386c0aad8070a23f18836bbaae342275dc0a83414c32ee", gravatar.Hash(" MyEmailAddress@example.com "))
+}
+
+func TestChecker(t *testing.T) {
+	withAvatar := gravatar.Hash("jane@example.com")
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		switch strings.TrimPrefix(r.URL.Path, "/avatar/") {
+		case withAvatar:
+			_, _ = w.Write([]byte("png"))
+		case gravatar.Hash("down@example.com"):
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	g := gravatar.New(gravatar.Options{Client: srv.Client(), BaseURL: srv.URL + "/avatar"})
+	v := emailkit.New().With(g)
+	ctx := context.Background()
+
+	r, err := v.Validate(ctx, "Jane@Example.com")
+	require.NoError(t, err)
+	c, ok := r.CheckFor(gravatar.Level)
+	require.True(t, ok)
+	assert.True(t, c.Passed)
+	assert.Equal(t, "true", c.Meta["gravatar"])
+	assert.Equal(t, srv.URL+"/avatar/"+withAvatar, c.Meta["gravatar.url"])
+	assert.Equal(t, emailkit.CategoryReputation, c.Category)
+	assert.Equal(t, []string{"d=404"}, queries)
+
+	r, _ = v.Validate(ctx, "joe@example.com")
+	c, _ = r.CheckFor(gravatar.Level)
+	assert.True(t, r.Valid)
+	assert.Equal(t, "no gravatar", c.Details)
+	assert.Equal(t, "false", c.Meta["gravatar"])
+
+	r, _ = v.Validate(ctx, "down@example.com")
+	c, _ = r.CheckFor(gravatar.Level)
+	assert.True(t, r.Valid, "an unanswered lookup does not fail the address")
+	assert.Equal(t, "gravatar status unknown: gravatar answered 503", c.Details)
+	assert.Nil(t, c.Meta)
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	g := gravatar.New(gravatar.Options{Client: srv.Client(), BaseURL: srv.URL, Timeout: 50 * time.Millisecond})
+	_, err := g.Exists(context.Background(), "jane@example.com")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFeatureRegistered(t *testing.T) {
+	for _, f := range emailkit.Features() {
+		if f.Name == "gravatar" {
+			assert.Equal(t, emailkit.StabilityExperimental, f.Stability)
+			return
+		}
+	}
+	t.Fatal("gravatar feature not registered")
+}
@@ -0,0 +1,42 @@
+package reputation_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/x/reputation"
+)
+
+func ExampleNew() {
+	l := reputation.New(reputation.Options{})
+	if f, err := os.Open("reputation.json"); err == nil {
+		_ = l.Load(f)
+		_ = f.Close()
+	}
+
+	v := emailkit.New().
+		WithDNS().
+		WithSMTP(emailkit.SMTPOptions{
+			HeloDomain: "myapp.com",
+			MailFrom:   "verify@myapp.com",
+			PerDomain:  l.Policy,
+		}).
+		With(l.Checker()).
+		WithSubscriber(l).
+		WithCalibration()
+	defer v.Close()
+}
+
+func ExampleLearner_Stats() {
+	l := reputation.New(reputation.Options{})
+	for range 3 {
+		l.Observe("example.com", emailkit.CheckResult{Level: emailkit.LevelSMTP, Passed: true})
+	}
+	l.Observe("example.com", emailkit.CheckResult{Level: emailkit.LevelSMTP, Code: emailkit.CodeGreylisted})
+
+	s, _ := l.Stats("example.com")
+	fmt.Printf("%.2f accepted, %.2f greylisted\n", s.AcceptanceRate(), s.GreylistRate())
+	// Output:
+	// 0.75 accepted, 0.25 greylisted
+}
@@ -0,0 +1,382 @@
+// Package reputation learns per-domain SMTP probe outcomes over time —
+// acceptance rates, greylisting frequency, catch-all flips — and feeds
+// them back into scoring and probing strategy, giving long-running
+// deployments a memory of the mail ecosystem they see.
+//
+// A Learner subscribes to a Validator's events, answers the SMTP
+// PerDomain hook, and adds a "reputation" level:
+//
+//	l := reputation.New(reputation.Options{})
+//	v := emailkit.New().WithDNS().
+//	    WithSMTP(emailkit.SMTPOptions{HeloDomain: "myapp.com", PerDomain: l.Policy}).
+//	    With(l.Checker()).
+//	    WithSubscriber(l)
+//
+// Observations decay with Options.HalfLife, so a domain's record follows
+// its current behavior. Persist it across restarts with Save and Load.
+//
+// The package is experimental (see package x).
+package reputation
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/internal/features"
+	"github.com/optimode/emailkit/internal/parse"
+)
+
+func init() {
+	features.Register(emailkit.Feature{
+		Name:        "reputation",
+		Stability:   emailkit.StabilityExperimental,
+		Package:     "github.com/optimode/emailkit/x/reputation",
+		Description: "per-domain SMTP outcome learning",
+	})
+}
+
+// Level is the CheckLevel of the Checker.
+const Level emailkit.CheckLevel = "reputation"
+
+// Options configures a Learner.
+type Options struct {
+	// MinProbes is the (decayed) number of observed probes below which a
+	// domain has no reputation yet. Default: 20
+	MinProbes float64
+	// HalfLife is the age at which an observation counts half. Default: 30 days
+	HalfLife time.Duration
+	// CatchAllRate is the share of accepted probes above which a domain
+	// that never rejects a recipient is treated as catch-all, even if no
+	// fingerprint matched. Default: 0.98
+	CatchAllRate float64
+	// GreylistRate is the share of greylisted probes above which Policy
+	// only checks the SMTP session (StrategyConnect). Default: 0.5
+	GreylistRate float64
+	// BlockedRate is the share of probes refused for the prober (blocked,
+	// rate-limited or policy) above which Policy stops probing the domain
+	// (StrategySkip). Default: 0.5
+	BlockedRate float64
+	// Now is injectable for testing. Default: time.Now
+	Now func() time.Time
+}
+
+// Stats is what a Learner knows about a domain. Counts are decayed by
+// Options.HalfLife, so they are fractional.
+type Stats struct {
+	Domain     string  `json:"domain"`
+	Accepted   float64 `json:"accepted"`
+	Rejected   float64 `json:"rejected"` // mailbox unknown, disabled or full
+	Greylisted float64 `json:"greylisted"`
+	Blocked    float64 `json:"blocked"` // blocked, rate-limited or policy refusals
+	Unknown    float64 `json:"unknown"` // temporary and other inconclusive failures
+	// CatchAll counts the accepted probes the SMTP level flagged as
+	// catch-all.
+	CatchAll float64 `json:"catchAll"`
+	// CatchAllFlips counts how often the catch-all flag of consecutive
+	// accepted probes changed, a sign of a domain switching providers or
+	// of catch-all detection that doesn't hold for it.
+	CatchAllFlips int       `json:"catchAllFlips"`
+	LastCatchAll  bool      `json:"lastCatchAll"`
+	Updated       time.Time `json:"updated"`
+}
+
+// Probes returns the (decayed) number of observed probes.
+func (s Stats) Probes() float64 {
+	return s.Accepted + s.Rejected + s.Greylisted + s.Blocked + s.Unknown
+}
+
+// AcceptanceRate returns the share of accepted probes.
+func (s Stats) AcceptanceRate() float64 { return s.rate(s.Accepted) }
+
+// GreylistRate returns the share of greylisted probes.
+func (s Stats) GreylistRate() float64 { return s.rate(s.Greylisted) }
+
+// BlockedRate returns the share of probes refused for the prober.
+func (s Stats) BlockedRate() float64 { return s.rate(s.Blocked) }
+
+// CatchAllRate returns the share of accepted probes flagged as catch-all.
+func (s Stats) CatchAllRate() float64 {
+	if s.Accepted == 0 {
+		return 0
+	}
+	return s.CatchAll / s.Accepted
+}
+
+func (s Stats) rate(n float64) float64 {
+	if p := s.Probes(); p > 0 {
+		return n / p
+	}
+	return 0
+}
+
+// decay ages the counts to now.
+func (s *Stats) decay(now time.Time, halfLife time.Duration) {
+	if s.Updated.IsZero() || !now.After(s.Updated) {
+		return
+	}
+	f := math.Exp2(-float64(now.Sub(s.Updated)) / float64(halfLife))
+	s.Accepted *= f
+	s.Rejected *= f
+	s.Greylisted *= f
+	s.Blocked *= f
+	s.Unknown *= f
+	s.CatchAll *= f
+}
+
+// Learner records SMTP outcomes per recipient domain. It is an
+// emailkit.Subscriber and is safe for concurrent use.
+type Learner struct {
+	opts Options
+
+	mu      sync.Mutex
+	domains map[string]*Stats
+}
+
+var (
+	_ emailkit.Subscriber = (*Learner)(nil)
+	_ emailkit.Forgetter  = (*Learner)(nil)
+)
+
+// New creates an empty Learner.
+func New(opts Options) *Learner {
+	if opts.MinProbes <= 0 {
+		opts.MinProbes = 20
+	}
+	if opts.HalfLife <= 0 {
+		opts.HalfLife = 30 * 24 * time.Hour
+	}
+	if opts.CatchAllRate <= 0 {
+		opts.CatchAllRate = 0.98
+	}
+	if opts.GreylistRate <= 0 {
+		opts.GreylistRate = 0.5
+	}
+	if opts.BlockedRate <= 0 {
+		opts.BlockedRate = 0.5
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Learner{opts: opts, domains: make(map[string]*Stats)}
+}
+
+// HandleEvent implements emailkit.Subscriber: it observes the SMTP level
+// of every CheckCompleted event. Events of privacy-mode validators carry
+// hashed addresses and are ignored.
+func (l *Learner) HandleEvent(e emailkit.Event) {
+	cc, ok := e.(emailkit.CheckCompleted)
+	if !ok || cc.Result.Level != emailkit.LevelSMTP || !strings.Contains(cc.Email, "@") {
+		return
+	}
+	if email := parse.NewEmail(cc.Email); email.Valid {
+		l.Observe(email.Domain, cc.Result)
+	}
+}
+
+// Observe records the SMTP level result r for domain (ASCII/Punycode
+// form). Results that say nothing about the domain's mailboxes are
+// ignored: skipped, deferred, cancelled or abandoned levels and
+// connect-only probes.
+func (l *Learner) Observe(domain string, r emailkit.CheckResult) {
+	if r.Level != emailkit.LevelSMTP || r.Meta["probe"] == "connect" {
+		return
+	}
+	switch r.Code {
+	case emailkit.CodeSkipped, emailkit.CodeDeferred, emailkit.CodeCancelled, emailkit.CodeTimedOut:
+		return
+	}
+	domain = strings.ToLower(domain)
+	now := l.opts.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s, ok := l.domains[domain]
+	if !ok {
+		s = &Stats{Domain: domain}
+		l.domains[domain] = s
+	}
+	s.decay(now, l.opts.HalfLife)
+	s.Updated = now
+	switch {
+	case r.Passed:
+		catchAll := r.Code == emailkit.CodeCatchAll
+		if s.Accepted > 0 && catchAll != s.LastCatchAll {
+			s.CatchAllFlips++
+		}
+		s.Accepted++
+		s.LastCatchAll = catchAll
+		if catchAll {
+			s.CatchAll++
+		}
+	case r.Code == emailkit.CodeMailboxUnknown || r.Code == emailkit.CodeMailboxDisabled || r.Code == emailkit.CodeMailboxFull:
+		s.Rejected++
+	case r.Code == emailkit.CodeGreylisted:
+		s.Greylisted++
+	case r.Code == emailkit.CodeBlocked || r.Code == emailkit.CodeRateLimited || r.Code == emailkit.CodePolicy:
+		s.Blocked++
+	default:
+		s.Unknown++
+	}
+}
+
+// Stats returns what the Learner knows about domain, decayed to now.
+func (l *Learner) Stats(domain string) (Stats, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s, ok := l.domains[strings.ToLower(domain)]
+	if !ok {
+		return Stats{}, false
+	}
+	out := *s
+	out.decay(l.opts.Now(), l.opts.HalfLife)
+	return out, true
+}
+
+// Forget drops what the Learner knows about domain, e.g. after its
+// mail setup changed, and reports whether it knew anything. It implements
+// emailkit.Forgetter, so Validator.Invalidate calls it for a subscribed
+// Learner.
+func (l *Learner) Forget(domain string) bool {
+	domain = strings.ToLower(domain)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.domains[domain]
+	delete(l.domains, domain)
+	return ok
+}
+
+// Domains returns the stats of every observed domain, decayed to now and
+// sorted by domain.
+func (l *Learner) Domains() []Stats {
+	now := l.opts.Now()
+	l.mu.Lock()
+	out := make([]Stats, 0, len(l.domains))
+	for _, s := range l.domains {
+		st := *s
+		st.decay(now, l.opts.HalfLife)
+		out = append(out, st)
+	}
+	l.mu.Unlock()
+	slices.SortFunc(out, func(a, b Stats) int { return cmp.Compare(a.Domain, b.Domain) })
+	return out
+}
+
+// known returns the stats of domain if it has a reputation.
+func (l *Learner) known(domain string) (Stats, bool) {
+	s, ok := l.Stats(domain)
+	return s, ok && s.Probes() >= l.opts.MinProbes
+}
+
+// catchAll reports whether s looks like a catch-all domain: flagged so
+// by the SMTP level, or accepting every recipient.
+func (l *Learner) catchAll(s Stats) bool {
+	if s.CatchAllRate() >= l.opts.CatchAllRate {
+		return true
+	}
+	return s.Rejected == 0 && s.AcceptanceRate() >= l.opts.CatchAllRate
+}
+
+// Policy returns the SMTP probing policy learned for domain; set it as
+// SMTPOptions.PerDomain. Domains that refuse the prober are skipped
+// (StrategySkip), and catch-all and mostly greylisting domains only get
+// their SMTP session checked (StrategyConnect), since RCPT TO tells
+// nothing there. Such probes are not observed, so the record decays below
+// Options.MinProbes after a while and full probing resumes. Unknown
+// domains get the zero policy (the SMTPOptions defaults).
+func (l *Learner) Policy(domain string) emailkit.DomainSMTPPolicy {
+	s, ok := l.known(domain)
+	switch {
+	case !ok:
+		return emailkit.DomainSMTPPolicy{}
+	case s.BlockedRate() >= l.opts.BlockedRate:
+		return emailkit.DomainSMTPPolicy{Strategy: emailkit.StrategySkip}
+	case l.catchAll(s) || s.GreylistRate() >= l.opts.GreylistRate:
+		return emailkit.DomainSMTPPolicy{Strategy: emailkit.StrategyConnect}
+	}
+	return emailkit.DomainSMTPPolicy{}
+}
+
+// Checker returns the "reputation" level. It always passes and reports
+// the domain's rates in Meta; for a learned catch-all domain it passes
+// with CodeCatchAll, which lowers the deliverability probability like a
+// catch-all SMTP answer (see WithCalibration).
+func (l *Learner) Checker() emailkit.Checker {
+	return checker{l}
+}
+
+type checker struct{ l *Learner }
+
+// Capabilities implements emailkit.CapabilityDeclarer: the level only
+// reads what the Learner recorded.
+func (c checker) Capabilities() emailkit.Capabilities {
+	return emailkit.Capabilities{Level: Level, Cost: emailkit.CostOffline}
+}
+
+// Forget implements emailkit.Forgetter.
+func (c checker) Forget(domain string) bool { return c.l.Forget(domain) }
+
+func (c checker) Check(ctx context.Context, email emailkit.Email) emailkit.CheckResult {
+	return c.CheckDomain(ctx, email)
+}
+
+// CheckDomain implements emailkit.DomainOnlyChecker.
+func (c checker) CheckDomain(_ context.Context, email emailkit.Email) emailkit.CheckResult {
+	s, ok := c.l.known(email.Domain)
+	if !ok {
+		return emailkit.CheckResult{Level: Level, Passed: true, Details: "no reputation yet", Category: emailkit.CategoryReputation}
+	}
+	r := emailkit.CheckResult{
+		Level:    Level,
+		Passed:   true,
+		Details:  fmt.Sprintf("%.0f%% of %.0f probes accepted", 100*s.AcceptanceRate(), s.Probes()),
+		Category: emailkit.CategoryReputation,
+		Meta: map[string]string{
+			"probes":          fmt.Sprintf("%.1f", s.Probes()),
+			"acceptance_rate": fmt.Sprintf("%.2f", s.AcceptanceRate()),
+			"greylist_rate":   fmt.Sprintf("%.2f", s.GreylistRate()),
+			"blocked_rate":    fmt.Sprintf("%.2f", s.BlockedRate()),
+			"catch_all_flips": fmt.Sprint(s.CatchAllFlips),
+		},
+	}
+	if c.l.catchAll(s) {
+		r.Code = emailkit.CodeCatchAll
+		r.Details = "learned catch-all domain: " + r.Details
+	}
+	return r
+}
+
+// Save writes every domain's stats as JSON.
+func (l *Learner) Save(w io.Writer) error {
+	l.mu.Lock()
+	out := make([]Stats, 0, len(l.domains))
+	for _, s := range l.domains {
+		out = append(out, *s)
+	}
+	l.mu.Unlock()
+	slices.SortFunc(out, func(a, b Stats) int { return cmp.Compare(a.Domain, b.Domain) })
+	return json.NewEncoder(w).Encode(out)
+}
+
+// Load reads stats written by Save, replacing those of the same domains.
+func (l *Learner) Load(r io.Reader) error {
+	var in []Stats
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return fmt.Errorf("reputation: load: %w", err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, s := range in {
+		s.Domain = strings.ToLower(s.Domain)
+		l.domains[s.Domain] = &s
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package reputation_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/optimode/emailkit"
+	"github.com/optimode/emailkit/x/reputation"
+)
+
+// clock is a settable Options.Now.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newLearner(opts reputation.Options) (*reputation.Learner, *clock) {
+	c := &clock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	opts.Now = c.now
+	return reputation.New(opts), c
+}
+
+func smtp(passed bool, code emailkit.CheckCode) emailkit.CheckResult {
+	return emailkit.CheckResult{Level: emailkit.LevelSMTP, Passed: passed, Code: code}
+}
+
+// smtpLevel stands in for the SMTP level, answering with its result.
+type smtpLevel struct{ r emailkit.CheckResult }
+
+func (s smtpLevel) Check(context.Context, emailkit.Email) emailkit.CheckResult { return s.r }
+
+func TestLearner_Observe(t *testing.T) {
+	l, _ := newLearner(reputation.Options{})
+
+	l.Observe("Example.com", smtp(true, ""))
+	l.Observe("example.com", smtp(true, emailkit.CodeCatchAll))
+	l.Observe("example.com", smtp(false, emailkit.CodeMailboxUnknown))
+	l.Observe("example.com", smtp(false, emailkit.CodeGreylisted))
+	l.Observe("example.com", smtp(false, emailkit.CodeRateLimited))
+	l.Observe("example.com", smtp(false, emailkit.CodeTemporary))
+	// Not about the domain's mailboxes
+	l.Observe("example.com", smtp(true, emailkit.CodeSkipped))
+	l.Observe("example.com", smtp(false, emailkit.CodeDeferred))
+	l.Observe("example.com", emailkit.CheckResult{Level: emailkit.LevelSMTP, Passed: true, Meta: map[string]string{"probe": "connect"}})
+	l.Observe("example.com", emailkit.CheckResult{Level: emailkit.LevelDNS, Passed: true})
+
+	s, ok := l.Stats("EXAMPLE.COM")
+	require.True(t, ok)
+	assert.Equal(t, "example.com", s.Domain)
+	assert.InDelta(t, 6, s.Probes(), 1e-9)
+	assert.InDelta(t, 2, s.Accepted, 1e-9)
+	assert.InDelta(t, 1, s.CatchAll, 1e-9)
+	assert.InDelta(t, 1.0/6, s.GreylistRate(), 1e-9)
+	assert.InDelta(t, 1.0/6, s.BlockedRate(), 1e-9)
+	assert.InDelta(t, 0.5, s.CatchAllRate(), 1e-9)
+	assert.Equal(t, 1, s.CatchAllFlips)
+
+	_, ok = l.Stats("other.example")
+	assert.False(t, ok)
+}
+
+func TestLearner_Decay(t *testing.T) {
+	l, c := newLearner(reputation.Options{HalfLife: time.Hour})
+	for range 4 {
+		l.Observe("example.com", smtp(true, ""))
+	}
+	c.t = c.t.Add(2 * time.Hour)
+	s, _ := l.Stats("example.com")
+	assert.InDelta(t, 1, s.Accepted, 1e-9)
+
+	l.Observe("example.com", smtp(false, emailkit.CodeMailboxUnknown))
+	s, _ = l.Stats("example.com")
+	assert.InDelta(t, 2, s.Probes(), 1e-9)
+	assert.InDelta(t, 0.5, s.AcceptanceRate(), 1e-9)
+}
+
+func TestLearner_Policy(t *testing.T) {
+	l, c := newLearner(reputation.Options{MinProbes: 4, HalfLife: time.Hour})
+	for range 4 {
+		l.Observe("blocked.example", smtp(false, emailkit.CodeBlocked))
+		l.Observe("catchall.example", smtp(true, emailkit.CodeCatchAll))
+		l.Observe("grey.example", smtp(false, emailkit.CodeGreylisted))
+		l.Observe("normal.example", smtp(true, ""))
+		l.Observe("normal.example", smtp(false, emailkit.CodeMailboxUnknown))
+	}
+	l.Observe("new.example", smtp(false, emailkit.CodeBlocked))
+
+	assert.Equal(t, emailkit.StrategySkip, l.Policy("blocked.example").Strategy)
+	assert.Equal(t, emailkit.StrategyConnect, l.Policy("catchall.example").Strategy)
+	assert.Equal(t, emailkit.StrategyConnect, l.Policy("grey.example").Strategy)
+	assert.Equal(t, emailkit.DomainSMTPPolicy{}, l.Policy("normal.example"))
+	assert.Equal(t, emailkit.DomainSMTPPolicy{}, l.Policy("new.example"), "below MinProbes")
+
+	// The record decays below MinProbes and full probing resumes
+	c.t = c.t.Add(2 * time.Hour)
+	assert.Equal(t, emailkit.DomainSMTPPolicy{}, l.Policy("blocked.example"))
+}
+
+func TestLearner_Checker(t *testing.T) {
+	l, _ := newLearner(reputation.Options{MinProbes: 3})
+	for range 3 {
+		l.Observe("catchall.example", smtp(true, "")) // accepts everything
+		l.Observe("normal.example", smtp(true, ""))
+		l.Observe("normal.example", smtp(false, emailkit.CodeMailboxUnknown))
+	}
+
+	v := emailkit.New().With(l.Checker()).WithCalibration()
+	ctx := context.Background()
+
+	r, err := v.Validate(ctx, "a@catchall.example")
+	require.NoError(t, err)
+	c, ok := r.CheckFor(reputation.Level)
+	require.True(t, ok)
+	assert.True(t, c.Passed)
+	assert.Equal(t, emailkit.CodeCatchAll, c.Code)
+	assert.Equal(t, "1.00", c.Meta["acceptance_rate"])
+	assert.InDelta(t, emailkit.DefaultCalibration()[emailkit.SignalCatchAll], r.DeliverabilityProbability, 1e-9)
+
+	r, err = v.Validate(ctx, "a@normal.example")
+	require.NoError(t, err)
+	c, _ = r.CheckFor(reputation.Level)
+	assert.Empty(t, c.Code)
+	assert.Equal(t, "50% of 6 probes accepted", c.Details)
+
+	r, err = v.Validate(ctx, "a@new.example")
+	require.NoError(t, err)
+	c, _ = r.CheckFor(reputation.Level)
+	assert.Equal(t, "no reputation yet", c.Details)
+	assert.Nil(t, c.Meta)
+}
+
+func TestLearner_HandleEvent(t *testing.T) {
+	l, _ := newLearner(reputation.Options{})
+	v := emailkit.New().With(smtpLevel{smtp(false, emailkit.CodeGreylisted)}).WithSubscriber(l)
+
+	_, err := v.Validate(context.Background(), "user@Example.com")
+	require.NoError(t, err)
+	s, ok := l.Stats("example.com")
+	require.True(t, ok)
+	assert.InDelta(t, 1, s.Greylisted, 1e-9)
+
+	// Hashed addresses carry no domain
+	v = emailkit.New().With(smtpLevel{smtp(true, "")}).WithSubscriber(l).WithPrivacy(emailkit.PrivacyOptions{Salt: []byte("salt")})
+	_, err = v.Validate(context.Background(), "user@hashed.example")
+	require.NoError(t, err)
+	_, ok = l.Stats("hashed.example")
+	assert.False(t, ok)
+}
+
+func TestLearner_Forget(t *testing.T) {
+	l, _ := newLearner(reputation.Options{})
+	l.Observe("example.com", smtp(true, ""))
+	l.Observe("other.example", smtp(true, ""))
+
+	// Reached through the level and the subscriber; counted once
+	v := emailkit.New().With(l.Checker()).WithSubscriber(l)
+	inv, err := v.Invalidate(context.Background(), "Example.COM")
+	require.NoError(t, err)
+	assert.Equal(t, 1, inv.Forgotten)
+	_, ok := l.Stats("example.com")
+	assert.False(t, ok)
+	_, ok = l.Stats("other.example")
+	assert.True(t, ok)
+
+	assert.False(t, l.Forget("example.com"))
+}
+
+func TestLearner_SaveLoad(t *testing.T) {
+	l, _ := newLearner(reputation.Options{})
+	l.Observe("b.example", smtp(true, emailkit.CodeCatchAll))
+	l.Observe("a.example", smtp(false, emailkit.CodeMailboxUnknown))
+
+	var buf bytes.Buffer
+	require.NoError(t, l.Save(&buf))
+
+	restored, _ := newLearner(reputation.Options{})
+	require.NoError(t, restored.Load(&buf))
+	assert.Equal(t, l.Domains(), restored.Domains())
+	require.Len(t, restored.Domains(), 2)
+	assert.Equal(t, "a.example", restored.Domains()[0].Domain)
+
+	assert.Error(t, restored.Load(bytes.NewBufferString("{")))
+}
+
+func TestFeatureRegistered(t *testing.T) {
+	for _, f := range emailkit.Features() {
+		if f.Name == "reputation" {
+			assert.Equal(t, emailkit.StabilityExperimental, f.Stability)
+			return
+		}
+	}
+	t.Fatal("reputation feature not registered")
+}